@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+	"github.com/cesarempathy/pv-zone-migrator/internal/ui"
+)
+
+// applyAccessibleStyles turns off colors and borders so console output is read
+// cleanly by screen readers
+func applyAccessibleStyles() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	cliBoxStyle = lipgloss.NewStyle().MarginTop(1)
+}
+
+// formatPlan renders the plan for the console, as sentences in accessible mode
+func formatPlan(plan *migrator.MigrationPlan) string {
+	if accessible {
+		return migrator.FormatPlanPlain(plan)
+	}
+	return migrator.FormatPlan(plan)
+}
+
+// icon returns emoji followed by a space, or nothing in accessible mode, where
+// screen readers would read the emoji's name aloud
+func icon(emoji string) string {
+	if accessible {
+		return ""
+	}
+	return emoji + " "
+}
+
+// printSummary prints the end-of-run summary, as sentences in accessible mode
+func printSummary(fm ui.Model, m *migrator.Migrator) {
+	if accessible {
+		fmt.Println()
+		fmt.Print(migrator.FormatSummaryPlain(m))
+		return
+	}
+	fm.PrintSummary()
+}
+
+// printActionRequired prints the run's orphaned objects, the volumes left in the
+// source zone and the warnings on their own, for runs that end without a summary
+func printActionRequired(m *migrator.Migrator) {
+	kubeContext := m.GetConfig().KubeContext
+	if accessible {
+		fmt.Print(migrator.FormatOrphansPlain(m.Orphans(), kubeContext))
+		fmt.Print(migrator.FormatStragglersPlain(m.Stragglers(), sourceZone))
+		fmt.Print(migrator.FormatWarningsPlain(m.Warnings()))
+		return
+	}
+	ui.PrintOrphans(m.Orphans(), kubeContext)
+	ui.PrintStragglers(m.Stragglers(), sourceZone)
+	ui.PrintActionRequired(m.Warnings())
+}
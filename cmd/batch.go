@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// defaultStateFile is where a --batch-size run records its results without --state-file
+const defaultStateFile = "pvc-migrator-state.jsonl"
+
+// statePath returns the state file of a batched run
+func statePath() string {
+	if stateFile == "" {
+		return defaultStateFile
+	}
+	return stateFile
+}
+
+// stateKey identifies the run's records in the state file by the cluster and
+// the configured target zones, so runs elsewhere sharing the file are ignored
+func stateKey(k8sClient *k8s.Client) migrator.StateKey {
+	destination := targetZone
+	if len(targetZones) > 0 {
+		destination = strings.Join(targetZones, ",")
+	}
+	return migrator.StateKey{KubeContext: k8sClient.ContextName(), Destination: destination}
+}
+
+// skipMigrated leaves out the PVCs the state file records as migrated to the
+// same zones of the same cluster, so a batched run that stopped resumes with
+// the PVCs it had not finished
+func skipMigrated(k8sClient *k8s.Client, allPVCs []pvcWithNamespace, pvcsByNamespace map[string][]string) ([]pvcWithNamespace, map[string][]string, error) {
+	done, err := migrator.MigratedPVCs(statePath(), stateKey(k8sClient))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(done) == 0 {
+		return allPVCs, pvcsByNamespace, nil
+	}
+
+	var left []pvcWithNamespace
+	byNamespace := make(map[string][]string)
+	for _, pvc := range allPVCs {
+		if done[pvc.Namespace+"/"+pvc.Name] {
+			continue
+		}
+		left = append(left, pvc)
+		byNamespace[pvc.Namespace] = append(byNamespace[pvc.Namespace], pvc.Name)
+	}
+	slog.Info("leaving out PVCs the state file records as migrated", "path", statePath(), "pvcs", len(allPVCs)-len(left))
+	fmt.Println(cliDimStyle.Render(icon("⏭") + i18n.T("cli.batch_resumed", len(allPVCs)-len(left), statePath())))
+	return left, byNamespace, nil
+}
+
+// splitBatches splits the PVCs, grouped by namespace as discovered, into batches
+// of at most size PVCs. A namespace is kept in one batch so its workloads are
+// only scaled down once, unless it has more PVCs than fit in a batch: it then
+// fills batches of its own, and its last PVCs share one with the namespaces
+// after it.
+func splitBatches(allPVCs []pvcWithNamespace, size int) []pvcBatch {
+	var batches []pvcBatch
+	flush := func(pvcs []pvcWithNamespace, continued bool) {
+		if len(pvcs) > 0 {
+			batches = append(batches, pvcBatch{number: len(batches) + 1, pvcs: pvcs, continued: continued})
+		}
+	}
+
+	var current []pvcWithNamespace
+	for start := 0; start < len(allPVCs); {
+		end := start + 1
+		for end < len(allPVCs) && allPVCs[end].Namespace == allPVCs[start].Namespace {
+			end++
+		}
+		namespace := allPVCs[start:end]
+		start = end
+
+		if len(current)+len(namespace) > size {
+			flush(current, false)
+			current = nil
+		}
+		for len(namespace) > size {
+			flush(namespace[:size], true)
+			namespace = namespace[size:]
+		}
+		current = append(current, namespace...)
+	}
+	flush(current, false)
+
+	if len(batches) > 0 {
+		batches[len(batches)-1].last = true
+	}
+	return batches
+}
+
+// batchPath returns the file a batch writes in place of path, numbered so each
+// batch keeps its own runbook and import blocks
+func batchPath(path string, number int) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), number, ext)
+}
+
+// migrateBatches migrates the PVCs --batch-size at a time. Each batch is
+// planned, run and recorded in the state file before the next one is planned,
+// so only the PVC names are held for the whole fleet. A batch with failures,
+// or cancelled, stops the run; running it again resumes with the PVCs the state
+// file does not record as migrated.
+func (r *migrationRun) migrateBatches(ctx context.Context, allPVCs []pvcWithNamespace) error {
+	batches := splitBatches(allPVCs, batchSize)
+	runbook, imports, id := runbookFile, terraformImports, migrationID
+	defer func() { runbookFile, terraformImports, migrationID = runbook, imports, id }()
+
+	for _, batch := range batches {
+		fmt.Println(cliHeaderStyle.Render(icon("📦") + i18n.T("cli.batch", batch.number, len(batches), len(batch.pvcs))))
+		runbookFile, terraformImports = batchPath(runbook, batch.number), batchPath(imports, batch.number)
+		if err := r.migrate(ctx, batch); err != nil {
+			return err
+		}
+		// --migration-id adopts what a crashed run created, whose unfinished
+		// PVCs are all in the first batch; later batches get an ID of their own
+		migrationID = ""
+	}
+	fmt.Println(cliSuccessStyle.Render(icon("✓") + i18n.T("cli.batch_done", len(batches), r.migrated, r.skipped, statePath())))
+	return nil
+}
+
+// recordBatch appends the batch's results to the state file and adds them to
+// the run's totals. A file that cannot be written is reported in the batch's
+// summary: the batch is planned again when the run is resumed.
+func (r *migrationRun) recordBatch(m *migrator.Migrator, batch pvcBatch) {
+	path := statePath()
+	if err := m.AppendState(path, stateKey(r.k8sClient), batch.number); err != nil {
+		slog.Error("failed to record batch results", "path", path, "batch", batch.number, "error", err)
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.state_failed", path, err),
+			Action:  i18n.T("warn.state_action"),
+		})
+	}
+	result := m.Result()
+	r.migrated += result.Migrated
+	r.skipped += result.Skipped
+}
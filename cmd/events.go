@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// lifecycleQueueSize bounds the events waiting to be published. Events only fire
+// on start, per-PVC completion and finish, so this is never reached in practice.
+const lifecycleQueueSize = 256
+
+// eventPublishing turns migrator events into lifecycle events on SNS/EventBridge.
+// Events are published from a background goroutine so a slow AWS endpoint never
+// stalls the migration goroutines.
+type eventPublishing struct {
+	publisher *aws.EventPublisher
+	m         *migrator.Migrator
+	queue     chan aws.LifecycleEvent
+	done      chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+// setupEventPublishing subscribes the lifecycle publisher to the migrator's events.
+// Events are published with the EC2 client's credentials, so with awsRoleArn
+// they come from the assumed role. It returns nil when neither an SNS topic nor
+// an EventBridge bus is configured.
+func setupEventPublishing(m *migrator.Migrator, ec2Client *aws.Client) *eventPublishing {
+	if snsTopicARN == "" && eventBusName == "" {
+		return nil
+	}
+
+	ep := &eventPublishing{
+		publisher: aws.NewEventPublisher(ec2Client.Config(), snsTopicARN, eventBusName),
+		m:         m,
+		queue:     make(chan aws.LifecycleEvent, lifecycleQueueSize),
+		done:      make(chan struct{}),
+	}
+	go ep.run()
+	m.AddListener(ep.observe)
+	return ep
+}
+
+// observe queues started on the first event of the run, then one event per PVC
+// that completes or fails
+func (ep *eventPublishing) observe(e migrator.Event) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.closed {
+		return
+	}
+
+	if !ep.started {
+		ep.started = true
+		start := ep.base(aws.LifecycleStarted, e.Time)
+		start.Total = len(ep.m.GetConfig().PVCList)
+		ep.enqueue(start)
+	}
+
+	var eventType string
+	switch e.Step {
+	case migrator.StepDone.String():
+		eventType = aws.LifecyclePVCCompleted
+	case migrator.StepFailed.String():
+		eventType = aws.LifecyclePVCFailed
+	default:
+		return
+	}
+	le := ep.base(eventType, e.Time)
+	le.PVC = e.PVC
+	le.Namespace = e.Namespace
+	le.SourceVolumeID = e.SourceVolumeID
+	le.SnapshotID = e.SnapshotID
+	le.VolumeID = e.VolumeID
+	le.Error = e.Error
+	ep.enqueue(le)
+}
+
+// enqueue hands an event to the publishing goroutine without blocking; the
+// caller must hold ep.mu. A full queue drops the event and records a warning.
+func (ep *eventPublishing) enqueue(le aws.LifecycleEvent) {
+	select {
+	case ep.queue <- le:
+	default:
+		ep.m.AddWarning(migrator.Warning{
+			PVC:     le.PVC,
+			Message: i18n.T("warn.event_failed", le.Type, "queue full"),
+			Action:  i18n.T("warn.event_action"),
+		})
+	}
+}
+
+// finish queues the finished event with the final counts, then waits until every
+// queued event has been published. Nothing is sent if the run never started.
+func (ep *eventPublishing) finish() {
+	if ep == nil {
+		return
+	}
+
+	ep.mu.Lock()
+	if ep.started {
+		ep.enqueue(ep.finishedEvent())
+	}
+	ep.closed = true
+	close(ep.queue)
+	ep.mu.Unlock()
+
+	<-ep.done
+}
+
+// run publishes queued events in order until the queue is closed
+func (ep *eventPublishing) run() {
+	defer close(ep.done)
+	for le := range ep.queue {
+		ep.publish(le)
+	}
+}
+
+func (ep *eventPublishing) finishedEvent() aws.LifecycleEvent {
+	le := ep.base(aws.LifecycleFinished, time.Now())
+	for _, s := range ep.m.GetStatuses() {
+		le.Total++
+		switch s.Step {
+		case migrator.StepDone:
+			le.Migrated++
+		case migrator.StepSkipped:
+			le.Skipped++
+		case migrator.StepFailed:
+			le.Failed++
+		case migrator.StepPending, migrator.StepGetInfo, migrator.StepSnapshot,
+			migrator.StepWaitSnapshot, migrator.StepCreateVolume, migrator.StepWaitVolume,
+			migrator.StepCleanup, migrator.StepCreatePV, migrator.StepCreatePVC:
+		}
+	}
+	return le
+}
+
+func (ep *eventPublishing) base(eventType string, at time.Time) aws.LifecycleEvent {
+	cfg := ep.m.GetConfig()
+	return aws.LifecycleEvent{
+		Type:        eventType,
+		Time:        at,
+		KubeContext: cfg.KubeContext,
+		TargetZone:  cfg.Destination(),
+		DryRun:      cfg.DryRun,
+	}
+}
+
+// publish sends an event, recording a warning if it could not be delivered so
+// gaps in the audit trail are visible in the summary
+func (ep *eventPublishing) publish(le aws.LifecycleEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ep.publisher.Publish(ctx, le); err != nil {
+		ep.m.AddWarning(migrator.Warning{
+			PVC:     le.PVC,
+			Message: i18n.T("warn.event_failed", le.Type, err),
+			Action:  i18n.T("warn.event_action"),
+		})
+	}
+}
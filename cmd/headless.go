@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// Progress format constants
+const (
+	progressFormatTUI  = "tui"
+	progressFormatJSON = "json"
+)
+
+// processStdout is the process's real stdout, kept for the JSON event stream after
+// reserveStdoutForJSON has pointed os.Stdout at stderr
+var processStdout = os.Stdout
+
+// jsonToStdout reports whether the JSON progress stream is written to stdout
+func jsonToStdout() bool {
+	return progressFormat == progressFormatJSON && (progressOutput == "" || progressOutput == "-")
+}
+
+// reserveStdoutForJSON sends all human-readable output (banners, plan, prompts and
+// the summary) to stderr, so stdout carries nothing but newline-delimited events
+func reserveStdoutForJSON() {
+	os.Stdout = os.Stderr
+}
+
+// openProgressOutput opens the destination for the progress event stream.
+// "-" means stdout; any other value is a file or named pipe.
+func openProgressOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopWriteCloser{processStdout}, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open progress output %s: %w", path, err)
+	}
+	return f, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// runHeadless runs the migration without the TUI. It prints the plan, asks for
+// confirmation unless --yes is set and blocks until every PVC has been processed. It returns false
+// if the operator declined to start the migration.
+func runHeadless(ctx context.Context, m *migrator.Migrator, plan *migrator.MigrationPlan) bool {
+	fmt.Print(formatPlan(plan))
+
+	if !dryRun && !autoApprove && !confirmStart() {
+		return false
+	}
+
+	// Ctrl+C cancels in-flight steps, mirroring the TUI behavior
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	m.Run(runCtx)
+	if retryFailed && runCtx.Err() == nil {
+		if n := len(m.RetryableFailures()); n > 0 {
+			fmt.Println(i18n.T("cli.retrying", n))
+			m.RetryFailed(runCtx)
+		}
+	}
+	return true
+}
+
+// confirmStart asks the operator to confirm the migration on stdin
+func confirmStart() bool {
+	return confirm(i18n.T("cli.confirm_start"))
+}
+
+// confirm asks the operator a yes or no question on stdin
+func confirm(prompt string) bool {
+	fmt.Print(cliWarningStyle.Render(prompt))
+
+	var input string
+	_, _ = fmt.Scanln(&input)
+	answer := strings.ToLower(strings.TrimSpace(input))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	return i18n.Locale() == i18n.Spanish && (answer == "s" || answer == "si" || answer == "sí")
+}
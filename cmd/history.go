@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+// historyFormat is the --output of history and history show
+var historyFormat string
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the migrations recorded in the cluster's journal",
+	Long: `List the runs recorded in the journal namespace by migrate --journal-namespace, latest
+first, with when they started, how long they took, the context they ran against, who ran
+them, their PVC counts and outcome. A run that stopped before recording its end is shown as
+unfinished. Use history show <migration-id> for the volumes and snapshots of each PVC.`,
+	Example: `  pvc-migrator history --journal-namespace ops
+  pvc-migrator history show 20261016T120000Z-0a1b --journal-namespace ops`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <migration-id>",
+	Short: "Show the full record of one migration",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryShow,
+}
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	historyCmd.PersistentFlags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	historyCmd.PersistentFlags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	historyCmd.PersistentFlags().StringVar(&journalNamespace, "journal-namespace", "", "Namespace the journal ConfigMaps were written to")
+	historyCmd.PersistentFlags().StringVarP(&historyFormat, "output", "o", inventoryFormatTable, "Output format: 'table' or 'json'")
+	historyCmd.AddCommand(historyShowCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// historyEntry is a run in the JSON output of history
+type historyEntry struct {
+	MigrationID string     `json:"migrationId"`
+	KubeContext string     `json:"kubeContext"`
+	Operator    string     `json:"operator"`
+	StartTime   time.Time  `json:"startTime"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	Outcome     string     `json:"outcome"`
+	Total       int        `json:"total"`
+	Migrated    int        `json:"migrated"`
+	Skipped     int        `json:"skipped"`
+	Failed      int        `json:"failed"`
+}
+
+// historyRecord is the JSON output of history show
+type historyRecord struct {
+	historyEntry
+	Result apiv1.Result `json:"result"`
+}
+
+func newHistoryEntry(r migrator.JournalRecord) historyEntry {
+	entry := historyEntry{
+		MigrationID: r.MigrationID,
+		KubeContext: r.KubeContext,
+		Operator:    r.Operator,
+		StartTime:   r.StartTime,
+		Outcome:     r.Outcome(),
+		Total:       r.Result.Total,
+		Migrated:    r.Result.Migrated,
+		Skipped:     r.Result.Skipped,
+		Failed:      r.Result.Failed,
+	}
+	if !r.EndTime.IsZero() {
+		entry.EndTime = &r.EndTime
+	}
+	return entry
+}
+
+// historyClient checks the flags shared by the history commands and returns a
+// client of the cluster holding the journal
+func historyClient() (*k8s.Client, error) {
+	if historyFormat != inventoryFormatTable && historyFormat != inventoryFormatJSON {
+		return nil, fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", historyFormat, inventoryFormatTable, inventoryFormatJSON)
+	}
+	if journalNamespace == "" {
+		return nil, fmt.Errorf("--journal-namespace (or journalNamespace in the config) is required to read the journal")
+	}
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return k8sClient, nil
+}
+
+func runHistory(_ *cobra.Command, _ []string) error {
+	k8sClient, err := historyClient()
+	if err != nil {
+		return err
+	}
+	records, err := migrator.ReadJournals(context.Background(), k8sClient, journalNamespace)
+	if err != nil {
+		return err
+	}
+
+	if historyFormat == inventoryFormatJSON {
+		entries := make([]historyEntry, 0, len(records))
+		for _, r := range records {
+			entries = append(entries, newHistoryEntry(r))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(records) == 0 {
+		fmt.Println(i18n.T("history.none", journalNamespace))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("history.header"))
+	for _, r := range records {
+		duration := "-"
+		if !r.EndTime.IsZero() {
+			duration = r.EndTime.Sub(r.StartTime).String()
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.MigrationID, formatJournalTime(r.StartTime), duration, r.KubeContext, r.Operator,
+			i18n.T("history.pvcs", r.Result.Migrated, r.Result.Skipped, r.Result.Failed), i18n.T("history."+r.Outcome()))
+	}
+	return w.Flush()
+}
+
+func runHistoryShow(_ *cobra.Command, args []string) error {
+	k8sClient, err := historyClient()
+	if err != nil {
+		return err
+	}
+	r, err := migrator.ReadJournal(context.Background(), k8sClient, journalNamespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	if historyFormat == inventoryFormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(historyRecord{historyEntry: newHistoryEntry(r), Result: r.Result})
+	}
+	printJournalRecord(r)
+	return nil
+}
+
+// printJournalRecord prints a run with the outcome, volumes and snapshots of
+// each PVC and the warnings left for the operator
+func printJournalRecord(r migrator.JournalRecord) {
+	fmt.Println(cliHeaderStyle.Render(i18n.T("history.show_title", r.MigrationID, i18n.T("history."+r.Outcome()))))
+	fmt.Println(i18n.T("history.show_context", r.KubeContext, r.Operator))
+	if r.EndTime.IsZero() {
+		fmt.Println(i18n.T("history.show_time_running", formatJournalTime(r.StartTime)))
+	} else {
+		fmt.Println(i18n.T("history.show_time", formatJournalTime(r.StartTime), formatJournalTime(r.EndTime), r.EndTime.Sub(r.StartTime)))
+	}
+	fmt.Println(i18n.T("history.show_target", r.Result.TargetZone))
+	fmt.Println(i18n.T("history.pvcs", r.Result.Migrated, r.Result.Skipped, r.Result.Failed))
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("history.show_header"))
+	for _, p := range r.Result.PVCs {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.PVC, p.Outcome, p.Step, orDash(p.SourceZone), orDash(p.TargetZone), orDash(p.SourceVolumeID), orDash(p.SnapshotID), orDash(p.VolumeID))
+	}
+	_ = w.Flush()
+	for _, p := range r.Result.PVCs {
+		if p.Error != "" {
+			fmt.Println(cliWarningStyle.Render(fmt.Sprintf("%s: %s", p.PVC, p.Error)))
+		}
+	}
+
+	if len(r.Result.Warnings) > 0 {
+		fmt.Println()
+		fmt.Println(i18n.T("history.show_warnings"))
+		for _, warning := range r.Result.Warnings {
+			fmt.Printf("  %s\n", warning.Message)
+			if warning.Action != "" {
+				fmt.Println(cliDimStyle.Render("    " + warning.Action))
+			}
+		}
+	}
+}
+
+// formatJournalTime renders a time recorded in a journal in UTC to the minute
+func formatJournalTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
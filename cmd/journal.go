@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// journaling keeps the run's journal ConfigMap up to date as PVCs finish. It is
+// written from a background goroutine so a slow API server never stalls the
+// migration goroutines; PVCs finishing while a write is in flight are recorded
+// together by the next one.
+type journaling struct {
+	m       *migrator.Migrator
+	journal migrator.Journal
+	pending chan struct{}
+	done    chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// setupJournal writes the journal of the run before anything is changed, so a
+// missing namespace or permission stops the run instead of leaving it
+// unrecorded, then subscribes to the migrator's events to keep it up to date.
+// It returns nil when no journal namespace is configured or on a dry run.
+func setupJournal(ctx context.Context, m *migrator.Migrator, k8sClient *k8s.Client) (*journaling, error) {
+	if journalNamespace == "" || dryRun {
+		return nil, nil
+	}
+
+	operator, err := k8sClient.WhoAmI(ctx)
+	if err != nil {
+		slog.Warn("failed to find who the cluster authenticates the run as, recording --as instead", "error", err)
+		operator = asUser
+	}
+	jl := &journaling{
+		m:       m,
+		journal: migrator.Journal{Namespace: journalNamespace, Operator: operator, StartTime: time.Now()},
+		pending: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if err := m.SaveJournal(ctx, jl.journal); err != nil {
+		return nil, err
+	}
+	go jl.run()
+	m.AddListener(jl.observe)
+	return jl, nil
+}
+
+// observe asks for a write when a PVC finishes, whatever its outcome
+func (jl *journaling) observe(e migrator.Event) {
+	switch e.Step {
+	case migrator.StepDone.String(), migrator.StepFailed.String(), migrator.StepSkipped.String():
+	default:
+		return
+	}
+
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+	if jl.closed {
+		return
+	}
+	select {
+	case jl.pending <- struct{}{}:
+	default:
+		// A write is already pending and will pick this PVC up
+	}
+}
+
+// run writes the journal each time one is asked for, until finish is called.
+// A failed write is only logged: the next one records the same PVCs.
+func (jl *journaling) run() {
+	defer close(jl.done)
+	for range jl.pending {
+		if err := jl.save(jl.journal); err != nil {
+			slog.Warn("failed to update migration journal", "namespace", jl.journal.Namespace, "error", err)
+		}
+	}
+}
+
+// finish waits for the pending write, then records the final result with the
+// end time, adding a warning if it could not be written
+func (jl *journaling) finish() {
+	if jl == nil {
+		return
+	}
+
+	jl.mu.Lock()
+	jl.closed = true
+	close(jl.pending)
+	jl.mu.Unlock()
+	<-jl.done
+
+	final := jl.journal
+	final.EndTime = time.Now()
+	if err := jl.save(final); err != nil {
+		slog.Error("failed to write migration journal", "namespace", final.Namespace, "error", err)
+		jl.m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.journal_failed", final.Namespace+"/"+k8s.JournalName(jl.m.MigrationID()), err),
+			Action:  i18n.T("warn.journal_action"),
+		})
+	}
+}
+
+func (jl *journaling) save(j migrator.Journal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return jl.m.SaveJournal(ctx, j)
+}
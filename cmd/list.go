@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// listFormat is the --output of list
+var listFormat string
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the EBS-backed PVCs with the details of their volumes",
+	Long: `List every EBS-backed PVC of the namespaces with its PV, EBS volume, size, volume
+type, encryption and Availability Zone, and the Deployments and StatefulSets mounting it.
+Nothing is changed and no plan is made, so it is a quick inventory before a migration.`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	listCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	listCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	listCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
+	listCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	listCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) whose PVCs to list (comma-separated)")
+	listCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List EBS-backed PVCs in every namespace")
+	listCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "List EBS-backed PVCs in namespaces matching this label selector (e.g. team=payments)")
+	listCmd.Flags().StringVarP(&listFormat, "output", "o", inventoryFormatTable, "Output format: 'table' or 'json'")
+
+	rootCmd.AddCommand(listCmd)
+}
+
+// listEntry is a PVC in the JSON output of list
+type listEntry struct {
+	PVC        string   `json:"pvc"`
+	PV         string   `json:"pv"`
+	VolumeID   string   `json:"volumeId"`
+	Found      bool     `json:"found"` // Whether the volume exists in EC2; the fields below are empty otherwise
+	SizeGiB    int32    `json:"sizeGiB,omitempty"`
+	VolumeType string   `json:"volumeType,omitempty"`
+	Encrypted  bool     `json:"encrypted"`
+	KMSKeyID   string   `json:"kmsKeyId,omitempty"`
+	Zone       string   `json:"zone,omitempty"`
+	Workloads  []string `json:"workloads"` // "Kind/name" of the Deployments and StatefulSets mounting it
+}
+
+func runList(_ *cobra.Command, _ []string) error {
+	if listFormat != inventoryFormatTable && listFormat != inventoryFormatJSON {
+		return fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", listFormat, inventoryFormatTable, inventoryFormatJSON)
+	}
+
+	ctx := context.Background()
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	if err := addDiscoveredNamespaces(ctx, k8sClient); err != nil {
+		return err
+	}
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+
+	volumes, err := migrator.VolumeZones(ctx, k8sClient, ec2Client, cfg.GetNamespaceNames())
+	if err != nil {
+		return err
+	}
+	workloads := migrator.VolumeWorkloads(ctx, k8sClient, volumes)
+
+	if listFormat == inventoryFormatJSON {
+		entries := make([]listEntry, 0, len(volumes))
+		for _, v := range volumes {
+			entries = append(entries, listEntry{
+				PVC:        v.PVC,
+				PV:         v.PVName,
+				VolumeID:   v.VolumeID,
+				Found:      v.Zone != "",
+				SizeGiB:    v.SizeGiB,
+				VolumeType: v.VolumeType,
+				Encrypted:  v.Encrypted,
+				KMSKeyID:   v.KMSKeyID,
+				Zone:       v.Zone,
+				Workloads:  append([]string{}, workloads[v.PVC]...),
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(volumes) == 0 {
+		fmt.Println(i18n.T("zones.none"))
+		return nil
+	}
+	printVolumes(volumes, workloads)
+	return nil
+}
+
+// printVolumes prints the PVCs with the details of their volume and the
+// workloads mounting them as a table
+func printVolumes(volumes []migrator.VolumeZone, workloads map[string][]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("list.header"))
+	for _, v := range volumes {
+		mounted := strings.Join(workloads[v.PVC], ", ")
+		if mounted == "" {
+			mounted = "-"
+		}
+		if v.Zone == "" {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\t-\t%s\t%s\n", v.PVC, v.PVName, v.VolumeID, i18n.T("zones.not_found"), mounted)
+			continue
+		}
+		encryption := i18n.T("list.unencrypted")
+		if v.Encrypted {
+			encryption = i18n.T("list.encrypted")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%dGiB\t%s\t%s\t%s\t%s\n",
+			v.PVC, v.PVName, v.VolumeID, v.SizeGiB, v.VolumeType, encryption, v.Zone, mounted)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println(cliDimStyle.Render(i18n.T("list.total", len(volumes))))
+}
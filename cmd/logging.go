@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// logCloser closes the log file opened by initLogging, if any
+var logCloser io.Closer
+
+// logPaneLines is how many log lines the TUI keeps for its log pane
+const logPaneLines = 1000
+
+// consoleLog receives log records when no --log-file is set
+var consoleLog = &heldWriter{w: os.Stderr}
+
+// heldWriter forwards writes to w, except between hold and release, when they are
+// buffered. Records written to stderr while the TUI owns the alternate screen would
+// corrupt it, so they are replayed once the TUI has exited.
+type heldWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf *bytes.Buffer
+}
+
+func (h *heldWriter) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buf != nil {
+		return h.buf.Write(p)
+	}
+	return h.w.Write(p)
+}
+
+// hold starts buffering writes
+func (h *heldWriter) hold() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buf == nil {
+		h.buf = &bytes.Buffer{}
+	}
+}
+
+// release writes out everything buffered since hold and stops buffering
+func (h *heldWriter) release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buf == nil {
+		return
+	}
+	_, _ = h.w.Write(h.buf.Bytes())
+	h.buf = nil
+}
+
+// parseLogLevel converts a --log-level value into a slog level
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level '%s': must be one of debug, info, warn, error", level)
+	}
+}
+
+// initLogging configures structured logging.
+//
+// With --log-file, JSON records are appended to the file (info level by default) so
+// every API call and decision survives the TUI for post-mortems. Without it, only
+// warnings go to stderr, held back while the TUI is running. --verbose forces debug.
+func initLogging(verbose bool, logFile, logLevel string) error {
+	level := slog.LevelWarn
+	if logFile != "" {
+		level = slog.LevelInfo
+	}
+	if logLevel != "" {
+		parsed, err := parseLogLevel(logLevel)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	}
+	if verbose {
+		level = slog.LevelDebug
+	}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logCloser = f
+		slog.SetDefault(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: level})))
+		return nil
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: level,
+		// Remove time from log output for cleaner CLI experience unless verbose
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if !verbose && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(consoleLog, opts)))
+	return nil
+}
+
+// teeLogs also sends log records to w, as text, until the returned function is
+// called. w gets info records, or debug ones when those are enabled, whatever the
+// configured level, so the TUI log pane shows the API calls being made.
+func teeLogs(w io.Writer) func() {
+	prev := slog.Default()
+	level := slog.LevelInfo
+	if prev.Enabled(context.Background(), slog.LevelDebug) {
+		level = slog.LevelDebug
+	}
+	pane := slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, a.Value.Time().Format("15:04:05"))
+			}
+			return a
+		},
+	})
+	slog.SetDefault(slog.New(teeHandler{prev.Handler(), pane}))
+	return func() { slog.SetDefault(prev) }
+}
+
+// teeHandler passes each record to every handler that accepts its level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
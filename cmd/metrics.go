@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/metrics"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// metricsJobName is the Pushgateway job the run's metrics are grouped under
+const metricsJobName = "pvc_migrator"
+
+// setupMetrics creates the Prometheus collectors and starts the /metrics endpoint
+// if requested. It runs before anything touches the cluster so a bad address fails
+// the run up front. It returns nil when metrics are disabled.
+func setupMetrics() (*metrics.Metrics, *http.Server, error) {
+	if metricsAddr == "" && metricsPushgateway == "" && metricsTextfileDir == "" {
+		return nil, nil, nil
+	}
+
+	mt := metrics.New()
+	if metricsAddr == "" {
+		return mt, nil, nil
+	}
+	srv, err := mt.Serve(metricsAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start metrics endpoint: %w", err)
+	}
+	fmt.Printf("%s http://%s/metrics\n", cliDimStyle.Render(icon("📈")+"Metrics:"), metricsAddr)
+	return mt, srv, nil
+}
+
+// attachMetrics wires the collectors into the migrator and EC2 client
+func attachMetrics(mt *metrics.Metrics, m *migrator.Migrator, ec2Client *aws.Client) {
+	if mt == nil {
+		return
+	}
+	m.AddListener(mt.Observe)
+	ec2Client.SetAPIHook(mt.ObserveAPICall)
+}
+
+// finishMetrics pushes the final values to the Pushgateway and writes them to
+// the textfile collector directory
+func finishMetrics(ctx context.Context, mt *metrics.Metrics, m *migrator.Migrator) {
+	if mt != nil {
+		mt.Finish()
+	}
+	if mt != nil && metricsPushgateway != "" {
+		if err := mt.Push(ctx, metricsPushgateway, metricsJobName); err != nil {
+			m.AddWarning(migrator.Warning{
+				Message: i18n.T("warn.metrics_failed", err),
+				Action:  i18n.T("warn.metrics_action"),
+			})
+		}
+	}
+	if mt != nil && metricsTextfileDir != "" {
+		if err := mt.WriteTextfile(metricsTextfileDir); err != nil {
+			m.AddWarning(migrator.Warning{
+				Message: i18n.T("warn.textfile_failed", err),
+				Action:  i18n.T("warn.textfile_action"),
+			})
+		}
+	}
+}
+
+// stopEndpoint stops an HTTP endpoint started for the run, if there is one. It
+// is deferred once the endpoint is up, so a run that fails early frees its port.
+func stopEndpoint(ctx context.Context, srv *http.Server) {
+	if srv == nil {
+		return
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(shutdownCtx)
+}
@@ -4,18 +4,29 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
+	"github.com/cesarempathy/pv-zone-migrator/internal/argocd"
 	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/config"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
 	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/metrics"
 	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
 	"github.com/cesarempathy/pv-zone-migrator/internal/ui"
 )
@@ -26,6 +37,17 @@ const (
 	scaleModeManual = "manual"
 )
 
+// Running Jobs that mount PVCs to migrate are waited for, unless the jobs policy
+// is jobsDelete
+const (
+	jobsDelete        = "delete"
+	defaultJobTimeout = 30 * time.Minute
+)
+
+// ApplicationSets that would revert the auto-sync change are only warned about,
+// unless the ApplicationSets policy is appSetsPause
+const appSetsPause = "pause"
+
 // Console output styles
 var (
 	cliHeaderStyle = lipgloss.NewStyle().
@@ -58,27 +80,6 @@ var (
 			Width(16)
 )
 
-// initLogging configures structured logging
-func initLogging(verbose bool) {
-	level := slog.LevelInfo
-	if verbose {
-		level = slog.LevelDebug
-	}
-
-	opts := &slog.HandlerOptions{
-		Level: level,
-		// Remove time from log output for cleaner CLI experience unless verbose
-		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
-			if !verbose && a.Key == slog.TimeKey {
-				return slog.Attr{}
-			}
-			return a
-		},
-	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
-	slog.SetDefault(logger)
-}
-
 // scaledWorkloadsPerNS stores scaled workloads for a namespace
 type scaledWorkloadsPerNS struct {
 	Namespace string
@@ -89,26 +90,124 @@ type scaledWorkloadsPerNS struct {
 type migrationContext struct {
 	ctx              context.Context
 	k8sClient        *k8s.Client
+	argoCD           k8s.ArgoCD // The Kubernetes client, or the argocd-server API with --argocd-server
 	argoCDApps       []k8s.ArgoCDAppInfo
+	applicationSets  []k8s.ApplicationSetInfo // Paused while auto-sync is off
+	fluxResources    []k8s.FluxResourceInfo
+	suspendedFlux    []k8s.FluxResourceInfo // Those suspended so far
+	scaledObjects    []k8s.ScaledObjectInfo // KEDA ScaledObjects of the workloads to scale down
+	claimsByNS       map[string][]string    // Mounted PVCs to migrate, per namespace
+	claimJobs        map[string][]k8s.ClaimJob
+	pausedObjects    []k8s.ScaledObjectInfo // Those paused so far
 	scaledWorkloads  []scaledWorkloadsPerNS
 	workloadInfoByNS map[string][]k8s.WorkloadInfo
+	cordonedNodes    []string // Nodes of the source zone cordoned by the run
 }
 
-// restoreOnError restores workloads and ArgoCD state on error
+// restoreOnError restores workloads, KEDA, Flux and ArgoCD state on error.
+// Cordoned nodes are released first, as the workloads still need the volumes of
+// the source zone.
 func (mc *migrationContext) restoreOnError() {
+	if len(mc.cordonedNodes) > 0 {
+		slog.Warn("uncordoning nodes after error", "zone", sourceZone, "nodes", mc.cordonedNodes)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.uncordoning_on_err", sourceZone))
+		_ = mc.k8sClient.UncordonNodes(mc.ctx, mc.cordonedNodes)
+	}
 	for _, sw := range mc.scaledWorkloads {
-		fmt.Printf("⚠️  Restoring workloads in namespace '%s' due to error...\n", sw.Namespace)
+		slog.Warn("restoring workloads after error", "namespace", sw.Namespace, "workloads", len(sw.Workloads))
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.restoring_on_err", sw.Namespace))
 		_ = mc.k8sClient.ScaleUpWorkloads(mc.ctx, sw.Namespace, sw.Workloads)
 	}
+	mc.releaseControllers()
+}
+
+// releaseControllers hands the workloads and PVCs back to KEDA, Flux and ArgoCD
+// when the run stops early
+func (mc *migrationContext) releaseControllers() {
+	if len(mc.pausedObjects) > 0 {
+		_ = mc.k8sClient.ResumeScaledObjects(mc.ctx, mc.pausedObjects)
+	}
+	if len(mc.suspendedFlux) > 0 {
+		_ = mc.k8sClient.ResumeFlux(mc.ctx, mc.suspendedFlux)
+	}
+	mc.enableArgoCDAutoSync()
+}
+
+// enableArgoCDAutoSync re-enables auto-sync, then lets the ApplicationSets update
+// their applications again, when the run stops early
+func (mc *migrationContext) enableArgoCDAutoSync() {
 	if len(mc.argoCDApps) > 0 {
-		_ = mc.k8sClient.EnableArgoCDAutoSync(mc.ctx, mc.argoCDApps)
+		_ = mc.argoCD.EnableArgoCDAutoSync(mc.ctx, mc.argoCDApps)
+	}
+	if len(mc.applicationSets) > 0 {
+		_ = mc.k8sClient.ResumeApplicationSets(mc.ctx, mc.applicationSets)
+	}
+}
+
+// pauseScaledObjects pauses the KEDA ScaledObjects in namespace, or in every
+// namespace when it is empty, so KEDA does not undo the scale-down
+func (mc *migrationContext) pauseScaledObjects(namespace string) error {
+	var objects []k8s.ScaledObjectInfo
+	for _, obj := range mc.scaledObjects {
+		if namespace == "" || obj.Namespace == namespace {
+			objects = append(objects, obj)
+		}
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+	// Objects paused before a failure must be resumed
+	mc.pausedObjects = append(mc.pausedObjects, objects...)
+	if err := mc.k8sClient.PauseScaledObjects(mc.ctx, objects); err != nil {
+		return fmt.Errorf("failed to pause KEDA autoscaling: %w", err)
+	}
+	return nil
+}
+
+// handleClaimJobs waits for the running Jobs that mount PVCs to migrate to
+// finish, or deletes them once confirmed, as scaling the workloads down does not
+// stop them
+func (mc *migrationContext) handleClaimJobs() error {
+	timeout := jobTimeout
+	if timeout == 0 {
+		timeout = defaultJobTimeout
+	}
+	for _, ns := range namespaces {
+		jobs := mc.claimJobs[ns]
+		if len(jobs) == 0 {
+			continue
+		}
+		if jobPolicy == jobsDelete {
+			if !autoApprove && !confirm(i18n.T("cli.jobs_confirm", len(jobs), ns)) {
+				mc.restoreOnError()
+				return fmt.Errorf("running jobs in namespace '%s' were not deleted", ns)
+			}
+			fmt.Println(cliDimStyle.Render(icon("🗑") + i18n.T("cli.jobs_deleting", ns)))
+			if err := mc.k8sClient.DeleteJobs(mc.ctx, ns, jobs); err != nil {
+				mc.restoreOnError()
+				return fmt.Errorf("failed to delete jobs in namespace '%s': %w", ns, err)
+			}
+		} else {
+			fmt.Println(cliDimStyle.Render(icon("⏳") + i18n.T("cli.jobs_waiting", ns, timeout)))
+		}
+		if err := mc.k8sClient.WaitForClaimJobs(mc.ctx, ns, mc.claimsByNS[ns], jobs, timeout); err != nil {
+			mc.restoreOnError()
+			return fmt.Errorf("jobs mounting PVCs in namespace '%s' did not finish: %w", ns, err)
+		}
 	}
+	return nil
 }
 
 // handleManualScaling handles manual workload scaling mode
 func (mc *migrationContext) handleManualScaling() error {
+	// Scaled to zero by KEDA itself once paused, so nothing scales them back up
+	if err := mc.pauseScaledObjects(""); err != nil {
+		mc.restoreOnError()
+		return err
+	}
+
 	fmt.Println()
-	fmt.Println(cliWarningStyle.Render("⚠️  Please scale down the workloads manually before proceeding:"))
+	fmt.Println(cliWarningStyle.Render(icon("⚠️ ") + i18n.T("cli.scale_down_manual")))
 	fmt.Println()
 
 	for ns, workloads := range mc.workloadInfoByNS {
@@ -116,30 +215,18 @@ func (mc *migrationContext) handleManualScaling() error {
 			continue
 		}
 		for _, w := range workloads {
-			var cmdStr string
-			switch w.Kind {
-			case "Deployment":
-				cmdStr = fmt.Sprintf("kubectl scale deployment %s --replicas=0 -n %s", w.Name, ns)
-			case "StatefulSet":
-				cmdStr = fmt.Sprintf("kubectl scale statefulset %s --replicas=0 -n %s", w.Name, ns)
-			}
-			if kubeContext != "" {
-				cmdStr += fmt.Sprintf(" --context=%s", kubeContext)
-			}
-			fmt.Printf("  %s\n", cliDimStyle.Render(cmdStr))
+			fmt.Printf("  %s\n", cliDimStyle.Render(scaleCommand(w, ns, 0)))
 		}
 	}
 
 	fmt.Println()
-	fmt.Println(cliInfoStyle.Render("Waiting for you to run the commands above..."))
-	fmt.Println(cliDimStyle.Render("Press Enter when workloads are scaled down, or 'q' to quit:"))
+	fmt.Println(cliInfoStyle.Render(i18n.T("cli.manual_waiting")))
+	fmt.Println(cliDimStyle.Render(i18n.T("cli.manual_prompt")))
 
 	var input string
 	_, _ = fmt.Scanln(&input)
 	if strings.ToLower(strings.TrimSpace(input)) == "q" {
-		if len(mc.argoCDApps) > 0 {
-			_ = mc.k8sClient.EnableArgoCDAutoSync(mc.ctx, mc.argoCDApps)
-		}
+		mc.releaseControllers()
 		return fmt.Errorf("migration cancelled by user")
 	}
 
@@ -151,21 +238,24 @@ func (mc *migrationContext) handleManualScaling() error {
 	}
 
 	// Wait for pods to terminate
-	fmt.Println(cliInfoStyle.Render("⏳ Verifying workloads are scaled down..."))
+	fmt.Println(cliInfoStyle.Render(icon("⏳") + i18n.T("cli.manual_verifying")))
 	for _, ns := range namespaces {
 		if len(mc.workloadInfoByNS[ns]) > 0 {
 			if err := mc.k8sClient.WaitForWorkloadsScaledDown(mc.ctx, ns, 5*time.Minute); err != nil {
-				if len(mc.argoCDApps) > 0 {
-					_ = mc.k8sClient.EnableArgoCDAutoSync(mc.ctx, mc.argoCDApps)
-				}
+				mc.releaseControllers()
 				return fmt.Errorf("workloads not scaled down in namespace '%s': %w", ns, err)
 			}
 		}
 	}
-	fmt.Println(cliSuccessStyle.Render("✓ All workloads scaled down"))
+	fmt.Println(cliSuccessStyle.Render(icon("✓") + i18n.T("cli.manual_done")))
 	return nil
 }
 
+// scaleCommand returns the kubectl command that scales a workload to replicas
+func scaleCommand(w k8s.WorkloadInfo, namespace string, replicas int32) string {
+	return migrator.ScaleCommand(w, namespace, replicas, kubeContext)
+}
+
 // handleAutoScaling handles automatic workload scaling mode
 func (mc *migrationContext) handleAutoScaling() error {
 	for _, ns := range namespaces {
@@ -180,6 +270,13 @@ func (mc *migrationContext) handleAutoScaling() error {
 			return fmt.Errorf("failed to scale down workloads in namespace '%s': %w", ns, err)
 		}
 		mc.scaledWorkloads = append(mc.scaledWorkloads, scaledWorkloadsPerNS{Namespace: ns, Workloads: scaledWorkloads})
+		slog.Info("scaled down workloads", "namespace", ns, "workloads", len(scaledWorkloads))
+
+		// Paused only now, as KEDA scaling to zero first would lose the replica counts
+		if err := mc.pauseScaledObjects(ns); err != nil {
+			mc.restoreOnError()
+			return err
+		}
 
 		if err := mc.k8sClient.WaitForWorkloadsScaledDown(mc.ctx, ns, 5*time.Minute); err != nil {
 			mc.restoreOnError()
@@ -189,6 +286,22 @@ func (mc *migrationContext) handleAutoScaling() error {
 	return nil
 }
 
+// cordonSourceNodes cordons the nodes of the source zone once the workloads are
+// scaled down, so they are scheduled in the other zones when scaled back up
+func (mc *migrationContext) cordonSourceNodes() error {
+	if !cordonNodes || dryRun {
+		return nil
+	}
+	nodes, err := mc.k8sClient.CordonZoneNodes(mc.ctx, sourceZone)
+	mc.cordonedNodes = nodes
+	if err != nil {
+		return fmt.Errorf("failed to cordon the nodes of %s: %w", sourceZone, err)
+	}
+	slog.Info("cordoned source zone nodes", "zone", sourceZone, "nodes", nodes)
+	fmt.Println(cliDimStyle.Render(icon("🚧") + i18n.T("cli.nodes_cordoned", len(nodes), sourceZone)))
+	return nil
+}
+
 // pvcWithNamespace represents a PVC with its namespace
 type pvcWithNamespace struct {
 	Namespace string
@@ -197,21 +310,43 @@ type pvcWithNamespace struct {
 
 // discoverPVCs discovers all PVCs from configured namespaces
 func discoverPVCs(ctx context.Context, k8sClient *k8s.Client) ([]pvcWithNamespace, map[string][]string, error) {
+	if err := addDiscoveredNamespaces(ctx, k8sClient); err != nil {
+		return nil, nil, err
+	}
+
 	var allPVCs []pvcWithNamespace
 	pvcsByNamespace := make(map[string][]string)
 
 	for _, nsCfg := range cfg.Namespaces {
 		if len(nsCfg.PVCs) > 0 {
-			for _, pvc := range nsCfg.PVCs {
+			selected := nsCfg.PVCs
+			if nsCfg.HasPVCPatterns() {
+				listed, err := k8sClient.ListPVCs(ctx, nsCfg.Name)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list PVCs in namespace '%s': %w", nsCfg.Name, err)
+				}
+				selected = nsCfg.ExpandPVCs(listed)
+				slog.Info("expanded PVC patterns", "namespace", nsCfg.Name, "patterns", nsCfg.PVCs, "count", len(selected))
+			}
+			for _, pvc := range selected {
 				allPVCs = append(allPVCs, pvcWithNamespace{Namespace: nsCfg.Name, Name: pvc})
 			}
-			pvcsByNamespace[nsCfg.Name] = nsCfg.PVCs
+			pvcsByNamespace[nsCfg.Name] = selected
 		} else {
-			discovered, err := k8sClient.ListPVCs(ctx, nsCfg.Name)
+			listed, err := k8sClient.ListPVCs(ctx, nsCfg.Name)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to list PVCs in namespace '%s': %w", nsCfg.Name, err)
 			}
+			discovered := make([]string, 0, len(listed))
+			for _, pvc := range listed {
+				if nsCfg.Excludes(pvc) {
+					slog.Info("excluding PVC from discovery", "namespace", nsCfg.Name, "pvc", pvc)
+					continue
+				}
+				discovered = append(discovered, pvc)
+			}
 			pvcsByNamespace[nsCfg.Name] = discovered
+			slog.Info("discovered PVCs", "namespace", nsCfg.Name, "count", len(discovered))
 			for _, pvc := range discovered {
 				allPVCs = append(allPVCs, pvcWithNamespace{Namespace: nsCfg.Name, Name: pvc})
 			}
@@ -220,290 +355,1536 @@ func discoverPVCs(ctx context.Context, k8sClient *k8s.Client) ([]pvcWithNamespac
 	return allPVCs, pvcsByNamespace, nil
 }
 
-// handleArgoCDApps finds and disables ArgoCD auto-sync for affected applications
-func handleArgoCDApps(ctx context.Context, k8sClient *k8s.Client) ([]k8s.ArgoCDAppInfo, error) {
-	if skipArgoCD {
-		return nil, nil
+// selectFromZone keeps only the PVCs whose volumes are in the --from-zone zone.
+// PVCs whose zone cannot be looked up are kept so the plan reports the error.
+func selectFromZone(ctx context.Context, k8sClient *k8s.Client, ec2Client *aws.Client,
+	allPVCs []pvcWithNamespace, pvcsByNamespace map[string][]string,
+) ([]pvcWithNamespace, map[string][]string) {
+	if sourceZone == "" {
+		return allPVCs, pvcsByNamespace
 	}
 
-	var argoCDApps []k8s.ArgoCDAppInfo
-	for _, ns := range namespaces {
-		apps, err := k8sClient.FindArgoCDAppsForNamespace(ctx, ns, argoCDNamespaces)
-		if err != nil {
+	var selected []pvcWithNamespace
+	byNamespace := make(map[string][]string)
+	for _, pvc := range allPVCs {
+		if zone, err := volumeZone(ctx, k8sClient, ec2Client, pvc); err != nil {
+			slog.Warn("failed to find the zone of a PVC, keeping it", "pvc", pvc.Namespace+"/"+pvc.Name, "error", err)
+		} else if zone != sourceZone {
+			slog.Info("PVC not in the source zone, leaving it out", "pvc", pvc.Namespace+"/"+pvc.Name, "zone", zone, "sourceZone", sourceZone)
 			continue
 		}
-		argoCDApps = append(argoCDApps, apps...)
+		selected = append(selected, pvc)
+		byNamespace[pvc.Namespace] = append(byNamespace[pvc.Namespace], pvc.Name)
 	}
+	fmt.Println(cliDimStyle.Render(icon("🧭") + i18n.T("cli.from_zone", len(selected), len(allPVCs), sourceZone)))
+	return selected, byNamespace
+}
 
-	argoCDAppNames := make([]string, 0, len(argoCDApps))
-	for _, app := range argoCDApps {
-		argoCDAppNames = append(argoCDAppNames, fmt.Sprintf("%s/%s", app.Namespace, app.Name))
+// volumeZone returns the Availability Zone of the volume backing a PVC
+func volumeZone(ctx context.Context, k8sClient *k8s.Client, ec2Client *aws.Client, pvc pvcWithNamespace) (string, error) {
+	info, err := k8sClient.GetPVCInfo(ctx, pvc.Namespace, pvc.Name)
+	if err != nil {
+		return "", err
 	}
-
-	fmt.Println(buildArgoCDBox(argoCDAppNames, argoCDNamespaces, dryRun))
-
-	if len(argoCDApps) > 0 && !dryRun {
-		if err := k8sClient.DisableArgoCDAutoSync(ctx, argoCDApps); err != nil {
-			return nil, fmt.Errorf("failed to disable ArgoCD auto-sync: %w", err)
+	if info.PVMissing {
+		if info.VolumeID, err = ec2Client.FindVolumeByPV(ctx, info.PVName); err != nil {
+			return "", err
 		}
 	}
-	return argoCDApps, nil
+	volume, err := ec2Client.GetVolumeInfo(ctx, info.VolumeID)
+	if err != nil {
+		return "", err
+	}
+	return volume.AvailabilityZone, nil
 }
 
-// collectWorkloadInfo gathers information about running workloads in all namespaces
-func collectWorkloadInfo(ctx context.Context, k8sClient *k8s.Client, argoCDApps []k8s.ArgoCDAppInfo) (map[string][]string, map[string][]k8s.WorkloadInfo, error) {
-	workloadsByNS := make(map[string][]string)
-	workloadInfoByNS := make(map[string][]k8s.WorkloadInfo)
+// addDiscoveredNamespaces appends the namespaces with EBS-backed PVCs found by
+// --all-namespaces or --namespace-selector to the configured ones. Their PVCs are
+// listed explicitly so claims on other storage are never planned.
+func addDiscoveredNamespaces(ctx context.Context, k8sClient *k8s.Client) error {
+	if !cfg.DiscoversNamespaces() {
+		return nil
+	}
 
-	for _, ns := range namespaces {
-		runningWorkloads, err := k8sClient.GetWorkloadStatus(ctx, ns)
-		if err != nil {
-			if len(argoCDApps) > 0 && !dryRun {
-				_ = k8sClient.EnableArgoCDAutoSync(ctx, argoCDApps)
-			}
-			return nil, nil, fmt.Errorf("failed to check workload status in namespace '%s': %w", ns, err)
+	ebsPVCs, err := k8sClient.ListEBSPVCs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list EBS-backed PVCs: %w", err)
+	}
+	var selected []string
+	if namespaceSelector != "" {
+		if selected, err = k8sClient.ListNamespaces(ctx, namespaceSelector); err != nil {
+			return err
 		}
-		workloadInfoByNS[ns] = runningWorkloads
-		for _, w := range runningWorkloads {
-			workloadsByNS[ns] = append(workloadsByNS[ns], fmt.Sprintf("%s/%s (replicas: %d)", w.Kind, w.Name, w.Replicas))
+	} else {
+		for ns := range ebsPVCs {
+			selected = append(selected, ns)
 		}
+		sort.Strings(selected)
 	}
-	return workloadsByNS, workloadInfoByNS, nil
-}
-
-func runMigrate(_ *cobra.Command, _ []string) error {
-	ctx := context.Background()
-
-	// Initialize structured logging
-	initLogging(verbose)
 
-	// Validate scaleMode
-	if scaleMode != scaleModeAuto && scaleMode != scaleModeManual {
-		return fmt.Errorf("invalid scale mode '%s': must be either '%s' or '%s'", scaleMode, scaleModeAuto, scaleModeManual)
+	for _, ns := range selected {
+		if len(ebsPVCs[ns]) == 0 || slices.Contains(namespaces, ns) {
+			continue
+		}
+		cfg.Namespaces = append(cfg.Namespaces, config.NamespaceConfig{Name: ns, PVCs: ebsPVCs[ns]})
 	}
+	namespaces = cfg.GetNamespaceNames()
+	slog.Info("discovered namespaces", "selector", namespaceSelector, "namespaces", namespaces)
+	return nil
+}
 
-	// Print header info
-	printHeaderInfo()
-
-	// Initialize Kubernetes client with optional context
-	k8sClient, err := k8s.NewClient(kubeContext)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+// newArgoCD returns what turns ArgoCD auto-sync off and on: the argocd-server API
+// with --argocd-server, otherwise the Kubernetes client
+func newArgoCD(k8sClient *k8s.Client) (k8s.ArgoCD, error) {
+	if argoCDServer == "" || skipArgoCD {
+		return k8sClient, nil
 	}
-
-	// Discover PVCs and collect initial information
-	allPVCs, _, argoCDApps, _, workloadInfoByNS, err := initializeMigration(ctx, k8sClient)
+	client, err := argocd.NewClient(argoCDServer, os.Getenv(argocd.TokenEnv), argoCDInsecure, nil)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create ArgoCD API client: %w", err)
 	}
+	return client, nil
+}
 
-	// Create migration context
-	mc := &migrationContext{
-		ctx:              ctx,
-		k8sClient:        k8sClient,
-		argoCDApps:       argoCDApps,
-		workloadInfoByNS: workloadInfoByNS,
+// findArgoCDApps finds the ArgoCD applications with auto-sync that manage the
+// namespaces whose workloads are scaled down
+func findArgoCDApps(ctx context.Context, argoCD k8s.ArgoCD, scaleNamespaces []string) []k8s.ArgoCDAppInfo {
+	if skipArgoCD || len(scaleNamespaces) == 0 {
+		return nil
 	}
 
-	// Handle workload scaling
-	totalWorkloads := calculateTotalWorkloads(workloadInfoByNS)
-	if totalWorkloads > 0 && !dryRun {
-		if err := handleWorkloadScaling(mc); err != nil {
-			return err
+	var argoCDApps []k8s.ArgoCDAppInfo
+	for _, ns := range scaleNamespaces {
+		apps, err := argoCD.FindArgoCDAppsForNamespace(ctx, ns, argoCDNamespaces)
+		if err != nil {
+			slog.Warn("failed to search ArgoCD applications", "namespace", ns, "error", err)
+			continue
 		}
+		for i := range apps {
+			apps[i].Strategy = argoCDStrategy
+		}
+		argoCDApps = append(argoCDApps, apps...)
 	}
 
-	// Initialize AWS client and create migrator
-	ec2Client, err := aws.NewEC2Client(ctx)
-	if err != nil {
-		mc.restoreOnError()
-		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	searched := argoCDNamespaces
+	if argoCDServer != "" {
+		searched = []string{argoCDServer}
 	}
+	fmt.Println(buildArgoCDBox(argoCDAppNames(argoCDApps), searched, dryRun))
+	return argoCDApps
+}
 
-	m, config := createMigrator(k8sClient, ec2Client, allPVCs)
-
-	// Handle plan-only mode
-	if planOnly {
-		return handlePlanMode(ctx, m)
+// disableArgoCDAutoSync turns off auto-sync so ArgoCD does not undo the scale-down
+func (mc *migrationContext) disableArgoCDAutoSync() error {
+	if len(mc.argoCDApps) == 0 || dryRun {
+		return nil
 	}
-
-	// Run migration UI
-	finalModel, err := runMigrationUI(mc, m, config)
-	if err != nil {
-		mc.restoreOnError()
-		return err
+	// ApplicationSets would revert the change to their applications straight away
+	if err := mc.k8sClient.PauseApplicationSets(mc.ctx, mc.applicationSets); err != nil {
+		_ = mc.k8sClient.ResumeApplicationSets(mc.ctx, mc.applicationSets)
+		return fmt.Errorf("failed to pause ArgoCD ApplicationSets: %w", err)
 	}
-
-	// Print summary and cleanup
-	if fm, ok := finalModel.(ui.Model); ok {
-		fm.PrintSummary()
-		if fm.HasErrors() {
-			os.Exit(1)
-		}
+	slog.Info("disabling ArgoCD auto-sync", "apps", argoCDAppNames(mc.argoCDApps))
+	if err := mc.argoCD.DisableArgoCDAutoSync(mc.ctx, mc.argoCDApps); err != nil {
+		// Apps disabled before the failure must be restored
+		mc.enableArgoCDAutoSync()
+		return fmt.Errorf("failed to disable ArgoCD auto-sync: %w", err)
 	}
-
-	// Restore workloads and ArgoCD
-	restoreWorkloads(ctx, k8sClient, mc)
-	restoreArgoCDAutoSync(ctx, k8sClient, mc)
-
 	return nil
 }
 
-// printHeaderInfo prints the migration header information
-func printHeaderInfo() {
-	if configFile != "" {
-		fmt.Printf("%s %s\n", cliDimStyle.Render("📄 Config:"), configFile)
-	}
-	if kubeContext != "" {
-		fmt.Printf("%s %s\n", cliDimStyle.Render("☸  Context:"), kubeContext)
+// findApplicationSets finds the ApplicationSets that would revert the auto-sync
+// change to the applications they own. They are returned to be paused with
+// --argocd-appsets pause; otherwise each application is warned about.
+func findApplicationSets(ctx context.Context, k8sClient *k8s.Client, argoCDApps []k8s.ArgoCDAppInfo) []k8s.ApplicationSetInfo {
+	if argoCDAppSets != appSetsPause {
+		for _, app := range argoCDApps {
+			if app.UsesApplicationSet() {
+				fmt.Println(cliWarningStyle.Render(icon("⚠️ ") + i18n.T("cli.appset_owned", app.Namespace+"/"+app.Name, app.ApplicationSet)))
+				fmt.Println("   " + cliDimStyle.Render(i18n.T("cli.appset_remedy")))
+			}
+		}
+		return nil
 	}
-}
 
-// initializeMigration discovers PVCs, ArgoCD apps, and workloads
-func initializeMigration(ctx context.Context, k8sClient *k8s.Client) (
-	[]pvcWithNamespace,
-	map[string][]string,
-	[]k8s.ArgoCDAppInfo,
-	map[string][]string,
-	map[string][]k8s.WorkloadInfo,
-	error,
-) {
-	// Discover PVCs
-	allPVCs, pvcsByNamespace, err := discoverPVCs(ctx, k8sClient)
+	sets, err := k8sClient.FindApplicationSets(ctx, argoCDApps)
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		slog.Warn("failed to search ArgoCD ApplicationSets", "error", err)
+		return nil
 	}
-	if len(allPVCs) == 0 {
-		return nil, nil, nil, nil, nil, fmt.Errorf("no PVCs found in any of the specified namespaces")
+	if len(sets) > 0 {
+		names := make([]string, 0, len(sets))
+		for _, set := range sets {
+			names = append(names, set.Namespace+"/"+set.Name)
+		}
+		fmt.Println(cliDimStyle.Render(icon("⏸") + i18n.T("cli.appset_pausing", strings.Join(names, ", "))))
 	}
-	fmt.Println(buildDiscoveryBox(pvcsByNamespace, len(allPVCs)))
+	return sets
+}
 
-	// Handle ArgoCD applications
-	argoCDApps, err := handleArgoCDApps(ctx, k8sClient)
-	if err != nil {
-		return nil, nil, nil, nil, nil, err
+// findFluxResources finds the Flux Kustomizations and HelmReleases that would
+// recreate the PVCs of the namespaces to migrate
+func findFluxResources(ctx context.Context, k8sClient *k8s.Client, migrateNamespaces []string) []k8s.FluxResourceInfo {
+	if skipFlux {
+		return nil
 	}
 
-	// Collect workload information
-	workloadsByNS, workloadInfoByNS, err := collectWorkloadInfo(ctx, k8sClient, argoCDApps)
-	if err != nil {
-		return nil, nil, nil, nil, nil, err
+	var resources []k8s.FluxResourceInfo
+	seen := make(map[k8s.FluxResourceInfo]bool)
+	for _, ns := range migrateNamespaces {
+		found, err := k8sClient.FindFluxResourcesForNamespace(ctx, ns, fluxNamespaces)
+		if err != nil {
+			slog.Warn("failed to search Flux objects", "namespace", ns, "error", err)
+			continue
+		}
+		for _, res := range found {
+			if !seen[res] {
+				seen[res] = true
+				resources = append(resources, res)
+			}
+		}
 	}
-	fmt.Println(buildWorkloadsBox(workloadsByNS, dryRun, scaleMode))
 
-	return allPVCs, pvcsByNamespace, argoCDApps, workloadsByNS, workloadInfoByNS, nil
+	if len(resources) > 0 {
+		names := make([]string, 0, len(resources))
+		for _, res := range resources {
+			names = append(names, fmt.Sprintf("%s %s/%s", res.Kind, res.Namespace, res.Name))
+		}
+		fmt.Println(cliDimStyle.Render(icon("⏸") + i18n.T("cli.flux_found", strings.Join(names, ", "))))
+	}
+	return resources
 }
 
-// calculateTotalWorkloads counts total workloads across all namespaces
-func calculateTotalWorkloads(workloadInfoByNS map[string][]k8s.WorkloadInfo) int {
-	total := 0
-	for _, workloads := range workloadInfoByNS {
-		total += len(workloads)
+// findHelmReleases finds the Helm releases managing the PVCs to migrate, and
+// warns that upgrading them after the run reverts it
+func findHelmReleases(ctx context.Context, k8sClient *k8s.Client, plan *migrator.MigrationPlan) []k8s.HelmReleaseInfo {
+	claimsByNS := make(map[string][]string)
+	for _, item := range plan.Items {
+		if item.Action == migrator.PlanActionMigrate {
+			claimsByNS[item.Namespace] = append(claimsByNS[item.Namespace], item.PVCName)
+		}
 	}
-	return total
-}
 
-// handleWorkloadScaling handles the scaling of workloads based on scale mode
-func handleWorkloadScaling(mc *migrationContext) error {
-	switch scaleMode {
-	case scaleModeManual:
-		return mc.handleManualScaling()
-	default:
-		return mc.handleAutoScaling()
+	var releases []k8s.HelmReleaseInfo
+	for _, ns := range plan.MigrateNamespaces() {
+		found, err := k8sClient.FindHelmReleases(ctx, ns, claimsByNS[ns], helmValues != "")
+		if err != nil {
+			slog.Warn("failed to search Helm releases", "namespace", ns, "error", err)
+			continue
+		}
+		releases = append(releases, found...)
 	}
+	for _, release := range releases {
+		fmt.Println(cliWarningStyle.Render(icon("⚠️ ") + i18n.T("cli.helm_found", release.Namespace+"/"+release.Name, strings.Join(release.Claims, ", "))))
+		fmt.Println("   " + cliDimStyle.Render(i18n.T("cli.helm_remedy")))
+	}
+	return releases
 }
 
-// createMigrator creates the migrator instance with necessary clients
-func createMigrator(k8sClient *k8s.Client, ec2Client *aws.Client, allPVCs []pvcWithNamespace) (
-	*migrator.Migrator,
-	*migrator.Config,
-) {
-	// Build PVC list with namespace prefix
-	pvcListWithNS := make([]string, 0, len(allPVCs))
-	for _, pvc := range allPVCs {
-		pvcListWithNS = append(pvcListWithNS, fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name))
+// reportHelmReleases lists the Helm releases whose PVCs were migrated under
+// action required, writing the values changes their next upgrade needs to
+// --helm-values
+func reportHelmReleases(m *migrator.Migrator, releases []k8s.HelmReleaseInfo) {
+	if dryRun {
+		return
 	}
-
-	// Create migration config
-	config := &migrator.Config{
-		Namespaces:     namespaces,
-		TargetZone:     targetZone,
-		StorageClass:   storageClass,
-		MaxConcurrency: maxConcurrency,
-		PVCList:        pvcListWithNS,
-		DryRun:         dryRun,
+	for _, release := range releases {
+		changes, migrated := m.HelmValues(release)
+		if !migrated {
+			continue
+		}
+		var actions []string
+		if len(release.Owned) > 0 {
+			actions = append(actions, i18n.T("warn.helm_owned"))
+			for _, claim := range release.Owned {
+				actions = append(actions, migrator.HelmOwnershipCommand(claim, release, kubeContext))
+			}
+		}
+		path, err := writeHelmValues(release, changes)
+		switch {
+		case err != nil:
+			slog.Error("failed to write Helm values", "release", release.Namespace+"/"+release.Name, "error", err)
+			actions = append(actions, i18n.T("warn.helm_action"))
+		case path != "":
+			actions = append(actions, i18n.T("warn.helm_values", path),
+				fmt.Sprintf("helm upgrade %s <chart> -n %s --reuse-values -f %s", release.Name, release.Namespace, path))
+		default:
+			actions = append(actions, i18n.T("warn.helm_action"))
+		}
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.helm_release", release.Namespace+"/"+release.Name, strings.Join(release.Claims, ", ")),
+			Action:  strings.Join(actions, "\n"),
+		})
 	}
-
-	m := migrator.New(config, k8sClient, ec2Client)
-	return m, config
 }
 
-// handlePlanMode generates and displays the migration plan
-func handlePlanMode(ctx context.Context, m *migrator.Migrator) error {
-	fmt.Println("\n🔍 Generating migration plan...")
-
-	plan, err := m.GeneratePlan(ctx)
+// writeHelmValues writes the values changes of a release to
+// <--helm-values>/<namespace>/<release>.yaml and returns the file, or nothing
+// when there are none to write
+func writeHelmValues(release k8s.HelmReleaseInfo, changes map[string]interface{}) (string, error) {
+	if helmValues == "" || len(changes) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(changes)
 	if err != nil {
-		return fmt.Errorf("failed to generate plan: %w", err)
+		return "", err
 	}
+	path := filepath.Join(helmValues, release.Namespace, release.Name+".yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0o600)
+}
 
-	fmt.Print(migrator.FormatPlan(plan))
-	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(
-		"Run without --plan flag to execute the migration."))
-	fmt.Println()
-
+// suspendFlux suspends the Flux objects so they do not recreate the PVCs deleted
+// during the run
+func (mc *migrationContext) suspendFlux() error {
+	if len(mc.fluxResources) == 0 || dryRun {
+		return nil
+	}
+	// Objects suspended before a failure must be resumed
+	mc.suspendedFlux = mc.fluxResources
+	if err := mc.k8sClient.SuspendFlux(mc.ctx, mc.fluxResources); err != nil {
+		return fmt.Errorf("failed to suspend Flux reconciliation: %w", err)
+	}
 	return nil
 }
 
-// runMigrationUI creates and runs the Bubble Tea UI
-func runMigrationUI(_ *migrationContext, m *migrator.Migrator, config *migrator.Config) (tea.Model, error) {
-	model := ui.NewModel(m, config)
-	p := tea.NewProgram(model, tea.WithAltScreen())
-
-	finalModel, err := p.Run()
-	if err != nil {
-		return nil, fmt.Errorf("UI error: %w", err)
+// findScaledObjects finds the KEDA ScaledObjects that scale the workloads about to
+// be scaled down
+func findScaledObjects(ctx context.Context, k8sClient *k8s.Client, workloadInfoByNS map[string][]k8s.WorkloadInfo, scaleNamespaces []string) []k8s.ScaledObjectInfo {
+	if skipKEDA {
+		return nil
 	}
 
-	return finalModel, nil
-}
+	var objects []k8s.ScaledObjectInfo
+	for _, ns := range scaleNamespaces {
+		found, err := k8sClient.FindScaledObjects(ctx, ns, workloadInfoByNS[ns])
+		if err != nil {
+			slog.Warn("failed to search KEDA ScaledObjects", "namespace", ns, "error", err)
+			continue
+		}
+		objects = append(objects, found...)
+	}
 
-// restoreWorkloads scales workloads back to their original replica counts
-func restoreWorkloads(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext) {
-	if len(mc.scaledWorkloads) == 0 || dryRun {
-		return
+	if len(objects) > 0 {
+		names := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			names = append(names, fmt.Sprintf("%s/%s", obj.Namespace, obj.Name))
+		}
+		fmt.Println(cliDimStyle.Render(icon("⏸") + i18n.T("cli.keda_found", strings.Join(names, ", "))))
 	}
+	return objects
+}
 
-	fmt.Println("\n🚀 Restoring workloads to original replica counts...")
-	for _, sw := range mc.scaledWorkloads {
-		fmt.Printf("   Namespace '%s':\n", sw.Namespace)
-		for _, w := range sw.Workloads {
-			fmt.Printf("     - %s/%s → %d replicas\n", w.Kind, w.Name, w.Replicas)
+// findClaimJobs finds the Jobs still running that mount a PVC about to be
+// migrated, by namespace
+func findClaimJobs(ctx context.Context, k8sClient *k8s.Client, claimsByNS map[string][]string) (map[string][]k8s.ClaimJob, error) {
+	claimJobs := make(map[string][]k8s.ClaimJob)
+	for _, ns := range namespaces {
+		if len(claimsByNS[ns]) == 0 {
+			continue
 		}
-		if err := k8sClient.ScaleUpWorkloads(ctx, sw.Namespace, sw.Workloads); err != nil {
-			fmt.Printf("   ⚠️  Warning: Failed to restore some workloads in '%s': %v\n", sw.Namespace, err)
-			fmt.Println("      Please manually restore workloads using kubectl")
-		} else {
-			fmt.Printf("   ✅ Workloads restored in namespace '%s'\n", sw.Namespace)
+		jobs, err := k8sClient.ClaimJobs(ctx, ns, claimsByNS[ns])
+		if err != nil {
+			return nil, fmt.Errorf("failed to check jobs in namespace '%s': %w", ns, err)
 		}
+		if len(jobs) == 0 {
+			continue
+		}
+		claimJobs[ns] = jobs
+		names := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			names = append(names, fmt.Sprintf("%s (%s)", job.Name, strings.Join(job.Claims, ", ")))
+		}
+		slog.Info("found running jobs mounting PVCs to migrate", "namespace", ns, "jobs", names)
+		fmt.Println(cliWarningStyle.Render(icon("⚠️ ") + i18n.T("cli.jobs_found", ns, strings.Join(names, "; "))))
 	}
+	return claimJobs, nil
 }
 
-// restoreArgoCDAutoSync re-enables auto-sync for ArgoCD applications
-func restoreArgoCDAutoSync(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext) {
-	if len(mc.argoCDApps) == 0 || dryRun {
-		return
+// mountedClaims returns the mounted PVCs the plan migrates, by namespace
+func mountedClaims(plan *migrator.MigrationPlan) map[string][]string {
+	claimsByNS := make(map[string][]string)
+	for _, item := range plan.Items {
+		if item.Action == migrator.PlanActionMigrate && item.Attached {
+			claimsByNS[item.Namespace] = append(claimsByNS[item.Namespace], item.PVCName)
+		}
 	}
+	return claimsByNS
+}
 
-	fmt.Println("\n🔓 Re-enabling ArgoCD auto-sync...")
-	for _, app := range mc.argoCDApps {
-		fmt.Printf("   - %s/%s\n", app.Namespace, app.Name)
+func argoCDAppNames(apps []k8s.ArgoCDAppInfo) []string {
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		names = append(names, fmt.Sprintf("%s/%s", app.Namespace, app.Name))
 	}
-	if err := k8sClient.EnableArgoCDAutoSync(ctx, mc.argoCDApps); err != nil {
-		fmt.Printf("⚠️  Warning: Failed to re-enable ArgoCD auto-sync: %v\n", err)
-		fmt.Println("   Please manually re-enable auto-sync in ArgoCD")
-	} else {
-		fmt.Println("   ✅ Auto-sync re-enabled")
+	return names
+}
+
+// collectWorkloadInfo gathers information about running workloads in the given namespaces
+func collectWorkloadInfo(ctx context.Context, k8sClient *k8s.Client, scaleNamespaces []string) (map[string][]k8s.WorkloadInfo, error) {
+	workloadInfoByNS := make(map[string][]k8s.WorkloadInfo)
+
+	for _, ns := range scaleNamespaces {
+		runningWorkloads, err := k8sClient.GetWorkloadStatus(ctx, ns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check workload status in namespace '%s': %w", ns, err)
+		}
+		workloadInfoByNS[ns] = runningWorkloads
+	}
+	return workloadInfoByNS, nil
+}
+
+// describeWorkloads formats the workloads of each namespace for the workloads box
+func describeWorkloads(workloadInfoByNS map[string][]k8s.WorkloadInfo) map[string][]string {
+	workloadsByNS := make(map[string][]string)
+	for ns, workloads := range workloadInfoByNS {
+		for _, w := range workloads {
+			workloadsByNS[ns] = append(workloadsByNS[ns], fmt.Sprintf("%s/%s (replicas: %d)", w.Kind, w.Name, w.Replicas))
+		}
+	}
+	return workloadsByNS
+}
+
+// Outcomes of a pass that end --watch without being failures
+var (
+	errNothingLeft = errors.New("nothing left to migrate")
+	errCancelled   = errors.New("migration cancelled")
+)
+
+// errPVCsFailed ends a run whose summary already lists the failed PVCs, or the
+// volumes left in the source zone, with exit status 1 and nothing more printed
+var errPVCsFailed = errors.New("PVCs failed to migrate")
+
+func runMigrate(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	var err error
+	if watchInterval > 0 {
+		err = watchMigrate(ctx)
+	} else if err = migrateOnce(ctx); errors.Is(err, errCancelled) {
+		err = nil
+	}
+	// Exits only once the deferred shutdowns of the run have completed
+	if errors.Is(err, errPVCsFailed) {
+		os.Exit(1)
+	}
+	return err
+}
+
+// watchMigrate runs migrations until a pass finds nothing left to migrate,
+// waiting watchInterval between passes. Namespaces and PVCs are discovered again
+// in every pass, so claims created since the previous one are picked up. A pass
+// with failures stops the loop, like a single run it exits with status 1.
+func watchMigrate(ctx context.Context) error {
+	if !execute || dryRun || planOnly {
+		return fmt.Errorf("--watch needs --execute and cannot be used with --dry-run or --plan")
+	}
+
+	configured := slices.Clone(cfg.Namespaces)
+	for pass := 1; ; pass++ {
+		cfg.Namespaces = slices.Clone(configured)
+		namespaces = cfg.GetNamespaceNames()
+
+		fmt.Println(cliHeaderStyle.Render(icon("🔁") + i18n.T("cli.watch_pass", pass)))
+		err := migrateOnce(ctx)
+		switch {
+		case errors.Is(err, errNothingLeft):
+			fmt.Println(cliSuccessStyle.Render(icon("✓") + i18n.T("cli.watch_done")))
+			return nil
+		case errors.Is(err, errCancelled):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		fmt.Println(cliDimStyle.Render(icon("💤") + i18n.T("cli.watch_sleeping", watchInterval)))
+		sleepCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		select {
+		case <-sleepCtx.Done():
+			stop()
+			fmt.Println(i18n.T("cli.cancelled"))
+			return nil
+		case <-time.After(watchInterval):
+		}
+		stop()
+	}
+}
+
+// migrateOnce discovers the PVCs, then plans and runs their migration, at most
+// --batch-size PVCs at a time when it is set. It returns errCancelled when the
+// operator stops the run, errPVCsFailed when a PVC failed and, with --watch,
+// errNothingLeft when no PVC needs migrating.
+func migrateOnce(ctx context.Context) error {
+	if batchSize > 0 && (!execute || dryRun || planOnly || outputFormat == outputFormatJSON) {
+		return fmt.Errorf("--batch-size needs --execute and cannot be used with --dry-run, --plan or --output json")
+	}
+
+	// Safe mode: a config file nobody reviewed only ever produces a plan
+	safeMode := !execute && !dryRun
+	if safeMode {
+		dryRun, planOnly = true, true
+	}
+
+	// Validate scaleMode
+	if scaleMode != scaleModeAuto && scaleMode != scaleModeManual {
+		return fmt.Errorf("invalid scale mode '%s': must be either '%s' or '%s'", scaleMode, scaleModeAuto, scaleModeManual)
+	}
+
+	// Validate progressFormat
+	if progressFormat != progressFormatTUI && progressFormat != progressFormatJSON {
+		return fmt.Errorf("invalid progress format '%s': must be either '%s' or '%s'", progressFormat, progressFormatTUI, progressFormatJSON)
+	}
+	if err := checkAutoApprove(autoApprove, scaleMode); err != nil {
+		return err
+	}
+	if outputFormat != outputFormatText && outputFormat != outputFormatJSON {
+		return fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", outputFormat, outputFormatText, outputFormatJSON)
+	}
+	if accessible && jsonToStdout() {
+		return fmt.Errorf("--accessible and JSON progress cannot both write to stdout: set --progress-output to a file")
+	}
+	if outputFormat == outputFormatJSON && jsonToStdout() {
+		return fmt.Errorf("--output json and JSON progress cannot both write to stdout: set --progress-output to a file")
+	}
+	if jsonToStdout() || outputFormat == outputFormatJSON {
+		reserveStdoutForJSON()
+	}
+	if accessible {
+		applyAccessibleStyles()
+	}
+
+	// Print header info
+	printHeaderInfo()
+
+	// Start metrics and tracing before anything touches the cluster, so a bad
+	// address or endpoint fails the run while nothing needs undoing
+	mt, metricsSrv, err := setupMetrics()
+	if err != nil {
+		return err
+	}
+	defer stopEndpoint(ctx, metricsSrv)
+	shutdownTracing, err := setupTracing(ctx)
+	if err != nil {
+		return err
+	}
+	defer finishTracing(ctx, shutdownTracing)
+	pprofSrv, err := setupProfiling()
+	if err != nil {
+		return err
+	}
+	defer stopEndpoint(ctx, pprofSrv)
+
+	// Initialize Kubernetes client with optional context
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	if skipRollouts {
+		k8sClient.SkipRollouts()
+	}
+	argoCD, err := newArgoCD(k8sClient)
+	if err != nil {
+		return err
+	}
+
+	// Discover PVCs
+	allPVCs, pvcsByNamespace, err := discoverPVCs(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	// Initialize AWS client and create migrator
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+	if err := resolveZoneIDs(ctx, ec2Client); err != nil {
+		return err
+	}
+	allPVCs, pvcsByNamespace = selectFromZone(ctx, k8sClient, ec2Client, allPVCs, pvcsByNamespace)
+	if len(allPVCs) == 0 {
+		if watchInterval > 0 {
+			return errNothingLeft
+		}
+		return fmt.Errorf("no PVCs found in any of the specified namespaces")
+	}
+	if batchSize > 0 {
+		allPVCs, pvcsByNamespace, err = skipMigrated(k8sClient, allPVCs, pvcsByNamespace)
+		if err != nil {
+			return err
+		}
+		if len(allPVCs) == 0 {
+			fmt.Println(cliSuccessStyle.Render(icon("✓") + i18n.T("cli.batch_none", statePath())))
+			return nil
+		}
+	}
+	fmt.Println(buildDiscoveryBox(pvcsByNamespace, len(allPVCs)))
+
+	run := &migrationRun{k8sClient: k8sClient, ec2Client: ec2Client, argoCD: argoCD, metrics: mt, safeMode: safeMode}
+	if batchSize > 0 {
+		return run.migrateBatches(ctx, allPVCs)
+	}
+	return run.migrate(ctx, pvcBatch{pvcs: allPVCs, last: true})
+}
+
+// checkAutoApprove rejects --yes with the manual scale mode, the default, which
+// waits for someone to scale the workloads down and press Enter
+func checkAutoApprove(autoApprove bool, mode string) error {
+	if autoApprove && mode == scaleModeManual {
+		return fmt.Errorf("--yes needs --mode %s: --mode %s, the default, waits for the workloads to be scaled down by hand", scaleModeAuto, scaleModeManual)
+	}
+	return nil
+}
+
+// migrationRun holds what migrateOnce sets up once for every PVC it migrates,
+// whether in a single pass or one --batch-size batch at a time
+type migrationRun struct {
+	k8sClient *k8s.Client
+	ec2Client *aws.Client
+	argoCD    k8s.ArgoCD
+	metrics   *metrics.Metrics
+	safeMode  bool
+
+	// Totals of the batches run so far, with --batch-size
+	migrated int
+	skipped  int
+}
+
+// pvcBatch is the set of PVCs a pass plans and migrates together
+type pvcBatch struct {
+	number    int // From 1 with --batch-size, 0 for a single pass over every PVC
+	pvcs      []pvcWithNamespace
+	last      bool // No batch follows, so the source zone can be checked for stragglers
+	continued bool // Its namespace has more PVCs in the next batch, so is not labelled complete yet
+}
+
+// migrate plans the migration of the batch's PVCs and runs it. It returns
+// errCancelled when the operator stops the run, errPVCsFailed when a PVC failed
+// and, with --watch, errNothingLeft when no PVC needs migrating.
+func (r *migrationRun) migrate(ctx context.Context, batch pvcBatch) error {
+	m, config := createMigrator(r.k8sClient, r.ec2Client, batch.pvcs)
+	slog.Info("migration configured",
+		"context", kubeContext, "targetZone", config.Destination(), "storageClass", storageClass,
+		"pvcs", len(config.PVCList), "concurrency", maxConcurrency, "dryRun", dryRun, "mode", scaleMode)
+
+	if planOnly {
+		fmt.Println("\n" + icon("🔍") + i18n.T("cli.plan_generating"))
+	}
+	plan, err := m.GeneratePlan(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate plan: %w", err)
+	}
+	if watchInterval > 0 && plan.Pending() == 0 {
+		return errNothingLeft
+	}
+
+	// Only namespaces with a mounted PVC to migrate need ArgoCD and workload
+	// handling; the others are migrated without touching either
+	scaleNamespaces := plan.ScaleNamespaces()
+	logFastPathNamespaces(scaleNamespaces)
+
+	// Find ArgoCD applications; auto-sync is only disabled once the runbook is written
+	argoCDApps := findArgoCDApps(ctx, r.argoCD, scaleNamespaces)
+	applicationSets := findApplicationSets(ctx, r.k8sClient, argoCDApps)
+	// Flux recreates deleted PVCs, so it is suspended wherever PVCs are migrated
+	fluxResources := findFluxResources(ctx, r.k8sClient, plan.MigrateNamespaces())
+	helmReleases := findHelmReleases(ctx, r.k8sClient, plan)
+
+	workloadInfoByNS, err := collectWorkloadInfo(ctx, r.k8sClient, scaleNamespaces)
+	if err != nil {
+		return err
+	}
+	fmt.Println(buildWorkloadsBox(describeWorkloads(workloadInfoByNS), dryRun, scaleMode))
+	scaledObjects := findScaledObjects(ctx, r.k8sClient, workloadInfoByNS, scaleNamespaces)
+	claimsByNS := mountedClaims(plan)
+	claimJobs, err := findClaimJobs(ctx, r.k8sClient, claimsByNS)
+	if err != nil {
+		return err
+	}
+
+	// Create migration context
+	mc := &migrationContext{
+		ctx:              ctx,
+		k8sClient:        r.k8sClient,
+		argoCD:           r.argoCD,
+		argoCDApps:       argoCDApps,
+		applicationSets:  applicationSets,
+		fluxResources:    fluxResources,
+		scaledObjects:    scaledObjects,
+		claimsByNS:       claimsByNS,
+		claimJobs:        claimJobs,
+		workloadInfoByNS: workloadInfoByNS,
+	}
+
+	// Write the fallback runbook before anything destructive happens
+	if runbookFile != "" {
+		if err := writeRunbook(plan, mc); err != nil {
+			return err
+		}
+	}
+
+	// Handle plan-only mode
+	if planOnly {
+		handlePlanMode(plan, r.safeMode)
+		if outputFormat == outputFormatJSON {
+			return writeDocument(plan.API())
+		}
+		return nil
+	}
+
+	// Nothing has been changed yet; protected clusters need their name typed first
+	if err := confirmProtectedContext(r.k8sClient.ContextName()); err != nil {
+		return err
+	}
+
+	attachMetrics(r.metrics, m, r.ec2Client)
+	notifier := setupNotifications(m)
+	lifecycle := setupEventPublishing(m, r.ec2Client)
+	journal, err := setupJournal(ctx, m, r.k8sClient)
+	if err != nil {
+		return err
+	}
+
+	// Disable ArgoCD auto-sync and suspend Flux, then scale down workloads
+	if err := mc.disableArgoCDAutoSync(); err != nil {
+		return err
+	}
+	if err := mc.suspendFlux(); err != nil {
+		mc.restoreOnError()
+		return err
+	}
+	if len(claimJobs) > 0 && !dryRun {
+		if err := mc.handleClaimJobs(); err != nil {
+			return err
+		}
+	}
+	totalWorkloads := calculateTotalWorkloads(workloadInfoByNS)
+	if totalWorkloads > 0 && !dryRun {
+		if err := handleWorkloadScaling(mc); err != nil {
+			return err
+		}
+	}
+	if err := mc.cordonSourceNodes(); err != nil {
+		mc.restoreOnError()
+		return err
+	}
+	if !dryRun {
+		fmt.Println(cliDimStyle.Render(icon("🪪") + i18n.T("cli.migration_id", m.MigrationID())))
+		if backupDir != "" {
+			fmt.Println(cliDimStyle.Render(icon("🗄") + i18n.T("cli.backup_dir", filepath.Join(backupDir, m.MigrationID()))))
+		}
+		if outputManifests != "" {
+			fmt.Println(cliDimStyle.Render(icon("📝") + i18n.T("cli.output_manifests", outputManifests)))
+		}
+	}
+
+	// Run migration UI, or report progress without it
+	var finalModel tea.Model
+	if progressFormat == progressFormatJSON || accessible || noTUI {
+		if progressFormat == progressFormatJSON {
+			out, err := openProgressOutput(progressOutput)
+			if err != nil {
+				mc.restoreOnError()
+				return err
+			}
+			defer func() { _ = out.Close() }()
+			events, warnings := migrator.NewJSONWriters(out)
+			m.AddListener(events)
+			m.AddWarningListener(warnings)
+		}
+		if accessible || (noTUI && progressFormat != progressFormatJSON) {
+			m.AddListener(migrator.NewPlainEventWriter(os.Stdout, len(config.PVCList)))
+		}
+
+		if runHeadless(ctx, m, plan) {
+			finalModel = ui.NewModel(m, config)
+		} else {
+			fmt.Println("\n" + i18n.T("cli.cancelled"))
+		}
+	} else {
+		finalModel, err = runMigrationUI(m, config, plan)
+		if err != nil {
+			mc.restoreOnError()
+			return err
+		}
+	}
+
+	// Restore workloads, KEDA, Flux, ArgoCD and cordoned nodes before the summary so
+	// their failures are listed in its action required section. Workloads are
+	// pinned to their zone first, so they are not rolled out again once scaled up.
+	pinWorkloads(ctx, m)
+	restoreWorkloads(ctx, r.k8sClient, mc, m)
+	resumeScaledObjects(ctx, r.k8sClient, mc, m)
+	resumeFlux(ctx, r.k8sClient, mc, m)
+	restoreArgoCDAutoSync(ctx, r.k8sClient, mc, m)
+	releaseSourceNodes(ctx, r.k8sClient, mc, m)
+	// Earlier batches would report the PVCs of the batches after them
+	if batch.last {
+		verifySourceZone(ctx, m)
+	}
+
+	// Optionally hydrate the new volumes in the background
+	createWarmupJobs(ctx, r.k8sClient, m)
+	if !batch.continued {
+		labelCompletedNamespaces(ctx, m)
+	}
+	reportHelmReleases(m, helmReleases)
+	if err := writeTerraformImports(m); err != nil {
+		slog.Error("failed to write Terraform import blocks", "error", err)
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.terraform_failed", err),
+			Action:  i18n.T("warn.terraform_action"),
+		})
+	}
+
+	finishMetrics(ctx, r.metrics, m)
+	finishProfiling(m)
+	finishNotifications(ctx, notifier)
+	lifecycle.finish()
+	journal.finish()
+	if batch.number > 0 {
+		r.recordBatch(m, batch)
+	}
+
+	// Last, so warnings raised while finishing are part of the report
+	appendReport(m)
+	logAPIUsage(m)
+
+	if outputFormat == outputFormatJSON {
+		if err := writeDocument(m.Result()); err != nil {
+			return err
+		}
+	}
+
+	// Print summary, or just the follow-ups if the run was cancelled
+	if fm, ok := finalModel.(ui.Model); ok {
+		printSummary(fm, m)
+		// With --watch, the next pass picks up PVCs created in the zone meanwhile
+		if fm.HasErrors() || (len(m.Stragglers()) > 0 && watchInterval == 0) {
+			return errPVCsFailed
+		}
+	} else {
+		printActionRequired(m)
+		return errCancelled
+	}
+
+	return nil
+}
+
+// printHeaderInfo prints the migration header information
+func printHeaderInfo() {
+	if configFile != "" {
+		fmt.Printf("%s %s\n", cliDimStyle.Render("📄 Config:"), configFile)
+	}
+	if kubeContext != "" {
+		fmt.Printf("%s %s\n", cliDimStyle.Render("☸  Context:"), kubeContext)
+	}
+	if asUser != "" {
+		fmt.Printf("%s %s\n", cliDimStyle.Render("👤 As:"), asUser)
+	}
+	if cfg.AWSRoleARN != "" {
+		fmt.Printf("%s %s\n", cliDimStyle.Render("🔑 AWS role:"), cfg.AWSRoleARN)
+	}
+}
+
+// impersonation returns the identity set with --as, --as-group and --as-uid
+func impersonation() k8s.Impersonation {
+	return k8s.Impersonation{User: asUser, Groups: asGroups, UID: asUID}
+}
+
+// newK8sClient connects to the cluster of --context as the --as identity, with
+// the CSI driver of the config and the parser of its volume handles
+func newK8sClient() (*k8s.Client, error) {
+	client, err := k8s.NewClient(kubeContext, impersonation())
+	if err != nil {
+		return nil, err
+	}
+	driver := k8s.CSIDriver{Name: cfg.CSIDriver}
+	if cfg.VolumeHandlePattern != "" {
+		if driver.ParseHandle, err = k8s.PatternVolumeHandleParser(cfg.VolumeHandlePattern); err != nil {
+			return nil, err
+		}
+	}
+	client.SetCSIDriver(driver)
+	return client, nil
+}
+
+// awsOptions returns the IAM role the EC2 client assumes, if any, and how many
+// times it attempts each call
+func awsOptions() aws.ClientOptions {
+	return aws.ClientOptions{
+		Role:        aws.AssumeRole{RoleARN: cfg.AWSRoleARN, ExternalID: cfg.AWSExternalID, SessionName: cfg.AWSSessionName},
+		MaxAttempts: awsMaxAttempts,
+	}
+}
+
+// resolveZoneIDs replaces the zone IDs given as target and source zones, such as
+// use1-az2, with the names the zones have in the account EC2 is called in. Zone
+// names point at different physical zones in each account, so given with an
+// IAM role to assume they are warned about.
+func resolveZoneIDs(ctx context.Context, ec2Client *aws.Client) error {
+	given := append([]string{targetZone, sourceZone}, targetZones...)
+	var byID, byName bool
+	for _, zone := range given {
+		switch {
+		case zone == "" || zone == migrator.TargetZoneAuto:
+		case aws.IsZoneID(zone):
+			byID = true
+		default:
+			byName = true
+		}
+	}
+	if byName && cfg.AWSRoleARN != "" {
+		slog.Warn("zones are given by name while EC2 is called as another role; names point at different physical zones in each account", "role", cfg.AWSRoleARN)
+		fmt.Println(cliWarningStyle.Render(i18n.T("zones.role_names", cfg.AWSRoleARN)))
+	}
+	if !byID {
+		return nil
+	}
+
+	ids, err := ec2Client.ZoneIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to look up the zone IDs: %w", err)
+	}
+	resolve := func(zone string) (string, error) {
+		if !aws.IsZoneID(zone) {
+			return zone, nil
+		}
+		name, err := aws.ZoneName(ids, zone)
+		if err != nil {
+			return "", err
+		}
+		slog.Info("resolved zone ID", "zoneId", zone, "zone", name)
+		fmt.Println(cliDimStyle.Render(i18n.T("zones.resolved", zone, name)))
+		return name, nil
+	}
+	if targetZone, err = resolve(targetZone); err != nil {
+		return err
+	}
+	if sourceZone, err = resolve(sourceZone); err != nil {
+		return err
+	}
+	for i, zone := range targetZones {
+		if targetZones[i], err = resolve(zone); err != nil {
+			return err
+		}
+	}
+	cfg.TargetZone, cfg.TargetZones, cfg.SourceZone = targetZone, targetZones, sourceZone
+	// A name and an ID may be the same zone
+	return cfg.Validate()
+}
+
+// logFastPathNamespaces logs the namespaces migrated without ArgoCD or workload
+// handling because none of their PVCs to migrate is mounted
+func logFastPathNamespaces(scaleNamespaces []string) {
+	for _, ns := range namespaces {
+		if !slices.Contains(scaleNamespaces, ns) {
+			slog.Info("no mounted PVC to migrate, skipping ArgoCD and workload scaling", "namespace", ns)
+		}
+	}
+}
+
+// calculateTotalWorkloads counts total workloads across all namespaces
+func calculateTotalWorkloads(workloadInfoByNS map[string][]k8s.WorkloadInfo) int {
+	total := 0
+	for _, workloads := range workloadInfoByNS {
+		total += len(workloads)
+	}
+	return total
+}
+
+// handleWorkloadScaling handles the scaling of workloads based on scale mode
+func handleWorkloadScaling(mc *migrationContext) error {
+	switch scaleMode {
+	case scaleModeManual:
+		return mc.handleManualScaling()
+	default:
+		return mc.handleAutoScaling()
+	}
+}
+
+// createMigrator creates the migrator instance with necessary clients
+func createMigrator(k8sClient *k8s.Client, ec2Client *aws.Client, allPVCs []pvcWithNamespace) (
+	*migrator.Migrator,
+	*migrator.Config,
+) {
+	// Build PVC list with namespace prefix
+	pvcListWithNS := make([]string, 0, len(allPVCs))
+	for _, pvc := range allPVCs {
+		pvcListWithNS = append(pvcListWithNS, fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name))
+	}
+
+	// Create migration config
+	config := &migrator.Config{
+		Namespaces:              namespaces,
+		TargetZone:              targetZone,
+		TargetZones:             targetZones,
+		SourceZone:              sourceZone,
+		StorageClass:            storageClass,
+		StorageClasses:          storageClassOverrides(allPVCs),
+		IncludeCoMounted:        includeCoMounted,
+		AllowEmptyZone:          allowEmptyZone,
+		CheckProvisioning:       checkProvisioning,
+		SimulateScheduling:      simulateScheduling,
+		CheckPermissions:        checkPermissions,
+		CheckQuotas:             checkQuotas,
+		SnapshotQuota:           cfg.SnapshotQuota,
+		StorageQuotasTiB:        cfg.StorageQuotasTiB,
+		FastSnapshotRestore:     fastRestore,
+		NamespaceStorageClasses: namespaceStorageClasses(),
+		MaxConcurrency:          maxConcurrency,
+		Scheduling:              scheduling,
+		MaxPerNamespace:         maxPerNamespace,
+		Priorities:              priorityOverrides(allPVCs),
+		NamespacePriorities:     namespacePriorities(),
+		Windows:                 namespaceWindows(),
+		PVCList:                 pvcListWithNS,
+		DryRun:                  dryRun,
+		KubeContext:             kubeContext,
+		StagedSnapshotMaxAge:    stagedSnapshotAge,
+		MaxSnapshotStaleness:    maxStaleness,
+		CheckWriteActivity:      checkWrites,
+		KMSKeyID:                kmsKeyID,
+		BackupVault:             aws.BackupVault{Name: cfg.BackupVault, RoleARN: cfg.BackupRoleARN, DeleteAfterDays: cfg.BackupRetentionDays},
+		VolumeType:              volumeType,
+		VolumeIOPS:              volumeIOPS,
+		VolumeThroughput:        volumeThroughput,
+		TagAnnotationPrefix:     tagPrefix,
+		Tags:                    cfg.Tags,
+		CSIDriver:               csiDriver,
+		MigrationID:             migrationID,
+		StepRetry:               migrator.RetryPolicy{MaxAttempts: stepMaxAttempts, Backoff: stepRetryBackoff, MaxBackoff: stepMaxBackoff},
+		BindTimeout:             bindTimeout,
+		BackupDir:               backupDir,
+		OutputManifests:         outputManifests,
+		JournalBackups:          journalBackups && journalNamespace != "",
+		VerifyMount:             verifyMount,
+		VerifyChecksum:          verifyChecksum,
+		ChecksumTimeout:         checksumTimeout,
+		FreezeFilesystem:        freezeFilesystem,
+		FreezeCommand:           freezeCommand,
+		ThawCommand:             thawCommand,
+		CheckImage:              cfg.WarmupImage,
+	}
+
+	m := migrator.New(config, k8sClient, ec2Client)
+	return m, config
+}
+
+// storageClassOverrides maps each PVC whose namespace or own config entry sets a
+// storage class other than the global one to that class
+func storageClassOverrides(allPVCs []pvcWithNamespace) map[string]string {
+	overrides := make(map[string]string)
+	for _, pvc := range allPVCs {
+		if class := cfg.StorageClassFor(pvc.Namespace, pvc.Name); class != storageClass {
+			overrides[pvc.Namespace+"/"+pvc.Name] = class
+		}
+	}
+	return overrides
+}
+
+// namespaceStorageClasses maps each configured namespace that sets its own storage
+// class to it, for PVCs the plan adds to the run
+func namespaceStorageClasses() map[string]string {
+	classes := make(map[string]string)
+	for _, ns := range cfg.Namespaces {
+		if ns.StorageClass != "" {
+			classes[ns.Name] = ns.StorageClass
+		}
+	}
+	return classes
+}
+
+// priorityOverrides maps each PVC whose namespace or own config entry sets a
+// priority other than normal to it
+func priorityOverrides(allPVCs []pvcWithNamespace) map[string]string {
+	overrides := make(map[string]string)
+	for _, pvc := range allPVCs {
+		if priority := cfg.PriorityFor(pvc.Namespace, pvc.Name); priority != migrator.PriorityNormal {
+			overrides[pvc.Namespace+"/"+pvc.Name] = priority
+		}
+	}
+	return overrides
+}
+
+// namespacePriorities maps each configured namespace that sets its own priority
+// to it, for PVCs the plan adds to the run
+func namespacePriorities() map[string]string {
+	priorities := make(map[string]string)
+	for _, ns := range cfg.Namespaces {
+		if ns.Priority != "" {
+			priorities[ns.Name] = ns.Priority
+		}
+	}
+	return priorities
+}
+
+// namespaceWindows maps each configured namespace with a window to it. Windows
+// were validated with the rest of the config in loadConfig.
+func namespaceWindows() map[string]migrator.TimeWindow {
+	windows := make(map[string]migrator.TimeWindow)
+	for _, ns := range cfg.Namespaces {
+		if ns.Window == "" {
+			continue
+		}
+		start, end, _ := ns.WindowBounds()
+		windows[ns.Name] = migrator.TimeWindow{Start: start, End: end}
+	}
+	return windows
+}
+
+// confirmProtectedContext makes the operator type the name of a kube context that
+// matches protectedContexts, or pass it with --confirm-context, before a run that
+// changes the cluster
+func confirmProtectedContext(kubeContext string) error {
+	if dryRun || !cfg.IsProtectedContext(kubeContext) {
+		return nil
+	}
+	if confirmContext != "" {
+		if confirmContext != kubeContext {
+			return fmt.Errorf("--confirm-context '%s' does not match the protected context '%s'", confirmContext, kubeContext)
+		}
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(cliWarningStyle.Render(icon("🛑") + i18n.T("cli.protected_context", kubeContext)))
+	fmt.Print(i18n.T("cli.protected_prompt"))
+	var input string
+	_, _ = fmt.Scanln(&input)
+	if strings.TrimSpace(input) != kubeContext {
+		return fmt.Errorf("the name typed does not match the protected context '%s'", kubeContext)
+	}
+	return nil
+}
+
+// handlePlanMode displays the migration plan
+func handlePlanMode(plan *migrator.MigrationPlan, safeMode bool) {
+	fmt.Print(formatPlan(plan))
+	hint := i18n.T("cli.plan_hint")
+	if safeMode {
+		hint = i18n.T("cli.safe_mode_hint")
+	}
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(hint))
+	fmt.Println()
+}
+
+// writeRunbook renders the plan as a runbook file, including the scale-down and
+// ArgoCD commands for the workloads that will be scaled
+func writeRunbook(plan *migrator.MigrationPlan, mc *migrationContext) error {
+	content := migrator.FormatRunbook(plan, migrator.RunbookOptions{
+		KubeContext:     kubeContext,
+		GeneratedAt:     time.Now(),
+		Workloads:       mc.workloadInfoByNS,
+		ArgoCDApps:      mc.argoCDApps,
+		ApplicationSets: mc.applicationSets,
+		ScaledObjects:   mc.scaledObjects,
+		FluxResources:   mc.fluxResources,
+	})
+	if err := os.WriteFile(runbookFile, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write runbook: %w", err)
+	}
+
+	fmt.Printf("%s %s\n", cliDimStyle.Render(icon("📖")+"Runbook:"), runbookFile)
+	return nil
+}
+
+// runMigrationUI creates and runs the Bubble Tea UI
+func runMigrationUI(m *migrator.Migrator, config *migrator.Config, plan *migrator.MigrationPlan) (tea.Model, error) {
+	logs := ui.NewLogBuffer(logPaneLines)
+	model := ui.NewModel(m, config).WithPlan(plan).WithLogs(logs)
+	if autoApprove {
+		model = model.WithAutoStart()
+	}
+	p := tea.NewProgram(model, tea.WithAltScreen())
+
+	consoleLog.hold()
+	untee := teeLogs(logs)
+	finalModel, err := p.Run()
+	untee()
+	consoleLog.release()
+	if err != nil {
+		return nil, fmt.Errorf("UI error: %w", err)
+	}
+
+	return finalModel, nil
+}
+
+// writeTerraformImports writes Terraform import blocks for the snapshots and
+// volumes the run created, so they can be brought under infrastructure code
+func writeTerraformImports(m *migrator.Migrator) error {
+	if terraformImports == "" || dryRun {
+		return nil
+	}
+	resources := m.CreatedResources()
+	content := migrator.FormatTerraformImports(resources, time.Now())
+	if err := os.WriteFile(terraformImports, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write Terraform import blocks: %w", err)
+	}
+	fmt.Printf("%s %s\n", cliDimStyle.Render(icon("🧾")+i18n.T("cli.terraform_imports", len(resources))), terraformImports)
+	return nil
+}
+
+// appendReport adds the manual commands for failed PVCs, the orphaned objects, the
+// volumes left in the source zone and the run's warnings to the runbook, so the printed runbook doubles as the
+// incident report
+func appendReport(m *migrator.Migrator) {
+	if runbookFile == "" {
+		return
+	}
+	content := migrator.FormatRemediations(m.Remediations())
+	if orphans := migrator.FormatOrphans(m.Orphans(), m.GetConfig().KubeContext); orphans != "" {
+		content += "\n" + orphans
+	}
+	if stragglers := migrator.FormatStragglers(m.Stragglers(), sourceZone); stragglers != "" {
+		content += "\n" + stragglers
+	}
+	if warnings := migrator.FormatWarnings(m.Warnings()); warnings != "" {
+		content += "\n" + warnings
+	}
+	content = strings.TrimPrefix(content, "\n")
+	if content == "" {
+		return
+	}
+	f, err := os.OpenFile(runbookFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err == nil {
+		_, err = f.WriteString("\n" + content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		slog.Warn("failed to append the run report to the runbook", "error", err)
+		return
+	}
+	fmt.Printf("%s %s\n", cliDimStyle.Render(icon("📖")+"Remediation commands and follow-ups added to runbook:"), runbookFile)
+}
+
+// verifySourceZone checks that an evacuation run left no volume in the source
+// zone. A check that cannot be made is listed under action required, as the
+// zone may not be empty.
+func verifySourceZone(ctx context.Context, m *migrator.Migrator) {
+	if err := m.VerifySourceZone(ctx); err != nil {
+		slog.Error("failed to verify the source zone is empty", "zone", sourceZone, "error", err)
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.verify_failed", sourceZone, err),
+			Action:  i18n.T("warn.verify_action", sourceZone),
+		})
+	}
+}
+
+// logAPIUsage logs the calls the run made to each API operation, to tune the
+// concurrency and rate limits of the next run
+func logAPIUsage(m *migrator.Migrator) {
+	for _, c := range m.APIUsage() {
+		slog.Info("API usage", "service", c.Service, "operation", c.Operation, "calls", c.Calls, "throttled", c.Throttled)
+	}
+}
+
+// pinWorkloads writes the patches pinning the workloads of the migrated PVCs to
+// their zone, and applies them with --apply-affinity
+func pinWorkloads(ctx context.Context, m *migrator.Migrator) {
+	if (affinityPatches == "" && !applyAffinity) || dryRun {
+		return
+	}
+
+	patches, err := m.AffinityPatches(ctx)
+	if err == nil && affinityPatches != "" {
+		err = migrator.WriteAffinityPatches(affinityPatches, patches)
+		if err == nil && len(patches) > 0 {
+			fmt.Println("\n" + icon("📌") + i18n.T("cli.affinity_written", len(patches), affinityPatches))
+		}
+	}
+	if err == nil && applyAffinity {
+		for _, p := range patches {
+			fmt.Printf("   %s\n", i18n.T("cli.affinity_applied", p.Kind, p.Namespace, p.Name, p.Zone))
+		}
+		err = m.ApplyAffinityPatches(ctx, patches)
+	}
+	if err != nil {
+		slog.Error("failed to pin workloads to their zone", "error", err)
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.affinity_failed", err),
+			Action:  i18n.T("warn.affinity_action"),
+		})
+	}
+}
+
+// restoreWorkloads scales workloads back to their original replica counts
+func restoreWorkloads(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.scaledWorkloads) == 0 || dryRun {
+		return
+	}
+
+	fmt.Println("\n" + icon("🚀") + i18n.T("cli.restoring"))
+	for _, sw := range mc.scaledWorkloads {
+		fmt.Printf("   %s\n", i18n.T("cli.restore_ns", sw.Namespace))
+		for _, w := range sw.Workloads {
+			fmt.Printf("     - %s\n", i18n.T("cli.restore_workload", w.Kind, w.Name, w.Replicas))
+		}
+		if err := k8sClient.ScaleUpWorkloads(ctx, sw.Namespace, sw.Workloads); err != nil {
+			slog.Error("failed to restore workloads", "namespace", sw.Namespace, "error", err)
+			fmt.Printf("   %s\n", icon("⚠️ ")+i18n.T("cli.restore_failed", sw.Namespace, err))
+			fmt.Printf("      %s\n", i18n.T("cli.restore_manually"))
+			commands := make([]string, 0, len(sw.Workloads))
+			for _, w := range sw.Workloads {
+				commands = append(commands, scaleCommand(w, sw.Namespace, w.Replicas))
+			}
+			m.AddWarning(migrator.Warning{
+				Message: i18n.T("warn.restore_failed", sw.Namespace, err),
+				Action:  i18n.T("warn.restore_action") + "\n" + strings.Join(commands, "\n"),
+			})
+		} else {
+			slog.Info("restored workloads", "namespace", sw.Namespace, "workloads", len(sw.Workloads))
+			fmt.Printf("   %s%s\n", icon("✅"), i18n.T("cli.restored", sw.Namespace))
+		}
+	}
+}
+
+// resumeScaledObjects hands the scaled workloads back to KEDA once they are
+// scaled up again
+func resumeScaledObjects(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.pausedObjects) == 0 || dryRun {
+		return
+	}
+
+	fmt.Println("\n" + icon("▶️ ") + i18n.T("cli.keda_resuming"))
+	for _, obj := range mc.pausedObjects {
+		fmt.Printf("   - %s/%s\n", obj.Namespace, obj.Name)
+	}
+	if err := k8sClient.ResumeScaledObjects(ctx, mc.pausedObjects); err != nil {
+		slog.Error("failed to resume KEDA autoscaling", "error", err)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.keda_failed", err))
+		commands := make([]string, 0, len(mc.pausedObjects))
+		for _, obj := range mc.pausedObjects {
+			commands = append(commands, migrator.KEDAResumeCommand(obj, kubeContext))
+		}
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.keda_failed", err),
+			Action:  i18n.T("warn.keda_action") + "\n" + strings.Join(commands, "\n"),
+		})
+	} else {
+		fmt.Println("   " + icon("✅") + i18n.T("cli.keda_resumed"))
+	}
+}
+
+// resumeFlux resumes the reconciliation of the Flux objects suspended for the run
+func resumeFlux(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.suspendedFlux) == 0 || dryRun {
+		return
+	}
+
+	fmt.Println("\n" + icon("▶️ ") + i18n.T("cli.flux_resuming"))
+	for _, res := range mc.suspendedFlux {
+		fmt.Printf("   - %s %s/%s\n", res.Kind, res.Namespace, res.Name)
+	}
+	if err := k8sClient.ResumeFlux(ctx, mc.suspendedFlux); err != nil {
+		slog.Error("failed to resume Flux reconciliation", "error", err)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.flux_failed", err))
+		commands := make([]string, 0, len(mc.suspendedFlux))
+		for _, res := range mc.suspendedFlux {
+			commands = append(commands, migrator.FluxResumeCommand(res, kubeContext))
+		}
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.flux_failed", err),
+			Action:  i18n.T("warn.flux_action") + "\n" + strings.Join(commands, "\n"),
+		})
+	} else {
+		fmt.Println("   " + icon("✅") + i18n.T("cli.flux_resumed"))
+	}
+}
+
+// restoreArgoCDAutoSync re-enables auto-sync for ArgoCD applications
+func restoreArgoCDAutoSync(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.argoCDApps) == 0 || dryRun {
+		return
+	}
+
+	fmt.Println("\n" + icon("🔓") + i18n.T("cli.argocd_enabling"))
+	for _, app := range mc.argoCDApps {
+		fmt.Printf("   - %s/%s\n", app.Namespace, app.Name)
+	}
+	if err := mc.argoCD.EnableArgoCDAutoSync(ctx, mc.argoCDApps); err != nil {
+		slog.Error("failed to re-enable ArgoCD auto-sync", "error", err)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.argocd_failed", err))
+		fmt.Printf("   %s\n", i18n.T("cli.argocd_manually"))
+		commands := make([]string, 0, len(mc.argoCDApps))
+		for _, app := range mc.argoCDApps {
+			commands = append(commands, migrator.ArgoCDEnableCommand(app, kubeContext))
+		}
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.argocd_failed", err),
+			Action:  i18n.T("warn.argocd_action") + "\n" + strings.Join(commands, "\n"),
+		})
+	} else {
+		fmt.Println("   " + icon("✅") + i18n.T("cli.argocd_enabled"))
+	}
+	resumeApplicationSets(ctx, k8sClient, mc, m)
+}
+
+// resumeApplicationSets lets the paused ApplicationSets update their applications
+// again, once auto-sync is back on
+func resumeApplicationSets(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.applicationSets) == 0 {
+		return
+	}
+
+	fmt.Println("\n" + icon("▶️ ") + i18n.T("cli.appset_resuming"))
+	if err := k8sClient.ResumeApplicationSets(ctx, mc.applicationSets); err != nil {
+		slog.Error("failed to resume ArgoCD ApplicationSets", "error", err)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.appset_failed", err))
+		commands := make([]string, 0, len(mc.applicationSets))
+		for _, set := range mc.applicationSets {
+			commands = append(commands, migrator.ApplicationSetResumeCommand(set, kubeContext))
+		}
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.appset_failed", err),
+			Action:  i18n.T("warn.appset_action") + "\n" + strings.Join(commands, "\n"),
+		})
+	} else {
+		fmt.Println("   " + icon("✅") + i18n.T("cli.appset_resumed"))
+	}
+}
+
+// podsScheduledTimeout is how long the pods scaled back up get to be placed in
+// the other zones before the nodes of the source zone are uncordoned
+const podsScheduledTimeout = 2 * time.Minute
+
+// releaseSourceNodes uncordons the nodes cordoned for the run once the pods scaled
+// back up have been placed, unless --keep-cordoned leaves them for the zone to be
+// torn down
+func releaseSourceNodes(ctx context.Context, k8sClient *k8s.Client, mc *migrationContext, m *migrator.Migrator) {
+	if len(mc.cordonedNodes) == 0 {
+		return
+	}
+
+	commands := make([]string, 0, len(mc.cordonedNodes))
+	for _, node := range mc.cordonedNodes {
+		commands = append(commands, uncordonCommand(node))
+	}
+	if keepCordoned {
+		fmt.Println("\n" + icon("🚧") + i18n.T("cli.nodes_kept", len(mc.cordonedNodes), sourceZone))
+		if failed := len(m.Remediations()); failed > 0 {
+			// PVCs that failed are still in the source zone, so their pods can only run there
+			m.AddWarning(migrator.Warning{
+				Message: i18n.T("warn.cordoned_failed", sourceZone, failed),
+				Action:  i18n.T("warn.uncordon_action") + "\n" + strings.Join(commands, "\n"),
+			})
+		}
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, podsScheduledTimeout)
+	defer cancel()
+	for _, sw := range mc.scaledWorkloads {
+		if err := k8sClient.WaitForPodsScheduled(waitCtx, sw.Namespace, podsScheduledTimeout); err != nil {
+			slog.Warn("pods not scheduled before uncordoning", "namespace", sw.Namespace, "error", err)
+			break
+		}
+	}
+
+	fmt.Println("\n" + icon("🔓") + i18n.T("cli.nodes_uncordoning", len(mc.cordonedNodes), sourceZone))
+	if err := k8sClient.UncordonNodes(ctx, mc.cordonedNodes); err != nil {
+		slog.Error("failed to uncordon nodes", "zone", sourceZone, "error", err)
+		fmt.Println(icon("⚠️ ") + i18n.T("cli.uncordon_failed", err))
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.uncordon_failed", sourceZone, err),
+			Action:  i18n.T("warn.uncordon_action") + "\n" + strings.Join(commands, "\n"),
+		})
+	} else {
+		slog.Info("uncordoned source zone nodes", "zone", sourceZone, "nodes", mc.cordonedNodes)
+		fmt.Println("   " + icon("✅") + i18n.T("cli.nodes_uncordoned"))
+	}
+}
+
+// uncordonCommand returns the kubectl command that uncordons a node
+func uncordonCommand(node string) string {
+	cmd := "kubectl uncordon " + node
+	if kubeContext != "" {
+		cmd += " --context=" + kubeContext
+	}
+	return cmd
+}
+
+// warmupConsumerTimeout is how long warm-up waits for the scaled-up application
+// pods to start, so each Job can be pinned to the node its volume is attached to
+const warmupConsumerTimeout = 5 * time.Minute
+
+// createWarmupJobs starts a read job for every migrated PVC so EBS lazily loads
+// the snapshot blocks before the application touches them
+func createWarmupJobs(ctx context.Context, k8sClient *k8s.Client, m *migrator.Migrator) {
+	if !warmupJobs || dryRun || outputManifests != "" {
+		return
+	}
+
+	statuses := m.GetStatuses()
+	names := make([]string, 0, len(statuses))
+	for name, s := range statuses {
+		// Warm-up jobs read the files of a mounted filesystem, which raw block
+		// claims do not have
+		if s.Step == migrator.StepDone && !s.Block {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	fmt.Println("\n" + icon("🔥") + i18n.T("cli.warmup_creating"))
+
+	// Wait for the consumers of all PVCs at once rather than one timeout per PVC
+	jobNames := make([]string, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		s := statuses[name]
+		wg.Go(func() {
+			jobNames[i], errs[i] = k8sClient.CreateWarmupJob(ctx, s.Namespace, s.PVCName, cfg.WarmupImage, warmupConsumerTimeout)
+		})
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		s := statuses[name]
+		switch err := errs[i]; {
+		case errors.Is(err, k8s.ErrNoClaimConsumer):
+			fmt.Printf("   - %s/%s: %s\n", s.Namespace, s.PVCName, cliDimStyle.Render(i18n.T("cli.warmup_skipped")))
+		case err != nil:
+			fmt.Printf("   %s%s\n", icon("⚠️ "), i18n.T("cli.warmup_failed", err))
+			m.AddWarning(migrator.Warning{
+				PVC:     name,
+				Message: i18n.T("warn.warmup_failed", err),
+				Action:  i18n.T("warn.warmup_action"),
+			})
+		default:
+			fmt.Printf("   - %s/%s\n", s.Namespace, jobNames[i])
+		}
+	}
+	fmt.Printf("   %s\n", cliDimStyle.Render(i18n.T("cli.warmup_labelled", k8s.LabelWarmup)))
+}
+
+// labelCompletedNamespaces stamps the namespaces whose PVCs are all in their target
+// zone, so later runs and people can tell which are done
+func labelCompletedNamespaces(ctx context.Context, m *migrator.Migrator) {
+	if !labelNamespaces || dryRun {
+		return
+	}
+
+	labelled, err := m.LabelCompletedNamespaces(ctx, time.Now())
+	if len(labelled) > 0 {
+		fmt.Println("\n" + icon("🏷️ ") + i18n.T("cli.namespaces_labelled", strings.Join(labelled, ", "), k8s.LabelZone, k8s.LabelCompletedAt))
+	}
+	if err != nil {
+		slog.Error("failed to label completed namespaces", "error", err)
+		m.AddWarning(migrator.Warning{
+			Message: i18n.T("warn.label_failed", err),
+			Action:  i18n.T("warn.label_action"),
+		})
 	}
 }
 
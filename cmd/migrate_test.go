@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAutoApprove(t *testing.T) {
+	t.Parallel()
+
+	defaultMode := migrateCmd.Flags().Lookup("mode").DefValue
+	cases := []struct {
+		name        string
+		autoApprove bool
+		mode        string
+		errContains string
+	}{
+		{name: "yes_default_mode", autoApprove: true, mode: defaultMode, errContains: "--yes needs --mode auto"},
+		{name: "yes_auto_mode", autoApprove: true, mode: scaleModeAuto},
+		{name: "no_yes_default_mode", mode: defaultMode},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkAutoApprove(tc.autoApprove, tc.mode)
+			if tc.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+			assert.NotContains(t, err.Error(), "--scale-mode")
+		})
+	}
+}
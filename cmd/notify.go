@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+	"github.com/cesarempathy/pv-zone-migrator/internal/notify"
+)
+
+// setupNotifications subscribes the configured webhooks to the migrator's events.
+// The webhooks were validated with the rest of the config in loadConfig. It
+// returns nil when no notifications are configured.
+func setupNotifications(m *migrator.Migrator) *notify.Notifier {
+	if len(cfg.Notifications) == 0 {
+		return nil
+	}
+
+	webhooks := make([]notify.Webhook, 0, len(cfg.Notifications))
+	for _, n := range cfg.Notifications {
+		webhooks = append(webhooks, notify.Webhook{URL: n.URL, Format: n.Format, Events: n.Events})
+	}
+
+	mcfg := m.GetConfig()
+	notifier := notify.New(webhooks, notify.RunInfo{
+		KubeContext: kubeContext,
+		Namespaces:  mcfg.Namespaces,
+		TargetZone:  mcfg.Destination(),
+		Total:       len(mcfg.PVCList),
+		DryRun:      mcfg.DryRun,
+	}, nil)
+	m.AddListener(notifier.Observe)
+	return notifier
+}
+
+// finishNotifications sends the run summary and waits for queued notifications
+func finishNotifications(ctx context.Context, notifier *notify.Notifier) {
+	if notifier != nil {
+		flushCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		notifier.Summary(flushCtx)
+	}
+}
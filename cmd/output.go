@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Formats of the plan and result documents, see --output
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// writeDocument writes a plan or result document of pkg/api/v1 to stdout, which
+// reserveStdoutForJSON has kept free of any other output
+func writeDocument(doc any) error {
+	enc := json.NewEncoder(processStdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+	"github.com/cesarempathy/pv-zone-migrator/internal/profiling"
+)
+
+// setupProfiling starts the pprof endpoint if requested. Like the metrics
+// endpoint, it starts before anything touches the cluster.
+func setupProfiling() (*http.Server, error) {
+	if pprofAddr == "" {
+		return nil, nil
+	}
+	srv, err := profiling.Serve(pprofAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start pprof endpoint: %w", err)
+	}
+	fmt.Printf("%s http://%s/debug/pprof/\n", cliDimStyle.Render(icon("🩺")+"pprof:"), pprofAddr)
+	return srv, nil
+}
+
+// finishProfiling writes heap and goroutine profiles when a PVC failed and
+// --profile-dir is set
+func finishProfiling(m *migrator.Migrator) {
+	if profileDir != "" && hasFailedPVCs(m) {
+		paths, err := profiling.WriteProfiles(profileDir, time.Now())
+		if err != nil {
+			m.AddWarning(migrator.Warning{
+				Message: i18n.T("warn.profile_failed", err),
+				Action:  i18n.T("warn.profile_action"),
+			})
+		}
+		for _, path := range paths {
+			slog.Info("profile written", "path", path)
+			fmt.Println(cliDimStyle.Render(i18n.T("cli.profile_written", path)))
+		}
+	}
+}
+
+// hasFailedPVCs reports whether the migration of any PVC failed
+func hasFailedPVCs(m *migrator.Migrator) bool {
+	for _, s := range m.GetStatuses() {
+		if s.Step == migrator.StepFailed {
+			return true
+		}
+	}
+	return false
+}
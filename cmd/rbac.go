@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+var (
+	rbacName           string
+	rbacServiceAccount string
+)
+
+var rbacCmd = &cobra.Command{
+	Use:   "rbac",
+	Short: "Print the minimal RBAC manifests a migration needs",
+	Long: `Print the ClusterRole and Roles with only the verbs the configured migration uses on
+PVCs, PVs, Deployments, StatefulSets, Argo Rollouts, Pods, Nodes, ArgoCD Applications, Flux objects, Helm release Secrets and the journal ConfigMap, so it can run with least
+privilege instead of cluster-admin. Namespaced permissions are granted with a Role in each
+namespace, or cluster-wide when namespaces are discovered with --all-namespaces or
+--namespace-selector. Pass --service-account to also print the bindings.`,
+	Example: `  pvc-migrator rbac -c config.yaml --service-account ops/pvc-migrator | kubectl apply -f -`,
+	Args:    cobra.NoArgs,
+	RunE:    runRBAC,
+}
+
+func init() {
+	rbacCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) containing the PVCs (comma-separated)")
+	rbacCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Grant access to EBS-backed PVCs in every namespace")
+	rbacCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Grant access for namespaces found by this label selector")
+	rbacCmd.Flags().BoolVar(&skipArgoCD, "skip-argocd", false, "Leave out ArgoCD Application permissions")
+	rbacCmd.Flags().StringSliceVar(&argoCDNamespaces, "argocd-namespaces", nil, "Namespaces to search for ArgoCD applications")
+	rbacCmd.Flags().StringVar(&argoCDServer, "argocd-server", "", "Leave out ArgoCD permissions, as auto-sync is turned off through this argocd-server")
+	rbacCmd.Flags().StringVar(&argoCDAppSets, "argocd-appsets", "", "Include the permissions to pause ApplicationSets when set to 'pause'")
+	rbacCmd.Flags().StringVar(&argoCDStrategy, "argocd-strategy", "", "Include the permissions to update AppProjects when set to 'sync-window'")
+	rbacCmd.Flags().BoolVar(&skipFlux, "skip-flux", false, "Leave out Flux Kustomization and HelmRelease permissions")
+	rbacCmd.Flags().StringSliceVar(&fluxNamespaces, "flux-namespaces", nil, "Namespaces to search for Flux objects (default flux-system)")
+	rbacCmd.Flags().BoolVar(&skipKEDA, "skip-keda", false, "Leave out KEDA ScaledObject permissions")
+	rbacCmd.Flags().BoolVar(&skipRollouts, "skip-rollouts", false, "Leave out Argo Rollout permissions")
+	rbacCmd.Flags().StringVar(&jobPolicy, "jobs", "", "Include the permissions to delete running Jobs when set to 'delete'")
+	rbacCmd.Flags().BoolVar(&warmupJobs, "warmup", false, "Include the permissions to create warm-up jobs")
+	rbacCmd.Flags().BoolVar(&verifyMount, "verify-mount", false, "Include the permissions to run mount check pods")
+	rbacCmd.Flags().BoolVar(&verifyChecksum, "verify-checksum", false, "Include the permissions to run checksum pods")
+	rbacCmd.Flags().BoolVar(&freezeFilesystem, "freeze", false, "Include the permissions to run freeze hooks in pods")
+	rbacCmd.Flags().BoolVar(&labelNamespaces, "label-namespaces", false, "Include the permissions to label completed namespaces")
+	rbacCmd.Flags().StringVar(&helmValues, "helm-values", "", "Include the permissions to read Helm release secrets when set")
+	rbacCmd.Flags().BoolVar(&checkProvisioning, "check-provisioning", false, "Include the permissions to read Karpenter NodePools and EC2NodeClasses")
+	rbacCmd.Flags().BoolVar(&simulateScheduling, "simulate-scheduling", false, "Include the permissions to dry-run pods and list the pods of every namespace")
+	rbacCmd.Flags().StringVar(&sourceZone, "from-zone", "", "Include the permissions to check this zone is left empty")
+	rbacCmd.Flags().StringVarP(&targetZone, "zone", "z", "", "Include the permissions to pick the zone of each namespace when set to 'auto'")
+	rbacCmd.Flags().StringVar(&journalNamespace, "journal-namespace", "", "Include the permissions to write the run's journal to this namespace")
+	rbacCmd.Flags().StringVar(&rbacName, "name", "pvc-migrator", "Name of the roles and bindings")
+	rbacCmd.Flags().StringVar(&rbacServiceAccount, "service-account", "", "Bind the roles to this service account (namespace/name)")
+
+	rootCmd.AddCommand(rbacCmd)
+}
+
+func runRBAC(_ *cobra.Command, _ []string) error {
+	opts := k8s.RBACOptions{
+		Name:               rbacName,
+		Namespaces:         namespaces,
+		DiscoverNamespaces: cfg.DiscoversNamespaces(),
+		KEDA:               !skipKEDA,
+		Rollouts:           !skipRollouts,
+		DeleteJobs:         jobPolicy == jobsDelete,
+		Warmup:             warmupJobs,
+		VerifyMount:        verifyMount,
+		VerifyChecksum:     verifyChecksum,
+		FreezeFilesystem:   freezeFilesystem,
+		LabelNamespaces:    labelNamespaces,
+		HelmValues:         helmValues != "",
+		CordonNodes:        cordonNodes,
+		AutoZone:           targetZone == migrator.TargetZoneAuto,
+		CheckProvisioning:  checkProvisioning,
+		SimulateScheduling: simulateScheduling,
+		VerifySourceZone:   sourceZone != "",
+		JournalNamespace:   journalNamespace,
+		ServiceAccount:     rbacServiceAccount,
+	}
+	if !skipArgoCD && argoCDServer == "" {
+		opts.ArgoCDNamespaces = argoCDNamespaces
+		opts.ArgoCDSyncWindows = argoCDStrategy == k8s.ArgoCDStrategySyncWindow
+		opts.ArgoCDAppSets = argoCDAppSets == appSetsPause
+	}
+	if !skipFlux {
+		opts.FluxNamespaces = fluxNamespaces
+		if len(opts.FluxNamespaces) == 0 {
+			opts.FluxNamespaces = []string{k8s.DefaultFluxNamespace}
+		}
+	}
+	manifests, err := k8s.RBACManifests(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Print(manifests)
+	return nil
+}
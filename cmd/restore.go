@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// restorePVC and restoreRun are the --pvc and --run of restore
+var (
+	restorePVC string
+	restoreRun string
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Move one migrated PVC back to its original zone",
+	Long: `Undo the migration of one PVC: create a volume in the zone it was migrated out of
+from the snapshot the run took, recorded in the journal by migrate --journal-namespace, and
+bind the PVC to it. The workloads of the PVC's namespace are scaled down while the claim is
+switched over and back up after. Data written since the migration is lost. The volume the
+PVC is on now is kept, so the restore can itself be undone. Pause ArgoCD auto-sync of the
+namespace first, or it may scale the workloads back up during the restore.`,
+	Example: `  pvc-migrator restore --pvc payments/data-postgres-0 --run 20261016T120000Z-0a1b --journal-namespace ops`,
+	Args:    cobra.NoArgs,
+	RunE:    runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&restorePVC, "pvc", "", "PVC to restore, as namespace/name")
+	restoreCmd.Flags().StringVar(&restoreRun, "run", "", "Migration ID of the run that migrated the PVC (see history)")
+	restoreCmd.Flags().StringVar(&journalNamespace, "journal-namespace", "", "Namespace the journal ConfigMaps were written to")
+	restoreCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	restoreCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	restoreCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	restoreCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
+	restoreCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	restoreCmd.Flags().BoolVarP(&autoApprove, "yes", "y", false, "Start the restore without asking for confirmation")
+	restoreCmd.Flags().StringVar(&backupDir, "backup-dir", "backups", "Save the PVC and PV to <dir>/<migration ID>/ before deleting them ('' to disable)")
+	restoreCmd.Flags().StringVar(&confirmContext, "confirm-context", "", "Name of the protected kube context, instead of typing it when asked")
+
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	if restoreRun == "" {
+		return fmt.Errorf("--run is required: the migration ID of the run that migrated the PVC")
+	}
+	if !strings.Contains(restorePVC, "/") {
+		return fmt.Errorf("invalid --pvc '%s': must be namespace/name", restorePVC)
+	}
+	if journalNamespace == "" {
+		return fmt.Errorf("--journal-namespace (or journalNamespace in the config) is required to find the run's snapshot")
+	}
+	printHeaderInfo()
+
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	if skipRollouts {
+		k8sClient.SkipRollouts()
+	}
+
+	record, err := migrator.ReadJournal(ctx, k8sClient, journalNamespace, restoreRun)
+	if err != nil {
+		return err
+	}
+	point, err := record.RestorePoint(restorePVC)
+	if err != nil {
+		return err
+	}
+	namespace, name := migrator.ParsePVCName(restorePVC)
+	info, err := k8sClient.GetPVCInfo(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(cliHeaderStyle.Render(i18n.T("restore.title", restorePVC, point.MigrationID)))
+	fmt.Println(i18n.T("restore.snapshot", point.Zone, point.SnapshotID, point.SourceVolumeID))
+	fmt.Println(i18n.T("restore.current", info.PVName, info.VolumeID))
+	fmt.Println(i18n.T("restore.scale", namespace))
+	fmt.Println(cliWarningStyle.Render(i18n.T("restore.data_loss")))
+	fmt.Println()
+	if err := confirmProtectedContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	if !autoApprove && !confirm(i18n.T("restore.confirm")) {
+		fmt.Println(i18n.T("restore.cancelled"))
+		return nil
+	}
+
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+	m := migrator.New(&migrator.Config{
+		Namespaces:     []string{namespace},
+		PVCList:        []string{restorePVC},
+		TargetZone:     point.Zone,
+		StorageClass:   info.StorageClass,
+		MaxConcurrency: 1,
+		KubeContext:    k8sClient.ContextName(),
+		KMSKeyID:       kmsKeyID,
+		StepRetry:      migrator.RetryPolicy{MaxAttempts: stepMaxAttempts, Backoff: stepRetryBackoff, MaxBackoff: stepMaxBackoff},
+		BindTimeout:    bindTimeout,
+		BackupDir:      backupDir,
+	}, k8sClient, ec2Client)
+
+	workloads, err := k8sClient.ScaleDownWorkloads(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to scale down workloads in %s: %w", namespace, err)
+	}
+	// The workloads come back up whether the claim was switched over or not
+	defer func() {
+		if err := k8sClient.ScaleUpWorkloads(ctx, namespace, workloads); err != nil {
+			fmt.Println(cliWarningStyle.Render(i18n.T("warn.restore_failed", namespace, err)))
+		}
+	}()
+	if err := k8sClient.WaitForWorkloadsScaledDown(ctx, namespace, 5*time.Minute); err != nil {
+		return fmt.Errorf("workloads in %s did not scale down: %w", namespace, err)
+	}
+
+	volumeID, err := m.RestorePVC(ctx, point)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", restorePVC, err)
+	}
+	fmt.Println(cliSuccessStyle.Render(i18n.T("restore.done", restorePVC, volumeID, point.Zone)))
+	fmt.Println(cliDimStyle.Render(i18n.T("restore.kept", info.VolumeID)))
+	return nil
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cesarempathy/pv-zone-migrator/internal/config"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
 )
 
 var (
@@ -17,17 +19,99 @@ var (
 	cfg *config.Config
 
 	// CLI flag values (can override config file)
-	kubeContext      string
-	namespaces       []string
-	targetZone       string
-	storageClass     string
-	maxConcurrency   int
-	dryRun           bool
-	skipArgoCD       bool
-	argoCDNamespaces []string
-	planOnly         bool
-	scaleMode        string // "auto" or "manual"
-	verbose          bool
+	kubeContext        string
+	namespaces         []string
+	targetZone         string
+	targetZones        []string
+	storageClass       string
+	maxConcurrency     int
+	scheduling         string
+	maxPerNamespace    int
+	dryRun             bool
+	skipArgoCD         bool
+	skipFlux           bool
+	fluxNamespaces     []string
+	skipKEDA           bool
+	skipRollouts       bool
+	jobPolicy          string // "wait" or "delete"
+	jobTimeout         time.Duration
+	argoCDNamespaces   []string
+	argoCDStrategy     string // "policy", "sync-window" or "annotation"
+	argoCDAppSets      string // "warn" or "pause"
+	argoCDServer       string
+	argoCDInsecure     bool
+	planOnly           bool
+	scaleMode          string // "auto" or "manual"
+	verbose            bool
+	warmupJobs         bool
+	labelNamespaces    bool
+	affinityPatches    string
+	applyAffinity      bool
+	helmValues         string
+	retryFailed        bool
+	includeCoMounted   bool
+	allowEmptyZone     bool
+	checkProvisioning  bool
+	simulateScheduling bool
+	checkQuotas        bool
+	checkPermissions   bool
+	fastRestore        bool
+	runbookFile        string
+	terraformImports   string
+	progressFormat     string
+	outputFormat       string
+	progressOutput     string
+	logFile            string
+	logLevel           string
+	accessible         bool
+	autoApprove        bool
+	execute            bool
+	watchInterval      time.Duration
+	batchSize          int
+	stateFile          string
+	confirmContext     string
+	asUser             string
+	asGroups           []string
+	asUID              string
+	noTUI              bool
+	metricsAddr        string
+	metricsPushgateway string
+	metricsTextfileDir string
+	pprofAddr          string
+	profileDir         string
+	otlpEndpoint       string
+	snsTopicARN        string
+	eventBusName       string
+	stagedSnapshotAge  time.Duration
+	maxStaleness       time.Duration
+	checkWrites        bool
+	awsMaxAttempts     int
+	kmsKeyID           string
+	volumeType         string
+	volumeIOPS         int32
+	volumeThroughput   int32
+	tagPrefix          string
+	csiDriver          string
+	migrationID        string
+	stepMaxAttempts    int
+	stepRetryBackoff   time.Duration
+	stepMaxBackoff     time.Duration
+	sourceZone         string
+	cordonNodes        bool
+	keepCordoned       bool
+	allNamespaces      bool
+	namespaceSelector  string
+	journalNamespace   string
+	backupDir          string
+	outputManifests    string
+	bindTimeout        time.Duration
+	journalBackups     bool
+	verifyMount        bool
+	verifyChecksum     bool
+	checksumTimeout    time.Duration
+	freezeFilesystem   bool
+	freezeCommand      string
+	thawCommand        string
 )
 
 var rootCmd = &cobra.Command{
@@ -44,25 +128,40 @@ This tool performs the following steps for each PVC:
 5. Creates new static PV and bound PVC in the target zone
 
 Example:
-  pvc-migrator migrate -n budibase -z eu-west-1a -s gp3 \
+  pvc-migrator migrate -n budibase -z eu-west-1a -s gp3 --execute \
     -p database-storage-0,database-storage-1,minio-data
   
   # Multiple namespaces:
-  pvc-migrator migrate -n ns1,ns2,ns3 -z eu-west-1a -s gp3
+  pvc-migrator migrate -n ns1,ns2,ns3 -z eu-west-1a -s gp3 --execute
     
   # Using a config file:
-  pvc-migrator migrate -c config.yaml`,
+  pvc-migrator migrate -c config.yaml --execute`,
 	Version: "1.0.0",
 	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-		return loadConfig(cmd)
+		if err := loadConfig(cmd); err != nil {
+			return err
+		}
+		if err := i18n.SetLocale(i18n.Detect(cfg.Locale)); err != nil {
+			return err
+		}
+		return initLogging(verbose, logFile, logLevel)
+	},
+	PersistentPostRunE: func(_ *cobra.Command, _ []string) error {
+		if logCloser != nil {
+			return logCloser.Close()
+		}
+		return nil
 	},
 }
 
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Start the PVC migration process",
-	Long:  `Migrate specified PVCs to the target AWS Availability Zone.`,
-	RunE:  runMigrate,
+	Long: `Migrate specified PVCs to the target AWS Availability Zone.
+
+Nothing is changed without --execute (or execute: true in the config file): the plan is
+shown as with --plan --dry-run.`,
+	RunE: runMigrate,
 }
 
 var initConfigCmd = &cobra.Command{
@@ -86,19 +185,102 @@ var initConfigCmd = &cobra.Command{
 func init() {
 	// Global config flag available to all commands
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to YAML configuration file")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append structured JSON logs to this file")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn, error (default info with --log-file, warn otherwise)")
 
 	// Migration-specific flags
 	migrateCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	migrateCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	migrateCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	migrateCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
 	migrateCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) containing the PVCs (comma-separated, discovers all PVCs)")
-	migrateCmd.Flags().StringVarP(&targetZone, "zone", "z", "", "Target AWS Availability Zone")
+	migrateCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Migrate EBS-backed PVCs in every namespace")
+	migrateCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Migrate EBS-backed PVCs in namespaces matching this label selector (e.g. team=payments)")
+	migrateCmd.Flags().StringVarP(&targetZone, "zone", "z", "", "Target AWS Availability Zone, or 'auto' for the least-loaded healthy zone of each namespace")
+	migrateCmd.Flags().StringSliceVar(&targetZones, "zones", nil, "Spread PVCs across these Availability Zones instead of one (comma-separated)")
+	migrateCmd.Flags().StringVar(&sourceZone, "from-zone", "", "Only migrate PVCs whose volumes are in this Availability Zone")
+	migrateCmd.Flags().BoolVar(&cordonNodes, "cordon-source-nodes", false, "Cordon the nodes of --from-zone during the run so workloads scaled back up are not scheduled there")
+	migrateCmd.Flags().BoolVar(&keepCordoned, "keep-cordoned", false, "Leave the nodes cordoned by --cordon-source-nodes cordoned after the run")
 	migrateCmd.Flags().StringVarP(&storageClass, "storage-class", "s", "", "Storage class for the new PVs")
 	migrateCmd.Flags().IntVar(&maxConcurrency, "concurrency", 0, "Maximum concurrent migrations")
+	migrateCmd.Flags().StringVar(&scheduling, "scheduling", "", "Order PVCs are started in: 'fifo' (default) or 'round-robin' across namespaces")
+	migrateCmd.Flags().IntVar(&maxPerNamespace, "max-per-namespace", 0, "Maximum concurrent migrations of one namespace (0 for no cap)")
 	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
+	migrateCmd.Flags().BoolVar(&execute, "execute", false, "Make changes; without it (or execute: true in the config) only the plan is shown")
+	migrateCmd.Flags().DurationVar(&watchInterval, "watch", 0, "Discover and migrate again this long after each run (e.g. 30m) until nothing is left to migrate")
+	migrateCmd.Flags().IntVar(&batchSize, "batch-size", 0, "Plan and migrate at most this many PVCs at a time, recording each one's result in --state-file (0 for all at once)")
+	migrateCmd.Flags().StringVar(&stateFile, "state-file", "", "File a --batch-size run records each PVC's result in and resumes from (default pvc-migrator-state.jsonl)")
 	migrateCmd.Flags().BoolVar(&skipArgoCD, "skip-argocd", false, "Skip ArgoCD auto-sync detection and handling")
 	migrateCmd.Flags().StringSliceVar(&argoCDNamespaces, "argocd-namespaces", nil, "Namespaces to search for ArgoCD applications")
+	migrateCmd.Flags().StringVar(&argoCDServer, "argocd-server", "", "Turn ArgoCD auto-sync off through this argocd-server's API, with the token in ARGOCD_AUTH_TOKEN, instead of editing Applications")
+	migrateCmd.Flags().BoolVar(&argoCDInsecure, "argocd-insecure", false, "Skip verifying the certificate of --argocd-server")
+	migrateCmd.Flags().StringVar(&argoCDAppSets, "argocd-appsets", "", "ApplicationSets that would revert the auto-sync change: 'warn' (default) with guidance per app, or 'pause' them (create-only) during the run")
+	migrateCmd.Flags().StringVar(&argoCDStrategy, "argocd-strategy", "", "How ArgoCD auto-sync is turned off: 'policy' (default) removes the automated policy, 'sync-window' adds a deny sync window, 'annotation' sets skip-reconcile")
+	migrateCmd.Flags().BoolVar(&skipFlux, "skip-flux", false, "Skip suspending the Flux Kustomizations and HelmReleases of the migrated namespaces")
+	migrateCmd.Flags().StringSliceVar(&fluxNamespaces, "flux-namespaces", nil, "Namespaces to search for Flux Kustomizations and HelmReleases (default flux-system)")
+	migrateCmd.Flags().BoolVar(&skipKEDA, "skip-keda", false, "Skip pausing the KEDA ScaledObjects of the scaled workloads")
+	migrateCmd.Flags().BoolVar(&skipRollouts, "skip-rollouts", false, "Leave Argo Rollouts out of the workloads scaled down")
+	migrateCmd.Flags().StringVar(&jobPolicy, "jobs", "", "Running Jobs that mount PVCs to migrate: 'wait' (default) for them to finish, or 'delete' them after confirmation")
+	migrateCmd.Flags().DurationVar(&jobTimeout, "job-timeout", 0, "How long to wait for running Jobs that mount PVCs to migrate (default 30m)")
 	migrateCmd.Flags().BoolVar(&planOnly, "plan", false, "Show migration plan and exit without executing")
 	migrateCmd.Flags().StringVar(&scaleMode, "mode", "manual", "Scale-down mode: 'auto' (program scales down) or 'manual' (show commands, wait for user)")
 	migrateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging (includes sensitive IDs)")
+	migrateCmd.Flags().StringVar(&progressFormat, "progress-format", progressFormatTUI, "Progress output: 'tui' (interactive) or 'json' (newline-delimited events, no TUI)")
+	migrateCmd.Flags().StringVar(&progressOutput, "progress-output", "-", "Destination for --progress-format json events: '-' for stdout, or a file/named pipe")
+	migrateCmd.Flags().StringVar(&runbookFile, "runbook", "", "Write a printable runbook with manual fallback commands to this file")
+	migrateCmd.Flags().StringVar(&terraformImports, "terraform-imports", "", "Write Terraform import blocks for the snapshots and volumes created to this file")
+	migrateCmd.Flags().BoolVarP(&autoApprove, "yes", "y", false, "Start the migration without asking for confirmation")
+	migrateCmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "Same as --yes")
+	migrateCmd.Flags().StringVar(&confirmContext, "confirm-context", "", "Name of the protected kube context, instead of typing it when asked")
+	migrateCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatText, "Also write the plan (with --plan) or the result as a versioned document: 'text' or 'json' (to stdout, everything else goes to stderr)")
+	migrateCmd.Flags().BoolVar(&noTUI, "no-tui", false, "Report progress as plain text lines instead of the interactive UI")
+	migrateCmd.Flags().BoolVar(&accessible, "accessible", false, "Screen-reader friendly output: no TUI, colors or spinners, one status sentence per change")
+	migrateCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address during the run (e.g. :9090)")
+	migrateCmd.Flags().StringVar(&metricsPushgateway, "metrics-pushgateway", "", "Push final Prometheus metrics to this Pushgateway URL")
+	migrateCmd.Flags().StringVar(&metricsTextfileDir, "metrics-textfile-dir", "", "Write final Prometheus metrics to pvc_migrator.prom in this node_exporter textfile collector directory")
+	migrateCmd.Flags().StringVar(&pprofAddr, "pprof-addr", "", "Serve Go pprof profiles on this address during the run (e.g. localhost:6060)")
+	migrateCmd.Flags().StringVar(&profileDir, "profile-dir", "", "Write heap and goroutine profiles to this directory when a PVC fails")
+	migrateCmd.Flags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "Export OpenTelemetry traces to this OTLP/HTTP endpoint (e.g. http://localhost:4318)")
+	migrateCmd.Flags().StringVar(&snsTopicARN, "sns-topic-arn", "", "Publish migration lifecycle events to this SNS topic")
+	migrateCmd.Flags().StringVar(&eventBusName, "event-bus", "", "Publish migration lifecycle events to this EventBridge bus")
+	migrateCmd.Flags().StringVar(&journalNamespace, "journal-namespace", "", "Keep a record of the run's volumes, snapshots and operator in a ConfigMap in this namespace")
+	migrateCmd.Flags().StringVar(&backupDir, "backup-dir", "backups", "Save each PVC and PV to <dir>/<migration ID>/ before deleting them ('' to disable)")
+	migrateCmd.Flags().StringVar(&outputManifests, "output-manifests", "", "Write the new PVs and PVCs, and kustomize patches deleting the old ones, to this directory for a GitOps repo instead of applying them")
+	migrateCmd.Flags().BoolVar(&journalBackups, "journal-backups", false, "Also keep the PVC and PV manifests in the journal ConfigMap (needs --journal-namespace)")
+	migrateCmd.Flags().BoolVar(&warmupJobs, "warmup", false, "Create background jobs that read migrated volumes to speed up hydration")
+	migrateCmd.Flags().BoolVar(&includeCoMounted, "include-comounted", false, "Add PVCs that pods mount together with the selected ones to the run")
+	migrateCmd.Flags().BoolVar(&allowEmptyZone, "allow-empty-zone", false, "Migrate PVCs even when no Ready, schedulable node in their target zone could run their pods")
+	migrateCmd.Flags().BoolVar(&checkProvisioning, "check-provisioning", false, "Check whether a Karpenter NodePool or node group can launch nodes in a target zone without any")
+	migrateCmd.Flags().BoolVar(&simulateScheduling, "simulate-scheduling", false, "Dry-run a pod of each workload in its target zone and report whether it could be scheduled")
+	migrateCmd.Flags().BoolVar(&checkPermissions, "check-permissions", false, "Dry-run the EC2 calls of a migration and fail the plan when the credentials are denied any of them")
+	migrateCmd.Flags().BoolVar(&checkQuotas, "check-quotas", false, "Fail the plan when the run would exceed the account's EBS snapshot or storage quota")
+	migrateCmd.Flags().BoolVar(&fastRestore, "fast-snapshot-restore", false, "Enable fast snapshot restore on each snapshot in its target zone while its volume is created, so it is not lazily loaded from S3 (billed per hour)")
+	migrateCmd.Flags().BoolVar(&retryFailed, "retry-failed", false, "Without the TUI, retry once the PVCs that failed before their PVC was changed")
+	migrateCmd.Flags().BoolVar(&labelNamespaces, "label-namespaces", false, "Label namespaces whose PVCs are all migrated and Bound with their zone and completion time")
+	migrateCmd.Flags().StringVar(&affinityPatches, "affinity-patches", "", "Write kustomize patches pinning the Deployments and StatefulSets of migrated PVCs to their zone to this directory")
+	migrateCmd.Flags().BoolVar(&applyAffinity, "apply-affinity", false, "Pin the Deployments and StatefulSets of migrated PVCs to their zone with a nodeSelector before scaling them up")
+	migrateCmd.Flags().StringVar(&helmValues, "helm-values", "", "Write the values changes the Helm releases of migrated PVCs need for their next upgrade to this directory (reads the release secrets)")
+	migrateCmd.Flags().DurationVar(&maxStaleness, "max-snapshot-staleness", 0, "Start from a staged snapshot if the volume was last written at most this long after it (e.g. 10m)")
+	migrateCmd.Flags().BoolVar(&checkWrites, "check-write-activity", false, "Find a volume's last write from CloudWatch VolumeWriteOps for --max-snapshot-staleness")
+	migrateCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	migrateCmd.Flags().StringVar(&csiDriver, "csi-driver", "", "CSI driver of the EBS PVs and of the new ones, for forks of the EBS CSI driver (default ebs.csi.aws.com)")
+	migrateCmd.Flags().StringVar(&tagPrefix, "tag-annotation-prefix", "", "Tag snapshots and volumes with the PVC annotations starting with this prefix (e.g. pv-zone-migrator.io/tag-)")
+	migrateCmd.Flags().IntVar(&stepMaxAttempts, "step-max-attempts", 0, "Attempts of a step that fails with a transient error, such as a 5xx from EC2 or a conflict creating the PV (default 3)")
+	migrateCmd.Flags().DurationVar(&stepRetryBackoff, "step-retry-backoff", 0, "Wait before a step's first retry, doubling after each (default 2s)")
+	migrateCmd.Flags().DurationVar(&stepMaxBackoff, "step-retry-max-backoff", 0, "Cap of the wait between a step's retries (default 30s)")
+	migrateCmd.Flags().DurationVar(&bindTimeout, "bind-timeout", 0, "How long a new PVC may take to be Bound before it fails (default 2m)")
+	migrateCmd.Flags().BoolVar(&verifyMount, "verify-mount", false, "Check each new PVC mounts read-write in a short-lived pod in the target zone")
+	migrateCmd.Flags().BoolVar(&verifyChecksum, "verify-checksum", false, "Hash the files on each volume before its snapshot and on the new volume after, and fail the PVC if they differ")
+	migrateCmd.Flags().DurationVar(&checksumTimeout, "checksum-timeout", 0, "How long the checksum of one volume may take (default 1h)")
+	migrateCmd.Flags().BoolVar(&freezeFilesystem, "freeze", false, "Freeze the filesystem of volumes still mounted by a pod while their snapshot is requested")
+	migrateCmd.Flags().StringVar(&freezeCommand, "freeze-command", "", "Command run in the mounting container to freeze, with the mount path as $1 (default fsfreeze -f \"$1\")")
+	migrateCmd.Flags().StringVar(&thawCommand, "thaw-command", "", "Command run in the mounting container to thaw, with the mount path as $1 (default fsfreeze -u \"$1\")")
+	migrateCmd.Flags().StringVar(&migrationID, "migration-id", "", "Adopt the snapshots and volumes a crashed run with this ID created (default: a new ID)")
+	migrateCmd.Flags().StringVar(&volumeType, "volume-type", "", "Type of the new volumes: gp3, io1 or io2; defaults to the type of the old volume, or gp3 for other types")
+	migrateCmd.Flags().Int32Var(&volumeIOPS, "iops", 0, "Provisioned IOPS of the new volumes; defaults to those of the old volume, checked against each volume's size in the plan")
+	migrateCmd.Flags().Int32Var(&volumeThroughput, "throughput", 0, "Throughput of new gp3 volumes in MiB/s; defaults to that of the old volume")
+	migrateCmd.Flags().StringVar(&kmsKeyID, "kms-key-id", "", "Encrypt new volumes with this KMS key (ID, ARN or alias) instead of the key of their snapshot")
+	migrateCmd.Flags().DurationVar(&stagedSnapshotAge, "staged-snapshot-max-age", 0, "Start from a snapshot made by the snapshot command when it is younger than this (e.g. 24h); writes after it are lost")
 
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(initConfigCmd)
@@ -131,8 +313,37 @@ func loadConfig(cmd *cobra.Command) error {
 			cfg.Namespaces[i] = config.NamespaceConfig{Name: ns}
 		}
 	}
+	if cmd.Flags().Changed("all-namespaces") {
+		cfg.AllNamespaces = allNamespaces
+	}
+	if cmd.Flags().Changed("namespace-selector") {
+		cfg.NamespaceSelector = namespaceSelector
+	}
+	if configFile == "" && !cmd.Flags().Changed("namespace") && cfg.DiscoversNamespaces() {
+		// Without a config file, the default namespace is only included if it matches
+		cfg.Namespaces = nil
+	}
 	if cmd.Flags().Changed("zone") {
 		cfg.TargetZone = targetZone
+		if !cmd.Flags().Changed("zones") {
+			// An explicit single zone replaces a list from the config file
+			cfg.TargetZones = nil
+		}
+	}
+	if cmd.Flags().Changed("zones") {
+		cfg.TargetZones = targetZones
+	}
+	if len(cfg.TargetZones) > 0 {
+		cfg.TargetZone = ""
+	}
+	if cmd.Flags().Changed("from-zone") {
+		cfg.SourceZone = sourceZone
+	}
+	if cmd.Flags().Changed("cordon-source-nodes") {
+		cfg.CordonSourceNodes = cordonNodes
+	}
+	if cmd.Flags().Changed("keep-cordoned") {
+		cfg.KeepCordoned = keepCordoned
 	}
 	if cmd.Flags().Changed("storage-class") {
 		cfg.StorageClass = storageClass
@@ -140,27 +351,254 @@ func loadConfig(cmd *cobra.Command) error {
 	if cmd.Flags().Changed("concurrency") {
 		cfg.MaxConcurrency = maxConcurrency
 	}
+	if cmd.Flags().Changed("scheduling") {
+		cfg.Scheduling = scheduling
+	}
+	if cmd.Flags().Changed("max-per-namespace") {
+		cfg.MaxPerNamespace = maxPerNamespace
+	}
 	if cmd.Flags().Changed("dry-run") {
 		cfg.DryRun = dryRun
 	}
+	if cmd.Flags().Changed("execute") {
+		cfg.Execute = execute
+	}
+	if cmd.Flags().Changed("watch") {
+		cfg.Watch = watchInterval
+	}
+	if cmd.Flags().Changed("batch-size") {
+		cfg.BatchSize = batchSize
+	}
+	if cmd.Flags().Changed("state-file") {
+		cfg.StateFile = stateFile
+	}
 	if cmd.Flags().Changed("skip-argocd") {
 		cfg.SkipArgoCD = skipArgoCD
 	}
+	if cmd.Flags().Changed("skip-flux") {
+		cfg.SkipFlux = skipFlux
+	}
+	if cmd.Flags().Changed("flux-namespaces") {
+		cfg.FluxNamespaces = fluxNamespaces
+	}
+	if cmd.Flags().Changed("skip-keda") {
+		cfg.SkipKEDA = skipKEDA
+	}
+	if cmd.Flags().Changed("skip-rollouts") {
+		cfg.SkipRollouts = skipRollouts
+	}
+	if cmd.Flags().Changed("jobs") {
+		cfg.Jobs = jobPolicy
+	}
+	if cmd.Flags().Changed("job-timeout") {
+		cfg.JobTimeout = jobTimeout
+	}
 	if cmd.Flags().Changed("argocd-namespaces") {
 		cfg.ArgoCDNamespaces = argoCDNamespaces
 	}
+	if cmd.Flags().Changed("argocd-strategy") {
+		cfg.ArgoCDStrategy = argoCDStrategy
+	}
+	if cmd.Flags().Changed("argocd-appsets") {
+		cfg.ArgoCDAppSets = argoCDAppSets
+	}
+	if cmd.Flags().Changed("argocd-server") {
+		cfg.ArgoCDServer = argoCDServer
+	}
+	if cmd.Flags().Changed("argocd-insecure") {
+		cfg.ArgoCDInsecure = argoCDInsecure
+	}
+	if cmd.Flags().Changed("warmup") {
+		cfg.WarmupJobs = warmupJobs
+	}
+	if cmd.Flags().Changed("include-comounted") {
+		cfg.IncludeCoMounted = includeCoMounted
+	}
+	if cmd.Flags().Changed("allow-empty-zone") {
+		cfg.AllowEmptyZone = allowEmptyZone
+	}
+	if cmd.Flags().Changed("check-provisioning") {
+		cfg.CheckProvisioning = checkProvisioning
+	}
+	if cmd.Flags().Changed("simulate-scheduling") {
+		cfg.SimulateScheduling = simulateScheduling
+	}
+	if cmd.Flags().Changed("check-permissions") {
+		cfg.CheckPermissions = checkPermissions
+	}
+	if cmd.Flags().Changed("check-quotas") {
+		cfg.CheckQuotas = checkQuotas
+	}
+	if cmd.Flags().Changed("fast-snapshot-restore") {
+		cfg.FastSnapshotRestore = fastRestore
+	}
+	if cmd.Flags().Changed("label-namespaces") {
+		cfg.LabelNamespaces = labelNamespaces
+	}
+	if cmd.Flags().Changed("affinity-patches") {
+		cfg.AffinityPatches = affinityPatches
+	}
+	if cmd.Flags().Changed("apply-affinity") {
+		cfg.ApplyAffinity = applyAffinity
+	}
+	if cmd.Flags().Changed("helm-values") {
+		cfg.HelmValues = helmValues
+	}
+	if cmd.Flags().Changed("staged-snapshot-max-age") {
+		cfg.StagedSnapshotMaxAge = stagedSnapshotAge
+	}
+	if cmd.Flags().Changed("max-snapshot-staleness") {
+		cfg.MaxSnapshotStaleness = maxStaleness
+	}
+	if cmd.Flags().Changed("check-write-activity") {
+		cfg.CheckWriteActivity = checkWrites
+	}
+	if cmd.Flags().Changed("aws-max-attempts") {
+		cfg.AWSMaxAttempts = awsMaxAttempts
+	}
+	if cmd.Flags().Changed("kms-key-id") {
+		cfg.KMSKeyID = kmsKeyID
+	}
+	if cmd.Flags().Changed("volume-type") {
+		cfg.VolumeType = volumeType
+	}
+	if cmd.Flags().Changed("iops") {
+		cfg.IOPS = volumeIOPS
+	}
+	if cmd.Flags().Changed("throughput") {
+		cfg.Throughput = volumeThroughput
+	}
+	if cmd.Flags().Changed("tag-annotation-prefix") {
+		cfg.TagAnnotationPrefix = tagPrefix
+	}
+	if cmd.Flags().Changed("csi-driver") {
+		cfg.CSIDriver = csiDriver
+	}
+	if cmd.Flags().Changed("migration-id") {
+		cfg.MigrationID = migrationID
+	}
+	if cmd.Flags().Changed("step-max-attempts") {
+		cfg.StepMaxAttempts = stepMaxAttempts
+	}
+	if cmd.Flags().Changed("step-retry-backoff") {
+		cfg.StepRetryBackoff = stepRetryBackoff
+	}
+	if cmd.Flags().Changed("step-retry-max-backoff") {
+		cfg.StepRetryMaxBackoff = stepMaxBackoff
+	}
+	if cmd.Flags().Changed("bind-timeout") {
+		cfg.BindTimeout = bindTimeout
+	}
+	if cmd.Flags().Changed("verify-mount") {
+		cfg.VerifyMount = verifyMount
+	}
+	if cmd.Flags().Changed("verify-checksum") {
+		cfg.VerifyChecksum = verifyChecksum
+	}
+	if cmd.Flags().Changed("checksum-timeout") {
+		cfg.ChecksumTimeout = checksumTimeout
+	}
+	if cmd.Flags().Changed("freeze") {
+		cfg.FreezeFilesystem = freezeFilesystem
+	}
+	if cmd.Flags().Changed("freeze-command") {
+		cfg.FreezeCommand = freezeCommand
+	}
+	if cmd.Flags().Changed("thaw-command") {
+		cfg.ThawCommand = thawCommand
+	}
+	if cmd.Flags().Changed("sns-topic-arn") {
+		cfg.Events.SNSTopicARN = snsTopicARN
+	}
+	if cmd.Flags().Changed("event-bus") {
+		cfg.Events.EventBusName = eventBusName
+	}
+	if cmd.Flags().Changed("journal-namespace") {
+		cfg.JournalNamespace = journalNamespace
+	}
+	if cmd.Flags().Changed("backup-dir") {
+		cfg.BackupDir = backupDir
+	}
+	if cmd.Flags().Changed("output-manifests") {
+		cfg.OutputManifests = outputManifests
+	}
+	if cmd.Flags().Changed("journal-backups") {
+		cfg.JournalBackups = journalBackups
+	}
 
 	// Sync back to global vars for backward compatibility
 	kubeContext = cfg.KubeContext
 	namespaces = cfg.GetNamespaceNames()
+	allNamespaces = cfg.AllNamespaces
+	namespaceSelector = cfg.NamespaceSelector
 	targetZone = cfg.TargetZone
+	targetZones = cfg.TargetZones
+	sourceZone = cfg.SourceZone
+	cordonNodes = cfg.CordonSourceNodes
+	keepCordoned = cfg.KeepCordoned
 	storageClass = cfg.StorageClass
 	maxConcurrency = cfg.MaxConcurrency
+	scheduling = cfg.Scheduling
+	maxPerNamespace = cfg.MaxPerNamespace
 	dryRun = cfg.DryRun
+	execute = cfg.Execute
+	watchInterval = cfg.Watch
+	batchSize = cfg.BatchSize
+	stateFile = cfg.StateFile
 	skipArgoCD = cfg.SkipArgoCD
 	argoCDNamespaces = cfg.ArgoCDNamespaces
+	argoCDStrategy = cfg.ArgoCDStrategy
+	argoCDAppSets = cfg.ArgoCDAppSets
+	argoCDServer = cfg.ArgoCDServer
+	argoCDInsecure = cfg.ArgoCDInsecure
+	skipFlux = cfg.SkipFlux
+	fluxNamespaces = cfg.FluxNamespaces
+	skipKEDA = cfg.SkipKEDA
+	skipRollouts = cfg.SkipRollouts
+	jobPolicy = cfg.Jobs
+	jobTimeout = cfg.JobTimeout
+	warmupJobs = cfg.WarmupJobs
+	labelNamespaces = cfg.LabelNamespaces
+	affinityPatches = cfg.AffinityPatches
+	applyAffinity = cfg.ApplyAffinity
+	helmValues = cfg.HelmValues
+	includeCoMounted = cfg.IncludeCoMounted
+	allowEmptyZone = cfg.AllowEmptyZone
+	checkProvisioning = cfg.CheckProvisioning
+	simulateScheduling = cfg.SimulateScheduling
+	checkQuotas = cfg.CheckQuotas
+	checkPermissions = cfg.CheckPermissions
+	fastRestore = cfg.FastSnapshotRestore
+	snsTopicARN = cfg.Events.SNSTopicARN
+	eventBusName = cfg.Events.EventBusName
+	journalNamespace = cfg.JournalNamespace
+	backupDir = cfg.BackupDir
+	outputManifests = cfg.OutputManifests
+	journalBackups = cfg.JournalBackups
+	stagedSnapshotAge = cfg.StagedSnapshotMaxAge
+	maxStaleness = cfg.MaxSnapshotStaleness
+	checkWrites = cfg.CheckWriteActivity
+	awsMaxAttempts = cfg.AWSMaxAttempts
+	kmsKeyID = cfg.KMSKeyID
+	volumeType = cfg.VolumeType
+	volumeIOPS = cfg.IOPS
+	volumeThroughput = cfg.Throughput
+	tagPrefix = cfg.TagAnnotationPrefix
+	csiDriver = cfg.CSIDriver
+	migrationID = cfg.MigrationID
+	stepMaxAttempts = cfg.StepMaxAttempts
+	stepRetryBackoff = cfg.StepRetryBackoff
+	stepMaxBackoff = cfg.StepRetryMaxBackoff
+	bindTimeout = cfg.BindTimeout
+	verifyMount = cfg.VerifyMount
+	verifyChecksum = cfg.VerifyChecksum
+	checksumTimeout = cfg.ChecksumTimeout
+	freezeFilesystem = cfg.FreezeFilesystem || cfg.FreezeCommand != ""
+	freezeCommand = cfg.FreezeCommand
+	thawCommand = cfg.ThawCommand
 
-	return nil
+	// Reject invalid settings before any command touches the cluster
+	return cfg.Validate()
 }
 
 // Execute runs the root command and handles any errors.
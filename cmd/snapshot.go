@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Pre-stage snapshots of the planned volumes",
+	Long: `Create, and wait for, an EBS snapshot of every planned volume that is not in the
+target zone yet. Nothing in the cluster is changed and workloads keep running, so the
+slow data copy can happen days before the maintenance window. The snapshots are tagged
+so the migration can find them later.`,
+	RunE: runSnapshot,
+}
+
+func init() {
+	snapshotCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	snapshotCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	snapshotCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	snapshotCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
+	snapshotCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	snapshotCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) containing the PVCs (comma-separated, discovers all PVCs)")
+	snapshotCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Snapshot EBS-backed PVCs in every namespace")
+	snapshotCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Snapshot EBS-backed PVCs in namespaces matching this label selector (e.g. team=payments)")
+	snapshotCmd.Flags().StringVarP(&targetZone, "zone", "z", "", "Target AWS Availability Zone, or 'auto' for the least-loaded healthy zone of each namespace")
+	snapshotCmd.Flags().StringSliceVar(&targetZones, "zones", nil, "Availability Zones the PVCs will be spread across (comma-separated)")
+	snapshotCmd.Flags().StringVar(&sourceZone, "from-zone", "", "Only snapshot PVCs whose volumes are in this Availability Zone")
+	snapshotCmd.Flags().IntVar(&maxConcurrency, "concurrency", 0, "Maximum concurrent snapshots")
+	snapshotCmd.Flags().StringVar(&tagPrefix, "tag-annotation-prefix", "", "Tag snapshots with the PVC annotations starting with this prefix (e.g. pv-zone-migrator.io/tag-)")
+	snapshotCmd.Flags().BoolVar(&freezeFilesystem, "freeze", false, "Freeze the filesystem of volumes mounted by a pod while their snapshot is requested")
+	snapshotCmd.Flags().StringVar(&freezeCommand, "freeze-command", "", "Command run in the mounting container to freeze, with the mount path as $1 (default fsfreeze -f \"$1\")")
+	snapshotCmd.Flags().StringVar(&thawCommand, "thaw-command", "", "Command run in the mounting container to thaw, with the mount path as $1 (default fsfreeze -u \"$1\")")
+	snapshotCmd.Flags().StringVar(&terraformImports, "terraform-imports", "", "Write Terraform import blocks for the snapshots created to this file")
+
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshot(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	printHeaderInfo()
+
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+
+	allPVCs, pvcsByNamespace, err := discoverPVCs(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+	if err := resolveZoneIDs(ctx, ec2Client); err != nil {
+		return err
+	}
+	allPVCs, pvcsByNamespace = selectFromZone(ctx, k8sClient, ec2Client, allPVCs, pvcsByNamespace)
+	if len(allPVCs) == 0 {
+		return fmt.Errorf("no PVCs found in any of the specified namespaces")
+	}
+	fmt.Println(buildDiscoveryBox(pvcsByNamespace, len(allPVCs)))
+
+	m, config := createMigrator(k8sClient, ec2Client, allPVCs)
+	config.Windows = nil // Staging snapshots changes nothing, so it runs outside the windows
+
+	fmt.Println(i18n.T("snapshot.starting", len(config.PVCList), config.Destination()))
+	m.AddListener(migrator.NewPlainEventWriter(os.Stdout, len(config.PVCList)))
+
+	// Spreading across or picking zones needs the plan to know where each PVC goes
+	if len(config.TargetZones) > 0 || config.TargetZone == migrator.TargetZoneAuto {
+		if _, err := m.GeneratePlan(ctx); err != nil {
+			return fmt.Errorf("failed to generate plan: %w", err)
+		}
+	}
+
+	// Ctrl+C stops waiting; snapshots already requested keep completing in EC2
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	m.RunSnapshots(runCtx)
+
+	if err := writeTerraformImports(m); err != nil {
+		return err
+	}
+	return printSnapshotSummary(m)
+}
+
+// printSnapshotSummary lists the snapshot of every PVC and fails when any is missing
+func printSnapshotSummary(m *migrator.Migrator) error {
+	statuses := m.GetStatuses()
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	failed := 0
+	for _, name := range names {
+		s := statuses[name]
+		switch s.Step {
+		case migrator.StepDone:
+			fmt.Println(cliSuccessStyle.Render(i18n.T("snapshot.ready", name, s.SnapshotID)))
+		case migrator.StepSkipped:
+			fmt.Println(cliDimStyle.Render(i18n.T("snapshot.skipped", name, s.CurrentZone)))
+		case migrator.StepFailed:
+			failed++
+			fmt.Println(cliWarningStyle.Render(i18n.T("snapshot.failed", name, s.Error)))
+		case migrator.StepPending, migrator.StepGetInfo, migrator.StepSnapshot, migrator.StepWaitSnapshot,
+			migrator.StepCreateVolume, migrator.StepWaitVolume, migrator.StepCleanup, migrator.StepCreatePV, migrator.StepCreatePVC:
+			failed++
+			fmt.Println(cliWarningStyle.Render(i18n.T("snapshot.unfinished", name)))
+		}
+	}
+	fmt.Println()
+	fmt.Println(cliDimStyle.Render(i18n.T("snapshot.tagged", aws.TagStaged)))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d snapshot(s) not ready", failed, len(names))
+	}
+	return nil
+}
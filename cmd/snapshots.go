@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// Snapshot inventory output formats
+const (
+	inventoryFormatTable = "table"
+	inventoryFormatJSON  = "json"
+)
+
+// inventoryFormat is the --output of snapshots list
+var inventoryFormat string
+
+var snapshotsCmd = &cobra.Command{
+	Use:   "snapshots",
+	Short: "Inspect the snapshots created by the tool",
+}
+
+var snapshotsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every snapshot the tool created in the account and region",
+	Long: `List every EBS snapshot of the account in the current region that carries the tool's
+MigratedPVC tag, whether made by migrate or staged by the snapshot command, with its age,
+size and source PVC, and the volume a migration created from it if any. Snapshots no
+migration used are candidates for cleanup.`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshotsList,
+}
+
+func init() {
+	snapshotsListCmd.Flags().StringVarP(&inventoryFormat, "output", "o", inventoryFormatTable, "Output format: 'table' or 'json'")
+	snapshotsCmd.AddCommand(snapshotsListCmd)
+	rootCmd.AddCommand(snapshotsCmd)
+}
+
+// inventoryEntry is a snapshot in the JSON output of snapshots list
+type inventoryEntry struct {
+	SnapshotID       string    `json:"snapshotId"`
+	SourceVolumeID   string    `json:"sourceVolumeId"`
+	PVC              string    `json:"pvc"`
+	SizeGiB          int32     `json:"sizeGiB"`
+	StartTime        time.Time `json:"startTime"`
+	State            string    `json:"state"`
+	Staged           bool      `json:"staged"`
+	Migrated         bool      `json:"migrated"`
+	MigratedVolumeID string    `json:"migratedVolumeId,omitempty"`
+}
+
+func runSnapshotsList(_ *cobra.Command, _ []string) error {
+	if inventoryFormat != inventoryFormatTable && inventoryFormat != inventoryFormatJSON {
+		return fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", inventoryFormat, inventoryFormatTable, inventoryFormatJSON)
+	}
+
+	ctx := context.Background()
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %w", err)
+	}
+	snapshots, err := ec2Client.ListToolSnapshots(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	if inventoryFormat == inventoryFormatJSON {
+		entries := make([]inventoryEntry, 0, len(snapshots))
+		for _, s := range snapshots {
+			entries = append(entries, inventoryEntry{
+				SnapshotID:       s.SnapshotID,
+				SourceVolumeID:   s.VolumeID,
+				PVC:              s.PVC,
+				SizeGiB:          s.SizeGiB,
+				StartTime:        s.StartTime,
+				State:            s.State,
+				Staged:           s.Staged,
+				Migrated:         s.Migrated(),
+				MigratedVolumeID: s.MigratedVolumeID,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println(i18n.T("snapshots.none"))
+		return nil
+	}
+	printInventory(snapshots, time.Now())
+	return nil
+}
+
+// printInventory prints the snapshots as a table, with their age at now
+func printInventory(snapshots []aws.ToolSnapshot, now time.Time) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("snapshots.header"))
+
+	var unused int32
+	for _, s := range snapshots {
+		migration := i18n.T("snapshots.unused")
+		switch {
+		case s.Migrated():
+			migration = i18n.T("snapshots.migrated", s.MigratedVolumeID)
+		case s.Staged:
+			migration = i18n.T("snapshots.staged")
+		}
+		if !s.Migrated() {
+			unused += s.SizeGiB
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%dGiB\t%s\t%s\t%s\t%s\n",
+			s.SnapshotID, formatAge(now.Sub(s.StartTime)), s.SizeGiB, s.PVC, s.VolumeID, s.State, migration)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println(cliDimStyle.Render(i18n.T("snapshots.total", len(snapshots), unused)))
+}
+
+// formatAge renders an age in days, or hours and minutes under a day
+func formatAge(age time.Duration) string {
+	if age >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+	return age.Truncate(time.Minute).String()
+}
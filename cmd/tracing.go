@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// setupTracing starts exporting OpenTelemetry spans when --otlp-endpoint or
+// OTEL_EXPORTER_OTLP_ENDPOINT is set
+func setupTracing(ctx context.Context) (tracing.ShutdownFunc, error) {
+	shutdown, err := tracing.Setup(ctx, otlpEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	if tracing.Enabled(otlpEndpoint) {
+		slog.Info("exporting traces over OTLP", "endpoint", otlpEndpoint)
+	}
+	return shutdown, nil
+}
+
+// finishTracing flushes buffered spans before the process exits
+func finishTracing(ctx context.Context, shutdown tracing.ShutdownFunc) {
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
+		fmt.Printf("%sWarning: %v\n", icon("⚠️ "), err)
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// whatIfFormat is the --output of whatif
+var whatIfFormat string
+
+var whatIfCmd = &cobra.Command{
+	Use:   "whatif",
+	Short: "Compare the performance and cost of the PVCs' volumes with another volume type",
+	Long: `Compare every EBS-backed PVC's volume, as it is, with the volume type, IOPS and throughput
+of --storage-class, read from the cluster, or of --volume-type, --iops and --throughput, which
+override the storage class. Each PVC's IOPS, throughput and monthly cost are shown with their
+change, and volumes the proposal cannot apply to, such as for their size, are reported.
+Nothing is changed and no zone change is planned.
+
+Example:
+  pvc-migrator whatif -n db --storage-class io2 --iops 10000`,
+	Args: cobra.NoArgs,
+	RunE: runWhatIf,
+}
+
+func init() {
+	whatIfCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	whatIfCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	whatIfCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	whatIfCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
+	whatIfCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	whatIfCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) whose PVCs to compare (comma-separated)")
+	whatIfCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Compare EBS-backed PVCs in every namespace")
+	whatIfCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Compare EBS-backed PVCs in namespaces matching this label selector (e.g. team=payments)")
+	whatIfCmd.Flags().StringVarP(&storageClass, "storage-class", "s", "", "Storage class whose type, iops, iopsPerGB and throughput parameters the volumes are compared with")
+	whatIfCmd.Flags().StringVar(&volumeType, "volume-type", "", "Volume type to compare with: gp3, io1 or io2; overrides that of --storage-class")
+	whatIfCmd.Flags().Int32Var(&volumeIOPS, "iops", 0, "Provisioned IOPS to compare with; overrides those of --storage-class")
+	whatIfCmd.Flags().Int32Var(&volumeThroughput, "throughput", 0, "Throughput of gp3 volumes in MiB/s to compare with; overrides that of --storage-class")
+	whatIfCmd.Flags().StringVarP(&whatIfFormat, "output", "o", inventoryFormatTable, "Output format: 'table' or 'json'")
+
+	rootCmd.AddCommand(whatIfCmd)
+}
+
+// whatIfDocument is the JSON output of whatif
+type whatIfDocument struct {
+	PriceRegion string        `json:"priceRegion"` // Region whose list prices the costs are at
+	PVCs        []whatIfEntry `json:"pvcs"`
+}
+
+// whatIfEntry is a PVC in the JSON output of whatif
+type whatIfEntry struct {
+	PVC      string        `json:"pvc"`
+	VolumeID string        `json:"volumeId"`
+	SizeGiB  int32         `json:"sizeGiB"`
+	Current  whatIfVolume  `json:"current"`
+	Proposed *whatIfVolume `json:"proposed,omitempty"` // Omitted when the proposal cannot apply to the volume
+	Error    string        `json:"error,omitempty"`    // Why it cannot
+}
+
+// whatIfVolume is a volume as it is or as proposed in the JSON output of whatif
+type whatIfVolume struct {
+	VolumeType  string   `json:"volumeType"`
+	IOPS        int32    `json:"iops"`
+	Throughput  int32    `json:"throughputMiBps,omitempty"` // Omitted when unknown for the type
+	MonthlyCost *float64 `json:"monthlyCostUSD,omitempty"`  // Omitted when either type has no known price
+}
+
+func runWhatIf(cmd *cobra.Command, _ []string) error {
+	if whatIfFormat != inventoryFormatTable && whatIfFormat != inventoryFormatJSON {
+		return fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", whatIfFormat, inventoryFormatTable, inventoryFormatJSON)
+	}
+
+	ctx := context.Background()
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	proposal, err := whatIfProposal(ctx, k8sClient, cmd.Flags().Changed("storage-class"))
+	if err != nil {
+		return err
+	}
+	if err := addDiscoveredNamespaces(ctx, k8sClient); err != nil {
+		return err
+	}
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+
+	volumes, err := migrator.VolumeZones(ctx, k8sClient, ec2Client, cfg.GetNamespaceNames())
+	if err != nil {
+		return err
+	}
+	whatIfs := migrator.WhatIfs(volumes, proposal)
+
+	if whatIfFormat == inventoryFormatJSON {
+		doc := whatIfDocument{PriceRegion: aws.PriceRegion, PVCs: make([]whatIfEntry, 0, len(whatIfs))}
+		for _, w := range whatIfs {
+			entry := whatIfEntry{
+				PVC:      w.PVC,
+				VolumeID: w.VolumeID,
+				SizeGiB:  w.SizeGiB,
+				Current:  whatIfVolume{VolumeType: w.VolumeType, IOPS: w.Current.IOPS, Throughput: w.Current.Throughput},
+				Error:    w.Error,
+			}
+			if w.Error == "" {
+				entry.Proposed = &whatIfVolume{VolumeType: w.Spec.VolumeType(), IOPS: w.Proposed.IOPS, Throughput: w.Proposed.Throughput}
+			}
+			if w.Priced {
+				entry.Current.MonthlyCost, entry.Proposed.MonthlyCost = &w.CurrentCost, &w.ProposedCost
+			}
+			doc.PVCs = append(doc.PVCs, entry)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	if len(whatIfs) == 0 {
+		fmt.Println(i18n.T("zones.none"))
+		return nil
+	}
+	printWhatIfs(whatIfs)
+	return nil
+}
+
+// whatIfProposal returns what the volumes are compared with: the storage class
+// when fromClass, with the volume type, IOPS and throughput given overriding it
+func whatIfProposal(ctx context.Context, k8sClient *k8s.Client, fromClass bool) (migrator.Proposal, error) {
+	var proposal migrator.Proposal
+	if fromClass {
+		class, err := k8sClient.GetStorageClassVolume(ctx, storageClass)
+		if err != nil {
+			return proposal, err
+		}
+		if driver := k8sClient.CSIDriver(); class.Provisioner != driver {
+			return proposal, fmt.Errorf("storage class %s is provisioned by %s, not %s", storageClass, class.Provisioner, driver)
+		}
+		proposal.Spec = aws.VolumeSpec{Type: class.Type, IOPS: class.IOPS, Throughput: class.Throughput}
+		proposal.IOPSPerGiB = class.IOPSPerGiB
+	} else if volumeType == "" && volumeIOPS == 0 && volumeThroughput == 0 {
+		return proposal, fmt.Errorf("whatif needs --storage-class, or --volume-type, --iops or --throughput")
+	}
+
+	if volumeType != "" {
+		proposal.Spec.Type = volumeType
+	}
+	if volumeIOPS > 0 {
+		proposal.Spec.IOPS, proposal.IOPSPerGiB = volumeIOPS, 0
+	}
+	if volumeThroughput > 0 {
+		proposal.Spec.Throughput = volumeThroughput
+	}
+	return proposal, nil
+}
+
+// printWhatIfs prints each PVC's volume as it is and as proposed as a table,
+// with the change of each, then the total monthly cost of both
+func printWhatIfs(whatIfs []migrator.WhatIf) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("whatif.header"))
+	var priced, unpriced int
+	var current, proposed float64
+	var invalid []migrator.WhatIf
+	for _, v := range whatIfs {
+		if v.Error != "" {
+			invalid = append(invalid, v)
+			_, _ = fmt.Fprintf(w, "%s\t%dGiB\t%s → %s\t-\t-\t%s\n", v.PVC, v.SizeGiB, v.VolumeType, v.Spec.VolumeType(), i18n.T("whatif.not_possible"))
+			continue
+		}
+		cost := "-"
+		if v.Priced {
+			priced++
+			current += v.CurrentCost
+			proposed += v.ProposedCost
+			cost = fmt.Sprintf("$%.2f → $%.2f (%+.2f)", v.CurrentCost, v.ProposedCost, v.ProposedCost-v.CurrentCost)
+		} else {
+			unpriced++
+		}
+		throughput := "-"
+		if v.Current.Throughput > 0 && v.Proposed.Throughput > 0 {
+			throughput = fmt.Sprintf("%d → %d MiB/s (%+d)", v.Current.Throughput, v.Proposed.Throughput, v.Proposed.Throughput-v.Current.Throughput)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%dGiB\t%s → %s\t%d → %d (%+d)\t%s\t%s\n",
+			v.PVC, v.SizeGiB, v.VolumeType, v.Spec.VolumeType(), v.Current.IOPS, v.Proposed.IOPS, v.Proposed.IOPS-v.Current.IOPS, throughput, cost)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	for _, v := range invalid {
+		fmt.Println(cliWarningStyle.Render(i18n.T("whatif.invalid", v.PVC, v.Spec.String(), v.Error)))
+	}
+	fmt.Println(cliDimStyle.Render(i18n.T("whatif.total", priced, current, proposed, proposed-current)))
+	if unpriced > 0 {
+		fmt.Println(cliDimStyle.Render(i18n.T("whatif.unpriced", unpriced)))
+	}
+	fmt.Println(cliDimStyle.Render(i18n.T("whatif.prices", aws.PriceRegion)))
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// zonesFormat is the --output of zones
+var zonesFormat string
+
+var zonesCmd = &cobra.Command{
+	Use:   "zones",
+	Short: "Show which Availability Zone the EBS volumes of the PVCs are in",
+	Long: `Map every EBS-backed PVC of the namespaces to the Availability Zone its volume is
+in, looked up with DescribeVolumes, and sum the PVCs and capacity of each zone. Nothing
+is changed; it helps decide what to migrate and where to.`,
+	Args: cobra.NoArgs,
+	RunE: runZones,
+}
+
+func init() {
+	zonesCmd.Flags().StringVar(&kubeContext, "context", "", "Kubernetes context to use (defaults to current context)")
+	zonesCmd.Flags().StringVar(&asUser, "as", "", "Username to impersonate for the Kubernetes requests, like kubectl --as")
+	zonesCmd.Flags().StringSliceVar(&asGroups, "as-group", nil, "Group to impersonate, can be repeated (requires --as)")
+	zonesCmd.Flags().StringVar(&asUID, "as-uid", "", "UID to impersonate (requires --as)")
+	zonesCmd.Flags().IntVar(&awsMaxAttempts, "aws-max-attempts", 0, "Attempts of each EC2 call that is throttled or fails with a transient error (default 10)")
+	zonesCmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "Kubernetes namespace(s) whose PVCs to map (comma-separated)")
+	zonesCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Map EBS-backed PVCs in every namespace")
+	zonesCmd.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Map EBS-backed PVCs in namespaces matching this label selector (e.g. team=payments)")
+	zonesCmd.Flags().StringVarP(&zonesFormat, "output", "o", inventoryFormatTable, "Output format: 'table' or 'json'")
+
+	rootCmd.AddCommand(zonesCmd)
+}
+
+// zonesDocument is the JSON output of zones
+type zonesDocument struct {
+	PVCs  []zonesPVC  `json:"pvcs"`
+	Zones []zonesZone `json:"zones"`
+}
+
+// zonesPVC is a PVC in the JSON output of zones
+type zonesPVC struct {
+	PVC      string `json:"pvc"`
+	VolumeID string `json:"volumeId"`
+	Zone     string `json:"zone,omitempty"` // Omitted when the volume was not found
+	SizeGiB  int32  `json:"sizeGiB"`
+}
+
+// zonesZone is a zone in the JSON output of zones
+type zonesZone struct {
+	Zone       string   `json:"zone"`
+	ZoneID     string   `json:"zoneId,omitempty"` // Omitted when the zone IDs could not be looked up
+	PVCs       int      `json:"pvcs"`
+	SizeGiB    int64    `json:"sizeGiB"`
+	Namespaces []string `json:"namespaces"`
+}
+
+func runZones(_ *cobra.Command, _ []string) error {
+	if zonesFormat != inventoryFormatTable && zonesFormat != inventoryFormatJSON {
+		return fmt.Errorf("invalid output format '%s': must be either '%s' or '%s'", zonesFormat, inventoryFormatTable, inventoryFormatJSON)
+	}
+
+	ctx := context.Background()
+	k8sClient, err := newK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if err := cfg.CheckContext(k8sClient.ContextName()); err != nil {
+		return err
+	}
+	if err := addDiscoveredNamespaces(ctx, k8sClient); err != nil {
+		return err
+	}
+	ec2Client, err := aws.NewEC2Client(ctx, awsOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AWS EC2 client: %w", err)
+	}
+
+	volumes, err := migrator.VolumeZones(ctx, k8sClient, ec2Client, cfg.GetNamespaceNames())
+	if err != nil {
+		return err
+	}
+	summaries := migrator.SummarizeZones(volumes)
+	zoneIDs, err := ec2Client.ZoneIDs(ctx)
+	if err != nil {
+		slog.Warn("failed to look up the zone IDs, not showing them", "error", err)
+	}
+
+	if zonesFormat == inventoryFormatJSON {
+		doc := zonesDocument{PVCs: make([]zonesPVC, 0, len(volumes)), Zones: make([]zonesZone, 0, len(summaries))}
+		for _, v := range volumes {
+			doc.PVCs = append(doc.PVCs, zonesPVC{PVC: v.PVC, VolumeID: v.VolumeID, Zone: v.Zone, SizeGiB: v.SizeGiB})
+		}
+		for _, s := range summaries {
+			doc.Zones = append(doc.Zones, zonesZone{Zone: s.Zone, ZoneID: zoneIDs[s.Zone], PVCs: s.PVCs, SizeGiB: s.SizeGiB, Namespaces: s.Namespaces})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	if len(volumes) == 0 {
+		fmt.Println(i18n.T("zones.none"))
+		return nil
+	}
+	printZones(volumes, summaries, zoneIDs)
+	return nil
+}
+
+// printZones prints the PVCs with the zone of their volume, then the totals and
+// ID of each zone
+func printZones(volumes []migrator.VolumeZone, summaries []migrator.ZoneSummary, zoneIDs map[string]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("zones.header"))
+	missing := 0
+	for _, v := range volumes {
+		if v.Zone == "" {
+			missing++
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t-\n", v.PVC, v.VolumeID, i18n.T("zones.not_found"))
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%dGiB\n", v.PVC, v.VolumeID, v.Zone, v.SizeGiB)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, i18n.T("zones.summary_header"))
+	var total int64
+	for _, s := range summaries {
+		total += s.SizeGiB
+		id := zoneIDs[s.Zone]
+		if id == "" {
+			id = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%dGiB\t%s\n", s.Zone, id, s.PVCs, s.SizeGiB, strings.Join(s.Namespaces, ", "))
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println(cliDimStyle.Render(i18n.T("zones.total", len(volumes)-missing, total, len(summaries))))
+	if missing > 0 {
+		fmt.Println(cliWarningStyle.Render(i18n.T("zones.missing", missing)))
+	}
+}
@@ -0,0 +1,101 @@
+// Package apiusage counts the calls a run makes to the AWS and Kubernetes APIs,
+// with the ones rejected by rate limits, so the totals can justify rate-limit
+// increases and tune the concurrency of the next run.
+package apiusage
+
+import (
+	"sort"
+	"sync"
+)
+
+// Services the calls are counted under
+const (
+	ServiceKubernetes = "Kubernetes"
+)
+
+// Count is the number of calls made to one operation of a service
+type Count struct {
+	Service   string // e.g. EC2, CloudWatch or Kubernetes
+	Operation string // e.g. DescribeSnapshots or "list persistentvolumeclaims"; empty in totals
+	Calls     int    // Requests sent, retries included
+	Throttled int    // Requests the service rejected for exceeding its rate limit
+}
+
+type key struct {
+	service, operation string
+}
+
+// Counter counts calls by service and operation. It is safe for concurrent use;
+// a nil Counter counts nothing.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[key]*Count
+}
+
+// NewCounter returns a Counter with no calls
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[key]*Count)}
+}
+
+// Add counts a request to an operation of a service
+func (c *Counter) Add(service, operation string, throttled bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := key{service, operation}
+	count, ok := c.counts[k]
+	if !ok {
+		count = &Count{Service: service, Operation: operation}
+		c.counts[k] = count
+	}
+	count.Calls++
+	if throttled {
+		count.Throttled++
+	}
+}
+
+// Counts returns the calls made so far, by service and operation
+func (c *Counter) Counts() []Count {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make([]Count, 0, len(c.counts))
+	for _, count := range c.counts {
+		counts = append(counts, *count)
+	}
+	sortCounts(counts)
+	return counts
+}
+
+// Totals sums counts by service
+func Totals(counts []Count) []Count {
+	byService := make(map[string]*Count)
+	for _, c := range counts {
+		total, ok := byService[c.Service]
+		if !ok {
+			total = &Count{Service: c.Service}
+			byService[c.Service] = total
+		}
+		total.Calls += c.Calls
+		total.Throttled += c.Throttled
+	}
+	totals := make([]Count, 0, len(byService))
+	for _, total := range byService {
+		totals = append(totals, *total)
+	}
+	sortCounts(totals)
+	return totals
+}
+
+func sortCounts(counts []Count) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Service != counts[j].Service {
+			return counts[i].Service < counts[j].Service
+		}
+		return counts[i].Operation < counts[j].Operation
+	})
+}
@@ -0,0 +1,42 @@
+package apiusage
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	c := NewCounter()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			c.Add("EC2", "DescribeSnapshots", i < 3)
+		})
+	}
+	wg.Wait()
+	c.Add(ServiceKubernetes, "get persistentvolumeclaims", false)
+	c.Add("EC2", "CreateSnapshot", false)
+
+	assert.Equal(t, []Count{
+		{Service: "EC2", Operation: "CreateSnapshot", Calls: 1},
+		{Service: "EC2", Operation: "DescribeSnapshots", Calls: 10, Throttled: 3},
+		{Service: ServiceKubernetes, Operation: "get persistentvolumeclaims", Calls: 1},
+	}, c.Counts())
+	assert.Equal(t, []Count{
+		{Service: "EC2", Calls: 11, Throttled: 3},
+		{Service: ServiceKubernetes, Calls: 1},
+	}, Totals(c.Counts()))
+}
+
+func TestCounter_Nil(t *testing.T) {
+	t.Parallel()
+
+	var c *Counter
+	c.Add("EC2", "DescribeVolumes", false)
+	assert.Empty(t, c.Counts())
+	assert.Empty(t, Totals(c.Counts()))
+}
@@ -0,0 +1,248 @@
+// Package argocd turns ArgoCD auto-sync off and on through the argocd-server API
+// with a token, for installations where the user running the migration may not
+// edit Application resources directly.
+package argocd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// TokenEnv is the environment variable holding the API token, as for the argocd CLI
+const TokenEnv = "ARGOCD_AUTH_TOKEN"
+
+// requestTimeout bounds each API call
+const requestTimeout = 30 * time.Second
+
+// Client makes the ArgoCD operations of a run through argocd-server
+type Client struct {
+	server string // Base URL, e.g. https://argocd.example.com
+	token  string
+	client *http.Client
+}
+
+// Ensure Client implements k8s.ArgoCD
+var _ k8s.ArgoCD = (*Client)(nil)
+
+// NewClient creates a client for the argocd-server at server, a host or URL,
+// authenticating with token. insecure skips the verification of its certificate.
+// A nil httpClient uses one with a timeout.
+func NewClient(server, token string, insecure bool, httpClient *http.Client) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("an ArgoCD API token is required; set %s", TokenEnv)
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid ArgoCD server '%s'", server)
+	}
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if insecure {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opted into with --argocd-insecure
+		}
+		httpClient = &http.Client{Transport: transport, Timeout: requestTimeout}
+	}
+	return &Client{server: strings.TrimSuffix(u.String(), "/"), token: token, client: httpClient}, nil
+}
+
+// applicationList is the body of the list applications call
+type applicationList struct {
+	Items []map[string]interface{} `json:"items"`
+}
+
+// FindArgoCDAppsForNamespace finds the applications with auto-sync deploying to
+// the target namespace among those the token may see. The server knows where
+// its applications are, so argoCDNamespaces is not used.
+func (c *Client) FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace string, _ []string) ([]k8s.ArgoCDAppInfo, error) {
+	var list applicationList
+	if err := c.do(ctx, http.MethodGet, "/api/v1/applications", nil, &list); err != nil {
+		return nil, err
+	}
+	var apps []k8s.ArgoCDAppInfo
+	for _, item := range list.Items {
+		if info, ok := k8s.AutoSyncedApp(&unstructured.Unstructured{Object: item}, targetNamespace); ok {
+			apps = append(apps, info)
+		}
+	}
+	return apps, nil
+}
+
+// DisableArgoCDAutoSync turns auto-sync off the way the strategy of each
+// application says, then terminates a sync it has in progress, which could
+// otherwise still scale the workloads back up
+func (c *Client) DisableArgoCDAutoSync(ctx context.Context, apps []k8s.ArgoCDAppInfo) error {
+	for _, app := range apps {
+		slog.Info("argocd: disabling auto-sync", "namespace", app.Namespace, "app", app.Name, "strategy", app.Strategy)
+		var err error
+		switch app.Strategy {
+		case k8s.ArgoCDStrategySyncWindow:
+			err = c.updateSyncWindows(ctx, app, func(windows []interface{}) []interface{} {
+				return k8s.AddDenyWindow(windows, app.Name)
+			})
+		case k8s.ArgoCDStrategyAnnotation:
+			err = c.patchApp(ctx, app, map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": map[string]interface{}{k8s.ArgoCDSkipReconcileAnnotation: "true"}},
+			})
+		default:
+			err = c.patchApp(ctx, app, map[string]interface{}{
+				"spec": map[string]interface{}{"syncPolicy": map[string]interface{}{"automated": nil}},
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to disable auto-sync for ArgoCD app %s/%s: %w", app.Namespace, app.Name, err)
+		}
+		if err := c.terminateSync(ctx, app); err != nil {
+			return fmt.Errorf("failed to terminate the sync of ArgoCD app %s/%s: %w", app.Namespace, app.Name, err)
+		}
+	}
+	return nil
+}
+
+// EnableArgoCDAutoSync undoes DisableArgoCDAutoSync, restoring the original
+// automated policy of each application
+func (c *Client) EnableArgoCDAutoSync(ctx context.Context, apps []k8s.ArgoCDAppInfo) error {
+	for _, app := range apps {
+		slog.Info("argocd: re-enabling auto-sync", "namespace", app.Namespace, "app", app.Name, "strategy", app.Strategy)
+		var err error
+		switch app.Strategy {
+		case k8s.ArgoCDStrategySyncWindow:
+			err = c.updateSyncWindows(ctx, app, func(windows []interface{}) []interface{} {
+				return k8s.RemoveDenyWindow(windows, app.Name)
+			})
+		case k8s.ArgoCDStrategyAnnotation:
+			err = c.patchApp(ctx, app, map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": map[string]interface{}{k8s.ArgoCDSkipReconcileAnnotation: nil}},
+			})
+		default:
+			var automated map[string]interface{}
+			if err := json.Unmarshal(app.AutoSyncPolicy, &automated); err != nil {
+				return fmt.Errorf("failed to unmarshal auto-sync policy for %s: %w", app.Name, err)
+			}
+			if automated == nil {
+				automated = map[string]interface{}{}
+			}
+			err = c.patchApp(ctx, app, map[string]interface{}{
+				"spec": map[string]interface{}{"syncPolicy": map[string]interface{}{"automated": automated}},
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to enable auto-sync for ArgoCD app %s/%s: %w", app.Namespace, app.Name, err)
+		}
+	}
+	return nil
+}
+
+// patchApp applies a JSON merge patch to an application
+func (c *Client) patchApp(ctx context.Context, app k8s.ArgoCDAppInfo, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"name":         app.Name,
+		"appNamespace": app.Namespace,
+		"patch":        string(data),
+		"patchType":    "merge",
+	}
+	return c.do(ctx, http.MethodPatch, appPath(app, ""), body, nil)
+}
+
+// terminateSync terminates the sync operation of an application, if one is running
+func (c *Client) terminateSync(ctx context.Context, app k8s.ArgoCDAppInfo) error {
+	var obj map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, appPath(app, ""), nil, &obj); err != nil {
+		return err
+	}
+	phase, _, _ := unstructured.NestedString(obj, "status", "operationState", "phase")
+	if phase != "Running" {
+		return nil
+	}
+	slog.Info("argocd: terminating running sync", "namespace", app.Namespace, "app", app.Name)
+	return c.do(ctx, http.MethodDelete, appPath(app, "/operation"), nil, nil)
+}
+
+// updateSyncWindows rewrites the sync windows of the application's AppProject
+func (c *Client) updateSyncWindows(ctx context.Context, app k8s.ArgoCDAppInfo, update func([]interface{}) []interface{}) error {
+	path := "/api/v1/projects/" + url.PathEscape(app.ProjectName())
+	var project map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &project); err != nil {
+		return err
+	}
+	windows, _, _ := unstructured.NestedSlice(project, "spec", "syncWindows")
+	windows = update(windows)
+	if len(windows) == 0 {
+		unstructured.RemoveNestedField(project, "spec", "syncWindows")
+	} else if err := unstructured.SetNestedSlice(project, windows, "spec", "syncWindows"); err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, path, map[string]interface{}{"project": project}, nil)
+}
+
+// appPath returns the API path of an application, with suffix appended
+func appPath(app k8s.ArgoCDAppInfo, suffix string) string {
+	return "/api/v1/applications/" + url.PathEscape(app.Name) + suffix + "?appNamespace=" + url.QueryEscape(app.Namespace)
+}
+
+// apiError is the body argocd-server returns with a failed call
+type apiError struct {
+	Message string `json:"message"`
+}
+
+// do calls the API, sending body and decoding the response into out when they
+// are not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("argocd-server request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr apiError
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("argocd-server %s %s: %s (HTTP %d)", method, strings.SplitN(path, "?", 2)[0], apiErr.Message, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode the argocd-server response: %w", err)
+	}
+	return nil
+}
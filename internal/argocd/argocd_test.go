@@ -0,0 +1,188 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// request is a call received by fakeServer
+type request struct {
+	Method string
+	Path   string // With the query
+	Body   map[string]interface{}
+}
+
+// fakeServer is an argocd-server that answers GETs from responses, by path
+// with the query, and records every call
+type fakeServer struct {
+	mu        sync.Mutex
+	requests  []request
+	responses map[string]interface{}
+}
+
+func newFakeServer(t *testing.T, responses map[string]interface{}) (*fakeServer, *Client) {
+	t.Helper()
+
+	f := &fakeServer{responses: responses}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid session","code":16,"message":"invalid session: token is expired"}`))
+			return
+		}
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(data, &body)
+		f.mu.Lock()
+		f.requests = append(f.requests, request{Method: req.Method, Path: req.URL.RequestURI(), Body: body})
+		f.mu.Unlock()
+		if req.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(f.responses[req.URL.RequestURI()])
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL, "secret", false, srv.Client())
+	require.NoError(t, err)
+	return f, c
+}
+
+// application returns an Application with auto-sync deploying to destination
+func application(name, destination, phase string) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"project":     "shop",
+			"destination": map[string]interface{}{"namespace": destination},
+			"syncPolicy":  map[string]interface{}{"automated": map[string]interface{}{"prune": true}},
+		},
+		"status": map[string]interface{}{"operationState": map[string]interface{}{"phase": phase}},
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewClient("argocd.example.com/", "secret", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://argocd.example.com", c.server)
+
+	_, err = NewClient("argocd.example.com", "", false, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), TokenEnv)
+}
+
+func TestClient_FindArgoCDAppsForNamespace(t *testing.T) {
+	t.Parallel()
+
+	_, c := newFakeServer(t, map[string]interface{}{
+		"/api/v1/applications": map[string]interface{}{"items": []interface{}{
+			application("web", "shop", ""),
+			application("billing", "billing", ""),
+		}},
+	})
+
+	apps, err := c.FindArgoCDAppsForNamespace(context.Background(), "shop", nil)
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "web", apps[0].Name)
+	assert.Equal(t, "argocd", apps[0].Namespace)
+	assert.Equal(t, "shop", apps[0].Project)
+	assert.JSONEq(t, `{"prune":true}`, string(apps[0].AutoSyncPolicy))
+}
+
+func TestClient_DisableAndEnableAutoSync(t *testing.T) {
+	t.Parallel()
+
+	f, c := newFakeServer(t, map[string]interface{}{
+		"/api/v1/applications/web?appNamespace=argocd": application("web", "shop", "Running"),
+	})
+	app := k8s.ArgoCDAppInfo{Name: "web", Namespace: "argocd", AutoSyncPolicy: json.RawMessage(`{"prune":true}`)}
+
+	require.NoError(t, c.DisableArgoCDAutoSync(context.Background(), []k8s.ArgoCDAppInfo{app}))
+	require.NoError(t, c.EnableArgoCDAutoSync(context.Background(), []k8s.ArgoCDAppInfo{app}))
+
+	require.Len(t, f.requests, 4)
+	assert.Equal(t, http.MethodPatch, f.requests[0].Method)
+	assert.Equal(t, "/api/v1/applications/web?appNamespace=argocd", f.requests[0].Path)
+	assert.Equal(t, "merge", f.requests[0].Body["patchType"])
+	assert.JSONEq(t, `{"spec":{"syncPolicy":{"automated":null}}}`, f.requests[0].Body["patch"].(string))
+	assert.Equal(t, http.MethodDelete, f.requests[2].Method, "the running sync is terminated")
+	assert.Equal(t, "/api/v1/applications/web/operation?appNamespace=argocd", f.requests[2].Path)
+	assert.JSONEq(t, `{"spec":{"syncPolicy":{"automated":{"prune":true}}}}`, f.requests[3].Body["patch"].(string))
+}
+
+func TestClient_Strategies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		strategy string
+		check    func(t *testing.T, requests []request)
+	}{
+		{
+			name:     "annotation",
+			strategy: k8s.ArgoCDStrategyAnnotation,
+			check: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 3)
+				assert.JSONEq(t, `{"metadata":{"annotations":{"argocd.argoproj.io/skip-reconcile":"true"}}}`, requests[0].Body["patch"].(string))
+				assert.JSONEq(t, `{"metadata":{"annotations":{"argocd.argoproj.io/skip-reconcile":null}}}`, requests[2].Body["patch"].(string))
+			},
+		},
+		{
+			name:     "sync_window",
+			strategy: k8s.ArgoCDStrategySyncWindow,
+			check: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 5)
+				assert.Equal(t, http.MethodPut, requests[1].Method)
+				assert.Equal(t, "/api/v1/projects/shop", requests[1].Path)
+				windows := requests[1].Body["project"].(map[string]interface{})["spec"].(map[string]interface{})["syncWindows"].([]interface{})
+				assert.Len(t, windows, 1)
+				assert.Equal(t, "deny", windows[0].(map[string]interface{})["kind"])
+				assert.Equal(t, http.MethodPut, requests[4].Method)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, c := newFakeServer(t, map[string]interface{}{
+				"/api/v1/applications/web?appNamespace=argocd": application("web", "shop", "Succeeded"),
+				"/api/v1/projects/shop": map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "shop", "namespace": "argocd"},
+					"spec":     map[string]interface{}{},
+				},
+			})
+			app := k8s.ArgoCDAppInfo{Name: "web", Namespace: "argocd", Project: "shop", Strategy: tc.strategy}
+
+			require.NoError(t, c.DisableArgoCDAutoSync(context.Background(), []k8s.ArgoCDAppInfo{app}))
+			require.NoError(t, c.EnableArgoCDAutoSync(context.Background(), []k8s.ArgoCDAppInfo{app}))
+			tc.check(t, f.requests)
+		})
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	t.Parallel()
+
+	_, c := newFakeServer(t, nil)
+	c.token = "expired"
+
+	_, err := c.FindArgoCDAppsForNamespace(context.Background(), "shop", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid session: token is expired (HTTP 401)")
+}
@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// writeMetricsPeriod is the resolution of the EBS write metrics that are checked
+const writeMetricsPeriod = 5 * time.Minute
+
+// cloudWatchAPI is the internal interface for CloudWatch operations
+type cloudWatchAPI interface {
+	GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+// LastWrite estimates when the volume was last written to after since, or
+// returns the zero time when it was not. Without useMetrics nothing tells when
+// a volume was last detached, so it is assumed written up to now. With it, the
+// end of the last CloudWatch period with VolumeWriteOps is used, and a volume
+// attached after since counts as written at its attach time, since mounting
+// the filesystem writes to it.
+func (c *Client) LastWrite(ctx context.Context, volumeID string, since time.Time, useMetrics bool) (_ time.Time, err error) {
+	if !useMetrics {
+		return time.Now(), nil
+	}
+	if c.cw == nil {
+		return time.Time{}, fmt.Errorf("CloudWatch client not configured")
+	}
+
+	ctx, span := tracer.Start(ctx, "cloudwatch.LastWrite")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeVolumes", "volumeId", volumeID, "attachments", true)
+	result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{volumeID}})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to describe volume %s: %w", volumeID, err)
+	}
+	if len(result.Volumes) == 0 {
+		return time.Time{}, fmt.Errorf("volume %s not found", volumeID)
+	}
+
+	var lastWrite time.Time
+	for _, att := range result.Volumes[0].Attachments {
+		if attached := aws.ToTime(att.AttachTime); attached.After(since) && attached.After(lastWrite) {
+			lastWrite = attached
+		}
+	}
+
+	slog.Info("cloudwatch: GetMetricStatistics", "volumeId", volumeID, "metric", "VolumeWriteOps", "since", since)
+	now := time.Now()
+	stats, err := c.cw.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/EBS"),
+		MetricName: aws.String("VolumeWriteOps"),
+		Dimensions: []cwtypes.Dimension{{Name: aws.String("VolumeId"), Value: aws.String(volumeID)}},
+		StartTime:  aws.Time(since.Truncate(writeMetricsPeriod)),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int32(int32(writeMetricsPeriod / time.Second)),
+		Statistics: []cwtypes.Statistic{cwtypes.StatisticSum},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get write metrics of volume %s: %w", volumeID, err)
+	}
+	for _, dp := range stats.Datapoints {
+		if aws.ToFloat64(dp.Sum) == 0 {
+			continue
+		}
+		// A period's timestamp is its start; writes may have happened until its end
+		end := aws.ToTime(dp.Timestamp).Add(writeMetricsPeriod)
+		if end.After(now) {
+			end = now
+		}
+		if end.After(lastWrite) {
+			lastWrite = end
+		}
+	}
+	return lastWrite, nil
+}
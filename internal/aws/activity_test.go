@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCloudWatchAPI implements the cloudWatchAPI interface for testing
+type mockCloudWatchAPI struct {
+	getMetricStatisticsFunc func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+func (m *mockCloudWatchAPI) GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	if m.getMetricStatisticsFunc != nil {
+		return m.getMetricStatisticsFunc(ctx, params, optFns...)
+	}
+	return nil, errors.New("GetMetricStatistics not implemented")
+}
+
+func TestClient_LastWrite(t *testing.T) {
+	t.Parallel()
+
+	since := time.Now().Add(-3 * time.Hour).Truncate(writeMetricsPeriod)
+	datapoint := func(offset time.Duration, sum float64) cwtypes.Datapoint {
+		return cwtypes.Datapoint{Timestamp: aws.Time(since.Add(offset)), Sum: aws.Float64(sum)}
+	}
+
+	tests := []struct {
+		name       string
+		attachTime time.Time
+		datapoints []cwtypes.Datapoint
+		metricsErr error
+		want       time.Time
+		wantErr    bool
+	}{
+		{
+			name: "no writes",
+			datapoints: []cwtypes.Datapoint{
+				datapoint(0, 0),
+				datapoint(5*time.Minute, 0),
+			},
+		},
+		{
+			name: "end of the last period with writes",
+			datapoints: []cwtypes.Datapoint{
+				datapoint(time.Hour, 12),
+				datapoint(10*time.Minute, 40),
+				datapoint(2*time.Hour, 0),
+			},
+			want: since.Add(time.Hour + writeMetricsPeriod),
+		},
+		{
+			name:       "attached after the snapshot",
+			attachTime: since.Add(90 * time.Minute),
+			datapoints: []cwtypes.Datapoint{datapoint(10*time.Minute, 3)},
+			want:       since.Add(90 * time.Minute),
+		},
+		{
+			name:       "attached before the snapshot",
+			attachTime: since.Add(-24 * time.Hour),
+		},
+		{
+			name:       "metrics error",
+			metricsErr: errors.New("access denied"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input *cloudwatch.GetMetricStatisticsInput
+			ec2API := &mockEC2API{
+				describeVolumesFunc: func(_ context.Context, _ *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+					vol := ec2types.Volume{VolumeId: aws.String("vol-123")}
+					if !tt.attachTime.IsZero() {
+						vol.Attachments = []ec2types.VolumeAttachment{{AttachTime: aws.Time(tt.attachTime), State: ec2types.VolumeAttachmentStateAttached}}
+					}
+					return &ec2.DescribeVolumesOutput{Volumes: []ec2types.Volume{vol}}, nil
+				},
+			}
+			cwAPI := &mockCloudWatchAPI{
+				getMetricStatisticsFunc: func(_ context.Context, params *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+					input = params
+					if tt.metricsErr != nil {
+						return nil, tt.metricsErr
+					}
+					return &cloudwatch.GetMetricStatisticsOutput{Datapoints: tt.datapoints}, nil
+				},
+			}
+			client := NewEC2ClientWithCloudWatch(ec2API, cwAPI)
+
+			got, err := client.LastWrite(context.Background(), "vol-123", since, true)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+			assert.Equal(t, "AWS/EBS", aws.ToString(input.Namespace))
+			assert.Equal(t, "VolumeWriteOps", aws.ToString(input.MetricName))
+			assert.Equal(t, "vol-123", aws.ToString(input.Dimensions[0].Value))
+			assert.Equal(t, since, aws.ToTime(input.StartTime))
+		})
+	}
+}
+
+func TestClient_LastWrite_WithoutMetrics(t *testing.T) {
+	t.Parallel()
+
+	client := NewEC2ClientWithInterface(&mockEC2API{})
+	before := time.Now()
+
+	got, err := client.LastWrite(context.Background(), "vol-123", before.Add(-time.Hour), false)
+
+	require.NoError(t, err)
+	assert.False(t, got.Before(before), "the volume is assumed written up to now")
+
+	_, err = client.LastWrite(context.Background(), "vol-123", before.Add(-time.Hour), true)
+	require.Error(t, err, "metrics need a CloudWatch client")
+}
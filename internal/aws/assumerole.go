@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// DefaultSessionName names the assumed-role sessions, so the calls of a run are
+// easy to find in CloudTrail
+const DefaultSessionName = "pvc-migrator"
+
+// AssumeRole is an IAM role the EC2 client assumes through STS instead of using
+// the default credentials directly. The zero value assumes no role.
+type AssumeRole struct {
+	RoleARN     string
+	ExternalID  string // Required by the role's trust policy, if any
+	SessionName string // Defaults to DefaultSessionName
+}
+
+// loadConfig loads the default AWS config with the adaptive retry mode, which
+// also slows the client down while EC2 throttles it, and credentials of the
+// role when one is given. The default credentials are then only used to call STS.
+func loadConfig(ctx context.Context, opts ClientOptions) (aws.Config, error) {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryMode(aws.RetryModeAdaptive), config.WithRetryMaxAttempts(maxAttempts))
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	role := opts.Role
+	if role.RoleARN == "" {
+		return cfg, nil
+	}
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleARN, role.options)
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+	return cfg, nil
+}
+
+// options sets the external ID and session name of the AssumeRole calls
+func (r AssumeRole) options(o *stscreds.AssumeRoleOptions) {
+	o.RoleSessionName = r.SessionName
+	if o.RoleSessionName == "" {
+		o.RoleSessionName = DefaultSessionName
+	}
+	if r.ExternalID != "" {
+		o.ExternalID = aws.String(r.ExternalID)
+	}
+}
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssumeRole_Options(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		role        AssumeRole
+		wantSession string
+		wantExtID   *string
+	}{
+		{
+			name:        "defaults",
+			role:        AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/migrator"},
+			wantSession: DefaultSessionName,
+		},
+		{
+			name:        "external_id_and_session",
+			role:        AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/migrator", ExternalID: "ext-1", SessionName: "ops-run"},
+			wantSession: "ops-run",
+			wantExtID:   aws.String("ext-1"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var o stscreds.AssumeRoleOptions
+			tc.role.options(&o)
+			assert.Equal(t, tc.wantSession, o.RoleSessionName)
+			assert.Equal(t, tc.wantExtID, o.ExternalID)
+		})
+	}
+}
+
+func TestClient_Config_AssumedRole(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	c, err := NewEC2Client(context.Background(), ClientOptions{
+		Role: AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/migrator"},
+	})
+	require.NoError(t, err)
+
+	cfg := c.Config()
+	assert.Equal(t, "eu-west-1", cfg.Region)
+	assert.True(t, aws.IsCredentialsProvider(cfg.Credentials, &stscreds.AssumeRoleProvider{}),
+		"clients built from the config call as the assumed role")
+}
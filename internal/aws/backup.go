@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// backupAPI is the internal interface for AWS Backup operations
+type backupAPI interface {
+	StartBackupJob(ctx context.Context, params *backup.StartBackupJobInput, optFns ...func(*backup.Options)) (*backup.StartBackupJobOutput, error)
+}
+
+// callerAPI is the internal interface for finding the account the client calls as
+type callerAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// BackupVault is the AWS Backup vault the migrated volumes are backed up to, so
+// the vault's retention and lock policies apply to the restore point
+type BackupVault struct {
+	Name            string
+	RoleARN         string // Role AWS Backup assumes to create the recovery point
+	DeleteAfterDays int64  // Retention of the recovery point; 0 keeps the vault's default
+}
+
+// vaultAccount caches the partition and account of the volumes' ARNs
+type vaultAccount struct {
+	once      sync.Once
+	partition string
+	account   string
+	err       error
+}
+
+// NewEC2ClientWithBackup creates a Client with custom EC2, AWS Backup and STS API implementations (for testing)
+func NewEC2ClientWithBackup(api ec2ClientAPI, backupClient backupAPI, caller callerAPI) *Client {
+	return &Client{ec2: api, backup: backupClient, caller: caller, vault: &vaultAccount{}}
+}
+
+// StartVolumeBackup starts an AWS Backup job that backs the volume up to the
+// vault as a recovery point tagged with the PVC, and returns the job's ID.
+// AWS Backup only copies recovery points it created itself between vaults, so
+// the volume is backed up rather than the migration snapshot copied; both hold
+// the same data, as the volume is no longer written to. Calls with the same
+// token start a single job.
+func (c *Client) StartVolumeBackup(ctx context.Context, vault BackupVault, volumeID, pvcName, token string) (_ string, err error) {
+	if c.backup == nil {
+		return "", fmt.Errorf("AWS Backup client not configured")
+	}
+	ctx, span := tracer.Start(ctx, "backup.StartBackupJob")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID), attribute.String("backup.vault", vault.Name))
+	defer func() { tracing.End(span, err) }()
+
+	resourceARN, err := c.volumeARN(ctx, volumeID)
+	if err != nil {
+		return "", err
+	}
+	input := &backup.StartBackupJobInput{
+		BackupVaultName:   aws.String(vault.Name),
+		IamRoleArn:        aws.String(vault.RoleARN),
+		ResourceArn:       aws.String(resourceARN),
+		IdempotencyToken:  aws.String(token),
+		RecoveryPointTags: map[string]string{TagMigratedPVC: SanitizeTag(pvcName)},
+	}
+	if vault.DeleteAfterDays > 0 {
+		input.Lifecycle = &backuptypes.Lifecycle{DeleteAfterDays: aws.Int64(vault.DeleteAfterDays)}
+	}
+
+	slog.Info("backup: StartBackupJob", "volumeId", volumeID, "vault", vault.Name)
+	out, err := c.backup.StartBackupJob(ctx, input)
+	if err != nil {
+		slog.Info("backup: StartBackupJob failed", "volumeId", volumeID, "vault", vault.Name, "error", err)
+		return "", err
+	}
+	return aws.ToString(out.BackupJobId), nil
+}
+
+// volumeARN returns the ARN of the volume, in the account and partition the
+// client calls as, which STS is asked for once
+func (c *Client) volumeARN(ctx context.Context, volumeID string) (string, error) {
+	c.vault.once.Do(func() {
+		out, err := c.caller.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			c.vault.err = fmt.Errorf("failed to find the AWS account: %w", err)
+			return
+		}
+		// arn:<partition>:sts::<account>:assumed-role/...
+		parts := strings.SplitN(aws.ToString(out.Arn), ":", 3)
+		if len(parts) < 3 {
+			c.vault.err = fmt.Errorf("failed to find the AWS partition in %q", aws.ToString(out.Arn))
+			return
+		}
+		c.vault.partition, c.vault.account = parts[1], aws.ToString(out.Account)
+	})
+	if c.vault.err != nil {
+		return "", c.vault.err
+	}
+	return fmt.Sprintf("arn:%s:ec2:%s:%s:volume/%s", c.vault.partition, c.cfg.Region, c.vault.account, volumeID), nil
+}
@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBackupAPI implements the backupAPI interface for testing
+type mockBackupAPI struct {
+	inputs []*backup.StartBackupJobInput
+	err    error
+}
+
+func (m *mockBackupAPI) StartBackupJob(_ context.Context, params *backup.StartBackupJobInput, _ ...func(*backup.Options)) (*backup.StartBackupJobOutput, error) {
+	m.inputs = append(m.inputs, params)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &backup.StartBackupJobOutput{BackupJobId: aws.String("job-1")}, nil
+}
+
+// mockCallerAPI implements the callerAPI interface for testing
+type mockCallerAPI struct {
+	arn   string
+	calls int
+	err   error
+}
+
+func (m *mockCallerAPI) GetCallerIdentity(context.Context, *sts.GetCallerIdentityInput, ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sts.GetCallerIdentityOutput{Arn: aws.String(m.arn), Account: aws.String("123456789012")}, nil
+}
+
+func TestClient_StartVolumeBackup(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		vault      BackupVault
+		callerARN  string
+		wantARN    string
+		wantDelete *int64
+	}{
+		{
+			name:      "vault default retention",
+			vault:     BackupVault{Name: "compliance", RoleARN: "arn:aws:iam::123456789012:role/backup"},
+			callerARN: "arn:aws:sts::123456789012:assumed-role/migrator/pvc-migrator",
+			wantARN:   "arn:aws:ec2::123456789012:volume/vol-1",
+		},
+		{
+			name:       "retention",
+			vault:      BackupVault{Name: "compliance", RoleARN: "arn:aws-us-gov:iam::123456789012:role/backup", DeleteAfterDays: 90},
+			callerARN:  "arn:aws-us-gov:sts::123456789012:assumed-role/migrator/pvc-migrator",
+			wantARN:    "arn:aws-us-gov:ec2::123456789012:volume/vol-1",
+			wantDelete: aws.Int64(90),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockBackupAPI{}
+			caller := &mockCallerAPI{arn: tc.callerARN}
+			client := NewEC2ClientWithBackup(&mockEC2API{}, mock, caller)
+			for range 2 {
+				jobID, err := client.StartVolumeBackup(context.Background(), tc.vault, "vol-1", "db/data", "token")
+				require.NoError(t, err)
+				assert.Equal(t, "job-1", jobID)
+			}
+
+			assert.Equal(t, 1, caller.calls)
+			require.Len(t, mock.inputs, 2)
+			input := mock.inputs[0]
+			assert.Equal(t, tc.vault.Name, aws.ToString(input.BackupVaultName))
+			assert.Equal(t, tc.vault.RoleARN, aws.ToString(input.IamRoleArn))
+			assert.Equal(t, tc.wantARN, aws.ToString(input.ResourceArn))
+			assert.Equal(t, "token", aws.ToString(input.IdempotencyToken))
+			assert.Equal(t, map[string]string{TagMigratedPVC: "db/data"}, input.RecoveryPointTags)
+			if tc.wantDelete == nil {
+				assert.Nil(t, input.Lifecycle)
+			} else {
+				require.NotNil(t, input.Lifecycle)
+				assert.Equal(t, tc.wantDelete, input.Lifecycle.DeleteAfterDays)
+			}
+		})
+	}
+}
+
+func TestClient_StartVolumeBackup_Errors(t *testing.T) {
+	t.Parallel()
+
+	vault := BackupVault{Name: "compliance", RoleARN: "arn:aws:iam::123456789012:role/backup"}
+
+	_, err := NewEC2ClientWithInterface(&mockEC2API{}).StartVolumeBackup(context.Background(), vault, "vol-1", "db/data", "token")
+	require.EqualError(t, err, "AWS Backup client not configured")
+
+	mock := &mockBackupAPI{}
+	client := NewEC2ClientWithBackup(&mockEC2API{}, mock, &mockCallerAPI{err: errors.New("access denied")})
+	_, err = client.StartVolumeBackup(context.Background(), vault, "vol-1", "db/data", "token")
+	require.ErrorContains(t, err, "failed to find the AWS account")
+	assert.Empty(t, mock.inputs)
+
+	mock = &mockBackupAPI{err: errors.New("vault not found")}
+	client = NewEC2ClientWithBackup(&mockEC2API{}, mock, &mockCallerAPI{arn: "arn:aws:sts::123456789012:assumed-role/migrator/x"})
+	_, err = client.StartVolumeBackup(context.Background(), vault, "vol-1", "db/data", "token")
+	require.EqualError(t, err, "vault not found")
+}
@@ -5,15 +5,29 @@ package aws
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
 )
 
+// tracer creates the ec2.* spans that appear under each migration step
+var tracer = otel.Tracer("github.com/cesarempathy/pv-zone-migrator/internal/aws")
+
 // ec2ClientAPI is the internal interface for EC2 SDK operations
 type ec2ClientAPI interface {
 	CreateSnapshot(ctx context.Context, params *ec2.CreateSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error)
@@ -24,17 +38,50 @@ type ec2ClientAPI interface {
 
 // Client wraps the AWS EC2 client
 type Client struct {
-	ec2 ec2ClientAPI
+	ec2      ec2ClientAPI
+	cw       cloudWatchAPI
+	settings ebsSettingsAPI
+	subnets  subnetsAPI
+	zones    zonesAPI
+	fsr      fastRestoreAPI
+	backup   backupAPI
+	caller   callerAPI
+	vault    *vaultAccount
+	usage    *apiusage.Counter
+	cfg      aws.Config // Loaded config, with the assumed role's credentials
+}
+
+// ClientOptions configures the clients created by NewEC2Client
+type ClientOptions struct {
+	Role        AssumeRole // IAM role to assume, if its ARN is set
+	MaxAttempts int        // Attempts of each call; 0 for DefaultMaxAttempts
 }
 
 // NewEC2Client creates a new AWS EC2 client
-func NewEC2Client(ctx context.Context) (*Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+func NewEC2Client(ctx context.Context, opts ClientOptions) (*Client, error) {
+	cfg, err := loadConfig(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return nil, err
 	}
+	usage := apiusage.NewCounter()
+	counted := cfg
+	counted.APIOptions = append(slices.Clip(cfg.APIOptions), countCalls(usage))
+
+	ec2Client := ec2.NewFromConfig(counted, func(o *ec2.Options) {
+		o.APIOptions = append(o.APIOptions, addThrottleObserver)
+	})
+	return &Client{
+		ec2: ec2Client, cw: cloudwatch.NewFromConfig(counted), settings: ec2Client, subnets: ec2Client, zones: ec2Client, fsr: ec2Client,
+		backup: backup.NewFromConfig(counted), caller: sts.NewFromConfig(counted), vault: &vaultAccount{},
+		usage: usage, cfg: cfg,
+	}, nil
+}
 
-	return &Client{ec2: ec2.NewFromConfig(cfg)}, nil
+// Config returns the AWS config the client was created from, with the
+// credentials of the assumed role, so the run's other AWS clients call as the
+// same identity. Its calls are not counted in the client's API usage.
+func (c *Client) Config() aws.Config {
+	return c.cfg
 }
 
 // NewEC2ClientWithInterface creates a Client with a custom EC2 API implementation (for testing)
@@ -42,6 +89,11 @@ func NewEC2ClientWithInterface(api ec2ClientAPI) *Client {
 	return &Client{ec2: api}
 }
 
+// NewEC2ClientWithCloudWatch creates a Client with custom EC2 and CloudWatch API implementations (for testing)
+func NewEC2ClientWithCloudWatch(api ec2ClientAPI, cw cloudWatchAPI) *Client {
+	return &Client{ec2: api, cw: cw}
+}
+
 // SanitizeTag cleans input strings to be safe for AWS Tags.
 // Allowed characters: Alphanumeric, spaces, and _ . : / = + - @
 func SanitizeTag(input string) string {
@@ -50,34 +102,145 @@ func SanitizeTag(input string) string {
 	return re.ReplaceAllString(input, "_")
 }
 
-// CreateSnapshot creates an EBS snapshot
-func (c *Client) CreateSnapshot(ctx context.Context, volumeID, pvcName, targetZone string) (string, error) {
+// Tags that mark a snapshot pre-staged by the snapshot command, so a later
+// migration of the same PVC can adopt it instead of copying the data again
+const (
+	TagStaged = "pvc-migrator/staged"
+	TagPVC    = "pvc-migrator/pvc" // "namespace/name" of the PVC
+)
+
+// TagMigratedPVC names the PVC on every snapshot and volume the tool creates
+const TagMigratedPVC = "MigratedPVC"
+
+// VolumeType is the EBS volume type of the volumes created from snapshots,
+// unless their VolumeSpec sets another
+const VolumeType = string(ec2types.VolumeTypeGp3)
+
+// SnapshotTags returns the tags put on every snapshot taken of the PVC
+func SnapshotTags(pvcName string) map[string]string {
+	return map[string]string{
+		"Name":         fmt.Sprintf("migrate-%s", SanitizeTag(pvcName)),
+		TagMigratedPVC: SanitizeTag(pvcName),
+	}
+}
+
+// StagedSnapshotTags returns the tags put on a snapshot the snapshot command stages
+func StagedSnapshotTags(namespace, pvcName string) map[string]string {
+	tags := SnapshotTags(pvcName)
+	tags[TagStaged] = "true"
+	tags[TagPVC] = SanitizeTag(namespace + "/" + pvcName)
+	return tags
+}
+
+// VolumeTags returns the tags put on the volume created for the PVC
+func VolumeTags(namespace, pvcName string) map[string]string {
+	return map[string]string{
+		"Name":                               fmt.Sprintf("migrated-%s", SanitizeTag(pvcName)),
+		TagMigratedPVC:                       SanitizeTag(pvcName),
+		"kubernetes.io/created-for/pvc/name": SanitizeTag(pvcName),
+		"kubernetes.io/created-for/pvc/namespace": SanitizeTag(namespace),
+	}
+}
+
+// ec2Tags converts tags into EC2 tags, sorted by key
+func ec2Tags(tags map[string]string) []ec2types.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]ec2types.Tag, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, ec2types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return result
+}
+
+// Limits EC2 puts on the tags of a resource
+const (
+	maxTags           = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// withExtraTags adds the tags a PVC asks for to those the tool puts on its
+// snapshot or volume. The tool's own tags win, and tags EC2 would reject are
+// dropped with a warning rather than failing the call.
+func withExtraTags(tags, extra map[string]string) map[string]string {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key, value := SanitizeTag(k), SanitizeTag(extra[k])
+		_, own := tags[key]
+		switch {
+		case own:
+			slog.Warn("custom tag conflicts with a tag of the tool, ignoring it", "key", key)
+		case key == "" || strings.HasPrefix(strings.ToLower(key), "aws:") || len(key) > maxTagKeyLength || len(value) > maxTagValueLength:
+			slog.Warn("custom tag is not valid in EC2, ignoring it", "key", key)
+		case len(tags) >= maxTags:
+			slog.Warn("too many tags, ignoring custom tag", "key", key, "max", maxTags)
+		default:
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// Tags the EBS CSI driver puts on the volumes it provisions, naming their PV
+var pvNameTags = []string{"kubernetes.io/created-for/pv/name", "CSIVolumeName"}
+
+// CreateSnapshot creates an EBS snapshot, with extraTags added to the tool's own.
+// A non-empty clientToken is recorded in TagClientToken, see FindSnapshotByToken.
+func (c *Client) CreateSnapshot(ctx context.Context, volumeID, pvcName, targetZone, clientToken string, extraTags map[string]string) (string, error) {
 	description := fmt.Sprintf("Migrate %s to %s", pvcName, targetZone)
+	tags := SnapshotTags(pvcName)
+	if clientToken != "" {
+		tags[TagClientToken] = clientToken
+	}
+	return c.createSnapshot(ctx, volumeID, pvcName, description, withExtraTags(tags, extraTags))
+}
 
+// CreateStagedSnapshot creates an EBS snapshot ahead of the migration, tagged
+// with TagStaged and TagPVC so the migration can adopt it later
+func (c *Client) CreateStagedSnapshot(ctx context.Context, volumeID, namespace, pvcName, targetZone string, extraTags map[string]string) (string, error) {
+	description := fmt.Sprintf("Pre-staged for migrating %s/%s to %s", namespace, pvcName, targetZone)
+	return c.createSnapshot(ctx, volumeID, pvcName, description, withExtraTags(StagedSnapshotTags(namespace, pvcName), extraTags))
+}
+
+func (c *Client) createSnapshot(ctx context.Context, volumeID, pvcName, description string, tags map[string]string) (string, error) {
 	input := &ec2.CreateSnapshotInput{
 		VolumeId:    aws.String(volumeID),
 		Description: aws.String(description),
 		TagSpecifications: []ec2types.TagSpecification{
 			{
 				ResourceType: ec2types.ResourceTypeSnapshot,
-				Tags: []ec2types.Tag{
-					{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("migrate-%s", SanitizeTag(pvcName)))},
-					{Key: aws.String("MigratedPVC"), Value: aws.String(SanitizeTag(pvcName))},
-				},
+				Tags:         ec2Tags(tags),
 			},
 		},
 	}
 
+	ctx, span := tracer.Start(ctx, "ec2.CreateSnapshot")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer span.End()
+
+	slog.Info("ec2: CreateSnapshot", "volumeId", volumeID, "pvc", pvcName)
 	result, err := c.ec2.CreateSnapshot(ctx, input)
 	if err != nil {
+		slog.Info("ec2: CreateSnapshot failed", "volumeId", volumeID, "error", err)
+		tracing.RecordError(span, err)
 		return "", err
 	}
 
+	span.SetAttributes(attribute.String("ec2.snapshot_id", aws.ToString(result.SnapshotId)))
 	return *result.SnapshotId, nil
 }
 
 // WaitForSnapshot waits for a snapshot to complete
 func (c *Client) WaitForSnapshot(ctx context.Context, snapshotID string) error {
+	slog.Debug("ec2: waiting for snapshot", "snapshotId", snapshotID)
 	waiter := ec2.NewSnapshotCompletedWaiter(c.ec2)
 	return waiter.Wait(ctx, &ec2.DescribeSnapshotsInput{
 		SnapshotIds: []string{snapshotID},
@@ -86,15 +249,24 @@ func (c *Client) WaitForSnapshot(ctx context.Context, snapshotID string) error {
 
 // GetSnapshotProgress returns the progress of a snapshot (0-100)
 func (c *Client) GetSnapshotProgress(ctx context.Context, snapshotID string) (int, string, error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID))
+	defer span.End()
+
+	slog.Info("ec2: DescribeSnapshots", "snapshotId", snapshotID)
 	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
 		SnapshotIds: []string{snapshotID},
 	})
 	if err != nil {
+		slog.Info("ec2: DescribeSnapshots failed", "snapshotId", snapshotID, "error", err)
+		tracing.RecordError(span, err)
 		return 0, "", err
 	}
 
 	if len(result.Snapshots) == 0 {
-		return 0, "", fmt.Errorf("snapshot not found")
+		err := fmt.Errorf("snapshot not found")
+		tracing.RecordError(span, err)
+		return 0, "", err
 	}
 
 	snapshot := result.Snapshots[0]
@@ -103,39 +275,186 @@ func (c *Client) GetSnapshotProgress(ctx context.Context, snapshotID string) (in
 		_, _ = fmt.Sscanf(*snapshot.Progress, "%d%%", &progress)
 	}
 
+	span.SetAttributes(
+		attribute.Int("ec2.snapshot_progress", progress),
+		attribute.String("ec2.snapshot_state", string(snapshot.State)),
+	)
 	return progress, string(snapshot.State), nil
 }
 
-// CreateVolume creates a new EBS volume from a snapshot
-func (c *Client) CreateVolume(ctx context.Context, snapshotID, targetZone, pvcName, namespace string, sizeGiB int32) (string, error) {
+// ValidateSnapshot checks that a snapshot EC2 reports as finished is usable as
+// the only copy of a volume: completed at 100%, taken of volumeID and at least
+// sizeGiB large
+func (c *Client) ValidateSnapshot(ctx context.Context, snapshotID, volumeID string, sizeGiB int32) (err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.String("ec2.volume_id", volumeID))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeSnapshots", "snapshotId", snapshotID, "validate", true)
+	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []string{snapshotID},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeSnapshots failed", "snapshotId", snapshotID, "error", err)
+		return err
+	}
+	if len(result.Snapshots) == 0 {
+		return fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+
+	snapshot := result.Snapshots[0]
+	if snapshot.State != ec2types.SnapshotStateCompleted {
+		return fmt.Errorf("snapshot %s is %s, not completed", snapshotID, snapshot.State)
+	}
+	if progress := aws.ToString(snapshot.Progress); progress != "100%" {
+		return fmt.Errorf("snapshot %s is completed at %s", snapshotID, progress)
+	}
+	if source := aws.ToString(snapshot.VolumeId); source != volumeID {
+		return fmt.Errorf("snapshot %s was taken of volume %s, not %s", snapshotID, source, volumeID)
+	}
+	if size := aws.ToInt32(snapshot.VolumeSize); size < sizeGiB {
+		return fmt.Errorf("snapshot %s is %dGiB, smaller than the %dGiB claim", snapshotID, size, sizeGiB)
+	}
+	return nil
+}
+
+// SnapshotInfo describes a completed snapshot
+type SnapshotInfo struct {
+	SnapshotID string
+	StartTime  time.Time
+}
+
+// FindStagedSnapshot returns the most recent completed snapshot of volumeID that
+// the snapshot command staged for the PVC, or nil when there is none
+func (c *Client) FindStagedSnapshot(ctx context.Context, volumeID, namespace, pvcName string) (*SnapshotInfo, error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer span.End()
+
+	slog.Info("ec2: DescribeSnapshots", "volumeId", volumeID, "staged", true)
+	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+		Filters: []ec2types.Filter{
+			{Name: aws.String("volume-id"), Values: []string{volumeID}},
+			{Name: aws.String("status"), Values: []string{string(ec2types.SnapshotStateCompleted)}},
+			{Name: aws.String("tag:" + TagStaged), Values: []string{"true"}},
+			{Name: aws.String("tag:" + TagPVC), Values: []string{SanitizeTag(namespace + "/" + pvcName)}},
+		},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeSnapshots failed", "volumeId", volumeID, "error", err)
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var latest *SnapshotInfo
+	for _, snap := range result.Snapshots {
+		started := aws.ToTime(snap.StartTime)
+		if latest == nil || started.After(latest.StartTime) {
+			latest = &SnapshotInfo{SnapshotID: aws.ToString(snap.SnapshotId), StartTime: started}
+		}
+	}
+	return latest, nil
+}
+
+// FindVolumeByPV returns the ID of the EBS volume provisioned for a PV, found
+// by the tags the EBS CSI driver sets. It is used when the PV was deleted but
+// its volume was retained.
+func (c *Client) FindVolumeByPV(ctx context.Context, pvName string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeVolumes")
+	span.SetAttributes(attribute.String("k8s.pv", pvName))
+	defer func() { tracing.End(span, err) }()
+
+	for _, tag := range pvNameTags {
+		slog.Info("ec2: DescribeVolumes", "tag", tag, "pv", pvName)
+		result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+			Filters: []ec2types.Filter{{Name: aws.String("tag:" + tag), Values: []string{pvName}}},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to find volume of PV %s: %w", pvName, err)
+		}
+		switch len(result.Volumes) {
+		case 0:
+			continue
+		case 1:
+			return aws.ToString(result.Volumes[0].VolumeId), nil
+		default:
+			return "", fmt.Errorf("%d volumes are tagged %s=%s", len(result.Volumes), tag, pvName)
+		}
+	}
+	return "", fmt.Errorf("no volume is tagged with the name of PV %s", pvName)
+}
+
+// CreateVolume creates a new EBS volume from a snapshot. It is encrypted with
+// kmsKeyID when set; otherwise it keeps the encryption of the snapshot, or gets
+// the account default. extraTags are added to the tool's own tags. With a
+// clientToken, EC2 returns the volume an earlier call with the same token
+// created, and adopted reports that it was not created by this call.
+func (c *Client) CreateVolume(ctx context.Context, snapshotID, targetZone, pvcName, namespace, kmsKeyID, clientToken string, sizeGiB int32, spec VolumeSpec, extraTags map[string]string) (volumeID string, adopted bool, err error) {
 	input := &ec2.CreateVolumeInput{
 		AvailabilityZone: aws.String(targetZone),
 		SnapshotId:       aws.String(snapshotID),
-		VolumeType:       ec2types.VolumeTypeGp3,
+		VolumeType:       ec2types.VolumeType(spec.VolumeType()),
 		Size:             aws.Int32(sizeGiB),
 		TagSpecifications: []ec2types.TagSpecification{
 			{
 				ResourceType: ec2types.ResourceTypeVolume,
-				Tags: []ec2types.Tag{
-					{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("migrated-%s", SanitizeTag(pvcName)))},
-					{Key: aws.String("MigratedPVC"), Value: aws.String(SanitizeTag(pvcName))},
-					{Key: aws.String("kubernetes.io/created-for/pvc/name"), Value: aws.String(SanitizeTag(pvcName))},
-					{Key: aws.String("kubernetes.io/created-for/pvc/namespace"), Value: aws.String(SanitizeTag(namespace))},
-				},
+				Tags:         ec2Tags(withExtraTags(VolumeTags(namespace, pvcName), extraTags)),
 			},
 		},
 	}
+	if kmsKeyID != "" {
+		input.Encrypted = aws.Bool(true)
+		input.KmsKeyId = aws.String(kmsKeyID)
+	}
+	if spec.IOPS > 0 {
+		input.Iops = aws.Int32(spec.IOPS)
+	}
+	if spec.Throughput > 0 {
+		input.Throughput = aws.Int32(spec.Throughput)
+	}
+	if clientToken != "" {
+		input.ClientToken = aws.String(clientToken)
+	}
+
+	ctx, span := tracer.Start(ctx, "ec2.CreateVolume")
+	span.SetAttributes(
+		attribute.String("ec2.snapshot_id", snapshotID),
+		attribute.String("ec2.availability_zone", targetZone),
+		attribute.Int("ec2.size_gib", int(sizeGiB)),
+		attribute.String("ec2.volume_type", spec.VolumeType()),
+	)
+	defer span.End()
 
+	slog.Info("ec2: CreateVolume", "snapshotId", snapshotID, "zone", targetZone, "sizeGiB", sizeGiB, "volumeType", spec.VolumeType(), "iops", spec.IOPS, "throughput", spec.Throughput)
 	result, err := c.ec2.CreateVolume(ctx, input)
 	if err != nil {
-		return "", err
+		slog.Info("ec2: CreateVolume failed", "snapshotId", snapshotID, "error", err)
+		tracing.RecordError(span, err)
+		return "", false, err
 	}
 
-	return *result.VolumeId, nil
+	// A client token used before returns the volume created for it, which a
+	// new volume is never in yet
+	volumeID = aws.ToString(result.VolumeId)
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	switch result.State {
+	case ec2types.VolumeStateCreating, "":
+		return volumeID, false, nil
+	case ec2types.VolumeStateAvailable:
+		span.SetAttributes(attribute.Bool("ec2.adopted", true))
+		return volumeID, true, nil
+	default:
+		err = fmt.Errorf("client token %s returned volume %s, which is %s; use another migration ID to create a new one",
+			clientToken, volumeID, result.State)
+		tracing.RecordError(span, err)
+		return "", false, err
+	}
 }
 
 // WaitForVolume waits for a volume to be available
 func (c *Client) WaitForVolume(ctx context.Context, volumeID string) error {
+	slog.Debug("ec2: waiting for volume", "volumeId", volumeID)
 	waiter := ec2.NewVolumeAvailableWaiter(c.ec2)
 	return waiter.Wait(ctx, &ec2.DescribeVolumesInput{
 		VolumeIds: []string{volumeID},
@@ -144,17 +463,27 @@ func (c *Client) WaitForVolume(ctx context.Context, volumeID string) error {
 
 // GetVolumeState returns the state of a volume
 func (c *Client) GetVolumeState(ctx context.Context, volumeID string) (string, error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeVolumes")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer span.End()
+
+	slog.Info("ec2: DescribeVolumes", "volumeId", volumeID)
 	result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
 		VolumeIds: []string{volumeID},
 	})
 	if err != nil {
+		slog.Info("ec2: DescribeVolumes failed", "volumeId", volumeID, "error", err)
+		tracing.RecordError(span, err)
 		return "", err
 	}
 
 	if len(result.Volumes) == 0 {
-		return "", fmt.Errorf("volume not found")
+		err := fmt.Errorf("volume not found")
+		tracing.RecordError(span, err)
+		return "", err
 	}
 
+	span.SetAttributes(attribute.String("ec2.volume_state", string(result.Volumes[0].State)))
 	return string(result.Volumes[0].State), nil
 }
 
@@ -163,25 +492,82 @@ type VolumeInfo struct {
 	VolumeID         string
 	AvailabilityZone string
 	State            string
+	SizeGiB          int32
+	VolumeType       string // gp3, io2...
+	IOPS             int32  // Provisioned or baseline IOPS; 0 for types without
+	Throughput       int32  // MiB/s of gp3 volumes
+	Encrypted        bool
+	KMSKeyID         string // Key the volume is encrypted with, if it is
 }
 
 // GetVolumeInfo returns detailed information about a volume including its availability zone
 func (c *Client) GetVolumeInfo(ctx context.Context, volumeID string) (*VolumeInfo, error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeVolumes")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer span.End()
+
+	slog.Info("ec2: DescribeVolumes", "volumeId", volumeID)
 	result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
 		VolumeIds: []string{volumeID},
 	})
 	if err != nil {
+		slog.Info("ec2: DescribeVolumes failed", "volumeId", volumeID, "error", err)
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
 	if len(result.Volumes) == 0 {
-		return nil, fmt.Errorf("volume not found: %s", volumeID)
+		err := fmt.Errorf("volume not found: %s", volumeID)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
 
-	vol := result.Volumes[0]
+	return volumeInfo(result.Volumes[0]), nil
+}
+
+// maxFilterValues is the most values DescribeVolumes accepts in one filter
+const maxFilterValues = 200
+
+// GetVolumesInfo returns the volumes among volumeIDs that exist, by ID, looked up
+// in batches of up to 200 IDs. Unlike GetVolumeInfo, an ID that does not exist is
+// left out of the result instead of failing the call.
+func (c *Client) GetVolumesInfo(ctx context.Context, volumeIDs []string) (_ map[string]*VolumeInfo, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeVolumes")
+	span.SetAttributes(attribute.Int("ec2.volume_count", len(volumeIDs)))
+	defer func() { tracing.End(span, err) }()
+
+	volumes := make(map[string]*VolumeInfo, len(volumeIDs))
+	for start := 0; start < len(volumeIDs); start += maxFilterValues {
+		batch := volumeIDs[start:min(start+maxFilterValues, len(volumeIDs))]
+		slog.Info("ec2: DescribeVolumes", "volumes", len(batch))
+		// A volume-id filter, unlike VolumeIds, does not fail on missing volumes
+		pages := ec2.NewDescribeVolumesPaginator(c.ec2, &ec2.DescribeVolumesInput{
+			Filters: []ec2types.Filter{{Name: aws.String("volume-id"), Values: batch}},
+		})
+		for pages.HasMorePages() {
+			page, err := pages.NextPage(ctx)
+			if err != nil {
+				slog.Info("ec2: DescribeVolumes failed", "volumes", len(batch), "error", err)
+				return nil, err
+			}
+			for _, vol := range page.Volumes {
+				volumes[aws.ToString(vol.VolumeId)] = volumeInfo(vol)
+			}
+		}
+	}
+	return volumes, nil
+}
+
+func volumeInfo(vol ec2types.Volume) *VolumeInfo {
 	return &VolumeInfo{
 		VolumeID:         aws.ToString(vol.VolumeId),
 		AvailabilityZone: aws.ToString(vol.AvailabilityZone),
 		State:            string(vol.State),
-	}, nil
+		SizeGiB:          aws.ToInt32(vol.Size),
+		VolumeType:       string(vol.VolumeType),
+		IOPS:             aws.ToInt32(vol.Iops),
+		Throughput:       aws.ToInt32(vol.Throughput),
+		Encrypted:        aws.ToBool(vol.Encrypted),
+		KMSKeyID:         aws.ToString(vol.KmsKeyId),
+	}
 }
@@ -3,7 +3,10 @@ package aws
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -52,24 +55,27 @@ func TestClient_CreateSnapshot(t *testing.T) {
 	t.Parallel()
 
 	cases := []struct {
-		name       string
-		volumeID   string
-		pvcName    string
-		targetZone string
-		mockSetup  func(m *mockEC2API)
-		wantID     string
-		wantErr    bool
+		name        string
+		volumeID    string
+		pvcName     string
+		targetZone  string
+		clientToken string
+		mockSetup   func(m *mockEC2API)
+		wantID      string
+		wantErr     bool
 	}{
 		{
-			name:       "success",
-			volumeID:   "vol-123",
-			pvcName:    "test-pvc",
-			targetZone: "us-west-2a",
+			name:        "success",
+			volumeID:    "vol-123",
+			pvcName:     "test-pvc",
+			targetZone:  "us-west-2a",
+			clientToken: "token-1",
 			mockSetup: func(m *mockEC2API) {
 				m.createSnapshotFunc = func(_ context.Context, params *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
 					// Verify inputs
 					assert.Equal(t, "vol-123", *params.VolumeId)
 					assert.Contains(t, *params.Description, "test-pvc")
+					assert.Contains(t, params.TagSpecifications[0].Tags, ec2types.Tag{Key: aws.String(TagClientToken), Value: aws.String("token-1")})
 					return &ec2.CreateSnapshotOutput{
 						SnapshotId: aws.String("snap-abc123"),
 					}, nil
@@ -102,7 +108,7 @@ func TestClient_CreateSnapshot(t *testing.T) {
 			client := NewEC2ClientWithInterface(mock)
 			ctx := context.Background()
 
-			snapshotID, err := client.CreateSnapshot(ctx, tc.volumeID, tc.pvcName, tc.targetZone)
+			snapshotID, err := client.CreateSnapshot(ctx, tc.volumeID, tc.pvcName, tc.targetZone, tc.clientToken, nil)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -115,6 +121,195 @@ func TestClient_CreateSnapshot(t *testing.T) {
 	}
 }
 
+func TestClient_CreateStagedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var input *ec2.CreateSnapshotInput
+	mock := &mockEC2API{
+		createSnapshotFunc: func(_ context.Context, params *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+			input = params
+			return &ec2.CreateSnapshotOutput{SnapshotId: aws.String("snap-staged")}, nil
+		},
+	}
+	client := NewEC2ClientWithInterface(mock)
+
+	snapshotID, err := client.CreateStagedSnapshot(context.Background(), "vol-123", "db", "data-0", "us-west-2a", map[string]string{"costcenter": "42", TagStaged: "false"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "snap-staged", snapshotID)
+	assert.Equal(t, "Pre-staged for migrating db/data-0 to us-west-2a", aws.ToString(input.Description))
+	tags := make(map[string]string)
+	for _, tag := range input.TagSpecifications[0].Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	assert.Equal(t, map[string]string{
+		"Name":        "migrate-data-0",
+		"MigratedPVC": "data-0",
+		TagStaged:     "true",
+		TagPVC:        "db/data-0",
+		"costcenter":  "42",
+	}, tags, "custom tags cannot override the tool's")
+}
+
+func TestWithExtraTags(t *testing.T) {
+	t.Parallel()
+
+	extra := map[string]string{
+		"team":         "payments's",
+		"aws:reserved": "x",
+		"Name":         "mine",
+		"long":         strings.Repeat("v", maxTagValueLength+1),
+	}
+	tags := withExtraTags(map[string]string{"Name": "migrate-data"}, extra)
+	assert.Equal(t, map[string]string{"Name": "migrate-data", "team": "payments_s"}, tags)
+
+	many := make(map[string]string)
+	for i := range maxTags + 5 {
+		many[fmt.Sprintf("tag-%02d", i)] = "x"
+	}
+	assert.Len(t, withExtraTags(map[string]string{"Name": "migrate-data"}, many), maxTags)
+	assert.Empty(t, withExtraTags(map[string]string{}, nil))
+}
+
+func TestClient_FindVolumeByPV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		volumes map[string][]ec2types.Volume // By tag key
+		err     error
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "created-for tag",
+			volumes: map[string][]ec2types.Volume{"kubernetes.io/created-for/pv/name": {{VolumeId: aws.String("vol-1")}}},
+			want:    "vol-1",
+		},
+		{
+			name:    "CSIVolumeName tag",
+			volumes: map[string][]ec2types.Volume{"CSIVolumeName": {{VolumeId: aws.String("vol-2")}}},
+			want:    "vol-2",
+		},
+		{
+			name:    "not found",
+			wantErr: "no volume is tagged with the name of PV pv-123",
+		},
+		{
+			name: "ambiguous",
+			volumes: map[string][]ec2types.Volume{"kubernetes.io/created-for/pv/name": {
+				{VolumeId: aws.String("vol-1")}, {VolumeId: aws.String("vol-2")},
+			}},
+			wantErr: "2 volumes are tagged",
+		},
+		{
+			name:    "api error",
+			err:     errors.New("throttled"),
+			wantErr: "throttled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockEC2API{
+				describeVolumesFunc: func(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					require.Len(t, params.Filters, 1)
+					assert.Equal(t, []string{"pv-123"}, params.Filters[0].Values)
+					tag := strings.TrimPrefix(aws.ToString(params.Filters[0].Name), "tag:")
+					return &ec2.DescribeVolumesOutput{Volumes: tt.volumes[tag]}, nil
+				},
+			}
+			client := NewEC2ClientWithInterface(mock)
+
+			got, err := client.FindVolumeByPV(context.Background(), "pv-123")
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_FindStagedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(6 * time.Hour)
+
+	tests := []struct {
+		name      string
+		snapshots []ec2types.Snapshot
+		err       error
+		want      *SnapshotInfo
+		wantErr   bool
+	}{
+		{
+			name: "picks the most recent",
+			snapshots: []ec2types.Snapshot{
+				{SnapshotId: aws.String("snap-old"), StartTime: aws.Time(older)},
+				{SnapshotId: aws.String("snap-new"), StartTime: aws.Time(newer)},
+			},
+			want: &SnapshotInfo{SnapshotID: "snap-new", StartTime: newer},
+		},
+		{
+			name: "none staged",
+		},
+		{
+			name:    "api error",
+			err:     errors.New("throttled"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input *ec2.DescribeSnapshotsInput
+			mock := &mockEC2API{
+				describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+					input = params
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &ec2.DescribeSnapshotsOutput{Snapshots: tt.snapshots}, nil
+				},
+			}
+			client := NewEC2ClientWithInterface(mock)
+
+			got, err := client.FindStagedSnapshot(context.Background(), "vol-123", "db", "data-0")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			filters := make(map[string][]string)
+			for _, f := range input.Filters {
+				filters[aws.ToString(f.Name)] = f.Values
+			}
+			assert.Equal(t, map[string][]string{
+				"volume-id":        {"vol-123"},
+				"status":           {"completed"},
+				"tag:" + TagStaged: {"true"},
+				"tag:" + TagPVC:    {"db/data-0"},
+			}, filters)
+			assert.Equal(t, []string{"self"}, input.OwnerIds)
+		})
+	}
+}
+
 func TestClient_GetSnapshotProgress(t *testing.T) {
 	t.Parallel()
 
@@ -237,19 +432,71 @@ func TestClient_GetSnapshotProgress(t *testing.T) {
 	}
 }
 
+func TestClient_ValidateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	good := ec2types.Snapshot{
+		SnapshotId: aws.String("snap-1"),
+		VolumeId:   aws.String("vol-1"),
+		VolumeSize: aws.Int32(20),
+		Progress:   aws.String("100%"),
+		State:      ec2types.SnapshotStateCompleted,
+	}
+
+	cases := []struct {
+		name    string
+		modify  func(s *ec2types.Snapshot)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*ec2types.Snapshot) {}},
+		{name: "larger_than_claim", modify: func(s *ec2types.Snapshot) { s.VolumeSize = aws.Int32(30) }},
+		{name: "not_completed", modify: func(s *ec2types.Snapshot) { s.State = ec2types.SnapshotStateError }, wantErr: "is error, not completed"},
+		{name: "incomplete_progress", modify: func(s *ec2types.Snapshot) { s.Progress = aws.String("99%") }, wantErr: "completed at 99%"},
+		{name: "other_volume", modify: func(s *ec2types.Snapshot) { s.VolumeId = aws.String("vol-2") }, wantErr: "taken of volume vol-2"},
+		{name: "too_small", modify: func(s *ec2types.Snapshot) { s.VolumeSize = aws.Int32(10) }, wantErr: "10GiB, smaller than the 20GiB claim"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			snapshot := good
+			tc.modify(&snapshot)
+			mock := &mockEC2API{
+				describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+					assert.Equal(t, []string{"snap-1"}, params.SnapshotIds)
+					return &ec2.DescribeSnapshotsOutput{Snapshots: []ec2types.Snapshot{snapshot}}, nil
+				},
+			}
+
+			err := NewEC2ClientWithInterface(mock).ValidateSnapshot(context.Background(), "snap-1", "vol-1", 20)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestClient_CreateVolume(t *testing.T) {
 	t.Parallel()
 
 	cases := []struct {
-		name       string
-		snapshotID string
-		targetZone string
-		pvcName    string
-		namespace  string
-		sizeGiB    int32
-		mockSetup  func(m *mockEC2API)
-		wantID     string
-		wantErr    bool
+		name        string
+		snapshotID  string
+		targetZone  string
+		pvcName     string
+		namespace   string
+		kmsKeyID    string
+		clientToken string
+		sizeGiB     int32
+		spec        VolumeSpec
+		mockSetup   func(m *mockEC2API)
+		wantID      string
+		wantAdopted bool
+		wantErr     bool
 	}{
 		{
 			name:       "success",
@@ -263,14 +510,93 @@ func TestClient_CreateVolume(t *testing.T) {
 					assert.Equal(t, "snap-123", *params.SnapshotId)
 					assert.Equal(t, "us-west-2a", *params.AvailabilityZone)
 					assert.Equal(t, int32(100), *params.Size)
+					assert.Equal(t, ec2types.VolumeTypeGp3, params.VolumeType)
+					assert.Nil(t, params.Iops, "baseline performance of gp3")
+					assert.Nil(t, params.Encrypted, "keeps the encryption of the snapshot")
+					assert.Nil(t, params.KmsKeyId)
+					assert.Nil(t, params.ClientToken)
 					return &ec2.CreateVolumeOutput{
 						VolumeId: aws.String("vol-newvol"),
+						State:    ec2types.VolumeStateCreating,
 					}, nil
 				}
 			},
 			wantID:  "vol-newvol",
 			wantErr: false,
 		},
+		{
+			name:       "kms_key",
+			snapshotID: "snap-123",
+			targetZone: "us-west-2a",
+			pvcName:    "my-pvc",
+			namespace:  "default",
+			kmsKeyID:   "alias/migration",
+			sizeGiB:    100,
+			mockSetup: func(m *mockEC2API) {
+				m.createVolumeFunc = func(_ context.Context, params *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					assert.True(t, aws.ToBool(params.Encrypted))
+					assert.Equal(t, "alias/migration", aws.ToString(params.KmsKeyId))
+					return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-encrypted")}, nil
+				}
+			},
+			wantID: "vol-encrypted",
+		},
+		{
+			name:       "provisioned_iops",
+			snapshotID: "snap-123",
+			targetZone: "us-west-2a",
+			sizeGiB:    100,
+			spec:       VolumeSpec{Type: VolumeTypeIO2, IOPS: 16000},
+			mockSetup: func(m *mockEC2API) {
+				m.createVolumeFunc = func(_ context.Context, params *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					assert.Equal(t, ec2types.VolumeTypeIo2, params.VolumeType)
+					assert.Equal(t, int32(16000), aws.ToInt32(params.Iops))
+					return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-io2")}, nil
+				}
+			},
+			wantID: "vol-io2",
+		},
+		{
+			name:        "client_token_new_volume",
+			snapshotID:  "snap-123",
+			targetZone:  "us-west-2a",
+			clientToken: "token-1",
+			sizeGiB:     100,
+			mockSetup: func(m *mockEC2API) {
+				m.createVolumeFunc = func(_ context.Context, params *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					assert.Equal(t, "token-1", aws.ToString(params.ClientToken))
+					return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-new"), State: ec2types.VolumeStateCreating}, nil
+				}
+			},
+			wantID: "vol-new",
+		},
+		{
+			name:        "client_token_adopted",
+			snapshotID:  "snap-123",
+			targetZone:  "us-west-2a",
+			clientToken: "token-1",
+			sizeGiB:     100,
+			mockSetup: func(m *mockEC2API) {
+				m.createVolumeFunc = func(_ context.Context, _ *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-earlier"), State: ec2types.VolumeStateAvailable}, nil
+				}
+			},
+			wantID:      "vol-earlier",
+			wantAdopted: true,
+		},
+		{
+			name:        "client_token_volume_in_use",
+			snapshotID:  "snap-123",
+			targetZone:  "us-west-2a",
+			clientToken: "token-1",
+			sizeGiB:     100,
+			mockSetup: func(m *mockEC2API) {
+				m.createVolumeFunc = func(_ context.Context, _ *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					return &ec2.CreateVolumeOutput{VolumeId: aws.String("vol-earlier"), State: ec2types.VolumeStateInUse}, nil
+				}
+			},
+			wantErr: true,
+		},
 		{
 			name:       "api_error",
 			snapshotID: "snap-error",
@@ -297,7 +623,7 @@ func TestClient_CreateVolume(t *testing.T) {
 			client := NewEC2ClientWithInterface(mock)
 			ctx := context.Background()
 
-			volumeID, err := client.CreateVolume(ctx, tc.snapshotID, tc.targetZone, tc.pvcName, tc.namespace, tc.sizeGiB)
+			volumeID, adopted, err := client.CreateVolume(ctx, tc.snapshotID, tc.targetZone, tc.pvcName, tc.namespace, tc.kmsKeyID, tc.clientToken, tc.sizeGiB, tc.spec, nil)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -306,6 +632,7 @@ func TestClient_CreateVolume(t *testing.T) {
 
 			require.NoError(t, err)
 			assert.Equal(t, tc.wantID, volumeID)
+			assert.Equal(t, tc.wantAdopted, adopted)
 		})
 	}
 }
@@ -425,6 +752,10 @@ func TestClient_GetVolumeInfo(t *testing.T) {
 								VolumeId:         aws.String("vol-123"),
 								AvailabilityZone: aws.String("us-west-2a"),
 								State:            ec2types.VolumeStateAvailable,
+								Size:             aws.Int32(10),
+								VolumeType:       ec2types.VolumeTypeGp3,
+								Iops:             aws.Int32(6000),
+								Throughput:       aws.Int32(250),
 							},
 						},
 					}, nil
@@ -434,6 +765,10 @@ func TestClient_GetVolumeInfo(t *testing.T) {
 				VolumeID:         "vol-123",
 				AvailabilityZone: "us-west-2a",
 				State:            "available",
+				SizeGiB:          10,
+				VolumeType:       "gp3",
+				IOPS:             6000,
+				Throughput:       250,
 			},
 			wantErr: false,
 		},
@@ -485,6 +820,54 @@ func TestClient_GetVolumeInfo(t *testing.T) {
 	}
 }
 
+func TestClient_GetVolumesInfo(t *testing.T) {
+	t.Parallel()
+
+	ids := make([]string, 450)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("vol-%d", i)
+	}
+	var batches []int
+	mock := &mockEC2API{
+		describeVolumesFunc: func(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			assert.Empty(t, params.VolumeIds, "missing volumes must not fail the batch")
+			require.Len(t, params.Filters, 1)
+			assert.Equal(t, "volume-id", aws.ToString(params.Filters[0].Name))
+			values := params.Filters[0].Values
+			batches = append(batches, len(values))
+
+			out := &ec2.DescribeVolumesOutput{}
+			for _, id := range values {
+				if id == "vol-7" {
+					continue // Deleted
+				}
+				vol := ec2types.Volume{VolumeId: aws.String(id), AvailabilityZone: aws.String("eu-west-1a")}
+				if id == "vol-3" {
+					vol.Encrypted, vol.KmsKeyId = aws.Bool(true), aws.String("alias/prod")
+				}
+				out.Volumes = append(out.Volumes, vol)
+			}
+			return out, nil
+		},
+	}
+
+	volumes, err := NewEC2ClientWithInterface(mock).GetVolumesInfo(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, []int{200, 200, 50}, batches)
+	assert.Len(t, volumes, 449)
+	assert.NotContains(t, volumes, "vol-7")
+	assert.Equal(t, "eu-west-1a", volumes["vol-449"].AvailabilityZone)
+	assert.True(t, volumes["vol-3"].Encrypted)
+	assert.Equal(t, "alias/prod", volumes["vol-3"].KMSKeyID)
+	assert.False(t, volumes["vol-4"].Encrypted)
+
+	mock.describeVolumesFunc = func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+		return nil, errors.New("access denied")
+	}
+	_, err = NewEC2ClientWithInterface(mock).GetVolumesInfo(context.Background(), ids)
+	require.Error(t, err)
+}
+
 func TestVolumeInfo_Struct(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// DefaultKMSKeyAlias is the AWS managed key EBS encrypts with when the account
+// has no default key of its own
+const DefaultKMSKeyAlias = "alias/aws/ebs"
+
+// ebsSettingsAPI is the internal interface for the account's EBS settings
+type ebsSettingsAPI interface {
+	GetEbsEncryptionByDefault(ctx context.Context, params *ec2.GetEbsEncryptionByDefaultInput, optFns ...func(*ec2.Options)) (*ec2.GetEbsEncryptionByDefaultOutput, error)
+	GetEbsDefaultKmsKeyId(ctx context.Context, params *ec2.GetEbsDefaultKmsKeyIdInput, optFns ...func(*ec2.Options)) (*ec2.GetEbsDefaultKmsKeyIdOutput, error)
+}
+
+// EncryptionDefaults are the account's EBS encryption settings in the region
+type EncryptionDefaults struct {
+	ByDefault bool   // Volumes are encrypted even when created from unencrypted snapshots
+	KMSKeyID  string // Key used when no key is given, e.g. alias/aws/ebs
+}
+
+// NewEC2ClientWithEBSSettings creates a Client with custom EC2 and EBS settings API implementations (for testing)
+func NewEC2ClientWithEBSSettings(api ec2ClientAPI, settings ebsSettingsAPI) *Client {
+	return &Client{ec2: api, settings: settings}
+}
+
+// EncryptionDefaults reads whether the account encrypts new EBS volumes by
+// default in the region, and with which key
+func (c *Client) EncryptionDefaults(ctx context.Context) (_ *EncryptionDefaults, err error) {
+	if c.settings == nil {
+		return nil, fmt.Errorf("EBS settings client not configured")
+	}
+
+	ctx, span := tracer.Start(ctx, "ec2.EncryptionDefaults")
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: GetEbsEncryptionByDefault")
+	byDefault, err := c.settings.GetEbsEncryptionByDefault(ctx, &ec2.GetEbsEncryptionByDefaultInput{})
+	if err != nil {
+		slog.Info("ec2: GetEbsEncryptionByDefault failed", "error", err)
+		return nil, err
+	}
+	slog.Info("ec2: GetEbsDefaultKmsKeyId")
+	key, err := c.settings.GetEbsDefaultKmsKeyId(ctx, &ec2.GetEbsDefaultKmsKeyIdInput{})
+	if err != nil {
+		slog.Info("ec2: GetEbsDefaultKmsKeyId failed", "error", err)
+		return nil, err
+	}
+
+	defaults := &EncryptionDefaults{
+		ByDefault: aws.ToBool(byDefault.EbsEncryptionByDefault),
+		KMSKeyID:  aws.ToString(key.KmsKeyId),
+	}
+	if defaults.KMSKeyID == "" {
+		defaults.KMSKeyID = DefaultKMSKeyAlias
+	}
+	span.SetAttributes(attribute.Bool("ec2.encryption_by_default", defaults.ByDefault))
+	return defaults, nil
+}
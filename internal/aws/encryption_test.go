@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEBSSettingsAPI implements the ebsSettingsAPI interface for testing
+type mockEBSSettingsAPI struct {
+	byDefault bool
+	keyID     string
+	err       error
+}
+
+func (m *mockEBSSettingsAPI) GetEbsEncryptionByDefault(context.Context, *ec2.GetEbsEncryptionByDefaultInput, ...func(*ec2.Options)) (*ec2.GetEbsEncryptionByDefaultOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ec2.GetEbsEncryptionByDefaultOutput{EbsEncryptionByDefault: aws.Bool(m.byDefault)}, nil
+}
+
+func (m *mockEBSSettingsAPI) GetEbsDefaultKmsKeyId(context.Context, *ec2.GetEbsDefaultKmsKeyIdInput, ...func(*ec2.Options)) (*ec2.GetEbsDefaultKmsKeyIdOutput, error) {
+	return &ec2.GetEbsDefaultKmsKeyIdOutput{KmsKeyId: aws.String(m.keyID)}, nil
+}
+
+func TestClient_EncryptionDefaults(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		settings *mockEBSSettingsAPI
+		want     *EncryptionDefaults
+		wantErr  bool
+	}{
+		{
+			name:     "customer_key",
+			settings: &mockEBSSettingsAPI{byDefault: true, keyID: "arn:aws:kms:eu-west-1:123456789012:key/abc"},
+			want:     &EncryptionDefaults{ByDefault: true, KMSKeyID: "arn:aws:kms:eu-west-1:123456789012:key/abc"},
+		},
+		{
+			name:     "aws_managed_key",
+			settings: &mockEBSSettingsAPI{},
+			want:     &EncryptionDefaults{KMSKeyID: DefaultKMSKeyAlias},
+		},
+		{
+			name:     "api_error",
+			settings: &mockEBSSettingsAPI{err: errors.New("access denied")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewEC2ClientWithEBSSettings(&mockEC2API{}, tc.settings).EncryptionDefaults(context.Background())
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := NewEC2ClientWithInterface(&mockEC2API{}).EncryptionDefaults(context.Background())
+	require.Error(t, err, "needs the settings client")
+}
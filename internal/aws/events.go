@@ -0,0 +1,146 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// Lifecycle event types published for a migration run
+const (
+	LifecycleStarted      = "started"
+	LifecyclePVCCompleted = "pvc-completed"
+	LifecyclePVCFailed    = "pvc-failed"
+	LifecycleFinished     = "finished"
+)
+
+// EventSource is the EventBridge source of published events
+const EventSource = "pvc-migrator"
+
+// LifecycleEvent is the JSON document published for each lifecycle event. PVC
+// fields are set for pvc-* events and the counts for started/finished events.
+type LifecycleEvent struct {
+	Type           string    `json:"type"`
+	Time           time.Time `json:"time"`
+	KubeContext    string    `json:"context,omitempty"`
+	TargetZone     string    `json:"targetZone"`
+	DryRun         bool      `json:"dryRun,omitempty"`
+	PVC            string    `json:"pvc,omitempty"`
+	Namespace      string    `json:"namespace,omitempty"`
+	SourceVolumeID string    `json:"sourceVolumeId,omitempty"`
+	SnapshotID     string    `json:"snapshotId,omitempty"`
+	VolumeID       string    `json:"volumeId,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Total          int       `json:"total,omitempty"`
+	Migrated       int       `json:"migrated,omitempty"`
+	Skipped        int       `json:"skipped,omitempty"`
+	Failed         int       `json:"failed,omitempty"`
+}
+
+// snsAPI is the internal interface for SNS operations
+type snsAPI interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// eventBridgeAPI is the internal interface for EventBridge operations
+type eventBridgeAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// EventPublisher publishes lifecycle events to an SNS topic, an EventBridge bus, or both
+type EventPublisher struct {
+	sns      snsAPI
+	topicARN string
+	eb       eventBridgeAPI
+	busName  string
+}
+
+// NewEventPublisher creates a publisher for the given SNS topic ARN and EventBridge
+// bus name from cfg, the EC2 client's Config, so events are published with the
+// role it assumed. Either destination may be empty to skip it.
+func NewEventPublisher(cfg aws.Config, topicARN, busName string) *EventPublisher {
+	p := &EventPublisher{topicARN: topicARN, busName: busName}
+	if topicARN != "" {
+		p.sns = sns.NewFromConfig(cfg)
+	}
+	if busName != "" {
+		p.eb = eventbridge.NewFromConfig(cfg)
+	}
+	return p
+}
+
+// NewEventPublisherWithInterface creates a publisher with custom API implementations (for testing)
+func NewEventPublisherWithInterface(snsClient snsAPI, topicARN string, ebClient eventBridgeAPI, busName string) *EventPublisher {
+	return &EventPublisher{sns: snsClient, topicARN: topicARN, eb: ebClient, busName: busName}
+}
+
+// Publish sends the event to every configured destination. Errors from each
+// destination are joined so one failing target does not hide the other.
+func (p *EventPublisher) Publish(ctx context.Context, e LifecycleEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+	}
+
+	var errs []error
+	if p.sns != nil {
+		slog.Info("sns: Publish", "topic", p.topicARN, "type", e.Type)
+		_, err := p.sns.Publish(ctx, &sns.PublishInput{
+			TopicArn: aws.String(p.topicARN),
+			Subject:  aws.String(eventSubject(e)),
+			Message:  aws.String(string(body)),
+			MessageAttributes: map[string]snstypes.MessageAttributeValue{
+				"type": {DataType: aws.String("String"), StringValue: aws.String(e.Type)},
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to publish %s event to SNS: %w", e.Type, err))
+		}
+	}
+
+	if p.eb != nil {
+		slog.Info("eventbridge: PutEvents", "bus", p.busName, "type", e.Type)
+		out, err := p.eb.PutEvents(ctx, &eventbridge.PutEventsInput{
+			Entries: []ebtypes.PutEventsRequestEntry{{
+				EventBusName: aws.String(p.busName),
+				Source:       aws.String(EventSource),
+				DetailType:   aws.String(eventSubject(e)),
+				Detail:       aws.String(string(body)),
+				Time:         aws.Time(e.Time),
+			}},
+		})
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("failed to put %s event on EventBridge: %w", e.Type, err))
+		case out.FailedEntryCount > 0 && len(out.Entries) > 0:
+			errs = append(errs, fmt.Errorf("EventBridge rejected %s event: %s", e.Type, aws.ToString(out.Entries[0].ErrorMessage)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// eventSubject is the SNS subject and EventBridge detail-type of an event
+func eventSubject(e LifecycleEvent) string {
+	switch e.Type {
+	case LifecycleStarted:
+		return "PVC Migration Started"
+	case LifecyclePVCCompleted:
+		return "PVC Migration PVC Completed"
+	case LifecyclePVCFailed:
+		return "PVC Migration PVC Failed"
+	case LifecycleFinished:
+		return "PVC Migration Finished"
+	default:
+		return "PVC Migration Event"
+	}
+}
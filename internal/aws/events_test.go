@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSNSAPI implements the snsAPI interface for testing
+type mockSNSAPI struct {
+	publishFunc func(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+	inputs      []*sns.PublishInput
+}
+
+func (m *mockSNSAPI) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	m.inputs = append(m.inputs, params)
+	if m.publishFunc != nil {
+		return m.publishFunc(ctx, params, optFns...)
+	}
+	return &sns.PublishOutput{}, nil
+}
+
+// mockEventBridgeAPI implements the eventBridgeAPI interface for testing
+type mockEventBridgeAPI struct {
+	putEventsFunc func(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+	inputs        []*eventbridge.PutEventsInput
+}
+
+func (m *mockEventBridgeAPI) PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	m.inputs = append(m.inputs, params)
+	if m.putEventsFunc != nil {
+		return m.putEventsFunc(ctx, params, optFns...)
+	}
+	return &eventbridge.PutEventsOutput{}, nil
+}
+
+func TestEventPublisher_PublishSNS(t *testing.T) {
+	t.Parallel()
+
+	snsMock := &mockSNSAPI{}
+	p := NewEventPublisherWithInterface(snsMock, "arn:aws:sns:eu-west-1:123456789012:migrations", nil, "")
+
+	e := LifecycleEvent{
+		Type:       LifecyclePVCFailed,
+		Time:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		TargetZone: "eu-west-1a",
+		PVC:        "data-0",
+		Namespace:  "default",
+		Error:      "snapshot failed",
+	}
+	require.NoError(t, p.Publish(context.Background(), e))
+
+	require.Len(t, snsMock.inputs, 1)
+	in := snsMock.inputs[0]
+	assert.Equal(t, "arn:aws:sns:eu-west-1:123456789012:migrations", aws.ToString(in.TopicArn))
+	assert.Equal(t, "PVC Migration PVC Failed", aws.ToString(in.Subject))
+	require.Contains(t, in.MessageAttributes, "type")
+	assert.Equal(t, "String", aws.ToString(in.MessageAttributes["type"].DataType))
+	assert.Equal(t, LifecyclePVCFailed, aws.ToString(in.MessageAttributes["type"].StringValue))
+
+	var got LifecycleEvent
+	require.NoError(t, json.Unmarshal([]byte(aws.ToString(in.Message)), &got))
+	assert.Equal(t, e, got)
+}
+
+func TestEventPublisher_PublishEventBridge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		mockSetup func(m *mockEventBridgeAPI)
+		wantErr   string
+	}{
+		{
+			name: "accepted",
+		},
+		{
+			name: "rejected entry",
+			mockSetup: func(m *mockEventBridgeAPI) {
+				m.putEventsFunc = func(_ context.Context, _ *eventbridge.PutEventsInput, _ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+					return &eventbridge.PutEventsOutput{
+						FailedEntryCount: 1,
+						Entries: []ebtypes.PutEventsResultEntry{{
+							ErrorCode:    aws.String("InternalFailure"),
+							ErrorMessage: aws.String("bus unavailable"),
+						}},
+					}, nil
+				}
+			},
+			wantErr: "EventBridge rejected started event: bus unavailable",
+		},
+		{
+			name: "API error",
+			mockSetup: func(m *mockEventBridgeAPI) {
+				m.putEventsFunc = func(_ context.Context, _ *eventbridge.PutEventsInput, _ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+					return nil, errors.New("access denied")
+				}
+			},
+			wantErr: "failed to put started event on EventBridge: access denied",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ebMock := &mockEventBridgeAPI{}
+			if tc.mockSetup != nil {
+				tc.mockSetup(ebMock)
+			}
+			p := NewEventPublisherWithInterface(nil, "", ebMock, "migrations")
+
+			at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			err := p.Publish(context.Background(), LifecycleEvent{Type: LifecycleStarted, Time: at, Total: 3})
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, ebMock.inputs, 1)
+			require.Len(t, ebMock.inputs[0].Entries, 1)
+			entry := ebMock.inputs[0].Entries[0]
+			assert.Equal(t, "migrations", aws.ToString(entry.EventBusName))
+			assert.Equal(t, EventSource, aws.ToString(entry.Source))
+			assert.Equal(t, "PVC Migration Started", aws.ToString(entry.DetailType))
+			assert.Equal(t, at, aws.ToTime(entry.Time))
+			assert.JSONEq(t, `{"type":"started","time":"2026-01-02T03:04:05Z","targetZone":"","total":3}`, aws.ToString(entry.Detail))
+		})
+	}
+}
+
+func TestEventPublisher_PublishBothFail(t *testing.T) {
+	t.Parallel()
+
+	snsMock := &mockSNSAPI{
+		publishFunc: func(_ context.Context, _ *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+			return nil, errors.New("topic not found")
+		},
+	}
+	ebMock := &mockEventBridgeAPI{
+		putEventsFunc: func(_ context.Context, _ *eventbridge.PutEventsInput, _ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+			return nil, errors.New("bus not found")
+		},
+	}
+	p := NewEventPublisherWithInterface(snsMock, "arn:aws:sns:eu-west-1:123456789012:migrations", ebMock, "migrations")
+
+	err := p.Publish(context.Background(), LifecycleEvent{Type: LifecycleFinished, Time: time.Now()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to publish finished event to SNS: topic not found")
+	assert.Contains(t, err.Error(), "failed to put finished event on EventBridge: bus not found")
+	assert.Len(t, snsMock.inputs, 1, "SNS failure must not stop the EventBridge publish")
+	assert.Len(t, ebMock.inputs, 1)
+}
+
+func TestEventSubject(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		eventType string
+		want      string
+	}{
+		{LifecycleStarted, "PVC Migration Started"},
+		{LifecyclePVCCompleted, "PVC Migration PVC Completed"},
+		{LifecyclePVCFailed, "PVC Migration PVC Failed"},
+		{LifecycleFinished, "PVC Migration Finished"},
+		{"unknown", "PVC Migration Event"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.eventType, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, eventSubject(LifecycleEvent{Type: tc.eventType}))
+		})
+	}
+}
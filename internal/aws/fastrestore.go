@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// fastRestoreAPI is the internal interface for fast snapshot restore operations
+type fastRestoreAPI interface {
+	EnableFastSnapshotRestores(ctx context.Context, params *ec2.EnableFastSnapshotRestoresInput, optFns ...func(*ec2.Options)) (*ec2.EnableFastSnapshotRestoresOutput, error)
+	DisableFastSnapshotRestores(ctx context.Context, params *ec2.DisableFastSnapshotRestoresInput, optFns ...func(*ec2.Options)) (*ec2.DisableFastSnapshotRestoresOutput, error)
+	DescribeFastSnapshotRestores(ctx context.Context, params *ec2.DescribeFastSnapshotRestoresInput, optFns ...func(*ec2.Options)) (*ec2.DescribeFastSnapshotRestoresOutput, error)
+}
+
+// States of fast snapshot restore of a snapshot in a zone. Volumes created from
+// the snapshot are only fully initialized once it is enabled.
+const (
+	FastRestoreEnabling   = string(ec2types.FastSnapshotRestoreStateCodeEnabling)
+	FastRestoreOptimizing = string(ec2types.FastSnapshotRestoreStateCodeOptimizing)
+	FastRestoreEnabled    = string(ec2types.FastSnapshotRestoreStateCodeEnabled)
+	FastRestoreDisabling  = string(ec2types.FastSnapshotRestoreStateCodeDisabling)
+)
+
+// NewEC2ClientWithFastRestore creates a Client with custom EC2 and fast snapshot restore API implementations (for testing)
+func NewEC2ClientWithFastRestore(api ec2ClientAPI, fastRestore fastRestoreAPI) *Client {
+	return &Client{ec2: api, fsr: fastRestore}
+}
+
+// FastSnapshotRestoreState returns the state of fast snapshot restore of the
+// snapshot in the zone, such as FastRestoreEnabled, or "" when it is disabled
+func (c *Client) FastSnapshotRestoreState(ctx context.Context, snapshotID, zone string) (_ string, err error) {
+	if c.fsr == nil {
+		return "", fmt.Errorf("fast snapshot restore client not configured")
+	}
+	ctx, span := tracer.Start(ctx, "ec2.DescribeFastSnapshotRestores")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.String("ec2.zone", zone))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeFastSnapshotRestores", "snapshotId", snapshotID, "zone", zone)
+	out, err := c.fsr.DescribeFastSnapshotRestores(ctx, &ec2.DescribeFastSnapshotRestoresInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("snapshot-id"), Values: []string{snapshotID}},
+			{Name: aws.String("availability-zone"), Values: []string{zone}},
+		},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeFastSnapshotRestores failed", "snapshotId", snapshotID, "error", err)
+		return "", err
+	}
+	for _, restore := range out.FastSnapshotRestores {
+		if state := string(restore.State); state != string(ec2types.FastSnapshotRestoreStateCodeDisabled) {
+			return state, nil
+		}
+	}
+	return "", nil
+}
+
+// EnableFastSnapshotRestore enables fast snapshot restore of the snapshot in the
+// zone. It is billed by the hour until disabled.
+func (c *Client) EnableFastSnapshotRestore(ctx context.Context, snapshotID, zone string) (err error) {
+	if c.fsr == nil {
+		return fmt.Errorf("fast snapshot restore client not configured")
+	}
+	ctx, span := tracer.Start(ctx, "ec2.EnableFastSnapshotRestores")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.String("ec2.zone", zone))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: EnableFastSnapshotRestores", "snapshotId", snapshotID, "zone", zone)
+	out, err := c.fsr.EnableFastSnapshotRestores(ctx, &ec2.EnableFastSnapshotRestoresInput{
+		SourceSnapshotIds: []string{snapshotID},
+		AvailabilityZones: []string{zone},
+	})
+	if err != nil {
+		slog.Info("ec2: EnableFastSnapshotRestores failed", "snapshotId", snapshotID, "error", err)
+		return err
+	}
+	for _, item := range out.Unsuccessful {
+		for _, e := range item.FastSnapshotRestoreStateErrors {
+			if e.Error != nil {
+				return fastRestoreError(e.Error.Code, e.AvailabilityZone, e.Error.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// DisableFastSnapshotRestore disables fast snapshot restore of the snapshot in the zone
+func (c *Client) DisableFastSnapshotRestore(ctx context.Context, snapshotID, zone string) (err error) {
+	if c.fsr == nil {
+		return fmt.Errorf("fast snapshot restore client not configured")
+	}
+	ctx, span := tracer.Start(ctx, "ec2.DisableFastSnapshotRestores")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.String("ec2.zone", zone))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DisableFastSnapshotRestores", "snapshotId", snapshotID, "zone", zone)
+	out, err := c.fsr.DisableFastSnapshotRestores(ctx, &ec2.DisableFastSnapshotRestoresInput{
+		SourceSnapshotIds: []string{snapshotID},
+		AvailabilityZones: []string{zone},
+	})
+	if err != nil {
+		slog.Info("ec2: DisableFastSnapshotRestores failed", "snapshotId", snapshotID, "error", err)
+		return err
+	}
+	for _, item := range out.Unsuccessful {
+		for _, e := range item.FastSnapshotRestoreStateErrors {
+			if e.Error != nil {
+				return fastRestoreError(e.Error.Code, e.AvailabilityZone, e.Error.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// fastRestoreError is the error EC2 reported for a snapshot in a zone
+func fastRestoreError(code, zone, message *string) error {
+	return fmt.Errorf("%s in %s: %s", aws.ToString(code), aws.ToString(zone), aws.ToString(message))
+}
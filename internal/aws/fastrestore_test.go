@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFastRestoreAPI implements the fastRestoreAPI interface for testing
+type mockFastRestoreAPI struct {
+	states      []ec2types.FastSnapshotRestoreStateCode
+	filters     []ec2types.Filter
+	enableOut   *ec2.EnableFastSnapshotRestoresOutput
+	disableOut  *ec2.DisableFastSnapshotRestoresOutput
+	enableInput *ec2.EnableFastSnapshotRestoresInput
+}
+
+func (m *mockFastRestoreAPI) EnableFastSnapshotRestores(_ context.Context, params *ec2.EnableFastSnapshotRestoresInput, _ ...func(*ec2.Options)) (*ec2.EnableFastSnapshotRestoresOutput, error) {
+	m.enableInput = params
+	return m.enableOut, nil
+}
+
+func (m *mockFastRestoreAPI) DisableFastSnapshotRestores(context.Context, *ec2.DisableFastSnapshotRestoresInput, ...func(*ec2.Options)) (*ec2.DisableFastSnapshotRestoresOutput, error) {
+	return m.disableOut, nil
+}
+
+func (m *mockFastRestoreAPI) DescribeFastSnapshotRestores(_ context.Context, params *ec2.DescribeFastSnapshotRestoresInput, _ ...func(*ec2.Options)) (*ec2.DescribeFastSnapshotRestoresOutput, error) {
+	m.filters = params.Filters
+	out := &ec2.DescribeFastSnapshotRestoresOutput{}
+	for _, state := range m.states {
+		out.FastSnapshotRestores = append(out.FastSnapshotRestores, ec2types.DescribeFastSnapshotRestoreSuccessItem{State: state})
+	}
+	return out, nil
+}
+
+func TestClient_FastSnapshotRestoreState(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		states []ec2types.FastSnapshotRestoreStateCode
+		want   string
+	}{
+		{name: "never enabled"},
+		{name: "disabled", states: []ec2types.FastSnapshotRestoreStateCode{ec2types.FastSnapshotRestoreStateCodeDisabled}},
+		{name: "optimizing", states: []ec2types.FastSnapshotRestoreStateCode{ec2types.FastSnapshotRestoreStateCodeOptimizing}, want: FastRestoreOptimizing},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockFastRestoreAPI{states: tc.states}
+			state, err := NewEC2ClientWithFastRestore(&mockEC2API{}, mock).FastSnapshotRestoreState(context.Background(), "snap-1", "eu-west-1a")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, state)
+			assert.Equal(t, []ec2types.Filter{
+				{Name: aws.String("snapshot-id"), Values: []string{"snap-1"}},
+				{Name: aws.String("availability-zone"), Values: []string{"eu-west-1a"}},
+			}, mock.filters)
+		})
+	}
+}
+
+func TestClient_EnableFastSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockFastRestoreAPI{enableOut: &ec2.EnableFastSnapshotRestoresOutput{}}
+	client := NewEC2ClientWithFastRestore(&mockEC2API{}, mock)
+	require.NoError(t, client.EnableFastSnapshotRestore(context.Background(), "snap-1", "eu-west-1a"))
+	assert.Equal(t, []string{"snap-1"}, mock.enableInput.SourceSnapshotIds)
+	assert.Equal(t, []string{"eu-west-1a"}, mock.enableInput.AvailabilityZones)
+
+	mock.enableOut = &ec2.EnableFastSnapshotRestoresOutput{Unsuccessful: []ec2types.EnableFastSnapshotRestoreErrorItem{{
+		SnapshotId: aws.String("snap-1"),
+		FastSnapshotRestoreStateErrors: []ec2types.EnableFastSnapshotRestoreStateErrorItem{{
+			AvailabilityZone: aws.String("eu-west-1a"),
+			Error:            &ec2types.EnableFastSnapshotRestoreStateError{Code: aws.String("InsufficientCredits"), Message: aws.String("no credits left")},
+		}},
+	}}}
+	err := client.EnableFastSnapshotRestore(context.Background(), "snap-1", "eu-west-1a")
+	assert.EqualError(t, err, "InsufficientCredits in eu-west-1a: no credits left")
+
+	_, err = NewEC2ClientWithInterface(&mockEC2API{}).FastSnapshotRestoreState(context.Background(), "snap-1", "eu-west-1a")
+	assert.ErrorContains(t, err, "fast snapshot restore client not configured")
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// APIHook is called after every EC2 API call with the operation name and the
+// error it returned, if any. Hooks must be safe for concurrent use.
+type APIHook func(operation string, err error)
+
+// SetAPIHook registers a hook that observes every EC2 API call made by the client,
+// including the polls issued by waiters. A later call replaces the previous hook.
+func (c *Client) SetAPIHook(hook APIHook) {
+	if h, ok := c.ec2.(*hookedEC2); ok {
+		h.hook = hook
+		return
+	}
+	c.ec2 = &hookedEC2{next: c.ec2, hook: hook}
+}
+
+// hookedEC2 decorates an ec2ClientAPI and reports each call to a hook
+type hookedEC2 struct {
+	next ec2ClientAPI
+	hook APIHook
+}
+
+func (h *hookedEC2) CreateSnapshot(ctx context.Context, params *ec2.CreateSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	out, err := h.next.CreateSnapshot(ctx, params, optFns...)
+	h.hook("CreateSnapshot", err)
+	return out, err
+}
+
+func (h *hookedEC2) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	out, err := h.next.DescribeSnapshots(ctx, params, optFns...)
+	h.hook("DescribeSnapshots", err)
+	return out, err
+}
+
+func (h *hookedEC2) CreateVolume(ctx context.Context, params *ec2.CreateVolumeInput, optFns ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	out, err := h.next.CreateVolume(ctx, params, optFns...)
+	h.hook("CreateVolume", err)
+	return out, err
+}
+
+func (h *hookedEC2) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	out, err := h.next.DescribeVolumes(ctx, params, optFns...)
+	h.hook("DescribeVolumes", err)
+	return out, err
+}
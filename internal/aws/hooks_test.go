@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SetAPIHook(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockEC2API{
+		createSnapshotFunc: func(_ context.Context, _ *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+			return &ec2.CreateSnapshotOutput{SnapshotId: aws.String("snap-1")}, nil
+		},
+		describeVolumesFunc: func(_ context.Context, _ *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+	client := NewEC2ClientWithInterface(mock)
+
+	type call struct {
+		op     string
+		failed bool
+	}
+	var (
+		mu    sync.Mutex
+		calls []call
+	)
+	client.SetAPIHook(func(op string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call{op: op, failed: err != nil})
+	})
+
+	_, err := client.CreateSnapshot(context.Background(), "vol-1", "pvc", "us-west-2a", "", nil)
+	require.NoError(t, err)
+	_, err = client.GetVolumeState(context.Background(), "vol-1")
+	require.Error(t, err)
+
+	assert.Equal(t, []call{
+		{op: "CreateSnapshot", failed: false},
+		{op: "DescribeVolumes", failed: true},
+	}, calls)
+}
+
+func TestClient_SetAPIHook_Replaces(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockEC2API{
+		describeSnapshotsFunc: func(_ context.Context, _ *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			return &ec2.DescribeSnapshotsOutput{
+				Snapshots: []ec2types.Snapshot{{State: ec2types.SnapshotStateCompleted, Progress: aws.String("100%")}},
+			}, nil
+		},
+	}
+	client := NewEC2ClientWithInterface(mock)
+
+	first, second := 0, 0
+	client.SetAPIHook(func(string, error) { first++ })
+	client.SetAPIHook(func(string, error) { second++ })
+
+	_, _, err := client.GetSnapshotProgress(context.Background(), "snap-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 1, second)
+}
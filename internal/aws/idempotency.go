@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// TagClientToken carries the client token of the migration that took a
+// snapshot. CreateSnapshot has no idempotency token, so a run restarted after a
+// crash finds the snapshot it already took by this tag.
+const TagClientToken = "pvc-migrator/client-token"
+
+// ClientToken derives an idempotency token from parts, such as the migration ID
+// and the PVC name. It is 64 characters long, the most EC2 accepts.
+func ClientToken(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindSnapshotByToken returns the most recent pending or completed snapshot of
+// volumeID tagged with the client token, or nil when there is none
+func (c *Client) FindSnapshotByToken(ctx context.Context, volumeID, clientToken string) (_ *SnapshotInfo, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeSnapshots", "volumeId", volumeID, "clientToken", clientToken)
+	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+		Filters: []ec2types.Filter{
+			{Name: aws.String("volume-id"), Values: []string{volumeID}},
+			{Name: aws.String("status"), Values: []string{string(ec2types.SnapshotStatePending), string(ec2types.SnapshotStateCompleted)}},
+			{Name: aws.String("tag:" + TagClientToken), Values: []string{clientToken}},
+		},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeSnapshots failed", "volumeId", volumeID, "error", err)
+		return nil, err
+	}
+
+	var latest *SnapshotInfo
+	for _, snap := range result.Snapshots {
+		started := aws.ToTime(snap.StartTime)
+		if latest == nil || started.After(latest.StartTime) {
+			latest = &SnapshotInfo{SnapshotID: aws.ToString(snap.SnapshotId), StartTime: started}
+		}
+	}
+	return latest, nil
+}
+
+// FindMigrationSnapshot returns the most recent pending or completed snapshot a
+// migration took of volumeID for the PVC, whatever its client token, or nil when
+// there is none. Snapshots staged by the snapshot command are left out.
+func (c *Client) FindMigrationSnapshot(ctx context.Context, volumeID, pvcName string) (_ *SnapshotInfo, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	span.SetAttributes(attribute.String("ec2.volume_id", volumeID))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeSnapshots", "volumeId", volumeID, "pvc", pvcName)
+	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+		Filters: []ec2types.Filter{
+			{Name: aws.String("volume-id"), Values: []string{volumeID}},
+			{Name: aws.String("status"), Values: []string{string(ec2types.SnapshotStatePending), string(ec2types.SnapshotStateCompleted)}},
+			{Name: aws.String("tag:" + TagMigratedPVC), Values: []string{SanitizeTag(pvcName)}},
+		},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeSnapshots failed", "volumeId", volumeID, "error", err)
+		return nil, err
+	}
+
+	var latest *SnapshotInfo
+	for _, snap := range result.Snapshots {
+		if tagMap(snap.Tags)[TagStaged] == "true" {
+			continue
+		}
+		started := aws.ToTime(snap.StartTime)
+		if latest == nil || started.After(latest.StartTime) {
+			latest = &SnapshotInfo{SnapshotID: aws.ToString(snap.SnapshotId), StartTime: started}
+		}
+	}
+	return latest, nil
+}
+
+// FindMigratedVolume returns the ID of the most recent creating or available
+// volume a migration created for the PVC from snapshotID in targetZone, whatever
+// its client token, or "" when there is none
+func (c *Client) FindMigratedVolume(ctx context.Context, snapshotID, targetZone, namespace, pvcName string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeVolumes")
+	span.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.String("ec2.availability_zone", targetZone))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeVolumes", "snapshotId", snapshotID, "zone", targetZone, "pvc", namespace+"/"+pvcName)
+	result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("snapshot-id"), Values: []string{snapshotID}},
+			{Name: aws.String("availability-zone"), Values: []string{targetZone}},
+			{Name: aws.String("status"), Values: []string{string(ec2types.VolumeStateCreating), string(ec2types.VolumeStateAvailable)}},
+			{Name: aws.String("tag:" + TagMigratedPVC), Values: []string{SanitizeTag(pvcName)}},
+			{Name: aws.String("tag:kubernetes.io/created-for/pvc/namespace"), Values: []string{SanitizeTag(namespace)}},
+		},
+	})
+	if err != nil {
+		slog.Info("ec2: DescribeVolumes failed", "snapshotId", snapshotID, "error", err)
+		return "", err
+	}
+
+	var latest ec2types.Volume
+	for _, vol := range result.Volumes {
+		if latest.VolumeId == nil || aws.ToTime(vol.CreateTime).After(aws.ToTime(latest.CreateTime)) {
+			latest = vol
+		}
+	}
+	span.SetAttributes(attribute.String("ec2.volume_id", aws.ToString(latest.VolumeId)))
+	return aws.ToString(latest.VolumeId), nil
+}
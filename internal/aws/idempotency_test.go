@@ -0,0 +1,241 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientToken(t *testing.T) {
+	t.Parallel()
+
+	token := ClientToken("run-1", "db/data-0", "vol-123")
+	assert.Len(t, token, 64)
+	assert.Equal(t, token, ClientToken("run-1", "db/data-0", "vol-123"), "stable across runs")
+	assert.NotEqual(t, token, ClientToken("run-2", "db/data-0", "vol-123"))
+	assert.NotEqual(t, ClientToken("a", "bc"), ClientToken("ab", "c"), "parts are separated")
+}
+
+func TestClient_FindSnapshotByToken(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		snapshots []ec2types.Snapshot
+		err       error
+		want      *SnapshotInfo
+		wantErr   bool
+	}{
+		{
+			name: "picks the most recent",
+			snapshots: []ec2types.Snapshot{
+				{SnapshotId: aws.String("snap-old"), StartTime: aws.Time(older)},
+				{SnapshotId: aws.String("snap-new"), StartTime: aws.Time(newer)},
+			},
+			want: &SnapshotInfo{SnapshotID: "snap-new", StartTime: newer},
+		},
+		{
+			name: "none taken",
+		},
+		{
+			name:    "api error",
+			err:     errors.New("throttled"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input *ec2.DescribeSnapshotsInput
+			mock := &mockEC2API{
+				describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+					input = params
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &ec2.DescribeSnapshotsOutput{Snapshots: tt.snapshots}, nil
+				},
+			}
+			client := NewEC2ClientWithInterface(mock)
+
+			got, err := client.FindSnapshotByToken(context.Background(), "vol-123", "token-1")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			filters := make(map[string][]string)
+			for _, f := range input.Filters {
+				filters[aws.ToString(f.Name)] = f.Values
+			}
+			assert.Equal(t, map[string][]string{
+				"volume-id":             {"vol-123"},
+				"status":                {"pending", "completed"},
+				"tag:" + TagClientToken: {"token-1"},
+			}, filters)
+		})
+	}
+}
+
+func TestClient_FindMigrationSnapshot(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	staged := []ec2types.Tag{{Key: aws.String(TagStaged), Value: aws.String("true")}}
+
+	tests := []struct {
+		name      string
+		snapshots []ec2types.Snapshot
+		err       error
+		want      *SnapshotInfo
+		wantErr   bool
+	}{
+		{
+			name: "picks the most recent",
+			snapshots: []ec2types.Snapshot{
+				{SnapshotId: aws.String("snap-old"), StartTime: aws.Time(older)},
+				{SnapshotId: aws.String("snap-new"), StartTime: aws.Time(newer)},
+			},
+			want: &SnapshotInfo{SnapshotID: "snap-new", StartTime: newer},
+		},
+		{
+			name: "skips staged snapshots",
+			snapshots: []ec2types.Snapshot{
+				{SnapshotId: aws.String("snap-old"), StartTime: aws.Time(older)},
+				{SnapshotId: aws.String("snap-staged"), StartTime: aws.Time(newer), Tags: staged},
+			},
+			want: &SnapshotInfo{SnapshotID: "snap-old", StartTime: older},
+		},
+		{
+			name: "none taken",
+		},
+		{
+			name:    "api error",
+			err:     errors.New("throttled"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input *ec2.DescribeSnapshotsInput
+			mock := &mockEC2API{
+				describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+					input = params
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &ec2.DescribeSnapshotsOutput{Snapshots: tt.snapshots}, nil
+				},
+			}
+			client := NewEC2ClientWithInterface(mock)
+
+			got, err := client.FindMigrationSnapshot(context.Background(), "vol-123", "data-0")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			filters := make(map[string][]string)
+			for _, f := range input.Filters {
+				filters[aws.ToString(f.Name)] = f.Values
+			}
+			assert.Equal(t, map[string][]string{
+				"volume-id":             {"vol-123"},
+				"status":                {"pending", "completed"},
+				"tag:" + TagMigratedPVC: {"data-0"},
+			}, filters)
+		})
+	}
+}
+
+func TestClient_FindMigratedVolume(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		volumes []ec2types.Volume
+		err     error
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "picks the most recent",
+			volumes: []ec2types.Volume{
+				{VolumeId: aws.String("vol-old"), CreateTime: aws.Time(older)},
+				{VolumeId: aws.String("vol-new"), CreateTime: aws.Time(older.Add(time.Hour))},
+			},
+			want: "vol-new",
+		},
+		{
+			name: "none created",
+		},
+		{
+			name:    "api error",
+			err:     errors.New("throttled"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input *ec2.DescribeVolumesInput
+			mock := &mockEC2API{
+				describeVolumesFunc: func(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+					input = params
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &ec2.DescribeVolumesOutput{Volumes: tt.volumes}, nil
+				},
+			}
+			client := NewEC2ClientWithInterface(mock)
+
+			got, err := client.FindMigratedVolume(context.Background(), "snap-1", "eu-west-1a", "db", "data-0")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			filters := make(map[string][]string)
+			for _, f := range input.Filters {
+				filters[aws.ToString(f.Name)] = f.Values
+			}
+			assert.Equal(t, map[string][]string{
+				"snapshot-id":           {"snap-1"},
+				"availability-zone":     {"eu-west-1a"},
+				"status":                {"creating", "available"},
+				"tag:" + TagMigratedPVC: {"data-0"},
+				"tag:kubernetes.io/created-for/pvc/namespace": {"db"},
+			}, filters)
+		})
+	}
+}
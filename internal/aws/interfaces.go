@@ -9,7 +9,7 @@ import (
 // This interface enables mocking for unit tests.
 type EC2API interface {
 	// CreateSnapshot creates an EBS snapshot and returns the snapshot ID.
-	CreateSnapshot(ctx context.Context, volumeID, pvcName, targetZone string) (string, error)
+	CreateSnapshot(ctx context.Context, volumeID, pvcName, targetZone, clientToken string, extraTags map[string]string) (string, error)
 
 	// WaitForSnapshot waits for a snapshot to complete.
 	WaitForSnapshot(ctx context.Context, snapshotID string) error
@@ -18,7 +18,7 @@ type EC2API interface {
 	GetSnapshotProgress(ctx context.Context, snapshotID string) (int, string, error)
 
 	// CreateVolume creates a new EBS volume from a snapshot.
-	CreateVolume(ctx context.Context, snapshotID, targetZone, pvcName, namespace string, sizeGiB int32) (string, error)
+	CreateVolume(ctx context.Context, snapshotID, targetZone, pvcName, namespace, kmsKeyID, clientToken string, sizeGiB int32, spec VolumeSpec, extraTags map[string]string) (volumeID string, adopted bool, err error)
 
 	// WaitForVolume waits for a volume to be available.
 	WaitForVolume(ctx context.Context, volumeID string) error
@@ -28,6 +28,9 @@ type EC2API interface {
 
 	// GetVolumeInfo returns detailed information about a volume.
 	GetVolumeInfo(ctx context.Context, volumeID string) (*VolumeInfo, error)
+
+	// GetVolumesInfo returns the volumes among volumeIDs that exist, by ID.
+	GetVolumesInfo(ctx context.Context, volumeIDs []string) (map[string]*VolumeInfo, error)
 }
 
 // Ensure Client implements EC2API
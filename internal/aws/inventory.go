@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// ToolSnapshot is a snapshot the tool created, found by its tags
+type ToolSnapshot struct {
+	SnapshotID string
+	VolumeID   string // Volume it was taken of
+	PVC        string // "namespace/name" for staged snapshots, the PVC name otherwise
+	SizeGiB    int32
+	StartTime  time.Time
+	State      string // pending, completed, error...
+	Staged     bool   // Made by the snapshot command
+	// MigratedVolumeID is the volume the tool created from the snapshot, empty
+	// when no migration got that far
+	MigratedVolumeID string
+}
+
+// Migrated reports whether a migration created its new volume from the snapshot
+func (s ToolSnapshot) Migrated() bool {
+	return s.MigratedVolumeID != ""
+}
+
+// ListToolSnapshots returns every snapshot of the account in the client's region
+// that carries the tool's tags, oldest first, with the volume a migration created
+// from it if any
+func (c *Client) ListToolSnapshots(ctx context.Context) ([]ToolSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSnapshots")
+	defer span.End()
+
+	slog.Info("ec2: DescribeSnapshots", "tag", TagMigratedPVC)
+	var snapshots []ToolSnapshot
+	pages := ec2.NewDescribeSnapshotsPaginator(c.ec2, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+		Filters:  []ec2types.Filter{{Name: aws.String("tag-key"), Values: []string{TagMigratedPVC}}},
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			slog.Info("ec2: DescribeSnapshots failed", "error", err)
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+		for _, s := range page.Snapshots {
+			tags := tagMap(s.Tags)
+			snap := ToolSnapshot{
+				SnapshotID: aws.ToString(s.SnapshotId),
+				VolumeID:   aws.ToString(s.VolumeId),
+				PVC:        tags[TagMigratedPVC],
+				SizeGiB:    aws.ToInt32(s.VolumeSize),
+				StartTime:  aws.ToTime(s.StartTime),
+				State:      string(s.State),
+				Staged:     tags[TagStaged] == "true",
+			}
+			if pvc := tags[TagPVC]; pvc != "" {
+				snap.PVC = pvc
+			}
+			snapshots = append(snapshots, snap)
+		}
+	}
+
+	volumes, err := c.migratedVolumes(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	for i := range snapshots {
+		snapshots[i].MigratedVolumeID = volumes[snapshots[i].SnapshotID]
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].StartTime.Before(snapshots[j].StartTime) })
+	return snapshots, nil
+}
+
+// migratedVolumes returns the volumes the tool created, by the snapshot they were
+// created from
+func (c *Client) migratedVolumes(ctx context.Context) (map[string]string, error) {
+	slog.Info("ec2: DescribeVolumes", "tag", TagMigratedPVC)
+	volumes := make(map[string]string)
+	pages := ec2.NewDescribeVolumesPaginator(c.ec2, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag-key"), Values: []string{TagMigratedPVC}}},
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			slog.Info("ec2: DescribeVolumes failed", "error", err)
+			return nil, err
+		}
+		for _, v := range page.Volumes {
+			if id := aws.ToString(v.SnapshotId); id != "" {
+				volumes[id] = aws.ToString(v.VolumeId)
+			}
+		}
+	}
+	return volumes, nil
+}
+
+// tagMap converts EC2 tags into a map
+func tagMap(tags []ec2types.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return m
+}
@@ -0,0 +1,96 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListToolSnapshots(t *testing.T) {
+	t.Parallel()
+
+	older := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+	mock := &mockEC2API{
+		describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			assert.Equal(t, []string{"self"}, params.OwnerIds)
+			require.Len(t, params.Filters, 1)
+			assert.Equal(t, []string{TagMigratedPVC}, params.Filters[0].Values)
+			if params.NextToken == nil {
+				return &ec2.DescribeSnapshotsOutput{
+					Snapshots: []ec2types.Snapshot{{
+						SnapshotId: aws.String("snap-staged"),
+						VolumeId:   aws.String("vol-1"),
+						VolumeSize: aws.Int32(20),
+						StartTime:  aws.Time(newer),
+						State:      ec2types.SnapshotStateCompleted,
+						Tags: []ec2types.Tag{
+							{Key: aws.String(TagMigratedPVC), Value: aws.String("logs")},
+							{Key: aws.String(TagStaged), Value: aws.String("true")},
+							{Key: aws.String(TagPVC), Value: aws.String("db/logs")},
+						},
+					}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeSnapshotsOutput{
+				Snapshots: []ec2types.Snapshot{{
+					SnapshotId: aws.String("snap-migrated"),
+					VolumeId:   aws.String("vol-0"),
+					VolumeSize: aws.Int32(10),
+					StartTime:  aws.Time(older),
+					State:      ec2types.SnapshotStateCompleted,
+					Tags:       []ec2types.Tag{{Key: aws.String(TagMigratedPVC), Value: aws.String("data")}},
+				}},
+			}, nil
+		},
+		describeVolumesFunc: func(_ context.Context, _ *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			return &ec2.DescribeVolumesOutput{
+				Volumes: []ec2types.Volume{
+					{VolumeId: aws.String("vol-new"), SnapshotId: aws.String("snap-migrated")},
+					{VolumeId: aws.String("vol-blank")},
+				},
+			}, nil
+		},
+	}
+
+	snapshots, err := NewEC2ClientWithInterface(mock).ListToolSnapshots(context.Background())
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+
+	assert.Equal(t, ToolSnapshot{
+		SnapshotID:       "snap-migrated",
+		VolumeID:         "vol-0",
+		PVC:              "data",
+		SizeGiB:          10,
+		StartTime:        older,
+		State:            "completed",
+		MigratedVolumeID: "vol-new",
+	}, snapshots[0], "oldest first")
+	assert.True(t, snapshots[0].Migrated())
+
+	assert.Equal(t, "db/logs", snapshots[1].PVC, "staged snapshots name the namespace")
+	assert.True(t, snapshots[1].Staged)
+	assert.False(t, snapshots[1].Migrated())
+}
+
+func TestClient_ListToolSnapshots_Error(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockEC2API{
+		describeSnapshotsFunc: func(_ context.Context, _ *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	_, err := NewEC2ClientWithInterface(mock).ListToolSnapshots(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access denied")
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// Error codes EC2 answers a call made with DryRun with: the caller has the
+// permission, or it does not
+const (
+	errCodeDryRunOperation       = "DryRunOperation"
+	errCodeUnauthorizedOperation = "UnauthorizedOperation"
+)
+
+// dryRunAllowed reports whether a call made with DryRun would have been allowed.
+// Errors other than the two dry-run answers are returned.
+func dryRunAllowed(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case errCodeDryRunOperation:
+			return true, nil
+		case errCodeUnauthorizedOperation:
+			return false, nil
+		}
+	}
+	return false, err
+}
+
+// MissingPermissions makes the EC2 calls a migration needs with DryRun set, on
+// the volume to migrate and in its target zone, and returns the actions the
+// credentials are denied, such as "ec2:CreateSnapshot". Nothing is created.
+// ec2:CreateTags is checked by tagging on create, so it is only checked when
+// CreateSnapshot or CreateVolume is allowed.
+func (c *Client) MissingPermissions(ctx context.Context, volumeID, zone string) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.MissingPermissions")
+	defer func() { tracing.End(span, err) }()
+
+	dryRun := aws.Bool(true)
+	snapshot := func(tags []ec2types.TagSpecification) error {
+		_, err := c.ec2.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{DryRun: dryRun, VolumeId: aws.String(volumeID), TagSpecifications: tags})
+		return err
+	}
+	volume := func(tags []ec2types.TagSpecification) error {
+		_, err := c.ec2.CreateVolume(ctx, &ec2.CreateVolumeInput{
+			DryRun:            dryRun,
+			AvailabilityZone:  aws.String(zone),
+			Size:              aws.Int32(1),
+			VolumeType:        ec2types.VolumeType(VolumeType),
+			TagSpecifications: tags,
+		})
+		return err
+	}
+	probes := []struct {
+		action string
+		call   func() error
+	}{
+		{action: "ec2:DescribeVolumes", call: func() error {
+			_, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{DryRun: dryRun, VolumeIds: []string{volumeID}})
+			return err
+		}},
+		{action: "ec2:DescribeSnapshots", call: func() error {
+			_, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{DryRun: dryRun, OwnerIds: []string{"self"}})
+			return err
+		}},
+		{action: "ec2:CreateSnapshot", call: func() error { return snapshot(nil) }},
+		{action: "ec2:CreateVolume", call: func() error { return volume(nil) }},
+	}
+
+	var missing []string
+	allowed := make(map[string]bool)
+	for _, probe := range probes {
+		slog.Info("ec2: dry run", "action", probe.action, "volumeId", volumeID, "zone", zone)
+		ok, err := dryRunAllowed(probe.call())
+		if err != nil {
+			slog.Info("ec2: dry run failed", "action", probe.action, "error", err)
+			return nil, err
+		}
+		allowed[probe.action] = ok
+		if !ok {
+			missing = append(missing, probe.action)
+		}
+	}
+
+	// Tagging on create needs ec2:CreateTags on top of the create action
+	var tagged func() error
+	switch {
+	case allowed["ec2:CreateSnapshot"]:
+		tagged = func() error {
+			return snapshot([]ec2types.TagSpecification{{ResourceType: ec2types.ResourceTypeSnapshot, Tags: ec2Tags(SnapshotTags("permission-check"))}})
+		}
+	case allowed["ec2:CreateVolume"]:
+		tagged = func() error {
+			return volume([]ec2types.TagSpecification{{ResourceType: ec2types.ResourceTypeVolume, Tags: ec2Tags(SnapshotTags("permission-check"))}})
+		}
+	default:
+		slog.Warn("neither ec2:CreateSnapshot nor ec2:CreateVolume is allowed, not checking ec2:CreateTags")
+		return missing, nil
+	}
+	slog.Info("ec2: dry run", "action", "ec2:CreateTags", "volumeId", volumeID, "zone", zone)
+	ok, err := dryRunAllowed(tagged())
+	if err != nil {
+		slog.Info("ec2: dry run failed", "action", "ec2:CreateTags", "error", err)
+		return nil, err
+	}
+	if !ok {
+		missing = append(missing, "ec2:CreateTags")
+	}
+	return missing, nil
+}
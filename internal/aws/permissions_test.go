@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MissingPermissions(t *testing.T) {
+	t.Parallel()
+
+	allowed := &smithy.GenericAPIError{Code: "DryRunOperation"}
+	denied := &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+	answer := func(ok bool) error {
+		if ok {
+			return allowed
+		}
+		return denied
+	}
+
+	cases := []struct {
+		name                               string
+		describe, snapshot, volume, tagged bool
+		want                               []string
+	}{
+		{name: "all allowed", describe: true, snapshot: true, volume: true, tagged: true},
+		{name: "no tags", describe: true, snapshot: true, volume: true, want: []string{"ec2:CreateTags"}},
+		{name: "no snapshot", describe: true, volume: true, tagged: true, want: []string{"ec2:CreateSnapshot"}},
+		{
+			name: "read only",
+			want: []string{"ec2:DescribeVolumes", "ec2:DescribeSnapshots", "ec2:CreateSnapshot", "ec2:CreateVolume"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockEC2API{
+				describeVolumesFunc: func(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+					assert.True(t, aws.ToBool(params.DryRun))
+					return nil, answer(tc.describe)
+				},
+				describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+					assert.True(t, aws.ToBool(params.DryRun))
+					return nil, answer(tc.describe)
+				},
+				createSnapshotFunc: func(_ context.Context, params *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+					assert.True(t, aws.ToBool(params.DryRun))
+					assert.Equal(t, "vol-1", aws.ToString(params.VolumeId))
+					if len(params.TagSpecifications) > 0 {
+						return nil, answer(tc.snapshot && tc.tagged)
+					}
+					return nil, answer(tc.snapshot)
+				},
+				createVolumeFunc: func(_ context.Context, params *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+					assert.True(t, aws.ToBool(params.DryRun))
+					assert.Equal(t, "eu-west-1a", aws.ToString(params.AvailabilityZone))
+					if len(params.TagSpecifications) > 0 {
+						return nil, answer(tc.volume && tc.tagged)
+					}
+					return nil, answer(tc.volume)
+				},
+			}
+
+			missing, err := NewEC2ClientWithInterface(mock).MissingPermissions(context.Background(), "vol-1", "eu-west-1a")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, missing)
+		})
+	}
+}
+
+func TestClient_MissingPermissions_Error(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockEC2API{
+		describeVolumesFunc: func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+	_, err := NewEC2ClientWithInterface(mock).MissingPermissions(context.Background(), "vol-1", "eu-west-1a")
+	assert.ErrorContains(t, err, "connection reset")
+}
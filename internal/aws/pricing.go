@@ -0,0 +1,66 @@
+package aws
+
+import (
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// PriceRegion is the region whose on-demand list prices MonthlyCost uses.
+// Prices elsewhere differ by region, and not in the same proportion for every
+// type, so its costs are estimates to compare options with.
+const PriceRegion = "us-east-1"
+
+// priceTier is the price of each unit up to upTo units, 0 for any number
+type priceTier struct {
+	upTo  int32
+	price float64
+}
+
+// ebsPrice is the price, in USD a month, of an EBS volume type: of each GiB,
+// of each provisioned IOPS and MiB/s above what the type includes, in tiers
+type ebsPrice struct {
+	perGiB     float64
+	freeIOPS   int32
+	perIOPS    []priceTier
+	freeMiBps  int32
+	perMiBps   float64
+	throughput bool // Whether throughput is provisioned, and so billed
+}
+
+// ebsPrices are the on-demand list prices of EBS volumes in PriceRegion
+var ebsPrices = map[string]ebsPrice{
+	VolumeType:                          {perGiB: 0.08, freeIOPS: gp3BaseIOPS, perIOPS: []priceTier{{price: 0.005}}, freeMiBps: gp3BaseThroughput, perMiBps: 0.04, throughput: true},
+	string(ec2types.VolumeTypeGp2):      {perGiB: 0.10},
+	VolumeTypeIO1:                       {perGiB: 0.125, perIOPS: []priceTier{{price: 0.065}}},
+	VolumeTypeIO2:                       {perGiB: 0.125, perIOPS: []priceTier{{upTo: 32000, price: 0.065}, {upTo: 64000, price: 0.0455}, {price: 0.032}}},
+	string(ec2types.VolumeTypeSt1):      {perGiB: 0.045},
+	string(ec2types.VolumeTypeSc1):      {perGiB: 0.015},
+	string(ec2types.VolumeTypeStandard): {perGiB: 0.05},
+}
+
+// MonthlyCost returns the cost, in USD a month at PriceRegion's on-demand
+// prices, of a volume of the type and size with the performance. It returns
+// false for types without a known price. I/O requests, billed for standard
+// volumes, and snapshots are left out.
+func MonthlyCost(volumeType string, sizeGiB int32, perf VolumePerformance) (float64, bool) {
+	p, ok := ebsPrices[volumeType]
+	if !ok {
+		return 0, false
+	}
+	cost := float64(sizeGiB) * p.perGiB
+	iops, from := max(perf.IOPS-p.freeIOPS, 0), int32(0)
+	for _, tier := range p.perIOPS {
+		units := iops - from
+		if tier.upTo > 0 {
+			units = min(units, tier.upTo-from)
+		}
+		if units <= 0 {
+			break
+		}
+		cost += float64(units) * tier.price
+		from = tier.upTo
+	}
+	if p.throughput {
+		cost += float64(max(perf.Throughput-p.freeMiBps, 0)) * p.perMiBps
+	}
+	return cost, true
+}
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthlyCost(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		volumeType string
+		sizeGiB    int32
+		perf       VolumePerformance
+		want       float64
+		wantOK     bool
+	}{
+		{name: "gp3 baseline", volumeType: "gp3", sizeGiB: 100, perf: VolumePerformance{IOPS: 3000, Throughput: 125}, want: 8, wantOK: true},
+		{name: "gp3 provisioned", volumeType: "gp3", sizeGiB: 100, perf: VolumePerformance{IOPS: 5000, Throughput: 225}, want: 8 + 10 + 4, wantOK: true},
+		{name: "gp2", volumeType: "gp2", sizeGiB: 100, perf: VolumePerformance{IOPS: 300, Throughput: 128}, want: 10, wantOK: true},
+		{name: "io1", volumeType: "io1", sizeGiB: 100, perf: VolumePerformance{IOPS: 1000, Throughput: 250}, want: 12.5 + 65, wantOK: true},
+		{name: "io2 first tier", volumeType: "io2", sizeGiB: 100, perf: VolumePerformance{IOPS: 10000, Throughput: 2500}, want: 12.5 + 650, wantOK: true},
+		{name: "io2 every tier", volumeType: "io2", sizeGiB: 100, perf: VolumePerformance{IOPS: 74000}, want: 12.5 + 32000*0.065 + 32000*0.0455 + 10000*0.032, wantOK: true},
+		{name: "unknown type", volumeType: "io3", sizeGiB: 100},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cost, ok := MonthlyCost(tc.volumeType, tc.sizeGiB, tc.perf)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.InDelta(t, tc.want, cost, 0.001)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// Default EBS quotas of an account in a region, as documented by AWS. Accounts
+// may have been granted more through Service Quotas.
+const DefaultSnapshotQuota = 100000 // EBS snapshots per region
+
+// defaultStorageQuotasTiB is the default storage of the volumes of each type an
+// account may have per region
+var defaultStorageQuotasTiB = map[string]int{
+	VolumeType:    50,
+	VolumeTypeIO1: 300,
+	VolumeTypeIO2: 20,
+}
+
+// DefaultStorageQuotaTiB returns the default storage of the volumes of the type
+// an account may have per region
+func DefaultStorageQuotaTiB(volumeType string) int {
+	return defaultStorageQuotasTiB[volumeType]
+}
+
+// EBSUsage is what counts towards the EBS quotas the run adds to
+type EBSUsage struct {
+	Snapshots  int              // Snapshots the account owns in the region
+	StorageGiB map[string]int64 // Size of its volumes of the types new volumes are created as, by type
+}
+
+// EBSUsage counts the snapshots the account owns in the region and the storage
+// of its volumes of each of the types
+func (c *Client) EBSUsage(ctx context.Context, volumeTypes []string) (_ EBSUsage, err error) {
+	ctx, span := tracer.Start(ctx, "ec2.EBSUsage")
+	defer func() { tracing.End(span, err) }()
+
+	usage := EBSUsage{StorageGiB: make(map[string]int64, len(volumeTypes))}
+	slog.Info("ec2: DescribeSnapshots", "owner", "self")
+	snapshots := ec2.NewDescribeSnapshotsPaginator(c.ec2, &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}})
+	for snapshots.HasMorePages() {
+		page, err := snapshots.NextPage(ctx)
+		if err != nil {
+			slog.Info("ec2: DescribeSnapshots failed", "error", err)
+			return EBSUsage{}, err
+		}
+		usage.Snapshots += len(page.Snapshots)
+	}
+
+	slog.Info("ec2: DescribeVolumes", "volumeTypes", volumeTypes)
+	volumes := ec2.NewDescribeVolumesPaginator(c.ec2, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("volume-type"), Values: volumeTypes}},
+	})
+	for volumes.HasMorePages() {
+		page, err := volumes.NextPage(ctx)
+		if err != nil {
+			slog.Info("ec2: DescribeVolumes failed", "error", err)
+			return EBSUsage{}, err
+		}
+		for _, vol := range page.Volumes {
+			usage.StorageGiB[string(vol.VolumeType)] += int64(aws.ToInt32(vol.Size))
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_EBSUsage(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockEC2API{
+		describeSnapshotsFunc: func(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			assert.Equal(t, []string{"self"}, params.OwnerIds)
+			if aws.ToString(params.NextToken) == "" {
+				return &ec2.DescribeSnapshotsOutput{Snapshots: make([]ec2types.Snapshot, 3), NextToken: aws.String("page-2")}, nil
+			}
+			return &ec2.DescribeSnapshotsOutput{Snapshots: make([]ec2types.Snapshot, 2)}, nil
+		},
+		describeVolumesFunc: func(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			require.Len(t, params.Filters, 1)
+			assert.Equal(t, "volume-type", aws.ToString(params.Filters[0].Name))
+			assert.Equal(t, []string{"gp3", "io2"}, params.Filters[0].Values)
+			return &ec2.DescribeVolumesOutput{Volumes: []ec2types.Volume{
+				{Size: aws.Int32(100), VolumeType: ec2types.VolumeTypeGp3},
+				{Size: aws.Int32(20), VolumeType: ec2types.VolumeTypeGp3},
+				{Size: aws.Int32(500), VolumeType: ec2types.VolumeTypeIo2},
+			}}, nil
+		},
+	}
+
+	usage, err := NewEC2ClientWithInterface(mock).EBSUsage(context.Background(), []string{VolumeType, VolumeTypeIO2})
+	require.NoError(t, err)
+	assert.Equal(t, EBSUsage{Snapshots: 5, StorageGiB: map[string]int64{"gp3": 120, "io2": 500}}, usage)
+
+	mock.describeVolumesFunc = func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+		return nil, errors.New("access denied")
+	}
+	_, err = NewEC2ClientWithInterface(mock).EBSUsage(context.Background(), []string{VolumeType, VolumeTypeIO2})
+	require.Error(t, err)
+}
+
+func TestDefaultStorageQuotaTiB(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 50, DefaultStorageQuotaTiB(VolumeType))
+	assert.Equal(t, 20, DefaultStorageQuotaTiB(VolumeTypeIO2))
+}
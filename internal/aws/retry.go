@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// DefaultMaxAttempts is how many times an EC2 call is attempted when it is
+// throttled or fails with a transient error. Runs with high concurrency poll
+// DescribeSnapshots often enough to hit the account's request rate limit.
+const DefaultMaxAttempts = 10
+
+// ThrottleObserver is called with the operation name each time EC2 throttles an
+// attempt of a call. The SDK then backs off and retries the call.
+type ThrottleObserver func(operation string)
+
+type throttleObserverKey struct{}
+
+// WithThrottleObserver returns a context whose EC2 calls report their throttled
+// attempts to observe
+func WithThrottleObserver(ctx context.Context, observe ThrottleObserver) context.Context {
+	return context.WithValue(ctx, throttleObserverKey{}, observe)
+}
+
+// IsThrottle reports whether err is a throttling error such as RequestLimitExceeded
+func IsThrottle(err error) bool {
+	for _, t := range retry.DefaultThrottles {
+		if t.IsErrorThrottle(err) == aws.TrueTernary {
+			return true
+		}
+	}
+	return false
+}
+
+// observeThrottles reports every throttled attempt, inside the SDK's retry loop,
+// to the ThrottleObserver of the call's context
+var observeThrottles = middleware.FinalizeMiddlewareFunc("ObserveThrottles",
+	func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleFinalize(ctx, in)
+		if err != nil && IsThrottle(err) {
+			if observe, ok := ctx.Value(throttleObserverKey{}).(ThrottleObserver); ok {
+				observe(awsmiddleware.GetOperationName(ctx))
+			}
+		}
+		return out, metadata, err
+	})
+
+// addThrottleObserver adds observeThrottles after the retry middleware
+func addThrottleObserver(stack *middleware.Stack) error {
+	return stack.Finalize.Insert(observeThrottles, (&retry.Attempt{}).ID(), middleware.After)
+}
+
+// IsTransient reports whether err is one the SDK retries, such as a 5xx from
+// EC2, a dropped connection or throttling, so the call may succeed if made again.
+// A context that is done is not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, r := range retry.DefaultRetryables {
+		if r.IsErrorRetryable(err) == aws.TrueTernary {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// throttlingHTTPClient answers the first throttled requests with
+// RequestLimitExceeded, then with an empty DescribeSnapshots response
+type throttlingHTTPClient struct {
+	mu        sync.Mutex
+	throttled int
+}
+
+func (c *throttlingHTTPClient) Do(_ *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.throttled > 0 {
+		c.throttled--
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{},
+			Body: io.NopCloser(strings.NewReader(
+				`<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors><RequestID>1</RequestID></Response>`)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body: io.NopCloser(strings.NewReader(
+			`<DescribeSnapshotsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>2</requestId><snapshotSet/></DescribeSnapshotsResponse>`)),
+	}, nil
+}
+
+func TestObserveThrottles(t *testing.T) {
+	t.Parallel()
+
+	httpClient := &throttlingHTTPClient{throttled: 2}
+	api := ec2.New(ec2.Options{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  httpClient,
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = 3
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+		APIOptions: []func(*middleware.Stack) error{addThrottleObserver},
+	})
+
+	var operations []string
+	ctx := WithThrottleObserver(context.Background(), func(operation string) {
+		operations = append(operations, operation)
+	})
+	_, err := api.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{})
+	require.NoError(t, err, "retried until the throttling stopped")
+	assert.Equal(t, []string{"DescribeSnapshots", "DescribeSnapshots"}, operations)
+
+	httpClient.throttled = 1
+	_, err = api.DescribeSnapshots(context.Background(), &ec2.DescribeSnapshotsInput{})
+	require.NoError(t, err, "calls without an observer are retried too")
+}
+
+func TestIsThrottle(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsThrottle(&smithy.GenericAPIError{Code: "RequestLimitExceeded"}))
+	assert.False(t, IsThrottle(&smithy.GenericAPIError{Code: "InvalidSnapshot.NotFound"}))
+	assert.False(t, IsThrottle(context.Canceled))
+}
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsTransient(&smithy.GenericAPIError{Code: "RequestLimitExceeded"}))
+	assert.True(t, IsTransient(&smithy.GenericAPIError{Code: "RequestTimeout"}))
+	assert.True(t, IsTransient(&awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}, Err: errors.New("unavailable")},
+	}))
+	assert.False(t, IsTransient(&smithy.GenericAPIError{Code: "InvalidSnapshot.NotFound"}))
+	assert.False(t, IsTransient(context.Canceled))
+	assert.False(t, IsTransient(fmt.Errorf("create volume: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTransient(nil))
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// subnetsAPI is the internal interface for looking up VPC subnets
+type subnetsAPI interface {
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+}
+
+// SubnetSelector picks subnets by ID or, when ID is empty, by tags. A tag value
+// of "*" matches any value, as in a Karpenter subnetSelectorTerm.
+type SubnetSelector struct {
+	ID   string
+	Tags map[string]string
+}
+
+// NewEC2ClientWithSubnets creates a Client with custom EC2 and subnet API implementations (for testing)
+func NewEC2ClientWithSubnets(api ec2ClientAPI, subnets subnetsAPI) *Client {
+	return &Client{ec2: api, subnets: subnets}
+}
+
+// SubnetZones returns the zones, sorted, of the subnets any of the selectors picks
+func (c *Client) SubnetZones(ctx context.Context, selectors []SubnetSelector) (_ []string, err error) {
+	if c.subnets == nil {
+		return nil, fmt.Errorf("subnet client not configured")
+	}
+
+	ctx, span := tracer.Start(ctx, "ec2.DescribeSubnets")
+	defer func() { tracing.End(span, err) }()
+
+	seen := make(map[string]bool)
+	for _, selector := range selectors {
+		input := &ec2.DescribeSubnetsInput{}
+		if selector.ID != "" {
+			input.Filters = []ec2types.Filter{{Name: aws.String("subnet-id"), Values: []string{selector.ID}}}
+		}
+		for key, value := range selector.Tags {
+			if value == "*" {
+				input.Filters = append(input.Filters, ec2types.Filter{Name: aws.String("tag-key"), Values: []string{key}})
+				continue
+			}
+			input.Filters = append(input.Filters, ec2types.Filter{Name: aws.String("tag:" + key), Values: []string{value}})
+		}
+		if len(input.Filters) == 0 {
+			continue
+		}
+
+		slog.Info("ec2: DescribeSubnets", "id", selector.ID, "tags", selector.Tags)
+		pages := ec2.NewDescribeSubnetsPaginator(c.subnets, input)
+		for pages.HasMorePages() {
+			page, err := pages.NextPage(ctx)
+			if err != nil {
+				slog.Info("ec2: DescribeSubnets failed", "error", err)
+				return nil, err
+			}
+			for _, subnet := range page.Subnets {
+				seen[aws.ToString(subnet.AvailabilityZone)] = true
+			}
+		}
+	}
+
+	zones := make([]string, 0, len(seen))
+	for zone := range seen {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSubnetsAPI implements the subnetsAPI interface for testing, matching
+// subnets on the subnet-id, tag-key and tag: filters
+type mockSubnetsAPI struct {
+	subnets []ec2types.Subnet
+}
+
+func (m *mockSubnetsAPI) DescribeSubnets(_ context.Context, params *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	out := &ec2.DescribeSubnetsOutput{}
+	for _, subnet := range m.subnets {
+		tags := make(map[string]string)
+		for _, tag := range subnet.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		matched := true
+		for _, filter := range params.Filters {
+			name, value := aws.ToString(filter.Name), filter.Values[0]
+			switch {
+			case name == "subnet-id":
+				matched = matched && aws.ToString(subnet.SubnetId) == value
+			case name == "tag-key":
+				_, ok := tags[value]
+				matched = matched && ok
+			default:
+				matched = matched && tags[name[len("tag:"):]] == value
+			}
+		}
+		if matched {
+			out.Subnets = append(out.Subnets, subnet)
+		}
+	}
+	return out, nil
+}
+
+func TestClient_SubnetZones(t *testing.T) {
+	t.Parallel()
+
+	subnet := func(id, zone string, tags map[string]string) ec2types.Subnet {
+		s := ec2types.Subnet{SubnetId: aws.String(id), AvailabilityZone: aws.String(zone)}
+		for k, v := range tags {
+			s.Tags = append(s.Tags, ec2types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		return s
+	}
+	c := NewEC2ClientWithSubnets(nil, &mockSubnetsAPI{subnets: []ec2types.Subnet{
+		subnet("subnet-a", "eu-west-1a", map[string]string{"karpenter.sh/discovery": "prod"}),
+		subnet("subnet-b", "eu-west-1b", map[string]string{"karpenter.sh/discovery": "staging"}),
+		subnet("subnet-c", "eu-west-1c", nil),
+	}})
+
+	cases := []struct {
+		name      string
+		selectors []SubnetSelector
+		want      []string
+	}{
+		{
+			name:      "tags",
+			selectors: []SubnetSelector{{Tags: map[string]string{"karpenter.sh/discovery": "prod"}}},
+			want:      []string{"eu-west-1a"},
+		},
+		{
+			name:      "any_tag_value",
+			selectors: []SubnetSelector{{Tags: map[string]string{"karpenter.sh/discovery": "*"}}},
+			want:      []string{"eu-west-1a", "eu-west-1b"},
+		},
+		{
+			name:      "ids_or_tags",
+			selectors: []SubnetSelector{{ID: "subnet-c"}, {Tags: map[string]string{"karpenter.sh/discovery": "staging"}}},
+			want:      []string{"eu-west-1b", "eu-west-1c"},
+		},
+		{
+			name:      "empty_selector",
+			selectors: []SubnetSelector{{}},
+			want:      []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			zones, err := c.SubnetZones(context.Background(), tc.selectors)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, zones)
+		})
+	}
+}
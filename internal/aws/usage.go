@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+)
+
+// countCalls returns a stack option counting every attempt of a call, inside the
+// SDK's retry loop, so retries and throttled attempts are counted too
+func countCalls(counter *apiusage.Counter) func(*middleware.Stack) error {
+	count := middleware.FinalizeMiddlewareFunc("CountCalls",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			counter.Add(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), err != nil && IsThrottle(err))
+			return out, metadata, err
+		})
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Insert(count, (&retry.Attempt{}).ID(), middleware.After)
+	}
+}
+
+// APIUsage returns the calls the client made to EC2 and CloudWatch so far, by
+// operation. Clients made for tests count nothing.
+func (c *Client) APIUsage() []apiusage.Count {
+	return c.usage.Counts()
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+)
+
+func TestCountCalls(t *testing.T) {
+	t.Parallel()
+
+	counter := apiusage.NewCounter()
+	api := ec2.New(ec2.Options{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  &throttlingHTTPClient{throttled: 2},
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = 3
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+		APIOptions: []func(*middleware.Stack) error{countCalls(counter)},
+	})
+
+	_, err := api.DescribeSnapshots(context.Background(), &ec2.DescribeSnapshotsInput{})
+	require.NoError(t, err)
+	_, err = api.DescribeSnapshots(context.Background(), &ec2.DescribeSnapshotsInput{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []apiusage.Count{
+		{Service: "EC2", Operation: "DescribeSnapshots", Calls: 4, Throttled: 2},
+	}, counter.Counts(), "every attempt is counted")
+}
@@ -0,0 +1,225 @@
+package aws
+
+import (
+	"fmt"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Provisioned IOPS SSD volume types new volumes can be created as, besides VolumeType
+const (
+	VolumeTypeIO1 = string(ec2types.VolumeTypeIo1)
+	VolumeTypeIO2 = string(ec2types.VolumeTypeIo2)
+)
+
+// Volumes of io2 above these only attach with their full size and IOPS to
+// instances built on Nitro that support io2 Block Express
+const (
+	BlockExpressMinSizeGiB = 16384
+	BlockExpressMinIOPS    = 64000
+)
+
+// Baseline performance of gp3 volumes whatever their size, the most throughput
+// they may be given, in MiB/s, and the IOPS each MiB/s of it needs
+const (
+	gp3BaseIOPS       = 3000
+	gp3BaseThroughput = 125
+	gp3MaxThroughput  = 2000
+	gp3IOPSPerMiBps   = 4
+)
+
+// volumeLimits are the size and IOPS EC2 accepts for a volume type, as
+// documented by AWS
+type volumeLimits struct {
+	minSizeGiB, maxSizeGiB int32
+	minIOPS, maxIOPS       int32
+	baseIOPS               int32 // IOPS any size gets, whatever IOPSPerGiB allows
+	iopsPerGiB             int32 // Highest ratio of IOPS to size
+	iopsRequired           bool
+}
+
+var volumeTypeLimits = map[string]volumeLimits{
+	VolumeType:    {minSizeGiB: 1, maxSizeGiB: 65536, minIOPS: 3000, maxIOPS: 80000, baseIOPS: gp3BaseIOPS, iopsPerGiB: 500},
+	VolumeTypeIO1: {minSizeGiB: 4, maxSizeGiB: 16384, minIOPS: 100, maxIOPS: 64000, iopsPerGiB: 50, iopsRequired: true},
+	VolumeTypeIO2: {minSizeGiB: 4, maxSizeGiB: 65536, minIOPS: 100, maxIOPS: 256000, iopsPerGiB: 1000, iopsRequired: true},
+}
+
+// VolumeSpec is the type and performance of the volumes created from snapshots.
+// The zero value creates VolumeType volumes with their baseline performance.
+type VolumeSpec struct {
+	Type       string // VolumeType, VolumeTypeIO1 or VolumeTypeIO2; empty for VolumeType
+	IOPS       int32  // Provisioned IOPS; required for io1 and io2, 0 for the baseline of gp3
+	Throughput int32  // MiB/s of gp3 volumes; 0 for their baseline
+}
+
+// gp2 volumes get 3 IOPS per GiB without bursting
+const gp2IOPSPerGiB = 3
+
+// SourceVolumeSpec returns the spec that recreates the volume with the same type
+// and performance. gp2 volumes become gp3 volumes with at least the IOPS their
+// size gives them. Volumes of other types new volumes cannot be created as, such
+// as st1, give the zero spec, and so do gp3 volumes with baseline performance.
+func SourceVolumeSpec(volume *VolumeInfo) VolumeSpec {
+	if volume.VolumeType == string(ec2types.VolumeTypeGp2) {
+		iops := min(int64(volume.SizeGiB)*gp2IOPSPerGiB, int64(volumeTypeLimits[VolumeType].maxIOPS))
+		if iops <= gp3BaseIOPS {
+			return VolumeSpec{}
+		}
+		return VolumeSpec{IOPS: int32(iops)}
+	}
+	if _, ok := volumeTypeLimits[volume.VolumeType]; !ok {
+		return VolumeSpec{}
+	}
+	spec := VolumeSpec{Type: volume.VolumeType, IOPS: volume.IOPS, Throughput: volume.Throughput}
+	if spec.Type == VolumeType {
+		spec.Type = ""
+		if spec.IOPS == gp3BaseIOPS {
+			spec.IOPS = 0
+		}
+		if spec.Throughput == gp3BaseThroughput {
+			spec.Throughput = 0
+		}
+	}
+	return spec
+}
+
+// VolumeType returns the EBS type the volumes are created as
+func (s VolumeSpec) VolumeType() string {
+	if s.Type == "" {
+		return VolumeType
+	}
+	return s.Type
+}
+
+// String describes the spec for the plan, such as "io2, 16000 IOPS"
+func (s VolumeSpec) String() string {
+	desc := s.VolumeType()
+	if s.IOPS > 0 {
+		desc += fmt.Sprintf(", %d IOPS", s.IOPS)
+	}
+	if s.Throughput > 0 {
+		desc += fmt.Sprintf(", %d MiB/s", s.Throughput)
+	}
+	return desc
+}
+
+// Validate checks the spec and the size of a volume against the limits EC2
+// enforces for its type, so a violation is reported before CreateVolume is
+// called rather than as its error
+func (s VolumeSpec) Validate(sizeGiB int32) error {
+	limits, ok := volumeTypeLimits[s.VolumeType()]
+	if !ok {
+		return fmt.Errorf("volume type %q is not supported; use %s, %s or %s", s.Type, VolumeType, VolumeTypeIO1, VolumeTypeIO2)
+	}
+	if sizeGiB < limits.minSizeGiB || sizeGiB > limits.maxSizeGiB {
+		return fmt.Errorf("%s volumes are %d to %d GiB, not %d GiB", s.VolumeType(), limits.minSizeGiB, limits.maxSizeGiB, sizeGiB)
+	}
+	if err := s.validateThroughput(); err != nil {
+		return err
+	}
+	if s.IOPS == 0 {
+		if limits.iopsRequired {
+			return fmt.Errorf("%s volumes need their IOPS set", s.VolumeType())
+		}
+		return nil
+	}
+	if s.IOPS < limits.minIOPS || s.IOPS > limits.maxIOPS {
+		return fmt.Errorf("%s volumes have %d to %d IOPS, not %d", s.VolumeType(), limits.minIOPS, limits.maxIOPS, s.IOPS)
+	}
+	if s.IOPS > limits.baseIOPS && int64(s.IOPS) > int64(sizeGiB)*int64(limits.iopsPerGiB) {
+		return fmt.Errorf("%s volumes have at most %d IOPS per GiB: %d IOPS need at least %d GiB, not %d GiB",
+			s.VolumeType(), limits.iopsPerGiB, s.IOPS, (s.IOPS+limits.iopsPerGiB-1)/limits.iopsPerGiB, sizeGiB)
+	}
+	return nil
+}
+
+// validateThroughput checks the throughput, which only gp3 volumes are given,
+// against its range and the IOPS of the volume
+func (s VolumeSpec) validateThroughput() error {
+	if s.Throughput == 0 {
+		return nil
+	}
+	if s.VolumeType() != VolumeType {
+		return fmt.Errorf("%s volumes cannot have their throughput set", s.VolumeType())
+	}
+	if s.Throughput < gp3BaseThroughput || s.Throughput > gp3MaxThroughput {
+		return fmt.Errorf("%s volumes have %d to %d MiB/s, not %d", VolumeType, gp3BaseThroughput, gp3MaxThroughput, s.Throughput)
+	}
+	iops := max(s.IOPS, gp3BaseIOPS)
+	if s.Throughput > iops/gp3IOPSPerMiBps {
+		return fmt.Errorf("%s volumes have at most 0.25 MiB/s per IOPS: %d MiB/s need at least %d IOPS, not %d",
+			VolumeType, s.Throughput, s.Throughput*gp3IOPSPerMiBps, iops)
+	}
+	return nil
+}
+
+// BlockExpress reports whether a volume of the size needs io2 Block Express: it
+// is an io2 volume above BlockExpressMinSizeGiB or BlockExpressMinIOPS
+func (s VolumeSpec) BlockExpress(sizeGiB int32) bool {
+	return s.VolumeType() == VolumeTypeIO2 && (sizeGiB > BlockExpressMinSizeGiB || s.IOPS > BlockExpressMinIOPS)
+}
+
+// VolumePerformance is the IOPS a volume delivers and the most throughput, in
+// MiB/s, it can reach
+type VolumePerformance struct {
+	IOPS       int32
+	Throughput int32 // 0 when unknown for the volume's type
+}
+
+// Documented throughput limits, in MiB/s, of the types whose throughput is
+// not provisioned
+const (
+	gp2SmallThroughput = 128 // Up to gp2SmallMaxGiB
+	gp2Throughput      = 250
+	gp2SmallMaxGiB     = 170
+	gp2MinIOPS         = 100
+	gp2MaxIOPS         = 16000
+	io1MaxThroughput   = 1000
+	io2MaxThroughput   = 4000
+	st1MiBpsPerTiB     = 40
+	st1MaxThroughput   = 500
+	sc1MiBpsPerTiB     = 12
+	sc1MaxThroughput   = 250
+	// Provisioned IOPS volumes get 256 KiB of throughput per IOPS
+	ioKiBPerIOPS = 256
+)
+
+// Performance returns the performance of the volume, from the IOPS and
+// throughput EC2 reports and the documented limits of its type
+func (v *VolumeInfo) Performance() VolumePerformance {
+	return volumePerformance(v.VolumeType, v.SizeGiB, v.IOPS, v.Throughput)
+}
+
+// Performance returns the performance of a volume of the spec and size
+func (s VolumeSpec) Performance(sizeGiB int32) VolumePerformance {
+	return volumePerformance(s.VolumeType(), sizeGiB, s.IOPS, s.Throughput)
+}
+
+// volumePerformance returns the performance of a volume of the type and size
+// with the IOPS and throughput it was given, which gp2, st1 and sc1 volumes
+// derive from their size instead
+func volumePerformance(volumeType string, sizeGiB, iops, throughput int32) VolumePerformance {
+	switch volumeType {
+	case VolumeType:
+		return VolumePerformance{IOPS: max(iops, gp3BaseIOPS), Throughput: max(throughput, gp3BaseThroughput)}
+	case string(ec2types.VolumeTypeGp2):
+		perf := VolumePerformance{
+			IOPS:       int32(min(max(int64(sizeGiB)*gp2IOPSPerGiB, gp2MinIOPS), gp2MaxIOPS)),
+			Throughput: gp2Throughput,
+		}
+		if sizeGiB <= gp2SmallMaxGiB {
+			perf.Throughput = gp2SmallThroughput
+		}
+		return perf
+	case VolumeTypeIO1:
+		return VolumePerformance{IOPS: iops, Throughput: min(iops*ioKiBPerIOPS/1024, io1MaxThroughput)}
+	case VolumeTypeIO2:
+		return VolumePerformance{IOPS: iops, Throughput: min(iops*ioKiBPerIOPS/1024, io2MaxThroughput)}
+	case string(ec2types.VolumeTypeSt1):
+		return VolumePerformance{IOPS: iops, Throughput: int32(min(int64(sizeGiB)*st1MiBpsPerTiB/1024, st1MaxThroughput))}
+	case string(ec2types.VolumeTypeSc1):
+		return VolumePerformance{IOPS: iops, Throughput: int32(min(int64(sizeGiB)*sc1MiBpsPerTiB/1024, sc1MaxThroughput))}
+	default:
+		return VolumePerformance{IOPS: iops}
+	}
+}
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeSpec_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		spec    VolumeSpec
+		sizeGiB int32
+		wantErr string
+	}{
+		{name: "gp3 baseline", sizeGiB: 1},
+		{name: "gp3 iops", spec: VolumeSpec{Type: VolumeType, IOPS: 16000}, sizeGiB: 32},
+		{name: "gp3 iops of small volume", spec: VolumeSpec{IOPS: 6000}, sizeGiB: 10, wantErr: "gp3 volumes have at most 500 IOPS per GiB: 6000 IOPS need at least 12 GiB, not 10 GiB"},
+		{name: "gp3 largest", spec: VolumeSpec{IOPS: 80000, Throughput: 2000}, sizeGiB: 65536},
+		{name: "gp3 too large", sizeGiB: 70000, wantErr: "gp3 volumes are 1 to 65536 GiB, not 70000 GiB"},
+		{name: "gp3 too many iops", spec: VolumeSpec{IOPS: 90000}, sizeGiB: 1000, wantErr: "gp3 volumes have 3000 to 80000 IOPS, not 90000"},
+		{name: "io2", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 16000}, sizeGiB: 100},
+		{name: "io2 without iops", spec: VolumeSpec{Type: VolumeTypeIO2}, sizeGiB: 100, wantErr: "io2 volumes need their IOPS set"},
+		{name: "io2 ratio", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 16000}, sizeGiB: 10, wantErr: "io2 volumes have at most 1000 IOPS per GiB: 16000 IOPS need at least 16 GiB, not 10 GiB"},
+		{name: "io2 block express", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 256000}, sizeGiB: 65536},
+		{name: "io2 too many iops", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 300000}, sizeGiB: 1000, wantErr: "io2 volumes have 100 to 256000 IOPS, not 300000"},
+		{name: "io2 too small", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 100}, sizeGiB: 2, wantErr: "io2 volumes are 4 to 65536 GiB, not 2 GiB"},
+		{name: "io1 ratio", spec: VolumeSpec{Type: VolumeTypeIO1, IOPS: 6000}, sizeGiB: 100, wantErr: "io1 volumes have at most 50 IOPS per GiB: 6000 IOPS need at least 120 GiB, not 100 GiB"},
+		{name: "gp3 throughput", spec: VolumeSpec{IOPS: 4000, Throughput: 1000}, sizeGiB: 100},
+		{name: "gp3 throughput of baseline iops", spec: VolumeSpec{Throughput: 1000}, sizeGiB: 100, wantErr: "gp3 volumes have at most 0.25 MiB/s per IOPS: 1000 MiB/s need at least 4000 IOPS, not 3000"},
+		{name: "gp3 too much throughput", spec: VolumeSpec{IOPS: 16000, Throughput: 2500}, sizeGiB: 100, wantErr: "gp3 volumes have 125 to 2000 MiB/s, not 2500"},
+		{name: "io2 throughput", spec: VolumeSpec{Type: VolumeTypeIO2, IOPS: 1000, Throughput: 500}, sizeGiB: 100, wantErr: "io2 volumes cannot have their throughput set"},
+		{name: "unsupported type", spec: VolumeSpec{Type: "st1"}, sizeGiB: 500, wantErr: `volume type "st1" is not supported; use gp3, io1 or io2`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.spec.Validate(tc.sizeGiB)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVolumeSpec_BlockExpress(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, VolumeSpec{Type: VolumeTypeIO2, IOPS: 64000}.BlockExpress(16384))
+	assert.True(t, VolumeSpec{Type: VolumeTypeIO2, IOPS: 64001}.BlockExpress(100))
+	assert.True(t, VolumeSpec{Type: VolumeTypeIO2, IOPS: 1000}.BlockExpress(20000))
+	assert.False(t, VolumeSpec{Type: VolumeTypeIO1, IOPS: 64000}.BlockExpress(16384))
+}
+
+func TestVolumeSpec_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "gp3", VolumeSpec{}.String())
+	assert.Equal(t, "io2, 16000 IOPS", VolumeSpec{Type: VolumeTypeIO2, IOPS: 16000}.String())
+	assert.Equal(t, "gp3, 6000 IOPS, 500 MiB/s", VolumeSpec{IOPS: 6000, Throughput: 500}.String())
+}
+
+func TestSourceVolumeSpec(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		volume VolumeInfo
+		want   VolumeSpec
+	}{
+		{name: "io2", volume: VolumeInfo{VolumeType: "io2", IOPS: 16000}, want: VolumeSpec{Type: VolumeTypeIO2, IOPS: 16000}},
+		{name: "gp3 baseline", volume: VolumeInfo{VolumeType: "gp3", IOPS: 3000, Throughput: 125}},
+		{name: "gp3 provisioned", volume: VolumeInfo{VolumeType: "gp3", IOPS: 6000, Throughput: 250}, want: VolumeSpec{IOPS: 6000, Throughput: 250}},
+		{name: "gp2 becomes gp3", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 100, IOPS: 300}},
+		{name: "gp2 keeps its iops", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 5120, IOPS: 15360}, want: VolumeSpec{IOPS: 15360}},
+		{name: "gp2 iops capped", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 65536, IOPS: 16000}, want: VolumeSpec{IOPS: 80000}},
+		{name: "st1 becomes gp3", volume: VolumeInfo{VolumeType: "st1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, SourceVolumeSpec(&tc.volume))
+		})
+	}
+}
+
+func TestVolumePerformance(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		volume VolumeInfo
+		want   VolumePerformance
+	}{
+		{name: "gp3 baseline", volume: VolumeInfo{VolumeType: "gp3", SizeGiB: 100, IOPS: 3000, Throughput: 125}, want: VolumePerformance{IOPS: 3000, Throughput: 125}},
+		{name: "gp3 provisioned", volume: VolumeInfo{VolumeType: "gp3", SizeGiB: 100, IOPS: 16000, Throughput: 1000}, want: VolumePerformance{IOPS: 16000, Throughput: 1000}},
+		{name: "small gp2", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 20, IOPS: 100}, want: VolumePerformance{IOPS: 100, Throughput: 128}},
+		{name: "gp2", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 1000, IOPS: 3000}, want: VolumePerformance{IOPS: 3000, Throughput: 250}},
+		{name: "large gp2", volume: VolumeInfo{VolumeType: "gp2", SizeGiB: 10000, IOPS: 16000}, want: VolumePerformance{IOPS: 16000, Throughput: 250}},
+		{name: "io1", volume: VolumeInfo{VolumeType: "io1", SizeGiB: 500, IOPS: 20000}, want: VolumePerformance{IOPS: 20000, Throughput: 1000}},
+		{name: "io2", volume: VolumeInfo{VolumeType: "io2", SizeGiB: 500, IOPS: 10000}, want: VolumePerformance{IOPS: 10000, Throughput: 2500}},
+		{name: "io2 block express", volume: VolumeInfo{VolumeType: "io2", SizeGiB: 20000, IOPS: 100000}, want: VolumePerformance{IOPS: 100000, Throughput: 4000}},
+		{name: "st1", volume: VolumeInfo{VolumeType: "st1", SizeGiB: 2048}, want: VolumePerformance{Throughput: 80}},
+		{name: "standard", volume: VolumeInfo{VolumeType: "standard", SizeGiB: 100}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.volume.Performance())
+		})
+	}
+
+	assert.Equal(t, VolumePerformance{IOPS: 3000, Throughput: 125}, VolumeSpec{}.Performance(100))
+	assert.Equal(t, VolumePerformance{IOPS: 10000, Throughput: 2500}, VolumeSpec{Type: VolumeTypeIO2, IOPS: 10000}.Performance(100))
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// zonesAPI is the internal interface for looking up the Availability Zones of the region
+type zonesAPI interface {
+	DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error)
+}
+
+// zoneIDRegex matches Availability Zone IDs such as use1-az2, which name the
+// same physical zone in every account, unlike zone names
+var zoneIDRegex = regexp.MustCompile(`^[a-z]{2,5}\d+-az\d+$`)
+
+// IsZoneID reports whether zone is an Availability Zone ID rather than a name
+func IsZoneID(zone string) bool {
+	return zoneIDRegex.MatchString(zone)
+}
+
+// NewEC2ClientWithZones creates a Client with custom EC2 and zone API implementations (for testing)
+func NewEC2ClientWithZones(api ec2ClientAPI, zones zonesAPI) *Client {
+	return &Client{ec2: api, zones: zones}
+}
+
+// ZoneIDs returns the ID of each Availability Zone of the region, by name, as
+// seen from the account the client makes its calls in
+func (c *Client) ZoneIDs(ctx context.Context) (_ map[string]string, err error) {
+	if c.zones == nil {
+		return nil, fmt.Errorf("zone client not configured")
+	}
+
+	ctx, span := tracer.Start(ctx, "ec2.DescribeAvailabilityZones")
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("ec2: DescribeAvailabilityZones")
+	out, err := c.zones.DescribeAvailabilityZones(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		slog.Info("ec2: DescribeAvailabilityZones failed", "error", err)
+		return nil, err
+	}
+	ids := make(map[string]string, len(out.AvailabilityZones))
+	for _, zone := range out.AvailabilityZones {
+		ids[aws.ToString(zone.ZoneName)] = aws.ToString(zone.ZoneId)
+	}
+	return ids, nil
+}
+
+// ZoneName returns the name of zone in the account ids were looked up in: zone
+// itself when it is a name, or the name of the zone with that ID
+func ZoneName(ids map[string]string, zone string) (string, error) {
+	if !IsZoneID(zone) {
+		return zone, nil
+	}
+	for name, id := range ids {
+		if id == zone {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("zone ID '%s' is not in the region", zone)
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockZonesAPI implements the zonesAPI interface for testing
+type mockZonesAPI struct {
+	zones map[string]string // Name -> ID
+}
+
+func (m *mockZonesAPI) DescribeAvailabilityZones(context.Context, *ec2.DescribeAvailabilityZonesInput, ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	out := &ec2.DescribeAvailabilityZonesOutput{}
+	for name, id := range m.zones {
+		out.AvailabilityZones = append(out.AvailabilityZones, ec2types.AvailabilityZone{ZoneName: aws.String(name), ZoneId: aws.String(id)})
+	}
+	return out, nil
+}
+
+func TestClient_ZoneIDs(t *testing.T) {
+	t.Parallel()
+
+	zones := map[string]string{"us-east-1a": "use1-az4", "us-east-1b": "use1-az6"}
+	ids, err := NewEC2ClientWithZones(&mockEC2API{}, &mockZonesAPI{zones: zones}).ZoneIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, zones, ids)
+
+	_, err = NewEC2ClientWithInterface(&mockEC2API{}).ZoneIDs(context.Background())
+	assert.ErrorContains(t, err, "zone client not configured")
+}
+
+func TestZoneName(t *testing.T) {
+	t.Parallel()
+
+	ids := map[string]string{"us-east-1a": "use1-az4", "us-east-1b": "use1-az6"}
+	cases := []struct {
+		zone    string
+		want    string
+		wantErr string
+	}{
+		{zone: "us-east-1a", want: "us-east-1a"},
+		{zone: "us-east-1z", want: "us-east-1z"}, // Names are passed through
+		{zone: "use1-az6", want: "us-east-1b"},
+		{zone: "use1-az1", wantErr: "zone ID 'use1-az1' is not in the region"},
+		{zone: "apse2-az3", wantErr: "zone ID 'apse2-az3' is not in the region"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.zone, func(t *testing.T) {
+			t.Parallel()
+
+			name, err := ZoneName(ids, tc.zone)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, name)
+		})
+	}
+}
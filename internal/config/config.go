@@ -4,28 +4,240 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // NamespaceConfig represents a namespace with optional PVC list
 type NamespaceConfig struct {
-	Name string   `yaml:"name"`
-	PVCs []string `yaml:"pvcs,omitempty"`
+	Name        string   `yaml:"name"`
+	PVCs        []string `yaml:"pvcs,omitempty"`        // Names, glob patterns (data-*) or regexes between slashes (/data-\d+/)
+	ExcludePVCs []string `yaml:"excludePVCs,omitempty"` // Names or glob patterns skipped when discovering all PVCs
+
+	StorageClass   string            `yaml:"storageClass,omitempty"`   // Overrides the global storageClass for this namespace
+	StorageClasses map[string]string `yaml:"storageClasses,omitempty"` // PVC name -> storage class, overriding both
+
+	Priority   string            `yaml:"priority,omitempty"`   // high, normal (default) or low for the PVCs of this namespace
+	Priorities map[string]string `yaml:"priorities,omitempty"` // PVC name -> priority, overriding the namespace's
+
+	Window string `yaml:"window,omitempty"` // Daily UTC time its PVCs may be migrated in, e.g. 02:00-04:00
+}
+
+// WindowBounds parses the namespace's window into its start and end after
+// midnight UTC. The end is before the start for windows that span midnight.
+func (n NamespaceConfig) WindowBounds() (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(n.Window, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("window '%s' is invalid; must be like '02:00-04:00'", n.Window)
+	}
+	if start, err = parseClock(from); err == nil {
+		end, err = parseClock(to)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("window '%s' is invalid: %w", n.Window, err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("window '%s' is empty", n.Window)
+	}
+	return start, end, nil
+}
+
+// parseClock parses a 24-hour HH:MM time into the time after midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a HH:MM time", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Excludes reports whether a discovered PVC matches one of the exclude patterns
+func (n NamespaceConfig) Excludes(pvcName string) bool {
+	for _, pattern := range n.ExcludePVCs {
+		// Patterns are checked by Validate, so a match error cannot happen here
+		if ok, _ := path.Match(pattern, pvcName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPVCPatterns reports whether any pvcs entry is a pattern that must be
+// expanded against the PVCs in the namespace
+func (n NamespaceConfig) HasPVCPatterns() bool {
+	for _, entry := range n.PVCs {
+		if isPVCPattern(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandPVCs resolves the pvcs entries against the PVCs listed in the namespace.
+// Literal names are kept as given; patterns add every listed PVC they match.
+// Config order is preserved and duplicates are dropped.
+func (n NamespaceConfig) ExpandPVCs(listed []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	for _, entry := range n.PVCs {
+		if !isPVCPattern(entry) {
+			add(entry)
+			continue
+		}
+		// Patterns are checked by Validate, so a compile error cannot happen here
+		match, _ := pvcMatcher(entry)
+		for _, name := range listed {
+			if match(name) {
+				add(name)
+			}
+		}
+	}
+	return result
+}
+
+// isPVCPattern reports whether a pvcs entry is a regex (/.../) or glob rather
+// than a literal name. PVC names never contain these characters.
+func isPVCPattern(entry string) bool {
+	return isPVCRegex(entry) || strings.ContainsAny(entry, "*?[")
+}
+
+func isPVCRegex(entry string) bool {
+	return len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/")
+}
+
+// pvcMatcher compiles a pvcs pattern. Regexes must match the whole name.
+func pvcMatcher(pattern string) (func(string) bool, error) {
+	if isPVCRegex(pattern) {
+		re, err := regexp.Compile("^(?:" + pattern[1:len(pattern)-1] + ")$")
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+// NotificationConfig is a webhook that receives migration notifications
+type NotificationConfig struct {
+	URL    string   `yaml:"url"`
+	Format string   `yaml:"format,omitempty"` // slack or generic; defaults to slack for hooks.slack.com URLs
+	Events []string `yaml:"events,omitempty"` // start, failure, summary; defaults to all
+}
+
+// EventsConfig selects where migration lifecycle events are published
+type EventsConfig struct {
+	SNSTopicARN  string `yaml:"snsTopicArn,omitempty"`
+	EventBusName string `yaml:"eventBusName,omitempty"`
 }
 
 // Config represents the YAML configuration file structure
 type Config struct {
-	KubeContext      string            `yaml:"kubeContext,omitempty"`
-	Namespaces       []NamespaceConfig `yaml:"namespaces"`
-	TargetZone       string            `yaml:"targetZone"`
-	StorageClass     string            `yaml:"storageClass"`
-	MaxConcurrency   int               `yaml:"maxConcurrency"`
-	DryRun           bool              `yaml:"dryRun"`
-	SkipArgoCD       bool              `yaml:"skipArgoCD"`
-	ArgoCDNamespaces []string          `yaml:"argoCDNamespaces"`
+	KubeContext          string               `yaml:"kubeContext,omitempty"`
+	ProtectedContexts    []string             `yaml:"protectedContexts,omitempty"` // Glob patterns (e.g. *prod*) of contexts whose name must be typed to run
+	AllowedContexts      []string             `yaml:"allowedContexts,omitempty"`   // Glob patterns of the only contexts the tool runs against
+	DeniedContexts       []string             `yaml:"deniedContexts,omitempty"`    // Glob patterns of contexts the tool refuses to run against
+	Namespaces           []NamespaceConfig    `yaml:"namespaces"`
+	AllNamespaces        bool                 `yaml:"allNamespaces,omitempty"`     // Add every namespace with EBS-backed PVCs
+	NamespaceSelector    string               `yaml:"namespaceSelector,omitempty"` // Add namespaces matching this label query (e.g. team=payments)
+	TargetZone           string               `yaml:"targetZone"`                  // Or auto: the least-loaded healthy zone for each namespace
+	TargetZones          []string             `yaml:"targetZones,omitempty"`       // Spread PVCs across these zones instead; targetZone is then ignored
+	SourceZone           string               `yaml:"sourceZone,omitempty"`        // Only migrate PVCs whose volumes are in this zone
+	CordonSourceNodes    bool                 `yaml:"cordonSourceNodes,omitempty"` // Cordon the nodes of sourceZone so scaled-up pods avoid them
+	KeepCordoned         bool                 `yaml:"keepCordoned,omitempty"`      // Leave those nodes cordoned after the run
+	IncludeCoMounted     bool                 `yaml:"includeCoMounted,omitempty"`  // Add PVCs that pods mount together with the selected ones
+	AllowEmptyZone       bool                 `yaml:"allowEmptyZone,omitempty"`    // Only warn when no node in the target zone could run the moved pods
+	CheckProvisioning    bool                 `yaml:"checkProvisioning,omitempty"` // Accept a target zone without nodes when Karpenter or a node group can launch some
+	StorageClass         string               `yaml:"storageClass"`
+	MaxConcurrency       int                  `yaml:"maxConcurrency"`
+	Scheduling           string               `yaml:"scheduling,omitempty"`      // Order PVCs start in: fifo (default) or round-robin across namespaces
+	MaxPerNamespace      int                  `yaml:"maxPerNamespace,omitempty"` // Most PVCs of one namespace in progress at once; 0 for no cap
+	Execute              bool                 `yaml:"execute,omitempty"`         // Make changes without --execute, for automation
+	Watch                time.Duration        `yaml:"watch,omitempty"`           // Discover and migrate again this long after each run until nothing is left
+	BatchSize            int                  `yaml:"batchSize,omitempty"`       // Plan and migrate at most this many PVCs at a time; 0 for all at once
+	StateFile            string               `yaml:"stateFile,omitempty"`       // Where a batched run records each PVC's result, and resumes from
+	DryRun               bool                 `yaml:"dryRun"`
+	SkipArgoCD           bool                 `yaml:"skipArgoCD"`
+	ArgoCDNamespaces     []string             `yaml:"argoCDNamespaces"`
+	ArgoCDStrategy       string               `yaml:"argoCDStrategy,omitempty"`       // How auto-sync is turned off: policy (default), sync-window or annotation
+	ArgoCDAppSets        string               `yaml:"argoCDAppSets,omitempty"`        // Owning ApplicationSets that would revert it: warn (default) or pause them
+	ArgoCDServer         string               `yaml:"argoCDServer,omitempty"`         // argocd-server to turn auto-sync off through, with the token in ARGOCD_AUTH_TOKEN
+	ArgoCDInsecure       bool                 `yaml:"argoCDInsecure,omitempty"`       // Skip verifying the certificate of argoCDServer
+	SkipFlux             bool                 `yaml:"skipFlux,omitempty"`             // Leave Flux Kustomizations and HelmReleases unsuspended
+	FluxNamespaces       []string             `yaml:"fluxNamespaces,omitempty"`       // Namespaces searched for Flux objects; defaults to flux-system
+	SkipKEDA             bool                 `yaml:"skipKEDA,omitempty"`             // Leave KEDA ScaledObjects of the scaled workloads unpaused
+	SkipRollouts         bool                 `yaml:"skipRollouts,omitempty"`         // Leave Argo Rollouts out of the workloads scaled down
+	Jobs                 string               `yaml:"jobs,omitempty"`                 // Jobs mounting PVCs to migrate: wait (default) for them to finish, or delete them
+	JobTimeout           time.Duration        `yaml:"jobTimeout,omitempty"`           // How long to wait for those Jobs; defaults to 30m
+	WarmupJobs           bool                 `yaml:"warmupJobs,omitempty"`           // Create read jobs to hydrate new volumes after the run
+	WarmupImage          string               `yaml:"warmupImage,omitempty"`          // Image used by warm-up jobs (needs sh and find) and mount checks
+	LabelNamespaces      bool                 `yaml:"labelNamespaces,omitempty"`      // Label namespaces whose PVCs are all in their target zone after the run
+	AffinityPatches      string               `yaml:"affinityPatches,omitempty"`      // Write patches pinning the workloads of migrated PVCs to their zone to this directory
+	ApplyAffinity        bool                 `yaml:"applyAffinity,omitempty"`        // Pin the workloads of migrated PVCs to their zone in the cluster
+	HelmValues           string               `yaml:"helmValues,omitempty"`           // Write the values changes Helm releases of migrated PVCs need to this directory
+	SimulateScheduling   bool                 `yaml:"simulateScheduling,omitempty"`   // Dry-run a pod of each workload in its target zone and report whether it fits
+	CheckPermissions     bool                 `yaml:"checkPermissions,omitempty"`     // Dry-run the EC2 calls of a migration and fail the plan when any is denied
+	CheckQuotas          bool                 `yaml:"checkQuotas,omitempty"`          // Fail the plan when the run would exceed the account's EBS snapshot or storage quota
+	SnapshotQuota        int                  `yaml:"snapshotQuota,omitempty"`        // EBS snapshots the account may own in the region; defaults to the AWS default of 100000
+	StorageQuotasTiB     map[string]int       `yaml:"storageQuotasTiB,omitempty"`     // TiB of volumes of each type the account may have in the region; defaults to the AWS defaults
+	FastSnapshotRestore  bool                 `yaml:"fastSnapshotRestore,omitempty"`  // Enable fast snapshot restore in the target zone while each new volume is created
+	Locale               string               `yaml:"locale,omitempty"`               // Language of user-facing messages (en, es); defaults to $LANG
+	Notifications        []NotificationConfig `yaml:"notifications,omitempty"`        // Webhooks notified on start, PVC failure and summary
+	Events               EventsConfig         `yaml:"events,omitempty"`               // SNS topic / EventBridge bus receiving lifecycle events
+	JournalNamespace     string               `yaml:"journalNamespace,omitempty"`     // Keep a record of each run in a ConfigMap in this namespace
+	BackupDir            string               `yaml:"backupDir,omitempty"`            // Save each PVC and PV under <dir>/<migration ID>/ before deleting them; empty disables
+	JournalBackups       bool                 `yaml:"journalBackups,omitempty"`       // Also keep those manifests in the journal ConfigMap
+	OutputManifests      string               `yaml:"outputManifests,omitempty"`      // Write the new PVs and PVCs here for a GitOps repo instead of applying them
+	StagedSnapshotMaxAge time.Duration        `yaml:"stagedSnapshotMaxAge,omitempty"` // Start from a staged snapshot younger than this (e.g. 24h); 0 disables
+	MaxSnapshotStaleness time.Duration        `yaml:"maxSnapshotStaleness,omitempty"` // Start from a staged snapshot if the volume was last written at most this long after it
+	CheckWriteActivity   bool                 `yaml:"checkWriteActivity,omitempty"`   // Find the last write from CloudWatch VolumeWriteOps
+	AWSRoleARN           string               `yaml:"awsRoleArn,omitempty"`           // IAM role the EC2 client assumes through STS
+	AWSExternalID        string               `yaml:"awsExternalId,omitempty"`        // External ID required by the role's trust policy
+	AWSSessionName       string               `yaml:"awsSessionName,omitempty"`       // Session name of the assumed role; defaults to pvc-migrator
+	AWSMaxAttempts       int                  `yaml:"awsMaxAttempts,omitempty"`       // Attempts of each throttled or failed EC2 call; defaults to 10
+	BackupVault          string               `yaml:"backupVault,omitempty"`          // Also back each source volume up to this AWS Backup vault once it is snapshotted
+	BackupRoleARN        string               `yaml:"backupRoleArn,omitempty"`        // IAM role AWS Backup assumes to create the recovery points; required with backupVault
+	BackupRetentionDays  int64                `yaml:"backupRetentionDays,omitempty"`  // Delete the recovery points after this many days; 0 keeps the vault's default
+	KMSKeyID             string               `yaml:"kmsKeyId,omitempty"`             // Encrypt new volumes with this KMS key (ID, ARN or alias)
+	VolumeType           string               `yaml:"volumeType,omitempty"`           // Type of the new volumes: gp3, io1 or io2; defaults to that of the old volume
+	IOPS                 int32                `yaml:"iops,omitempty"`                 // Provisioned IOPS of the new volumes; required with volumeType io1 or io2
+	Throughput           int32                `yaml:"throughput,omitempty"`           // MiB/s of new gp3 volumes
+	TagAnnotationPrefix  string               `yaml:"tagAnnotationPrefix,omitempty"`  // PVC annotations starting with this become snapshot and volume tags
+	Tags                 map[string]string    `yaml:"tags,omitempty"`                 // Tags of every snapshot and volume; values may use {{.Namespace}}, {{.PVCName}} and {{.RunID}}
+	CSIDriver            string               `yaml:"csiDriver,omitempty"`            // CSI driver of the PVs, besides ebs.csi.aws.com; new PVs use it
+	VolumeHandlePattern  string               `yaml:"volumeHandlePattern,omitempty"`  // Regex finding the volume ID in the csiDriver's volume handles; its first group if any
+	MigrationID          string               `yaml:"migrationId,omitempty"`          // Adopt the snapshots and volumes a crashed run with this ID created
+	StepMaxAttempts      int                  `yaml:"stepMaxAttempts,omitempty"`      // Attempts of a step that fails with a transient error; defaults to 3
+	StepRetryBackoff     time.Duration        `yaml:"stepRetryBackoff,omitempty"`     // Wait before a step's first retry, doubling after each; defaults to 2s
+	StepRetryMaxBackoff  time.Duration        `yaml:"stepRetryMaxBackoff,omitempty"`  // Cap of the wait between a step's retries; defaults to 30s
+	BindTimeout          time.Duration        `yaml:"bindTimeout,omitempty"`          // How long a new PVC may take to be Bound before it fails; defaults to 2m
+	VerifyMount          bool                 `yaml:"verifyMount,omitempty"`          // Check each new PVC mounts read-write in a pod in the target zone
+	VerifyChecksum       bool                 `yaml:"verifyChecksum,omitempty"`       // Fail PVCs whose new volume's files do not hash like the source volume's
+	ChecksumTimeout      time.Duration        `yaml:"checksumTimeout,omitempty"`      // How long the checksum of one volume may take; defaults to 1h
+	FreezeFilesystem     bool                 `yaml:"freezeFilesystem,omitempty"`     // Freeze the filesystem of volumes still mounted while their snapshot is requested
+	FreezeCommand        string               `yaml:"freezeCommand,omitempty"`        // Run in the mounting container to freeze, with the mount path as $1; defaults to fsfreeze -f
+	ThawCommand          string               `yaml:"thawCommand,omitempty"`          // Run in the mounting container to thaw, with the mount path as $1; defaults to fsfreeze -u
 }
 
 // DefaultConfig returns a config with default values
@@ -39,6 +251,7 @@ func DefaultConfig() *Config {
 		DryRun:           false,
 		SkipArgoCD:       false,
 		ArgoCDNamespaces: []string{"argocd", "argo-cd", "gitops"},
+		BackupDir:        "backups",
 	}
 }
 
@@ -58,25 +271,107 @@ func LoadFromFile(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// kmsKeyRegex matches KMS key IDs, multi-Region key IDs, aliases and their ARNs
+var kmsKeyRegex = regexp.MustCompile(`^(arn:aws[a-z-]*:kms:[a-z0-9-]+:\d{12}:)?(key/)?([0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}|mrk-[0-9a-f]{32})$|^(arn:aws[a-z-]*:kms:[a-z0-9-]+:\d{12}:)?alias/[A-Za-z0-9/_-]+$`)
+
+// csiDriverRegex matches CSI driver names, DNS names of at most 63 characters
+var csiDriverRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,61}[a-z0-9])?$`)
+
+// roleARNRegex matches IAM role ARNs in any partition, with or without a path
+var roleARNRegex = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/.+$`)
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if len(c.Namespaces) == 0 {
+	if len(c.Namespaces) == 0 && !c.DiscoversNamespaces() {
 		return fmt.Errorf("at least one namespace is required")
 	}
+	if _, err := labels.Parse(c.NamespaceSelector); err != nil {
+		return fmt.Errorf("namespaceSelector '%s' is invalid: %w", c.NamespaceSelector, err)
+	}
 	for _, ns := range c.Namespaces {
 		if ns.Name == "" {
 			return fmt.Errorf("namespace name cannot be empty")
 		}
+		for pvc, class := range ns.StorageClasses {
+			if class == "" {
+				return fmt.Errorf("namespace '%s': storage class of pvc '%s' cannot be empty", ns.Name, pvc)
+			}
+		}
+		if !validPriority(ns.Priority) {
+			return fmt.Errorf("namespace '%s': priority '%s' is invalid; must be 'high', 'normal' or 'low'", ns.Name, ns.Priority)
+		}
+		for pvc, priority := range ns.Priorities {
+			if !validPriority(priority) {
+				return fmt.Errorf("namespace '%s': priority '%s' of pvc '%s' is invalid; must be 'high', 'normal' or 'low'", ns.Name, priority, pvc)
+			}
+		}
+		if ns.Window != "" {
+			if _, _, err := ns.WindowBounds(); err != nil {
+				return fmt.Errorf("namespace '%s': %w", ns.Name, err)
+			}
+		}
+		if len(ns.PVCs) > 0 && len(ns.ExcludePVCs) > 0 {
+			return fmt.Errorf("namespace '%s': excludePVCs only applies when pvcs is empty", ns.Name)
+		}
+		for _, entry := range ns.PVCs {
+			if !isPVCPattern(entry) {
+				continue
+			}
+			if _, err := pvcMatcher(entry); err != nil {
+				return fmt.Errorf("namespace '%s': pvc pattern '%s' is invalid: %w", ns.Name, entry, err)
+			}
+		}
+		for _, pattern := range ns.ExcludePVCs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("namespace '%s': exclude pattern '%s' is invalid: %w", ns.Name, pattern, err)
+			}
+		}
+	}
+	// Validate zone formats (e.g., us-east-1a, or the zone ID use1-az2)
+	// This prevents basic injection and ensures they look like an AWS AZ.
+	// A full validation against the AWS API happens later in the client,
+	// which also resolves zone IDs to the names of the account.
+	azRegex := regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d[a-z]$|^[a-z]{2,5}\d+-az\d+$`)
+	if len(c.TargetZones) > 0 {
+		if len(c.TargetZones) < 2 {
+			return fmt.Errorf("targetZones needs at least two zones; use targetZone for one")
+		}
+		seen := make(map[string]bool)
+		for _, zone := range c.TargetZones {
+			if !azRegex.MatchString(zone) {
+				return fmt.Errorf("targetZones entry '%s' is invalid; must match format like 'us-east-1a' or 'use1-az2'", zone)
+			}
+			if seen[zone] {
+				return fmt.Errorf("targetZones lists '%s' twice", zone)
+			}
+			seen[zone] = true
+		}
+	} else {
+		if c.TargetZone == "" {
+			return fmt.Errorf("targetZone is required")
+		}
+		// auto picks the least-loaded zone for each namespace when planning
+		if c.TargetZone != "auto" && !azRegex.MatchString(c.TargetZone) {
+			return fmt.Errorf("targetZone '%s' is invalid; must match format like 'us-east-1a' or 'use1-az2', or be 'auto'", c.TargetZone)
+		}
+	}
+	if c.SourceZone != "" {
+		if !azRegex.MatchString(c.SourceZone) {
+			return fmt.Errorf("sourceZone '%s' is invalid; must match format like 'us-east-1a' or 'use1-az2'", c.SourceZone)
+		}
+		if c.SourceZone == c.TargetZone {
+			return fmt.Errorf("sourceZone and targetZone cannot both be '%s'", c.TargetZone)
+		}
+		if slices.Contains(c.TargetZones, c.SourceZone) {
+			return fmt.Errorf("sourceZone '%s' cannot also be in targetZones", c.SourceZone)
+		}
 	}
-	if c.TargetZone == "" {
-		return fmt.Errorf("targetZone is required")
+
+	if c.CordonSourceNodes && c.SourceZone == "" {
+		return fmt.Errorf("cordonSourceNodes requires sourceZone")
 	}
-	// Validate TargetZone format (e.g., us-east-1a)
-	// This prevents basic injection and ensures it looks like an AWS AZ.
-	// A full validation against the AWS API happens later in the client.
-	azRegex := regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d[a-z]$`)
-	if !azRegex.MatchString(c.TargetZone) {
-		return fmt.Errorf("targetZone '%s' is invalid; must match format like 'us-east-1a'", c.TargetZone)
+	if c.KeepCordoned && !c.CordonSourceNodes {
+		return fmt.Errorf("keepCordoned requires cordonSourceNodes")
 	}
 
 	if c.StorageClass == "" {
@@ -85,9 +380,247 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrency < 1 {
 		return fmt.Errorf("maxConcurrency must be at least 1")
 	}
+	switch c.Scheduling {
+	case "", "fifo", "round-robin":
+	default:
+		return fmt.Errorf("scheduling '%s' is invalid; must be 'fifo' or 'round-robin'", c.Scheduling)
+	}
+	if c.MaxPerNamespace < 0 {
+		return fmt.Errorf("maxPerNamespace cannot be negative")
+	}
+	switch c.ArgoCDStrategy {
+	case "", "policy", "sync-window", "annotation":
+	default:
+		return fmt.Errorf("argoCDStrategy '%s' is invalid; must be 'policy', 'sync-window' or 'annotation'", c.ArgoCDStrategy)
+	}
+	switch c.ArgoCDAppSets {
+	case "", "warn", "pause":
+	default:
+		return fmt.Errorf("argoCDAppSets '%s' is invalid; must be 'warn' or 'pause'", c.ArgoCDAppSets)
+	}
+	if c.ArgoCDServer != "" && c.ArgoCDAppSets == "pause" {
+		return fmt.Errorf("argoCDAppSets 'pause' cannot be used with argoCDServer")
+	}
+	switch c.Jobs {
+	case "", "wait", "delete":
+	default:
+		return fmt.Errorf("jobs '%s' is invalid; must be 'wait' or 'delete'", c.Jobs)
+	}
+	if c.JobTimeout < 0 {
+		return fmt.Errorf("jobTimeout cannot be negative")
+	}
+	if c.AWSMaxAttempts < 0 {
+		return fmt.Errorf("awsMaxAttempts cannot be negative")
+	}
+	if c.StepMaxAttempts < 0 {
+		return fmt.Errorf("stepMaxAttempts cannot be negative")
+	}
+	if c.StepRetryBackoff < 0 || c.StepRetryMaxBackoff < 0 {
+		return fmt.Errorf("stepRetryBackoff and stepRetryMaxBackoff cannot be negative")
+	}
+	if c.StepRetryMaxBackoff > 0 && c.StepRetryMaxBackoff < c.StepRetryBackoff {
+		return fmt.Errorf("stepRetryMaxBackoff %s is shorter than stepRetryBackoff %s", c.StepRetryMaxBackoff, c.StepRetryBackoff)
+	}
+	if c.BindTimeout < 0 {
+		return fmt.Errorf("bindTimeout cannot be negative")
+	}
+	if c.ChecksumTimeout < 0 {
+		return fmt.Errorf("checksumTimeout cannot be negative")
+	}
+	if c.OutputManifests != "" && c.ApplyAffinity {
+		return fmt.Errorf("applyAffinity cannot be used with outputManifests, as the new PVCs are not applied")
+	}
+	if c.OutputManifests != "" && (c.VerifyMount || c.VerifyChecksum) {
+		return fmt.Errorf("verifyMount and verifyChecksum cannot be used with outputManifests, as the new PVCs are not applied")
+	}
+	if (c.FreezeCommand == "") != (c.ThawCommand == "") {
+		return fmt.Errorf("freezeCommand and thawCommand must be set together, or the filesystem could be left frozen")
+	}
+	if c.Watch < 0 {
+		return fmt.Errorf("watch cannot be negative")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("batchSize cannot be negative")
+	}
+	if c.BatchSize > 0 && c.Watch > 0 {
+		return fmt.Errorf("batchSize cannot be used with watch")
+	}
+	if c.StagedSnapshotMaxAge < 0 {
+		return fmt.Errorf("stagedSnapshotMaxAge cannot be negative")
+	}
+	if c.MaxSnapshotStaleness < 0 {
+		return fmt.Errorf("maxSnapshotStaleness cannot be negative")
+	}
+	if c.AWSRoleARN != "" && !roleARNRegex.MatchString(c.AWSRoleARN) {
+		return fmt.Errorf("awsRoleArn '%s' is invalid; must be like 'arn:aws:iam::123456789012:role/name'", c.AWSRoleARN)
+	}
+	if c.KMSKeyID != "" && !kmsKeyRegex.MatchString(c.KMSKeyID) {
+		return fmt.Errorf("kmsKeyId '%s' is invalid; must be a key ID, key ARN or alias like 'alias/ebs'", c.KMSKeyID)
+	}
+	if c.VolumeType != "" && !slices.Contains([]string{"gp3", "io1", "io2"}, c.VolumeType) {
+		return fmt.Errorf("volumeType '%s' is invalid; must be 'gp3', 'io1' or 'io2'", c.VolumeType)
+	}
+	if c.IOPS < 0 {
+		return fmt.Errorf("iops cannot be negative")
+	}
+	if (c.VolumeType == "io1" || c.VolumeType == "io2") && c.IOPS == 0 {
+		return fmt.Errorf("volumeType '%s' requires iops", c.VolumeType)
+	}
+	if c.Throughput < 0 {
+		return fmt.Errorf("throughput cannot be negative")
+	}
+	if c.Throughput > 0 && c.VolumeType != "" && c.VolumeType != "gp3" {
+		return fmt.Errorf("throughput only applies to volumeType 'gp3'")
+	}
+	for volumeType, tib := range c.StorageQuotasTiB {
+		if !slices.Contains([]string{"gp3", "io1", "io2"}, volumeType) {
+			return fmt.Errorf("storageQuotasTiB type '%s' is invalid; must be 'gp3', 'io1' or 'io2'", volumeType)
+		}
+		if tib <= 0 {
+			return fmt.Errorf("storageQuotasTiB of '%s' must be positive", volumeType)
+		}
+	}
+	if c.TagAnnotationPrefix != "" && strings.Count(c.TagAnnotationPrefix, "/") != 1 {
+		return fmt.Errorf("tagAnnotationPrefix '%s' is invalid; must include the annotation's domain, like 'pv-zone-migrator.io/tag-'", c.TagAnnotationPrefix)
+	}
+	if c.CSIDriver != "" && !csiDriverRegex.MatchString(c.CSIDriver) {
+		return fmt.Errorf("csiDriver '%s' is invalid; must be a DNS name like 'ebs.csi.aws.com'", c.CSIDriver)
+	}
+	if c.VolumeHandlePattern != "" {
+		if _, err := regexp.Compile(c.VolumeHandlePattern); err != nil {
+			return fmt.Errorf("volumeHandlePattern is invalid: %w", err)
+		}
+	}
+	for key, value := range c.Tags {
+		if err := validateTag(key, value); err != nil {
+			return err
+		}
+	}
+	if c.AWSRoleARN == "" && (c.AWSExternalID != "" || c.AWSSessionName != "") {
+		return fmt.Errorf("awsExternalId and awsSessionName require awsRoleArn")
+	}
+	if c.BackupVault == "" && (c.BackupRoleARN != "" || c.BackupRetentionDays != 0) {
+		return fmt.Errorf("backupRoleArn and backupRetentionDays require backupVault")
+	}
+	if c.BackupVault != "" && c.BackupRoleARN == "" {
+		return fmt.Errorf("backupVault requires backupRoleArn")
+	}
+	if c.BackupRoleARN != "" && !roleARNRegex.MatchString(c.BackupRoleARN) {
+		return fmt.Errorf("backupRoleArn '%s' is invalid; must be like 'arn:aws:iam::123456789012:role/name'", c.BackupRoleARN)
+	}
+	if c.BackupRetentionDays < 0 {
+		return fmt.Errorf("backupRetentionDays cannot be negative")
+	}
+	for _, n := range c.Notifications {
+		if err := n.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the webhook URL, payload format and event names
+func (n NotificationConfig) Validate() error {
+	u, err := url.Parse(n.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("notification url '%s' is invalid; must be an http(s) URL", n.URL)
+	}
+	switch n.Format {
+	case "", "slack", "generic":
+	default:
+		return fmt.Errorf("notification format '%s' is invalid; must be 'slack' or 'generic'", n.Format)
+	}
+	for _, e := range n.Events {
+		switch e {
+		case "start", "failure", "summary":
+		default:
+			return fmt.Errorf("notification event '%s' is invalid; must be 'start', 'failure' or 'summary'", e)
+		}
+	}
+	return nil
+}
+
+// DiscoversNamespaces reports whether namespaces are found in the cluster
+// rather than only listed in the config
+func (c *Config) DiscoversNamespaces() bool {
+	return c.AllNamespaces || c.NamespaceSelector != ""
+}
+
+// StorageClassFor returns the storage class of the new PV and PVC of a claim: its
+// own override, else its namespace's, else the global storageClass
+func (c *Config) StorageClassFor(namespace, pvc string) string {
+	for _, ns := range c.Namespaces {
+		if ns.Name != namespace {
+			continue
+		}
+		if class, ok := ns.StorageClasses[pvc]; ok {
+			return class
+		}
+		if ns.StorageClass != "" {
+			return ns.StorageClass
+		}
+	}
+	return c.StorageClass
+}
+
+// PriorityFor returns the priority of a claim: its own, else its namespace's,
+// else normal
+func (c *Config) PriorityFor(namespace, pvc string) string {
+	for _, ns := range c.Namespaces {
+		if ns.Name != namespace {
+			continue
+		}
+		if priority, ok := ns.Priorities[pvc]; ok {
+			return priority
+		}
+		if ns.Priority != "" {
+			return ns.Priority
+		}
+	}
+	return "normal"
+}
+
+// validPriority reports whether a priority is one of high, normal or low, or
+// empty for the default
+func validPriority(priority string) bool {
+	switch priority {
+	case "", "high", "normal", "low":
+		return true
+	}
+	return false
+}
+
+// IsProtectedContext reports whether a kube context matches one of the
+// protectedContexts patterns
+func (c *Config) IsProtectedContext(kubeContext string) bool {
+	return matchContext(c.ProtectedContexts, kubeContext)
+}
+
+// CheckContext returns an error when a kube context matches deniedContexts, or
+// allowedContexts is set and the context matches none of its patterns
+func (c *Config) CheckContext(kubeContext string) error {
+	if matchContext(c.DeniedContexts, kubeContext) {
+		return fmt.Errorf("context '%s' matches deniedContexts", kubeContext)
+	}
+	if len(c.AllowedContexts) > 0 && !matchContext(c.AllowedContexts, kubeContext) {
+		return fmt.Errorf("context '%s' is not in allowedContexts", kubeContext)
+	}
 	return nil
 }
 
+// matchContext reports whether a kube context matches one of the glob patterns.
+// Unlike PVC patterns, * also matches '/', as EKS context names are ARNs ending
+// in cluster/<name>.
+func matchContext(patterns []string, kubeContext string) bool {
+	for _, pattern := range patterns {
+		expr := strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern))
+		if regexp.MustCompile("^" + expr + "$").MatchString(kubeContext) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNamespaceNames returns just the namespace names
 func (c *Config) GetNamespaceNames() []string {
 	names := make([]string, len(c.Namespaces))
@@ -123,11 +656,47 @@ func WriteExampleConfig(path string) error {
 # This file contains configuration for migrating PVCs between AWS Availability Zones.
 #
 # Each namespace can optionally specify which PVCs to migrate.
-# If no PVCs are specified for a namespace, all PVCs in that namespace will be migrated.
+# If no PVCs are specified for a namespace, all PVCs in that namespace will be migrated,
+# except those matching excludePVCs (exact names or glob patterns):
+#
+#   - name: namespace-3
+#     excludePVCs: [scratch, cache-*]
+#
+# pvcs entries can also be glob patterns or regexes between slashes, which are
+# matched against the PVCs that exist in the namespace:
+#
+#   - name: namespace-4
+#     pvcs: [data-postgres-*, "/logs-[0-9]+/"]
+#
+# allNamespaces: true also migrates the EBS-backed PVCs of every other namespace,
+# and namespaceSelector: team=payments those of the namespaces with that label.
+# Namespaces listed above keep their own pvcs/excludePVCs settings.
+#
+# targetZones: [eu-west-1a, eu-west-1b, eu-west-1c] spreads PVCs across zones
+# instead of moving them all to targetZone, keeping StatefulSet replicas apart.
+#
+# A namespace can override storageClass for its PVCs, and storageClasses for single ones:
+#
+#   - name: namespace-5
+#     storageClass: io2
+#     storageClasses: {wal-0: io1}
 #
 # CLI flags can override some values (--zone, --storage-class, etc.)
 
 # kubeContext: my-cluster-context  # Optional: kubectl context to use (defaults to current)
+# locale: es                        # Optional: language of messages, en or es (defaults to $LANG)
+#
+# notifications:                    # Optional: post to Slack or generic webhooks
+#   - url: https://hooks.slack.com/services/T000/B000/XXXX
+#     events: [start, failure, summary]  # Defaults to all events
+#   - url: https://alerts.example.com/hooks/pvc-migrator
+#     format: generic                # JSON body with event details instead of Slack text
+#
+# events:                           # Optional: publish lifecycle events for automation
+#   snsTopicArn: arn:aws:sns:eu-west-1:123456789012:pvc-migrations
+#   eventBusName: default
+#
+# journalNamespace: ops             # Optional: keep a record of each run in a ConfigMap here
 
 `
 	if err := os.WriteFile(path, []byte(header+string(data)), 0600); err != nil {
@@ -136,3 +705,20 @@ func WriteExampleConfig(path string) error {
 
 	return nil
 }
+
+// validateTag checks a key of tags and renders its value with every variable,
+// so a template that would fail for each PVC fails the config instead
+func validateTag(key, value string) error {
+	if key == "" || strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return fmt.Errorf("tags key '%s' is invalid; must not be empty or start with 'aws:'", key)
+	}
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return fmt.Errorf("tags value of '%s' is not a valid template: %w", key, err)
+	}
+	data := map[string]string{"Namespace": "default", "PVCName": "data", "RunID": "run"}
+	if err := tmpl.Execute(io.Discard, data); err != nil {
+		return fmt.Errorf("tags value of '%s' is invalid; may only use {{.Namespace}}, {{.PVCName}} and {{.RunID}}: %w", key, err)
+	}
+	return nil
+}
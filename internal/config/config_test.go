@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,6 +22,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.False(t, cfg.DryRun)
 	assert.False(t, cfg.SkipArgoCD)
 	assert.Equal(t, []string{"argocd", "argo-cd", "gitops"}, cfg.ArgoCDNamespaces)
+	assert.Equal(t, "backups", cfg.BackupDir)
 }
 
 func TestLoadFromFile(t *testing.T) {
@@ -149,6 +151,37 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: "at least one namespace is required",
 		},
+		{
+			name: "all_namespaces_without_list",
+			config: &Config{
+				AllNamespaces:  true,
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "namespace_selector_without_list",
+			config: &Config{
+				NamespaceSelector: "team=payments,env!=dev",
+				TargetZone:        "us-west-2a",
+				StorageClass:      "gp3",
+				MaxConcurrency:    1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_namespace_selector",
+			config: &Config{
+				NamespaceSelector: "team in payments",
+				TargetZone:        "us-west-2a",
+				StorageClass:      "gp3",
+				MaxConcurrency:    1,
+			},
+			wantErr:     true,
+			errContains: "namespaceSelector 'team in payments' is invalid",
+		},
 		{
 			name: "empty_namespace_name",
 			config: &Config{
@@ -204,23 +237,1096 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: "maxConcurrency must be at least 1",
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-
-			err := tc.config.Validate()
-
-			if tc.wantErr {
-				require.Error(t, err)
-				if tc.errContains != "" {
-					assert.Contains(t, err.Error(), tc.errContains)
-				}
-				return
-			}
-
-			require.NoError(t, err)
+		{
+			name: "valid_round_robin_with_cap",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-west-2a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  5,
+				Scheduling:      "round-robin",
+				MaxPerNamespace: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_scheduling",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				Scheduling:     "random",
+			},
+			wantErr:     true,
+			errContains: "scheduling 'random' is invalid",
+		},
+		{
+			name: "invalid_argocd_strategy",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				ArgoCDStrategy: "pause",
+			},
+			wantErr:     true,
+			errContains: "argoCDStrategy 'pause' is invalid",
+		},
+		{
+			name: "invalid_argocd_appsets",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				ArgoCDAppSets:  "delete",
+			},
+			wantErr:     true,
+			errContains: "argoCDAppSets 'delete' is invalid",
+		},
+		{
+			name: "argocd_server_appsets_pause",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				ArgoCDAppSets:  "pause",
+				ArgoCDServer:   "argocd.example.com",
+			},
+			wantErr:     true,
+			errContains: "cannot be used with argoCDServer",
+		},
+		{
+			name: "output_manifests_verify_mount",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-west-2a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  5,
+				OutputManifests: "gitops",
+				VerifyMount:     true,
+			},
+			wantErr:     true,
+			errContains: "cannot be used with outputManifests",
+		},
+		{
+			name: "output_manifests_apply_affinity",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-west-2a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  5,
+				OutputManifests: "gitops",
+				ApplyAffinity:   true,
+			},
+			wantErr:     true,
+			errContains: "applyAffinity cannot be used with outputManifests",
+		},
+		{
+			name: "invalid_jobs",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				Jobs:           "kill",
+			},
+			wantErr:     true,
+			errContains: "jobs 'kill' is invalid",
+		},
+		{
+			name: "negative_job_timeout",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 5,
+				JobTimeout:     -time.Minute,
+			},
+			wantErr:     true,
+			errContains: "jobTimeout cannot be negative",
+		},
+		{
+			name: "invalid_max_per_namespace",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-west-2a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  5,
+				MaxPerNamespace: -1,
+			},
+			wantErr:     true,
+			errContains: "maxPerNamespace cannot be negative",
+		},
+		{
+			name: "valid_notifications",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Notifications: []NotificationConfig{
+					{URL: "https://hooks.slack.com/services/T/B/X"},
+					{URL: "http://alerts.internal/hook", Format: "generic", Events: []string{"failure", "summary"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_notification_url",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Notifications:  []NotificationConfig{{URL: "hooks.slack.com/services"}},
+			},
+			wantErr:     true,
+			errContains: "must be an http(s) URL",
+		},
+		{
+			name: "invalid_notification_format",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Notifications:  []NotificationConfig{{URL: "https://example.com", Format: "teams"}},
+			},
+			wantErr:     true,
+			errContains: "notification format 'teams' is invalid",
+		},
+		{
+			name: "invalid_notification_event",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-west-2a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Notifications:  []NotificationConfig{{URL: "https://example.com", Events: []string{"done"}}},
+			},
+			wantErr:     true,
+			errContains: "notification event 'done' is invalid",
+		},
+		{
+			name: "exclude_patterns",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", ExcludePVCs: []string{"scratch", "cache-*"}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_exclude_pattern",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", ExcludePVCs: []string{"cache-["}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "exclude pattern 'cache-[' is invalid",
+		},
+		{
+			name: "exclude_with_explicit_pvcs",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", PVCs: []string{"data"}, ExcludePVCs: []string{"scratch"}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "excludePVCs only applies when pvcs is empty",
+		},
+		{
+			name: "empty_pvc_storage_class",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", StorageClasses: map[string]string{"data-0": ""}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "storage class of pvc 'data-0' cannot be empty",
+		},
+		{
+			name: "invalid_window",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", Window: "2am-4am"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "namespace 'ns1': window '2am-4am' is invalid",
+		},
+		{
+			name: "invalid_namespace_priority",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", Priority: "urgent"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "priority 'urgent' is invalid",
+		},
+		{
+			name: "invalid_pvc_priority",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", Priorities: map[string]string{"data-0": "HIGH"}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "priority 'HIGH' of pvc 'data-0' is invalid",
+		},
+		{
+			name: "invalid_pvc_glob",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", PVCs: []string{"data-["}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "pvc pattern 'data-[' is invalid",
+		},
+		{
+			name: "invalid_pvc_regex",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "ns1", PVCs: []string{"/data-(/"}}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "pvc pattern '/data-(/' is invalid",
+		},
+		{
+			name: "valid_source_zone",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				SourceZone:     "us-east-1c",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_source_zone",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				SourceZone:     "east",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "sourceZone 'east' is invalid",
+		},
+		{
+			name: "valid_target_zones",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"us-east-1a", "us-east-1b"},
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "zone_ids",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"use1-az2", "us-east-1b"},
+				SourceZone:     "use1-az4",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "auto_target_zone",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "auto",
+				SourceZone:     "us-east-1b",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "single_target_zones_entry",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"us-east-1a"},
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "targetZones needs at least two zones",
+		},
+		{
+			name: "duplicate_target_zones",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"us-east-1a", "us-east-1a"},
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "targetZones lists 'us-east-1a' twice",
+		},
+		{
+			name: "invalid_target_zones_entry",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"us-east-1a", "east"},
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "targetZones entry 'east' is invalid",
+		},
+		{
+			name: "source_zone_in_target_zones",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZones:    []string{"us-east-1a", "us-east-1b"},
+				SourceZone:     "us-east-1b",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "sourceZone 'us-east-1b' cannot also be in targetZones",
+		},
+		{
+			name: "source_zone_is_target_zone",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				SourceZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+			},
+			wantErr:     true,
+			errContains: "sourceZone and targetZone cannot both be 'us-east-1a'",
+		},
+		{
+			name: "cordon_without_source_zone",
+			config: &Config{
+				Namespaces:        []NamespaceConfig{{Name: "default"}},
+				TargetZone:        "us-east-1a",
+				StorageClass:      "gp3",
+				MaxConcurrency:    1,
+				CordonSourceNodes: true,
+			},
+			wantErr:     true,
+			errContains: "cordonSourceNodes requires sourceZone",
+		},
+		{
+			name: "keep_cordoned_without_cordon",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				SourceZone:     "us-east-1b",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				KeepCordoned:   true,
+			},
+			wantErr:     true,
+			errContains: "keepCordoned requires cordonSourceNodes",
+		},
+		{
+			name: "valid_aws_role",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				AWSRoleARN:     "arn:aws:iam::123456789012:role/ops/pvc-migrator",
+				AWSExternalID:  "ext-1",
+				AWSSessionName: "nightly",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_aws_role",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				AWSRoleARN:     "arn:aws:iam::123456789012:user/alice",
+			},
+			wantErr:     true,
+			errContains: "awsRoleArn 'arn:aws:iam::123456789012:user/alice' is invalid",
+		},
+		{
+			name: "valid_kms_key_arn",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				KMSKeyID:       "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid_kms_key_alias",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				KMSKeyID:       "alias/ebs-prod",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_kms_key",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				KMSKeyID:       "my-key",
+			},
+			wantErr:     true,
+			errContains: "kmsKeyId 'my-key' is invalid",
+		},
+		{
+			name: "valid_io2",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				VolumeType:     "io2",
+				IOPS:           16000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "io2_without_iops",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				VolumeType:     "io2",
+			},
+			wantErr:     true,
+			errContains: "volumeType 'io2' requires iops",
+		},
+		{
+			name: "invalid_volume_type",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				VolumeType:     "st1",
+			},
+			wantErr:     true,
+			errContains: "volumeType 'st1' is invalid",
+		},
+		{
+			name: "io2_with_throughput",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				VolumeType:     "io2",
+				IOPS:           16000,
+				Throughput:     500,
+			},
+			wantErr:     true,
+			errContains: "throughput only applies to volumeType 'gp3'",
+		},
+		{
+			name: "valid_storage_quotas",
+			config: &Config{
+				Namespaces:       []NamespaceConfig{{Name: "default"}},
+				TargetZone:       "us-east-1a",
+				StorageClass:     "gp3",
+				MaxConcurrency:   1,
+				StorageQuotasTiB: map[string]int{"gp3": 300, "io2": 50},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_storage_quota_type",
+			config: &Config{
+				Namespaces:       []NamespaceConfig{{Name: "default"}},
+				TargetZone:       "us-east-1a",
+				StorageClass:     "gp3",
+				MaxConcurrency:   1,
+				StorageQuotasTiB: map[string]int{"gp2": 300},
+			},
+			wantErr:     true,
+			errContains: "storageQuotasTiB type 'gp2' is invalid",
+		},
+		{
+			name: "valid_csi_driver",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				CSIDriver:           "ebs.csi.example.com",
+				VolumeHandlePattern: `^ebs-(vol-[0-9a-f]+)@`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_csi_driver",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				CSIDriver:      "EBS CSI",
+			},
+			wantErr:     true,
+			errContains: "csiDriver 'EBS CSI' is invalid",
+		},
+		{
+			name: "invalid_volume_handle_pattern",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				VolumeHandlePattern: "(vol-",
+			},
+			wantErr:     true,
+			errContains: "volumeHandlePattern is invalid",
+		},
+		{
+			name: "valid_tags",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Tags:           map[string]string{"team": "data", "owner": "{{.Namespace}}/{{.PVCName}}", "migration": "{{.RunID}}"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tags_unknown_variable",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Tags:           map[string]string{"cluster": "{{.Cluster}}"},
+			},
+			wantErr:     true,
+			errContains: "tags value of 'cluster' is invalid",
+		},
+		{
+			name: "tags_invalid_template",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Tags:           map[string]string{"owner": "{{.PVCName"},
+			},
+			wantErr:     true,
+			errContains: "tags value of 'owner' is not a valid template",
+		},
+		{
+			name: "tags_reserved_key",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Tags:           map[string]string{"aws:createdBy": "me"},
+			},
+			wantErr:     true,
+			errContains: "tags key 'aws:createdBy' is invalid",
+		},
+		{
+			name: "valid_tag_annotation_prefix",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				TagAnnotationPrefix: "pv-zone-migrator.io/tag-",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_tag_annotation_prefix",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				TagAnnotationPrefix: "tag-",
+			},
+			wantErr:     true,
+			errContains: "tagAnnotationPrefix 'tag-' is invalid",
+		},
+		{
+			name: "aws_external_id_without_role",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				AWSExternalID:  "ext-1",
+			},
+			wantErr:     true,
+			errContains: "awsExternalId and awsSessionName require awsRoleArn",
+		},
+		{
+			name: "valid_backup_vault",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				BackupVault:         "compliance",
+				BackupRoleARN:       "arn:aws:iam::123456789012:role/service-role/AWSBackupDefaultServiceRole",
+				BackupRetentionDays: 35,
+			},
+			wantErr: false,
+		},
+		{
+			name: "backup_vault_without_role",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BackupVault:    "compliance",
+			},
+			wantErr:     true,
+			errContains: "backupVault requires backupRoleArn",
+		},
+		{
+			name: "backup_role_without_vault",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BackupRoleARN:  "arn:aws:iam::123456789012:role/backup",
+			},
+			wantErr:     true,
+			errContains: "backupRoleArn and backupRetentionDays require backupVault",
+		},
+		{
+			name: "invalid_backup_role",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BackupVault:    "compliance",
+				BackupRoleARN:  "backup",
+			},
+			wantErr:     true,
+			errContains: "backupRoleArn 'backup' is invalid",
+		},
+		{
+			name: "negative_backup_retention",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				BackupVault:         "compliance",
+				BackupRoleARN:       "arn:aws:iam::123456789012:role/backup",
+				BackupRetentionDays: -1,
+			},
+			wantErr:     true,
+			errContains: "backupRetentionDays cannot be negative",
+		},
+		{
+			name: "negative_staged_snapshot_max_age",
+			config: &Config{
+				Namespaces:           []NamespaceConfig{{Name: "default"}},
+				TargetZone:           "us-east-1a",
+				StorageClass:         "gp3",
+				MaxConcurrency:       1,
+				StagedSnapshotMaxAge: -time.Hour,
+			},
+			wantErr:     true,
+			errContains: "stagedSnapshotMaxAge cannot be negative",
+		},
+		{
+			name: "negative_aws_max_attempts",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				AWSMaxAttempts: -1,
+			},
+			wantErr:     true,
+			errContains: "awsMaxAttempts cannot be negative",
+		},
+		{
+			name: "negative_step_max_attempts",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-east-1a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  1,
+				StepMaxAttempts: -1,
+			},
+			wantErr:     true,
+			errContains: "stepMaxAttempts cannot be negative",
+		},
+		{
+			name: "negative_bind_timeout",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BindTimeout:    -time.Second,
+			},
+			wantErr:     true,
+			errContains: "bindTimeout cannot be negative",
+		},
+		{
+			name: "negative_checksum_timeout",
+			config: &Config{
+				Namespaces:      []NamespaceConfig{{Name: "default"}},
+				TargetZone:      "us-east-1a",
+				StorageClass:    "gp3",
+				MaxConcurrency:  1,
+				ChecksumTimeout: -time.Minute,
+			},
+			wantErr:     true,
+			errContains: "checksumTimeout cannot be negative",
+		},
+		{
+			name: "freeze_command_without_thaw",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				FreezeCommand:  "psql -c 'SELECT pg_backup_start(1)'",
+			},
+			wantErr:     true,
+			errContains: "freezeCommand and thawCommand must be set together",
+		},
+		{
+			name: "step_retry_max_backoff_below_backoff",
+			config: &Config{
+				Namespaces:          []NamespaceConfig{{Name: "default"}},
+				TargetZone:          "us-east-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				StepRetryBackoff:    time.Minute,
+				StepRetryMaxBackoff: 10 * time.Second,
+			},
+			wantErr:     true,
+			errContains: "stepRetryMaxBackoff 10s is shorter than stepRetryBackoff 1m0s",
+		},
+		{
+			name: "negative_watch",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				Watch:          -time.Minute,
+			},
+			wantErr:     true,
+			errContains: "watch cannot be negative",
+		},
+		{
+			name: "negative_batch_size",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BatchSize:      -1,
+			},
+			wantErr:     true,
+			errContains: "batchSize cannot be negative",
+		},
+		{
+			name: "batch_size_with_watch",
+			config: &Config{
+				Namespaces:     []NamespaceConfig{{Name: "default"}},
+				TargetZone:     "us-east-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				BatchSize:      500,
+				Watch:          30 * time.Minute,
+			},
+			wantErr:     true,
+			errContains: "batchSize cannot be used with watch",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.config.Validate()
+
+			if tc.wantErr {
+				require.Error(t, err)
+				if tc.errContains != "" {
+					assert.Contains(t, err.Error(), tc.errContains)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNamespaceConfig_Excludes(t *testing.T) {
+	t.Parallel()
+
+	ns := NamespaceConfig{Name: "db", ExcludePVCs: []string{"scratch", "cache-*", "tmp-?"}}
+
+	cases := []struct {
+		pvc  string
+		want bool
+	}{
+		{pvc: "scratch", want: true},
+		{pvc: "scratch-2", want: false},
+		{pvc: "cache-redis-0", want: true},
+		{pvc: "tmp-1", want: true},
+		{pvc: "tmp-10", want: false},
+		{pvc: "data-postgres-0", want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pvc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, ns.Excludes(tc.pvc))
+		})
+	}
+}
+
+func TestNamespaceConfig_ExpandPVCs(t *testing.T) {
+	t.Parallel()
+
+	listed := []string{"data-postgres-0", "data-postgres-1", "data-postgres-10", "logs-0", "logs-a", "scratch"}
+
+	cases := []struct {
+		name         string
+		pvcs         []string
+		wantPatterns bool
+		want         []string
+	}{
+		{
+			name: "literal_names_kept",
+			pvcs: []string{"scratch", "missing"},
+			want: []string{"scratch", "missing"},
+		},
+		{
+			name:         "glob",
+			pvcs:         []string{"data-postgres-?"},
+			wantPatterns: true,
+			want:         []string{"data-postgres-0", "data-postgres-1"},
+		},
+		{
+			name:         "regex_matches_whole_name",
+			pvcs:         []string{"/logs-[0-9]+/", "/postgres/"},
+			wantPatterns: true,
+			want:         []string{"logs-0"},
+		},
+		{
+			name:         "mixed_without_duplicates",
+			pvcs:         []string{"scratch", "data-*", "data-postgres-1"},
+			wantPatterns: true,
+			want:         []string{"scratch", "data-postgres-0", "data-postgres-1", "data-postgres-10"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ns := NamespaceConfig{Name: "db", PVCs: tc.pvcs}
+			assert.Equal(t, tc.wantPatterns, ns.HasPVCPatterns())
+			assert.Equal(t, tc.want, ns.ExpandPVCs(listed))
+		})
+	}
+}
+
+func TestConfig_StorageClassFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		StorageClass: "gp3",
+		Namespaces: []NamespaceConfig{
+			{Name: "db", StorageClass: "io2", StorageClasses: map[string]string{"wal-0": "io1"}},
+			{Name: "logs", StorageClasses: map[string]string{"archive": "sc1"}},
+			{Name: "web"},
+		},
+	}
+
+	cases := []struct {
+		namespace string
+		pvc       string
+		expected  string
+	}{
+		{namespace: "db", pvc: "data-0", expected: "io2"},
+		{namespace: "db", pvc: "wal-0", expected: "io1"},
+		{namespace: "logs", pvc: "archive", expected: "sc1"},
+		{namespace: "logs", pvc: "app", expected: "gp3"},
+		{namespace: "web", pvc: "static", expected: "gp3"},
+		{namespace: "other", pvc: "data-0", expected: "gp3"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.namespace+"/"+tc.pvc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, cfg.StorageClassFor(tc.namespace, tc.pvc))
+		})
+	}
+}
+
+func TestConfig_PriorityFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		Namespaces: []NamespaceConfig{
+			{Name: "db", Priority: "high", Priorities: map[string]string{"scratch": "normal"}},
+			{Name: "archive", Priorities: map[string]string{"cold": "low"}},
+		},
+	}
+
+	cases := []struct {
+		namespace string
+		pvc       string
+		expected  string
+	}{
+		{namespace: "db", pvc: "data-0", expected: "high"},
+		{namespace: "db", pvc: "scratch", expected: "normal"},
+		{namespace: "archive", pvc: "cold", expected: "low"},
+		{namespace: "archive", pvc: "index", expected: "normal"},
+		{namespace: "other", pvc: "data-0", expected: "normal"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.namespace+"/"+tc.pvc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, cfg.PriorityFor(tc.namespace, tc.pvc))
+		})
+	}
+}
+
+func TestNamespaceConfig_WindowBounds(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		window    string
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantErr   string
+	}{
+		{window: "02:00-04:00", wantStart: 2 * time.Hour, wantEnd: 4 * time.Hour},
+		{window: "23:30 - 01:15", wantStart: 23*time.Hour + 30*time.Minute, wantEnd: time.Hour + 15*time.Minute},
+		{window: "02:00", wantErr: "must be like '02:00-04:00'"},
+		{window: "02:00-25:00", wantErr: "'25:00' is not a HH:MM time"},
+		{window: "03:00-03:00", wantErr: "is empty"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.window, func(t *testing.T) {
+			t.Parallel()
+			start, end, err := NamespaceConfig{Window: tc.window}.WindowBounds()
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStart, start)
+			assert.Equal(t, tc.wantEnd, end)
+		})
+	}
+}
+
+func TestConfig_IsProtectedContext(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{ProtectedContexts: []string{"*prod*", "payments-?"}}
+
+	cases := []struct {
+		context  string
+		expected bool
+	}{
+		{context: "prod", expected: true},
+		{context: "eu-prod-1", expected: true},
+		{context: "arn:aws:eks:eu-west-1:123456789012:cluster/prod-main", expected: true},
+		{context: "payments-1", expected: true},
+		{context: "payments-12", expected: false},
+		{context: "staging", expected: false},
+		{context: "", expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.context, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, cfg.IsProtectedContext(tc.context))
+		})
+	}
+
+	assert.False(t, (&Config{}).IsProtectedContext("prod"), "nothing is protected by default")
+}
+
+func TestConfig_CheckContext(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		config  Config
+		context string
+		wantErr string
+	}{
+		{name: "no lists", context: "prod"},
+		{name: "allowed", config: Config{AllowedContexts: []string{"staging-*"}}, context: "staging-eu"},
+		{name: "not allowed", config: Config{AllowedContexts: []string{"staging-*"}}, context: "prod", wantErr: "not in allowedContexts"},
+		{name: "denied", config: Config{DeniedContexts: []string{"*prod*"}}, context: "arn:aws:eks:eu-west-1:123456789012:cluster/prod", wantErr: "matches deniedContexts"},
+		{
+			name:    "deny wins over allow",
+			config:  Config{AllowedContexts: []string{"*"}, DeniedContexts: []string{"prod"}},
+			context: "prod",
+			wantErr: "matches deniedContexts",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.config.CheckContext(tc.context)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
 		})
 	}
 }
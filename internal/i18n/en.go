@@ -0,0 +1,434 @@
+package i18n
+
+// catalogEN holds the English messages. Every key used by the tool must be present here.
+var catalogEN = map[string]string{
+	// Migration plan
+	"plan.title":                  "MIGRATION PLAN",
+	"plan.configuration":          "Configuration:",
+	"plan.target_zone":            "Target Zone:",
+	"plan.storage_class":          "Storage Class:",
+	"plan.volume_type":            "Volume Type:",
+	"plan.namespaces":             "Namespaces:",
+	"plan.concurrency":            "Concurrency:",
+	"plan.auto_zones":             "Zone per namespace (least-loaded healthy zone):",
+	"plan.auto_zone":              "%s → %s: %d nodes, %d%% CPU / %d%% memory requested after the move",
+	"plan.auto_zone_none":         "%s: no healthy zone outside %s",
+	"plan.node_issues":            "Target zone nodes:",
+	"plan.node_issue_empty":       "⚠️  %s has no Ready, schedulable node for %s",
+	"plan.node_issue_selector":    "⚠️  No node in %s matches the nodeSelector of %s, which mounts %s",
+	"plan.node_issue_launch":      "⚠️  %s has no Ready, schedulable node for %s, and no Karpenter NodePool or node group can launch one",
+	"plan.scheduling":             "Pod scheduling simulation:",
+	"plan.sched_fits":             "✓ A pod of %s fits on %d node(s) in %s",
+	"plan.sched_unfit":            "⚠️  A pod of %s fits on no node in %s: %s",
+	"plan.sched_denied":           "⚠️  A pod of %s in %s was rejected: %s",
+	"plan.missing_permissions":    "Missing EC2 permissions:",
+	"plan.quotas":                 "EBS quotas:",
+	"plan.quota_snapshots":        "Snapshots: %d owned + %d new, of a quota of %d",
+	"plan.quota_storage":          "%s storage: %dGiB + %dGiB new, of a quota of %dGiB",
+	"plan.encryption":             "Encryption:",
+	"plan.kms_conflict":           "⚠️  KMS key %s differs from the account default key %s",
+	"plan.dry_run":                "⚠️  DRY RUN MODE - No changes will be made",
+	"plan.pvcs_to_process":        "PVCs to Process (%d):",
+	"plan.count_migrate":          "✓ Migrate: %d",
+	"plan.count_skip":             "○ Skip: %d",
+	"plan.count_error":            "✗ Error: %d",
+	"plan.col_pvc":                "PVC",
+	"plan.col_zone":               "Current Zone",
+	"plan.col_action":             "Action",
+	"plan.will_migrate":           "✓ Will migrate → %s",
+	"plan.skip_same_az":           "○ Skip (same AZ)",
+	"plan.skip_short":             "○ Skip",
+	"plan.volume_detail":          "  └─ %s, Volume: %s",
+	"plan.unattached":             "  └─ Not mounted, workloads keep running",
+	"plan.staged_snapshot":        "  └─ Starts from staged snapshot %s (%s)",
+	"plan.tags":                   "  └─ Tags: %s",
+	"plan.priority":               "  └─ Priority: %s",
+	"plan.window":                 "  └─ Window: %s",
+	"plan.block":                  "  └─ Raw block device: the new PV and PVC are volumeMode Block",
+	"plan.storage_class_override": "  └─ Storage class: %s",
+	"plan.block_express":          "  └─ io2 Block Express: only attaches to Nitro instances that support it",
+	"plan.volume_override":        "  └─ Volume: %s",
+	"plan.co_mounted":             "  └─ Added: pod %s also mounts it",
+	"plan.pv_deleted":             "  └─ PV %s was deleted; its volume is adopted and the PV and PVC are rebuilt",
+	"plan.pv_unhealthy":           "  └─ PVC %s, PV %s; the PV and PVC are rebuilt",
+	"plan.resume_claim":           "  └─ An earlier run deleted the PVC; it is recreated on PV %s",
+	"plan.resume_pv":              "  └─ Goes on from PV %s of an earlier run",
+	"plan.encrypted":              "  └─ Encrypted with %s",
+	"plan.reencrypted":            "  └─ Encrypted with %s, re-encrypted from %s",
+	"plan.unencrypted":            "  └─ Not encrypted",
+	"plan.actions":                "Actions to be performed:",
+	"plan.action_snapshots":       "Create EBS snapshots for %d volume(s)",
+	"plan.action_volumes":         "Create new volumes in %s",
+	"plan.action_volumes_fsr":     "Create new volumes in %s, with fast snapshot restore enabled while they are created",
+	"plan.action_delete":          "Delete old PVCs and PVs",
+	"plan.action_create":          "Create new static PVs and bound PVCs",
+
+	// Plain-text (accessible) plan and progress
+	"plain.title":                  "Migration plan.",
+	"plain.target_zone":            "Target zone: %s.",
+	"plain.storage_class":          "Storage class: %s.",
+	"plain.volume_type":            "Volume type: %s.",
+	"plain.namespaces":             "Namespaces: %s.",
+	"plain.concurrency":            "Concurrency: %d.",
+	"plain.encryption":             "Encryption: %s.",
+	"plain.auto_zone":              "Namespace %s moves to %s, its least-loaded healthy zone: %d nodes, %d%% of CPU and %d%% of memory requested after the move.",
+	"plain.auto_zone_none":         "Namespace %s has no healthy zone outside %s to move to.",
+	"plain.node_issue_empty":       "Warning: zone %s has no Ready, schedulable node to run the pods of %s.",
+	"plain.node_issue_selector":    "Warning: no node in zone %s matches the nodeSelector of %s, which mounts %s.",
+	"plain.node_issue_launch":      "Warning: zone %s has no Ready, schedulable node to run the pods of %s, and no Karpenter NodePool or node group can launch one.",
+	"plain.sched_fits":             "A pod of %s fits on %d node(s) in zone %s.",
+	"plain.sched_unfit":            "Warning: a pod of %s fits on no node in zone %s: %s.",
+	"plain.sched_denied":           "Warning: a pod of %s in zone %s was rejected: %s.",
+	"plain.missing_permissions":    "Error: the credentials are denied %s, so no PVC is migrated.",
+	"plain.quota_snapshots":        "EBS snapshots: %d owned and %d new, of a quota of %d.",
+	"plain.quota_storage":          "EBS %s storage: %dGiB and %dGiB new, of a quota of %dGiB.",
+	"plain.kms_conflict":           "Warning: KMS key %s differs from the account default key %s.",
+	"encryption.run_key":           "new volumes use KMS key %s",
+	"encryption.by_default":        "account encrypts new volumes by default with %s",
+	"encryption.off":               "account default off, volumes keep the encryption of their snapshot",
+	"volume.kept":                  "as the old volume; gp3 for types other than gp3, io1 and io2",
+	"volume.kept_with":             "type of the old volume, gp3 for types other than gp3, io1 and io2, with %s",
+	"encryption.unknown":           "account defaults unknown",
+	"plain.dry_run":                "Dry run: no changes will be made.",
+	"plain.fast_restore":           "Fast snapshot restore is enabled on each snapshot in its target zone while its volume is created, and billed by the hour until then.",
+	"plain.counts":                 "%d PVCs: %d to migrate, %d to skip, %d with errors.",
+	"plain.migrate":                "Migrate %s, %s, from %s to %s.",
+	"plain.unattached":             "No pod mounts %s, so no workloads are scaled down for it.",
+	"plain.staged_snapshot":        "%s starts from staged snapshot %s taken %s. Writes made after it are not migrated.",
+	"plain.tags":                   "%s is tagged %s.",
+	"plain.priority":               "%s has %s priority.",
+	"plain.window":                 "%s is only migrated between %s.",
+	"plain.block":                  "%s is a raw block device; its new PV and PVC are volumeMode Block.",
+	"plain.storage_class_override": "%s uses storage class %s.",
+	"plain.block_express":          "%s becomes an io2 Block Express volume, which only attaches to Nitro instances that support it.",
+	"plain.volume_override":        "%s gets a %s volume.",
+	"plain.co_mounted":             "%s was added because pod %s also mounts it.",
+	"plain.pv_deleted":             "%s lost PV %s; volume %s was found by its tags and is adopted, and a new PV and PVC are created.",
+	"plain.pv_unhealthy":           "%s is %s with a %s PV; a new PV and PVC are created.",
+	"plain.resume_claim":           "An earlier run deleted %s; it is recreated on PV %s, keeping volume %s.",
+	"plain.resume_pv":              "%s goes on from PV %s an earlier run created; no new snapshot or volume is made.",
+	"plain.encrypted":              "%s is encrypted with %s.",
+	"plain.reencrypted":            "%s is re-encrypted from %s to %s.",
+	"plain.unencrypted":            "%s is not encrypted.",
+	"plain.skip":                   "Skip %s, already in the target zone.",
+	"plain.error":                  "Error for %s: %s.",
+	"plain.progress":               "snapshot %d percent complete.",
+	"plain.finished":               "%d of %d PVCs finished.",
+	"plain.get_info":               "getting volume information.",
+	"plain.skipped":                "skipped, already in the target zone.",
+	"plain.snapshot":               "creating snapshot.",
+	"plain.wait_snapshot":          "waiting for snapshot.",
+	"plain.create_volume":          "creating volume in the target zone.",
+	"plain.wait_volume":            "waiting for volume.",
+	"plain.cleanup":                "removing old PVC and PV.",
+	"plain.create_pv":              "creating persistent volume.",
+	"plain.create_pvc":             "creating persistent volume claim.",
+	"plain.done":                   "migrated successfully.",
+	"plain.failed":                 "failed.",
+	"plain.failed_error":           "failed: %s",
+	"plain.incomplete":             "did not finish.",
+	"plain.new_volume":             "New volume: %s.",
+	"plain.mount_passed":           "The mount check passed.",
+	"plain.mount_failed":           "The mount check failed: %v.",
+	"plain.checksum_ok":            "The files on the new volume match the source, checksum %s.",
+
+	// Accessible end-of-run summary
+	"plain.summary_title":   "Migration summary.",
+	"plain.summary_counts":  "%d PVCs: %d migrated, %d skipped, %d failed.",
+	"plain.api_calls":       "API calls: %s.",
+	"plain.some_failed":     "Some migrations failed. Check the errors above.",
+	"plain.with_warnings":   "Migrations completed, but %d warnings need attention.",
+	"plain.all_ok":          "All migrations completed successfully.",
+	"plain.finish":          "To finish %s by hand, run:",
+	"plain.rollback":        "To roll back %s, run:",
+	"plain.orphans":         "Orphaned Kubernetes objects: %d left without a claim. Bind a claim to them with the finish commands of their PVC, or delete them. Their EBS volumes are kept.",
+	"plain.orphans_gc":      "To delete them:",
+	"plain.stragglers":      "Source zone %s is not empty: %d EBS volume(s) claimed in the run's namespaces are still there. Migrate them, or move their PVCs out of the namespaces, before decommissioning the zone.",
+	"plain.zone_not_empty":  "The source zone still holds volumes, see below.",
+	"plain.action_required": "Action required: %d follow-ups.",
+	"plain.action":          "To fix it:",
+
+	// Step names shown in the TUI
+	"priority.high":            "high",
+	"priority.low":             "low",
+	"tui.throttled":            "(throttled by EC2, backing off)",
+	"tui.priority":             "%s priority",
+	"step.pending":             "Pending",
+	"step.get_info":            "Getting Info",
+	"step.skipped":             "Skipped",
+	"step.snapshot":            "Creating Snapshot",
+	"step.wait_snapshot":       "Snapshot Progress",
+	"step.create_volume":       "Creating Volume",
+	"step.wait_volume":         "Volume Creating",
+	"step.cleanup":             "Cleaning Up",
+	"step.create_pv":           "Creating PV",
+	"step.create_pvc":          "Creating PVC",
+	"step.done":                "Completed",
+	"step.failed":              "Failed",
+	"step.short.pending":       "Pending",
+	"step.short.get_info":      "Info",
+	"step.short.skipped":       "Skipped",
+	"step.short.snapshot":      "Snap",
+	"step.short.wait_snapshot": "Snap…",
+	"step.short.create_volume": "Volume",
+	"step.short.wait_volume":   "Volume…",
+	"step.short.cleanup":       "Cleanup",
+	"step.short.create_pv":     "PV",
+	"step.short.create_pvc":    "PVC",
+	"step.short.done":          "Done",
+	"step.short.failed":        "Failed",
+
+	// Terminal UI
+	"tui.title":               "🚀 PVC Migration Tool",
+	"tui.generating":          "Generating migration plan...",
+	"tui.fetching":            "Fetching volume information from AWS...",
+	"tui.plan_failed":         "✗ Failed to generate plan: ",
+	"tui.press_q_exit":        "Press q to exit",
+	"tui.scale_warning":       "⚠️  WARNING: Ensure all deployments/statefulsets are SCALED TO 0",
+	"tui.scale_warning_short": "⚠️  Workloads must be SCALED TO 0",
+	"tui.confirm":             "Press %s or %s to start, %s or %s to cancel",
+	"tui.confirm_short":       "%s start · %s cancel",
+	"tui.cancelled":           "👋 Migration cancelled.",
+	"tui.pvcs_to_migrate":     "PVCs to migrate:",
+	"tui.progress":            "Migration Progress:",
+	"tui.press_cancel":        "Press p to pause or resume, q or Ctrl+C to cancel",
+	"tui.press_cancel_logs":   "Press p to pause or resume, l to show or hide the log, q or Ctrl+C to cancel",
+	"tui.logs":                "Log (↑/↓ PgUp/PgDn to scroll)",
+	"tui.logs_scrolled":       "%d line(s) back, End to follow",
+	"tui.paused":              "⏸ Paused: no new PVCs are started; those in progress finish",
+	"tui.complete":            "✅ Migration complete! Press q to exit",
+	"tui.retry_prompt":        "%d PVC(s) failed before their PVC was changed. Press r to retry them, q to exit",
+	"tui.zone_short":          "Zone:",
+
+	// Summary
+	"summary.title":           "MIGRATION SUMMARY",
+	"summary.new_volume":      "New Volume:",
+	"summary.adopted":         "Adopted from an earlier run:",
+	"summary.mount_check":     "Mount check:",
+	"summary.mount_passed":    "passed",
+	"summary.mount_failed":    "failed: %v",
+	"summary.checksum":        "Checksum:",
+	"summary.checksum_ok":     "verified, %s",
+	"summary.already_in_zone": "(already in target zone)",
+	"summary.error":           "Error:",
+	"summary.incomplete":      "(Incomplete)",
+	"summary.retry_hint":      "%d of them failed before their PVC was changed and are migrated again by re-running the command",
+	"summary.total":           "Total: %d",
+	"summary.success":         "Success: %d",
+	"summary.skipped":         "Skipped: %d",
+	"summary.failed":          "Failed: %d",
+	"summary.some_failed":     "⚠️  Some migrations failed. Please check the errors above.",
+	"summary.all_ok":          "🎉 All migrations completed successfully!",
+	"summary.next_step":       "Next step: Ensure your workloads can schedule pods in %s",
+	"summary.with_warnings":   "⚠️  Migrations completed, but %d warning(s) need attention.",
+	"summary.api_calls":       "API calls: %s",
+	"summary.api_service":     "%s %d",
+	"summary.api_throttled":   "(%d throttled)",
+	"summary.orphans":         "ORPHANED KUBERNETES OBJECTS",
+	"summary.orphans_hint":    "Bind a claim to them with the finish commands of their PVC, or delete them. Their EBS volumes are kept.",
+	"summary.orphan":          "%s: %s %s (volume %s), %s",
+	"summary.orphans_gc":      "Delete:",
+	"summary.zone_not_empty":  "⚠️  The source zone still holds volumes. See below.",
+	"summary.stragglers":      "VOLUMES LEFT IN %s",
+	"summary.stragglers_hint": "Migrate them, or move their PVCs out of the run's namespaces, before decommissioning the zone.",
+	"summary.straggler":       "%s: PV %s, volume %s",
+	"summary.stragglers_list": "List:",
+	"summary.action_required": "ACTION REQUIRED",
+	"summary.action":          "Action:",
+	"summary.finish":          "To finish by hand:",
+	"summary.rollback":        "To roll back:",
+
+	// Console prompts and warnings
+	"cli.confirm_start":       "Start the migration? [y/N]: ",
+	"cli.cancelled":           "Migration cancelled.",
+	"cli.retrying":            "Retrying %d failed PVC(s)...",
+	"cli.restoring_on_err":    "Restoring workloads in namespace '%s' due to error...",
+	"cli.restore_failed":      "Warning: Failed to restore some workloads in '%s': %v",
+	"cli.restore_manually":    "Please manually restore workloads using kubectl",
+	"cli.argocd_failed":       "Warning: Failed to re-enable ArgoCD auto-sync: %v",
+	"cli.uncordoning_on_err":  "Uncordoning the nodes of %s due to error...",
+	"cli.uncordon_failed":     "Warning: Failed to uncordon nodes: %v",
+	"cli.argocd_manually":     "Please manually re-enable auto-sync in ArgoCD",
+	"cli.scale_down_manual":   "Please scale down the workloads manually before proceeding:",
+	"cli.manual_waiting":      "Waiting for you to run the commands above...",
+	"cli.manual_prompt":       "Press Enter when workloads are scaled down, or 'q' to quit:",
+	"cli.manual_verifying":    "Verifying workloads are scaled down...",
+	"cli.manual_done":         "All workloads scaled down",
+	"cli.jobs_found":          "Jobs still running in '%s' mount PVCs to migrate: %s",
+	"cli.jobs_waiting":        "Waiting for the jobs in '%s' to finish (up to %s)...",
+	"cli.jobs_confirm":        "Delete the %d running job(s) in '%s' and their pods? [y/N]: ",
+	"cli.jobs_deleting":       "Deleting the jobs in '%s'...",
+	"cli.plan_generating":     "Generating migration plan...",
+	"cli.from_zone":           "Selected %d of %d PVCs with volumes in %s",
+	"cli.profile_written":     "Profile written to %s",
+	"cli.safe_mode_hint":      "Nothing was changed. Run with --execute (or set execute: true in the config) to migrate.",
+	"cli.watch_pass":          "Pass %d",
+	"cli.watch_sleeping":      "Looking for PVCs left to migrate again in %s",
+	"cli.watch_done":          "Nothing left to migrate",
+	"cli.batch":               "Batch %d of %d: %d PVCs",
+	"cli.batch_resumed":       "%d PVCs already migrated in %s, leaving them out",
+	"cli.batch_done":          "%d batches: %d PVCs migrated, %d skipped; results in %s",
+	"cli.batch_none":          "Every PVC is recorded as migrated in %s, nothing left to migrate",
+	"cli.plan_hint":           "Run without --plan flag to execute the migration.",
+	"cli.restoring":           "Restoring workloads to original replica counts...",
+	"cli.restore_ns":          "Namespace '%s':",
+	"cli.restore_workload":    "%s/%s: %d replicas",
+	"cli.restored":            "Workloads restored in namespace '%s'",
+	"cli.argocd_enabling":     "Re-enabling ArgoCD auto-sync...",
+	"cli.argocd_enabled":      "Auto-sync re-enabled",
+	"cli.appset_owned":        "ArgoCD app %s is owned by ApplicationSet %s, which reverts the auto-sync change",
+	"cli.appset_remedy":       "Make it create-only (spec.syncPolicy.applicationsSync) for the run, or use --argocd-appsets pause or --argocd-strategy sync-window",
+	"cli.appset_pausing":      "ArgoCD ApplicationSets made create-only during the run: %s",
+	"cli.appset_resuming":     "Resuming ArgoCD ApplicationSets...",
+	"cli.appset_resumed":      "ApplicationSets resumed",
+	"cli.appset_failed":       "Warning: Failed to resume ArgoCD ApplicationSets: %v",
+	"cli.keda_found":          "KEDA ScaledObjects paused while workloads are scaled down: %s",
+	"cli.keda_resuming":       "Resuming KEDA autoscaling...",
+	"cli.keda_resumed":        "KEDA autoscaling resumed",
+	"cli.keda_failed":         "Warning: Failed to resume KEDA autoscaling: %v",
+	"cli.flux_found":          "Flux objects suspended while PVCs are migrated: %s",
+	"cli.helm_found":          "Helm release %s manages PVCs to migrate: %s",
+	"cli.helm_remedy":         "A helm upgrade recreates deleted PVCs with the old zone settings; update the release values after the run",
+	"cli.flux_resuming":       "Resuming Flux reconciliation...",
+	"cli.flux_resumed":        "Flux reconciliation resumed",
+	"cli.flux_failed":         "Warning: Failed to resume Flux reconciliation: %v",
+	"cli.nodes_cordoned":      "Cordoned %d node(s) in %s so workloads scaled back up are scheduled elsewhere",
+	"cli.nodes_kept":          "Leaving %d node(s) in %s cordoned",
+	"cli.nodes_uncordoning":   "Uncordoning %d node(s) in %s...",
+	"cli.nodes_uncordoned":    "Nodes uncordoned",
+	"cli.migration_id":        "Migration ID %s: if the run crashes, re-run with --migration-id to adopt the snapshots and volumes it created",
+	"cli.backup_dir":          "Each PVC and PV is saved to %s before it is deleted",
+	"cli.output_manifests":    "The new PVs and PVCs are written to %s for the GitOps repo, not applied",
+	"cli.warmup_creating":     "Creating warm-up jobs for migrated volumes...",
+	"cli.warmup_skipped":      "skipped, no running pod mounts it",
+	"cli.warmup_failed":       "Warning: %v",
+	"cli.warmup_labelled":     "Jobs are labelled %s=true and removed automatically after they finish",
+	"cli.protected_context":   "Context %s is protected: this run changes PVs, PVCs and workloads in it.",
+	"cli.protected_prompt":    "Type the context name to continue: ",
+	"cli.terraform_imports":   "Terraform import blocks for %d created resource(s):",
+	"cli.namespaces_labelled": "Labelled completed namespaces %s with %s and %s",
+	"cli.affinity_written":    "Wrote %d patches pinning workloads to their zone to %s",
+	"cli.affinity_applied":    "Pinning %s %s/%s to %s",
+
+	// Snapshot pre-staging command
+	"snapshot.starting":   "Creating snapshots of %d PVC(s) for %s. Nothing in the cluster is changed.",
+	"snapshot.ready":      "%s: snapshot %s is ready",
+	"snapshot.skipped":    "%s: already in %s, no snapshot needed",
+	"snapshot.failed":     "%s: failed: %v",
+	"snapshot.unfinished": "%s: did not finish",
+	"snapshots.none":      "No snapshots created by the tool were found.",
+	"snapshots.header":    "SNAPSHOT\tAGE\tSIZE\tPVC\tSOURCE VOLUME\tSTATE\tMIGRATION",
+	"snapshots.migrated":  "new volume %s",
+	"snapshots.staged":    "staged, not used yet",
+	"snapshots.unused":    "not used",
+	"snapshots.total":     "%d snapshot(s); %d GiB in snapshots no migration used.",
+	"snapshot.tagged":     "Snapshots are tagged %s=true so a later migration can find them.",
+
+	// Zones command
+	"zones.none":           "No EBS-backed PVCs were found in the namespaces.",
+	"zones.header":         "PVC\tVOLUME\tZONE\tSIZE",
+	"zones.not_found":      "volume not found",
+	"zones.summary_header": "ZONE\tZONE ID\tPVCS\tCAPACITY\tNAMESPACES",
+	"zones.total":          "%d PVC(s), %d GiB in %d zone(s).",
+	"zones.missing":        "%d PVC(s) whose volume was not found in EC2 are left out of the totals.",
+	"zones.resolved":       "Zone ID %s is %s in this account.",
+	"zones.role_names":     "⚠️  Zone names point at different physical zones in each account, and EC2 is called as %s; give zone IDs such as use1-az2 to be sure of the zone.",
+
+	// List command
+	"list.header":      "PVC\tPV\tVOLUME\tSIZE\tTYPE\tENCRYPTED\tZONE\tWORKLOADS",
+	"list.encrypted":   "yes",
+	"list.unencrypted": "no",
+	"list.total":       "%d EBS-backed PVC(s).",
+
+	// What-if command
+	"whatif.header":       "PVC\tSIZE\tTYPE\tIOPS\tTHROUGHPUT\tCOST/MONTH",
+	"whatif.invalid":      "%s cannot have %s: %s",
+	"whatif.total":        "%d PVC(s): $%.2f → $%.2f a month (%+.2f).",
+	"whatif.unpriced":     "%d PVC(s) whose volume type has no known price are left out of the total.",
+	"whatif.prices":       "Costs are estimates at the on-demand list prices of %s in USD, without snapshots; other regions and discounts differ.",
+	"whatif.not_possible": "not possible",
+
+	// History command
+	"history.none":              "No migrations are recorded in namespace %s.",
+	"history.header":            "MIGRATION ID\tSTARTED\tDURATION\tCONTEXT\tOPERATOR\tPVCS\tOUTCOME",
+	"history.pvcs":              "%d migrated, %d skipped, %d failed",
+	"history.succeeded":         "succeeded",
+	"history.failed":            "failed",
+	"history.unfinished":        "unfinished",
+	"history.show_title":        "Migration %s: %s",
+	"history.show_context":      "Context: %s, run by %s",
+	"history.show_time":         "Started %s, finished %s (%s)",
+	"history.show_time_running": "Started %s, no end recorded",
+	"history.show_target":       "Target zone: %s",
+	"history.show_header":       "PVC\tOUTCOME\tSTEP\tFROM\tTO\tOLD VOLUME\tSNAPSHOT\tNEW VOLUME",
+	"history.show_warnings":     "Warnings:",
+
+	// Restore command
+	"restore.title":     "Restoring %s from migration %s",
+	"restore.snapshot":  "A new volume is created in %s from snapshot %s of the original volume %s.",
+	"restore.current":   "The claim is bound to PV %s on volume %s now; that volume is kept.",
+	"restore.data_loss": "Data written since the migration is lost: the new volume holds what the snapshot held.",
+	"restore.scale":     "The workloads of namespace %s are scaled down during the restore and back up after it.",
+	"restore.confirm":   "Restore the PVC? [y/N]: ",
+	"restore.cancelled": "Restore cancelled.",
+	"restore.done":      "%s restored to volume %s in %s",
+	"restore.kept":      "Volume %s of the migration is kept; delete it once the app works again.",
+
+	// Warnings collected for the summary
+	"warn.restore_failed":      "Workloads in namespace '%s' were not restored: %v",
+	"warn.restore_action":      "Scale the workloads back up:",
+	"warn.retry_volume_left":   "Volume %s created by the failed attempt is not used by the retry",
+	"warn.retry_volume_action": "Delete it once the retry succeeds:",
+	"warn.retry_volume_pvs":    "PV %s still references it; keep the volume until the PV is deleted",
+	"warn.retry_volume_check":  "Could not check whether a PV references it (%v); make sure none does before deleting it",
+	"warn.argocd_failed":       "ArgoCD auto-sync was not re-enabled: %v",
+	"warn.argocd_action":       "Re-enable auto-sync manually:",
+	"warn.appset_failed":       "ArgoCD ApplicationSets were not resumed: %v",
+	"warn.appset_action":       "Restore their applicationsSync policy manually:",
+	"warn.keda_failed":         "KEDA autoscaling was not resumed: %v",
+	"warn.keda_action":         "Restore the paused-replicas annotations manually:",
+	"warn.flux_failed":         "Flux reconciliation was not resumed: %v",
+	"warn.flux_action":         "Resume the Flux objects manually:",
+	"warn.helm_release":        "Helm release %s manages migrated PVCs (%s); its next upgrade can revert them",
+	"warn.helm_owned":          "Give the new PVCs the Helm ownership metadata so the release can adopt them:",
+	"warn.helm_values":         "Upgrade the release with the values changes written to %s:",
+	"warn.helm_action":         "Set the storage class and zone of the migrated PVCs in the release values before its next upgrade",
+	"warn.uncordon_failed":     "Nodes of %s were not uncordoned: %v",
+	"warn.cordoned_failed":     "Nodes of %s are left cordoned, but %d PVC(s) failed and still need them for their pods",
+	"warn.uncordon_action":     "Uncordon them:",
+	"warn.warmup_failed":       "Warm-up job was not created: %v",
+	"warn.warmup_action":       "The volume hydrates on first read; expect slower I/O until then",
+	"warn.fsr_failed":          "Fast snapshot restore was not enabled, so the new volume loads its blocks on first read: %v",
+	"warn.fsr_disable_failed":  "Fast snapshot restore of %s in %s was not disabled and is still billed by the hour: %v",
+	"warn.fsr_disable_action":  "Disable it:\naws ec2 disable-fast-snapshot-restores --availability-zones %s --source-snapshot-ids %s",
+	"warn.terraform_failed":    "Terraform import blocks were not written: %v",
+	"warn.terraform_action":    "Find the snapshots and volumes by their MigratedPVC tag and reconcile them by hand",
+	"warn.verify_failed":       "Could not verify that no volume is left in %s: %v",
+	"warn.verify_action":       "List the volumes left in the zone:\naws ec2 describe-volumes --filters Name=availability-zone,Values=%s",
+	"warn.label_failed":        "Namespaces were not labelled: %v",
+	"warn.label_action":        "Check the PVCs are Bound, then run the migration again or label the namespaces by hand",
+	"warn.affinity_failed":     "Workloads were not pinned to their zone: %v",
+	"warn.affinity_action":     "Add a topology.kubernetes.io/zone nodeSelector to their pod templates by hand, or in the repo that deploys them",
+	"warn.mount_check_failed":  "The new volume did not pass the mount check: %v",
+	"warn.mount_check_action":  "The data is in place, but check the volume mounts in the target zone before relying on it:",
+	"warn.checksum_skipped":    "The checksum was not verified, as the snapshot was not taken by this run right after the source checksum",
+	"warn.checksum_action":     "Compare the files on the new volume with the application's own checks before relying on it",
+	"warn.block_skipped":       "The PVC is a raw block device (volumeMode: Block), so the filesystem freeze, checksum and mount check were skipped",
+	"warn.block_action":        "Check the data on the new volume with the application's own tools before relying on it",
+	"warn.thaw_failed":         "The filesystem in pod %s is still frozen: %v",
+	"warn.thaw_action":         "Thaw it now, as the application cannot write to it until then:",
+	"warn.metrics_failed":      "Final metrics were not pushed to the Pushgateway: %v",
+	"warn.metrics_action":      "Check the Pushgateway URL; this run's metrics are lost",
+	"warn.textfile_failed":     "Final metrics were not written for the textfile collector: %v",
+	"warn.textfile_action":     "Check that the directory exists and is writable",
+	"warn.profile_failed":      "Profiles were not written: %v",
+	"warn.profile_action":      "Check that --profile-dir exists and is writable",
+	"warn.event_failed":        "Lifecycle event '%s' was not published: %v",
+	"warn.event_action":        "Check the SNS topic / EventBridge bus and IAM permissions; downstream automation missed this event",
+	"warn.journal_failed":      "Migration journal %s was not written: %v",
+	"warn.journal_action":      "Check that the namespace exists and configmaps can be created and updated in it; the cluster's record of this run is incomplete",
+	"warn.state_failed":        "Results were not recorded in state file %s: %v",
+	"warn.state_action":        "Fix the file before resuming: the PVCs of this batch are planned again, and skipped once found in their target zone",
+	"warn.vault_failed":        "Volume %s was not backed up to AWS Backup vault %s: %v",
+	"warn.vault_action":        "The migration snapshot is the only restore point; check the vault, backupRoleArn and the backup:StartBackupJob and iam:PassRole permissions, then back the volume up by hand",
+}
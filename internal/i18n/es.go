@@ -0,0 +1,434 @@
+package i18n
+
+// catalogES holds the Spanish messages. Missing keys fall back to English.
+var catalogES = map[string]string{
+	// Migration plan
+	"plan.title":                  "PLAN DE MIGRACIÓN",
+	"plan.configuration":          "Configuración:",
+	"plan.target_zone":            "Zona destino:",
+	"plan.storage_class":          "Clase de almacenamiento:",
+	"plan.volume_type":            "Tipo de volumen:",
+	"plan.namespaces":             "Namespaces:",
+	"plan.concurrency":            "Concurrencia:",
+	"plan.auto_zones":             "Zona por namespace (zona sana menos cargada):",
+	"plan.auto_zone":              "%s → %s: %d nodos, %d%% de CPU / %d%% de memoria solicitados tras el traslado",
+	"plan.auto_zone_none":         "%s: ninguna zona sana fuera de %s",
+	"plan.node_issues":            "Nodos de la zona destino:",
+	"plan.node_issue_empty":       "⚠️  %s no tiene ningún nodo Ready y planificable para %s",
+	"plan.node_issue_selector":    "⚠️  Ningún nodo de %s cumple el nodeSelector de %s, que monta %s",
+	"plan.node_issue_launch":      "⚠️  %s no tiene ningún nodo Ready y planificable para %s, y ningún NodePool de Karpenter ni grupo de nodos puede lanzar uno",
+	"plan.scheduling":             "Simulación de planificación de pods:",
+	"plan.sched_fits":             "✓ Un pod de %s cabe en %d nodo(s) de %s",
+	"plan.sched_unfit":            "⚠️  Un pod de %s no cabe en ningún nodo de %s: %s",
+	"plan.sched_denied":           "⚠️  Un pod de %s en %s fue rechazado: %s",
+	"plan.missing_permissions":    "Permisos de EC2 que faltan:",
+	"plan.quotas":                 "Cuotas de EBS:",
+	"plan.quota_snapshots":        "Snapshots: %d propios + %d nuevos, de una cuota de %d",
+	"plan.quota_storage":          "Almacenamiento %s: %dGiB + %dGiB nuevos, de una cuota de %dGiB",
+	"plan.encryption":             "Cifrado:",
+	"plan.kms_conflict":           "⚠️  La clave KMS %s difiere de la clave por defecto de la cuenta %s",
+	"plan.dry_run":                "⚠️  MODO SIMULACIÓN - No se realizarán cambios",
+	"plan.pvcs_to_process":        "PVCs a procesar (%d):",
+	"plan.count_migrate":          "✓ Migrar: %d",
+	"plan.count_skip":             "○ Omitir: %d",
+	"plan.count_error":            "✗ Error: %d",
+	"plan.col_pvc":                "PVC",
+	"plan.col_zone":               "Zona actual",
+	"plan.col_action":             "Acción",
+	"plan.will_migrate":           "✓ Se migrará → %s",
+	"plan.skip_same_az":           "○ Omitir (misma AZ)",
+	"plan.skip_short":             "○ Omitir",
+	"plan.volume_detail":          "  └─ %s, Volumen: %s",
+	"plan.unattached":             "  └─ Sin montar, las cargas siguen en marcha",
+	"plan.staged_snapshot":        "  └─ Parte del snapshot preparado %s (%s)",
+	"plan.tags":                   "  └─ Etiquetas: %s",
+	"plan.priority":               "  └─ Prioridad: %s",
+	"plan.window":                 "  └─ Ventana: %s",
+	"plan.block":                  "  └─ Dispositivo de bloques: el PV y el PVC nuevos son volumeMode Block",
+	"plan.storage_class_override": "  └─ Clase de almacenamiento: %s",
+	"plan.block_express":          "  └─ io2 Block Express: solo se conecta a instancias Nitro que lo admiten",
+	"plan.volume_override":        "  └─ Volumen: %s",
+	"plan.co_mounted":             "  └─ Añadido: el pod %s también lo monta",
+	"plan.resume_claim":           "  └─ Una ejecución anterior borró el PVC; se recrea sobre el PV %s",
+	"plan.resume_pv":              "  └─ Continúa desde el PV %s de una ejecución anterior",
+	"plan.pv_deleted":             "  └─ El PV %s fue borrado; se adopta su volumen y se recrean el PV y el PVC",
+	"plan.pv_unhealthy":           "  └─ PVC %s, PV %s; se recrean el PV y el PVC",
+	"plan.encrypted":              "  └─ Cifrado con %s",
+	"plan.reencrypted":            "  └─ Cifrado con %s, recifrado desde %s",
+	"plan.unencrypted":            "  └─ Sin cifrar",
+	"plan.actions":                "Acciones a realizar:",
+	"plan.action_snapshots":       "Crear snapshots EBS de %d volumen(es)",
+	"plan.action_volumes":         "Crear volúmenes nuevos en %s",
+	"plan.action_volumes_fsr":     "Crear volúmenes nuevos en %s, con la restauración rápida de snapshots activada mientras se crean",
+	"plan.action_delete":          "Eliminar los PVCs y PVs antiguos",
+	"plan.action_create":          "Crear PVs estáticos nuevos y PVCs vinculados",
+
+	// Plain-text (accessible) plan and progress
+	"plain.title":                  "Plan de migración.",
+	"plain.target_zone":            "Zona destino: %s.",
+	"plain.storage_class":          "Clase de almacenamiento: %s.",
+	"plain.volume_type":            "Tipo de volumen: %s.",
+	"plain.namespaces":             "Namespaces: %s.",
+	"plain.concurrency":            "Concurrencia: %d.",
+	"plain.encryption":             "Cifrado: %s.",
+	"plain.auto_zone":              "El namespace %s pasa a %s, su zona sana menos cargada: %d nodos, %d%% de CPU y %d%% de memoria solicitados tras el traslado.",
+	"plain.auto_zone_none":         "El namespace %s no tiene ninguna zona sana fuera de %s a la que moverse.",
+	"plain.node_issue_empty":       "Aviso: la zona %s no tiene ningún nodo Ready y planificable para los pods de %s.",
+	"plain.node_issue_selector":    "Aviso: ningún nodo de la zona %s cumple el nodeSelector de %s, que monta %s.",
+	"plain.node_issue_launch":      "Aviso: la zona %s no tiene ningún nodo Ready y planificable para los pods de %s, y ningún NodePool de Karpenter ni grupo de nodos puede lanzar uno.",
+	"plain.sched_fits":             "Un pod de %s cabe en %d nodo(s) de la zona %s.",
+	"plain.sched_unfit":            "Aviso: un pod de %s no cabe en ningún nodo de la zona %s: %s.",
+	"plain.sched_denied":           "Aviso: un pod de %s en la zona %s fue rechazado: %s.",
+	"plain.missing_permissions":    "Error: a las credenciales se les deniega %s, así que no se migra ningún PVC.",
+	"plain.quota_snapshots":        "Snapshots de EBS: %d propios y %d nuevos, de una cuota de %d.",
+	"plain.quota_storage":          "Almacenamiento %s de EBS: %dGiB y %dGiB nuevos, de una cuota de %dGiB.",
+	"plain.kms_conflict":           "Aviso: la clave KMS %s difiere de la clave por defecto de la cuenta %s.",
+	"encryption.run_key":           "los volúmenes nuevos usan la clave KMS %s",
+	"encryption.by_default":        "la cuenta cifra los volúmenes nuevos por defecto con %s",
+	"encryption.off":               "cifrado por defecto desactivado, los volúmenes mantienen el cifrado de su snapshot",
+	"volume.kept":                  "como el volumen antiguo; gp3 para tipos distintos de gp3, io1 e io2",
+	"volume.kept_with":             "tipo del volumen antiguo, gp3 para tipos distintos de gp3, io1 e io2, con %s",
+	"encryption.unknown":           "configuración de la cuenta desconocida",
+	"plain.dry_run":                "Simulación: no se realizarán cambios.",
+	"plain.fast_restore":           "La restauración rápida se activa en cada snapshot en su zona destino mientras se crea su volumen, y se cobra por hora hasta entonces.",
+	"plain.counts":                 "%d PVCs: %d a migrar, %d a omitir, %d con errores.",
+	"plain.migrate":                "Migrar %s, %s, de %s a %s.",
+	"plain.unattached":             "Ningún pod monta %s, así que no se escala ninguna carga por él.",
+	"plain.staged_snapshot":        "%s parte del snapshot preparado %s tomado el %s. Las escrituras posteriores no se migran.",
+	"plain.tags":                   "%s se etiqueta con %s.",
+	"plain.priority":               "%s tiene prioridad %s.",
+	"plain.window":                 "%s solo se migra entre %s.",
+	"plain.block":                  "%s es un dispositivo de bloques; su PV y su PVC nuevos son volumeMode Block.",
+	"plain.storage_class_override": "%s usa la clase de almacenamiento %s.",
+	"plain.block_express":          "%s pasa a ser un volumen io2 Block Express, que solo se conecta a instancias Nitro que lo admiten.",
+	"plain.volume_override":        "%s recibe un volumen %s.",
+	"plain.co_mounted":             "%s se añadió porque el pod %s también lo monta.",
+	"plain.resume_claim":           "Una ejecución anterior borró %s; se recrea sobre el PV %s y se conserva el volumen %s.",
+	"plain.resume_pv":              "%s continúa desde el PV %s que creó una ejecución anterior; no se crean snapshot ni volumen nuevos.",
+	"plain.pv_deleted":             "%s perdió el PV %s; el volumen %s se encontró por sus etiquetas y se adopta, y se crean un PV y un PVC nuevos.",
+	"plain.pv_unhealthy":           "%s está %s con un PV %s; se crean un PV y un PVC nuevos.",
+	"plain.encrypted":              "%s se cifra con %s.",
+	"plain.reencrypted":            "%s se recifra de %s a %s.",
+	"plain.unencrypted":            "%s no está cifrado.",
+	"plain.skip":                   "Omitir %s, ya está en la zona destino.",
+	"plain.error":                  "Error en %s: %s.",
+	"plain.progress":               "snapshot completado al %d por ciento.",
+	"plain.finished":               "%d de %d PVCs terminados.",
+	"plain.get_info":               "obteniendo información del volumen.",
+	"plain.skipped":                "omitido, ya está en la zona destino.",
+	"plain.snapshot":               "creando snapshot.",
+	"plain.wait_snapshot":          "esperando al snapshot.",
+	"plain.create_volume":          "creando volumen en la zona destino.",
+	"plain.wait_volume":            "esperando al volumen.",
+	"plain.cleanup":                "eliminando el PVC y el PV antiguos.",
+	"plain.create_pv":              "creando el volumen persistente.",
+	"plain.create_pvc":             "creando la reclamación de volumen persistente.",
+	"plain.done":                   "migrado correctamente.",
+	"plain.failed":                 "ha fallado.",
+	"plain.failed_error":           "ha fallado: %s",
+	"plain.incomplete":             "no ha terminado.",
+	"plain.new_volume":             "Volumen nuevo: %s.",
+	"plain.mount_passed":           "La prueba de montaje fue correcta.",
+	"plain.mount_failed":           "La prueba de montaje falló: %v.",
+	"plain.checksum_ok":            "Los ficheros del volumen nuevo coinciden con el origen, suma %s.",
+
+	// Accessible end-of-run summary
+	"plain.summary_title":   "Resumen de la migración.",
+	"plain.summary_counts":  "%d PVCs: %d migrados, %d omitidos, %d fallidos.",
+	"plain.api_calls":       "Llamadas a la API: %s.",
+	"plain.some_failed":     "Algunas migraciones han fallado. Revise los errores anteriores.",
+	"plain.with_warnings":   "Migraciones completadas, pero %d avisos requieren atención.",
+	"plain.all_ok":          "Todas las migraciones se han completado correctamente.",
+	"plain.finish":          "Para terminar %s a mano, ejecute:",
+	"plain.rollback":        "Para deshacer %s, ejecute:",
+	"plain.orphans":         "Objetos de Kubernetes huérfanos: %d sin claim. Vincula un claim con los comandos para terminar su PVC, o bórralos. Sus volúmenes EBS se conservan.",
+	"plain.orphans_gc":      "Para borrarlos:",
+	"plain.stragglers":      "La zona origen %s no está vacía: %d volumen(es) EBS reclamados en los namespaces de la ejecución siguen ahí. Mígrelos, o saque sus PVCs de los namespaces, antes de retirar la zona.",
+	"plain.zone_not_empty":  "La zona origen todavía tiene volúmenes, vea más abajo.",
+	"plain.action_required": "Acción necesaria: %d tareas pendientes.",
+	"plain.action":          "Para resolverlo:",
+
+	// Step names shown in the TUI
+	"priority.high":            "alta",
+	"priority.low":             "baja",
+	"tui.throttled":            "(EC2 limita las peticiones, reintentando)",
+	"tui.priority":             "prioridad %s",
+	"step.pending":             "Pendiente",
+	"step.get_info":            "Obteniendo info",
+	"step.skipped":             "Omitido",
+	"step.snapshot":            "Creando snapshot",
+	"step.wait_snapshot":       "Progreso snapshot",
+	"step.create_volume":       "Creando volumen",
+	"step.wait_volume":         "Volumen en creación",
+	"step.cleanup":             "Limpiando",
+	"step.create_pv":           "Creando PV",
+	"step.create_pvc":          "Creando PVC",
+	"step.done":                "Completado",
+	"step.failed":              "Fallido",
+	"step.short.pending":       "Pendiente",
+	"step.short.get_info":      "Info",
+	"step.short.skipped":       "Omitido",
+	"step.short.snapshot":      "Snap",
+	"step.short.wait_snapshot": "Snap…",
+	"step.short.create_volume": "Volumen",
+	"step.short.wait_volume":   "Volumen…",
+	"step.short.cleanup":       "Limpieza",
+	"step.short.create_pv":     "PV",
+	"step.short.create_pvc":    "PVC",
+	"step.short.done":          "Hecho",
+	"step.short.failed":        "Fallido",
+
+	// Terminal UI
+	"tui.title":               "🚀 Herramienta de migración de PVC",
+	"tui.generating":          "Generando el plan de migración...",
+	"tui.fetching":            "Obteniendo información de los volúmenes en AWS...",
+	"tui.plan_failed":         "✗ No se pudo generar el plan: ",
+	"tui.press_q_exit":        "Pulse q para salir",
+	"tui.scale_warning":       "⚠️  ATENCIÓN: Asegúrese de que todos los deployments/statefulsets están ESCALADOS A 0",
+	"tui.scale_warning_short": "⚠️  Las cargas deben estar ESCALADAS A 0",
+	"tui.confirm":             "Pulse %s o %s para empezar, %s o %s para cancelar",
+	"tui.confirm_short":       "%s empezar · %s cancelar",
+	"tui.cancelled":           "👋 Migración cancelada.",
+	"tui.pvcs_to_migrate":     "PVCs a migrar:",
+	"tui.progress":            "Progreso de la migración:",
+	"tui.press_cancel":        "Pulse p para pausar o reanudar, q o Ctrl+C para cancelar",
+	"tui.press_cancel_logs":   "Pulse p para pausar o reanudar, l para mostrar u ocultar el registro, q o Ctrl+C para cancelar",
+	"tui.logs":                "Registro (↑/↓ RePág/AvPág para desplazarse)",
+	"tui.logs_scrolled":       "%d línea(s) atrás, Fin para seguir",
+	"tui.paused":              "⏸ En pausa: no se inician PVCs nuevos; los que están en curso terminan",
+	"tui.complete":            "✅ ¡Migración completada! Pulse q para salir",
+	"tui.retry_prompt":        "%d PVC(s) fallaron antes de modificar su PVC. Pulse r para reintentarlos, q para salir",
+	"tui.zone_short":          "Zona:",
+
+	// Summary
+	"summary.title":           "RESUMEN DE LA MIGRACIÓN",
+	"summary.new_volume":      "Volumen nuevo:",
+	"summary.adopted":         "Adoptado de una ejecución anterior:",
+	"summary.mount_check":     "Prueba de montaje:",
+	"summary.mount_passed":    "correcta",
+	"summary.mount_failed":    "fallida: %v",
+	"summary.checksum":        "Suma de comprobación:",
+	"summary.checksum_ok":     "verificada, %s",
+	"summary.already_in_zone": "(ya está en la zona destino)",
+	"summary.error":           "Error:",
+	"summary.incomplete":      "(Incompleto)",
+	"summary.retry_hint":      "%d de ellos fallaron antes de modificar su PVC y se migran de nuevo al volver a ejecutar el comando",
+	"summary.total":           "Total: %d",
+	"summary.success":         "Correctos: %d",
+	"summary.skipped":         "Omitidos: %d",
+	"summary.failed":          "Fallidos: %d",
+	"summary.some_failed":     "⚠️  Algunas migraciones han fallado. Revise los errores anteriores.",
+	"summary.all_ok":          "🎉 ¡Todas las migraciones se han completado correctamente!",
+	"summary.next_step":       "Siguiente paso: asegúrese de que sus cargas pueden programar pods en %s",
+	"summary.api_calls":       "Llamadas a la API: %s",
+	"summary.api_service":     "%s %d",
+	"summary.api_throttled":   "(%d limitadas)",
+	"summary.with_warnings":   "⚠️  Migraciones completadas, pero %d aviso(s) requieren atención.",
+	"summary.orphans":         "OBJETOS DE KUBERNETES HUÉRFANOS",
+	"summary.orphans_hint":    "Vincula un claim con los comandos para terminar su PVC, o bórralos. Sus volúmenes EBS se conservan.",
+	"summary.orphan":          "%s: %s %s (volumen %s), %s",
+	"summary.orphans_gc":      "Borrar:",
+	"summary.zone_not_empty":  "⚠️  La zona origen todavía tiene volúmenes. Vea más abajo.",
+	"summary.stragglers":      "VOLÚMENES QUE QUEDAN EN %s",
+	"summary.stragglers_hint": "Mígrelos, o saque sus PVCs de los namespaces de la ejecución, antes de retirar la zona.",
+	"summary.straggler":       "%s: PV %s, volumen %s",
+	"summary.stragglers_list": "Listar:",
+	"summary.action_required": "ACCIÓN NECESARIA",
+	"summary.action":          "Acción:",
+	"summary.finish":          "Para terminar a mano:",
+	"summary.rollback":        "Para deshacer:",
+
+	// Console prompts and warnings
+	"cli.confirm_start":       "¿Iniciar la migración? [s/N]: ",
+	"cli.cancelled":           "Migración cancelada.",
+	"cli.retrying":            "Reintentando %d PVC(s) fallidos...",
+	"cli.restoring_on_err":    "Restaurando las cargas del namespace '%s' debido a un error...",
+	"cli.restore_failed":      "Aviso: no se pudieron restaurar algunas cargas en '%s': %v",
+	"cli.restore_manually":    "Restaure las cargas manualmente con kubectl",
+	"cli.argocd_failed":       "Aviso: no se pudo reactivar la sincronización automática de ArgoCD: %v",
+	"cli.uncordoning_on_err":  "Desacordonando los nodos de %s debido a un error...",
+	"cli.uncordon_failed":     "Aviso: no se pudieron desacordonar los nodos: %v",
+	"cli.argocd_manually":     "Reactive la sincronización automática manualmente en ArgoCD",
+	"cli.scale_down_manual":   "Escale a 0 las cargas manualmente antes de continuar:",
+	"cli.manual_waiting":      "Esperando a que ejecute los comandos anteriores...",
+	"cli.manual_prompt":       "Pulse Intro cuando las cargas estén escaladas a 0, o 'q' para salir:",
+	"cli.manual_verifying":    "Comprobando que las cargas están escaladas a 0...",
+	"cli.manual_done":         "Todas las cargas escaladas a 0",
+	"cli.jobs_found":          "Jobs aún en ejecución en '%s' montan PVCs a migrar: %s",
+	"cli.jobs_waiting":        "Esperando a que terminen los jobs de '%s' (hasta %s)...",
+	"cli.jobs_confirm":        "¿Eliminar los %d job(s) en ejecución de '%s' y sus pods? [s/N]: ",
+	"cli.jobs_deleting":       "Eliminando los jobs de '%s'...",
+	"cli.from_zone":           "Seleccionados %d de %d PVCs con volúmenes en %s",
+	"cli.profile_written":     "Perfil escrito en %s",
+	"cli.plan_generating":     "Generando el plan de migración...",
+	"cli.safe_mode_hint":      "No se ha modificado nada. Ejecute con --execute (o execute: true en la configuración) para migrar.",
+	"cli.watch_pass":          "Pasada %d",
+	"cli.watch_sleeping":      "Se volverán a buscar PVCs pendientes de migrar en %s",
+	"cli.watch_done":          "No queda nada por migrar",
+	"cli.batch":               "Lote %d de %d: %d PVCs",
+	"cli.batch_resumed":       "%d PVCs ya migrados en %s, se dejan fuera",
+	"cli.batch_done":          "%d lotes: %d PVCs migrados, %d omitidos; resultados en %s",
+	"cli.batch_none":          "Todos los PVCs constan como migrados en %s, no queda nada por migrar",
+	"cli.plan_hint":           "Ejecute sin la opción --plan para realizar la migración.",
+	"cli.restoring":           "Restaurando las cargas a su número de réplicas original...",
+	"cli.restore_ns":          "Namespace '%s':",
+	"cli.restore_workload":    "%s/%s: %d réplicas",
+	"cli.restored":            "Cargas restauradas en el namespace '%s'",
+	"cli.argocd_enabling":     "Reactivando la sincronización automática de ArgoCD...",
+	"cli.argocd_enabled":      "Sincronización automática reactivada",
+	"cli.appset_owned":        "La app de ArgoCD %s pertenece al ApplicationSet %s, que revierte el cambio de auto-sync",
+	"cli.appset_remedy":       "Páselo a create-only (spec.syncPolicy.applicationsSync) durante la ejecución, o use --argocd-appsets pause o --argocd-strategy sync-window",
+	"cli.appset_pausing":      "ApplicationSets de ArgoCD en create-only durante la ejecución: %s",
+	"cli.appset_resuming":     "Reanudando los ApplicationSets de ArgoCD...",
+	"cli.appset_resumed":      "ApplicationSets reanudados",
+	"cli.appset_failed":       "Aviso: no se pudieron reanudar los ApplicationSets de ArgoCD: %v",
+	"cli.keda_found":          "ScaledObjects de KEDA en pausa mientras las cargas de trabajo están reducidas: %s",
+	"cli.keda_resuming":       "Reanudando el autoescalado de KEDA...",
+	"cli.keda_resumed":        "Autoescalado de KEDA reanudado",
+	"cli.keda_failed":         "Aviso: no se pudo reanudar el autoescalado de KEDA: %v",
+	"cli.flux_found":          "Objetos de Flux suspendidos mientras se migran los PVCs: %s",
+	"cli.helm_found":          "La release de Helm %s gestiona PVCs a migrar: %s",
+	"cli.helm_remedy":         "Un helm upgrade recrea los PVCs borrados con la configuración de zona anterior; actualice los values de la release tras la ejecución",
+	"cli.flux_resuming":       "Reanudando la reconciliación de Flux...",
+	"cli.flux_resumed":        "Reconciliación de Flux reanudada",
+	"cli.flux_failed":         "Aviso: no se pudo reanudar la reconciliación de Flux: %v",
+	"cli.nodes_cordoned":      "Acordonados %d nodo(s) en %s para que las cargas reescaladas se programen en otra zona",
+	"cli.nodes_kept":          "Se dejan acordonados %d nodo(s) en %s",
+	"cli.nodes_uncordoning":   "Desacordonando %d nodo(s) en %s...",
+	"cli.nodes_uncordoned":    "Nodos desacordonados",
+	"cli.migration_id":        "ID de migración %s: si la ejecución falla, vuelve a ejecutar con --migration-id para adoptar los snapshots y volúmenes que creó",
+	"cli.backup_dir":          "Cada PVC y PV se guarda en %s antes de borrarlo",
+	"cli.output_manifests":    "Los nuevos PV y PVC se escriben en %s para el repositorio GitOps, sin aplicarlos",
+	"cli.warmup_creating":     "Creando jobs de precalentamiento para los volúmenes migrados...",
+	"cli.warmup_skipped":      "omitido, ningún pod en ejecución lo monta",
+	"cli.warmup_failed":       "Aviso: %v",
+	"cli.warmup_labelled":     "Los jobs llevan la etiqueta %s=true y se eliminan automáticamente al terminar",
+	"cli.protected_context":   "El contexto %s está protegido: esta ejecución modifica PVs, PVCs y cargas de trabajo en él.",
+	"cli.protected_prompt":    "Escriba el nombre del contexto para continuar: ",
+	"cli.terraform_imports":   "Bloques import de Terraform para %d recurso(s) creado(s):",
+	"cli.namespaces_labelled": "Namespaces completados %s etiquetados con %s y %s",
+	"cli.affinity_written":    "Se escribieron %d parches que fijan las cargas de trabajo a su zona en %s",
+	"cli.affinity_applied":    "Fijando %s %s/%s a %s",
+
+	// Snapshot pre-staging command
+	"snapshot.starting":   "Creando snapshots de %d PVC(s) para %s. No se modifica nada en el clúster.",
+	"snapshot.ready":      "%s: el snapshot %s está listo",
+	"snapshot.skipped":    "%s: ya está en %s, no necesita snapshot",
+	"snapshot.failed":     "%s: falló: %v",
+	"snapshot.unfinished": "%s: no terminó",
+	"snapshots.none":      "No se encontraron snapshots creados por la herramienta.",
+	"snapshots.header":    "SNAPSHOT\tEDAD\tTAMAÑO\tPVC\tVOLUMEN ORIGEN\tESTADO\tMIGRACIÓN",
+	"snapshots.migrated":  "volumen nuevo %s",
+	"snapshots.staged":    "preparado, sin usar todavía",
+	"snapshots.unused":    "sin usar",
+	"snapshots.total":     "%d snapshot(s); %d GiB en snapshots que ninguna migración usó.",
+	"snapshot.tagged":     "Los snapshots llevan la etiqueta %s=true para que una migración posterior los encuentre.",
+
+	// Zones command
+	"zones.none":           "No se encontraron PVCs respaldados por EBS en los namespaces.",
+	"zones.header":         "PVC\tVOLUMEN\tZONA\tTAMAÑO",
+	"zones.not_found":      "volumen no encontrado",
+	"zones.summary_header": "ZONA\tID DE ZONA\tPVCS\tCAPACIDAD\tNAMESPACES",
+	"zones.total":          "%d PVC(s), %d GiB en %d zona(s).",
+	"zones.missing":        "%d PVC(s) cuyo volumen no se encontró en EC2 quedan fuera de los totales.",
+	"zones.resolved":       "El ID de zona %s es %s en esta cuenta.",
+	"zones.role_names":     "⚠️  Los nombres de zona apuntan a zonas físicas distintas en cada cuenta, y EC2 se llama como %s; indica IDs de zona como use1-az2 para asegurar la zona.",
+
+	// List command
+	"list.header":      "PVC\tPV\tVOLUMEN\tTAMAÑO\tTIPO\tCIFRADO\tZONA\tWORKLOADS",
+	"list.encrypted":   "sí",
+	"list.unencrypted": "no",
+	"list.total":       "%d PVC(s) respaldados por EBS.",
+
+	// What-if command
+	"whatif.header":       "PVC\tTAMAÑO\tTIPO\tIOPS\tRENDIMIENTO\tCOSTE/MES",
+	"whatif.invalid":      "%s no puede tener %s: %s",
+	"whatif.total":        "%d PVC(s): $%.2f → $%.2f al mes (%+.2f).",
+	"whatif.unpriced":     "%d PVC(s) cuyo tipo de volumen no tiene un precio conocido quedan fuera del total.",
+	"whatif.prices":       "Los costes son estimaciones con los precios de lista bajo demanda de %s en USD, sin snapshots; otras regiones y descuentos difieren.",
+	"whatif.not_possible": "no es posible",
+
+	// History command
+	"history.none":              "No hay migraciones registradas en el namespace %s.",
+	"history.header":            "ID DE MIGRACIÓN\tINICIO\tDURACIÓN\tCONTEXTO\tOPERADOR\tPVCS\tRESULTADO",
+	"history.pvcs":              "%d migrados, %d omitidos, %d fallidos",
+	"history.succeeded":         "completada",
+	"history.failed":            "fallida",
+	"history.unfinished":        "sin terminar",
+	"history.show_title":        "Migración %s: %s",
+	"history.show_context":      "Contexto: %s, ejecutada por %s",
+	"history.show_time":         "Inicio %s, fin %s (%s)",
+	"history.show_time_running": "Inicio %s, sin fin registrado",
+	"history.show_target":       "Zona destino: %s",
+	"history.show_header":       "PVC\tRESULTADO\tPASO\tDESDE\tHACIA\tVOLUMEN ANTIGUO\tSNAPSHOT\tVOLUMEN NUEVO",
+	"history.show_warnings":     "Avisos:",
+
+	// Restore command
+	"restore.title":     "Restaurando %s desde la migración %s",
+	"restore.snapshot":  "Se crea un volumen nuevo en %s a partir del snapshot %s del volumen original %s.",
+	"restore.current":   "El claim está ligado ahora al PV %s del volumen %s; ese volumen se conserva.",
+	"restore.data_loss": "Se pierden los datos escritos desde la migración: el volumen nuevo contiene lo que contenía el snapshot.",
+	"restore.scale":     "Las cargas del namespace %s se escalan a cero durante la restauración y se vuelven a escalar después.",
+	"restore.confirm":   "¿Restaurar el PVC? [s/N]: ",
+	"restore.cancelled": "Restauración cancelada.",
+	"restore.done":      "%s restaurado en el volumen %s de %s",
+	"restore.kept":      "Se conserva el volumen %s de la migración; bórrelo cuando la aplicación vuelva a funcionar.",
+
+	// Warnings collected for the summary
+	"warn.restore_failed":      "No se restauraron las cargas del namespace '%s': %v",
+	"warn.restore_action":      "Vuelva a escalar las cargas:",
+	"warn.retry_volume_left":   "El volumen %s creado por el intento fallido no se usa en el reintento",
+	"warn.retry_volume_action": "Bórrelo cuando el reintento termine bien:",
+	"warn.retry_volume_pvs":    "El PV %s todavía lo referencia; conserve el volumen hasta que se borre el PV",
+	"warn.retry_volume_check":  "No se pudo comprobar si algún PV lo referencia (%v); asegúrese de que ninguno lo hace antes de borrarlo",
+	"warn.argocd_failed":       "No se reactivó la sincronización automática de ArgoCD: %v",
+	"warn.argocd_action":       "Reactive la sincronización automática manualmente:",
+	"warn.appset_failed":       "No se reanudaron los ApplicationSets de ArgoCD: %v",
+	"warn.appset_action":       "Restaure a mano su política applicationsSync:",
+	"warn.keda_failed":         "No se reanudó el autoescalado de KEDA: %v",
+	"warn.keda_action":         "Restaure a mano las anotaciones paused-replicas:",
+	"warn.flux_failed":         "No se reanudó la reconciliación de Flux: %v",
+	"warn.flux_action":         "Reanude a mano los objetos de Flux:",
+	"warn.helm_release":        "La release de Helm %s gestiona PVCs migrados (%s); su próximo upgrade puede revertirlos",
+	"warn.helm_owned":          "Añada a los nuevos PVCs los metadatos de propiedad de Helm para que la release los adopte:",
+	"warn.helm_values":         "Actualice la release con los cambios de values escritos en %s:",
+	"warn.helm_action":         "Fije la storage class y la zona de los PVCs migrados en los values de la release antes de su próximo upgrade",
+	"warn.uncordon_failed":     "No se desacordonaron los nodos de %s: %v",
+	"warn.cordoned_failed":     "Los nodos de %s siguen acordonados, pero %d PVC(s) fallaron y sus pods aún los necesitan",
+	"warn.uncordon_action":     "Desacordónelos:",
+	"warn.warmup_failed":       "No se creó el job de precalentamiento: %v",
+	"warn.warmup_action":       "El volumen se hidrata en la primera lectura; la E/S será más lenta hasta entonces",
+	"warn.fsr_failed":          "No se activó la restauración rápida del snapshot, así que el nuevo volumen carga sus bloques en la primera lectura: %v",
+	"warn.fsr_disable_failed":  "La restauración rápida de %s en %s no se desactivó y se sigue cobrando por hora: %v",
+	"warn.fsr_disable_action":  "Desactívala:\naws ec2 disable-fast-snapshot-restores --availability-zones %s --source-snapshot-ids %s",
+	"warn.terraform_failed":    "No se escribieron los bloques import de Terraform: %v",
+	"warn.terraform_action":    "Busque los snapshots y volúmenes por su etiqueta MigratedPVC y concílielos a mano",
+	"warn.verify_failed":       "No se pudo verificar que no quede ningún volumen en %s: %v",
+	"warn.verify_action":       "Liste los volúmenes que quedan en la zona:\naws ec2 describe-volumes --filters Name=availability-zone,Values=%s",
+	"warn.label_failed":        "No se etiquetaron los namespaces: %v",
+	"warn.label_action":        "Compruebe que los PVCs están Bound y vuelva a ejecutar la migración o etiquete los namespaces a mano",
+	"warn.affinity_failed":     "No se fijaron las cargas de trabajo a su zona: %v",
+	"warn.affinity_action":     "Añada un nodeSelector topology.kubernetes.io/zone a sus plantillas de pod a mano, o en el repositorio que las despliega",
+	"warn.mount_check_failed":  "El volumen nuevo no superó la prueba de montaje: %v",
+	"warn.mount_check_action":  "Los datos están en su sitio, pero compruebe que el volumen se monta en la zona destino antes de confiar en él:",
+	"warn.checksum_skipped":    "No se verificó la suma de comprobación, ya que el snapshot no lo tomó esta ejecución justo después de la suma del origen",
+	"warn.checksum_action":     "Compare los ficheros del volumen nuevo con las comprobaciones de la propia aplicación antes de confiar en él",
+	"warn.block_skipped":       "El PVC es un dispositivo de bloques (volumeMode: Block), así que se omitieron la congelación del sistema de ficheros, la suma de comprobación y la prueba de montaje",
+	"warn.block_action":        "Compruebe los datos del volumen nuevo con las herramientas de la propia aplicación antes de confiar en él",
+	"warn.thaw_failed":         "El sistema de ficheros del pod %s sigue congelado: %v",
+	"warn.thaw_action":         "Descongélelo ya, pues la aplicación no puede escribir en él hasta entonces:",
+	"warn.metrics_failed":      "No se enviaron las métricas finales al Pushgateway: %v",
+	"warn.metrics_action":      "Revise la URL del Pushgateway; las métricas de esta ejecución se han perdido",
+	"warn.textfile_failed":     "No se escribieron las métricas finales para el textfile collector: %v",
+	"warn.textfile_action":     "Compruebe que el directorio existe y se puede escribir en él",
+	"warn.profile_failed":      "No se escribieron los perfiles: %v",
+	"warn.profile_action":      "Compruebe que --profile-dir existe y se puede escribir en él",
+	"warn.event_failed":        "No se publicó el evento de ciclo de vida '%s': %v",
+	"warn.event_action":        "Revise el topic de SNS / bus de EventBridge y los permisos IAM; la automatización no recibió este evento",
+	"warn.journal_failed":      "No se escribió el diario de migración %s: %v",
+	"warn.journal_action":      "Compruebe que el namespace existe y que se pueden crear y actualizar configmaps en él; el registro de esta ejecución en el clúster está incompleto",
+	"warn.state_failed":        "Los resultados no se registraron en el archivo de estado %s: %v",
+	"warn.state_action":        "Corrija el archivo antes de reanudar: los PVCs de este lote se vuelven a planificar y se omiten si ya están en su zona de destino",
+	"warn.vault_failed":        "No se hizo copia del volumen %s en el almacén de AWS Backup %s: %v",
+	"warn.vault_action":        "El snapshot de la migración es el único punto de restauración; revise el almacén, backupRoleArn y los permisos backup:StartBackupJob e iam:PassRole, y haga la copia del volumen a mano",
+}
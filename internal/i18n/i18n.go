@@ -0,0 +1,102 @@
+// Package i18n provides a minimal message catalog for user-facing strings.
+// Messages are looked up by key in the active locale, falling back to English
+// and finally to the key itself, then formatted with fmt.Sprintf.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Supported locales
+const (
+	English = "en"
+	Spanish = "es"
+)
+
+var catalogs = map[string]map[string]string{
+	English: catalogEN,
+	Spanish: catalogES,
+}
+
+var (
+	mu     sync.RWMutex
+	locale = English
+)
+
+// Supported returns the supported locale codes, sorted
+func Supported() []string {
+	codes := make([]string, 0, len(catalogs))
+	for code := range catalogs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Normalize reduces a locale such as "es_ES.UTF-8" or "es-MX" to its language code
+func Normalize(l string) string {
+	l = strings.ToLower(strings.TrimSpace(l))
+	if i := strings.IndexAny(l, "_-.@"); i >= 0 {
+		l = l[:i]
+	}
+	return l
+}
+
+// SetLocale selects the catalog used by T
+func SetLocale(l string) error {
+	code := Normalize(l)
+	if _, ok := catalogs[code]; !ok {
+		return fmt.Errorf("unsupported locale '%s': must be one of %s", l, strings.Join(Supported(), ", "))
+	}
+	mu.Lock()
+	locale = code
+	mu.Unlock()
+	return nil
+}
+
+// Locale returns the active locale code
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Detect returns the locale to use: the configured one if set, otherwise the first
+// supported language from LC_ALL, LC_MESSAGES or LANG, otherwise English
+func Detect(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if _, ok := catalogs[Normalize(v)]; ok {
+			return Normalize(v)
+		}
+		// The first variable that is set wins, as with setlocale(3)
+		break
+	}
+	return English
+}
+
+// T returns the message for key in the active locale, formatted with args
+func T(key string, args ...any) string {
+	mu.RLock()
+	msg, ok := catalogs[locale][key]
+	mu.RUnlock()
+	if !ok {
+		if msg, ok = catalogEN[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "en", want: "en"},
+		{in: "es_ES.UTF-8", want: "es"},
+		{in: "es-MX", want: "es"},
+		{in: " ES ", want: "es"},
+		{in: "C.UTF-8", want: "c"},
+		{in: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, Normalize(tc.in))
+		})
+	}
+}
+
+// TestLocale is not parallel: it changes the package-wide locale
+func TestLocale(t *testing.T) {
+	defer func() { _ = SetLocale(English) }()
+
+	assert.Equal(t, "MIGRATION PLAN", T("plan.title"))
+	assert.Equal(t, "PVCs to Process (3):", T("plan.pvcs_to_process", 3))
+
+	require.NoError(t, SetLocale("es_ES.UTF-8"))
+	assert.Equal(t, Spanish, Locale())
+	assert.Equal(t, "PLAN DE MIGRACIÓN", T("plan.title"))
+	assert.Equal(t, "PVCs a procesar (3):", T("plan.pvcs_to_process", 3))
+	assert.Equal(t, "missing.key", T("missing.key"), "unknown keys render as the key")
+
+	err := SetLocale("fr")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "en, es")
+	assert.Equal(t, Spanish, Locale(), "a rejected locale leaves the current one")
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured string
+		env        map[string]string
+		want       string
+	}{
+		{name: "configured_wins", configured: "es", env: map[string]string{"LANG": "en_US.UTF-8"}, want: "es"},
+		{name: "lang", env: map[string]string{"LANG": "es_AR.UTF-8"}, want: "es"},
+		{name: "lc_all_overrides_lang", env: map[string]string{"LC_ALL": "en_GB.UTF-8", "LANG": "es_ES.UTF-8"}, want: "en"},
+		{name: "unsupported_falls_back", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: "en"},
+		{name: "nothing_set", want: "en"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(k, tc.env[k])
+			}
+			assert.Equal(t, tc.want, Detect(tc.configured))
+		})
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	t.Parallel()
+
+	for code, catalog := range catalogs {
+		for key, en := range catalogEN {
+			msg, ok := catalog[key]
+			if !assert.True(t, ok, "%s is missing %s", code, key) {
+				continue
+			}
+			assert.Equal(t, strings.Count(en, "%"), strings.Count(msg, "%"), "%s: %s has different format verbs", code, key)
+		}
+	}
+}
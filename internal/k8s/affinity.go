@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClaimWorkload is a Deployment or StatefulSet whose pods mount PVCs
+type ClaimWorkload struct {
+	Kind         string // "Deployment" or "StatefulSet"
+	Name         string
+	Claims       []string          // Of the PVCs asked about, those its pods mount
+	Annotations  map[string]string // Of the workload
+	NodeSelector map[string]string // Of its pod template
+}
+
+// ClaimWorkloads returns the Deployments and StatefulSets in the namespace whose
+// pods mount one of the PVCs, through a volume of their pod template or, for
+// StatefulSets, one of their volume claim templates. Workloads scaled to zero are
+// included, as they are scaled back up after the run.
+func (c *Client) ClaimWorkloads(ctx context.Context, namespace string, pvcNames []string) ([]ClaimWorkload, error) {
+	wanted := make(map[string]bool, len(pvcNames))
+	for _, name := range pvcNames {
+		wanted[name] = true
+	}
+
+	var workloads []ClaimWorkload
+	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, deploy := range deployments.Items {
+		if claims := templateClaims(deploy.Spec.Template.Spec, wanted); len(claims) > 0 {
+			workloads = append(workloads, ClaimWorkload{
+				Kind: "Deployment", Name: deploy.Name, Claims: claims,
+				Annotations: deploy.Annotations, NodeSelector: deploy.Spec.Template.Spec.NodeSelector,
+			})
+		}
+	}
+
+	statefulsets, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, sts := range statefulsets.Items {
+		claims := templateClaims(sts.Spec.Template.Spec, wanted)
+		for _, tmpl := range sts.Spec.VolumeClaimTemplates {
+			// The claims of a template are named <template>-<statefulset>-<ordinal>
+			prefix := tmpl.Name + "-" + sts.Name + "-"
+			for _, name := range pvcNames {
+				if ordinal, ok := strings.CutPrefix(name, prefix); ok && isOrdinal(ordinal) {
+					claims = append(claims, name)
+				}
+			}
+		}
+		if len(claims) > 0 {
+			workloads = append(workloads, ClaimWorkload{
+				Kind: "StatefulSet", Name: sts.Name, Claims: claims,
+				Annotations: sts.Annotations, NodeSelector: sts.Spec.Template.Spec.NodeSelector,
+			})
+		}
+	}
+	return workloads, nil
+}
+
+// templateClaims returns the wanted PVCs a pod template mounts
+func templateClaims(spec corev1.PodSpec, wanted map[string]bool) []string {
+	var claims []string
+	for _, vol := range spec.Volumes {
+		if vol.PersistentVolumeClaim != nil && wanted[vol.PersistentVolumeClaim.ClaimName] {
+			claims = append(claims, vol.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// isOrdinal tells whether s is the ordinal of a StatefulSet pod
+func isOrdinal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SetWorkloadZone pins the pods of a Deployment or StatefulSet to the zone with a
+// nodeSelector on its pod template, which rolls its pods out again
+func (c *Client) SetWorkloadZone(ctx context.Context, namespace, kind, name, zone string) error {
+	slog.Info("k8s: pinning workload to zone", "namespace", namespace, "kind", kind, "name", name, "zone", zone)
+	switch kind {
+	case "Deployment":
+		deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+		setZoneSelector(&deploy.Spec.Template.Spec, zone)
+		if _, err := c.clientset.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to pin deployment %s to %s: %w", name, zone, err)
+		}
+	case "StatefulSet":
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get statefulset %s: %w", name, err)
+		}
+		setZoneSelector(&sts.Spec.Template.Spec, zone)
+		if _, err := c.clientset.AppsV1().StatefulSets(namespace).Update(ctx, sts, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to pin statefulset %s to %s: %w", name, zone, err)
+		}
+	default:
+		return fmt.Errorf("cannot pin %s %s to a zone", kind, name)
+	}
+	return nil
+}
+
+// setZoneSelector adds the zone to the nodeSelector of a pod template
+func setZoneSelector(spec *corev1.PodSpec, zone string) {
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = make(map[string]string)
+	}
+	spec.NodeSelector[corev1.LabelTopologyZone] = zone
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// claimTemplate returns a pod template mounting the claims
+func claimTemplate(claims ...string) corev1.PodTemplateSpec {
+	var tmpl corev1.PodTemplateSpec
+	for _, claim := range claims {
+		tmpl.Spec.Volumes = append(tmpl.Spec.Volumes, corev1.Volume{
+			Name:         claim,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+	}
+	return tmpl
+}
+
+func TestClient_ClaimWorkloads(t *testing.T) {
+	t.Parallel()
+
+	c := NewClientWithInterface(fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Template: claimTemplate("uploads", "cache")},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Template: claimTemplate("logs")},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+			},
+		},
+	), nil)
+
+	workloads, err := c.ClaimWorkloads(context.Background(), "shop", []string{"uploads", "data-db-0", "data-db-1", "data-dbx-0", "data-db-a"})
+	require.NoError(t, err)
+	assert.Equal(t, []ClaimWorkload{
+		{Kind: "Deployment", Name: "web", Claims: []string{"uploads"}},
+		{Kind: "StatefulSet", Name: "db", Claims: []string{"data-db-0", "data-db-1"}},
+	}, workloads)
+}
+
+func TestClient_SetWorkloadZone(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpl := claimTemplate("data")
+	tmpl.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	clientset := fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Template: tmpl}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}},
+	)
+	c := NewClientWithInterface(clientset, nil)
+
+	require.NoError(t, c.SetWorkloadZone(ctx, "shop", "Deployment", "web", "eu-west-1a"))
+	require.NoError(t, c.SetWorkloadZone(ctx, "shop", "StatefulSet", "db", "eu-west-1a"))
+	require.Error(t, c.SetWorkloadZone(ctx, "shop", KindRollout, "canary", "eu-west-1a"))
+
+	deploy, err := clientset.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", corev1.LabelTopologyZone: "eu-west-1a"}, deploy.Spec.Template.Spec.NodeSelector)
+	sts, err := clientset.AppsV1().StatefulSets("shop").Get(ctx, "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{corev1.LabelTopologyZone: "eu-west-1a"}, sts.Spec.Template.Spec.NodeSelector)
+}
@@ -0,0 +1,243 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Ways of keeping ArgoCD from syncing an application during a run
+const (
+	// ArgoCDStrategyPolicy removes spec.syncPolicy.automated, the default
+	ArgoCDStrategyPolicy = "policy"
+	// ArgoCDStrategySyncWindow adds a deny sync window for the application to its
+	// AppProject, leaving the Application itself untouched
+	ArgoCDStrategySyncWindow = "sync-window"
+	// ArgoCDStrategyAnnotation sets ArgoCDSkipReconcileAnnotation on the Application
+	ArgoCDStrategyAnnotation = "annotation"
+)
+
+// ArgoCDSkipReconcileAnnotation stops ArgoCD from reconciling an Application
+const ArgoCDSkipReconcileAnnotation = "argocd.argoproj.io/skip-reconcile"
+
+// Schedule and duration of the deny window added for an application. It opens
+// every minute for a day, so it stays in force until it is removed.
+const (
+	argoCDWindowSchedule = "* * * * *"
+	argoCDWindowDuration = "24h"
+)
+
+// argoCDProjectGVR returns the GroupVersionResource for ArgoCD AppProjects
+func argoCDProjectGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "appprojects",
+	}
+}
+
+// argoCDAppSetGVR returns the GroupVersionResource for ArgoCD ApplicationSets
+func argoCDAppSetGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "applicationsets",
+	}
+}
+
+// ArgoCDAppSetCreateOnly is the applicationsSync policy under which an
+// ApplicationSet creates applications but no longer updates or deletes them
+const ArgoCDAppSetCreateOnly = "create-only"
+
+// ApplicationSetInfo stores information about an ArgoCD ApplicationSet
+type ApplicationSetInfo struct {
+	Name      string
+	Namespace string
+	// ApplicationsSync is spec.syncPolicy.applicationsSync before the run,
+	// restored afterwards; empty when it was not set
+	ApplicationsSync string
+}
+
+// owningApplicationSet returns the name of the ApplicationSet that owns the
+// application, if any
+func owningApplicationSet(app *unstructured.Unstructured) string {
+	for _, ref := range app.GetOwnerReferences() {
+		if ref.Kind == "ApplicationSet" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// UsesApplicationSet tells whether the application is owned by an ApplicationSet
+// that reverts the changes its strategy makes to it. A deny sync window is on the
+// AppProject, so it is not affected.
+func (a ArgoCDAppInfo) UsesApplicationSet() bool {
+	return a.ApplicationSet != "" && a.Strategy != ArgoCDStrategySyncWindow
+}
+
+// FindApplicationSets returns the ApplicationSets that own the applications and
+// would revert the changes made to them. Those already create-only leave their
+// applications alone and are left out.
+func (c *Client) FindApplicationSets(ctx context.Context, apps []ArgoCDAppInfo) ([]ApplicationSetInfo, error) {
+	var sets []ApplicationSetInfo
+	seen := make(map[string]bool)
+	for _, app := range apps {
+		key := app.Namespace + "/" + app.ApplicationSet
+		if !app.UsesApplicationSet() || seen[key] {
+			continue
+		}
+		seen[key] = true
+		obj, err := c.dynamicClient.Resource(argoCDAppSetGVR()).Namespace(app.Namespace).Get(ctx, app.ApplicationSet, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ArgoCD ApplicationSet %s: %w", key, err)
+		}
+		policy, _, _ := unstructured.NestedString(obj.Object, "spec", "syncPolicy", "applicationsSync")
+		if policy == ArgoCDAppSetCreateOnly {
+			continue
+		}
+		sets = append(sets, ApplicationSetInfo{Name: app.ApplicationSet, Namespace: app.Namespace, ApplicationsSync: policy})
+	}
+	return sets, nil
+}
+
+// PauseApplicationSets makes the ApplicationSets create-only, so they do not
+// revert the changes made to their applications during the run
+func (c *Client) PauseApplicationSets(ctx context.Context, sets []ApplicationSetInfo) error {
+	for _, set := range sets {
+		slog.Info("k8s: pausing ArgoCD ApplicationSet", "namespace", set.Namespace, "applicationSet", set.Name)
+		if err := c.setApplicationsSync(ctx, set, ArgoCDAppSetCreateOnly); err != nil {
+			return fmt.Errorf("failed to pause ArgoCD ApplicationSet %s/%s: %w", set.Namespace, set.Name, err)
+		}
+	}
+	return nil
+}
+
+// ResumeApplicationSets restores the applicationsSync policy the ApplicationSets
+// had before the run
+func (c *Client) ResumeApplicationSets(ctx context.Context, sets []ApplicationSetInfo) error {
+	for _, set := range sets {
+		slog.Info("k8s: resuming ArgoCD ApplicationSet", "namespace", set.Namespace, "applicationSet", set.Name)
+		if err := c.setApplicationsSync(ctx, set, set.ApplicationsSync); err != nil {
+			return fmt.Errorf("failed to resume ArgoCD ApplicationSet %s/%s: %w", set.Namespace, set.Name, err)
+		}
+	}
+	return nil
+}
+
+// setApplicationsSync sets spec.syncPolicy.applicationsSync of an ApplicationSet,
+// or removes it when policy is empty
+func (c *Client) setApplicationsSync(ctx context.Context, set ApplicationSetInfo, policy string) error {
+	resource := c.dynamicClient.Resource(argoCDAppSetGVR()).Namespace(set.Namespace)
+	obj, err := resource.Get(ctx, set.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if policy == "" {
+		unstructured.RemoveNestedField(obj.Object, "spec", "syncPolicy", "applicationsSync")
+	} else if err := unstructured.SetNestedField(obj.Object, policy, "spec", "syncPolicy", "applicationsSync"); err != nil {
+		return err
+	}
+	_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// pauseArgoCDApp keeps ArgoCD from syncing the application with the sync-window
+// or annotation strategy
+func (c *Client) pauseArgoCDApp(ctx context.Context, app ArgoCDAppInfo) error {
+	if app.Strategy == ArgoCDStrategySyncWindow {
+		return c.updateSyncWindows(ctx, app, func(windows []interface{}) []interface{} {
+			return AddDenyWindow(windows, app.Name)
+		})
+	}
+	return c.setSkipReconcile(ctx, app, true)
+}
+
+// resumeArgoCDApp undoes pauseArgoCDApp
+func (c *Client) resumeArgoCDApp(ctx context.Context, app ArgoCDAppInfo) error {
+	if app.Strategy == ArgoCDStrategySyncWindow {
+		return c.updateSyncWindows(ctx, app, func(windows []interface{}) []interface{} {
+			return RemoveDenyWindow(windows, app.Name)
+		})
+	}
+	return c.setSkipReconcile(ctx, app, false)
+}
+
+// updateSyncWindows rewrites the sync windows of the application's AppProject,
+// found in the namespace of the application
+func (c *Client) updateSyncWindows(ctx context.Context, app ArgoCDAppInfo, update func([]interface{}) []interface{}) error {
+	resource := c.dynamicClient.Resource(argoCDProjectGVR()).Namespace(app.Namespace)
+	project, err := resource.Get(ctx, app.ProjectName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ArgoCD project %s/%s: %w", app.Namespace, app.ProjectName(), err)
+	}
+	windows, _, _ := unstructured.NestedSlice(project.Object, "spec", "syncWindows")
+	windows = update(windows)
+	if len(windows) == 0 {
+		unstructured.RemoveNestedField(project.Object, "spec", "syncWindows")
+	} else if err := unstructured.SetNestedSlice(project.Object, windows, "spec", "syncWindows"); err != nil {
+		return fmt.Errorf("failed to set the sync windows of ArgoCD project %s: %w", app.ProjectName(), err)
+	}
+	if _, err := resource.Update(ctx, project, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ArgoCD project %s/%s: %w", app.Namespace, app.ProjectName(), err)
+	}
+	return nil
+}
+
+// AddDenyWindow returns the sync windows of an AppProject with a deny window for
+// the application added
+func AddDenyWindow(windows []interface{}, appName string) []interface{} {
+	return append(windows, map[string]interface{}{
+		"kind":         "deny",
+		"schedule":     argoCDWindowSchedule,
+		"duration":     argoCDWindowDuration,
+		"applications": []interface{}{appName},
+	})
+}
+
+// RemoveDenyWindow returns the sync windows of an AppProject without the deny
+// window AddDenyWindow added for the application
+func RemoveDenyWindow(windows []interface{}, appName string) []interface{} {
+	return slices.DeleteFunc(windows, func(w interface{}) bool {
+		window, _ := w.(map[string]interface{})
+		return isDenyWindowFor(window, appName)
+	})
+}
+
+// isDenyWindowFor tells whether a sync window is the one added for the application
+func isDenyWindowFor(window map[string]interface{}, appName string) bool {
+	if window["kind"] != "deny" || window["schedule"] != argoCDWindowSchedule || window["duration"] != argoCDWindowDuration {
+		return false
+	}
+	apps, _, _ := unstructured.NestedStringSlice(window, "applications")
+	return len(apps) == 1 && apps[0] == appName
+}
+
+// setSkipReconcile sets or removes the skip-reconcile annotation of an application
+func (c *Client) setSkipReconcile(ctx context.Context, app ArgoCDAppInfo, skip bool) error {
+	resource := c.dynamicClient.Resource(argoCDAppGVR()).Namespace(app.Namespace)
+	obj, err := resource.Get(ctx, app.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ArgoCD app %s/%s: %w", app.Namespace, app.Name, err)
+	}
+	annotations := obj.GetAnnotations()
+	if skip {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[ArgoCDSkipReconcileAnnotation] = "true"
+	} else {
+		delete(annotations, ArgoCDSkipReconcileAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+	if _, err := resource.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ArgoCD app %s/%s: %w", app.Namespace, app.Name, err)
+	}
+	slog.Debug("k8s: ArgoCD skip-reconcile annotation updated", "namespace", app.Namespace, "app", app.Name, "skip", skip)
+	return nil
+}
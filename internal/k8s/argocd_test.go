@@ -0,0 +1,198 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// argoCDApplication returns an ArgoCD Application with auto-sync deploying to
+// the destination namespace, with the annotations
+func argoCDApplication(name, destination string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"project":     "shop",
+			"destination": map[string]interface{}{"namespace": destination},
+			"syncPolicy":  map[string]interface{}{"automated": map[string]interface{}{"selfHeal": true}},
+		},
+	}}
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+// argoCDProject returns an ArgoCD AppProject with the sync windows
+func argoCDProject(name string, windows ...interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{}
+	if len(windows) > 0 {
+		spec["syncWindows"] = windows
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "AppProject",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "argocd"},
+		"spec":       spec,
+	}}
+}
+
+// argoCDClient returns a client whose dynamic client holds the Applications and
+// AppProjects
+func argoCDClient(objects ...runtime.Object) *Client {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			argoCDAppGVR():     "ApplicationList",
+			argoCDProjectGVR(): "AppProjectList",
+			argoCDAppSetGVR():  "ApplicationSetList",
+		}, objects...)
+	return NewClientWithInterface(fake.NewSimpleClientset(), dynamicClient) //nolint:staticcheck // NewClientset requires apply configurations
+}
+
+func TestClient_FindArgoCDAppsForNamespace_SkipReconcile(t *testing.T) {
+	t.Parallel()
+
+	c := argoCDClient(
+		argoCDApplication("web", "shop", nil),
+		argoCDApplication("paused", "shop", map[string]string{ArgoCDSkipReconcileAnnotation: "true"}),
+	)
+
+	apps, err := c.FindArgoCDAppsForNamespace(context.Background(), "shop", []string{"argocd"})
+	require.NoError(t, err)
+	require.Len(t, apps, 1, "applications ArgoCD does not reconcile are left alone")
+	assert.Equal(t, "web", apps[0].Name)
+	assert.Equal(t, "shop", apps[0].ProjectName())
+}
+
+func TestClient_ArgoCDStrategies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	existing := map[string]interface{}{"kind": "allow", "schedule": "0 22 * * *", "duration": "1h", "applications": []interface{}{"*"}}
+
+	cases := []struct {
+		name     string
+		strategy string
+		check    func(t *testing.T, app, project *unstructured.Unstructured)
+	}{
+		{
+			name:     "sync_window",
+			strategy: ArgoCDStrategySyncWindow,
+			check: func(t *testing.T, app, project *unstructured.Unstructured) {
+				windows, _, _ := unstructured.NestedSlice(project.Object, "spec", "syncWindows")
+				require.Len(t, windows, 2)
+				assert.True(t, isDenyWindowFor(windows[1].(map[string]interface{}), "web"))
+				_, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+				assert.True(t, found, "the application is not changed")
+			},
+		},
+		{
+			name:     "annotation",
+			strategy: ArgoCDStrategyAnnotation,
+			check: func(t *testing.T, app, _ *unstructured.Unstructured) {
+				assert.Equal(t, "true", app.GetAnnotations()[ArgoCDSkipReconcileAnnotation])
+				_, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+				assert.True(t, found, "the automated policy is kept")
+			},
+		},
+		{
+			name:     "policy",
+			strategy: ArgoCDStrategyPolicy,
+			check: func(t *testing.T, app, _ *unstructured.Unstructured) {
+				_, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+				assert.False(t, found)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := argoCDClient(argoCDApplication("web", "shop", nil), argoCDProject("shop", existing))
+			apps, err := c.FindArgoCDAppsForNamespace(ctx, "shop", []string{"argocd"})
+			require.NoError(t, err)
+			require.Len(t, apps, 1)
+			apps[0].Strategy = tc.strategy
+
+			get := func() (*unstructured.Unstructured, *unstructured.Unstructured) {
+				app, err := c.dynamicClient.Resource(argoCDAppGVR()).Namespace("argocd").Get(ctx, "web", metav1.GetOptions{})
+				require.NoError(t, err)
+				project, err := c.dynamicClient.Resource(argoCDProjectGVR()).Namespace("argocd").Get(ctx, "shop", metav1.GetOptions{})
+				require.NoError(t, err)
+				return app, project
+			}
+
+			require.NoError(t, c.DisableArgoCDAutoSync(ctx, apps))
+			app, project := get()
+			tc.check(t, app, project)
+
+			require.NoError(t, c.EnableArgoCDAutoSync(ctx, apps))
+			app, project = get()
+			assert.Equal(t, argoCDApplication("web", "shop", nil).Object["spec"], app.Object["spec"])
+			assert.NotContains(t, app.GetAnnotations(), ArgoCDSkipReconcileAnnotation)
+			assert.Equal(t, argoCDProject("shop", existing).Object["spec"], project.Object["spec"], "only the window added is removed")
+		})
+	}
+}
+
+func TestClient_ApplicationSets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	owned := argoCDApplication("web", "shop", nil)
+	owned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet", Name: "shops", UID: "1"}})
+	frozen := argoCDApplication("api", "shop", nil)
+	frozen.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet", Name: "frozen", UID: "2"}})
+	appSet := func(name, policy string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "ApplicationSet",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "argocd"},
+			"spec":       map[string]interface{}{"syncPolicy": map[string]interface{}{"preserveResourcesOnDeletion": true}},
+		}}
+		if policy != "" {
+			require.NoError(t, unstructured.SetNestedField(obj.Object, policy, "spec", "syncPolicy", "applicationsSync"))
+		}
+		return obj
+	}
+	c := argoCDClient(owned, frozen, argoCDApplication("db", "shop", nil), appSet("shops", ""), appSet("frozen", ArgoCDAppSetCreateOnly))
+
+	apps, err := c.FindArgoCDAppsForNamespace(ctx, "shop", []string{"argocd"})
+	require.NoError(t, err)
+	owners := make(map[string]string)
+	for _, app := range apps {
+		owners[app.Name] = app.ApplicationSet
+	}
+	assert.Equal(t, map[string]string{"web": "shops", "api": "frozen", "db": ""}, owners)
+
+	sets, err := c.FindApplicationSets(ctx, apps)
+	require.NoError(t, err)
+	assert.Equal(t, []ApplicationSetInfo{{Name: "shops", Namespace: "argocd"}}, sets, "create-only ApplicationSets are left alone")
+
+	for i := range apps {
+		apps[i].Strategy = ArgoCDStrategySyncWindow
+	}
+	sets, err = c.FindApplicationSets(ctx, apps)
+	require.NoError(t, err)
+	assert.Empty(t, sets, "sync windows are not reverted by ApplicationSets")
+
+	require.NoError(t, c.PauseApplicationSets(ctx, []ApplicationSetInfo{{Name: "shops", Namespace: "argocd"}}))
+	obj, err := c.dynamicClient.Resource(argoCDAppSetGVR()).Namespace("argocd").Get(ctx, "shops", metav1.GetOptions{})
+	require.NoError(t, err)
+	policy, _, _ := unstructured.NestedString(obj.Object, "spec", "syncPolicy", "applicationsSync")
+	assert.Equal(t, ArgoCDAppSetCreateOnly, policy)
+
+	require.NoError(t, c.ResumeApplicationSets(ctx, []ApplicationSetInfo{{Name: "shops", Namespace: "argocd"}}))
+	obj, err = c.dynamicClient.Resource(argoCDAppSetGVR()).Namespace("argocd").Get(ctx, "shops", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, appSet("shops", "").Object["spec"], obj.Object["spec"])
+}
@@ -0,0 +1,41 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ClaimManifests returns the PVC and its PV as a YAML stream, as kubectl get -o
+// yaml prints them but without their managed fields, so what cleanup deletes can
+// be inspected or recreated later. A PV that no longer exists is left out.
+func (c *Client) ClaimManifests(ctx context.Context, namespace, pvcName, pvName string) ([]byte, error) {
+	pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PVC %s/%s: %w", namespace, pvcName, err)
+	}
+	pvc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"}
+	pvc.ManagedFields = nil
+	manifests, err := yaml.Marshal(pvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal PVC %s/%s: %w", namespace, pvcName, err)
+	}
+
+	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return manifests, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PV %s: %w", pvName, err)
+	}
+	pv.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolume"}
+	pv.ManagedFields = nil
+	doc, err := yaml.Marshal(pv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal PV %s: %w", pvName, err)
+	}
+	return append(append(manifests, "---\n"...), doc...), nil
+}
@@ -0,0 +1,38 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClaimManifests(t *testing.T) {
+	t.Parallel()
+
+	pvc := newPVC("db", "data", "pv-data", "10Gi")
+	pvc.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+	client := newTestClient(pvc, newCSIPV("pv-data", "vol-1"))
+	ctx := context.Background()
+
+	manifests, err := client.ClaimManifests(ctx, "db", "data", "pv-data")
+	require.NoError(t, err)
+	docs := strings.Split(string(manifests), "---\n")
+	require.Len(t, docs, 2)
+	assert.Contains(t, docs[0], "kind: PersistentVolumeClaim")
+	assert.Contains(t, docs[0], "volumeName: pv-data")
+	assert.NotContains(t, docs[0], "managedFields")
+	assert.Contains(t, docs[1], "kind: PersistentVolume\n")
+	assert.Contains(t, docs[1], "volumeHandle: vol-1")
+
+	// A PV already deleted is left out
+	manifests, err = client.ClaimManifests(ctx, "db", "data", "pv-gone")
+	require.NoError(t, err)
+	assert.NotContains(t, string(manifests), "---")
+
+	_, err = client.ClaimManifests(ctx, "db", "missing", "pv-data")
+	require.ErrorContains(t, err, "failed to get PVC db/missing")
+}
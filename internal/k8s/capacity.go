@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/pager"
+)
+
+// Resources is an amount of CPU, in millicores, and memory, in bytes
+type Resources struct {
+	CPU    int64
+	Memory int64
+}
+
+// Add returns the sum of r and o
+func (r Resources) Add(o Resources) Resources {
+	return Resources{CPU: r.CPU + o.CPU, Memory: r.Memory + o.Memory}
+}
+
+// ZoneCapacity is what the healthy nodes of a zone, those Ready and
+// schedulable, can run and what the pods on them request
+type ZoneCapacity struct {
+	Zone        string
+	Nodes       int // Healthy nodes; 0 when every node of the zone is cordoned or not Ready
+	Allocatable Resources
+	Requested   Resources
+	// ByNamespace is what the pods of each namespace request on the zone's
+	// healthy nodes
+	ByNamespace map[string]Resources
+}
+
+// ZoneCapacities returns the capacity of every zone with nodes, sorted by zone.
+// Pods that finished are not counted.
+func (c *Client) ZoneCapacities(ctx context.Context) ([]ZoneCapacity, error) {
+	slog.Info("k8s: listing nodes and pods for zone capacity")
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	zones := make(map[string]*ZoneCapacity)
+	nodeZones := make(map[string]string) // Healthy nodes only
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		zone := node.Labels[corev1.LabelTopologyZone]
+		if zone == "" {
+			continue
+		}
+		capacity, ok := zones[zone]
+		if !ok {
+			capacity = &ZoneCapacity{Zone: zone, ByNamespace: make(map[string]Resources)}
+			zones[zone] = capacity
+		}
+		if node.Spec.Unschedulable || !nodeReady(node) {
+			continue
+		}
+		capacity.Nodes++
+		capacity.Allocatable = capacity.Allocatable.Add(Resources{
+			CPU:    node.Status.Allocatable.Cpu().MilliValue(),
+			Memory: node.Status.Allocatable.Memory().Value(),
+		})
+		nodeZones[node.Name] = zone
+	}
+
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().Pods("").List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	err = p.EachListItem(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"}, func(obj runtime.Object) error {
+		pod := obj.(*corev1.Pod)
+		zone, ok := nodeZones[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return nil
+		}
+		requests := podRequests(pod)
+		capacity := zones[zone]
+		capacity.Requested = capacity.Requested.Add(requests)
+		capacity.ByNamespace[pod.Namespace] = capacity.ByNamespace[pod.Namespace].Add(requests)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	result := make([]ZoneCapacity, 0, len(zones))
+	for _, capacity := range zones {
+		result = append(result, *capacity)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Zone < result[j].Zone })
+	return result, nil
+}
+
+// nodeReady reports whether the node's Ready condition is True
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podRequests returns what the scheduler reserves for a pod: the sum of its
+// containers' requests, or the largest request of an init container if higher
+func podRequests(pod *corev1.Pod) Resources {
+	var total Resources
+	for _, container := range pod.Spec.Containers {
+		total = total.Add(Resources{
+			CPU:    container.Resources.Requests.Cpu().MilliValue(),
+			Memory: container.Resources.Requests.Memory().Value(),
+		})
+	}
+	for _, container := range pod.Spec.InitContainers {
+		total.CPU = max(total.CPU, container.Resources.Requests.Cpu().MilliValue())
+		total.Memory = max(total.Memory, container.Resources.Requests.Memory().Value())
+	}
+	return total
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func readyNode(name, zone, cpu, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	node := newNode(name, zone, false)
+	node.Status = corev1.NodeStatus{
+		Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+		Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+	}
+	return node
+}
+
+func requestingPod(namespace, name, node, cpu, memory string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+			}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestClient_ZoneCapacities(t *testing.T) {
+	t.Parallel()
+
+	cordoned := readyNode("node-b2", "eu-west-1b", "4", "16Gi", true)
+	cordoned.Spec.Unschedulable = true
+	withInit := requestingPod("db", "migrate-0", "node-a1", "100m", "128Mi", corev1.PodRunning)
+	withInit.Spec.InitContainers = []corev1.Container{{Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}}}
+
+	client := newTestClient(
+		readyNode("node-a1", "eu-west-1a", "4", "16Gi", true),
+		readyNode("node-a2", "eu-west-1a", "2", "8Gi", true),
+		readyNode("node-b1", "eu-west-1b", "4", "16Gi", false),
+		cordoned,
+		readyNode("node-c1", "eu-west-1c", "8", "32Gi", true),
+		newNode("node-unlabelled", "", false),
+		requestingPod("db", "db-0", "node-a1", "1", "2Gi", corev1.PodRunning),
+		requestingPod("web", "web-0", "node-a2", "250m", "512Mi", corev1.PodPending),
+		requestingPod("web", "done", "node-a2", "2", "4Gi", corev1.PodSucceeded),
+		requestingPod("web", "web-1", "node-b1", "1", "1Gi", corev1.PodRunning),
+		requestingPod("web", "unscheduled", "", "1", "1Gi", corev1.PodPending),
+		withInit,
+	)
+
+	capacities, err := client.ZoneCapacities(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ZoneCapacity{
+		{
+			Zone:        "eu-west-1a",
+			Nodes:       2,
+			Allocatable: Resources{CPU: 6000, Memory: 24 << 30},
+			Requested:   Resources{CPU: 1750, Memory: (2<<30 + 512<<20 + 128<<20)},
+			ByNamespace: map[string]Resources{
+				"db":  {CPU: 1500, Memory: 2<<30 + 128<<20},
+				"web": {CPU: 250, Memory: 512 << 20},
+			},
+		},
+		{Zone: "eu-west-1b", ByNamespace: map[string]Resources{}},
+		{Zone: "eu-west-1c", Nodes: 1, Allocatable: Resources{CPU: 8000, Memory: 32 << 30}, ByNamespace: map[string]Resources{}},
+	}, capacities)
+}
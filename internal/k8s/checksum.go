@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// LabelChecksum marks the pods ClaimChecksum creates
+const LabelChecksum = "pvc-migrator/checksum"
+
+const checksumPrefix = "pvc-migrator-checksum-"
+
+// checksumScript hashes the path and content of every regular file on the volume,
+// in a stable order, into one SHA-256 written to the termination log
+var checksumScript = fmt.Sprintf(
+	"cd %s && find . -xdev -type f -exec sha256sum {} + | LC_ALL=C sort -k 2 | sha256sum | cut -d ' ' -f 1 > /dev/termination-log",
+	warmupMountPath)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ClaimChecksum runs a pod in the zone that mounts the PVC read-only and returns
+// a SHA-256 of the path and content of every file on it. Two volumes holding the
+// same files have the same checksum, whatever their filesystem's layout. The pod
+// tolerates cordoned nodes and is deleted whatever the outcome. The PVC's
+// workloads must be scaled down, as an RWO volume can only be attached to one node.
+func (c *Client) ClaimChecksum(ctx context.Context, namespace, pvcName, zone, image string, timeout time.Duration) (string, error) {
+	sum, err := c.runClaimPod(ctx, claimPod{
+		namespace:        namespace,
+		pvcName:          pvcName,
+		zone:             zone,
+		image:            image,
+		prefix:           checksumPrefix,
+		label:            LabelChecksum,
+		script:           checksumScript,
+		readOnly:         true,
+		tolerateCordoned: true,
+	}, timeout)
+	if err != nil {
+		return "", fmt.Errorf("checksum of PVC %s: %w", pvcName, err)
+	}
+	if !sha256Pattern.MatchString(sum) {
+		return "", fmt.Errorf("checksum of PVC %s: pod returned '%s', not a SHA-256", pvcName, sum)
+	}
+	return sum, nil
+}
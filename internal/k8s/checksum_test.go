@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClient_ClaimChecksum(t *testing.T) {
+	t.Parallel()
+
+	sum := strings.Repeat("ab", 32)
+	cases := []struct {
+		name    string
+		message string
+		want    string
+		wantErr string
+	}{
+		{name: "checksum", message: sum + "\n", want: sum},
+		{name: "not_a_checksum", message: "sh: sha256sum: not found", wantErr: "pod returned 'sh: sha256sum: not found', not a SHA-256"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientset := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires apply configurations
+			var created *corev1.Pod
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				pod.Status = corev1.PodStatus{
+					Phase: corev1.PodSucceeded,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed", Message: tc.message}},
+					}},
+				}
+				created = pod.DeepCopy()
+				return false, nil, nil
+			})
+
+			got, err := NewClientWithInterface(clientset, nil).ClaimChecksum(context.Background(), "db", "data", "eu-west-1b", "", 0)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+
+			require.NotNil(t, created)
+			assert.Equal(t, "eu-west-1b", created.Spec.NodeSelector[corev1.LabelTopologyZone])
+			assert.True(t, created.Spec.Containers[0].VolumeMounts[0].ReadOnly, "the volume is only read")
+			assert.Equal(t, "true", created.Labels[LabelChecksum])
+			assert.Equal(t, corev1.TaintNodeUnschedulable, created.Spec.Tolerations[0].Key, "cordoned source nodes can run it")
+			assert.True(t, strings.HasPrefix(created.Name, checksumPrefix+"data-"))
+		})
+	}
+}
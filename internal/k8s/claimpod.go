@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// claimPod is a short-lived pod that runs a script against a PVC mounted at
+// /data, see runClaimPod
+type claimPod struct {
+	namespace string
+	pvcName   string
+	zone      string // Zone the pod is pinned to
+	image     string // DefaultWarmupImage when empty
+	prefix    string // Prefix of the pod's name
+	label     string // Label marking the pod's purpose
+	script    string // Run by sh -c
+	readOnly  bool
+	// tolerateCordoned lets the pod run on cordoned nodes, such as those of the
+	// source zone during a run with --cordon-source-nodes
+	tolerateCordoned bool
+}
+
+// claimPodName returns the name of a claim pod of a PVC
+func claimPodName(prefix, pvcName, suffix string) string {
+	return truncateName(prefix+pvcName, maxNameLength-len(suffix)-1) + "-" + suffix
+}
+
+// runClaimPod runs the pod, waits up to timeout for it to succeed and returns
+// its termination message. The pod is deleted whatever the outcome. The PVC's
+// workloads must be scaled down, as an RWO volume can only be attached to one node.
+func (c *Client) runClaimPod(ctx context.Context, p claimPod, timeout time.Duration) (string, error) {
+	image := p.image
+	if image == "" {
+		image = DefaultWarmupImage
+	}
+	deadlineSeconds := int64(timeout.Seconds()) + 1
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimPodName(p.prefix, p.pvcName, utilrand.String(5)),
+			Namespace: p.namespace,
+			Labels: map[string]string{
+				LabelManagedBy: ManagedByValue,
+				p.label:        "true",
+				LabelWarmupPVC: warmupPVCLabel(p.pvcName),
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &deadlineSeconds,
+			NodeSelector:          map[string]string{corev1.LabelTopologyZone: p.zone},
+			Containers: []corev1.Container{
+				{
+					Name:                     "check",
+					Image:                    image,
+					Command:                  []string{"sh", "-c", p.script},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					VolumeMounts:             []corev1.VolumeMount{{Name: "data", MountPath: warmupMountPath, ReadOnly: p.readOnly}},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: p.pvcName, ReadOnly: p.readOnly},
+					},
+				},
+			},
+		},
+	}
+	if p.tolerateCordoned {
+		pod.Spec.Tolerations = []corev1.Toleration{
+			{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}
+	}
+
+	slog.Info("k8s: creating claim pod", "namespace", p.namespace, "pvc", p.pvcName, "zone", p.zone, "label", p.label)
+	created, err := c.clientset.CoreV1().Pods(p.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create pod for PVC %s: %w", p.pvcName, err)
+	}
+	defer c.deleteClaimPod(context.WithoutCancel(ctx), p.namespace, created.Name)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pod, err := c.clientset.CoreV1().Pods(p.namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get pod %s: %w", created.Name, err)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			return terminationMessage(pod), nil
+		case corev1.PodFailed:
+			return "", fmt.Errorf("pod %s failed: %s", created.Name, podFailure(pod))
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("pod %s is still %s after %s: %s", created.Name, pod.Status.Phase, timeout, podFailure(pod))
+		}
+		slog.Debug("k8s: waiting for claim pod", "namespace", p.namespace, "pod", created.Name, "phase", pod.Status.Phase)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// deleteClaimPod deletes a claim pod at once; a failure is only logged, as the
+// pod has finished or is deleted by its active deadline
+func (c *Client) deleteClaimPod(ctx context.Context, namespace, name string) {
+	gracePeriod := int64(0)
+	err := c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+	if err != nil {
+		slog.Warn("k8s: failed to delete claim pod", "namespace", namespace, "pod", name, "error", err)
+	}
+}
+
+// terminationMessage returns what the pod's container wrote to its termination log
+func terminationMessage(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil {
+			return strings.TrimSpace(t.Message)
+		}
+	}
+	return ""
+}
+
+// podFailure describes why a pod has not succeeded, from its container's
+// termination or waiting state, or else its conditions and status
+func podFailure(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil {
+			return strings.TrimSpace(fmt.Sprintf("%s (exit code %d) %s", t.Reason, t.ExitCode, t.Message))
+		}
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			return strings.TrimSpace(w.Reason + " " + w.Message)
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			return cond.Message
+		}
+	}
+	if pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	return fmt.Sprintf("see kubectl describe pod %s -n %s", pod.Name, pod.Namespace)
+}
@@ -6,38 +6,86 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/pager"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
 )
 
+// tracer creates the k8s.* spans that appear under each migration step
+var tracer = otel.Tracer("github.com/cesarempathy/pv-zone-migrator/internal/k8s")
+
+// listPageSize is the number of objects fetched per request when listing PVs
+// and PVCs, so clusters with many thousands of claims are never held in one response
+const listPageSize = 500
+
 // Client wraps the Kubernetes clientset
 type Client struct {
 	clientset     kubernetes.Interface
 	dynamicClient dynamic.Interface
+	contextName   string // Kube context the client talks to, empty in tests
+	usage         *apiusage.Counter
+	executor      Executor // Runs freeze hooks in pods, see ExecHook
+	skipRollouts  bool     // Argo Rollouts are not listed, see SkipRollouts
+
+	// csiDriver creates new PVs and is recognized besides DefaultCSIDriver,
+	// see SetCSIDriver
+	csiDriver CSIDriver
 }
 
 // PVCInfo contains information about a PVC and its backing volume
 type PVCInfo struct {
 	PVName     string
+	VolumeID   string // Empty when the PV is missing
+	Capacity   string
+	CapacityGi int32
+
+	ClaimPhase corev1.PersistentVolumeClaimPhase // Lost once the PV is deleted or released
+	PVPhase    corev1.PersistentVolumePhase
+	PVMissing  bool // The PV was deleted; its EBS volume may still exist
+
+	VolumeMode corev1.PersistentVolumeMode // Of the claim; Block for raw block devices
+
+	Annotations  map[string]string // Of the claim
+	StorageClass string            // Of the claim
+}
+
+// StaticPV is a PV made by CreateStaticPV, as found by GetStaticPV
+type StaticPV struct {
+	Name       string
 	VolumeID   string
+	Zone       string // Zone of its node affinity
 	Capacity   string
 	CapacityGi int32
+	Claim      string // "namespace/name" of the claim it is bound to, empty when unbound
+
+	VolumeMode corev1.PersistentVolumeMode // Filesystem, or Block for raw block devices
 }
 
 // WorkloadInfo stores information about a scaled workload
 type WorkloadInfo struct {
-	Kind     string // "Deployment" or "StatefulSet"
+	Kind     string // "Deployment", "StatefulSet" or KindRollout
 	Name     string
 	Replicas int32
 }
@@ -46,15 +94,46 @@ type WorkloadInfo struct {
 type ArgoCDAppInfo struct {
 	Name           string
 	Namespace      string
+	Project        string          // AppProject of the application, for the sync-window strategy
 	AutoSyncPolicy json.RawMessage // Store the original automated policy for restoration
+	Strategy       string          // How auto-sync is turned off: ArgoCDStrategyPolicy (or empty), ArgoCDStrategySyncWindow or ArgoCDStrategyAnnotation
+	ApplicationSet string          // ApplicationSet that owns the application, in the same namespace; empty when none
+}
+
+// ProjectName returns the AppProject of the application, "default" when unset
+func (a ArgoCDAppInfo) ProjectName() string {
+	if a.Project == "" {
+		return "default"
+	}
+	return a.Project
+}
+
+// InClusterContext is the context name of a client using the in-cluster config,
+// for allowedContexts, deniedContexts and protectedContexts
+const InClusterContext = "in-cluster"
+
+// Impersonation is the identity the client acts as, like kubectl's --as,
+// --as-group and --as-uid. The zero value makes requests as the kubeconfig user.
+type Impersonation struct {
+	User   string
+	Groups []string
+	UID    string
 }
 
 // NewClient creates a new Kubernetes client
 // kubeContext is optional - if empty, uses the current context from kubeconfig
-func NewClient(kubeContext string) (*Client, error) {
+func NewClient(kubeContext string, as Impersonation) (*Client, error) {
+	if as.User == "" && (len(as.Groups) > 0 || as.UID != "") {
+		return nil, fmt.Errorf("impersonating groups or a UID requires a user to impersonate")
+	}
+
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
 		kubeconfig = os.Getenv("HOME") + "/.kube/config"
+		// Inside a pod without a kubeconfig, use the pod's service account
+		if _, err := os.Stat(kubeconfig); os.IsNotExist(err) && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+			return newInClusterClient(kubeContext, as)
+		}
 	}
 
 	// Build config with optional context override
@@ -63,6 +142,9 @@ func NewClient(kubeContext string) (*Client, error) {
 	if kubeContext != "" {
 		configOverrides.CurrentContext = kubeContext
 	}
+	configOverrides.AuthInfo.Impersonate = as.User
+	configOverrides.AuthInfo.ImpersonateGroups = as.Groups
+	configOverrides.AuthInfo.ImpersonateUID = as.UID
 
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 
@@ -77,6 +159,9 @@ func NewClient(kubeContext string) (*Client, error) {
 		currentContext = kubeContext
 	}
 
+	slog.Debug("loaded kubeconfig", "path", kubeconfig, "context", currentContext,
+		"as", as.User, "asGroups", as.Groups, "asUID", as.UID)
+
 	// Safety check: Warn if running against production-like contexts
 	if strings.Contains(strings.ToLower(currentContext), "prod") {
 		fmt.Printf("⚠️  WARNING: You are running against a context named '%s'.\n", currentContext)
@@ -88,6 +173,29 @@ func NewClient(kubeContext string) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
 	}
+	return newClientForConfig(config, currentContext, kubectlExecutor{kubeconfig: kubeconfig, context: currentContext, as: as})
+}
+
+// newInClusterClient creates a client that authenticates with the service
+// account of the pod it runs in. There are no contexts to choose from.
+func newInClusterClient(kubeContext string, as Impersonation) (*Client, error) {
+	if kubeContext != "" {
+		return nil, fmt.Errorf("context '%s' was requested, but there is no kubeconfig and the in-cluster config has no contexts", kubeContext)
+	}
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+	}
+	config.Impersonate = rest.ImpersonationConfig{UserName: as.User, Groups: as.Groups, UID: as.UID}
+	slog.Debug("using in-cluster config", "host", config.Host, "as", as.User, "asGroups", as.Groups, "asUID", as.UID)
+	return newClientForConfig(config, InClusterContext, kubectlExecutor{as: as})
+}
+
+func newClientForConfig(config *rest.Config, contextName string, executor Executor) (*Client, error) {
+	usage := apiusage.NewCounter()
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &countingTransport{next: rt, counter: usage}
+	})
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -102,9 +210,17 @@ func NewClient(kubeContext string) (*Client, error) {
 	return &Client{
 		clientset:     clientset,
 		dynamicClient: dynamicClient,
+		contextName:   contextName,
+		usage:         usage,
+		executor:      executor,
 	}, nil
 }
 
+// ContextName returns the kube context the client talks to
+func (c *Client) ContextName() string {
+	return c.contextName
+}
+
 // NewClientWithInterface creates a Client with a custom clientset (for testing)
 func NewClientWithInterface(clientset kubernetes.Interface, dynamicClient dynamic.Interface) *Client {
 	return &Client{
@@ -115,21 +231,157 @@ func NewClientWithInterface(clientset kubernetes.Interface, dynamicClient dynami
 
 // ListPVCs returns all PVC names in the given namespace
 func (c *Client) ListPVCs(ctx context.Context, namespace string) ([]string, error) {
-	pvcList, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	slog.Info("k8s: listing PVCs", "namespace", namespace)
+	var names []string
+	err := c.eachPVC(ctx, namespace, func(pvc *corev1.PersistentVolumeClaim) {
+		names = append(names, pvc.Name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list PVCs in namespace %s: %w", namespace, err)
 	}
 
-	names := make([]string, 0, len(pvcList.Items))
-	for _, pvc := range pvcList.Items {
-		names = append(names, pvc.Name)
+	return names, nil
+}
+
+// eachPVC calls fn for every PVC in the namespace, fetching them a page at a time
+func (c *Client) eachPVC(ctx context.Context, namespace string, fn func(*corev1.PersistentVolumeClaim)) error {
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	return p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+		fn(obj.(*corev1.PersistentVolumeClaim))
+		return nil
+	})
+}
+
+// ListNamespaces returns the names of the namespaces matching the label
+// selector, sorted. An empty selector matches every namespace.
+func (c *Client) ListNamespaces(ctx context.Context, selector string) ([]string, error) {
+	slog.Info("k8s: listing namespaces", "selector", selector)
+	nsList, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LabelNamespace adds labels to a namespace, replacing the values of any it already has
+func (c *Client) LabelNamespace(ctx context.Context, namespace string, labels map[string]string) error {
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"labels": labels}})
+	if err != nil {
+		return fmt.Errorf("failed to build label patch: %w", err)
 	}
+	slog.Info("k8s: labelling namespace", "namespace", namespace, "labels", labels)
+	if _, err := c.clientset.CoreV1().Namespaces().Patch(ctx, namespace, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// ListEBSPVCs returns the names of the PVCs bound to an EBS volume, by namespace,
+// across the whole cluster. Claims on other storage are left out.
+func (c *Client) ListEBSPVCs(ctx context.Context) (map[string][]string, error) {
+	slog.Info("k8s: listing EBS-backed PVCs in all namespaces")
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	ebs := make(map[string]bool)
+	err := p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+		if pv := obj.(*corev1.PersistentVolume); c.isEBSVolume(pv.Spec.PersistentVolumeSource) {
+			ebs[pv.Name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVs: %w", err)
+	}
+
+	byNamespace := make(map[string][]string)
+	err = c.eachPVC(ctx, metav1.NamespaceAll, func(pvc *corev1.PersistentVolumeClaim) {
+		if ebs[pvc.Spec.VolumeName] {
+			byNamespace[pvc.Namespace] = append(byNamespace[pvc.Namespace], pvc.Name)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	return byNamespace, nil
+}
 
+// ClaimedVolume is an EBS volume a claim references through its PV
+type ClaimedVolume struct {
+	PVC      string // "namespace/name" of the claim the PV is bound to
+	PVName   string
+	VolumeID string
+}
+
+// ListClaimedEBSVolumes returns the EBS-backed PVs claimed in the namespaces,
+// sorted by claim
+func (c *Client) ListClaimedEBSVolumes(ctx context.Context, namespaces []string) ([]ClaimedVolume, error) {
+	slog.Info("k8s: listing EBS-backed PVs", "namespaces", namespaces)
+	wanted := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		wanted[ns] = true
+	}
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	var volumes []ClaimedVolume
+	err := p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+		pv := obj.(*corev1.PersistentVolume)
+		ref := pv.Spec.ClaimRef
+		if ref == nil || !wanted[ref.Namespace] || !c.isEBSVolume(pv.Spec.PersistentVolumeSource) {
+			return nil
+		}
+		volumes = append(volumes, ClaimedVolume{PVC: ref.Namespace + "/" + ref.Name, PVName: pv.Name, VolumeID: c.pvVolumeID(pv)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVs: %w", err)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].PVC < volumes[j].PVC })
+	return volumes, nil
+}
+
+// VolumePVs returns the names of the PVs that reference an EBS volume, whether
+// claimed or not, sorted
+func (c *Client) VolumePVs(ctx context.Context, volumeID string) ([]string, error) {
+	slog.Info("k8s: listing PVs of volume", "volumeId", volumeID)
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	var names []string
+	err := p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+		pv := obj.(*corev1.PersistentVolume)
+		if c.isEBSVolume(pv.Spec.PersistentVolumeSource) && c.pvVolumeID(pv) == volumeID {
+			names = append(names, pv.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVs: %w", err)
+	}
+	sort.Strings(names)
 	return names, nil
 }
 
 // GetPVCInfo retrieves information about a PVC and its backing PV
-func (c *Client) GetPVCInfo(ctx context.Context, namespace, pvcName string) (*PVCInfo, error) {
+func (c *Client) GetPVCInfo(ctx context.Context, namespace, pvcName string) (_ *PVCInfo, err error) {
+	ctx, span := tracer.Start(ctx, "k8s.GetPVCInfo")
+	span.SetAttributes(attribute.String("k8s.namespace", namespace), attribute.String("k8s.pvc", pvcName))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("k8s: getting PVC", "namespace", namespace, "pvc", pvcName)
 	pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PVC %s: %w", pvcName, err)
@@ -140,27 +392,53 @@ func (c *Client) GetPVCInfo(ctx context.Context, namespace, pvcName string) (*PV
 		return nil, fmt.Errorf("PVC %s is not bound to any PV", pvcName)
 	}
 
+	capacityStr, capacityGi := quantityGi(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	info := &PVCInfo{
+		PVName:     pvName,
+		Capacity:   capacityStr,
+		CapacityGi: capacityGi,
+		ClaimPhase: pvc.Status.Phase,
+		VolumeMode: volumeMode(pvc.Spec.VolumeMode),
+
+		Annotations: pvc.Annotations,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		info.StorageClass = *pvc.Spec.StorageClassName
+	}
+
 	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if errors.IsNotFound(err) && pvc.Status.Phase == corev1.ClaimLost {
+		// A Lost claim whose PV was deleted; the caller looks for the volume in EC2
+		info.PVMissing = true
+		return info, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PV %s: %w", pvName, err)
 	}
-
-	volumeID := ""
-	if pv.Spec.CSI != nil && pv.Spec.CSI.VolumeHandle != "" {
-		volumeID = pv.Spec.CSI.VolumeHandle
-	} else if pv.Spec.AWSElasticBlockStore != nil && pv.Spec.AWSElasticBlockStore.VolumeID != "" {
-		volumeID = pv.Spec.AWSElasticBlockStore.VolumeID
-		if strings.Contains(volumeID, "/") {
-			parts := strings.Split(volumeID, "/")
-			volumeID = parts[len(parts)-1]
-		}
+	if ref := pv.Spec.ClaimRef; ref != nil && (ref.Namespace != namespace || ref.Name != pvcName) {
+		return nil, fmt.Errorf("PV %s is claimed by %s/%s, not by PVC %s", pvName, ref.Namespace, ref.Name, pvcName)
 	}
+	info.PVPhase = pv.Status.Phase
 
+	volumeID := c.pvVolumeID(pv)
 	if volumeID == "" {
 		return nil, fmt.Errorf("could not find AWS Volume ID for PV %s", pvName)
 	}
+	info.VolumeID = volumeID
+	return info, nil
+}
+
+// volumeMode returns the volume mode of a claim or PV, which is Filesystem when
+// unset
+func volumeMode(mode *corev1.PersistentVolumeMode) corev1.PersistentVolumeMode {
+	if mode == nil || *mode == "" {
+		return corev1.PersistentVolumeFilesystem
+	}
+	return *mode
+}
 
-	capacity := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+// quantityGi returns a storage size, and that size in whole GiB (at least 1)
+func quantityGi(capacity resource.Quantity) (string, int32) {
 	capacityStr := capacity.String()
 	// Safe conversion: capacity is typically in GiB range, well within int32
 	capacityBytes := capacity.Value() / (1024 * 1024 * 1024)
@@ -175,18 +453,33 @@ func (c *Client) GetPVCInfo(ctx context.Context, namespace, pvcName string) (*PV
 	if capacityGi < 1 {
 		capacityGi = 1
 	}
-
-	return &PVCInfo{
-		PVName:     pvName,
-		VolumeID:   volumeID,
-		Capacity:   capacityStr,
-		CapacityGi: capacityGi,
-	}, nil
+	return capacityStr, capacityGi
 }
 
-// CleanupResources removes old PVC and PV
-func (c *Client) CleanupResources(ctx context.Context, namespace, pvcName, pvName string) error {
+// CleanupResources removes old PVC and PV. Nothing is deleted unless the PVC is
+// still bound to the PV and the PV still references volumeID, the volume that was
+// snapshotted, so a PV recreated or rebound during the run is never deleted.
+func (c *Client) CleanupResources(ctx context.Context, namespace, pvcName, pvName, volumeID string) error {
+	ctx, span := tracer.Start(ctx, "k8s.CleanupResources")
+	span.SetAttributes(
+		attribute.String("k8s.namespace", namespace),
+		attribute.String("k8s.pvc", pvcName),
+		attribute.String("k8s.pv", pvName),
+	)
+	defer span.End()
+
 	pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err == nil && pvc.Spec.VolumeName != pvName {
+		return fmt.Errorf("PVC %s/%s is now bound to PV %s, not %s; nothing was deleted", namespace, pvcName, pvc.Spec.VolumeName, pvName)
+	}
+	pv, pvErr := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if pvErr == nil {
+		if current := c.pvVolumeID(pv); current != volumeID {
+			return fmt.Errorf("PV %s now references volume %s, not the snapshotted volume %s; nothing was deleted", pvName, current, volumeID)
+		}
+	}
+
+	slog.Info("k8s: deleting old PVC and PV", "namespace", namespace, "pvc", pvcName, "pv", pvName, "volumeId", volumeID)
 	if err == nil {
 		if len(pvc.Finalizers) > 0 {
 			pvc.Finalizers = nil
@@ -201,8 +494,7 @@ func (c *Client) CleanupResources(ctx context.Context, namespace, pvcName, pvNam
 		})
 	}
 
-	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
-	if err == nil {
+	if pvErr == nil {
 		if len(pv.Finalizers) > 0 {
 			pv.Finalizers = nil
 			_, _ = c.clientset.CoreV1().PersistentVolumes().Update(ctx, pv, metav1.UpdateOptions{})
@@ -218,14 +510,24 @@ func (c *Client) CleanupResources(ctx context.Context, namespace, pvcName, pvNam
 	return nil
 }
 
-// CreateStaticPV creates a new PersistentVolume bound to an AWS EBS volume
-func (c *Client) CreateStaticPV(ctx context.Context, pvName, volumeID, capacity, storageClass, targetZone string) error {
+// CreateStaticPV creates a new PersistentVolume bound to an AWS EBS volume. A
+// Block PV is handed to pods as a raw device, so it has no filesystem type.
+func (c *Client) CreateStaticPV(ctx context.Context, pvName, volumeID, capacity, storageClass, targetZone string, mode corev1.PersistentVolumeMode) (err error) {
+	ctx, span := tracer.Start(ctx, "k8s.CreateStaticPV")
+	span.SetAttributes(attribute.String("k8s.pv", pvName), attribute.String("ec2.volume_id", volumeID))
+	defer func() { tracing.End(span, err) }()
+
+	slog.Info("k8s: creating static PV", "pv", pvName, "volumeId", volumeID, "capacity", capacity, "zone", targetZone, "volumeMode", volumeMode(&mode))
 	capacityQuantity, err := resource.ParseQuantity(capacity)
 	if err != nil {
 		return fmt.Errorf("failed to parse capacity %s: %w", capacity, err)
 	}
 
-	filesystemMode := corev1.PersistentVolumeFilesystem
+	mode = volumeMode(&mode)
+	fsType := "ext4"
+	if mode == corev1.PersistentVolumeBlock {
+		fsType = ""
+	}
 
 	pv := &corev1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
@@ -238,14 +540,14 @@ func (c *Client) CreateStaticPV(ctx context.Context, pvName, volumeID, capacity,
 			Capacity: corev1.ResourceList{
 				corev1.ResourceStorage: capacityQuantity,
 			},
-			VolumeMode:                    &filesystemMode,
+			VolumeMode:                    &mode,
 			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
 			StorageClassName:              storageClass,
 			PersistentVolumeSource: corev1.PersistentVolumeSource{
 				CSI: &corev1.CSIPersistentVolumeSource{
-					Driver:       "ebs.csi.aws.com",
-					FSType:       "ext4",
+					Driver:       c.CSIDriver(),
+					FSType:       fsType,
 					VolumeHandle: volumeID,
 				},
 			},
@@ -271,8 +573,88 @@ func (c *Client) CreateStaticPV(ctx context.Context, pvName, volumeID, capacity,
 	return err
 }
 
-// CreateBoundPVC creates a new PVC bound to a specific PV
-func (c *Client) CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName, capacity, storageClass string) error {
+// GetStaticPV returns the PV of that name when CreateStaticPV made it, as an
+// earlier run that stopped before its claim was switched over leaves it behind,
+// or nil when there is no such PV
+func (c *Client) GetStaticPV(ctx context.Context, pvName string) (_ *StaticPV, err error) {
+	ctx, span := tracer.Start(ctx, "k8s.GetStaticPV")
+	span.SetAttributes(attribute.String("k8s.pv", pvName))
+	defer func() { tracing.End(span, err) }()
+
+	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PV %s: %w", pvName, err)
+	}
+	if pv.Labels["migrated"] != "true" {
+		return nil, nil
+	}
+
+	capacity, capacityGi := quantityGi(pv.Spec.Capacity[corev1.ResourceStorage])
+	static := &StaticPV{Name: pvName, VolumeID: c.pvVolumeID(pv), Capacity: capacity, CapacityGi: capacityGi, VolumeMode: volumeMode(pv.Spec.VolumeMode)}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		static.Claim = ref.Namespace + "/" + ref.Name
+	}
+	if affinity := pv.Spec.NodeAffinity; affinity != nil && affinity.Required != nil {
+		for _, term := range affinity.Required.NodeSelectorTerms {
+			for _, expr := range term.MatchExpressions {
+				if expr.Key == "topology.kubernetes.io/zone" && len(expr.Values) == 1 {
+					static.Zone = expr.Values[0]
+				}
+			}
+		}
+	}
+	return static, nil
+}
+
+// DeleteUnboundPV deletes a PV made by CreateStaticPV that no claim is bound to,
+// to roll back a migration that stopped before its PVC was created. The EBS
+// volume is kept, as the PV's reclaim policy is Retain.
+func (c *Client) DeleteUnboundPV(ctx context.Context, pvName string) (err error) {
+	ctx, span := tracer.Start(ctx, "k8s.DeleteUnboundPV")
+	span.SetAttributes(attribute.String("k8s.pv", pvName))
+	defer func() { tracing.End(span, err) }()
+
+	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get PV %s: %w", pvName, err)
+	}
+	if pv.Labels["migrated"] != "true" {
+		return fmt.Errorf("PV %s was not created by a migration; it was not deleted", pvName)
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		return fmt.Errorf("PV %s is claimed by %s/%s; it was not deleted", pvName, ref.Namespace, ref.Name)
+	}
+
+	slog.Info("k8s: deleting unbound PV", "pv", pvName, "volumeId", c.pvVolumeID(pv))
+	// Fails if the PV changed since it was read, e.g. because a claim was bound to it
+	err = c.clientset.CoreV1().PersistentVolumes().Delete(ctx, pvName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &pv.UID, ResourceVersion: &pv.ResourceVersion},
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// CreateBoundPVC creates a new PVC bound to a specific PV, which must have the
+// same volume mode
+func (c *Client) CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName, capacity, storageClass string, mode corev1.PersistentVolumeMode) (err error) {
+	ctx, span := tracer.Start(ctx, "k8s.CreateBoundPVC")
+	span.SetAttributes(
+		attribute.String("k8s.namespace", namespace),
+		attribute.String("k8s.pvc", pvcName),
+		attribute.String("k8s.pv", pvName),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	mode = volumeMode(&mode)
+	slog.Info("k8s: creating bound PVC", "namespace", namespace, "pvc", pvcName, "pv", pvName, "volumeMode", mode)
 	capacityQuantity, err := resource.ParseQuantity(capacity)
 	if err != nil {
 		return fmt.Errorf("failed to parse capacity %s: %w", capacity, err)
@@ -288,6 +670,7 @@ func (c *Client) CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			VolumeMode:       &mode,
 			StorageClassName: &storageClass,
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
@@ -302,8 +685,40 @@ func (c *Client) CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName,
 	return err
 }
 
-// ScaleDownWorkloads scales all Deployments and StatefulSets in the namespace to 0
-// and returns their original replica counts for later restoration
+// WaitForPVCBound waits until the PV controller has bound the claim. A claim
+// whose storage class, capacity or access modes do not match its PV stays
+// Pending, and one whose PV is gone is Lost.
+func (c *Client) WaitForPVCBound(ctx context.Context, namespace, pvcName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get PVC %s/%s: %w", namespace, pvcName, err)
+		}
+		phase := pvc.Status.Phase
+		switch phase {
+		case corev1.ClaimBound:
+			return nil
+		case corev1.ClaimLost:
+			return fmt.Errorf("PVC %s/%s is Lost: PV %s is gone", namespace, pvcName, pvc.Spec.VolumeName)
+		case "":
+			phase = corev1.ClaimPending
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("PVC %s/%s is still %s after %s; see kubectl describe pvc %s -n %s", namespace, pvcName, phase, timeout, pvcName, namespace)
+		}
+		slog.Debug("k8s: waiting for PVC to bind", "namespace", namespace, "pvc", pvcName, "phase", phase)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// ScaleDownWorkloads scales all Deployments, StatefulSets and Argo Rollouts in the
+// namespace to 0 and returns their original replica counts for later restoration
 func (c *Client) ScaleDownWorkloads(ctx context.Context, namespace string) ([]WorkloadInfo, error) {
 	var workloads []WorkloadInfo
 
@@ -322,6 +737,7 @@ func (c *Client) ScaleDownWorkloads(ctx context.Context, namespace string) ([]Wo
 			})
 
 			// Scale to 0
+			slog.Info("k8s: scaling deployment to 0", "namespace", namespace, "name", deploy.Name, "replicas", *deploy.Spec.Replicas)
 			zero := int32(0)
 			deploy.Spec.Replicas = &zero
 			_, err := c.clientset.AppsV1().Deployments(namespace).Update(ctx, &deploy, metav1.UpdateOptions{})
@@ -346,6 +762,7 @@ func (c *Client) ScaleDownWorkloads(ctx context.Context, namespace string) ([]Wo
 			})
 
 			// Scale to 0
+			slog.Info("k8s: scaling statefulset to 0", "namespace", namespace, "name", sts.Name, "replicas", *sts.Spec.Replicas)
 			zero := int32(0)
 			sts.Spec.Replicas = &zero
 			_, err := c.clientset.AppsV1().StatefulSets(namespace).Update(ctx, &sts, metav1.UpdateOptions{})
@@ -355,6 +772,19 @@ func (c *Client) ScaleDownWorkloads(ctx context.Context, namespace string) ([]Wo
 		}
 	}
 
+	// Scale down Argo Rollouts
+	rollouts, err := c.runningRollouts(ctx, namespace)
+	if err != nil {
+		return workloads, err
+	}
+	for _, rollout := range rollouts {
+		workloads = append(workloads, rollout)
+		slog.Info("k8s: scaling rollout to 0", "namespace", namespace, "name", rollout.Name, "replicas", rollout.Replicas)
+		if err := c.scaleRollout(ctx, namespace, rollout.Name, 0); err != nil {
+			return workloads, err
+		}
+	}
+
 	return workloads, nil
 }
 
@@ -378,6 +808,7 @@ func (c *Client) WaitForWorkloadsScaledDown(ctx context.Context, namespace strin
 		if runningPods == 0 {
 			return nil
 		}
+		slog.Debug("k8s: waiting for pods to terminate", "namespace", namespace, "running", runningPods)
 
 		select {
 		case <-ctx.Done():
@@ -392,6 +823,7 @@ func (c *Client) WaitForWorkloadsScaledDown(ctx context.Context, namespace strin
 // ScaleUpWorkloads restores workloads to their original replica counts
 func (c *Client) ScaleUpWorkloads(ctx context.Context, namespace string, workloads []WorkloadInfo) error {
 	for _, w := range workloads {
+		slog.Info("k8s: restoring replicas", "namespace", namespace, "kind", w.Kind, "name", w.Name, "replicas", w.Replicas)
 		switch w.Kind {
 		case "Deployment":
 			deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, w.Name, metav1.GetOptions{})
@@ -414,6 +846,11 @@ func (c *Client) ScaleUpWorkloads(ctx context.Context, namespace string, workloa
 			if err != nil {
 				return fmt.Errorf("failed to scale statefulset %s to %d: %w", w.Name, w.Replicas, err)
 			}
+
+		case KindRollout:
+			if err := c.scaleRollout(ctx, namespace, w.Name, w.Replicas); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -454,7 +891,52 @@ func (c *Client) GetWorkloadStatus(ctx context.Context, namespace string) ([]Wor
 		}
 	}
 
-	return workloads, nil
+	rollouts, err := c.runningRollouts(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return append(workloads, rollouts...), nil
+}
+
+// MountedPVCs returns the names of the PVCs in the namespace that are mounted by a
+// pod that has not finished. Claims nobody mounts can be migrated without scaling
+// any workload down.
+func (c *Client) MountedPVCs(ctx context.Context, namespace string) (map[string]bool, error) {
+	podClaims, err := c.PodClaims(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	mounted := make(map[string]bool)
+	for _, claims := range podClaims {
+		for _, claim := range claims {
+			mounted[claim] = true
+		}
+	}
+	return mounted, nil
+}
+
+// PodClaims returns the PVCs mounted by each pod in the namespace that has not
+// finished, by pod name. Pods mounting no PVC are left out.
+func (c *Client) PodClaims(ctx context.Context, namespace string) (map[string][]string, error) {
+	slog.Info("k8s: listing pods to find mounted PVCs", "namespace", namespace)
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	podClaims := make(map[string][]string)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				podClaims[pod.Name] = append(podClaims[pod.Name], vol.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	return podClaims, nil
 }
 
 // argoCDAppGVR returns the GroupVersionResource for ArgoCD Applications
@@ -478,6 +960,7 @@ func (c *Client) FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace
 	for _, ns := range argoCDNamespaces {
 		appList, err := c.dynamicClient.Resource(argoCDAppGVR()).Namespace(ns).List(ctx, metav1.ListOptions{})
 		if err != nil {
+			slog.Debug("k8s: cannot list ArgoCD applications", "namespace", ns, "error", err)
 			if errors.IsNotFound(err) {
 				continue
 			}
@@ -485,25 +968,9 @@ func (c *Client) FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace
 			continue
 		}
 
-		for _, app := range appList.Items {
-			// Check if app targets our namespace
-			destNS, found, err := unstructured.NestedString(app.Object, "spec", "destination", "namespace")
-			if err != nil || !found {
-				continue
-			}
-
-			if destNS == targetNamespace {
-				// Check if auto-sync is enabled
-				automated, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
-				if found && automated != nil {
-					// Store the automated policy for restoration
-					automatedJSON, _ := json.Marshal(automated)
-					apps = append(apps, ArgoCDAppInfo{
-						Name:           app.GetName(),
-						Namespace:      ns,
-						AutoSyncPolicy: automatedJSON,
-					})
-				}
+		for i := range appList.Items {
+			if info, ok := AutoSyncedApp(&appList.Items[i], targetNamespace); ok {
+				apps = append(apps, info)
 			}
 		}
 	}
@@ -511,9 +978,47 @@ func (c *Client) FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace
 	return apps, nil
 }
 
-// DisableArgoCDAutoSync disables auto-sync for the given ArgoCD applications
+// AutoSyncedApp returns the information of an ArgoCD Application that deploys to
+// the target namespace with auto-sync, and false for any other
+func AutoSyncedApp(app *unstructured.Unstructured, targetNamespace string) (ArgoCDAppInfo, bool) {
+	// Check if app targets our namespace
+	destNS, found, err := unstructured.NestedString(app.Object, "spec", "destination", "namespace")
+	if err != nil || !found || destNS != targetNamespace {
+		return ArgoCDAppInfo{}, false
+	}
+	// ArgoCD does not reconcile an application with skip-reconcile set
+	if app.GetAnnotations()[ArgoCDSkipReconcileAnnotation] == "true" {
+		return ArgoCDAppInfo{}, false
+	}
+
+	// Check if auto-sync is enabled
+	automated, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+	if !found || automated == nil {
+		return ArgoCDAppInfo{}, false
+	}
+	// Store the automated policy for restoration
+	automatedJSON, _ := json.Marshal(automated)
+	project, _, _ := unstructured.NestedString(app.Object, "spec", "project")
+	return ArgoCDAppInfo{
+		Name:           app.GetName(),
+		Namespace:      app.GetNamespace(),
+		Project:        project,
+		AutoSyncPolicy: automatedJSON,
+		ApplicationSet: owningApplicationSet(app),
+	}, true
+}
+
+// DisableArgoCDAutoSync disables auto-sync for the given ArgoCD applications,
+// the way their strategy says
 func (c *Client) DisableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo) error {
 	for _, appInfo := range apps {
+		slog.Info("k8s: disabling ArgoCD auto-sync", "namespace", appInfo.Namespace, "app", appInfo.Name, "strategy", appInfo.Strategy)
+		if appInfo.Strategy != "" && appInfo.Strategy != ArgoCDStrategyPolicy {
+			if err := c.pauseArgoCDApp(ctx, appInfo); err != nil {
+				return fmt.Errorf("failed to disable auto-sync for ArgoCD app %s/%s: %w", appInfo.Namespace, appInfo.Name, err)
+			}
+			continue
+		}
 		app, err := c.dynamicClient.Resource(argoCDAppGVR()).Namespace(appInfo.Namespace).Get(ctx, appInfo.Name, metav1.GetOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to get ArgoCD app %s/%s: %w", appInfo.Namespace, appInfo.Name, err)
@@ -540,6 +1045,13 @@ func (c *Client) DisableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo
 // EnableArgoCDAutoSync re-enables auto-sync for the given ArgoCD applications
 func (c *Client) EnableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo) error {
 	for _, appInfo := range apps {
+		slog.Info("k8s: re-enabling ArgoCD auto-sync", "namespace", appInfo.Namespace, "app", appInfo.Name, "strategy", appInfo.Strategy)
+		if appInfo.Strategy != "" && appInfo.Strategy != ArgoCDStrategyPolicy {
+			if err := c.resumeArgoCDApp(ctx, appInfo); err != nil {
+				return fmt.Errorf("failed to enable auto-sync for ArgoCD app %s/%s: %w", appInfo.Namespace, appInfo.Name, err)
+			}
+			continue
+		}
 		app, err := c.dynamicClient.Resource(argoCDAppGVR()).Namespace(appInfo.Namespace).Get(ctx, appInfo.Name, metav1.GetOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to get ArgoCD app %s/%s: %w", appInfo.Namespace, appInfo.Name, err)
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +64,12 @@ func newCSIPV(name, volumeHandle string) *corev1.PersistentVolume {
 	}
 }
 
+// helper to mark a PVC as Lost
+func lostPVC(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	pvc.Status.Phase = corev1.ClaimLost
+	return pvc
+}
+
 // helper to create a PV with legacy AWS EBS volume source
 func newLegacyEBSPV(name, volumeID string) *corev1.PersistentVolume {
 	return &corev1.PersistentVolume{
@@ -131,6 +138,25 @@ func newStatefulSet(namespace, name string, replicas int32) *appsv1.StatefulSet
 	}
 }
 
+func TestNewClient_ImpersonationNeedsUser(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient("", Impersonation{Groups: []string{"system:masters"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a user")
+
+	_, err = NewClient("", Impersonation{UID: "1234"})
+	require.Error(t, err)
+}
+
+func TestNewInClusterClient_RejectsContext(t *testing.T) {
+	t.Parallel()
+
+	_, err := newInClusterClient("prod", Impersonation{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "in-cluster config has no contexts")
+}
+
 func TestClient_ListPVCs(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +257,25 @@ func TestClient_GetPVCInfo(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:      "annotated_claim",
+			namespace: "default",
+			pvcName:   "tagged-pvc",
+			pvc: func() *corev1.PersistentVolumeClaim {
+				pvc := newPVC("default", "tagged-pvc", "tagged-pv", "50Gi")
+				pvc.Annotations = map[string]string{"pv-zone-migrator.io/tag-costcenter": "42"}
+				return pvc
+			}(),
+			pv: newCSIPV("tagged-pv", "vol-tagged"),
+			wantInfo: &PVCInfo{
+				PVName:      "tagged-pv",
+				VolumeID:    "vol-tagged",
+				Capacity:    "50Gi",
+				CapacityGi:  50,
+				Annotations: map[string]string{"pv-zone-migrator.io/tag-costcenter": "42"},
+			},
+			wantErr: false,
+		},
 		{
 			name:      "legacy_ebs_volume",
 			namespace: "default",
@@ -283,6 +328,55 @@ func TestClient_GetPVCInfo(t *testing.T) {
 			wantErr:     true,
 			errContains: "failed to get PV",
 		},
+		{
+			name:      "lost_pv_not_found",
+			namespace: "default",
+			pvcName:   "orphan-pvc",
+			pvc:       lostPVC(newPVC("default", "orphan-pvc", "missing-pv", "10Gi")),
+			pv:        nil,
+			wantInfo: &PVCInfo{
+				PVName:     "missing-pv",
+				Capacity:   "10Gi",
+				CapacityGi: 10,
+				ClaimPhase: corev1.ClaimLost,
+				PVMissing:  true,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "released_pv",
+			namespace: "default",
+			pvcName:   "lost-pvc",
+			pvc:       lostPVC(newPVC("default", "lost-pvc", "released-pv", "10Gi")),
+			pv: func() *corev1.PersistentVolume {
+				pv := newCSIPV("released-pv", "vol-released")
+				pv.Status.Phase = corev1.VolumeReleased
+				return pv
+			}(),
+			wantInfo: &PVCInfo{
+				PVName:     "released-pv",
+				VolumeID:   "vol-released",
+				Capacity:   "10Gi",
+				CapacityGi: 10,
+				ClaimPhase: corev1.ClaimLost,
+				PVPhase:    corev1.VolumeReleased,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "pv_claimed_by_another_pvc",
+			namespace: "default",
+			pvcName:   "lost-pvc",
+			pvc:       lostPVC(newPVC("default", "lost-pvc", "taken-pv", "10Gi")),
+			pv: func() *corev1.PersistentVolume {
+				pv := newCSIPV("taken-pv", "vol-taken")
+				pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "other", Name: "data"}
+				return pv
+			}(),
+			wantInfo:    nil,
+			wantErr:     true,
+			errContains: "PV taken-pv is claimed by other/data",
+		},
 		{
 			name:      "small_capacity",
 			namespace: "default",
@@ -329,6 +423,7 @@ func TestClient_GetPVCInfo(t *testing.T) {
 			assert.Equal(t, tc.wantInfo.VolumeID, info.VolumeID)
 			assert.Equal(t, tc.wantInfo.Capacity, info.Capacity)
 			assert.Equal(t, tc.wantInfo.CapacityGi, info.CapacityGi)
+			assert.Equal(t, tc.wantInfo.Annotations, info.Annotations)
 		})
 	}
 }
@@ -607,6 +702,173 @@ func TestClient_GetWorkloadStatus(t *testing.T) {
 	}
 }
 
+func TestClient_MountedPVCs(t *testing.T) {
+	t.Parallel()
+
+	finished := newPodWithClaim("db", "backup-1", "node-a", "backup")
+	finished.Status.Phase = corev1.PodSucceeded
+	pending := newPodWithClaim("db", "web-0", "", "cache")
+	pending.Status.Phase = corev1.PodPending
+	client := newTestClient(
+		newPodWithClaim("db", "postgres-0", "node-a", "data-postgres-0"),
+		pending,
+		finished,
+		newPodWithClaim("other", "app-0", "node-b", "logs"),
+	)
+
+	mounted, err := client.MountedPVCs(context.Background(), "db")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"data-postgres-0": true, "cache": true}, mounted)
+}
+
+func TestClient_PodClaims(t *testing.T) {
+	t.Parallel()
+
+	app := newPodWithClaim("db", "app-0", "node-a", "data")
+	app.Spec.Volumes = append(app.Spec.Volumes, corev1.Volume{
+		Name:         "logs",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "logs"}},
+	}, corev1.Volume{
+		Name:         "config",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	finished := newPodWithClaim("db", "backup-1", "node-a", "backup")
+	finished.Status.Phase = corev1.PodFailed
+	client := newTestClient(app, finished, newPodWithClaim("db", "web-0", "node-b", "cache"))
+
+	podClaims, err := client.PodClaims(context.Background(), "db")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"app-0": {"data", "logs"}, "web-0": {"cache"}}, podClaims)
+}
+
+func TestClient_ListNamespaces(t *testing.T) {
+	t.Parallel()
+
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	client := newTestClient(
+		namespace("payments-db", map[string]string{"team": "payments"}),
+		namespace("checkout", map[string]string{"team": "payments"}),
+		namespace("search", map[string]string{"team": "discovery"}),
+	)
+
+	cases := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "", want: []string{"checkout", "payments-db", "search"}},
+		{selector: "team=payments", want: []string{"checkout", "payments-db"}},
+		{selector: "team=billing", want: []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.selector, func(t *testing.T) {
+			t.Parallel()
+			got, err := client.ListNamespaces(context.Background(), tc.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestClient_LabelNamespace(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "db",
+		Labels: map[string]string{"team": "payments", LabelZone: "eu-west-1b"},
+	}})
+
+	err := client.LabelNamespace(context.Background(), "db", map[string]string{LabelZone: "eu-west-1a", LabelCompletedAt: "20261016T120000Z"})
+	require.NoError(t, err)
+
+	ns, err := client.clientset.CoreV1().Namespaces().Get(context.Background(), "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", LabelZone: "eu-west-1a", LabelCompletedAt: "20261016T120000Z"}, ns.Labels)
+
+	err = client.LabelNamespace(context.Background(), "missing", map[string]string{LabelZone: "eu-west-1a"})
+	assert.ErrorContains(t, err, "failed to label namespace missing")
+}
+
+func TestClient_ListEBSPVCs(t *testing.T) {
+	t.Parallel()
+
+	nfs := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-shared"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "efs.csi.aws.com", VolumeHandle: "fs-123"},
+			},
+		},
+	}
+	client := newTestClient(
+		newPVC("db", "data-0", "pv-data-0", "10Gi"), newCSIPV("pv-data-0", "vol-0"),
+		newPVC("db", "shared", "pv-shared", "10Gi"), nfs,
+		newPVC("legacy", "old", "pv-old", "10Gi"), newLegacyEBSPV("pv-old", "vol-1"),
+		newPVC("web", "pending", "", "10Gi"),
+	)
+
+	got, err := client.ListEBSPVCs(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"db": {"data-0"}, "legacy": {"old"}}, got)
+}
+
+func TestClient_ListClaimedEBSVolumes(t *testing.T) {
+	t.Parallel()
+
+	claimed := func(pv *corev1.PersistentVolume, namespace, claim string) *corev1.PersistentVolume {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: namespace, Name: claim}
+		return pv
+	}
+	nfs := claimed(&corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-shared"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "efs.csi.aws.com", VolumeHandle: "fs-123"},
+			},
+		},
+	}, "db", "shared")
+	client := newTestClient(
+		claimed(newCSIPV("pv-data-1", "vol-1"), "db", "data-1"),
+		claimed(newLegacyEBSPV("pv-data-0", "aws://eu-west-1a/vol-0"), "db", "data-0"),
+		claimed(newCSIPV("pv-web", "vol-2"), "web", "data"),
+		newCSIPV("pv-available", "vol-3"),
+		nfs,
+	)
+
+	got, err := client.ListClaimedEBSVolumes(context.Background(), []string{"db"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ClaimedVolume{
+		{PVC: "db/data-0", PVName: "pv-data-0", VolumeID: "vol-0"},
+		{PVC: "db/data-1", PVName: "pv-data-1", VolumeID: "vol-1"},
+	}, got)
+}
+
+func TestClient_VolumePVs(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(
+		newCSIPV("pv-data-0", "vol-1"),
+		newLegacyEBSPV("pv-legacy", "aws://eu-west-1a/vol-1"),
+		newCSIPV("pv-other", "vol-2"),
+	)
+	ctx := context.Background()
+	require.NoError(t, client.CreateStaticPV(ctx, "data-0-static", "vol-1", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+
+	got, err := client.VolumePVs(ctx, "vol-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data-0-static", "pv-data-0", "pv-legacy"}, got)
+
+	got, err = client.VolumePVs(ctx, "vol-9")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func TestClient_CreateStaticPV(t *testing.T) {
 	t.Parallel()
 
@@ -646,7 +908,7 @@ func TestClient_CreateStaticPV(t *testing.T) {
 			client := newTestClient()
 			ctx := context.Background()
 
-			err := client.CreateStaticPV(ctx, tc.pvName, tc.volumeID, tc.capacity, tc.storageClass, tc.targetZone)
+			err := client.CreateStaticPV(ctx, tc.pvName, tc.volumeID, tc.capacity, tc.storageClass, tc.targetZone, corev1.PersistentVolumeFilesystem)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -707,7 +969,7 @@ func TestClient_CreateBoundPVC(t *testing.T) {
 			client := newTestClient()
 			ctx := context.Background()
 
-			err := client.CreateBoundPVC(ctx, tc.namespace, tc.pvcName, tc.pvName, tc.capacity, tc.storageClass)
+			err := client.CreateBoundPVC(ctx, tc.namespace, tc.pvcName, tc.pvName, tc.capacity, tc.storageClass, corev1.PersistentVolumeFilesystem)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -728,6 +990,154 @@ func TestClient_CreateBoundPVC(t *testing.T) {
 	}
 }
 
+func TestClient_WaitForPVCBound(t *testing.T) {
+	t.Parallel()
+
+	claim := func(phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		pvc := newPVC("db", "data", "data-static", "10Gi")
+		pvc.Status.Phase = phase
+		return pvc
+	}
+	tests := []struct {
+		name    string
+		pvc     *corev1.PersistentVolumeClaim
+		wantErr string
+	}{
+		{name: "bound", pvc: claim(corev1.ClaimBound)},
+		{name: "pending", pvc: claim(corev1.ClaimPending), wantErr: "PVC db/data is still Pending after 0s"},
+		{name: "no_phase_yet", pvc: claim(""), wantErr: "PVC db/data is still Pending"},
+		{name: "lost", pvc: claim(corev1.ClaimLost), wantErr: "PVC db/data is Lost: PV data-static is gone"},
+		{name: "missing", pvc: newPVC("db", "other", "", "10Gi"), wantErr: "failed to get PVC db/data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newTestClient(tt.pvc).WaitForPVCBound(context.Background(), "db", "data", 0)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_DeleteUnboundPV(t *testing.T) {
+	t.Parallel()
+
+	claimed := newCSIPV("claimed-static", "vol-2")
+	claimed.Labels = map[string]string{"migrated": "true"}
+	claimed.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "default", Name: "data"}
+
+	cases := []struct {
+		name    string
+		pvName  string
+		wantErr string
+		deleted bool
+	}{
+		{name: "unbound", pvName: "data-static", deleted: true},
+		{name: "missing", pvName: "gone-static", deleted: true},
+		{name: "claimed", pvName: "claimed-static", wantErr: "claimed by default/data"},
+		{name: "not_migrated", pvName: "other", wantErr: "not created by a migration"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(claimed.DeepCopy(), newCSIPV("other", "vol-3"))
+			ctx := context.Background()
+			require.NoError(t, client.CreateStaticPV(ctx, "data-static", "vol-1", "10Gi", "gp3", "eu-west-1b", corev1.PersistentVolumeFilesystem))
+
+			err := client.DeleteUnboundPV(ctx, tc.pvName)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = client.clientset.CoreV1().PersistentVolumes().Get(ctx, tc.pvName, metav1.GetOptions{})
+			assert.Equal(t, tc.deleted, apierrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestClient_GetStaticPV(t *testing.T) {
+	t.Parallel()
+
+	claimed := newCSIPV("claimed-static", "vol-2")
+	claimed.Labels = map[string]string{"migrated": "true"}
+	claimed.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "default", Name: "claimed"}
+
+	cases := []struct {
+		name   string
+		pvName string
+		want   *StaticPV
+	}{
+		{
+			name:   "unbound",
+			pvName: "data-static",
+			want:   &StaticPV{Name: "data-static", VolumeID: "vol-1", Zone: "eu-west-1b", Capacity: "10Gi", CapacityGi: 10, VolumeMode: corev1.PersistentVolumeFilesystem},
+		},
+		{
+			name:   "claimed",
+			pvName: "claimed-static",
+			want:   &StaticPV{Name: "claimed-static", VolumeID: "vol-2", Capacity: "0", CapacityGi: 1, Claim: "default/claimed", VolumeMode: corev1.PersistentVolumeFilesystem},
+		},
+		{name: "missing", pvName: "gone-static"},
+		{name: "not_migrated", pvName: "other"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(claimed.DeepCopy(), newCSIPV("other", "vol-3"))
+			ctx := context.Background()
+			require.NoError(t, client.CreateStaticPV(ctx, "data-static", "vol-1", "10Gi", "gp3", "eu-west-1b", corev1.PersistentVolumeFilesystem))
+
+			got, err := client.GetStaticPV(ctx, tc.pvName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestClient_BlockVolumeMode(t *testing.T) {
+	t.Parallel()
+
+	block := corev1.PersistentVolumeBlock
+	pvc := newPVC("db", "raw", "pv-raw", "10Gi")
+	pvc.Spec.VolumeMode = &block
+	client := newTestClient(pvc, newCSIPV("pv-raw", "vol-1"), newPVC("db", "data", "pv-data", "10Gi"), newCSIPV("pv-data", "vol-2"))
+	ctx := context.Background()
+
+	info, err := client.GetPVCInfo(ctx, "db", "raw")
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, info.VolumeMode)
+	info, err = client.GetPVCInfo(ctx, "db", "data")
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeFilesystem, info.VolumeMode, "claims without a volume mode are Filesystem")
+
+	require.NoError(t, client.CreateStaticPV(ctx, "raw-static", "vol-3", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeBlock))
+	pv, err := client.clientset.CoreV1().PersistentVolumes().Get(ctx, "raw-static", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *pv.Spec.VolumeMode)
+	assert.Empty(t, pv.Spec.CSI.FSType, "a raw device has no filesystem")
+
+	static, err := client.GetStaticPV(ctx, "raw-static")
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, static.VolumeMode)
+
+	require.NoError(t, client.CreateBoundPVC(ctx, "db", "raw-new", "raw-static", "10Gi", "gp3", corev1.PersistentVolumeBlock))
+	created, err := client.clientset.CoreV1().PersistentVolumeClaims("db").Get(ctx, "raw-new", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *created.Spec.VolumeMode)
+}
+
 func TestClient_CleanupResources(t *testing.T) {
 	t.Parallel()
 
@@ -739,7 +1149,7 @@ func TestClient_CleanupResources(t *testing.T) {
 		client := newTestClient(pvc, pv)
 		ctx := context.Background()
 
-		err := client.CleanupResources(ctx, "default", "cleanup-pvc", "cleanup-pv")
+		err := client.CleanupResources(ctx, "default", "cleanup-pvc", "cleanup-pv", "vol-123")
 
 		require.NoError(t, err)
 
@@ -759,10 +1169,66 @@ func TestClient_CleanupResources(t *testing.T) {
 		ctx := context.Background()
 
 		// Should not error when resources don't exist
-		err := client.CleanupResources(ctx, "default", "nonexistent-pvc", "nonexistent-pv")
+		err := client.CleanupResources(ctx, "default", "nonexistent-pvc", "nonexistent-pv", "vol-123")
 
 		require.NoError(t, err)
 	})
+
+	t.Run("pv_references_another_volume", func(t *testing.T) {
+		t.Parallel()
+
+		pvc := newPVC("default", "cleanup-pvc", "cleanup-pv", "10Gi")
+		pv := newCSIPV("cleanup-pv", "vol-recreated")
+		client := newTestClient(pvc, pv)
+		ctx := context.Background()
+
+		err := client.CleanupResources(ctx, "default", "cleanup-pvc", "cleanup-pv", "vol-123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "references volume vol-recreated, not the snapshotted volume vol-123")
+
+		_, err = client.clientset.CoreV1().PersistentVolumeClaims("default").Get(ctx, "cleanup-pvc", metav1.GetOptions{})
+		require.NoError(t, err, "PVC is kept")
+		_, err = client.clientset.CoreV1().PersistentVolumes().Get(ctx, "cleanup-pv", metav1.GetOptions{})
+		require.NoError(t, err, "PV is kept")
+	})
+
+	t.Run("pvc_rebound", func(t *testing.T) {
+		t.Parallel()
+
+		pvc := newPVC("default", "cleanup-pvc", "other-pv", "10Gi")
+		pv := newCSIPV("cleanup-pv", "vol-123")
+		client := newTestClient(pvc, pv)
+
+		err := client.CleanupResources(context.Background(), "default", "cleanup-pvc", "cleanup-pv", "vol-123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "now bound to PV other-pv")
+	})
+}
+
+func TestClient_ListPVCs_Paged(t *testing.T) {
+	t.Parallel()
+
+	fakeClientset := fake.NewSimpleClientset() //nolint:staticcheck // deprecated but still functional
+	var limits []int64
+	fakeClientset.PrependReactor("list", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).GetListOptions()
+		limits = append(limits, opts.Limit)
+		page := &corev1.PersistentVolumeClaimList{}
+		if opts.Continue == "" {
+			page.Items = []corev1.PersistentVolumeClaim{*newPVC("db", "data-0", "pv-0", "1Gi")}
+			page.Continue = "page-2"
+		} else {
+			page.Items = []corev1.PersistentVolumeClaim{*newPVC("db", "data-1", "pv-1", "1Gi")}
+		}
+		return true, page, nil
+	})
+	client := NewClientWithInterface(fakeClientset, nil)
+
+	names, err := client.ListPVCs(context.Background(), "db")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data-0", "data-1"}, names)
+	assert.Equal(t, []int64{listPageSize, listPageSize}, limits)
 }
 
 func TestClient_ListPVCs_APIError(t *testing.T) {
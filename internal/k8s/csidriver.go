@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultCSIDriver is the AWS EBS CSI driver, which new PVs use unless
+// SetCSIDriver names another
+const DefaultCSIDriver = "ebs.csi.aws.com"
+
+// VolumeHandleParser returns the EBS volume ID a CSI volume handle references,
+// or an error when the handle references none
+type VolumeHandleParser func(handle string) (string, error)
+
+// CSIDriver is a CSI driver that provisions EBS volumes, such as a fork of the
+// EBS CSI driver
+type CSIDriver struct {
+	Name string
+	// ParseHandle reads the volume ID out of the volume handles of the driver's
+	// PVs; ParseVolumeHandle when nil
+	ParseHandle VolumeHandleParser
+}
+
+// ParseVolumeHandle is the VolumeHandleParser of handles that are the volume ID,
+// as the EBS CSI driver's are, or end with it as their last path segment, such
+// as aws://eu-west-1a/vol-1 or an EC2 volume ARN
+func ParseVolumeHandle(handle string) (string, error) {
+	volumeID := handle[strings.LastIndex(handle, "/")+1:]
+	if !strings.HasPrefix(volumeID, "vol-") {
+		return "", fmt.Errorf("volume handle %q does not end with an EBS volume ID", handle)
+	}
+	return volumeID, nil
+}
+
+// PatternVolumeHandleParser returns a VolumeHandleParser that finds the volume
+// ID in a handle with the regular expression: its first group, or the whole
+// match when it has none
+func PatternVolumeHandleParser(pattern string) (VolumeHandleParser, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid volume handle pattern %q: %w", pattern, err)
+	}
+	return func(handle string) (string, error) {
+		match := re.FindStringSubmatch(handle)
+		if match == nil {
+			return "", fmt.Errorf("volume handle %q does not match %q", handle, pattern)
+		}
+		volumeID := match[0]
+		if len(match) > 1 {
+			volumeID = match[1]
+		}
+		if volumeID == "" {
+			return "", fmt.Errorf("volume handle %q does not match %q", handle, pattern)
+		}
+		return volumeID, nil
+	}, nil
+}
+
+// SetCSIDriver makes the client create PVs with the driver and read the volume
+// IDs of its PVs with its parser. PVs of DefaultCSIDriver are still recognized,
+// so a cluster moving between the two drivers migrates both.
+func (c *Client) SetCSIDriver(driver CSIDriver) {
+	c.csiDriver = driver
+}
+
+// CSIDriver returns the name of the driver new PVs are created with
+func (c *Client) CSIDriver() string {
+	if c.csiDriver.Name == "" {
+		return DefaultCSIDriver
+	}
+	return c.csiDriver.Name
+}
+
+// handleParser returns the parser of the volume handles of a driver, or nil
+// when the driver does not provision EBS volumes. CreateStaticPV writes the bare
+// volume ID as the handle, so a handle the configured parser rejects is read
+// with ParseVolumeHandle too.
+func (c *Client) handleParser(driver string) VolumeHandleParser {
+	switch {
+	case driver == c.CSIDriver() && c.csiDriver.ParseHandle != nil:
+		parse := c.csiDriver.ParseHandle
+		return func(handle string) (string, error) {
+			volumeID, err := parse(handle)
+			if err != nil {
+				if id, idErr := ParseVolumeHandle(handle); idErr == nil {
+					return id, nil
+				}
+			}
+			return volumeID, err
+		}
+	case driver == c.CSIDriver() || driver == DefaultCSIDriver:
+		return ParseVolumeHandle
+	}
+	return nil
+}
+
+// isEBSVolume reports whether a PV is backed by an EBS CSI driver or the
+// in-tree EBS plugin
+func (c *Client) isEBSVolume(source corev1.PersistentVolumeSource) bool {
+	if source.CSI != nil {
+		return c.handleParser(source.CSI.Driver) != nil
+	}
+	return source.AWSElasticBlockStore != nil
+}
+
+// pvVolumeID returns the EBS volume ID a PV references, or "" when it is not
+// an EBS volume. In-tree volume IDs like aws://eu-west-1a/vol-1 are trimmed.
+func (c *Client) pvVolumeID(pv *corev1.PersistentVolume) string {
+	if pv.Spec.CSI != nil && pv.Spec.CSI.VolumeHandle != "" {
+		parse := c.handleParser(pv.Spec.CSI.Driver)
+		if parse == nil {
+			return ""
+		}
+		volumeID, err := parse(pv.Spec.CSI.VolumeHandle)
+		if err != nil {
+			slog.Warn("k8s: cannot read the volume ID of PV", "pv", pv.Name, "driver", pv.Spec.CSI.Driver, "error", err)
+			return ""
+		}
+		return volumeID
+	}
+	if pv.Spec.AWSElasticBlockStore != nil && pv.Spec.AWSElasticBlockStore.VolumeID != "" {
+		volumeID := pv.Spec.AWSElasticBlockStore.VolumeID
+		if strings.Contains(volumeID, "/") {
+			parts := strings.Split(volumeID, "/")
+			volumeID = parts[len(parts)-1]
+		}
+		return volumeID
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseVolumeHandle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handle  string
+		want    string
+		wantErr string
+	}{
+		{name: "volume ID", handle: "vol-0abc", want: "vol-0abc"},
+		{name: "zone path", handle: "aws://eu-west-1a/vol-0abc", want: "vol-0abc"},
+		{name: "ARN", handle: "arn:aws:ec2:eu-west-1:123456789012:volume/vol-0abc", want: "vol-0abc"},
+		{name: "not EBS", handle: "fs-123", wantErr: `volume handle "fs-123" does not end with an EBS volume ID`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseVolumeHandle(tt.handle)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPatternVolumeHandleParser(t *testing.T) {
+	t.Parallel()
+
+	parse, err := PatternVolumeHandleParser(`^ebs-(vol-[0-9a-f]+)@`)
+	require.NoError(t, err)
+	got, err := parse("ebs-vol-0abc@eu-west-1a")
+	require.NoError(t, err)
+	assert.Equal(t, "vol-0abc", got)
+	_, err = parse("vol-0abc")
+	assert.EqualError(t, err, `volume handle "vol-0abc" does not match "^ebs-(vol-[0-9a-f]+)@"`)
+
+	whole, err := PatternVolumeHandleParser(`vol-[0-9a-f]+`)
+	require.NoError(t, err)
+	got, err = whole("eu-west-1a:vol-0abc")
+	require.NoError(t, err)
+	assert.Equal(t, "vol-0abc", got)
+
+	_, err = PatternVolumeHandleParser(`(vol-`)
+	assert.ErrorContains(t, err, `invalid volume handle pattern "(vol-"`)
+}
+
+func TestClient_SetCSIDriver(t *testing.T) {
+	t.Parallel()
+
+	forked := newCSIPV("pv-data-0", "ebs-vol-0@eu-west-1a")
+	forked.Spec.CSI.Driver = "ebs.csi.example.com"
+	client := newTestClient(
+		newPVC("db", "data-0", "pv-data-0", "10Gi"), forked,
+		newPVC("db", "data-1", "pv-data-1", "10Gi"), newCSIPV("pv-data-1", "vol-1"),
+	)
+	assert.Equal(t, DefaultCSIDriver, client.CSIDriver())
+	_, err := client.GetPVCInfo(context.Background(), "db", "data-0")
+	require.ErrorContains(t, err, "could not find AWS Volume ID for PV pv-data-0", "other drivers are not EBS")
+
+	parse, err := PatternVolumeHandleParser(`^ebs-(vol-[0-9a-f]+)@`)
+	require.NoError(t, err)
+	client.SetCSIDriver(CSIDriver{Name: "ebs.csi.example.com", ParseHandle: parse})
+	assert.Equal(t, "ebs.csi.example.com", client.CSIDriver())
+
+	info, err := client.GetPVCInfo(context.Background(), "db", "data-0")
+	require.NoError(t, err)
+	assert.Equal(t, "vol-0", info.VolumeID)
+	info, err = client.GetPVCInfo(context.Background(), "db", "data-1")
+	require.NoError(t, err)
+	assert.Equal(t, "vol-1", info.VolumeID, "PVs of the EBS CSI driver are still recognized")
+
+	pvcs, err := client.ListEBSPVCs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"db": {"data-0", "data-1"}}, pvcs)
+
+	require.NoError(t, client.CreateStaticPV(context.Background(), "pv-new", "vol-2", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+	pv, err := client.clientset.CoreV1().PersistentVolumes().Get(context.Background(), "pv-new", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "ebs.csi.example.com", pv.Spec.CSI.Driver)
+	assert.Equal(t, "vol-2", pv.Spec.CSI.VolumeHandle)
+}
+
+func TestClient_SetCSIDriver_ReadsOwnPVs(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient()
+	parse, err := PatternVolumeHandleParser(`^ebs-(vol-[0-9a-f]+)@`)
+	require.NoError(t, err)
+	client.SetCSIDriver(CSIDriver{Name: "ebs.csi.example.com", ParseHandle: parse})
+	ctx := context.Background()
+	require.NoError(t, client.CreateStaticPV(ctx, "data-static", "vol-2", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+
+	static, err := client.GetStaticPV(ctx, "data-static")
+	require.NoError(t, err)
+	require.NotNil(t, static)
+	assert.Equal(t, "vol-2", static.VolumeID, "the bare volume ID the tool writes does not match the pattern")
+
+	pvs, err := client.VolumePVs(ctx, "vol-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data-static"}, pvs)
+}
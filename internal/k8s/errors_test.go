@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	pvs := schema.GroupResource{Resource: "persistentvolumes"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil},
+		{name: "conflict", err: apierrors.NewConflict(pvs, "data-static", errors.New("modified")), want: true},
+		{name: "wrapped server timeout", err: fmt.Errorf("create PV: %w", apierrors.NewServerTimeout(pvs, "create", 1)), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("etcd")), want: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("restarting"), want: true},
+		{name: "dropped connection", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "already exists", err: apierrors.NewAlreadyExists(pvs, "data-static")},
+		{name: "not found", err: apierrors.NewNotFound(pvs, "data-static")},
+		{name: "forbidden", err: apierrors.NewForbidden(pvs, "data-static", errors.New("rbac"))},
+		{name: "cancelled", err: context.Canceled},
+		{name: "deadline exceeded", err: fmt.Errorf("create PV: %w", context.DeadlineExceeded)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// execTimeout bounds one command run in a pod, so a hung freeze hook cannot
+// hold up the snapshot
+const execTimeout = time.Minute
+
+// Executor runs a command in a container of a running pod and returns its output
+type Executor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error)
+}
+
+// kubectlExecutor runs commands through kubectl exec, as the client's user in its
+// context, as client-go's streaming exec is not among the tool's dependencies
+type kubectlExecutor struct {
+	kubeconfig string // Empty for the in-cluster config
+	context    string // Empty for the in-cluster config
+	as         Impersonation
+}
+
+// Exec runs the command with kubectl exec
+func (e kubectlExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "kubectl", e.args(namespace, pod, container, command)...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return out.String(), fmt.Errorf("%w: %s", err, strings.TrimSpace(out.String()))
+	}
+	return out.String(), nil
+}
+
+// args returns the kubectl arguments running the command in the container
+func (e kubectlExecutor) args(namespace, pod, container string, command []string) []string {
+	var args []string
+	if e.kubeconfig != "" {
+		args = append(args, "--kubeconfig", e.kubeconfig)
+	}
+	if e.context != "" {
+		args = append(args, "--context", e.context)
+	}
+	if e.as.User != "" {
+		args = append(args, "--as", e.as.User)
+	}
+	for _, g := range e.as.Groups {
+		args = append(args, "--as-group", g)
+	}
+	if e.as.UID != "" {
+		args = append(args, "--as-uid", e.as.UID)
+	}
+	args = append(args, "exec", "-n", namespace, pod, "-c", container, "--")
+	return append(args, command...)
+}
+
+// SetExecutor replaces how commands are run in pods, kubectl exec by default
+func (c *Client) SetExecutor(e Executor) {
+	c.executor = e
+}
+
+// ClaimMount is a running container that mounts a PVC read-write
+type ClaimMount struct {
+	Pod       string
+	Container string
+	MountPath string
+}
+
+// ClaimMounts returns the running containers that mount the PVC read-write, one
+// per pod, so commands acting on the filesystem can be run in them
+func (c *Client) ClaimMounts(ctx context.Context, namespace, pvcName string) ([]ClaimMount, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var mounts []ClaimMount
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if mount, ok := claimMount(pod, pvcName); ok {
+			mounts = append(mounts, mount)
+		}
+	}
+	return mounts, nil
+}
+
+// claimMount returns the first container of the pod that mounts the PVC read-write
+func claimMount(pod *corev1.Pod, pvcName string) (ClaimMount, bool) {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil || vol.PersistentVolumeClaim.ClaimName != pvcName || vol.PersistentVolumeClaim.ReadOnly {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, vm := range container.VolumeMounts {
+				if vm.Name == vol.Name && !vm.ReadOnly {
+					return ClaimMount{Pod: pod.Name, Container: container.Name, MountPath: vm.MountPath}, true
+				}
+			}
+		}
+	}
+	return ClaimMount{}, false
+}
+
+// ExecHook runs a shell command in the container of a claim mount, with the
+// mount path as $1
+func (c *Client) ExecHook(ctx context.Context, namespace string, mount ClaimMount, command string) error {
+	if c.executor == nil {
+		return fmt.Errorf("no way to run commands in pods is configured")
+	}
+	slog.Info("k8s: running hook in pod", "namespace", namespace, "pod", mount.Pod, "container", mount.Container, "command", command)
+	_, err := c.executor.Exec(ctx, namespace, mount.Pod, mount.Container, []string{"sh", "-c", command, "sh", mount.MountPath})
+	if err != nil {
+		return fmt.Errorf("failed to run '%s' in pod %s/%s: %w", command, namespace, mount.Pod, err)
+	}
+	return nil
+}
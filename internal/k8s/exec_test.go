@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeExecutor records the commands it is asked to run
+type fakeExecutor struct {
+	calls [][]string
+	err   error
+}
+
+func (f *fakeExecutor) Exec(_ context.Context, namespace, pod, container string, command []string) (string, error) {
+	f.calls = append(f.calls, append([]string{namespace, pod, container}, command...))
+	return "", f.err
+}
+
+// mountingPod returns a pod in phase whose second container mounts the claim at /data
+func mountingPod(name, claim string, phase corev1.PodPhase, readOnly bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "db"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+			}},
+			Containers: []corev1.Container{
+				{Name: "exporter"},
+				{Name: "postgres", VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data", ReadOnly: readOnly}}},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestClient_ClaimMounts(t *testing.T) {
+	t.Parallel()
+
+	clientset := fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		mountingPod("postgres-0", "data", corev1.PodRunning, false),
+		mountingPod("backup", "data", corev1.PodRunning, true),
+		mountingPod("postgres-1", "data", corev1.PodPending, false),
+		mountingPod("other", "logs", corev1.PodRunning, false),
+	)
+
+	mounts, err := NewClientWithInterface(clientset, nil).ClaimMounts(context.Background(), "db", "data")
+	require.NoError(t, err)
+	assert.Equal(t, []ClaimMount{{Pod: "postgres-0", Container: "postgres", MountPath: "/data"}}, mounts,
+		"only running pods mounting the claim read-write are returned")
+}
+
+func TestClient_ExecHook(t *testing.T) {
+	t.Parallel()
+
+	mount := ClaimMount{Pod: "postgres-0", Container: "postgres", MountPath: "/data"}
+	cases := []struct {
+		name     string
+		executor *fakeExecutor
+		wantErr  string
+	}{
+		{name: "ok", executor: &fakeExecutor{}},
+		{name: "fails", executor: &fakeExecutor{err: errors.New("exit status 1")}, wantErr: "failed to run 'fsfreeze -f \"$1\"' in pod db/postgres-0: exit status 1"},
+		{name: "no_executor", wantErr: "no way to run commands in pods is configured"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewClientWithInterface(fake.NewSimpleClientset(), nil) //nolint:staticcheck // NewClientset requires apply configurations
+			if tc.executor != nil {
+				c.SetExecutor(tc.executor)
+			}
+			err := c.ExecHook(context.Background(), "db", mount, `fsfreeze -f "$1"`)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.executor != nil {
+				assert.Equal(t, [][]string{{"db", "postgres-0", "postgres", "sh", "-c", `fsfreeze -f "$1"`, "sh", "/data"}}, tc.executor.calls,
+					"the mount path is passed as $1")
+			}
+		})
+	}
+}
+
+func TestKubectlExecutor_Args(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		executor kubectlExecutor
+		want     []string
+	}{
+		{
+			name:     "in_cluster",
+			executor: kubectlExecutor{},
+			want:     []string{"exec", "-n", "db", "postgres-0", "-c", "postgres", "--", "sync"},
+		},
+		{
+			name:     "kubeconfig",
+			executor: kubectlExecutor{kubeconfig: "/home/ops/.kube/config", context: "prod", as: Impersonation{User: "ops", Groups: []string{"sre", "dba"}, UID: "42"}},
+			want: []string{
+				"--kubeconfig", "/home/ops/.kube/config", "--context", "prod", "--as", "ops", "--as-group", "sre", "--as-group", "dba", "--as-uid", "42",
+				"exec", "-n", "db", "postgres-0", "-c", "postgres", "--", "sync",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.executor.args("db", "postgres-0", "postgres", []string{"sync"}))
+		})
+	}
+}
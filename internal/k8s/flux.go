@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Kinds of the Flux objects suspended during a run
+const (
+	KindKustomization = "Kustomization"
+	KindHelmRelease   = "HelmRelease"
+)
+
+// DefaultFluxNamespace is where Flux keeps its objects unless told otherwise
+const DefaultFluxNamespace = "flux-system"
+
+// FluxResourceInfo stores information about a Flux Kustomization or HelmRelease
+type FluxResourceInfo struct {
+	Kind      string // KindKustomization or KindHelmRelease
+	Name      string
+	Namespace string
+}
+
+// fluxGVR returns the GroupVersionResource for a kind of Flux object
+func fluxGVR(kind string) schema.GroupVersionResource {
+	if kind == KindHelmRelease {
+		return schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}
+	}
+	return schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+}
+
+// fluxOwnerLabels are the labels Flux sets on the objects it applies, naming the
+// Kustomization or HelmRelease that applied them, by kind
+var fluxOwnerLabels = map[string][2]string{
+	KindKustomization: {"kustomize.toolkit.fluxcd.io/name", "kustomize.toolkit.fluxcd.io/namespace"},
+	KindHelmRelease:   {"helm.toolkit.fluxcd.io/name", "helm.toolkit.fluxcd.io/namespace"},
+}
+
+// FindFluxResourcesForNamespace finds the Flux Kustomizations and HelmReleases
+// that would recreate the PVCs of the target namespace: those that applied one
+// of its PVCs, found by their labels, and those in fluxNamespaces whose
+// targetNamespace it is. Suspended ones are left out, so they stay suspended.
+func (c *Client) FindFluxResourcesForNamespace(ctx context.Context, targetNamespace string, fluxNamespaces []string) ([]FluxResourceInfo, error) {
+	if c.dynamicClient == nil {
+		return nil, nil
+	}
+	if len(fluxNamespaces) == 0 {
+		fluxNamespaces = []string{DefaultFluxNamespace}
+	}
+
+	found := make(map[FluxResourceInfo]bool)
+	err := c.eachPVC(ctx, targetNamespace, func(pvc *corev1.PersistentVolumeClaim) {
+		for kind, labels := range fluxOwnerLabels {
+			name, namespace := pvc.Labels[labels[0]], pvc.Labels[labels[1]]
+			if name != "" && namespace != "" {
+				found[FluxResourceInfo{Kind: kind, Name: name, Namespace: namespace}] = true
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []FluxResourceInfo
+	for res := range found {
+		obj, err := c.dynamicClient.Resource(fluxGVR(res.Kind)).Namespace(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Flux %s %s/%s: %w", res.Kind, res.Namespace, res.Name, err)
+		}
+		if !fluxSuspended(obj) {
+			resources = append(resources, res)
+		}
+	}
+
+	for _, ns := range fluxNamespaces {
+		for _, kind := range []string{KindKustomization, KindHelmRelease} {
+			list, err := c.dynamicClient.Resource(fluxGVR(kind)).Namespace(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				// Flux or the namespace might not exist, skip
+				slog.Debug("k8s: cannot list Flux objects", "kind", kind, "namespace", ns, "error", err)
+				continue
+			}
+			for i := range list.Items {
+				obj := &list.Items[i]
+				res := FluxResourceInfo{Kind: kind, Name: obj.GetName(), Namespace: ns}
+				target, _, _ := unstructured.NestedString(obj.Object, "spec", "targetNamespace")
+				if target != targetNamespace || found[res] || fluxSuspended(obj) {
+					continue
+				}
+				found[res] = true
+				resources = append(resources, res)
+			}
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return resources, nil
+}
+
+// fluxSuspended tells whether the reconciliation of a Flux object is suspended
+func fluxSuspended(obj *unstructured.Unstructured) bool {
+	suspended, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend")
+	return suspended
+}
+
+// SuspendFlux suspends the reconciliation of the given Flux objects
+func (c *Client) SuspendFlux(ctx context.Context, resources []FluxResourceInfo) error {
+	for _, res := range resources {
+		slog.Info("k8s: suspending Flux reconciliation", "kind", res.Kind, "namespace", res.Namespace, "name", res.Name)
+		if err := c.setFluxSuspend(ctx, res, true); err != nil {
+			return fmt.Errorf("failed to suspend Flux %s %s/%s: %w", res.Kind, res.Namespace, res.Name, err)
+		}
+	}
+	return nil
+}
+
+// ResumeFlux resumes the reconciliation of the given Flux objects
+func (c *Client) ResumeFlux(ctx context.Context, resources []FluxResourceInfo) error {
+	for _, res := range resources {
+		slog.Info("k8s: resuming Flux reconciliation", "kind", res.Kind, "namespace", res.Namespace, "name", res.Name)
+		if err := c.setFluxSuspend(ctx, res, false); err != nil {
+			return fmt.Errorf("failed to resume Flux %s %s/%s: %w", res.Kind, res.Namespace, res.Name, err)
+		}
+	}
+	return nil
+}
+
+// setFluxSuspend sets spec.suspend of a Flux object, or removes it to resume
+func (c *Client) setFluxSuspend(ctx context.Context, res FluxResourceInfo, suspend bool) error {
+	resource := c.dynamicClient.Resource(fluxGVR(res.Kind)).Namespace(res.Namespace)
+	obj, err := resource.Get(ctx, res.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if suspend {
+		if err := unstructured.SetNestedField(obj.Object, true, "spec", "suspend"); err != nil {
+			return err
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "spec", "suspend")
+	}
+	_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
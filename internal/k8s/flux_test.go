@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fluxObject returns a Flux object of the kind with the spec
+func fluxObject(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	gvr := fluxGVR(kind)
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gvr.GroupVersion().String(),
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+// fluxClient returns a client holding the PVCs and the Flux objects
+func fluxClient(pvcs []runtime.Object, objects ...runtime.Object) *Client {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		fluxGVR(KindKustomization): "KustomizationList",
+		fluxGVR(KindHelmRelease):   "HelmReleaseList",
+	}, objects...)
+	return NewClientWithInterface(fake.NewSimpleClientset(pvcs...), dynamicClient) //nolint:staticcheck // NewClientset requires apply configurations
+}
+
+func TestClient_FindFluxResourcesForNamespace(t *testing.T) {
+	t.Parallel()
+
+	pvcs := []runtime.Object{
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "shop", Labels: map[string]string{
+			"kustomize.toolkit.fluxcd.io/name":      "apps",
+			"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+		}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "shop", Labels: map[string]string{
+			"helm.toolkit.fluxcd.io/name":      "redis",
+			"helm.toolkit.fluxcd.io/namespace": "shop",
+		}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "shop", Labels: map[string]string{
+			"kustomize.toolkit.fluxcd.io/name":      "gone",
+			"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+		}}},
+	}
+	c := fluxClient(pvcs,
+		fluxObject(KindKustomization, "flux-system", "apps", map[string]interface{}{"path": "./apps"}),
+		fluxObject(KindHelmRelease, "shop", "redis", map[string]interface{}{}),
+		fluxObject(KindHelmRelease, "flux-system", "shop-db", map[string]interface{}{"targetNamespace": "shop"}),
+		fluxObject(KindKustomization, "flux-system", "paused", map[string]interface{}{"targetNamespace": "shop", "suspend": true}),
+		fluxObject(KindKustomization, "flux-system", "infra", map[string]interface{}{"targetNamespace": "infra"}),
+	)
+
+	resources, err := c.FindFluxResourcesForNamespace(context.Background(), "shop", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []FluxResourceInfo{
+		{Kind: KindHelmRelease, Name: "shop-db", Namespace: "flux-system"},
+		{Kind: KindKustomization, Name: "apps", Namespace: "flux-system"},
+		{Kind: KindHelmRelease, Name: "redis", Namespace: "shop"},
+	}, resources, "suspended, missing and unrelated objects are left out")
+}
+
+func TestClient_SuspendAndResumeFlux(t *testing.T) {
+	t.Parallel()
+
+	c := fluxClient(nil, fluxObject(KindKustomization, "flux-system", "apps", map[string]interface{}{"path": "./apps"}))
+	resources := []FluxResourceInfo{{Kind: KindKustomization, Name: "apps", Namespace: "flux-system"}}
+	ctx := context.Background()
+	spec := func() map[string]interface{} {
+		obj, err := c.dynamicClient.Resource(fluxGVR(KindKustomization)).Namespace("flux-system").Get(ctx, "apps", metav1.GetOptions{})
+		require.NoError(t, err)
+		spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+		return spec
+	}
+
+	require.NoError(t, c.SuspendFlux(ctx, resources))
+	assert.Equal(t, map[string]interface{}{"path": "./apps", "suspend": true}, spec())
+	require.NoError(t, c.ResumeFlux(ctx, resources))
+	assert.Equal(t, map[string]interface{}{"path": "./apps"}, spec())
+
+	require.ErrorContains(t, c.SuspendFlux(ctx, []FluxResourceInfo{{Kind: KindHelmRelease, Name: "gone", Namespace: "shop"}}),
+		"failed to suspend Flux HelmRelease shop/gone")
+}
@@ -0,0 +1,227 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations Helm sets on the objects of a release
+const (
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// HelmReleaseInfo stores information about a Helm release that manages PVCs of a run
+type HelmReleaseInfo struct {
+	Name      string
+	Namespace string
+	Claims    []string // PVCs of the run it manages, directly or through a workload, as "namespace/pvcname"
+	Chart     string   // "name-version", from the release secret; empty when it was not read
+	Revision  int      // Of the deployed release, from the release secret
+	// Owned are the Claims the release installed itself, whose Helm ownership
+	// metadata the PVCs that replace them lack
+	Owned []string
+	// Values are the chart's defaults merged with the values the release was
+	// deployed with; nil when the release secret was not read
+	Values map[string]interface{}
+}
+
+// helmRelease is the part of a release, as Helm stores it in its secret, that is read
+type helmRelease struct {
+	Version int                    `json:"version"`
+	Config  map[string]interface{} `json:"config"`
+	Chart   struct {
+		Metadata struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"metadata"`
+		Values map[string]interface{} `json:"values"`
+	} `json:"chart"`
+}
+
+// FindHelmReleases finds the Helm releases managing the PVCs of the namespace,
+// from the annotations Helm sets on the PVCs and on the Deployments and
+// StatefulSets mounting them. With readValues, the deployed release is read from
+// its secret for its chart and values; a release stored otherwise is returned
+// without them.
+func (c *Client) FindHelmReleases(ctx context.Context, namespace string, pvcNames []string, readValues bool) ([]HelmReleaseInfo, error) {
+	wanted := make(map[string]bool, len(pvcNames))
+	for _, name := range pvcNames {
+		wanted[name] = true
+	}
+
+	// Claims of each release by namespace and name, true for those it owns
+	found := make(map[[2]string]map[string]bool)
+	add := func(annotations map[string]string, owned bool, claims ...string) {
+		name := annotations[helmReleaseNameAnnotation]
+		if name == "" {
+			return
+		}
+		ns := annotations[helmReleaseNamespaceAnnotation]
+		if ns == "" {
+			ns = namespace
+		}
+		key := [2]string{ns, name}
+		if found[key] == nil {
+			found[key] = make(map[string]bool)
+		}
+		for _, claim := range claims {
+			found[key][namespace+"/"+claim] = found[key][namespace+"/"+claim] || owned
+		}
+	}
+
+	err := c.eachPVC(ctx, namespace, func(pvc *corev1.PersistentVolumeClaim) {
+		if wanted[pvc.Name] {
+			add(pvc.Annotations, true, pvc.Name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := c.ClaimWorkloads(ctx, namespace, pvcNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workloads {
+		add(w.Annotations, false, w.Claims...)
+	}
+
+	releases := make([]HelmReleaseInfo, 0, len(found))
+	for key, claims := range found {
+		release := HelmReleaseInfo{Namespace: key[0], Name: key[1]}
+		for claim, owned := range claims {
+			release.Claims = append(release.Claims, claim)
+			if owned {
+				release.Owned = append(release.Owned, claim)
+			}
+		}
+		sort.Strings(release.Claims)
+		sort.Strings(release.Owned)
+		if readValues {
+			if err := c.readHelmRelease(ctx, &release); err != nil {
+				return nil, err
+			}
+		}
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+	return releases, nil
+}
+
+// readHelmRelease fills in the chart, revision and values of a release from the
+// secret of its deployed revision, if there is one
+func (c *Client) readHelmRelease(ctx context.Context, release *HelmReleaseInfo) error {
+	secrets, err := c.clientset.CoreV1().Secrets(release.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,status=deployed,name=" + release.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the secrets of Helm release %s/%s: %w", release.Namespace, release.Name, err)
+	}
+	var latest *corev1.Secret
+	for i := range secrets.Items {
+		version, _ := strconv.Atoi(secrets.Items[i].Labels["version"])
+		if latest == nil || version > release.Revision {
+			latest, release.Revision = &secrets.Items[i], version
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	decoded, err := decodeHelmRelease(latest.Data["release"])
+	if err != nil {
+		return fmt.Errorf("failed to decode Helm release %s/%s: %w", release.Namespace, release.Name, err)
+	}
+	release.Chart = decoded.Chart.Metadata.Name + "-" + decoded.Chart.Metadata.Version
+	release.Values = mergeValues(decoded.Chart.Values, decoded.Config)
+	return nil
+}
+
+// decodeHelmRelease decodes a release as Helm stores it in a secret: JSON,
+// gzipped, then base64 encoded
+func decodeHelmRelease(data []byte) (*helmRelease, error) {
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = reader.Close() }()
+		if raw, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+	var release helmRelease
+	if err := json.Unmarshal(raw, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// mergeValues returns the chart's default values with the overrides applied, as
+// Helm merges them: maps are merged and a null override removes the key
+func mergeValues(defaults, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		override, ok := v.(map[string]interface{})
+		base, isMap := merged[k].(map[string]interface{})
+		if ok && isMap {
+			merged[k] = mergeValues(base, override)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// HelmValuesChanges returns the values to deploy a release with after the run:
+// every storageClass or storageClassName value set to the storage class of the
+// new PVCs and, when zone is not empty, the zone added to every nodeSelector. It
+// is empty when the values have neither.
+func HelmValuesChanges(values map[string]interface{}, storageClass, zone string) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for k, v := range values {
+		switch value := v.(type) {
+		case string:
+			if (k == "storageClass" || k == "storageClassName") && value != storageClass {
+				changes[k] = storageClass
+			}
+		case map[string]interface{}:
+			if k == "nodeSelector" {
+				if zone != "" && value[corev1.LabelTopologyZone] != zone {
+					changes[k] = map[string]interface{}{corev1.LabelTopologyZone: zone}
+				}
+				continue
+			}
+			if nested := HelmValuesChanges(value, storageClass, zone); len(nested) > 0 {
+				changes[k] = nested
+			}
+		}
+	}
+	return changes
+}
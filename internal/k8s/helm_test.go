@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// helmSecret returns the secret Helm stores a revision of a release in
+func helmSecret(t *testing.T, name string, revision int, status string, values map[string]interface{}) *corev1.Secret {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"name":    name,
+		"version": revision,
+		"config":  values,
+		"chart": map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "postgresql", "version": "15.2.0"},
+			"values": map[string]interface{}{
+				"global":  map[string]interface{}{"storageClass": ""},
+				"primary": map[string]interface{}{"persistence": map[string]interface{}{"size": "8Gi"}, "nodeSelector": map[string]interface{}{}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v" + strconv.Itoa(revision),
+			Namespace: "shop",
+			Labels:    map[string]string{"owner": "helm", "name": name, "status": status, "version": strconv.Itoa(revision)},
+		},
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(gz.Bytes()))},
+	}
+}
+
+func TestClient_FindHelmReleases(t *testing.T) {
+	t.Parallel()
+
+	release := map[string]string{helmReleaseNameAnnotation: "db", helmReleaseNamespaceAnnotation: "shop"}
+	c := NewClientWithInterface(fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "uploads", Namespace: "shop", Annotations: map[string]string{helmReleaseNameAnnotation: "web"}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "shop"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "scratch", Namespace: "shop"}},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop", Annotations: release},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+			},
+		},
+		helmSecret(t, "db", 1, "superseded", nil),
+		helmSecret(t, "db", 2, "deployed", map[string]interface{}{"global": map[string]interface{}{"storageClass": "gp2"}}),
+	), nil)
+	ctx := context.Background()
+	claims := []string{"uploads", "data-db-0", "scratch"}
+
+	releases, err := c.FindHelmReleases(ctx, "shop", claims, false)
+	require.NoError(t, err)
+	assert.Equal(t, []HelmReleaseInfo{
+		{Name: "db", Namespace: "shop", Claims: []string{"shop/data-db-0"}},
+		{Name: "web", Namespace: "shop", Claims: []string{"shop/uploads"}, Owned: []string{"shop/uploads"}},
+	}, releases, "PVCs of no release are left out")
+
+	releases, err = c.FindHelmReleases(ctx, "shop", claims, true)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, "postgresql-15.2.0", releases[0].Chart)
+	assert.Equal(t, 2, releases[0].Revision)
+	assert.Equal(t, map[string]interface{}{
+		"global":  map[string]interface{}{"storageClass": "gp2"},
+		"primary": map[string]interface{}{"persistence": map[string]interface{}{"size": "8Gi"}, "nodeSelector": map[string]interface{}{}},
+	}, releases[0].Values)
+	assert.Nil(t, releases[1].Values, "a release without a secret has no values")
+}
+
+func TestHelmValuesChanges(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]interface{}{
+		"global":  map[string]interface{}{"storageClass": "gp2"},
+		"primary": map[string]interface{}{"persistence": map[string]interface{}{"storageClass": "gp3", "size": "8Gi"}, "nodeSelector": map[string]interface{}{}},
+		"image":   map[string]interface{}{"tag": "16"},
+	}
+
+	cases := []struct {
+		name string
+		zone string
+		want map[string]interface{}
+	}{
+		{
+			name: "storage_class",
+			want: map[string]interface{}{"global": map[string]interface{}{"storageClass": "gp3"}},
+		},
+		{
+			name: "zone",
+			zone: "eu-west-1a",
+			want: map[string]interface{}{
+				"global":  map[string]interface{}{"storageClass": "gp3"},
+				"primary": map[string]interface{}{"nodeSelector": map[string]interface{}{corev1.LabelTopologyZone: "eu-west-1a"}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, HelmValuesChanges(values, "gp3", tc.zone))
+		})
+	}
+}
@@ -4,6 +4,8 @@ package k8s
 import (
 	"context"
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 // API defines the interface for Kubernetes operations used by the migrator.
@@ -12,30 +14,78 @@ type API interface {
 	// ListPVCs returns all PVC names in the given namespace.
 	ListPVCs(ctx context.Context, namespace string) ([]string, error)
 
+	// ListNamespaces returns the namespaces matching a label selector.
+	ListNamespaces(ctx context.Context, selector string) ([]string, error)
+
+	// LabelNamespace adds labels to a namespace.
+	LabelNamespace(ctx context.Context, namespace string, labels map[string]string) error
+
+	// ListEBSPVCs returns the EBS-backed PVC names in every namespace.
+	ListEBSPVCs(ctx context.Context) (map[string][]string, error)
+
 	// GetPVCInfo retrieves information about a PVC and its backing PV.
 	GetPVCInfo(ctx context.Context, namespace, pvcName string) (*PVCInfo, error)
 
-	// CleanupResources removes old PVC and PV.
-	CleanupResources(ctx context.Context, namespace, pvcName, pvName string) error
+	// CleanupResources removes old PVC and PV, if the PV still references volumeID.
+	CleanupResources(ctx context.Context, namespace, pvcName, pvName, volumeID string) error
 
 	// CreateStaticPV creates a new PersistentVolume bound to an AWS EBS volume.
-	CreateStaticPV(ctx context.Context, pvName, volumeID, capacity, storageClass, targetZone string) error
+	CreateStaticPV(ctx context.Context, pvName, volumeID, capacity, storageClass, targetZone string, mode corev1.PersistentVolumeMode) error
+
+	// DeleteUnboundPV deletes a PV made by CreateStaticPV that no claim is bound to.
+	DeleteUnboundPV(ctx context.Context, pvName string) error
 
 	// CreateBoundPVC creates a new PVC bound to a specific PV.
-	CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName, capacity, storageClass string) error
+	CreateBoundPVC(ctx context.Context, namespace, pvcName, pvName, capacity, storageClass string, mode corev1.PersistentVolumeMode) error
+
+	// WaitForPVCBound waits until the PV controller has bound the claim.
+	WaitForPVCBound(ctx context.Context, namespace, pvcName string, timeout time.Duration) error
+
+	// RunMountCheck runs a pod in the zone that writes to the PVC and reads it back.
+	RunMountCheck(ctx context.Context, namespace, pvcName, zone, image string, timeout time.Duration) error
+
+	// ClaimChecksum runs a pod in the zone that hashes the files on the PVC.
+	ClaimChecksum(ctx context.Context, namespace, pvcName, zone, image string, timeout time.Duration) (string, error)
+
+	// ClaimMounts returns the running containers that mount the PVC read-write.
+	ClaimMounts(ctx context.Context, namespace, pvcName string) ([]ClaimMount, error)
+
+	// ExecHook runs a shell command in the container of a claim mount.
+	ExecHook(ctx context.Context, namespace string, mount ClaimMount, command string) error
 
-	// ScaleDownWorkloads scales all Deployments and StatefulSets in the namespace to 0.
+	// ScaleDownWorkloads scales all Deployments, StatefulSets and Argo Rollouts in the namespace to 0.
 	ScaleDownWorkloads(ctx context.Context, namespace string) ([]WorkloadInfo, error)
 
 	// WaitForWorkloadsScaledDown waits until all pods in the namespace are terminated.
 	WaitForWorkloadsScaledDown(ctx context.Context, namespace string, timeout time.Duration) error
 
+	// ClaimJobs returns the Jobs with unfinished pods that mount one of the PVCs.
+	ClaimJobs(ctx context.Context, namespace string, pvcNames []string) ([]ClaimJob, error)
+
+	// WaitForClaimJobs waits until the Jobs no longer have pods mounting the PVCs.
+	WaitForClaimJobs(ctx context.Context, namespace string, pvcNames []string, jobs []ClaimJob, timeout time.Duration) error
+
+	// DeleteJobs deletes the Jobs and their pods.
+	DeleteJobs(ctx context.Context, namespace string, jobs []ClaimJob) error
+
 	// ScaleUpWorkloads restores workloads to their original replica counts.
 	ScaleUpWorkloads(ctx context.Context, namespace string, workloads []WorkloadInfo) error
 
 	// GetWorkloadStatus returns a summary of running workloads in the namespace.
 	GetWorkloadStatus(ctx context.Context, namespace string) ([]WorkloadInfo, error)
 
+	// ClaimWorkloads returns the Deployments and StatefulSets whose pods mount one of the PVCs.
+	ClaimWorkloads(ctx context.Context, namespace string, pvcNames []string) ([]ClaimWorkload, error)
+
+	// SetWorkloadZone pins the pods of a Deployment or StatefulSet to a zone.
+	SetWorkloadZone(ctx context.Context, namespace, kind, name, zone string) error
+
+	// MountedPVCs returns the PVCs in the namespace mounted by a pod that has not finished.
+	MountedPVCs(ctx context.Context, namespace string) (map[string]bool, error)
+
+	// PodClaims returns the PVCs mounted by each pod in the namespace that has not finished.
+	PodClaims(ctx context.Context, namespace string) (map[string][]string, error)
+
 	// FindArgoCDAppsForNamespace finds ArgoCD applications targeting the given namespace.
 	FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace string, argoCDNamespaces []string) ([]ArgoCDAppInfo, error)
 
@@ -44,7 +94,57 @@ type API interface {
 
 	// EnableArgoCDAutoSync re-enables auto-sync for the given ArgoCD applications.
 	EnableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo) error
+
+	// FindFluxResourcesForNamespace finds the Flux objects that would recreate the namespace's PVCs.
+	FindFluxResourcesForNamespace(ctx context.Context, targetNamespace string, fluxNamespaces []string) ([]FluxResourceInfo, error)
+
+	// SuspendFlux suspends the reconciliation of the given Flux objects.
+	SuspendFlux(ctx context.Context, resources []FluxResourceInfo) error
+
+	// ResumeFlux resumes the reconciliation of the given Flux objects.
+	ResumeFlux(ctx context.Context, resources []FluxResourceInfo) error
+
+	// FindHelmReleases finds the Helm releases managing the PVCs of the namespace.
+	FindHelmReleases(ctx context.Context, namespace string, pvcNames []string, readValues bool) ([]HelmReleaseInfo, error)
+
+	// FindScaledObjects finds the KEDA ScaledObjects that scale the given workloads.
+	FindScaledObjects(ctx context.Context, namespace string, workloads []WorkloadInfo) ([]ScaledObjectInfo, error)
+
+	// PauseScaledObjects pauses the given KEDA ScaledObjects at zero replicas.
+	PauseScaledObjects(ctx context.Context, objects []ScaledObjectInfo) error
+
+	// ResumeScaledObjects restores the given KEDA ScaledObjects as they were.
+	ResumeScaledObjects(ctx context.Context, objects []ScaledObjectInfo) error
+
+	// CordonZoneNodes cordons the schedulable nodes of a zone and returns their names.
+	CordonZoneNodes(ctx context.Context, zone string) ([]string, error)
+
+	// UncordonNodes marks the given nodes schedulable again.
+	UncordonNodes(ctx context.Context, nodes []string) error
+
+	// WaitForPodsScheduled waits until no pod in the namespace is waiting for a node.
+	WaitForPodsScheduled(ctx context.Context, namespace string, timeout time.Duration) error
+
+	// CreateWarmupJob creates a Job that reads the PVC contents to hydrate a restored volume,
+	// pinned to the node of the pod mounting the PVC once it is running.
+	CreateWarmupJob(ctx context.Context, namespace, pvcName, image string, timeout time.Duration) (string, error)
 }
 
 // Ensure Client implements API
 var _ API = (*Client)(nil)
+
+// ArgoCD defines the ArgoCD operations of a run. Client makes them on the
+// Application resources; the argocd package makes them through argocd-server.
+type ArgoCD interface {
+	// FindArgoCDAppsForNamespace finds ArgoCD applications targeting the given namespace.
+	FindArgoCDAppsForNamespace(ctx context.Context, targetNamespace string, argoCDNamespaces []string) ([]ArgoCDAppInfo, error)
+
+	// DisableArgoCDAutoSync disables auto-sync for the given ArgoCD applications.
+	DisableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo) error
+
+	// EnableArgoCDAutoSync re-enables auto-sync for the given ArgoCD applications.
+	EnableArgoCDAutoSync(ctx context.Context, apps []ArgoCDAppInfo) error
+}
+
+// Ensure Client implements ArgoCD
+var _ ArgoCD = (*Client)(nil)
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClaimJob is a Job with pods still running that mount some of the PVCs
+type ClaimJob struct {
+	Name   string
+	Claims []string // PVCs its pods mount, sorted
+}
+
+// ClaimJobs returns the Jobs in the namespace whose pods, not finished yet,
+// mount one of the PVCs. Scaling the workloads down does not stop them, so the
+// volumes stay attached until they finish.
+func (c *Client) ClaimJobs(ctx context.Context, namespace string, pvcNames []string) ([]ClaimJob, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(pvcNames))
+	for _, name := range pvcNames {
+		wanted[name] = true
+	}
+	claims := make(map[string]map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || owner.Kind != "Job" {
+			continue
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim == nil || !wanted[vol.PersistentVolumeClaim.ClaimName] {
+				continue
+			}
+			if claims[owner.Name] == nil {
+				claims[owner.Name] = make(map[string]bool)
+			}
+			claims[owner.Name][vol.PersistentVolumeClaim.ClaimName] = true
+		}
+	}
+
+	jobs := make([]ClaimJob, 0, len(claims))
+	for name, set := range claims {
+		job := ClaimJob{Name: name}
+		for claim := range set {
+			job.Claims = append(job.Claims, claim)
+		}
+		sort.Strings(job.Claims)
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+// WaitForClaimJobs waits until none of the Jobs has a pod left that mounts one of
+// the PVCs
+func (c *Client) WaitForClaimJobs(ctx context.Context, namespace string, pvcNames []string, jobs []ClaimJob, timeout time.Duration) error {
+	waiting := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		waiting[job.Name] = true
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		current, err := c.ClaimJobs(ctx, namespace, pvcNames)
+		if err != nil {
+			return err
+		}
+		var running []string
+		for _, job := range current {
+			if waiting[job.Name] {
+				running = append(running, job.Name)
+			}
+		}
+		if len(running) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("timeout waiting for jobs to finish: %s", strings.Join(running, ", "))
+		}
+		slog.Debug("k8s: waiting for jobs to finish", "namespace", namespace, "jobs", running)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// DeleteJobs deletes the Jobs and, in the background, their pods. Jobs already
+// gone are skipped.
+func (c *Client) DeleteJobs(ctx context.Context, namespace string, jobs []ClaimJob) error {
+	propagation := metav1.DeletePropagationBackground
+	for _, job := range jobs {
+		slog.Info("k8s: deleting job", "namespace", namespace, "job", job.Name, "claims", job.Claims)
+		err := c.clientset.BatchV1().Jobs(namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
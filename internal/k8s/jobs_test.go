@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// ownedPod returns a pod in phase controlled by a kind/owner that mounts the claims
+func ownedPod(name, kind, owner string, phase corev1.PodPhase, claims ...string) *corev1.Pod {
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "shop",
+			OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &controller}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	for _, claim := range claims {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         claim,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+	}
+	return pod
+}
+
+func TestClient_ClaimJobs(t *testing.T) {
+	t.Parallel()
+
+	c := NewClientWithInterface(fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		ownedPod("report-abc", "Job", "report", corev1.PodRunning, "data", "cache"),
+		ownedPod("export-abc", "Job", "export", corev1.PodPending, "data"),
+		ownedPod("export-def", "Job", "export", corev1.PodRunning, "data"),
+		ownedPod("backup-abc", "Job", "backup", corev1.PodSucceeded, "data"),
+		ownedPod("logs-abc", "Job", "logs", corev1.PodRunning, "logs"),
+		ownedPod("web-abc", "ReplicaSet", "web", corev1.PodRunning, "data"),
+	), nil)
+
+	jobs, err := c.ClaimJobs(context.Background(), "shop", []string{"data", "cache"})
+	require.NoError(t, err)
+	assert.Equal(t, []ClaimJob{
+		{Name: "export", Claims: []string{"data"}},
+		{Name: "report", Claims: []string{"cache", "data"}},
+	}, jobs, "finished pods, other claims and other controllers are left out")
+}
+
+func TestClient_WaitForClaimJobs(t *testing.T) {
+	t.Parallel()
+
+	c := NewClientWithInterface(fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		ownedPod("report-abc", "Job", "report", corev1.PodRunning, "data"),
+		ownedPod("export-abc", "Job", "export", corev1.PodRunning, "data"),
+	), nil)
+	ctx := context.Background()
+
+	err := c.WaitForClaimJobs(ctx, "shop", []string{"data"}, []ClaimJob{{Name: "report"}}, 0)
+	require.EqualError(t, err, "timeout waiting for jobs to finish: report", "only the given jobs are waited for")
+	require.NoError(t, c.WaitForClaimJobs(ctx, "shop", []string{"data"}, []ClaimJob{{Name: "backup"}}, 0))
+}
+
+func TestClient_DeleteJobs(t *testing.T) {
+	t.Parallel()
+
+	clientset := fake.NewSimpleClientset(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "shop"}}) //nolint:staticcheck // NewClientset requires apply configurations
+	ctx := context.Background()
+
+	require.NoError(t, NewClientWithInterface(clientset, nil).DeleteJobs(ctx, "shop", []ClaimJob{{Name: "report"}, {Name: "gone"}}))
+	jobs, err := clientset.BatchV1().Jobs("shop").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelMigrationID is stamped on the journal ConfigMaps with the migration ID of
+// their run, so they can be found with a label selector
+const LabelMigrationID = "pvc-migrator/migration-id"
+
+const journalPrefix = "pvc-migrator-journal-"
+
+// invalidNameChars matches what may not appear in an object name or label value
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// JournalName returns the name of the ConfigMap keeping the journal of a run.
+// Migration IDs given by hand are lowercased and stripped of characters an object
+// name may not hold.
+func JournalName(migrationID string) string {
+	id := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(migrationID), "-"), "-.")
+	return truncateName(journalPrefix+id, maxNameLength)
+}
+
+// SaveJournal creates the journal ConfigMap of a run in the namespace, or replaces
+// the data of the one written earlier in the run
+func (c *Client) SaveJournal(ctx context.Context, namespace, migrationID string, data map[string]string) error {
+	name := JournalName(migrationID)
+	labels := map[string]string{
+		LabelManagedBy:   ManagedByValue,
+		LabelMigrationID: strings.TrimPrefix(name, journalPrefix),
+	}
+	configMaps := c.clientset.CoreV1().ConfigMaps(namespace)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Data:       data,
+	}
+	_, err := configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	if err == nil {
+		slog.Info("k8s: created migration journal", "namespace", namespace, "configMap", name)
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create journal %s/%s: %w", namespace, name, err)
+	}
+
+	existing, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get journal %s/%s: %w", namespace, name, err)
+	}
+	if existing.Labels == nil {
+		existing.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		existing.Labels[k] = v
+	}
+	existing.Data = data
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update journal %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// ListJournals returns the data of every journal ConfigMap in the namespace
+func (c *Client) ListJournals(ctx context.Context, namespace string) ([]map[string]string, error) {
+	slog.Info("k8s: listing migration journals", "namespace", namespace)
+	list, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: LabelManagedBy + "=" + ManagedByValue + "," + LabelMigrationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journals in %s: %w", namespace, err)
+	}
+	journals := make([]map[string]string, 0, len(list.Items))
+	for _, cm := range list.Items {
+		journals = append(journals, cm.Data)
+	}
+	return journals, nil
+}
+
+// GetJournal returns the data of the journal ConfigMap of a run
+func (c *Client) GetJournal(ctx context.Context, namespace, migrationID string) (map[string]string, error) {
+	name := JournalName(migrationID)
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal %s/%s: %w", namespace, name, err)
+	}
+	return cm.Data, nil
+}
+
+// WhoAmI returns the username the cluster authenticates the client as, after
+// any impersonation
+func (c *Client) WhoAmI(ctx context.Context) (string, error) {
+	review, err := c.clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review own identity: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
@@ -0,0 +1,111 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestJournalName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		migrationID string
+		want        string
+	}{
+		{
+			name:        "generated",
+			migrationID: "20261016T120000Z-0a1b",
+			want:        "pvc-migrator-journal-20261016t120000z-0a1b",
+		},
+		{
+			name:        "given_by_hand",
+			migrationID: "Q3 move_to/1a!",
+			want:        "pvc-migrator-journal-q3-move-to-1a",
+		},
+		{
+			name:        "truncated",
+			migrationID: strings.Repeat("a", 100),
+			want:        "pvc-migrator-journal-" + strings.Repeat("a", 42),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := JournalName(tc.migrationID)
+			assert.Equal(t, tc.want, got)
+			assert.Empty(t, validation.IsDNS1123Subdomain(got))
+			assert.Empty(t, validation.IsValidLabelValue(strings.TrimPrefix(got, journalPrefix)))
+		})
+	}
+}
+
+func TestClient_SaveJournal(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient()
+	ctx := context.Background()
+
+	require.NoError(t, client.SaveJournal(ctx, "ops", "20261016T120000Z-0a1b", map[string]string{"startTime": "t0"}))
+	require.NoError(t, client.SaveJournal(ctx, "ops", "20261016T120000Z-0a1b", map[string]string{"startTime": "t0", "endTime": "t1"}))
+
+	cm, err := client.clientset.CoreV1().ConfigMaps("ops").Get(ctx, "pvc-migrator-journal-20261016t120000z-0a1b", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"startTime": "t0", "endTime": "t1"}, cm.Data, "the second write replaces the data")
+	assert.Equal(t, ManagedByValue, cm.Labels[LabelManagedBy])
+	assert.Equal(t, "20261016t120000z-0a1b", cm.Labels[LabelMigrationID])
+}
+
+func TestClient_ListJournals(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient()
+	ctx := context.Background()
+	require.NoError(t, client.SaveJournal(ctx, "ops", "run-1", map[string]string{"migrationId": "run-1"}))
+	require.NoError(t, client.SaveJournal(ctx, "ops", "run-2", map[string]string{"migrationId": "run-2"}))
+	require.NoError(t, client.SaveJournal(ctx, "other", "run-3", map[string]string{"migrationId": "run-3"}))
+	_, err := client.clientset.CoreV1().ConfigMaps("ops").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Labels: map[string]string{LabelManagedBy: ManagedByValue}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	journals, err := client.ListJournals(ctx, "ops")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []map[string]string{{"migrationId": "run-1"}, {"migrationId": "run-2"}}, journals)
+
+	journal, err := client.GetJournal(ctx, "ops", "run-2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"migrationId": "run-2"}, journal)
+
+	_, err = client.GetJournal(ctx, "ops", "run-3")
+	require.Error(t, err)
+}
+
+func TestClient_WhoAmI(t *testing.T) {
+	t.Parallel()
+
+	fakeClientset := fake.NewSimpleClientset() //nolint:staticcheck // deprecated but still functional
+	fakeClientset.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authenticationv1.SelfSubjectReview{
+			Status: authenticationv1.SelfSubjectReviewStatus{UserInfo: authenticationv1.UserInfo{Username: "alice@example.com"}},
+		}, nil
+	})
+	client := NewClientWithInterface(fakeClientset, nil)
+
+	user, err := client.WhoAmI(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", user)
+}
@@ -0,0 +1,198 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Labels naming the node group of a node: EKS managed node groups, then eksctl
+// self-managed ones
+var nodeGroupLabels = []string{"eks.amazonaws.com/nodegroup", "alpha.eksctl.io/nodegroup-name"}
+
+// karpenterNodePoolGVR returns the GroupVersionResource for Karpenter NodePools
+func karpenterNodePoolGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "karpenter.sh",
+		Version:  "v1",
+		Resource: "nodepools",
+	}
+}
+
+// karpenterNodeClassGVR returns the GroupVersionResource for Karpenter EC2NodeClasses
+func karpenterNodeClassGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "karpenter.k8s.aws",
+		Version:  "v1",
+		Resource: "ec2nodeclasses",
+	}
+}
+
+// SubnetSelectorTerm is a subnetSelectorTerm of an EC2NodeClass: a subnet ID,
+// or tags the subnets carry, "*" matching any value
+type SubnetSelectorTerm struct {
+	ID   string
+	Tags map[string]string
+}
+
+// KarpenterNodePool is a Karpenter NodePool and the zones it may launch nodes in
+type KarpenterNodePool struct {
+	Name string
+	// Zones are the zones its requirements allow; nil when they do not restrict
+	// the zone. ExcludedZones are those a NotIn requirement rules out.
+	Zones         []string
+	ExcludedZones []string
+	NodeClass     string // Name of its EC2NodeClass
+	// SubnetZones are the zones of the subnets its EC2NodeClass resolved, from
+	// its status; nil when it has not resolved any, SubnetSelectors then telling
+	// which subnets it launches nodes in
+	SubnetZones     []string
+	SubnetSelectors []SubnetSelectorTerm
+}
+
+// AllowsZone reports whether the requirements of the NodePool let it launch
+// nodes in zone
+func (p KarpenterNodePool) AllowsZone(zone string) bool {
+	for _, z := range p.ExcludedZones {
+		if z == zone {
+			return false
+		}
+	}
+	if p.Zones == nil {
+		return true
+	}
+	for _, z := range p.Zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// KarpenterNodePools returns the Karpenter NodePools, sorted by name, with the
+// subnets of their EC2NodeClass. None are found when Karpenter is not installed.
+func (c *Client) KarpenterNodePools(ctx context.Context) ([]KarpenterNodePool, error) {
+	slog.Info("k8s: listing Karpenter NodePools")
+	pools, err := c.dynamicClient.Resource(karpenterNodePoolGVR()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list Karpenter NodePools: %w", err)
+	}
+
+	classes := make(map[string]*unstructured.Unstructured)
+	var result []KarpenterNodePool
+	for i := range pools.Items {
+		pool := KarpenterNodePool{Name: pools.Items[i].GetName()}
+		requirements, _, _ := unstructured.NestedSlice(pools.Items[i].Object, "spec", "template", "spec", "requirements")
+		for _, r := range requirements {
+			req, ok := r.(map[string]interface{})
+			if !ok || req["key"] != corev1.LabelTopologyZone {
+				continue
+			}
+			values, _, _ := unstructured.NestedStringSlice(req, "values")
+			switch req["operator"] {
+			case "In":
+				pool.Zones = append([]string{}, values...)
+			case "NotIn":
+				pool.ExcludedZones = values
+			case "DoesNotExist":
+				pool.Zones = []string{}
+			}
+		}
+
+		pool.NodeClass, _, _ = unstructured.NestedString(pools.Items[i].Object, "spec", "template", "spec", "nodeClassRef", "name")
+		if pool.NodeClass != "" {
+			class, ok := classes[pool.NodeClass]
+			if !ok {
+				class, err = c.dynamicClient.Resource(karpenterNodeClassGVR()).Get(ctx, pool.NodeClass, metav1.GetOptions{})
+				if err != nil && !errors.IsNotFound(err) {
+					return nil, fmt.Errorf("failed to get Karpenter EC2NodeClass %s: %w", pool.NodeClass, err)
+				}
+				classes[pool.NodeClass] = class
+			}
+			if class != nil {
+				pool.SubnetZones, pool.SubnetSelectors = nodeClassSubnets(class)
+			}
+		}
+		result = append(result, pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// nodeClassSubnets returns the zones of the subnets an EC2NodeClass resolved,
+// from its status, and its subnetSelectorTerms
+func nodeClassSubnets(class *unstructured.Unstructured) ([]string, []SubnetSelectorTerm) {
+	var zones []string
+	subnets, _, _ := unstructured.NestedSlice(class.Object, "status", "subnets")
+	seen := make(map[string]bool)
+	for _, s := range subnets {
+		subnet, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if zone, _, _ := unstructured.NestedString(subnet, "zone"); zone != "" && !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	var selectors []SubnetSelectorTerm
+	terms, _, _ := unstructured.NestedSlice(class.Object, "spec", "subnetSelectorTerms")
+	for _, t := range terms {
+		term, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(term, "id")
+		tags, _, _ := unstructured.NestedStringMap(term, "tags")
+		selectors = append(selectors, SubnetSelectorTerm{ID: id, Tags: tags})
+	}
+	return zones, selectors
+}
+
+// NodeGroupZones returns the zones each node group has nodes in, by node group,
+// from the labels EKS and eksctl set on their nodes. Nodes of no node group,
+// such as those Karpenter launches, are left out.
+func (c *Client) NodeGroupZones(ctx context.Context) (map[string][]string, error) {
+	slog.Info("k8s: listing nodes for node groups")
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, node := range nodes.Items {
+		zone := node.Labels[corev1.LabelTopologyZone]
+		for _, label := range nodeGroupLabels {
+			group := node.Labels[label]
+			if group == "" || zone == "" {
+				continue
+			}
+			if seen[group] == nil {
+				seen[group] = make(map[string]bool)
+			}
+			seen[group][zone] = true
+			break
+		}
+	}
+
+	groups := make(map[string][]string, len(seen))
+	for group, zones := range seen {
+		for zone := range zones {
+			groups[group] = append(groups[group], zone)
+		}
+		sort.Strings(groups[group])
+	}
+	return groups, nil
+}
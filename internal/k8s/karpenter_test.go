@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// karpenterObject returns a Karpenter object of the kind with the spec and status
+func karpenterObject(gvr schema.GroupVersionResource, kind, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gvr.GroupVersion().String(),
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+// nodePool returns a NodePool using the EC2NodeClass, with the zone requirement if operator is set
+func nodePool(name, nodeClass, operator string, zones ...interface{}) *unstructured.Unstructured {
+	template := map[string]interface{}{"nodeClassRef": map[string]interface{}{"group": "karpenter.k8s.aws", "kind": "EC2NodeClass", "name": nodeClass}}
+	if operator != "" {
+		template["requirements"] = []interface{}{
+			map[string]interface{}{"key": "kubernetes.io/arch", "operator": "In", "values": []interface{}{"amd64"}},
+			map[string]interface{}{"key": corev1.LabelTopologyZone, "operator": operator, "values": zones},
+		}
+	}
+	return karpenterObject(karpenterNodePoolGVR(), "NodePool", name, map[string]interface{}{"template": map[string]interface{}{"spec": template}}, nil)
+}
+
+func TestClient_KarpenterNodePools(t *testing.T) {
+	t.Parallel()
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		karpenterNodePoolGVR():  "NodePoolList",
+		karpenterNodeClassGVR(): "EC2NodeClassList",
+	},
+		nodePool("general", "default", "In", "eu-west-1a", "eu-west-1b"),
+		nodePool("spot", "discovered", "NotIn", "eu-west-1a"),
+		nodePool("any", "missing", ""),
+		karpenterObject(karpenterNodeClassGVR(), "EC2NodeClass", "default",
+			map[string]interface{}{"subnetSelectorTerms": []interface{}{map[string]interface{}{"id": "subnet-a"}}},
+			map[string]interface{}{"subnets": []interface{}{
+				map[string]interface{}{"id": "subnet-b", "zone": "eu-west-1b"},
+				map[string]interface{}{"id": "subnet-a", "zone": "eu-west-1a"},
+			}}),
+		karpenterObject(karpenterNodeClassGVR(), "EC2NodeClass", "discovered",
+			map[string]interface{}{"subnetSelectorTerms": []interface{}{map[string]interface{}{"tags": map[string]interface{}{"karpenter.sh/discovery": "prod"}}}},
+			nil),
+	)
+	c := NewClientWithInterface(fake.NewSimpleClientset(), dynamicClient) //nolint:staticcheck // NewClientset requires apply configurations
+
+	pools, err := c.KarpenterNodePools(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []KarpenterNodePool{
+		{Name: "any", NodeClass: "missing"},
+		{Name: "general", Zones: []string{"eu-west-1a", "eu-west-1b"}, NodeClass: "default", SubnetZones: []string{"eu-west-1a", "eu-west-1b"}, SubnetSelectors: []SubnetSelectorTerm{{ID: "subnet-a"}}},
+		{Name: "spot", ExcludedZones: []string{"eu-west-1a"}, NodeClass: "discovered", SubnetSelectors: []SubnetSelectorTerm{{Tags: map[string]string{"karpenter.sh/discovery": "prod"}}}},
+	}, pools)
+
+	assert.True(t, pools[0].AllowsZone("eu-west-1c"), "no zone requirement")
+	assert.False(t, pools[1].AllowsZone("eu-west-1c"))
+	assert.False(t, pools[2].AllowsZone("eu-west-1a"))
+	assert.True(t, pools[2].AllowsZone("eu-west-1c"))
+}
+
+func TestClient_NodeGroupZones(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, zone string, labels map[string]string) *corev1.Node {
+		labels[corev1.LabelTopologyZone] = zone
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	c := newTestClient(
+		node("node-a", "eu-west-1a", map[string]string{"eks.amazonaws.com/nodegroup": "general"}),
+		node("node-b", "eu-west-1b", map[string]string{"eks.amazonaws.com/nodegroup": "general"}),
+		node("node-c", "eu-west-1c", map[string]string{"alpha.eksctl.io/nodegroup-name": "batch"}),
+		node("node-k", "eu-west-1c", map[string]string{"karpenter.sh/nodepool": "default"}),
+	)
+
+	groups, err := c.NodeGroupZones(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"general": {"eu-west-1a", "eu-west-1b"},
+		"batch":   {"eu-west-1c"},
+	}, groups)
+}
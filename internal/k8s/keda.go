@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KEDAPausedReplicasAnnotation pauses the autoscaling of a KEDA ScaledObject and
+// holds its target at the given replica count
+const KEDAPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+
+// ScaledObjectInfo stores information about a KEDA ScaledObject
+type ScaledObjectInfo struct {
+	Name      string
+	Namespace string
+	Target    string // "Kind/name" of the workload it scales
+	// PausedReplicas is the value of KEDAPausedReplicasAnnotation before the
+	// run, restored afterwards; empty when it was not set
+	PausedReplicas string
+}
+
+// kedaScaledObjectGVR returns the GroupVersionResource for KEDA ScaledObjects
+func kedaScaledObjectGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "keda.sh",
+		Version:  "v1alpha1",
+		Resource: "scaledobjects",
+	}
+}
+
+// FindScaledObjects finds the KEDA ScaledObjects in the namespace that scale one
+// of the workloads. None are found when KEDA is not installed.
+func (c *Client) FindScaledObjects(ctx context.Context, namespace string, workloads []WorkloadInfo) ([]ScaledObjectInfo, error) {
+	if c.dynamicClient == nil || len(workloads) == 0 {
+		return nil, nil
+	}
+	list, err := c.dynamicClient.Resource(kedaScaledObjectGVR()).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			slog.Debug("k8s: KEDA ScaledObjects are not served", "namespace", namespace)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list KEDA ScaledObjects: %w", err)
+	}
+
+	scaled := make(map[string]bool, len(workloads))
+	for _, w := range workloads {
+		scaled[w.Kind+"/"+w.Name] = true
+	}
+	var objects []ScaledObjectInfo
+	for _, obj := range list.Items {
+		name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+		if kind == "" {
+			kind = "Deployment" // KEDA's default
+		}
+		if !scaled[kind+"/"+name] {
+			continue
+		}
+		objects = append(objects, ScaledObjectInfo{
+			Name:           obj.GetName(),
+			Namespace:      namespace,
+			Target:         kind + "/" + name,
+			PausedReplicas: obj.GetAnnotations()[KEDAPausedReplicasAnnotation],
+		})
+	}
+	return objects, nil
+}
+
+// PauseScaledObjects pauses the given ScaledObjects at zero replicas, so KEDA does
+// not scale their workloads back up during the migration
+func (c *Client) PauseScaledObjects(ctx context.Context, objects []ScaledObjectInfo) error {
+	for _, obj := range objects {
+		slog.Info("k8s: pausing KEDA ScaledObject", "namespace", obj.Namespace, "scaledObject", obj.Name, "target", obj.Target)
+		if err := c.setPausedReplicas(ctx, obj, "0"); err != nil {
+			return fmt.Errorf("failed to pause KEDA ScaledObject %s/%s: %w", obj.Namespace, obj.Name, err)
+		}
+	}
+	return nil
+}
+
+// ResumeScaledObjects restores the paused-replicas annotation the given
+// ScaledObjects had before the run, removing it when they had none
+func (c *Client) ResumeScaledObjects(ctx context.Context, objects []ScaledObjectInfo) error {
+	for _, obj := range objects {
+		slog.Info("k8s: resuming KEDA ScaledObject", "namespace", obj.Namespace, "scaledObject", obj.Name, "target", obj.Target)
+		if err := c.setPausedReplicas(ctx, obj, obj.PausedReplicas); err != nil {
+			return fmt.Errorf("failed to resume KEDA ScaledObject %s/%s: %w", obj.Namespace, obj.Name, err)
+		}
+	}
+	return nil
+}
+
+// setPausedReplicas sets the paused-replicas annotation of a ScaledObject, or
+// removes it when value is empty
+func (c *Client) setPausedReplicas(ctx context.Context, info ScaledObjectInfo, value string) error {
+	resource := c.dynamicClient.Resource(kedaScaledObjectGVR()).Namespace(info.Namespace)
+	obj, err := resource.Get(ctx, info.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if value == "" {
+		delete(annotations, KEDAPausedReplicasAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[KEDAPausedReplicasAnnotation] = value
+	}
+	obj.SetAnnotations(annotations)
+	_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
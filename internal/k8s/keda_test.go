@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// scaledObject returns a KEDA ScaledObject scaling the target, with the
+// annotations; an empty kind is left to KEDA's default
+func scaledObject(name, kind, target string, annotations map[string]string) *unstructured.Unstructured {
+	ref := map[string]interface{}{"name": target}
+	if kind != "" {
+		ref["kind"] = kind
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+		"spec":       map[string]interface{}{"scaleTargetRef": ref},
+	}}
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+// kedaClient returns a client whose dynamic client holds the ScaledObjects
+func kedaClient(objects ...runtime.Object) *Client {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kedaScaledObjectGVR(): "ScaledObjectList"}, objects...)
+	return NewClientWithInterface(fake.NewSimpleClientset(), dynamicClient) //nolint:staticcheck // NewClientset requires apply configurations
+}
+
+func TestClient_FindScaledObjects(t *testing.T) {
+	t.Parallel()
+
+	c := kedaClient(
+		scaledObject("web", "", "web", nil),
+		scaledObject("worker", "StatefulSet", "worker", map[string]string{KEDAPausedReplicasAnnotation: "2"}),
+		scaledObject("cron", "Deployment", "cron", nil),
+	)
+	workloads := []WorkloadInfo{{Kind: "Deployment", Name: "web", Replicas: 2}, {Kind: "StatefulSet", Name: "worker", Replicas: 1}}
+
+	objects, err := c.FindScaledObjects(context.Background(), "shop", workloads)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ScaledObjectInfo{
+		{Name: "web", Namespace: "shop", Target: "Deployment/web"},
+		{Name: "worker", Namespace: "shop", Target: "StatefulSet/worker", PausedReplicas: "2"},
+	}, objects, "only ScaledObjects of the scaled workloads are found")
+
+	objects, err = NewClientWithInterface(fake.NewSimpleClientset(), nil).FindScaledObjects(context.Background(), "shop", workloads) //nolint:staticcheck // NewClientset requires apply configurations
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestClient_PauseAndResumeScaledObjects(t *testing.T) {
+	t.Parallel()
+
+	c := kedaClient(
+		scaledObject("web", "", "web", map[string]string{"owner": "shop"}),
+		scaledObject("worker", "StatefulSet", "worker", map[string]string{KEDAPausedReplicasAnnotation: "2"}),
+	)
+	objects := []ScaledObjectInfo{
+		{Name: "web", Namespace: "shop", Target: "Deployment/web"},
+		{Name: "worker", Namespace: "shop", Target: "StatefulSet/worker", PausedReplicas: "2"},
+	}
+	ctx := context.Background()
+	annotations := func(name string) map[string]string {
+		obj, err := c.dynamicClient.Resource(kedaScaledObjectGVR()).Namespace("shop").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return obj.GetAnnotations()
+	}
+
+	require.NoError(t, c.PauseScaledObjects(ctx, objects))
+	assert.Equal(t, map[string]string{"owner": "shop", KEDAPausedReplicasAnnotation: "0"}, annotations("web"))
+	assert.Equal(t, map[string]string{KEDAPausedReplicasAnnotation: "0"}, annotations("worker"))
+
+	require.NoError(t, c.ResumeScaledObjects(ctx, objects))
+	assert.Equal(t, map[string]string{"owner": "shop"}, annotations("web"))
+	assert.Equal(t, map[string]string{KEDAPausedReplicasAnnotation: "2"}, annotations("worker"), "an earlier pause is kept")
+
+	require.ErrorContains(t, c.PauseScaledObjects(ctx, []ScaledObjectInfo{{Name: "gone", Namespace: "shop"}}), "failed to pause KEDA ScaledObject shop/gone")
+}
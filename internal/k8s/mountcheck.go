@@ -0,0 +1,40 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LabelMountCheck marks the pods RunMountCheck creates
+const LabelMountCheck = "pvc-migrator/mount-check"
+
+const (
+	mountCheckPrefix = "pvc-migrator-check-"
+	mountCheckFile   = warmupMountPath + "/.pvc-migrator-check"
+)
+
+// MountCheckPodName returns the name of the mount check pod of a PVC
+func MountCheckPodName(pvcName, suffix string) string {
+	return claimPodName(mountCheckPrefix, pvcName, suffix)
+}
+
+// RunMountCheck runs a pod in the zone that mounts the PVC read-write, writes,
+// syncs, reads back and removes a file on it, and returns nil once the pod has
+// succeeded. The pod is deleted whatever the outcome. The PVC's workloads must be
+// scaled down, as an RWO volume can only be attached to one node.
+func (c *Client) RunMountCheck(ctx context.Context, namespace, pvcName, zone, image string, timeout time.Duration) error {
+	_, err := c.runClaimPod(ctx, claimPod{
+		namespace: namespace,
+		pvcName:   pvcName,
+		zone:      zone,
+		image:     image,
+		prefix:    mountCheckPrefix,
+		label:     LabelMountCheck,
+		script:    fmt.Sprintf("echo ok > %[1]s && sync && grep -q ok %[1]s && rm %[1]s", mountCheckFile),
+	}, timeout)
+	if err != nil {
+		return fmt.Errorf("mount check of PVC %s: %w", pvcName, err)
+	}
+	return nil
+}
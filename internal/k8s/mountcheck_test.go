@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClient_RunMountCheck(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		status  corev1.PodStatus
+		wantErr string
+	}{
+		{
+			name:   "succeeded",
+			status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+		{
+			name: "read_only",
+			status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Reason: "Error", ExitCode: 1, Message: "sh: can't create /data/.pvc-migrator-check: Read-only file system\n",
+					}},
+				}},
+			},
+			wantErr: "failed: Error (exit code 1) sh: can't create /data/.pvc-migrator-check: Read-only file system",
+		},
+		{
+			name: "unschedulable",
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type: corev1.PodScheduled, Status: corev1.ConditionFalse,
+					Message: "0/3 nodes are available: 3 node(s) had volume node affinity conflict.",
+				}},
+			},
+			wantErr: "is still Pending after 0s: 0/3 nodes are available",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientset := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires apply configurations
+			var created *corev1.Pod
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				pod.Status = tc.status
+				created = pod.DeepCopy()
+				return false, nil, nil
+			})
+			client := NewClientWithInterface(clientset, nil)
+			ctx := context.Background()
+
+			err := client.RunMountCheck(ctx, "db", "data", "eu-west-1b", "", 0)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NotNil(t, created)
+			assert.Equal(t, "eu-west-1b", created.Spec.NodeSelector[corev1.LabelTopologyZone])
+			assert.Equal(t, corev1.RestartPolicyNever, created.Spec.RestartPolicy)
+			assert.Equal(t, DefaultWarmupImage, created.Spec.Containers[0].Image)
+			assert.False(t, created.Spec.Containers[0].VolumeMounts[0].ReadOnly, "the check writes to the volume")
+			assert.Equal(t, "data", created.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+			assert.Equal(t, "true", created.Labels[LabelMountCheck])
+			assert.Equal(t, ManagedByValue, created.Labels[LabelManagedBy])
+
+			pods, err := clientset.CoreV1().Pods("db").List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, pods.Items, "the pod is deleted whatever the outcome")
+		})
+	}
+}
+
+func TestMountCheckPodName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "pvc-migrator-check-data-abcde", MountCheckPodName("data", "abcde"))
+
+	name := MountCheckPodName(strings.Repeat("a", 80), "abcde")
+	assert.Empty(t, validation.IsDNS1123Label(name))
+	assert.True(t, strings.HasSuffix(name, "-abcde"))
+}
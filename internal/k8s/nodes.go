@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CordonZoneNodes marks the schedulable nodes of a zone unschedulable and returns
+// their names, sorted. Nodes already cordoned are left out, so uncordoning the
+// result restores the zone as it was. Running pods are not evicted.
+func (c *Client) CordonZoneNodes(ctx context.Context, zone string) ([]string, error) {
+	slog.Info("k8s: listing nodes", "zone", zone)
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: corev1.LabelTopologyZone + "=" + zone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes in zone %s: %w", zone, err)
+	}
+
+	var cordoned []string
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		if err := c.setUnschedulable(ctx, node.Name, true); err != nil {
+			return cordoned, err
+		}
+		cordoned = append(cordoned, node.Name)
+	}
+	sort.Strings(cordoned)
+	return cordoned, nil
+}
+
+// ZoneNodeLabels returns the labels of the healthy nodes, those Ready and
+// schedulable, of every zone with nodes. A zone whose nodes are all cordoned or
+// not Ready has none. Nodes without a zone label are left out.
+func (c *Client) ZoneNodeLabels(ctx context.Context) (map[string][]map[string]string, error) {
+	slog.Info("k8s: listing nodes")
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	zones := make(map[string][]map[string]string)
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		zone := node.Labels[corev1.LabelTopologyZone]
+		if zone == "" {
+			continue
+		}
+		if node.Spec.Unschedulable || !nodeReady(node) {
+			if _, ok := zones[zone]; !ok {
+				zones[zone] = nil
+			}
+			continue
+		}
+		zones[zone] = append(zones[zone], node.Labels)
+	}
+	return zones, nil
+}
+
+// UncordonNodes marks the nodes schedulable again. Nodes that no longer exist,
+// such as those of a node group scaled in meanwhile, are skipped.
+func (c *Client) UncordonNodes(ctx context.Context, nodes []string) error {
+	for _, name := range nodes {
+		if err := c.setUnschedulable(ctx, name, false); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForPodsScheduled waits until every pending pod of the namespace has been
+// assigned a node, so nodes cordoned for the run can be uncordoned without pods
+// scaled back up landing on them
+func (c *Client) WaitForPodsScheduled(ctx context.Context, namespace string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		unscheduled := 0
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodPending && pod.Spec.NodeName == "" {
+				unscheduled++
+			}
+		}
+
+		if unscheduled == 0 {
+			return nil
+		}
+		slog.Debug("k8s: waiting for pods to be scheduled", "namespace", namespace, "unscheduled", unscheduled)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	return fmt.Errorf("timeout waiting for pods to be scheduled")
+}
+
+// setUnschedulable cordons or uncordons a node, like kubectl cordon
+func (c *Client) setUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"unschedulable": unschedulable}})
+	if err != nil {
+		return fmt.Errorf("failed to build cordon patch: %w", err)
+	}
+	slog.Info("k8s: setting node schedulability", "node", name, "unschedulable", unschedulable)
+	if _, err := c.clientset.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		if unschedulable {
+			return fmt.Errorf("failed to cordon node %s: %w", name, err)
+		}
+		return fmt.Errorf("failed to uncordon node %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNode(name, zone string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+	}
+}
+
+func TestClient_CordonZoneNodes(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(
+		newNode("node-b", "eu-west-1b", false),
+		newNode("node-a", "eu-west-1b", false),
+		newNode("node-drained", "eu-west-1b", true),
+		newNode("node-other", "eu-west-1a", false),
+	)
+	ctx := context.Background()
+	unschedulable := func(name string) bool {
+		node, err := client.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return node.Spec.Unschedulable
+	}
+
+	cordoned, err := client.CordonZoneNodes(ctx, "eu-west-1b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a", "node-b"}, cordoned, "already cordoned nodes are left out")
+	assert.True(t, unschedulable("node-a"))
+	assert.True(t, unschedulable("node-b"))
+	assert.False(t, unschedulable("node-other"))
+
+	require.NoError(t, client.UncordonNodes(ctx, append(cordoned, "node-gone")))
+	assert.False(t, unschedulable("node-a"))
+	assert.False(t, unschedulable("node-b"))
+	assert.True(t, unschedulable("node-drained"), "nodes cordoned before the run stay cordoned")
+}
+
+func TestClient_ZoneNodeLabels(t *testing.T) {
+	t.Parallel()
+
+	ready := func(node *corev1.Node) *corev1.Node {
+		node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+		return node
+	}
+	client := newTestClient(
+		ready(newNode("node-a", "eu-west-1a", false)),
+		newNode("node-not-ready", "eu-west-1a", false),
+		ready(newNode("node-drained", "eu-west-1b", true)),
+		ready(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-unlabelled"}}),
+	)
+
+	zones, err := client.ZoneNodeLabels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]map[string]string{
+		"eu-west-1a": {{corev1.LabelTopologyZone: "eu-west-1a"}},
+		"eu-west-1b": nil,
+	}, zones)
+}
+
+func TestClient_WaitForPodsScheduled(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "db"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+	}
+
+	client := newTestClient(pod("scheduled", "node-a"))
+	require.NoError(t, client.WaitForPodsScheduled(context.Background(), "db", time.Second))
+
+	client = newTestClient(pod("scheduled", "node-a"), pod("waiting", ""))
+	err := client.WaitForPodsScheduled(context.Background(), "db", time.Millisecond)
+	assert.ErrorContains(t, err, "timeout waiting for pods to be scheduled")
+}
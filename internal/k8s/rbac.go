@@ -0,0 +1,215 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// RBACOptions describes a run, to work out the Kubernetes permissions it needs
+type RBACOptions struct {
+	Name       string   // Name of the roles and bindings
+	Namespaces []string // Namespaces whose PVCs are migrated
+	// DiscoverNamespaces is set when namespaces are found in the cluster, so the
+	// namespaced permissions must be granted cluster-wide
+	DiscoverNamespaces bool
+	ArgoCDNamespaces   []string // Namespaces searched for ArgoCD Applications; empty when ArgoCD is skipped
+	ArgoCDSyncWindows  bool     // Deny sync windows are added to the AppProjects instead
+	ArgoCDAppSets      bool     // ApplicationSets owning the applications are paused
+	FluxNamespaces     []string // Namespaces searched for Flux objects; empty when Flux is skipped
+	KEDA               bool     // KEDA ScaledObjects of the scaled workloads are paused
+	Rollouts           bool     // Argo Rollouts are scaled down like Deployments
+	DeleteJobs         bool     // Running Jobs that mount the PVCs are deleted
+	Warmup             bool     // Warm-up jobs are created
+	VerifyMount        bool     // A mount check pod is run for each migrated PVC
+	VerifyChecksum     bool     // Checksum pods are run for each migrated PVC
+	FreezeFilesystem   bool     // Freeze hooks are run in the pods mounting a volume
+	LabelNamespaces    bool     // Completed namespaces are labelled
+	HelmValues         bool     // Helm release secrets are read to render the values changes of the releases
+	CordonNodes        bool     // Nodes of the source zone are cordoned during the run
+	AutoZone           bool     // The target zone is picked from the capacity of the nodes and the pods on them
+	CheckProvisioning  bool     // Karpenter NodePools and EC2NodeClasses are read for target zones without nodes
+	SimulateScheduling bool     // A pod of each workload is created with a dry run in its target zone
+	VerifySourceZone   bool     // The PVs are listed after an evacuation run to check none is left in the source zone
+	JournalNamespace   string   // Namespace the journal ConfigMap is written to; empty when no journal is kept
+	// ServiceAccount is the "namespace/name" the roles are bound to; no bindings
+	// are generated when empty
+	ServiceAccount string
+}
+
+// namespacedRules are the permissions needed in every namespace whose PVCs are migrated
+func (o RBACOptions) namespacedRules() []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"persistentvolumeclaims"}, Verbs: []string{"get", "list", "create", "update", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments", "statefulsets"}, Verbs: []string{"get", "list", "update"}},
+	}
+	if o.Rollouts {
+		rules = append(rules,
+			rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"rollouts"}, Verbs: []string{"list"}},
+			rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"rollouts/scale"}, Verbs: []string{"get", "update"}},
+		)
+	}
+	if len(o.FluxNamespaces) > 0 {
+		// The Flux objects that applied the PVCs may live next to them
+		rules = append(rules,
+			rbacv1.PolicyRule{APIGroups: []string{fluxGVR(KindKustomization).Group}, Resources: []string{fluxGVR(KindKustomization).Resource}, Verbs: []string{"get", "update"}},
+			rbacv1.PolicyRule{APIGroups: []string{fluxGVR(KindHelmRelease).Group}, Resources: []string{fluxGVR(KindHelmRelease).Resource}, Verbs: []string{"get", "update"}},
+		)
+	}
+	if o.KEDA {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"keda.sh"}, Resources: []string{"scaledobjects"}, Verbs: []string{"get", "list", "update"}})
+	}
+	if o.Warmup {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"create"}})
+	}
+	if o.DeleteJobs {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"delete"}})
+	}
+	if o.VerifyMount || o.VerifyChecksum {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "create", "delete"}})
+	}
+	if o.FreezeFilesystem {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}})
+	}
+	if o.HelmValues {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}})
+	}
+	if o.SimulateScheduling {
+		// Dry runs are authorized like the requests they stand for
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"create"}})
+	}
+	return rules
+}
+
+// clusterRules are the permissions on cluster-scoped resources, plus the
+// namespaced ones when namespaces are discovered
+func (o RBACOptions) clusterRules() []rbacv1.PolicyRule {
+	pvVerbs := []string{"get", "create", "update", "delete"}
+	if o.DiscoverNamespaces || o.VerifySourceZone {
+		pvVerbs = append(pvVerbs, "list")
+	}
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"persistentvolumes"}, Verbs: pvVerbs},
+	}
+
+	switch {
+	case o.DiscoverNamespaces && o.LabelNamespaces:
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"list", "patch"}})
+	case o.DiscoverNamespaces:
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"list"}})
+	case o.LabelNamespaces:
+		// Only the namespaces of the run are ever labelled
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""}, Resources: []string{"namespaces"}, ResourceNames: o.Namespaces, Verbs: []string{"patch"},
+		})
+	}
+
+	switch {
+	case o.CordonNodes:
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list", "patch"}})
+	default:
+		// The plan checks the target zones have nodes to run the moved pods on
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list"}})
+	}
+	if o.CheckProvisioning {
+		rules = append(rules,
+			rbacv1.PolicyRule{APIGroups: []string{karpenterNodePoolGVR().Group}, Resources: []string{karpenterNodePoolGVR().Resource}, Verbs: []string{"list"}},
+			rbacv1.PolicyRule{APIGroups: []string{karpenterNodeClassGVR().Group}, Resources: []string{karpenterNodeClassGVR().Resource}, Verbs: []string{"get"}},
+		)
+	}
+	if (o.AutoZone || o.SimulateScheduling) && !o.DiscoverNamespaces {
+		// The pods of every namespace count towards the load of a zone or node
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}})
+	}
+
+	if o.DiscoverNamespaces {
+		rules = append(rules, o.namespacedRules()...)
+	}
+	return rules
+}
+
+// RBACManifests returns the ClusterRole and Roles a run needs, with their
+// bindings when a service account is given, as a multi-document YAML stream
+func RBACManifests(o RBACOptions) (string, error) {
+	var saNamespace, saName string
+	if o.ServiceAccount != "" {
+		var ok bool
+		saNamespace, saName, ok = strings.Cut(o.ServiceAccount, "/")
+		if !ok || saNamespace == "" || saName == "" {
+			return "", fmt.Errorf("service account '%s' must be in the form namespace/name", o.ServiceAccount)
+		}
+	}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: saNamespace, Name: saName}}
+
+	objects := []any{&rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: o.Name},
+		Rules:      o.clusterRules(),
+	}}
+	if saName != "" {
+		objects = append(objects, &rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: o.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: o.Name},
+			Subjects:   subjects,
+		})
+	}
+
+	addRole := func(name, namespace string, rules []rbacv1.PolicyRule) {
+		objects = append(objects, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Rules:      rules,
+		})
+		if saName != "" {
+			objects = append(objects, &rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+				Subjects:   subjects,
+			})
+		}
+	}
+	if !o.DiscoverNamespaces {
+		for _, ns := range o.Namespaces {
+			addRole(o.Name, ns, o.namespacedRules())
+		}
+	}
+	for _, ns := range o.ArgoCDNamespaces {
+		rules := []rbacv1.PolicyRule{
+			{APIGroups: []string{argoCDAppGVR().Group}, Resources: []string{argoCDAppGVR().Resource}, Verbs: []string{"get", "list", "update"}},
+		}
+		if o.ArgoCDSyncWindows {
+			rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{argoCDProjectGVR().Group}, Resources: []string{argoCDProjectGVR().Resource}, Verbs: []string{"get", "update"}})
+		}
+		if o.ArgoCDAppSets {
+			rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{argoCDAppSetGVR().Group}, Resources: []string{argoCDAppSetGVR().Resource}, Verbs: []string{"get", "update"}})
+		}
+		addRole(o.Name+"-argocd", ns, rules)
+	}
+	for _, ns := range o.FluxNamespaces {
+		addRole(o.Name+"-flux", ns, []rbacv1.PolicyRule{
+			{APIGroups: []string{fluxGVR(KindKustomization).Group}, Resources: []string{fluxGVR(KindKustomization).Resource}, Verbs: []string{"get", "list", "update"}},
+			{APIGroups: []string{fluxGVR(KindHelmRelease).Group}, Resources: []string{fluxGVR(KindHelmRelease).Resource}, Verbs: []string{"get", "list", "update"}},
+		})
+	}
+	if o.JournalNamespace != "" {
+		addRole(o.Name+"-journal", o.JournalNamespace, []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list", "create", "update"}},
+		})
+	}
+
+	docs := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal RBAC manifest: %w", err)
+		}
+		docs = append(docs, string(doc))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// decodeRBAC splits a manifest stream into its objects, by kind
+func decodeRBAC(t *testing.T, manifests string) (clusterRoles []rbacv1.ClusterRole, roles []rbacv1.Role, bindings int) {
+	t.Helper()
+	for _, doc := range strings.Split(manifests, "---\n") {
+		var meta struct{ Kind string }
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &meta))
+		switch meta.Kind {
+		case "ClusterRole":
+			var role rbacv1.ClusterRole
+			require.NoError(t, yaml.Unmarshal([]byte(doc), &role))
+			clusterRoles = append(clusterRoles, role)
+		case "Role":
+			var role rbacv1.Role
+			require.NoError(t, yaml.Unmarshal([]byte(doc), &role))
+			roles = append(roles, role)
+		case "ClusterRoleBinding", "RoleBinding":
+			bindings++
+		default:
+			t.Fatalf("unexpected kind %q", meta.Kind)
+		}
+	}
+	return clusterRoles, roles, bindings
+}
+
+func TestRBACManifests_Namespaces(t *testing.T) {
+	t.Parallel()
+
+	out, err := RBACManifests(RBACOptions{
+		Name:              "pvc-migrator",
+		Namespaces:        []string{"db", "web"},
+		ArgoCDNamespaces:  []string{"argocd"},
+		ArgoCDSyncWindows: true,
+		ArgoCDAppSets:     true,
+		LabelNamespaces:   true,
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, out, "creationTimestamp")
+
+	clusterRoles, roles, bindings := decodeRBAC(t, out)
+	require.Len(t, clusterRoles, 1)
+	assert.Zero(t, bindings, "no service account")
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"persistentvolumes"}, Verbs: []string{"get", "create", "update", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"namespaces"}, ResourceNames: []string{"db", "web"}, Verbs: []string{"patch"}},
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list"}},
+	}, clusterRoles[0].Rules, "nothing namespaced is granted cluster-wide")
+
+	require.Len(t, roles, 3)
+	assert.Equal(t, "db", roles[0].Namespace)
+	assert.Equal(t, "web", roles[1].Namespace)
+	assert.Len(t, roles[0].Rules, 3, "no jobs without warm-up")
+	assert.Equal(t, "pvc-migrator-argocd", roles[2].Name)
+	assert.Equal(t, "argocd", roles[2].Namespace)
+	assert.Equal(t, []string{"argoproj.io"}, roles[2].Rules[0].APIGroups)
+	assert.Equal(t, rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"appprojects"}, Verbs: []string{"get", "update"}}, roles[2].Rules[1])
+	assert.Equal(t, rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"applicationsets"}, Verbs: []string{"get", "update"}}, roles[2].Rules[2])
+}
+
+func TestRBACManifests_DiscoverNamespaces(t *testing.T) {
+	t.Parallel()
+
+	out, err := RBACManifests(RBACOptions{
+		Name:               "pvc-migrator",
+		DiscoverNamespaces: true,
+		KEDA:               true,
+		Rollouts:           true,
+		DeleteJobs:         true,
+		Warmup:             true,
+		VerifyMount:        true,
+		CordonNodes:        true,
+		FreezeFilesystem:   true,
+		HelmValues:         true,
+		CheckProvisioning:  true,
+		SimulateScheduling: true,
+		JournalNamespace:   "ops",
+		ServiceAccount:     "ops/pvc-migrator",
+	})
+	require.NoError(t, err)
+
+	clusterRoles, roles, bindings := decodeRBAC(t, out)
+	require.Len(t, clusterRoles, 1)
+	require.Len(t, roles, 1, "namespaced permissions are granted cluster-wide, except the journal's")
+	assert.Equal(t, "pvc-migrator-journal", roles[0].Name)
+	assert.Equal(t, "ops", roles[0].Namespace)
+	assert.Equal(t, []string{"configmaps"}, roles[0].Rules[0].Resources)
+	assert.Equal(t, 2, bindings)
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"list"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"create"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"delete"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"rollouts/scale"}, Verbs: []string{"get", "update"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"keda.sh"}, Resources: []string{"scaledobjects"}, Verbs: []string{"get", "list", "update"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "create", "delete"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"create"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"karpenter.sh"}, Resources: []string{"nodepools"}, Verbs: []string{"list"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"karpenter.k8s.aws"}, Resources: []string{"ec2nodeclasses"}, Verbs: []string{"get"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list", "patch"}})
+	assert.Contains(t, clusterRoles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}})
+	assert.Contains(t, out, "name: pvc-migrator\n  namespace: ops\n")
+}
+
+func TestRBACManifests_Flux(t *testing.T) {
+	t.Parallel()
+
+	out, err := RBACManifests(RBACOptions{
+		Name:           "pvc-migrator",
+		Namespaces:     []string{"db"},
+		FluxNamespaces: []string{"flux-system"},
+	})
+	require.NoError(t, err)
+
+	_, roles, _ := decodeRBAC(t, out)
+	require.Len(t, roles, 2)
+	assert.Contains(t, roles[0].Rules, rbacv1.PolicyRule{APIGroups: []string{"helm.toolkit.fluxcd.io"}, Resources: []string{"helmreleases"}, Verbs: []string{"get", "update"}})
+	assert.Equal(t, "pvc-migrator-flux", roles[1].Name)
+	assert.Equal(t, "flux-system", roles[1].Namespace)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{"kustomize.toolkit.fluxcd.io"}, Resources: []string{"kustomizations"}, Verbs: []string{"get", "list", "update"}},
+		{APIGroups: []string{"helm.toolkit.fluxcd.io"}, Resources: []string{"helmreleases"}, Verbs: []string{"get", "list", "update"}},
+	}, roles[1].Rules)
+}
+
+func TestRBACManifests_AutoZoneAndSourceZone(t *testing.T) {
+	t.Parallel()
+
+	out, err := RBACManifests(RBACOptions{
+		Name:             "pvc-migrator",
+		Namespaces:       []string{"db"},
+		AutoZone:         true,
+		VerifySourceZone: true,
+	})
+	require.NoError(t, err)
+
+	clusterRoles, _, _ := decodeRBAC(t, out)
+	require.Len(t, clusterRoles, 1)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"persistentvolumes"}, Verbs: []string{"get", "create", "update", "delete", "list"}},
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+	}, clusterRoles[0].Rules, "the pods of every namespace load the zones")
+}
+
+func TestRBACManifests_InvalidServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	_, err := RBACManifests(RBACOptions{Name: "pvc-migrator", ServiceAccount: "pvc-migrator"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace/name")
+}
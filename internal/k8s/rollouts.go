@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KindRollout is the kind of an Argo Rollout, scaled like a Deployment
+const KindRollout = "Rollout"
+
+// rolloutGVR returns the GroupVersionResource for Argo Rollouts
+func rolloutGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "rollouts",
+	}
+}
+
+// SkipRollouts leaves Argo Rollouts out of the workloads, for clusters where
+// they cannot be listed
+func (c *Client) SkipRollouts() {
+	c.skipRollouts = true
+}
+
+// runningRollouts returns the Argo Rollouts in the namespace with replicas. None
+// are found when Argo Rollouts is not installed.
+func (c *Client) runningRollouts(ctx context.Context, namespace string) ([]WorkloadInfo, error) {
+	if c.dynamicClient == nil || c.skipRollouts {
+		return nil, nil
+	}
+	list, err := c.dynamicClient.Resource(rolloutGVR()).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			slog.Debug("k8s: Argo Rollouts are not served", "namespace", namespace)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list rollouts: %w", err)
+	}
+
+	var workloads []WorkloadInfo
+	for _, obj := range list.Items {
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1 // The Rollout default
+		}
+		if replicas > 0 {
+			workloads = append(workloads, WorkloadInfo{Kind: KindRollout, Name: obj.GetName(), Replicas: int32(replicas)})
+		}
+	}
+	return workloads, nil
+}
+
+// scaleRollout sets the replicas of an Argo Rollout through its scale subresource
+func (c *Client) scaleRollout(ctx context.Context, namespace, name string, replicas int32) error {
+	resource := c.dynamicClient.Resource(rolloutGVR()).Namespace(namespace)
+	scale, err := resource.Get(ctx, name, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return fmt.Errorf("failed to get the scale of rollout %s: %w", name, err)
+	}
+	if err := unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return fmt.Errorf("failed to set the replicas of rollout %s: %w", name, err)
+	}
+	if _, err := resource.Update(ctx, scale, metav1.UpdateOptions{}, "scale"); err != nil {
+		return fmt.Errorf("failed to scale rollout %s to %d: %w", name, replicas, err)
+	}
+	return nil
+}
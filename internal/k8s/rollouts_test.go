@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// rollout returns an Argo Rollout with the replicas, or none set when negative
+func rollout(name string, replicas int64) *unstructured.Unstructured {
+	spec := map[string]interface{}{}
+	if replicas >= 0 {
+		spec["replicas"] = replicas
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+		"spec":       spec,
+	}}
+}
+
+func TestClient_Rollouts(t *testing.T) {
+	t.Parallel()
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rolloutGVR(): "RolloutList"},
+		rollout("web", 3), rollout("canary", -1), rollout("idle", 0))
+	var mu sync.Mutex
+	var subresources []string
+	dynamicClient.PrependReactor("*", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() == "get" || action.GetVerb() == "update" {
+			mu.Lock()
+			subresources = append(subresources, action.GetVerb()+" "+action.GetSubresource())
+			mu.Unlock()
+		}
+		return false, nil, nil
+	})
+	c := NewClientWithInterface(fake.NewSimpleClientset(), dynamicClient) //nolint:staticcheck // NewClientset requires apply configurations
+	ctx := context.Background()
+	replicas := func(name string) int64 {
+		obj, err := dynamicClient.Resource(rolloutGVR()).Namespace("shop").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		n, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		return n
+	}
+
+	status, err := c.GetWorkloadStatus(ctx, "shop")
+	require.NoError(t, err)
+	want := []WorkloadInfo{{Kind: KindRollout, Name: "web", Replicas: 3}, {Kind: KindRollout, Name: "canary", Replicas: 1}}
+	assert.ElementsMatch(t, want, status, "rollouts without replicas default to one")
+
+	scaled, err := c.ScaleDownWorkloads(ctx, "shop")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, scaled)
+	assert.Equal(t, int64(0), replicas("web"))
+	assert.Equal(t, int64(0), replicas("canary"))
+	mu.Lock()
+	assert.Contains(t, subresources, "update scale", "rollouts are scaled through their scale subresource")
+	mu.Unlock()
+
+	require.NoError(t, c.ScaleUpWorkloads(ctx, "shop", scaled))
+	assert.Equal(t, int64(3), replicas("web"))
+	assert.Equal(t, int64(1), replicas("canary"))
+
+	c.SkipRollouts()
+	status, err = c.GetWorkloadStatus(ctx, "shop")
+	require.NoError(t, err)
+	assert.Empty(t, status)
+}
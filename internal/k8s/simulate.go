@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/pager"
+)
+
+// NodeUsage is a healthy node, Ready and schedulable, and what is left of its
+// allocatable CPU and memory once the requests of the pods on it are taken
+type NodeUsage struct {
+	Name   string
+	Labels map[string]string
+	Taints []corev1.Taint
+	Free   Resources
+}
+
+// ZoneNodeUsage returns the healthy nodes of the zone, sorted by name, with
+// what the pods that have not finished leave free on them
+func (c *Client) ZoneNodeUsage(ctx context.Context, zone string) ([]NodeUsage, error) {
+	slog.Info("k8s: listing nodes and pods for node usage", "zone", zone)
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: corev1.LabelTopologyZone + "=" + zone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes in zone %s: %w", zone, err)
+	}
+
+	usage := make(map[string]*NodeUsage)
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable || !nodeReady(node) {
+			continue
+		}
+		usage[node.Name] = &NodeUsage{
+			Name:   node.Name,
+			Labels: node.Labels,
+			Taints: node.Spec.Taints,
+			Free: Resources{
+				CPU:    node.Status.Allocatable.Cpu().MilliValue(),
+				Memory: node.Status.Allocatable.Memory().Value(),
+			},
+		}
+	}
+	if len(usage) == 0 {
+		return nil, nil
+	}
+
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().Pods("").List(ctx, opts)
+	}))
+	p.PageSize = listPageSize
+	err = p.EachListItem(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"}, func(obj runtime.Object) error {
+		pod := obj.(*corev1.Pod)
+		node, ok := usage[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return nil
+		}
+		requests := podRequests(pod)
+		node.Free = Resources{CPU: node.Free.CPU - requests.CPU, Memory: node.Free.Memory - requests.Memory}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	result := make([]NodeUsage, 0, len(usage))
+	for _, node := range usage {
+		result = append(result, *node)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// DryRunWorkloadPod builds a pod from the template of a Deployment or
+// StatefulSet, pinned to the zone with a nodeSelector, and creates it with a
+// server-side dry run: admission, such as quotas, Pod Security and webhooks, is
+// applied, but nothing is persisted or scheduled. It returns the pod as the API
+// server admitted it.
+func (c *Client) DryRunWorkloadPod(ctx context.Context, namespace, kind, name, zone string) (*corev1.Pod, error) {
+	var template corev1.PodTemplateSpec
+	switch kind {
+	case "Deployment":
+		deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+		template = deploy.Spec.Template
+	case "StatefulSet":
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset %s: %w", name, err)
+		}
+		template = sts.Spec.Template
+	default:
+		return nil, fmt.Errorf("cannot simulate the pods of %s %s", kind, name)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name + "-zone-check",
+			Namespace:   namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	setZoneSelector(&pod.Spec, zone)
+
+	slog.Info("k8s: dry-run creating pod", "namespace", namespace, "kind", kind, "name", name, "zone", zone)
+	created, err := c.clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Unfit returns why the pod cannot be scheduled on the node, or nothing when it
+// can: its nodeSelector or required node affinity does not match the node, it
+// does not tolerate a taint of the node, or the node has too little CPU or
+// memory left for its requests
+func (n NodeUsage) Unfit(pod *corev1.Pod) string {
+	if !matchesNodeSelector(n.Labels, pod.Spec.NodeSelector) {
+		return "nodeSelector does not match"
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		!matchesNodeSelectorTerms(n.Labels, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+		return "node affinity does not match"
+	}
+	for i := range n.Taints {
+		taint := &n.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || tolerated(pod.Spec.Tolerations, taint) {
+			continue
+		}
+		return fmt.Sprintf("taint %s=%s:%s not tolerated", taint.Key, taint.Value, taint.Effect)
+	}
+	requests := podRequests(pod)
+	if requests.CPU > n.Free.CPU {
+		return "insufficient cpu"
+	}
+	if requests.Memory > n.Free.Memory {
+		return "insufficient memory"
+	}
+	return ""
+}
+
+// matchesNodeSelector reports whether the labels carry every key and value of the selector
+func matchesNodeSelector(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelectorTerms reports whether the labels match one of the terms of
+// a required node affinity, by their label expressions
+func matchesNodeSelectorTerms(labels map[string]string, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		matched := len(term.MatchExpressions) > 0
+		for _, expr := range term.MatchExpressions {
+			value, ok := labels[expr.Key]
+			switch expr.Operator {
+			case corev1.NodeSelectorOpIn:
+				matched = matched && ok && contains(expr.Values, value)
+			case corev1.NodeSelectorOpNotIn:
+				matched = matched && !(ok && contains(expr.Values, value))
+			case corev1.NodeSelectorOpExists:
+				matched = matched && ok
+			case corev1.NodeSelectorOpDoesNotExist:
+				matched = matched && !ok
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// tolerated reports whether one of the tolerations tolerates the taint
+func tolerated(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for _, t := range tolerations {
+		if t.Effect != "" && t.Effect != taint.Effect {
+			continue
+		}
+		if t.Key == "" && t.Operator == corev1.TolerationOpExists {
+			return true
+		}
+		if t.Key != taint.Key {
+			continue
+		}
+		if t.Operator == corev1.TolerationOpExists || t.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
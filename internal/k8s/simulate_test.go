@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClient_ZoneNodeUsage(t *testing.T) {
+	t.Parallel()
+
+	tainted := readyNode("node-a2", "eu-west-1a", "2", "8Gi", true)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	client := newTestClient(
+		readyNode("node-a1", "eu-west-1a", "4", "16Gi", true),
+		tainted,
+		readyNode("node-a3", "eu-west-1a", "4", "16Gi", false),
+		readyNode("node-b1", "eu-west-1b", "4", "16Gi", true),
+		requestingPod("db", "db-0", "node-a1", "1", "2Gi", corev1.PodRunning),
+		requestingPod("db", "done", "node-a1", "2", "4Gi", corev1.PodSucceeded),
+		requestingPod("web", "web-0", "node-b1", "1", "1Gi", corev1.PodRunning),
+	)
+
+	usage, err := client.ZoneNodeUsage(context.Background(), "eu-west-1a")
+	require.NoError(t, err)
+	require.Len(t, usage, 2, "nodes not Ready are left out")
+	assert.Equal(t, "node-a1", usage[0].Name)
+	assert.Equal(t, Resources{CPU: 3000, Memory: 14 << 30}, usage[0].Free)
+	assert.Equal(t, "node-a2", usage[1].Name)
+	assert.Equal(t, tainted.Spec.Taints, usage[1].Taints)
+	assert.Equal(t, Resources{CPU: 2000, Memory: 8 << 30}, usage[1].Free)
+
+	usage, err = client.ZoneNodeUsage(context.Background(), "eu-west-1c")
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
+
+func TestClient_DryRunWorkloadPod(t *testing.T) {
+	t.Parallel()
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "db"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeSelector: map[string]string{"pool": "general"}},
+		}},
+	}
+	client := newTestClient(deploy)
+
+	pod, err := client.DryRunWorkloadPod(context.Background(), "db", "Deployment", "web", "eu-west-1b")
+	require.NoError(t, err)
+	assert.Equal(t, "web-zone-check", pod.Name)
+	assert.Equal(t, map[string]string{"app": "web"}, pod.Labels)
+	assert.Equal(t, map[string]string{"pool": "general", corev1.LabelTopologyZone: "eu-west-1b"}, pod.Spec.NodeSelector)
+	assert.Equal(t, map[string]string{"pool": "general"}, deploy.Spec.Template.Spec.NodeSelector, "the template is left alone")
+
+	_, err = client.DryRunWorkloadPod(context.Background(), "db", "StatefulSet", "missing", "eu-west-1b")
+	require.Error(t, err)
+	_, err = client.DryRunWorkloadPod(context.Background(), "db", "DaemonSet", "agent", "eu-west-1b")
+	require.Error(t, err)
+}
+
+func TestNodeUsage_Unfit(t *testing.T) {
+	t.Parallel()
+
+	node := NodeUsage{
+		Name:   "node-a1",
+		Labels: map[string]string{corev1.LabelTopologyZone: "eu-west-1a", "pool": "general"},
+		Taints: []corev1.Taint{
+			{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		},
+		Free: Resources{CPU: 1000, Memory: 2 << 30},
+	}
+	tolerating := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	affinity := func(op corev1.NodeSelectorOperator, values ...string) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: op, Values: values}}}},
+		}}}
+	}
+	requests := func(cpu, memory string) []corev1.Container {
+		return []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+		}}}
+	}
+
+	cases := []struct {
+		name   string
+		spec   corev1.PodSpec
+		reason string
+	}{
+		{name: "fits", spec: corev1.PodSpec{Tolerations: tolerating, Containers: requests("500m", "1Gi")}},
+		{
+			name:   "selector",
+			spec:   corev1.PodSpec{NodeSelector: map[string]string{"pool": "gpu"}, Tolerations: tolerating},
+			reason: "nodeSelector does not match",
+		},
+		{name: "affinity_in", spec: corev1.PodSpec{Affinity: affinity(corev1.NodeSelectorOpIn, "general"), Tolerations: tolerating}},
+		{
+			name:   "affinity_not_in",
+			spec:   corev1.PodSpec{Affinity: affinity(corev1.NodeSelectorOpNotIn, "general"), Tolerations: tolerating},
+			reason: "node affinity does not match",
+		},
+		{name: "taint", spec: corev1.PodSpec{}, reason: "taint dedicated=db:NoSchedule not tolerated"},
+		{
+			name: "tolerate_all",
+			spec: corev1.PodSpec{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+		},
+		{name: "cpu", spec: corev1.PodSpec{Tolerations: tolerating, Containers: requests("2", "1Gi")}, reason: "insufficient cpu"},
+		{name: "memory", spec: corev1.PodSpec{Tolerations: tolerating, Containers: requests("500m", "4Gi")}, reason: "insufficient memory"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.reason, node.Unfit(&corev1.Pod{Spec: tc.spec}))
+		})
+	}
+}
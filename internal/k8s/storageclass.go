@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageClassVolume is the EBS volume a storage class of the EBS CSI driver
+// provisions, from its type, iops, iopsPerGB and throughput parameters
+type StorageClassVolume struct {
+	Provisioner string
+	Type        string // gp3 when the class does not set it, as for the driver
+	IOPS        int32
+	IOPSPerGiB  int32 // IOPS of each GiB of the volume, for io1 and io2
+	Throughput  int32 // MiB/s of gp3 volumes
+}
+
+// GetStorageClassVolume returns the volume the storage class provisions
+func (c *Client) GetStorageClassVolume(ctx context.Context, name string) (*StorageClassVolume, error) {
+	slog.Info("k8s: getting storage class", "name", name)
+	sc, err := c.clientset.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage class %s: %w", name, err)
+	}
+
+	volume := &StorageClassVolume{Provisioner: sc.Provisioner, Type: sc.Parameters["type"]}
+	if volume.Type == "" {
+		volume.Type = "gp3"
+	}
+	for param, field := range map[string]*int32{"iops": &volume.IOPS, "iopsPerGB": &volume.IOPSPerGiB, "throughput": &volume.Throughput} {
+		value, ok := sc.Parameters[param]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("storage class %s has an invalid %s parameter %q", name, param, value)
+		}
+		*field = int32(n)
+	}
+	return volume, nil
+}
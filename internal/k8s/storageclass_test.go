@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClient_GetStorageClassVolume(t *testing.T) {
+	t.Parallel()
+
+	class := func(name string, params map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: DefaultCSIDriver, Parameters: params}
+	}
+	client := NewClientWithInterface(fake.NewSimpleClientset( //nolint:staticcheck // NewClientset requires apply configurations
+		class("default", nil),
+		class("io2", map[string]string{"type": "io2", "iopsPerGB": "50"}),
+		class("fast", map[string]string{"type": "gp3", "iops": "6000", "throughput": "250"}),
+		class("broken", map[string]string{"type": "io2", "iops": "lots"}),
+	), nil)
+
+	cases := []struct {
+		name    string
+		want    *StorageClassVolume
+		wantErr string
+	}{
+		{name: "default", want: &StorageClassVolume{Provisioner: DefaultCSIDriver, Type: "gp3"}},
+		{name: "io2", want: &StorageClassVolume{Provisioner: DefaultCSIDriver, Type: "io2", IOPSPerGiB: 50}},
+		{name: "fast", want: &StorageClassVolume{Provisioner: DefaultCSIDriver, Type: "gp3", IOPS: 6000, Throughput: 250}},
+		{name: "broken", wantErr: `storage class broken has an invalid iops parameter "lots"`},
+		{name: "missing", wantErr: "failed to get storage class missing"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			volume, err := client.GetStorageClassVolume(context.Background(), tc.name)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, volume)
+		})
+	}
+}
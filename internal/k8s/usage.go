@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+)
+
+// countingTransport counts every request made to the API server, retries
+// included, by verb and resource. Requests rejected with 429 Too Many Requests
+// by the server's priority and fairness limits are counted as throttled.
+type countingTransport struct {
+	next    http.RoundTripper
+	counter *apiusage.Counter
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	throttled := err == nil && resp.StatusCode == http.StatusTooManyRequests
+	t.counter.Add(apiusage.ServiceKubernetes, requestOperation(req), throttled)
+	return resp, err
+}
+
+// requestOperation names the API call a request makes as its verb and resource,
+// e.g. "list persistentvolumeclaims" or "update deployments/scale". Requests
+// outside a group version, such as discovery, are named by their method.
+func requestOperation(req *http.Request) string {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return strings.ToLower(req.Method) + " discovery"
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+
+	resource := parts[0]
+	named := len(parts) >= 2
+	if len(parts) >= 3 {
+		resource += "/" + parts[2]
+	}
+
+	var verb string
+	switch req.Method {
+	case http.MethodGet:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			verb = "watch"
+		case named:
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		verb = "delete"
+		if !named {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(req.Method)
+	}
+	return verb + " " + resource
+}
+
+// APIUsage returns the requests the client made to the API server so far, by
+// verb and resource. Clients made for tests count nothing.
+func (c *Client) APIUsage() []apiusage.Count {
+	return c.usage.Counts()
+}
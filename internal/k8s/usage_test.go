@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+)
+
+func TestRequestOperation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{name: "list_namespaced", method: http.MethodGet, url: "/api/v1/namespaces/db/persistentvolumeclaims?limit=500", want: "list persistentvolumeclaims"},
+		{name: "get_namespaced", method: http.MethodGet, url: "/api/v1/namespaces/db/persistentvolumeclaims/data", want: "get persistentvolumeclaims"},
+		{name: "get_cluster_scoped", method: http.MethodGet, url: "/api/v1/persistentvolumes/pv-1", want: "get persistentvolumes"},
+		{name: "list_namespaces", method: http.MethodGet, url: "/api/v1/namespaces", want: "list namespaces"},
+		{name: "patch_namespace", method: http.MethodPatch, url: "/api/v1/namespaces/db", want: "patch namespaces"},
+		{name: "watch", method: http.MethodGet, url: "/api/v1/namespaces/db/pods?watch=true", want: "watch pods"},
+		{name: "group_subresource", method: http.MethodPut, url: "/apis/apps/v1/namespaces/db/statefulsets/web/scale", want: "update statefulsets/scale"},
+		{name: "create", method: http.MethodPost, url: "/apis/batch/v1/namespaces/db/jobs", want: "create jobs"},
+		{name: "delete", method: http.MethodDelete, url: "/api/v1/persistentvolumes/pv-1", want: "delete persistentvolumes"},
+		{name: "discovery", method: http.MethodGet, url: "/apis", want: "get discovery"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, tc.url, nil)
+			assert.Equal(t, tc.want, requestOperation(req))
+		})
+	}
+}
+
+type statusRoundTripper int
+
+func (s statusRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: int(s), Body: http.NoBody}, nil
+}
+
+func TestCountingTransport(t *testing.T) {
+	t.Parallel()
+
+	counter := apiusage.NewCounter()
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusOK} {
+		rt := &countingTransport{next: statusRoundTripper(status), counter: counter}
+		resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/persistentvolumes/pv-1", nil))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	assert.Equal(t, []apiusage.Count{
+		{Service: apiusage.ServiceKubernetes, Operation: "get persistentvolumes", Calls: 2, Throttled: 1},
+	}, counter.Counts())
+}
@@ -0,0 +1,192 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// Labels applied to Kubernetes objects created by the tool
+const (
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	ManagedByValue = "pvc-migrator"
+	LabelWarmup    = "pvc-migrator/warmup"
+	LabelWarmupPVC = "pvc-migrator/pvc"
+)
+
+// Labels stamped on namespaces whose PVCs have all been migrated
+const (
+	LabelZone        = "pvc-migrator/zone"
+	LabelCompletedAt = "pvc-migrator/completed-at"
+)
+
+// DefaultWarmupImage is the container image used by warm-up jobs when none is configured
+const DefaultWarmupImage = "busybox:1.36"
+
+const (
+	warmupMountPath  = "/data"
+	warmupTTLSeconds = int32(600) // Finished jobs are garbage collected after 10 minutes
+	warmupJobPrefix  = "pvc-migrator-warmup-"
+	maxNameLength    = 63 // Label values and the job-name label derived from Job names
+)
+
+// ErrNoClaimConsumer is returned by CreateWarmupJob when no running pod mounts the
+// PVC. The warm-up is skipped rather than risk an RWO volume being attached to the
+// Job's node before the application pod is scheduled.
+var ErrNoClaimConsumer = errors.New("no running pod mounts the PVC")
+
+// WarmupJobName returns the name of the warm-up Job for a PVC. The random suffix
+// keeps re-runs within the TTL and PVCs sharing a long prefix from colliding.
+func WarmupJobName(pvcName, suffix string) string {
+	return truncateName(warmupJobPrefix+pvcName, maxNameLength-len(suffix)-1) + "-" + suffix
+}
+
+// warmupPVCLabel returns a valid label value identifying a PVC. Names longer than a
+// label value allows are truncated and suffixed with a hash so they stay unique.
+func warmupPVCLabel(pvcName string) string {
+	if len(pvcName) <= maxNameLength {
+		return pvcName
+	}
+	sum := sha256.Sum256([]byte(pvcName))
+	hash := hex.EncodeToString(sum[:])[:8]
+	return truncateName(pvcName, maxNameLength-len(hash)-1) + "-" + hash
+}
+
+// truncateName cuts a name to at most n characters without leaving a trailing
+// '-' or '.', which Kubernetes names and label values may not end with
+func truncateName(name string, n int) string {
+	if len(name) > n {
+		name = name[:n]
+	}
+	return strings.TrimRight(name, "-.")
+}
+
+// CreateWarmupJob creates a Job that sequentially reads every file on the PVC so that
+// EBS lazily loads the snapshot blocks in the background. The Job is labelled for the
+// tool and removed automatically by the TTL controller once it has finished.
+//
+// An RWO volume can only be attached to one node, so the Job waits up to timeout for
+// a running pod mounting the PVC and is pinned to that pod's node. If none appears,
+// ErrNoClaimConsumer is returned and no Job is created.
+func (c *Client) CreateWarmupJob(ctx context.Context, namespace, pvcName, image string, timeout time.Duration) (string, error) {
+	if image == "" {
+		image = DefaultWarmupImage
+	}
+
+	nodeName, err := c.waitForClaimNode(ctx, namespace, pvcName, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	labels := map[string]string{
+		LabelManagedBy: ManagedByValue,
+		LabelWarmup:    "true",
+		LabelWarmupPVC: warmupPVCLabel(pvcName),
+	}
+	backoffLimit := int32(2)
+	ttl := warmupTTLSeconds
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:    "warmup",
+				Image:   image,
+				Command: []string{"sh", "-c", fmt.Sprintf("find %s -xdev -type f -exec cat {} + > /dev/null", warmupMountPath)},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "data", MountPath: warmupMountPath, ReadOnly: true},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvcName,
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		NodeSelector: map[string]string{corev1.LabelHostname: nodeName},
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WarmupJobName(pvcName, utilrand.String(5)),
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+
+	slog.Info("k8s: creating warm-up job", "namespace", namespace, "pvc", pvcName, "node", nodeName)
+	created, err := c.clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create warm-up job for PVC %s: %w", pvcName, err)
+	}
+	return created.Name, nil
+}
+
+// waitForClaimNode polls until a pod mounting the PVC is running and returns its node
+func (c *Client) waitForClaimNode(ctx context.Context, namespace, pvcName string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		nodeName, err := c.findNodeForClaim(ctx, namespace, pvcName)
+		if err != nil {
+			return "", err
+		}
+		if nodeName != "" {
+			return nodeName, nil
+		}
+		if !time.Now().Before(deadline) {
+			return "", fmt.Errorf("skipping warm-up for PVC %s: %w", pvcName, ErrNoClaimConsumer)
+		}
+		slog.Debug("k8s: waiting for a running pod to mount the PVC", "namespace", namespace, "pvc", pvcName)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// findNodeForClaim returns the node of a running pod mounting the PVC, or "" if none
+func (c *Client) findNodeForClaim(ctx context.Context, namespace, pvcName string) (string, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" {
+			continue
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == pvcName {
+				return pod.Spec.NodeName, nil
+			}
+		}
+	}
+	return "", nil
+}
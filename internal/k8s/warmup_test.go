@@ -0,0 +1,189 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// helper to create a running pod mounting a PVC
+func newPodWithClaim(namespace, name, nodeName, claimName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestClient_CreateWarmupJob(t *testing.T) {
+	t.Parallel()
+
+	consumer := newPodWithClaim("default", "app-0", "node-a", "data-pvc")
+
+	cases := []struct {
+		name         string
+		objects      []runtime.Object
+		image        string
+		wantImage    string
+		wantNodeName string
+		wantErr      error
+	}{
+		{
+			name:         "default_image",
+			objects:      []runtime.Object{consumer},
+			wantImage:    DefaultWarmupImage,
+			wantNodeName: "node-a",
+		},
+		{
+			name:         "custom_image",
+			objects:      []runtime.Object{consumer},
+			image:        "alpine:3.20",
+			wantImage:    "alpine:3.20",
+			wantNodeName: "node-a",
+		},
+		{
+			name: "pinned_to_consumer_node",
+			objects: []runtime.Object{
+				newPodWithClaim("default", "other", "node-b", "other-pvc"),
+				consumer,
+			},
+			wantImage:    DefaultWarmupImage,
+			wantNodeName: "node-a",
+		},
+		{
+			name:    "no_consumer_skipped",
+			wantErr: ErrNoClaimConsumer,
+		},
+		{
+			name: "pending_consumer_skipped",
+			objects: []runtime.Object{
+				func() *corev1.Pod {
+					pod := newPodWithClaim("default", "app-0", "", "data-pvc")
+					pod.Status.Phase = corev1.PodPending
+					return pod
+				}(),
+			},
+			wantErr: ErrNoClaimConsumer,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(tc.objects...)
+			ctx := context.Background()
+
+			jobName, err := client.CreateWarmupJob(ctx, "default", "data-pvc", tc.image, 0)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				jobs, err := client.clientset.BatchV1().Jobs("default").List(ctx, metav1.ListOptions{})
+				require.NoError(t, err)
+				assert.Empty(t, jobs.Items, "no job may race the application pod for the volume")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(jobName, "pvc-migrator-warmup-data-pvc-"), jobName)
+
+			job, err := client.clientset.BatchV1().Jobs("default").Get(ctx, jobName, metav1.GetOptions{})
+			require.NoError(t, err)
+
+			assert.Equal(t, ManagedByValue, job.Labels[LabelManagedBy])
+			assert.Equal(t, "true", job.Labels[LabelWarmup])
+			assert.Equal(t, "data-pvc", job.Labels[LabelWarmupPVC])
+			require.NotNil(t, job.Spec.TTLSecondsAfterFinished)
+
+			podSpec := job.Spec.Template.Spec
+			require.Len(t, podSpec.Containers, 1)
+			assert.Equal(t, tc.wantImage, podSpec.Containers[0].Image)
+			require.Len(t, podSpec.Volumes, 1)
+			assert.Equal(t, "data-pvc", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+			assert.True(t, podSpec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+			assert.Equal(t, tc.wantNodeName, podSpec.NodeSelector[corev1.LabelHostname])
+		})
+	}
+}
+
+func TestClient_CreateWarmupJob_Rerun(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(newPodWithClaim("default", "app-0", "node-a", "data-pvc"))
+	ctx := context.Background()
+
+	first, err := client.CreateWarmupJob(ctx, "default", "data-pvc", "", 0)
+	require.NoError(t, err)
+	second, err := client.CreateWarmupJob(ctx, "default", "data-pvc", "", 0)
+	require.NoError(t, err, "a re-run within the TTL must not collide with the previous job")
+	assert.NotEqual(t, first, second)
+}
+
+func TestWarmupJobName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pvcName string
+		want    string
+	}{
+		{
+			name:    "short",
+			pvcName: "data",
+			want:    "pvc-migrator-warmup-data-abcde",
+		},
+		{
+			name:    "truncated",
+			pvcName: strings.Repeat("a", 100),
+			want:    "pvc-migrator-warmup-" + strings.Repeat("a", 37) + "-abcde",
+		},
+		{
+			name:    "no_trailing_separator",
+			pvcName: strings.Repeat("a", 36) + "-data",
+			want:    "pvc-migrator-warmup-" + strings.Repeat("a", 36) + "-abcde",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := WarmupJobName(tc.pvcName, "abcde")
+			assert.Equal(t, tc.want, got)
+			assert.LessOrEqual(t, len(got), 63)
+		})
+	}
+}
+
+func TestWarmupPVCLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "data-pvc", warmupPVCLabel("data-pvc"))
+
+	// Long names sharing a prefix must map to distinct, valid label values
+	a := warmupPVCLabel(strings.Repeat("a", 54) + "-" + strings.Repeat("x", 20))
+	b := warmupPVCLabel(strings.Repeat("a", 54) + "-" + strings.Repeat("y", 20))
+	assert.NotEqual(t, a, b)
+	for _, v := range []string{a, b} {
+		assert.LessOrEqual(t, len(v), 63)
+		assert.Empty(t, validation.IsValidLabelValue(v), v)
+	}
+}
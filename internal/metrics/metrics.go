@@ -0,0 +1,254 @@
+// Package metrics exposes Prometheus metrics for migration runs.
+// Metrics are fed from migrator events and EC2 API hooks, and can be scraped
+// from an HTTP endpoint, pushed to a Pushgateway or written for the
+// node_exporter textfile collector when the run ends.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+const metricNamespace = "pvc_migrator"
+
+// TextfileName is the file written in the textfile collector directory
+const TextfileName = "pvc_migrator.prom"
+
+// Result label values for the PVC counter
+const (
+	ResultMigrated = "migrated"
+	ResultFailed   = "failed"
+	ResultSkipped  = "skipped"
+)
+
+// stepState tracks the step a PVC is in, when it entered it and when the PVC
+// started being migrated
+type stepState struct {
+	step    string
+	since   time.Time
+	started time.Time
+}
+
+// Metrics holds the collectors for a single migration run
+type Metrics struct {
+	registry      *prometheus.Registry
+	pvcs          *prometheus.CounterVec
+	inProgress    prometheus.Gauge
+	snapshotBytes prometheus.Counter
+	stepDuration  *prometheus.HistogramVec
+	awsErrors     *prometheus.CounterVec
+	runStart      prometheus.Gauge
+	runEnd        prometheus.Gauge
+	namespacePVCs *prometheus.CounterVec
+	pvcDuration   *prometheus.HistogramVec
+
+	mu    sync.Mutex
+	steps map[string]stepState
+}
+
+// New creates the collectors and registers them on a dedicated registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		pvcs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "pvcs_total",
+			Help:      "PVCs that finished processing, by result (migrated, failed, skipped).",
+		}, []string{"result"}),
+		inProgress: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "pvcs_in_progress",
+			Help:      "PVCs currently being migrated.",
+		}),
+		snapshotBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "snapshot_bytes_total",
+			Help:      "Size of the source volumes of completed snapshots, in bytes.",
+		}),
+		stepDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Name:      "step_duration_seconds",
+			Help:      "Time spent in each migration step.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200},
+		}, []string{"step"}),
+		awsErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "aws_api_errors_total",
+			Help:      "EC2 API calls that returned an error, by operation.",
+		}, []string{"operation"}),
+		runStart: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "run_start_timestamp_seconds",
+			Help:      "Unix time the migration run started.",
+		}),
+		runEnd: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "run_end_timestamp_seconds",
+			Help:      "Unix time the migration run finished; 0 while it is running.",
+		}),
+		namespacePVCs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "namespace_pvcs_total",
+			Help:      "PVCs that finished processing, by namespace and result.",
+		}, []string{"namespace", "result"}),
+		pvcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Name:      "pvc_duration_seconds",
+			Help:      "Time from the first step of a PVC's migration until it was done or failed, by namespace.",
+			Buckets:   []float64{60, 300, 600, 1200, 1800, 3600, 7200, 14400, 28800},
+		}, []string{"namespace"}),
+		steps: make(map[string]stepState),
+	}
+
+	m.registry.MustRegister(m.pvcs, m.inProgress, m.snapshotBytes, m.stepDuration, m.awsErrors, m.runStart,
+		m.runEnd, m.namespacePVCs, m.pvcDuration)
+	m.runStart.SetToCurrentTime()
+
+	// Export zero values so alerts can use rate() from the first scrape
+	for _, r := range []string{ResultMigrated, ResultFailed, ResultSkipped} {
+		m.pvcs.WithLabelValues(r)
+	}
+	return m
+}
+
+// Registry returns the registry holding the run's collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Observe updates the metrics from a migrator event. It has the signature of a
+// migrator.EventListener.
+func (m *Metrics) Observe(e migrator.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev := m.steps[e.PVC]
+	if prev.step == e.Step {
+		return
+	}
+	wasActive := isActive(prev.step)
+	started := prev.started
+	if isActive(e.Step) && !wasActive {
+		started = e.Time
+	}
+	m.steps[e.PVC] = stepState{step: e.Step, since: e.Time, started: started}
+
+	if wasActive {
+		m.stepDuration.WithLabelValues(stepLabel(prev.step)).Observe(e.Time.Sub(prev.since).Seconds())
+	}
+
+	// The snapshot is complete once the volume is being created from it
+	if e.Step == migrator.StepCreateVolume.String() && e.SizeGiB > 0 {
+		m.snapshotBytes.Add(float64(int64(e.SizeGiB) << 30))
+	}
+
+	switch {
+	case isActive(e.Step) && !wasActive:
+		m.inProgress.Inc()
+	case !isActive(e.Step) && wasActive:
+		m.inProgress.Dec()
+	}
+
+	var result string
+	switch e.Step {
+	case migrator.StepDone.String():
+		result = ResultMigrated
+	case migrator.StepFailed.String():
+		result = ResultFailed
+	case migrator.StepSkipped.String():
+		result = ResultSkipped
+	default:
+		return
+	}
+	m.pvcs.WithLabelValues(result).Inc()
+	m.namespacePVCs.WithLabelValues(e.Namespace, result).Inc()
+	if wasActive && !started.IsZero() {
+		m.pvcDuration.WithLabelValues(e.Namespace).Observe(e.Time.Sub(started).Seconds())
+	}
+}
+
+// Finish records the end of the run. It is called before the final values are
+// pushed or written.
+func (m *Metrics) Finish() {
+	m.runEnd.SetToCurrentTime()
+}
+
+// ObserveAPICall counts failed EC2 API calls. It has the signature of an aws.APIHook.
+func (m *Metrics) ObserveAPICall(operation string, err error) {
+	if err != nil {
+		m.awsErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// Handler returns an HTTP handler serving the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Serve starts an HTTP server exposing /metrics on addr. The listener is bound
+// before returning so address errors are reported immediately.
+func (m *Metrics) Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = ln.Close()
+		}
+	}()
+	return srv, nil
+}
+
+// Push sends the current metrics to a Prometheus Pushgateway under the given job name
+func (m *Metrics) Push(ctx context.Context, url, job string) error {
+	if err := push.New(url, job).Gatherer(m.registry).PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", url, err)
+	}
+	return nil
+}
+
+// WriteTextfile writes the current metrics to TextfileName in dir for the
+// node_exporter textfile collector. The file is replaced atomically so the
+// collector never reads a partial file.
+func (m *Metrics) WriteTextfile(dir string) error {
+	path := filepath.Join(dir, TextfileName)
+	if err := prometheus.WriteToTextfile(path, m.registry); err != nil {
+		return fmt.Errorf("failed to write metrics to %s: %w", path, err)
+	}
+	return nil
+}
+
+// isActive reports whether a step name is one of the in-flight migration steps
+func isActive(step string) bool {
+	switch step {
+	case "", migrator.StepPending.String(), migrator.StepDone.String(),
+		migrator.StepFailed.String(), migrator.StepSkipped.String():
+		return false
+	default:
+		return true
+	}
+}
+
+// stepLabel turns a step name such as "Creating Snapshot" into "creating_snapshot"
+func stepLabel(step string) string {
+	return strings.ToLower(strings.ReplaceAll(step, " ", "_"))
+}
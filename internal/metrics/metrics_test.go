@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+func event(pvc string, step migrator.Step, at time.Time) migrator.Event {
+	ns, _, _ := strings.Cut(pvc, "/")
+	return migrator.Event{PVC: pvc, Namespace: ns, Step: step.String(), Time: at, SizeGiB: 10}
+}
+
+func TestMetrics_Observe(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	t0 := time.Unix(1000, 0)
+
+	m.Observe(event("ns/a", migrator.StepGetInfo, t0))
+	m.Observe(event("ns/a", migrator.StepSnapshot, t0.Add(time.Second)))
+	m.Observe(event("ns/b", migrator.StepGetInfo, t0))
+	assert.InDelta(t, 2, testutil.ToFloat64(m.inProgress), 0)
+
+	m.Observe(event("ns/a", migrator.StepWaitSnapshot, t0.Add(2*time.Second)))
+	m.Observe(event("ns/a", migrator.StepWaitSnapshot, t0.Add(30*time.Second))) // progress only
+	m.Observe(event("ns/a", migrator.StepCreateVolume, t0.Add(62*time.Second)))
+	m.Observe(event("ns/a", migrator.StepDone, t0.Add(70*time.Second)))
+	m.Observe(event("ns/b", migrator.StepSkipped, t0.Add(time.Second)))
+
+	assert.InDelta(t, 0, testutil.ToFloat64(m.inProgress), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.pvcs.WithLabelValues(ResultMigrated)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.pvcs.WithLabelValues(ResultSkipped)), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(m.pvcs.WithLabelValues(ResultFailed)), 0)
+	assert.InDelta(t, float64(10<<30), testutil.ToFloat64(m.snapshotBytes), 0)
+
+	// One series each for getting_info, creating_snapshot, snapshot_progress and creating_volume
+	assert.Equal(t, 4, testutil.CollectAndCount(m.stepDuration))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.namespacePVCs.WithLabelValues("ns", ResultMigrated)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.namespacePVCs.WithLabelValues("ns", ResultSkipped)), 0)
+	// Only ns/a ran to completion; the skipped PVC has no duration
+	assert.Equal(t, 1, testutil.CollectAndCount(m.pvcDuration))
+}
+
+func TestMetrics_WriteTextfile(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	t0 := time.Unix(1000, 0)
+	m.Observe(event("db/data-0", migrator.StepGetInfo, t0))
+	m.Observe(event("db/data-0", migrator.StepDone, t0.Add(90*time.Second)))
+	m.Observe(event("web/static", migrator.StepGetInfo, t0))
+	m.Observe(event("web/static", migrator.StepFailed, t0.Add(time.Minute)))
+	m.Finish()
+
+	dir := t.TempDir()
+	require.NoError(t, m.WriteTextfile(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, TextfileName))
+	require.NoError(t, err)
+	out := string(data)
+	assert.Contains(t, out, `pvc_migrator_namespace_pvcs_total{namespace="db",result="migrated"} 1`)
+	assert.Contains(t, out, `pvc_migrator_namespace_pvcs_total{namespace="web",result="failed"} 1`)
+	assert.Contains(t, out, `pvc_migrator_pvc_duration_seconds_sum{namespace="db"} 90`)
+	assert.NotContains(t, out, "pvc_migrator_run_end_timestamp_seconds 0")
+
+	require.Error(t, m.WriteTextfile(filepath.Join(dir, "missing")))
+}
+
+func TestMetrics_ObserveAPICall(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.ObserveAPICall("CreateSnapshot", nil)
+	m.ObserveAPICall("CreateSnapshot", errors.New("throttled"))
+	m.ObserveAPICall("DescribeVolumes", errors.New("throttled"))
+	m.ObserveAPICall("DescribeVolumes", errors.New("throttled"))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.awsErrors.WithLabelValues("CreateSnapshot")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(m.awsErrors.WithLabelValues("DescribeVolumes")), 0)
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.Observe(event("ns/a", migrator.StepGetInfo, time.Now()))
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	out := string(body)
+	assert.Contains(t, out, "pvc_migrator_pvcs_in_progress 1")
+	assert.Contains(t, out, `pvc_migrator_pvcs_total{result="failed"} 0`)
+	assert.Contains(t, out, "pvc_migrator_run_start_timestamp_seconds")
+}
+
+func TestMetrics_Push(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	gw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gw.Close()
+
+	m := New()
+	require.NoError(t, m.Push(context.Background(), gw.URL, "pvc_migrator"))
+	assert.True(t, strings.HasSuffix(gotPath, "/job/pvc_migrator"), gotPath)
+}
+
+func TestStepLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "creating_snapshot", stepLabel("Creating Snapshot"))
+	assert.Equal(t, "getting_info", stepLabel(migrator.StepGetInfo.String()))
+}
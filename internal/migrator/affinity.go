@@ -0,0 +1,138 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AffinityPatch pins the pods of a Deployment or StatefulSet to the zone the
+// PVCs they mount were migrated to
+type AffinityPatch struct {
+	Namespace string
+	Kind      string // "Deployment" or "StatefulSet"
+	Name      string
+	Zone      string
+}
+
+// Path returns the file the patch is written to under dir
+func (p AffinityPatch) Path(dir string) string {
+	return filepath.Join(dir, p.Namespace, strings.ToLower(p.Kind)+"-"+p.Name+"-zone.yaml")
+}
+
+// Manifest returns the patch as a strategic merge patch adding the zone to the
+// nodeSelector of the workload's pod template, for kustomize
+func (p AffinityPatch) Manifest() string {
+	return fmt.Sprintf(`apiVersion: apps/v1
+kind: %s
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  template:
+    spec:
+      nodeSelector:
+        topology.kubernetes.io/zone: %s
+`, p.Kind, p.Name, p.Namespace, p.Zone)
+}
+
+// claimZones returns the zone each PVC of the run is in once it is over, by
+// namespace and claim, for those migrated or already in their target zone. The
+// namespaces with a PVC migrated are returned too.
+func (m *Migrator) claimZones() (map[string]map[string]string, map[string]bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	zones := make(map[string]map[string]string)
+	migrated := make(map[string]bool)
+	for _, s := range m.statuses {
+		var zone string
+		switch s.Step {
+		case StepDone:
+			zone = s.TargetZone
+			if zone == "" {
+				zone = m.config.TargetZone
+			}
+			migrated[s.Namespace] = true
+		case StepSkipped:
+			zone = s.CurrentZone
+		default:
+			continue
+		}
+		if zones[s.Namespace] == nil {
+			zones[s.Namespace] = make(map[string]string)
+		}
+		zones[s.Namespace][s.PVCName] = zone
+	}
+	return zones, migrated
+}
+
+// AffinityPatches returns the patches pinning the Deployments and StatefulSets
+// that mount a PVC the run migrated to the zone of their PVCs. Workloads whose
+// PVCs are in different zones, such as a StatefulSet spread across zones, are
+// left out, as no single zone suits all their pods.
+func (m *Migrator) AffinityPatches(ctx context.Context) ([]AffinityPatch, error) {
+	zones, migrated := m.claimZones()
+	namespaces := make([]string, 0, len(migrated))
+	for ns := range migrated {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var patches []AffinityPatch
+	for _, ns := range namespaces {
+		claims := make([]string, 0, len(zones[ns]))
+		for claim := range zones[ns] {
+			claims = append(claims, claim)
+		}
+		sort.Strings(claims)
+		workloads, err := m.k8sClient.ClaimWorkloads(ctx, ns, claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the workloads of namespace %s: %w", ns, err)
+		}
+		for _, w := range workloads {
+			zone := zones[ns][w.Claims[0]]
+			mixed := false
+			for _, claim := range w.Claims[1:] {
+				mixed = mixed || zones[ns][claim] != zone
+			}
+			if mixed {
+				slog.Warn("PVCs of workload are in different zones, not pinning it", "namespace", ns, "kind", w.Kind, "name", w.Name)
+				continue
+			}
+			patches = append(patches, AffinityPatch{Namespace: ns, Kind: w.Kind, Name: w.Name, Zone: zone})
+		}
+	}
+	return patches, nil
+}
+
+// WriteAffinityPatches writes each patch to its file under dir
+func WriteAffinityPatches(dir string, patches []AffinityPatch) error {
+	for _, p := range patches {
+		path := p.Path(dir)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("failed to create affinity patch directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(p.Manifest()), 0o600); err != nil {
+			return fmt.Errorf("failed to write affinity patch: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyAffinityPatches pins each workload to its zone in the cluster, going on
+// with the rest when one fails
+func (m *Migrator) ApplyAffinityPatches(ctx context.Context, patches []AffinityPatch) error {
+	var errs []error
+	for _, p := range patches {
+		if err := m.k8sClient.SetWorkloadZone(ctx, p.Namespace, p.Kind, p.Name, p.Zone); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", p.Namespace, p.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestAffinityPatches(t *testing.T) {
+	t.Parallel()
+
+	template := func(claims ...string) corev1.PodTemplateSpec {
+		var tmpl corev1.PodTemplateSpec
+		for _, claim := range claims {
+			tmpl.Spec.Volumes = append(tmpl.Spec.Volumes, corev1.Volume{
+				Name:         claim,
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+			})
+		}
+		return tmpl
+	}
+	clientset := bindingClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Template: template("uploads", "cache")}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Template: template("uploads", "spool")}},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+			},
+		},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "blog", Namespace: "news"}, Spec: appsv1.DeploymentSpec{Template: template("posts")}},
+	)
+	m := New(&Config{
+		PVCList:    []string{"shop/uploads", "shop/cache", "shop/spool", "shop/data-db-0", "news/posts"},
+		TargetZone: "eu-west-1a",
+	}, k8s.NewClientWithInterface(clientset, nil), nil)
+	m.statuses["shop/uploads"].Step = StepDone
+	m.statuses["shop/cache"].Step = StepSkipped
+	m.statuses["shop/cache"].CurrentZone = "eu-west-1a"
+	m.statuses["shop/spool"].Step = StepSkipped
+	m.statuses["shop/spool"].CurrentZone = "eu-west-1b"
+	m.statuses["shop/data-db-0"].Step = StepDone
+	m.statuses["news/posts"].Step = StepFailed
+
+	ctx := context.Background()
+	patches, err := m.AffinityPatches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []AffinityPatch{
+		{Namespace: "shop", Kind: "Deployment", Name: "web", Zone: "eu-west-1a"},
+		{Namespace: "shop", Kind: "StatefulSet", Name: "db", Zone: "eu-west-1a"},
+	}, patches, "workloads with PVCs in two zones and namespaces with none migrated are left out")
+
+	dir := t.TempDir()
+	require.NoError(t, WriteAffinityPatches(dir, patches))
+	data, err := os.ReadFile(filepath.Join(dir, "shop", "statefulset-db-zone.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, patches[1].Manifest(), string(data))
+	assert.Contains(t, string(data), "kind: StatefulSet\nmetadata:\n  name: db\n  namespace: shop\n")
+	assert.Contains(t, string(data), "topology.kubernetes.io/zone: eu-west-1a\n")
+
+	require.NoError(t, m.ApplyAffinityPatches(ctx, patches))
+	deploy, err := clientset.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1a", deploy.Spec.Template.Spec.NodeSelector[corev1.LabelTopologyZone])
+	worker, err := clientset.AppsV1().Deployments("shop").Get(ctx, "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, worker.Spec.Template.Spec.NodeSelector)
+}
@@ -0,0 +1,232 @@
+package migrator
+
+import (
+	"maps"
+	"sort"
+	"time"
+
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+// API returns the plan as the versioned document written by --output json
+func (p *MigrationPlan) API() apiv1.Plan {
+	plan := apiv1.Plan{
+		APIVersion:   apiv1.APIVersion,
+		Kind:         apiv1.KindPlan,
+		TargetZone:   p.TargetZone,
+		StorageClass: p.StorageClass,
+		DryRun:       p.DryRun,
+		Namespaces:   append([]string{}, p.Namespaces...),
+		Concurrency:  p.Concurrency,
+		Items:        make([]apiv1.PlanItem, 0, len(p.Items)),
+		KMSKeyID:     p.KMSKeyID,
+	}
+	if p.Encryption != nil {
+		plan.EncryptionByDefault = &p.Encryption.ByDefault
+		plan.DefaultKMSKeyID = p.Encryption.KMSKeyID
+	}
+	for _, item := range p.Items {
+		apiItem := apiv1.PlanItem{
+			PVC:              item.Name,
+			Namespace:        item.Namespace,
+			Name:             item.PVCName,
+			Action:           planActions[item.Action],
+			Reason:           item.Reason,
+			PVName:           item.PVName,
+			VolumeID:         item.VolumeID,
+			Capacity:         item.Capacity,
+			SizeGiB:          item.CapacityGi,
+			CurrentZone:      item.CurrentZone,
+			TargetZone:       item.TargetZone,
+			StorageClass:     item.StorageClass,
+			Attached:         item.Attached,
+			ClaimPhase:       item.ClaimPhase,
+			PVPhase:          item.PVPhase,
+			PVMissing:        item.PVMissing,
+			Priority:         item.Priority,
+			Window:           item.Window,
+			CoMountedWith:    item.CoMountedWith,
+			StagedSnapshotID: item.StagedSnapshotID,
+			SourceKMSKeyID:   item.SourceKMSKeyID,
+			KMSKeyID:         item.KMSKeyID,
+			Tags:             item.Tags,
+		}
+		apiItem.StagedSnapshotTime = timeOrNil(item.StagedSnapshotTime)
+		if item.Block {
+			apiItem.VolumeMode = string(item.volumeMode())
+		}
+		if item.Action == PlanActionMigrate {
+			apiItem.VolumeType = item.VolumeSpec.VolumeType()
+			apiItem.IOPS = item.VolumeSpec.IOPS
+			apiItem.Throughput = item.VolumeSpec.Throughput
+			apiItem.BlockExpress = item.VolumeSpec.BlockExpress(item.CapacityGi)
+		}
+		if item.ResumeAt != StepPending {
+			apiItem.ResumeAt = item.ResumeAt.String()
+		}
+		plan.Items = append(plan.Items, apiItem)
+	}
+	for _, c := range p.AutoZones {
+		plan.AutoZones = append(plan.AutoZones, apiv1.ZoneChoice{
+			Namespace:     c.Namespace,
+			Zone:          c.Zone,
+			Excluded:      append([]string{}, c.Excluded...),
+			Nodes:         c.Nodes,
+			CPUPercent:    c.CPUPercent,
+			MemoryPercent: c.MemoryPercent,
+		})
+	}
+	for _, issue := range p.NodeIssues {
+		plan.NodeIssues = append(plan.NodeIssues, apiv1.NodeIssue{
+			Zone:            issue.Zone,
+			Workload:        issue.Workload,
+			PVCs:            append([]string{}, issue.Claims...),
+			Unprovisionable: issue.Unprovisionable,
+		})
+	}
+	for _, s := range p.Schedulability {
+		plan.Schedulability = append(plan.Schedulability, apiv1.WorkloadSchedule{
+			Workload: s.Namespace + "/" + s.Kind + "/" + s.Name,
+			Zone:     s.Zone,
+			Admitted: s.Admitted,
+			Nodes:    s.Nodes,
+			Reason:   s.Reason,
+		})
+	}
+	plan.FastSnapshotRestore = p.FastRestore
+	if !p.KeepVolume {
+		plan.VolumeType = p.VolumeSpec.VolumeType()
+	}
+	plan.IOPS = p.VolumeSpec.IOPS
+	plan.Throughput = p.VolumeSpec.Throughput
+	plan.MissingPermissions = append([]string(nil), p.MissingPermissions...)
+	if len(p.ZoneIDs) > 0 {
+		plan.ZoneIDs = maps.Clone(p.ZoneIDs)
+	}
+	if q := p.Quotas; q != nil {
+		plan.Quotas = &apiv1.QuotaCheck{
+			Snapshots:     q.Snapshots,
+			NewSnapshots:  q.NewSnapshots,
+			SnapshotQuota: q.SnapshotQuota,
+			Storage:       make([]apiv1.StorageQuota, 0, len(q.Storage)),
+		}
+		for _, s := range q.Storage {
+			plan.Quotas.Storage = append(plan.Quotas.Storage, apiv1.StorageQuota{
+				VolumeType:    s.VolumeType,
+				StorageGiB:    s.StorageGiB,
+				NewStorageGiB: s.NewStorageGiB,
+				QuotaGiB:      s.QuotaGiB,
+			})
+		}
+	}
+	return plan
+}
+
+// planActions maps plan actions to their names in the versioned documents
+var planActions = map[PlanAction]string{
+	PlanActionMigrate: apiv1.ActionMigrate,
+	PlanActionSkip:    apiv1.ActionSkip,
+	PlanActionError:   apiv1.ActionError,
+}
+
+// Result returns the outcome of the run as the versioned document written by
+// --output json, with the PVCs sorted by name
+func (m *Migrator) Result() apiv1.Result {
+	statuses := m.GetStatuses()
+	remediations := make(map[string]Remediation)
+	for _, r := range m.Remediations() {
+		remediations[r.PVC] = r
+	}
+
+	result := apiv1.Result{
+		APIVersion:  apiv1.APIVersion,
+		Kind:        apiv1.KindResult,
+		TargetZone:  m.config.Destination(),
+		MigrationID: m.config.MigrationID,
+		DryRun:      m.config.DryRun,
+		Total:       len(statuses),
+		PVCs:        make([]apiv1.PVCResult, 0, len(statuses)),
+		Warnings:    make([]apiv1.Warning, 0),
+	}
+
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := statuses[name]
+		pvc := apiv1.PVCResult{
+			PVC:             s.Name,
+			Namespace:       s.Namespace,
+			Name:            s.PVCName,
+			Outcome:         apiv1.OutcomeIncomplete,
+			Step:            s.Step.String(),
+			SourceZone:      s.CurrentZone,
+			TargetZone:      s.TargetZone,
+			SourceVolumeID:  s.OldVolumeID,
+			SnapshotID:      s.SnapshotID,
+			BackupJobID:     s.BackupJobID,
+			VolumeID:        s.NewVolumeID,
+			AdoptedSnapshot: s.AdoptedSnapshot,
+			AdoptedVolume:   s.AdoptedVolume,
+			MountCheck:      s.MountCheck,
+			Checksum:        s.Checksum,
+			SizeGiB:         s.SizeGiB,
+			StartTime:       timeOrNil(s.StartTime),
+			EndTime:         timeOrNil(s.EndTime),
+		}
+		if s.ResumedAt != StepPending {
+			pvc.ResumedAt = s.ResumedAt.String()
+		}
+		pvc.ChecksumVerified = s.ChecksumVerified
+		switch s.Step {
+		case StepDone:
+			pvc.Outcome = apiv1.OutcomeMigrated
+			result.Migrated++
+		case StepSkipped:
+			pvc.Outcome = apiv1.OutcomeSkipped
+			result.Skipped++
+		case StepFailed:
+			pvc.Outcome = apiv1.OutcomeFailed
+			pvc.Step = s.FailedStep.String()
+			if s.Error != nil {
+				pvc.Error = s.Error.Error()
+			}
+			pvc.Finish = remediations[name].Finish
+			pvc.Rollback = remediations[name].Rollback
+			result.Failed++
+		case StepPending, StepGetInfo, StepSnapshot, StepWaitSnapshot, StepCreateVolume,
+			StepWaitVolume, StepCleanup, StepCreatePV, StepCreatePVC:
+			// Left incomplete by a cancelled run
+		}
+		result.PVCs = append(result.PVCs, pvc)
+	}
+
+	for _, w := range m.Warnings() {
+		result.Warnings = append(result.Warnings, apiv1.Warning{Time: w.Time, PVC: w.PVC, Message: w.Message, Action: w.Action})
+	}
+	for _, o := range m.Orphans() {
+		result.Orphans = append(result.Orphans, apiv1.Orphan{
+			PVC: o.PVC, Kind: o.Kind, Name: o.Name, VolumeID: o.VolumeID, Reason: o.Reason,
+			Delete: o.DeleteCommand(m.config.KubeContext),
+		})
+	}
+	for _, s := range m.Stragglers() {
+		result.Stragglers = append(result.Stragglers, apiv1.Straggler{PVC: s.PVC, PVName: s.PVName, VolumeID: s.VolumeID})
+	}
+	for _, c := range m.APIUsage() {
+		result.APIUsage = append(result.APIUsage, apiv1.APIUsage{
+			Service: c.Service, Operation: c.Operation, Calls: c.Calls, Throttled: c.Throttled,
+		})
+	}
+	return result
+}
+
+// timeOrNil returns a pointer to t, or nil when it is zero so it is left out
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
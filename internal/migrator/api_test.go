@@ -0,0 +1,106 @@
+package migrator
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+func TestMigrationPlan_API(t *testing.T) {
+	t.Parallel()
+
+	staged := time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC)
+	plan := &MigrationPlan{
+		TargetZone:   "eu-west-1a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Concurrency:  2,
+		KMSKeyID:     "alias/prod",
+		Encryption:   &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/aws/ebs"},
+		Items: []PVCPlanItem{
+			{
+				Name: "db/data", Namespace: "db", PVCName: "data", PVName: "pvc-1", VolumeID: "vol-1",
+				Capacity: "10Gi", CapacityGi: 10, CurrentZone: "eu-west-1b", TargetZone: "eu-west-1a",
+				Action: PlanActionMigrate, Attached: true, Priority: PriorityHigh,
+				StagedSnapshotID: "snap-1", StagedSnapshotTime: staged,
+				SourceKMSKeyID: "alias/old", KMSKeyID: "alias/prod",
+			},
+			{Name: "db/logs", Namespace: "db", PVCName: "logs", Action: PlanActionSkip, Reason: "already in target zone"},
+		},
+	}
+
+	doc := plan.API()
+	assert.Equal(t, apiv1.APIVersion, doc.APIVersion)
+	assert.Equal(t, apiv1.KindPlan, doc.Kind)
+	require.Len(t, doc.Items, 2)
+	assert.Equal(t, apiv1.ActionMigrate, doc.Items[0].Action)
+	assert.Equal(t, int32(10), doc.Items[0].SizeGiB)
+	assert.Equal(t, &staged, doc.Items[0].StagedSnapshotTime)
+	assert.Equal(t, apiv1.ActionSkip, doc.Items[1].Action)
+	assert.Nil(t, doc.Items[1].StagedSnapshotTime)
+	assert.Equal(t, "alias/prod", doc.KMSKeyID)
+	require.NotNil(t, doc.EncryptionByDefault)
+	assert.True(t, *doc.EncryptionByDefault)
+	assert.Equal(t, "alias/aws/ebs", doc.DefaultKMSKeyID)
+	assert.Equal(t, "alias/old", doc.Items[0].SourceKMSKeyID)
+	assert.Equal(t, "alias/prod", doc.Items[0].KMSKeyID)
+
+	data, err := json.Marshal(doc.Items[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"pvc":"db/logs","namespace":"db","name":"logs","action":"skip","reason":"already in target zone","attached":false}`, string(data))
+}
+
+func TestMigrator_Result(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/b", "ns/a", "ns/ok", "ns/new"}, TargetZone: "eu-west-1a", MigrationID: "run-1"}, nil, nil)
+	m.updateStatus("ns/b", StepSnapshot, 0, nil)
+	m.updateStatus("ns/b", StepFailed, 0, errors.New("throttled"))
+	m.updateStatus("ns/a", StepSkipped, 100, nil)
+	m.updateStatus("ns/ok", StepDone, 100, nil)
+	m.statuses["ns/ok"].AdoptedVolume = true
+	m.statuses["ns/ok"].ResumedAt = StepCreatePVC
+	m.AddWarning(Warning{Message: "ArgoCD auto-sync was not re-enabled"})
+	m.orphans = append(m.orphans, OrphanedObject{PVC: "ns/b", Kind: "PersistentVolume", Name: "b-static", VolumeID: "vol-new"})
+
+	result := m.Result()
+	assert.Equal(t, apiv1.KindResult, result.Kind)
+	assert.Equal(t, "eu-west-1a", result.TargetZone)
+	assert.Equal(t, "run-1", result.MigrationID)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 1, result.Migrated)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 1, result.Failed)
+
+	require.Len(t, result.PVCs, 4)
+	outcomes := make(map[string]string)
+	for _, pvc := range result.PVCs {
+		outcomes[pvc.PVC] = pvc.Outcome
+	}
+	assert.Equal(t, map[string]string{
+		"ns/a": apiv1.OutcomeSkipped, "ns/b": apiv1.OutcomeFailed, "ns/new": apiv1.OutcomeIncomplete, "ns/ok": apiv1.OutcomeMigrated,
+	}, outcomes)
+
+	failed := result.PVCs[1]
+	assert.Equal(t, "ns/b", failed.PVC, "sorted by name")
+	assert.Equal(t, StepSnapshot.String(), failed.Step, "the step that failed")
+	assert.Equal(t, "throttled", failed.Error)
+	assert.NotEmpty(t, failed.Finish)
+	assert.True(t, result.PVCs[3].AdoptedVolume)
+	assert.False(t, result.PVCs[3].AdoptedSnapshot)
+	assert.Equal(t, StepCreatePVC.String(), result.PVCs[3].ResumedAt)
+	assert.Empty(t, failed.ResumedAt)
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "ArgoCD auto-sync was not re-enabled", result.Warnings[0].Message)
+
+	require.Len(t, result.Orphans, 1)
+	assert.Equal(t, "kubectl delete persistentvolume b-static --ignore-not-found", result.Orphans[0].Delete)
+}
@@ -0,0 +1,172 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// TargetZoneAuto as the target zone has GeneratePlan pick the least-loaded
+// healthy zone for each namespace
+const TargetZoneAuto = "auto"
+
+// ZoneChoice is the zone GeneratePlan picked for the PVCs of a namespace with
+// TargetZoneAuto, and the load that made it the pick
+type ZoneChoice struct {
+	Namespace string
+	Zone      string   // Empty when no healthy zone is left to move to
+	Excluded  []string // Zones the namespace's volumes are moved out of
+	Nodes     int      // Ready, schedulable nodes in Zone
+	// CPUPercent and MemoryPercent are the shares of Zone's allocatable CPU and
+	// memory requested once the namespace's pods have moved there
+	CPUPercent    int
+	MemoryPercent int
+}
+
+// autoZone reports whether the zone of each namespace is picked by GeneratePlan
+func (c *Config) autoZone() bool {
+	return c.TargetZone == TargetZoneAuto
+}
+
+// chooseZones picks the zone of each namespace with PVCs to migrate, outside
+// the zones their volumes are in, and sets it as the target zone of those PVCs.
+// PVCs resumed from the static PV of an
+// earlier run keep the zone of its volume.
+func (m *Migrator) chooseZones(ctx context.Context, items []PVCPlanItem) []ZoneChoice {
+	moving := func(item PVCPlanItem) bool {
+		return item.Action == PlanActionMigrate && (item.ResumeAt == StepPending || item.TargetZone == "")
+	}
+
+	var namespaces []string
+	excluded := make(map[string]map[string]bool)
+	for _, item := range items {
+		if !moving(item) {
+			continue
+		}
+		if excluded[item.Namespace] == nil {
+			namespaces = append(namespaces, item.Namespace)
+			excluded[item.Namespace] = make(map[string]bool)
+		}
+		if item.CurrentZone != "" {
+			excluded[item.Namespace][item.CurrentZone] = true
+		}
+	}
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	capacities, err := m.k8sClient.ZoneCapacities(ctx)
+	if err != nil {
+		for i := range items {
+			if moving(items[i]) {
+				items[i].Action = PlanActionError
+				items[i].Reason = fmt.Sprintf("Failed to inspect zone capacity: %v", err)
+			}
+		}
+		return nil
+	}
+
+	choices := make([]ZoneChoice, 0, len(namespaces))
+	zones := make(map[string]string, len(namespaces))
+	for _, ns := range namespaces {
+		choice := pickZone(capacities, ns, excluded[ns])
+		choices = append(choices, choice)
+		zones[ns] = choice.Zone
+	}
+	for i := range items {
+		if !moving(items[i]) {
+			continue
+		}
+		zone := zones[items[i].Namespace]
+		if zone == "" {
+			items[i].Action = PlanActionError
+			items[i].Reason = fmt.Sprintf("No healthy zone outside %s to move to", strings.Join(sortedZones(excluded[items[i].Namespace]), ", "))
+			continue
+		}
+		items[i].TargetZone = zone
+	}
+	return choices
+}
+
+// pickZone returns the healthy zone outside excluded with the lowest share of its
+// CPU or memory requested once the namespace's pods in other zones move there.
+// Ties go to the zone with more nodes, then to the first by name. The pods are
+// then counted in the zone picked, so later namespaces see its new load.
+func pickZone(capacities []k8s.ZoneCapacity, namespace string, excluded map[string]bool) ZoneChoice {
+	choice := ZoneChoice{Namespace: namespace, Excluded: sortedZones(excluded)}
+
+	best, bestLoad := -1, 0
+	for i, c := range capacities {
+		if c.Nodes == 0 || excluded[c.Zone] {
+			continue
+		}
+		cpu, memory := loadAfterMove(capacities, i, namespace)
+		load := max(cpu, memory)
+		if best < 0 || load < bestLoad || (load == bestLoad && c.Nodes > capacities[best].Nodes) {
+			best, bestLoad = i, load
+			choice.Zone, choice.Nodes, choice.CPUPercent, choice.MemoryPercent = c.Zone, c.Nodes, cpu, memory
+		}
+	}
+	if best < 0 {
+		return choice
+	}
+
+	for i := range capacities {
+		if i == best {
+			continue
+		}
+		moved := capacities[i].ByNamespace[namespace]
+		capacities[i].Requested = k8s.Resources{
+			CPU:    capacities[i].Requested.CPU - moved.CPU,
+			Memory: capacities[i].Requested.Memory - moved.Memory,
+		}
+		delete(capacities[i].ByNamespace, namespace)
+		capacities[best].Requested = capacities[best].Requested.Add(moved)
+		capacities[best].ByNamespace[namespace] = capacities[best].ByNamespace[namespace].Add(moved)
+	}
+	return choice
+}
+
+// loadAfterMove returns the percentage of the CPU and memory of capacities[i]
+// requested once the namespace's pods in other zones move there
+func loadAfterMove(capacities []k8s.ZoneCapacity, i int, namespace string) (cpu, memory int) {
+	requested := capacities[i].Requested
+	for j, c := range capacities {
+		if j != i {
+			requested = requested.Add(c.ByNamespace[namespace])
+		}
+	}
+	return percent(requested.CPU, capacities[i].Allocatable.CPU), percent(requested.Memory, capacities[i].Allocatable.Memory)
+}
+
+// percent returns requested as a whole percentage of allocatable, or 100 when
+// nothing is allocatable
+func percent(requested, allocatable int64) int {
+	if allocatable <= 0 {
+		return 100
+	}
+	return int(requested * 100 / allocatable)
+}
+
+func sortedZones(zones map[string]bool) []string {
+	result := make([]string, 0, len(zones))
+	for zone := range zones {
+		result = append(result, zone)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// chosenZones returns the zones picked for the namespaces, sorted
+func chosenZones(choices []ZoneChoice) []string {
+	zones := make(map[string]bool)
+	for _, c := range choices {
+		if c.Zone != "" {
+			zones[c.Zone] = true
+		}
+	}
+	return sortedZones(zones)
+}
@@ -0,0 +1,160 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestPickZone(t *testing.T) {
+	t.Parallel()
+
+	zone := func(name string, nodes int, cpu, memory int64, byNamespace map[string]k8s.Resources) k8s.ZoneCapacity {
+		c := k8s.ZoneCapacity{Zone: name, Nodes: nodes, Allocatable: k8s.Resources{CPU: 1000, Memory: 1000}, ByNamespace: byNamespace}
+		c.Requested = k8s.Resources{CPU: cpu, Memory: memory}
+		return c
+	}
+
+	cases := []struct {
+		name       string
+		capacities []k8s.ZoneCapacity
+		excluded   map[string]bool
+		expected   ZoneChoice
+	}{
+		{
+			name: "least_loaded_after_the_move",
+			capacities: []k8s.ZoneCapacity{
+				zone("eu-west-1a", 3, 400, 100, map[string]k8s.Resources{"db": {CPU: 300, Memory: 100}}),
+				zone("eu-west-1b", 3, 500, 200, map[string]k8s.Resources{}),
+				zone("eu-west-1c", 3, 100, 600, map[string]k8s.Resources{}),
+			},
+			excluded: map[string]bool{"eu-west-1a": true},
+			expected: ZoneChoice{Namespace: "db", Zone: "eu-west-1c", Excluded: []string{"eu-west-1a"}, Nodes: 3, CPUPercent: 40, MemoryPercent: 70},
+		},
+		{
+			name: "ties_go_to_more_nodes",
+			capacities: []k8s.ZoneCapacity{
+				zone("eu-west-1a", 3, 0, 0, map[string]k8s.Resources{}),
+				zone("eu-west-1b", 2, 200, 200, map[string]k8s.Resources{}),
+				zone("eu-west-1c", 4, 200, 200, map[string]k8s.Resources{}),
+			},
+			excluded: map[string]bool{"eu-west-1a": true},
+			expected: ZoneChoice{Namespace: "db", Zone: "eu-west-1c", Excluded: []string{"eu-west-1a"}, Nodes: 4, CPUPercent: 20, MemoryPercent: 20},
+		},
+		{
+			name: "zones_without_healthy_nodes_are_skipped",
+			capacities: []k8s.ZoneCapacity{
+				zone("eu-west-1a", 3, 0, 0, map[string]k8s.Resources{}),
+				zone("eu-west-1b", 0, 0, 0, map[string]k8s.Resources{}),
+			},
+			excluded: map[string]bool{"eu-west-1a": true},
+			expected: ZoneChoice{Namespace: "db", Excluded: []string{"eu-west-1a"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, pickZone(tc.capacities, "db", tc.excluded))
+		})
+	}
+}
+
+func TestPickZone_MovesTheLoad(t *testing.T) {
+	t.Parallel()
+
+	capacities := []k8s.ZoneCapacity{
+		{Zone: "eu-west-1a", Nodes: 1, Allocatable: k8s.Resources{CPU: 1000, Memory: 1000}, Requested: k8s.Resources{CPU: 600, Memory: 600},
+			ByNamespace: map[string]k8s.Resources{"db": {CPU: 300, Memory: 300}, "web": {CPU: 300, Memory: 300}}},
+		{Zone: "eu-west-1b", Nodes: 1, Allocatable: k8s.Resources{CPU: 1000, Memory: 1000}, ByNamespace: map[string]k8s.Resources{}},
+		{Zone: "eu-west-1c", Nodes: 1, Allocatable: k8s.Resources{CPU: 1000, Memory: 1000}, Requested: k8s.Resources{CPU: 100, Memory: 100},
+			ByNamespace: map[string]k8s.Resources{}},
+	}
+	excluded := map[string]bool{"eu-west-1a": true}
+
+	first := pickZone(capacities, "db", excluded)
+	assert.Equal(t, "eu-west-1b", first.Zone)
+	assert.Equal(t, k8s.Resources{CPU: 300, Memory: 300}, capacities[0].Requested)
+	assert.Equal(t, k8s.Resources{CPU: 300, Memory: 300}, capacities[1].Requested)
+
+	second := pickZone(capacities, "web", excluded)
+	assert.Equal(t, "eu-west-1c", second.Zone, "the zone picked for db is now busier")
+	assert.Equal(t, 40, second.CPUPercent)
+}
+
+func TestGeneratePlan_AutoZone(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, zone, cpu string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("16Gi")},
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "web"},
+		Spec: corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+		}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	objects := []runtime.Object{node("node-a", "eu-west-1a", "4"), node("node-b", "eu-west-1b", "4"), node("node-c", "eu-west-1c", "4"), pod}
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+	objects = append(objects, boundClaim("logs", "logs-0", "vol-2")...)
+
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0", "db/data-1", "logs/logs-0"},
+		TargetZone: TargetZoneAuto,
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1c", "vol-1": "eu-west-1c", "vol-2": "eu-west-1b"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 3)
+
+	assert.Equal(t, []ZoneChoice{
+		{Namespace: "db", Zone: "eu-west-1b", Excluded: []string{"eu-west-1c"}, Nodes: 1},
+		{Namespace: "logs", Zone: "eu-west-1c", Excluded: []string{"eu-west-1b"}, Nodes: 1},
+	}, plan.AutoZones, "the busy zone is avoided")
+	assert.Equal(t, "eu-west-1b, eu-west-1c", plan.TargetZone)
+	for _, item := range plan.Items {
+		assert.Equal(t, PlanActionMigrate, item.Action, item.Name)
+	}
+	assert.Equal(t, "eu-west-1b", plan.Items[0].TargetZone)
+	assert.Equal(t, "eu-west-1b", plan.Items[1].TargetZone)
+	assert.Equal(t, "eu-west-1c", plan.Items[2].TargetZone)
+
+	zone, err := m.targetZone("db/data-1")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1b", zone, "the run follows the plan")
+	assert.Contains(t, FormatPlanPlain(plan), "Namespace db moves to eu-west-1b, its least-loaded healthy zone: 1 nodes, 0% of CPU and 0% of memory requested after the move.")
+}
+
+func TestGeneratePlan_AutoZone_NoHealthyZone(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: TargetZoneAuto,
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1c"}}, boundClaim("db", "data-0", "vol-0")...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, PlanActionError, plan.Items[0].Action)
+	assert.Equal(t, "No healthy zone outside eu-west-1c to move to", plan.Items[0].Reason)
+	assert.Contains(t, FormatPlanPlain(plan), "Namespace db has no healthy zone outside eu-west-1c to move to.")
+}
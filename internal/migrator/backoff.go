@@ -0,0 +1,35 @@
+package migrator
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Poll intervals of the steps that wait on EC2. Snapshots of large volumes take
+// hours, so their polls back off further than those of new volumes.
+const (
+	snapshotPollInitial = 5 * time.Second
+	snapshotPollMax     = time.Minute
+	volumePollInitial   = 2 * time.Second
+	volumePollMax       = 15 * time.Second
+)
+
+// pollBackoff spaces out the polls of a step: short intervals at first, doubling
+// up to a cap, with jitter so PVCs started together do not poll in lockstep
+type pollBackoff struct {
+	initial time.Duration
+	max     time.Duration
+	attempt int
+}
+
+// next returns how long to wait before the next poll: a random duration between
+// half and all of the current interval
+func (b *pollBackoff) next() time.Duration {
+	d := b.max
+	if b.attempt < 30 { // initial<<attempt would overflow long before
+		d = min(b.initial<<b.attempt, b.max)
+	}
+	b.attempt++
+	half := d / 2
+	return half + rand.N(d-half+1) //nolint:gosec // Jitter needs no cryptographic randomness
+}
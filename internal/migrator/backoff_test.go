@@ -0,0 +1,23 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollBackoff_Next(t *testing.T) {
+	t.Parallel()
+
+	b := pollBackoff{initial: time.Second, max: 10 * time.Second}
+	for i, interval := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		interval *= time.Second
+		d := b.next()
+		assert.GreaterOrEqual(t, d, interval/2, "poll %d", i)
+		assert.LessOrEqual(t, d, interval, "poll %d", i)
+	}
+
+	b.attempt = 100
+	assert.LessOrEqual(t, b.next(), 10*time.Second, "no overflow after many polls")
+}
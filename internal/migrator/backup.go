@@ -0,0 +1,51 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// BackupPath returns the file the manifests of a PVC are saved to before cleanup
+func (c *Config) BackupPath(pvcName string) string {
+	namespace, shortName := ParsePVCName(pvcName)
+	return filepath.Join(c.BackupDir, c.MigrationID, namespace, shortName+".yaml")
+}
+
+// backupClaim saves the PVC and PV cleanup is about to delete, exactly as they
+// are, to BackupDir and for the journal. Cleanup does not go ahead without it.
+func (m *Migrator) backupClaim(ctx context.Context, pvcName string, info *k8s.PVCInfo) error {
+	if m.config.BackupDir == "" && !m.config.JournalBackups {
+		return nil
+	}
+	namespace, shortName := ParsePVCName(pvcName)
+	manifests, err := m.k8sClient.ClaimManifests(ctx, namespace, shortName, info.PVName)
+	if err != nil {
+		return err
+	}
+
+	if m.config.BackupDir != "" {
+		path := m.config.BackupPath(pvcName)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := os.WriteFile(path, manifests, 0o600); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+		slog.Info("PVC and PV backed up", "pvc", pvcName, "pv", info.PVName, "path", path)
+	}
+
+	if m.config.JournalBackups {
+		m.mu.Lock()
+		if m.backups == nil {
+			m.backups = make(map[string]string)
+		}
+		m.backups[pvcName] = string(manifests)
+		m.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestBackupClaim(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	clientset := bindingClientset(boundClaim("shop", "data", "vol-1")...)
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		MigrationID:    "20261016T120000Z-0a1b",
+		BackupDir:      dir,
+		JournalBackups: true,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{}))
+	ctx := context.Background()
+
+	require.NoError(t, m.backupClaim(ctx, "shop/data", &k8s.PVCInfo{PVName: "pv-data"}))
+	path := filepath.Join(dir, "20261016T120000Z-0a1b", "shop", "data.yaml")
+	assert.Equal(t, path, m.config.BackupPath("shop/data"))
+	backup, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(backup), "kind: PersistentVolumeClaim")
+	assert.Contains(t, string(backup), "volumeHandle: vol-1")
+
+	require.NoError(t, m.SaveJournal(ctx, Journal{Namespace: "ops"}))
+	cm, err := clientset.CoreV1().ConfigMaps("ops").Get(ctx, k8s.JournalName("20261016T120000Z-0a1b"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, string(backup), cm.Data[JournalKeyBackupPrefix+"shop.data.yaml"])
+}
+
+func TestBackupClaim_Disabled(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{PVCList: []string{"shop/data"}}, &fakeEC2{})
+	// Nothing is read, so the missing PVC does not matter
+	require.NoError(t, m.backupClaim(context.Background(), "shop/data", &k8s.PVCInfo{PVName: "pv-data"}))
+}
@@ -0,0 +1,22 @@
+package migrator
+
+import (
+	"log/slog"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// warnBlockChecks warns that the checks the run was asked for that work on the
+// files of a volume, the filesystem freeze, the checksum and the mount check,
+// are skipped for a claim of a raw block device, which has none
+func (m *Migrator) warnBlockChecks(pvcName string) {
+	if !m.config.FreezeFilesystem && !m.config.VerifyChecksum && !m.config.VerifyMount {
+		return
+	}
+	slog.Info("raw block device, skipping the filesystem checks", "pvc", pvcName)
+	m.AddWarning(Warning{
+		PVC:     pvcName,
+		Message: i18n.T("warn.block_skipped"),
+		Action:  i18n.T("warn.block_action"),
+	})
+}
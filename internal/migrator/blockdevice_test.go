@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestRun_BlockVolumeMode(t *testing.T) {
+	t.Parallel()
+
+	objects := boundClaim("shop", "data", "vol-old")
+	block := corev1.PersistentVolumeBlock
+	objects[0].(*corev1.PersistentVolumeClaim).Spec.VolumeMode = &block
+	objects[1].(*corev1.PersistentVolume).Spec.VolumeMode = &block
+	clientset := bindingClientset(objects...)
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		StorageClass:   "gp3",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+		VerifyMount:    true,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-new"},
+	}))
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.True(t, plan.Items[0].Block)
+
+	m.Run(context.Background())
+
+	status := m.GetStatuses()["shop/data"]
+	require.NoError(t, status.Error)
+	assert.Equal(t, StepDone, status.Step)
+	assert.True(t, status.Block)
+	assert.Empty(t, status.MountCheck, "a raw device has no files to write")
+
+	pv, err := clientset.CoreV1().PersistentVolumes().Get(context.Background(), "data-static", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *pv.Spec.VolumeMode)
+	assert.Empty(t, pv.Spec.CSI.FSType)
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims("shop").Get(context.Background(), "data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *pvc.Spec.VolumeMode)
+
+	warnings := m.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, i18n.T("warn.block_skipped"), warnings[0].Message)
+}
@@ -0,0 +1,79 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// ErrChecksumMismatch is the error of a PVC whose new volume does not hold the
+// same files as its source volume
+var ErrChecksumMismatch = errors.New("the files on the new volume differ from those on the source volume")
+
+// DefaultChecksumTimeout is how long the checksum of a volume may take by default
+const DefaultChecksumTimeout = time.Hour
+
+// checksumTimeout returns how long the checksum of a volume may take
+func (c *Config) checksumTimeout() time.Duration {
+	if c.ChecksumTimeout > 0 {
+		return c.ChecksumTimeout
+	}
+	return DefaultChecksumTimeout
+}
+
+// sourceChecksum records the checksum of the files on a PVC's source volume, in
+// zone, right before its snapshot is taken. It returns false when the PVC
+// failed; its status is already set.
+func (m *Migrator) sourceChecksum(ctx context.Context, pvcName, zone string) bool {
+	namespace, shortName := ParsePVCName(pvcName)
+	sum, err := m.k8sClient.ClaimChecksum(ctx, namespace, shortName, zone, m.config.CheckImage, m.config.checksumTimeout())
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("checksum source volume: %w", err))
+		return false
+	}
+	m.mu.Lock()
+	m.statuses[pvcName].Checksum = sum
+	m.touch(m.statuses[pvcName])
+	m.mu.Unlock()
+	slog.Info("source volume checksummed", "pvc", pvcName, "checksum", sum)
+	return true
+}
+
+// verifyChecksum compares the checksum of the files on a PVC's new claim, in
+// zone, with that of its source volume. It returns false when the PVC failed;
+// its status is already set. A PVC whose snapshot was not taken after its
+// source checksum, such as a staged one, cannot be compared and is reported as
+// a warning.
+func (m *Migrator) verifyChecksum(ctx context.Context, pvcName, zone string) bool {
+	namespace, shortName := ParsePVCName(pvcName)
+	m.mu.RLock()
+	want := m.statuses[pvcName].Checksum
+	m.mu.RUnlock()
+	if want == "" {
+		m.AddWarning(Warning{
+			PVC:     pvcName,
+			Message: i18n.T("warn.checksum_skipped"),
+			Action:  i18n.T("warn.checksum_action"),
+		})
+		return true
+	}
+
+	got, err := m.k8sClient.ClaimChecksum(ctx, namespace, shortName, zone, m.config.CheckImage, m.config.checksumTimeout())
+	if err == nil && got != want {
+		err = fmt.Errorf("%w: %s on the new volume, %s on the source", ErrChecksumMismatch, got, want)
+	}
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("verify checksum: %w", err))
+		return false
+	}
+	m.mu.Lock()
+	m.statuses[pvcName].ChecksumVerified = true
+	m.touch(m.statuses[pvcName])
+	m.mu.Unlock()
+	slog.Info("new volume checksum matches", "pvc", pvcName, "checksum", got)
+	return true
+}
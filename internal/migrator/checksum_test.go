@@ -0,0 +1,116 @@
+package migrator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestMigratePVC_VerifyChecksum(t *testing.T) {
+	t.Parallel()
+
+	source := strings.Repeat("a", 64)
+	cases := []struct {
+		name      string
+		target    string // Checksum of the new volume
+		wantStep  Step
+		wantError error
+	}{
+		{name: "match", target: source, wantStep: StepDone},
+		{name: "mismatch", target: strings.Repeat("b", 64), wantStep: StepFailed, wantError: ErrChecksumMismatch},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+			var mu sync.Mutex
+			var zones []string
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				zone := pod.Spec.NodeSelector[corev1.LabelTopologyZone]
+				mu.Lock()
+				zones = append(zones, zone)
+				mu.Unlock()
+				sum := source
+				if zone == "eu-west-1a" {
+					sum = tc.target
+				}
+				pod.Status = corev1.PodStatus{
+					Phase: corev1.PodSucceeded,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: sum}},
+					}},
+				}
+				return false, nil, nil
+			})
+			m := New(&Config{
+				PVCList:        []string{"shop/data"},
+				TargetZone:     "eu-west-1a",
+				MaxConcurrency: 1,
+				StepRetry:      RetryPolicy{MaxAttempts: 1},
+				VerifyChecksum: true,
+			}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+				zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+				created: map[string]string{"snap-vol-old": "vol-new"},
+			}))
+			m.Run(context.Background())
+
+			status := m.GetStatuses()["shop/data"]
+			assert.Equal(t, tc.wantStep, status.Step)
+			assert.Equal(t, []string{"eu-west-1b", "eu-west-1a"}, zones, "the source volume is hashed in its zone, then the new one")
+			assert.Equal(t, source, status.Checksum)
+			if tc.wantError != nil {
+				require.ErrorIs(t, status.Error, tc.wantError)
+				assert.Equal(t, StepCreatePVC, status.FailedStep)
+				assert.False(t, status.ChecksumVerified)
+				return
+			}
+			require.NoError(t, status.Error)
+			assert.True(t, status.ChecksumVerified)
+			assert.Empty(t, m.Warnings())
+		})
+	}
+}
+
+func TestMigratePVC_VerifyChecksumResumed(t *testing.T) {
+	t.Parallel()
+
+	// The static PV an earlier run created holds a snapshot of unknown age
+	clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+	clientset.PrependReactor("create", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		t.Error("no checksum pod is run")
+		return false, nil, nil
+	})
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+		VerifyChecksum: true,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+		zones: map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+	}))
+	ctx := context.Background()
+	require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+	m.Run(ctx)
+
+	status := m.GetStatuses()["shop/data"]
+	require.NoError(t, status.Error)
+	assert.Equal(t, StepDone, status.Step)
+	assert.False(t, status.ChecksumVerified)
+	warnings := m.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "shop/data", warnings[0].PVC)
+}
@@ -0,0 +1,217 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// coMountedGroup is a set of PVCs that pods mount together, so their volumes
+// must stay in one zone for those pods to be scheduled
+type coMountedGroup struct {
+	pod    string   // One of the pods mounting them, for messages
+	claims []string // Full names, "namespace/pvcname", sorted
+}
+
+// coMountedGroups merges the claims of pods mounting more than one PVC into
+// groups, so a claim shared by two pods links all of their claims together
+func coMountedGroups(namespace string, podClaims map[string][]string) []coMountedGroup {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(c string) string {
+		if parent[c] != c {
+			parent[c] = find(parent[c])
+		}
+		return parent[c]
+	}
+
+	pods := make([]string, 0, len(podClaims))
+	for pod := range podClaims {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	podOf := make(map[string]string)
+	for _, pod := range pods {
+		claims := podClaims[pod]
+		if len(claims) < 2 {
+			continue
+		}
+		for _, c := range claims {
+			if _, ok := parent[c]; !ok {
+				parent[c] = c
+				podOf[c] = pod
+			}
+		}
+		for _, c := range claims[1:] {
+			parent[find(c)] = find(claims[0])
+		}
+	}
+
+	byRoot := make(map[string]*coMountedGroup)
+	var roots []string
+	for c := range parent {
+		root := find(c)
+		g, ok := byRoot[root]
+		if !ok {
+			g = &coMountedGroup{pod: podOf[root]}
+			byRoot[root] = g
+			roots = append(roots, root)
+		}
+		g.claims = append(g.claims, namespace+"/"+c)
+	}
+	sort.Strings(roots)
+
+	groups := make([]coMountedGroup, 0, len(roots))
+	for _, root := range roots {
+		g := byRoot[root]
+		sort.Strings(g.claims)
+		if len(g.claims) > 1 {
+			groups = append(groups, *g)
+		}
+	}
+	return groups
+}
+
+// resolveCoMounted keeps the PVCs of each group in one zone. PVCs to migrate go
+// to the zone of the group's PVCs that stay put, when that zone is a target zone,
+// and otherwise to a shared target zone. When a PVC of the group cannot follow,
+// because it is not part of the run or cannot be migrated, the PVCs that would
+// move are turned into plan errors rather than leave their pods unschedulable.
+// It returns why each of those PVCs is blocked, by name.
+func (m *Migrator) resolveCoMounted(ctx context.Context, items []PVCPlanItem, groups []coMountedGroup) map[string]string {
+	blocked := make(map[string]string)
+	index := make(map[string]int, len(items))
+	for i, item := range items {
+		index[item.Name] = i
+	}
+
+	for _, g := range groups {
+		var moving []int
+		var blockers []string
+		fixedZones := make(map[string][]string) // Zone -> PVCs staying in it
+		for _, name := range g.claims {
+			i, ok := index[name]
+			if !ok {
+				// Not part of the run; it only matters if it is an EBS volume
+				if zone, ok := m.claimZone(ctx, name); ok {
+					fixedZones[zone] = append(fixedZones[zone], name)
+				}
+				continue
+			}
+			switch items[i].Action {
+			case PlanActionMigrate:
+				moving = append(moving, i)
+			case PlanActionSkip:
+				fixedZones[items[i].CurrentZone] = append(fixedZones[items[i].CurrentZone], name)
+			case PlanActionError:
+				blockers = append(blockers, name)
+			}
+		}
+		if len(moving) == 0 {
+			continue
+		}
+
+		block := func(reason string) {
+			for _, i := range moving {
+				items[i].Action = PlanActionError
+				items[i].Reason = reason
+				items[i].TargetZone = m.config.fixedZone()
+				blocked[items[i].Name] = reason
+			}
+		}
+
+		if len(blockers) > 0 {
+			block(fmt.Sprintf("pod %s also mounts %s, which cannot be migrated", g.pod, strings.Join(blockers, ", ")))
+			continue
+		}
+
+		zones := make([]string, 0, len(fixedZones))
+		for zone := range fixedZones {
+			zones = append(zones, zone)
+		}
+		sort.Strings(zones)
+
+		var zone string
+		switch {
+		case len(zones) > 1:
+			block(fmt.Sprintf("pod %s mounts PVCs in zones %s", g.pod, strings.Join(zones, ", ")))
+			continue
+		case len(zones) == 1 && !m.config.inTargetZone(zones[0]):
+			block(fmt.Sprintf("pod %s also mounts %s in %s, which is not part of the run; add it so they move together",
+				g.pod, strings.Join(fixedZones[zones[0]], ", "), zones[0]))
+			continue
+		case len(zones) == 1:
+			zone = zones[0]
+		default:
+			zone = items[moving[0]].TargetZone
+		}
+
+		for _, i := range moving {
+			if items[i].TargetZone != zone {
+				slog.Info("moving co-mounted PVC with the rest of its pod's PVCs", "pvc", items[i].Name, "pod", g.pod, "zone", zone)
+				items[i].TargetZone = zone
+			}
+		}
+	}
+	return blocked
+}
+
+// coMountedSibling is an EBS-backed PVC outside the run that a pod mounts together
+// with PVCs to migrate
+type coMountedSibling struct {
+	name string // Full name, "namespace/pvcname"
+	pod  string
+}
+
+// coMountedSiblings returns the EBS-backed PVCs outside the run that share a pod
+// with PVCs to migrate, sorted by name
+func (m *Migrator) coMountedSiblings(ctx context.Context, items []PVCPlanItem, groups []coMountedGroup) []coMountedSibling {
+	actions := make(map[string]PlanAction, len(items))
+	for _, item := range items {
+		actions[item.Name] = item.Action
+	}
+
+	var siblings []coMountedSibling
+	for _, g := range groups {
+		moving := false
+		for _, name := range g.claims {
+			if action, ok := actions[name]; ok && action == PlanActionMigrate {
+				moving = true
+			}
+		}
+		if !moving {
+			continue
+		}
+		for _, name := range g.claims {
+			if _, ok := actions[name]; ok {
+				continue
+			}
+			if _, ok := m.claimZone(ctx, name); ok {
+				slog.Info("adding co-mounted PVC to the run", "pvc", name, "pod", g.pod)
+				siblings = append(siblings, coMountedSibling{name: name, pod: g.pod})
+			}
+		}
+	}
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i].name < siblings[j].name })
+	return siblings
+}
+
+// claimZone returns the zone of the EBS volume behind a PVC outside the run, or
+// false when it is not backed by one that can be found
+func (m *Migrator) claimZone(ctx context.Context, pvcName string) (string, bool) {
+	ns, shortName := ParsePVCName(pvcName)
+	info, err := m.pvcInfo(ctx, ns, shortName)
+	if err != nil {
+		slog.Debug("co-mounted PVC has no EBS volume", "pvc", pvcName, "error", err)
+		return "", false
+	}
+	volume, err := m.awsClient.GetVolumeInfo(ctx, info.VolumeID)
+	if err != nil {
+		slog.Debug("co-mounted PVC volume not found", "pvc", pvcName, "error", err)
+		return "", false
+	}
+	return volume.AvailabilityZone, true
+}
@@ -0,0 +1,174 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// coMountingPod returns a running pod that mounts every claim
+func coMountingPod(namespace, name string, claims ...string) *corev1.Pod {
+	pod := mountingPod(namespace, name, claims[0])
+	for _, claim := range claims[1:] {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         claim,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+	}
+	return pod
+}
+
+func TestCoMountedGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := coMountedGroups("db", map[string][]string{
+		"app-0":  {"data", "logs"},
+		"app-1":  {"logs", "cache"},
+		"web-0":  {"static"},
+		"job-0":  {"in", "out"},
+		"twice":  {"same", "same"},
+		"backup": {},
+	})
+
+	claims := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		claims = append(claims, g.claims)
+	}
+	assert.ElementsMatch(t, [][]string{{"db/cache", "db/data", "db/logs"}, {"db/in", "db/out"}}, claims)
+}
+
+func TestGeneratePlan_CoMounted(t *testing.T) {
+	t.Parallel()
+
+	zones := map[string]string{"vol-data": "eu-west-1b", "vol-logs": "eu-west-1b", "vol-cache": "eu-west-1c"}
+	claims := func(names ...string) []runtime.Object {
+		var objects []runtime.Object
+		for _, name := range names {
+			objects = append(objects, boundClaim("db", name, "vol-"+name)...)
+		}
+		return objects
+	}
+
+	cases := []struct {
+		name        string
+		config      *Config
+		objects     []runtime.Object
+		wantActions map[string]PlanAction
+		wantZones   map[string]string
+		wantReason  string
+	}{
+		{
+			name:        "mounted_with_a_pvc_outside_the_run",
+			config:      &Config{PVCList: []string{"db/data"}, TargetZone: "eu-west-1a"},
+			objects:     append(claims("data", "logs"), coMountingPod("db", "app-0", "data", "logs")),
+			wantActions: map[string]PlanAction{"db/data": PlanActionError},
+			wantReason:  "pod app-0 also mounts db/logs in eu-west-1b, which is not part of the run",
+		},
+		{
+			name:        "mounted_with_a_pvc_in_the_run",
+			config:      &Config{PVCList: []string{"db/data", "db/logs"}, TargetZone: "eu-west-1a"},
+			objects:     append(claims("data", "logs"), coMountingPod("db", "app-0", "data", "logs")),
+			wantActions: map[string]PlanAction{"db/data": PlanActionMigrate, "db/logs": PlanActionMigrate},
+			wantZones:   map[string]string{"db/data": "eu-west-1a", "db/logs": "eu-west-1a"},
+		},
+		{
+			name:        "mounted_with_a_pvc_that_cannot_be_migrated",
+			config:      &Config{PVCList: []string{"db/data", "db/gone"}, TargetZone: "eu-west-1a"},
+			objects:     append(claims("data"), coMountingPod("db", "app-0", "data", "gone")),
+			wantActions: map[string]PlanAction{"db/data": PlanActionError, "db/gone": PlanActionError},
+			wantReason:  "pod app-0 also mounts db/gone, which cannot be migrated",
+		},
+		{
+			name:        "spread_keeps_them_together",
+			config:      &Config{PVCList: []string{"db/data", "db/logs"}, TargetZones: []string{"eu-west-1a", "eu-west-1c"}},
+			objects:     append(claims("data", "logs"), coMountingPod("db", "app-0", "data", "logs")),
+			wantActions: map[string]PlanAction{"db/data": PlanActionMigrate, "db/logs": PlanActionMigrate},
+			wantZones:   map[string]string{"db/data": "eu-west-1a", "db/logs": "eu-west-1a"},
+		},
+		{
+			name:        "spread_follows_the_pvc_that_stays",
+			config:      &Config{PVCList: []string{"db/data", "db/cache"}, TargetZones: []string{"eu-west-1a", "eu-west-1c"}},
+			objects:     append(claims("data", "cache"), coMountingPod("db", "app-0", "data", "cache")),
+			wantActions: map[string]PlanAction{"db/data": PlanActionMigrate, "db/cache": PlanActionSkip},
+			wantZones:   map[string]string{"db/data": "eu-west-1c", "db/cache": "eu-west-1c"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := newFakeMigrator(tc.config, &fakeEC2{zones: zones}, tc.objects...)
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+
+			for _, item := range plan.Items {
+				assert.Equal(t, tc.wantActions[item.Name], item.Action, "%s: %s", item.Name, item.Reason)
+				if want, ok := tc.wantZones[item.Name]; ok {
+					assert.Equal(t, want, item.TargetZone, item.Name)
+				}
+			}
+			if tc.wantReason != "" {
+				assert.Contains(t, plan.Items[0].Reason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestGeneratePlan_IncludeCoMounted(t *testing.T) {
+	t.Parallel()
+
+	objects := append(boundClaim("db", "data", "vol-data"), boundClaim("db", "logs", "vol-logs")...)
+	objects = append(objects,
+		coMountingPod("db", "app-0", "data", "logs", "scratch"), // scratch is not bound to an EBS volume
+		coMountingPod("db", "report-0", "archive", "cold"),      // shares no PVC with the run
+	)
+	m := newFakeMigrator(&Config{
+		PVCList:                 []string{"db/data"},
+		TargetZone:              "eu-west-1a",
+		StorageClass:            "gp3",
+		NamespaceStorageClasses: map[string]string{"db": "io2"},
+		StorageClasses:          map[string]string{"db/data": "gp3"},
+		IncludeCoMounted:        true,
+	}, &fakeEC2{zones: map[string]string{"vol-data": "eu-west-1b", "vol-logs": "eu-west-1b"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 2)
+
+	assert.Equal(t, PlanActionMigrate, plan.Items[0].Action, plan.Items[0].Reason)
+	assert.Empty(t, plan.Items[0].CoMountedWith)
+
+	added := plan.Items[1]
+	assert.Equal(t, "db/logs", added.Name)
+	assert.Equal(t, PlanActionMigrate, added.Action, added.Reason)
+	assert.Equal(t, "app-0", added.CoMountedWith)
+	assert.Equal(t, "eu-west-1a", added.TargetZone)
+	assert.Equal(t, "io2", added.StorageClass, "uses its namespace's storage class")
+
+	assert.Equal(t, []string{"db/data", "db/logs"}, m.GetConfig().PVCList)
+	assert.Contains(t, m.GetStatuses(), "db/logs")
+}
+
+func TestRun_CoMountedBlocked(t *testing.T) {
+	t.Parallel()
+
+	objects := append(boundClaim("db", "data", "vol-data"), boundClaim("db", "logs", "vol-logs")...)
+	objects = append(objects, coMountingPod("db", "app-0", "data", "logs"))
+	ec2API := &fakeEC2{zones: map[string]string{"vol-data": "eu-west-1b", "vol-logs": "eu-west-1b"}}
+	m := newFakeMigrator(&Config{PVCList: []string{"db/data"}, TargetZone: "eu-west-1a", MaxConcurrency: 1}, ec2API, objects...)
+
+	_, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	m.Run(context.Background())
+
+	s := m.GetStatuses()["db/data"]
+	assert.Equal(t, StepFailed, s.Step)
+	require.Error(t, s.Error)
+	assert.Contains(t, s.Error.Error(), "not part of the run")
+	assert.Empty(t, ec2API.snapshotTags(), "nothing is snapshotted")
+}
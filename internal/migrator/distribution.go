@@ -0,0 +1,133 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// VolumeZone is an EBS-backed PVC and the zone its volume is in, with the
+// details of the volume
+type VolumeZone struct {
+	PVC      string // "namespace/name"
+	PVName   string
+	VolumeID string
+	Zone     string // Empty when the volume was not found in EC2, as are the details below
+	SizeGiB  int32
+	// VolumeType is the EBS type of the volume, such as gp3
+	VolumeType string
+	IOPS       int32 // Provisioned or baseline IOPS; 0 for types without
+	Throughput int32 // MiB/s of gp3 volumes
+	Encrypted  bool
+	KMSKeyID   string
+}
+
+// ZoneSummary is how many of the PVCs, and how much capacity, are in a zone
+type ZoneSummary struct {
+	Zone       string
+	PVCs       int
+	SizeGiB    int64
+	Namespaces []string // Sorted
+}
+
+// VolumeZones returns the EBS-backed PVCs of the namespaces, sorted by PVC, with
+// the zone and details of their volume, looked up in batches with DescribeVolumes
+func VolumeZones(ctx context.Context, k8sClient *k8s.Client, awsClient *aws.Client, namespaces []string) ([]VolumeZone, error) {
+	claimed, err := k8sClient.ListClaimedEBSVolumes(ctx, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(claimed))
+	for _, v := range claimed {
+		if v.VolumeID != "" {
+			ids = append(ids, v.VolumeID)
+		}
+	}
+	infos, err := awsClient.GetVolumesInfo(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up volumes: %w", err)
+	}
+
+	result := make([]VolumeZone, 0, len(claimed))
+	for _, v := range claimed {
+		volume := VolumeZone{PVC: v.PVC, PVName: v.PVName, VolumeID: v.VolumeID}
+		if info, ok := infos[v.VolumeID]; ok {
+			volume.Zone = info.AvailabilityZone
+			volume.SizeGiB = info.SizeGiB
+			volume.VolumeType = info.VolumeType
+			volume.IOPS = info.IOPS
+			volume.Throughput = info.Throughput
+			volume.Encrypted = info.Encrypted
+			volume.KMSKeyID = info.KMSKeyID
+		}
+		result = append(result, volume)
+	}
+	return result, nil
+}
+
+// SummarizeZones returns the PVC count and capacity of each zone, sorted by
+// zone. PVCs whose volume was not found are left out.
+func SummarizeZones(volumes []VolumeZone) []ZoneSummary {
+	zones := make(map[string]*ZoneSummary)
+	namespaces := make(map[string]map[string]bool)
+	for _, v := range volumes {
+		if v.Zone == "" {
+			continue
+		}
+		summary, ok := zones[v.Zone]
+		if !ok {
+			summary = &ZoneSummary{Zone: v.Zone}
+			zones[v.Zone] = summary
+			namespaces[v.Zone] = make(map[string]bool)
+		}
+		summary.PVCs++
+		summary.SizeGiB += int64(v.SizeGiB)
+		if ns, _ := ParsePVCName(v.PVC); !namespaces[v.Zone][ns] {
+			namespaces[v.Zone][ns] = true
+			summary.Namespaces = append(summary.Namespaces, ns)
+		}
+	}
+
+	result := make([]ZoneSummary, 0, len(zones))
+	for _, summary := range zones {
+		sort.Strings(summary.Namespaces)
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Zone < result[j].Zone })
+	return result
+}
+
+// VolumeWorkloads returns the Deployments and StatefulSets whose pods mount each
+// of the PVCs, as "Kind/name", by PVC. A namespace whose workloads cannot be
+// listed is logged and left out.
+func VolumeWorkloads(ctx context.Context, k8sClient *k8s.Client, volumes []VolumeZone) map[string][]string {
+	claims := make(map[string][]string) // By namespace
+	var namespaces []string
+	for _, v := range volumes {
+		ns, name := ParsePVCName(v.PVC)
+		if claims[ns] == nil {
+			namespaces = append(namespaces, ns)
+		}
+		claims[ns] = append(claims[ns], name)
+	}
+	sort.Strings(namespaces)
+
+	result := make(map[string][]string)
+	for _, ns := range namespaces {
+		workloads, err := k8sClient.ClaimWorkloads(ctx, ns, claims[ns])
+		if err != nil {
+			slog.Warn("failed to find the workloads of namespace", "namespace", ns, "error", err)
+			continue
+		}
+		for _, w := range workloads {
+			for _, claim := range w.Claims {
+				result[ns+"/"+claim] = append(result[ns+"/"+claim], w.Kind+"/"+w.Name)
+			}
+		}
+	}
+	return result
+}
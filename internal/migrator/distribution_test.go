@@ -0,0 +1,92 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestVolumeZones(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{
+		claimedPV("db", "data-1", "vol-1"),
+		claimedPV("db", "data-0", "vol-0"),
+		claimedPV("web", "cache", "vol-2"),
+		claimedPV("web", "deleted", "vol-gone"),
+		claimedPV("other", "logs", "vol-3"),
+	}
+	k8sClient := k8s.NewClientWithInterface(bindingClientset(objects...), nil)
+	awsClient := aws.NewEC2ClientWithInterface(&fakeEC2{
+		zones:   map[string]string{"vol-0": "eu-west-1a", "vol-1": "eu-west-1b", "vol-2": "eu-west-1a", "vol-3": "eu-west-1c"},
+		kmsKeys: map[string]string{"vol-1": "alias/prod"},
+	})
+
+	volumes, err := VolumeZones(context.Background(), k8sClient, awsClient, []string{"db", "web"})
+	require.NoError(t, err)
+	assert.Equal(t, []VolumeZone{
+		{PVC: "db/data-0", PVName: "pv-db-data-0", VolumeID: "vol-0", Zone: "eu-west-1a", SizeGiB: 10},
+		{PVC: "db/data-1", PVName: "pv-db-data-1", VolumeID: "vol-1", Zone: "eu-west-1b", SizeGiB: 10, Encrypted: true, KMSKeyID: "alias/prod"},
+		{PVC: "web/cache", PVName: "pv-web-cache", VolumeID: "vol-2", Zone: "eu-west-1a", SizeGiB: 10},
+		{PVC: "web/deleted", PVName: "pv-web-deleted", VolumeID: "vol-gone"},
+	}, volumes, "other namespaces are left out")
+}
+
+func TestSummarizeZones(t *testing.T) {
+	t.Parallel()
+
+	summaries := SummarizeZones([]VolumeZone{
+		{PVC: "web/cache", Zone: "eu-west-1b", SizeGiB: 5},
+		{PVC: "db/data-0", Zone: "eu-west-1a", SizeGiB: 10},
+		{PVC: "db/data-1", Zone: "eu-west-1a", SizeGiB: 20},
+		{PVC: "web/data", Zone: "eu-west-1a", SizeGiB: 1},
+		{PVC: "web/deleted"},
+	})
+	assert.Equal(t, []ZoneSummary{
+		{Zone: "eu-west-1a", PVCs: 3, SizeGiB: 31, Namespaces: []string{"db", "web"}},
+		{Zone: "eu-west-1b", PVCs: 1, SizeGiB: 5, Namespaces: []string{"web"}},
+	}, summaries)
+	assert.Empty(t, SummarizeZones(nil))
+}
+
+func TestVolumeWorkloads(t *testing.T) {
+	t.Parallel()
+
+	claimVolume := func(claim string) corev1.Volume {
+		return corev1.Volume{
+			Name:         claim,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		}
+	}
+	k8sClient := k8s.NewClientWithInterface(bindingClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "db"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{claimVolume("shared"), claimVolume("cache")},
+			}}},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db"},
+			Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{claimVolume("shared")},
+			}}},
+		},
+	), nil)
+
+	workloads := VolumeWorkloads(context.Background(), k8sClient, []VolumeZone{
+		{PVC: "db/shared"}, {PVC: "db/cache"}, {PVC: "db/orphan"},
+	})
+	assert.Equal(t, map[string][]string{
+		"db/shared": {"Deployment/api", "StatefulSet/postgres"},
+		"db/cache":  {"Deployment/api"},
+	}, workloads)
+}
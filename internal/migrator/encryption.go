@@ -0,0 +1,50 @@
+package migrator
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// newVolumeKey returns the KMS key the new volume of a PVC is encrypted with, or
+// "" when it is not encrypted. The run's key wins; otherwise the volume keeps
+// the key of its snapshot, which is that of the source volume, and the account
+// default applies to unencrypted ones. defaults is nil when unknown.
+func newVolumeKey(runKey, sourceKey string, defaults *aws.EncryptionDefaults) string {
+	switch {
+	case runKey != "":
+		return runKey
+	case sourceKey != "":
+		return sourceKey
+	case defaults != nil && defaults.ByDefault:
+		return defaults.KMSKeyID
+	}
+	return ""
+}
+
+// encryptionDefaults reads the account's EBS encryption defaults for the plan,
+// returning nil when they cannot be read
+func (m *Migrator) encryptionDefaults(ctx context.Context) *aws.EncryptionDefaults {
+	defaults, err := m.awsClient.EncryptionDefaults(ctx)
+	if err != nil {
+		slog.Warn("failed to read the account's EBS encryption defaults", "error", err)
+		return nil
+	}
+	if m.config.KMSKeyID != "" && defaults.ByDefault && defaults.KMSKeyID != m.config.KMSKeyID {
+		slog.Warn("the run's KMS key differs from the account's default EBS key",
+			"kmsKeyId", m.config.KMSKeyID, "accountDefault", defaults.KMSKeyID)
+	}
+	return defaults
+}
+
+// KMSConflict reports whether the run's KMS key differs from the key the account
+// encrypts new volumes with by default
+func (p *MigrationPlan) KMSConflict() bool {
+	return p.KMSKeyID != "" && p.Encryption != nil && p.Encryption.ByDefault && p.Encryption.KMSKeyID != p.KMSKeyID
+}
+
+// Reencrypted reports whether the new volume uses another key than the source volume
+func (i PVCPlanItem) Reencrypted() bool {
+	return i.SourceKMSKeyID != "" && i.KMSKeyID != i.SourceKMSKeyID
+}
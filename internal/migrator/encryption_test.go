@@ -0,0 +1,105 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// fakeEBSSettings reports the account's EBS encryption defaults
+type fakeEBSSettings struct {
+	byDefault  bool
+	defaultKey string
+}
+
+func (f fakeEBSSettings) GetEbsEncryptionByDefault(context.Context, *ec2.GetEbsEncryptionByDefaultInput, ...func(*ec2.Options)) (*ec2.GetEbsEncryptionByDefaultOutput, error) {
+	return &ec2.GetEbsEncryptionByDefaultOutput{EbsEncryptionByDefault: awssdk.Bool(f.byDefault)}, nil
+}
+
+func (f fakeEBSSettings) GetEbsDefaultKmsKeyId(context.Context, *ec2.GetEbsDefaultKmsKeyIdInput, ...func(*ec2.Options)) (*ec2.GetEbsDefaultKmsKeyIdOutput, error) {
+	return &ec2.GetEbsDefaultKmsKeyIdOutput{KmsKeyId: awssdk.String(f.defaultKey)}, nil
+}
+
+func TestNewVolumeKey(t *testing.T) {
+	t.Parallel()
+
+	encrypted := &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/aws/ebs"}
+	cases := []struct {
+		name      string
+		runKey    string
+		sourceKey string
+		defaults  *aws.EncryptionDefaults
+		want      string
+	}{
+		{name: "run_key_wins", runKey: "alias/prod", sourceKey: "alias/old", defaults: encrypted, want: "alias/prod"},
+		{name: "keeps_source_key", sourceKey: "alias/old", defaults: encrypted, want: "alias/old"},
+		{name: "account_default", defaults: encrypted, want: "alias/aws/ebs"},
+		{name: "not_by_default", defaults: &aws.EncryptionDefaults{KMSKeyID: "alias/aws/ebs"}},
+		{name: "unknown_defaults"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, newVolumeKey(tc.runKey, tc.sourceKey, tc.defaults))
+		})
+	}
+}
+
+func TestMigrationPlan_KMSConflict(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		plan MigrationPlan
+		want bool
+	}{
+		{name: "no_run_key", plan: MigrationPlan{Encryption: &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/aws/ebs"}}},
+		{name: "unknown_defaults", plan: MigrationPlan{KMSKeyID: "alias/prod"}},
+		{name: "not_by_default", plan: MigrationPlan{KMSKeyID: "alias/prod", Encryption: &aws.EncryptionDefaults{KMSKeyID: "alias/aws/ebs"}}},
+		{name: "same_key", plan: MigrationPlan{KMSKeyID: "alias/prod", Encryption: &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/prod"}}},
+		{name: "other_key", plan: MigrationPlan{KMSKeyID: "alias/prod", Encryption: &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/aws/ebs"}}, want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.plan.KMSConflict())
+		})
+	}
+}
+
+func TestGeneratePlan_Encryption(t *testing.T) {
+	t.Parallel()
+
+	objects := append(boundClaim("db", "plain", "vol-plain"), boundClaim("db", "secret", "vol-secret")...)
+	ec2API := &fakeEC2{
+		zones:   map[string]string{"vol-plain": "eu-west-1b", "vol-secret": "eu-west-1b"},
+		kmsKeys: map[string]string{"vol-secret": "arn:aws:kms:eu-west-1:123456789012:key/old"},
+	}
+	clientset := bindingClientset(objects...)
+	awsClient := aws.NewEC2ClientWithEBSSettings(ec2API, fakeEBSSettings{byDefault: true})
+	cfg := &Config{TargetZone: "eu-west-1a", PVCList: []string{"db/plain", "db/secret"}, KMSKeyID: "alias/prod"}
+
+	plan, err := New(cfg, k8s.NewClientWithInterface(clientset, nil), awsClient).GeneratePlan(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, plan.Encryption)
+	assert.Equal(t, aws.DefaultKMSKeyAlias, plan.Encryption.KMSKeyID)
+	assert.True(t, plan.KMSConflict())
+	items := make(map[string]PVCPlanItem)
+	for _, item := range plan.Items {
+		items[item.Name] = item
+	}
+	assert.Empty(t, items["db/plain"].SourceKMSKeyID)
+	assert.Equal(t, "alias/prod", items["db/plain"].KMSKeyID)
+	assert.Equal(t, "arn:aws:kms:eu-west-1:123456789012:key/old", items["db/secret"].SourceKMSKeyID)
+	assert.True(t, items["db/secret"].Reencrypted())
+}
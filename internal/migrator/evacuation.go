@@ -0,0 +1,113 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// Straggler is an EBS volume claimed in the run's namespaces that is still in
+// the source zone once an evacuation run is over
+type Straggler struct {
+	PVC      string // Claim the volume's PV is bound to
+	PVName   string
+	VolumeID string
+}
+
+// VerifySourceZone lists the EBS-backed PVs claimed in the run's namespaces once
+// the run is over and records those whose volume is still in the source zone,
+// such as the volumes of failed or excluded PVCs. Stragglers fail the run. It
+// does nothing without a source zone, on a dry run, or when the run output
+// manifests, whose claims only move once they are applied.
+func (m *Migrator) VerifySourceZone(ctx context.Context) error {
+	if m.config.SourceZone == "" || m.config.DryRun || m.config.OutputManifests != "" {
+		return nil
+	}
+
+	volumes, err := m.k8sClient.ListClaimedEBSVolumes(ctx, m.config.Namespaces)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		if v.VolumeID != "" {
+			ids = append(ids, v.VolumeID)
+		}
+	}
+	infos, err := m.awsClient.GetVolumesInfo(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to look up volumes: %w", err)
+	}
+
+	var stragglers []Straggler
+	for _, v := range volumes {
+		info, ok := infos[v.VolumeID]
+		if !ok {
+			slog.Warn("volume of PV not found, leaving it out of the source zone check", "pvc", v.PVC, "pv", v.PVName, "volumeId", v.VolumeID)
+			continue
+		}
+		if info.AvailabilityZone == m.config.SourceZone {
+			stragglers = append(stragglers, Straggler{PVC: v.PVC, PVName: v.PVName, VolumeID: v.VolumeID})
+		}
+	}
+	slog.Info("verified source zone", "zone", m.config.SourceZone, "volumes", len(volumes), "stragglers", len(stragglers))
+
+	m.mu.Lock()
+	m.stragglers = stragglers
+	m.mu.Unlock()
+	return nil
+}
+
+// Stragglers returns the volumes VerifySourceZone found in the source zone,
+// sorted by PVC
+func (m *Migrator) Stragglers() []Straggler {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Straggler(nil), m.stragglers...)
+}
+
+// FormatStragglers renders the volumes left in the source zone as a runbook
+// section, with the command that lists them
+func FormatStragglers(stragglers []Straggler, sourceZone string) string {
+	if len(stragglers) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Volumes left in the source zone\n\n")
+	b.WriteString(fmt.Sprintf("These EBS volumes claimed in the run's namespaces are still in %s. Migrate\n", sourceZone))
+	b.WriteString("them, or move their PVCs out of the namespaces, before decommissioning the zone.\n\n")
+	for _, s := range stragglers {
+		b.WriteString(fmt.Sprintf("- %s: PV %s, volume %s\n", s.PVC, s.PVName, s.VolumeID))
+	}
+	b.WriteString("\n```sh\n")
+	b.WriteString(DescribeStragglersCommand(stragglers) + "\n")
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// FormatStragglersPlain renders the volumes left in the source zone as sentences
+func FormatStragglersPlain(stragglers []Straggler, sourceZone string) string {
+	if len(stragglers) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("plain.stragglers", sourceZone, len(stragglers)) + "\n")
+	for _, s := range stragglers {
+		b.WriteString(i18n.T("summary.straggler", s.PVC, s.PVName, s.VolumeID) + "\n")
+	}
+	return b.String()
+}
+
+// DescribeStragglersCommand returns the AWS CLI command describing the volumes
+func DescribeStragglersCommand(stragglers []Straggler) string {
+	ids := make([]string, 0, len(stragglers))
+	for _, s := range stragglers {
+		ids = append(ids, s.VolumeID)
+	}
+	return "aws ec2 describe-volumes --volume-ids " + strings.Join(ids, " ")
+}
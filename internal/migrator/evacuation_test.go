@@ -0,0 +1,90 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// claimedPV returns an EBS CSI PV bound to the claim
+func claimedPV(namespace, claim, volumeID string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-" + namespace + "-" + claim},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: volumeID},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: namespace, Name: claim},
+		},
+	}
+}
+
+func TestVerifySourceZone(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{
+		claimedPV("db", "data-0", "vol-moved"),
+		claimedPV("db", "data-1", "vol-failed"),
+		claimedPV("db", "excluded", "vol-excluded"),
+		claimedPV("db", "gone", "vol-deleted"),
+		claimedPV("web", "data", "vol-other-namespace"),
+	}
+	ec2API := &fakeEC2{zones: map[string]string{
+		"vol-moved":           "eu-west-1a",
+		"vol-failed":          "eu-west-1b",
+		"vol-excluded":        "eu-west-1b",
+		"vol-other-namespace": "eu-west-1b",
+	}}
+	m := newFakeMigrator(&Config{
+		Namespaces: []string{"db"},
+		PVCList:    []string{"db/data-0", "db/data-1"},
+		TargetZone: "eu-west-1a",
+		SourceZone: "eu-west-1b",
+	}, ec2API, objects...)
+
+	require.NoError(t, m.VerifySourceZone(context.Background()))
+
+	expected := []Straggler{
+		{PVC: "db/data-1", PVName: "pv-db-data-1", VolumeID: "vol-failed"},
+		{PVC: "db/excluded", PVName: "pv-db-excluded", VolumeID: "vol-excluded"},
+	}
+	assert.Equal(t, expected, m.Stragglers(), "PVs of other namespaces and missing volumes are left out")
+	assert.Len(t, m.Result().Stragglers, 2)
+
+	report := FormatStragglers(m.Stragglers(), "eu-west-1b")
+	assert.Contains(t, report, "## Volumes left in the source zone")
+	assert.Contains(t, report, "- db/excluded: PV pv-db-excluded, volume vol-excluded\n")
+	assert.Contains(t, report, "aws ec2 describe-volumes --volume-ids vol-failed vol-excluded\n")
+	assert.Contains(t, FormatStragglersPlain(m.Stragglers(), "eu-west-1b"), "Source zone eu-west-1b is not empty: 2 EBS volume(s)")
+}
+
+func TestVerifySourceZone_Skipped(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		config Config
+	}{
+		{name: "no_source_zone", config: Config{Namespaces: []string{"db"}}},
+		{name: "dry_run", config: Config{Namespaces: []string{"db"}, SourceZone: "eu-west-1b", DryRun: true}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+			m := newFakeMigrator(&tc.config, ec2API, claimedPV("db", "data-0", "vol-0"))
+
+			require.NoError(t, m.VerifySourceZone(context.Background()))
+			assert.Empty(t, m.Stragglers())
+			assert.Zero(t, ec2API.describeVolumes)
+			assert.Empty(t, FormatStragglers(m.Stragglers(), tc.config.SourceZone))
+		})
+	}
+}
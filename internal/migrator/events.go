@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event describes a change in the migration status of a single PVC
+type Event struct {
+	Seq            uint64    `json:"seq"` // Increases with every status change, across all PVCs
+	Time           time.Time `json:"time"`
+	PVC            string    `json:"pvc"` // Full name in format "namespace/pvcname"
+	Namespace      string    `json:"namespace"`
+	Step           string    `json:"step"`
+	Progress       int       `json:"progress"`
+	SnapshotID     string    `json:"snapshotId,omitempty"`
+	VolumeID       string    `json:"volumeId,omitempty"` // New volume in the target zone
+	SourceVolumeID string    `json:"sourceVolumeId,omitempty"`
+	SizeGiB        int32     `json:"sizeGiB,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// EventListener is called for every status change. Listeners are invoked from the
+// migration goroutines and must be safe for concurrent use.
+type EventListener func(Event)
+
+// AddListener registers a listener for status change events
+func (m *Migrator) AddListener(l EventListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, l)
+}
+
+// newEvent builds an event from a status; the caller must hold m.mu
+func newEvent(s *PVCStatus) Event {
+	e := Event{
+		Seq:            s.Seq,
+		Time:           time.Now(),
+		PVC:            s.Name,
+		Namespace:      s.Namespace,
+		Step:           s.Step.String(),
+		Progress:       s.Progress,
+		SnapshotID:     s.SnapshotID,
+		VolumeID:       s.NewVolumeID,
+		SourceVolumeID: s.OldVolumeID,
+		SizeGiB:        s.SizeGiB,
+	}
+	if s.Error != nil {
+		e.Error = s.Error.Error()
+	}
+	return e
+}
+
+// jsonWarning is a warning as written to the JSON progress stream. Its type field
+// tells it apart from status events, which have none.
+type jsonWarning struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	PVC     string    `json:"pvc,omitempty"`
+	Message string    `json:"message"`
+	Action  string    `json:"action,omitempty"`
+}
+
+// NewJSONEventWriter returns a listener that writes each event as a line of JSON
+func NewJSONEventWriter(w io.Writer) EventListener {
+	events, _ := NewJSONWriters(w)
+	return events
+}
+
+// NewJSONWriters returns listeners that write events and warnings as lines of JSON
+// to the same stream. Warning lines have "type":"warning".
+func NewJSONWriters(w io.Writer) (EventListener, WarningListener) {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	events := func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(e)
+	}
+	warnings := func(wa Warning) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(jsonWarning{Type: "warning", Time: wa.Time, PVC: wa.PVC, Message: wa.Message, Action: wa.Action})
+	}
+	return events, warnings
+}
@@ -0,0 +1,93 @@
+package migrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Listeners(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		PVCList: []string{"ns/pvc-1"},
+	}
+	m := New(config, nil, nil)
+
+	var events []Event
+	m.AddListener(func(e Event) { events = append(events, e) })
+
+	m.updateStatus("ns/pvc-1", StepWaitSnapshot, 10, nil)
+	m.updateStatus("ns/pvc-1", StepWaitSnapshot, 10, nil) // unchanged, not emitted
+	m.updateStatus("ns/pvc-1", StepWaitSnapshot, 40, nil)
+	m.updateStatus("ns/pvc-1", StepFailed, 0, errors.New("boom"))
+	m.updateStatus("ns/unknown", StepDone, 100, nil)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "ns/pvc-1", events[0].PVC)
+	assert.Equal(t, "ns", events[0].Namespace)
+	assert.Equal(t, "Snapshot Progress", events[0].Step)
+	assert.Equal(t, 10, events[0].Progress)
+	assert.Equal(t, 40, events[1].Progress)
+	assert.Equal(t, "Failed", events[2].Step)
+	assert.Equal(t, "boom", events[2].Error)
+	assert.Equal(t, events[0].Seq+1, events[1].Seq)
+	assert.Equal(t, events[1].Seq+1, events[2].Seq)
+}
+
+func TestNewJSONEventWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	write := NewJSONEventWriter(&buf)
+
+	write(Event{PVC: "ns/a", Namespace: "ns", Step: "Creating Snapshot", SnapshotID: "snap-1"})
+	write(Event{PVC: "ns/b", Namespace: "ns", Step: "Completed", Progress: 100, VolumeID: "vol-2"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "ns/a", first["pvc"])
+	assert.Equal(t, "snap-1", first["snapshotId"])
+	assert.NotContains(t, first, "error")
+
+	var second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "vol-2", second["volumeId"])
+	assert.InDelta(t, 100, second["progress"], 0)
+}
+
+func TestNewJSONWriters_Warnings(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	events, warnings := NewJSONWriters(&buf)
+	m := New(&Config{PVCList: []string{"ns/a"}}, nil, nil)
+	m.AddListener(events)
+	m.AddWarningListener(warnings)
+
+	m.updateStatus("ns/a", StepDone, 100, nil)
+	m.AddWarning(Warning{Message: "workloads not restored", Action: "kubectl scale deployment app --replicas=2 -n ns"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.NotContains(t, event, "type", "status events carry no type")
+
+	var warning map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &warning))
+	assert.Equal(t, "warning", warning["type"])
+	assert.Equal(t, "workloads not restored", warning["message"])
+	assert.Equal(t, "kubectl scale deployment app --replicas=2 -n ns", warning["action"])
+	assert.NotContains(t, warning, "pvc")
+	assert.NotEmpty(t, warning["time"])
+}
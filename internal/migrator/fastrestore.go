@@ -0,0 +1,97 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// enableFastRestore enables fast snapshot restore of the snapshot in the target
+// zone and waits until it is enabled, so the new volume is fully initialized
+// when created instead of loading its blocks from S3 on first read. It returns
+// whether the run enabled it, and so must disable it once the volume is created;
+// fast restore already enabled by someone else is left alone. Failing to enable
+// it is recorded as a warning and the volume created without it.
+func (m *Migrator) enableFastRestore(ctx context.Context, pvcName, snapshotID, zone string) bool {
+	warn := func(err error) {
+		m.AddWarning(Warning{
+			PVC:     pvcName,
+			Message: i18n.T("warn.fsr_failed", err),
+			Action:  i18n.T("warn.warmup_action"),
+		})
+	}
+
+	var state string
+	err := m.retryStep(ctx, pvcName, StepCreateVolume, func() (err error) {
+		state, err = m.awsClient.FastSnapshotRestoreState(ctx, snapshotID, zone)
+		return err
+	})
+	if err != nil {
+		warn(err)
+		return false
+	}
+	enabled := false
+	if state == "" || state == aws.FastRestoreDisabling {
+		err = m.retryStep(ctx, pvcName, StepCreateVolume, func() error {
+			return m.awsClient.EnableFastSnapshotRestore(ctx, snapshotID, zone)
+		})
+		if err != nil {
+			warn(err)
+			return false
+		}
+		enabled = true
+		slog.Info("fast snapshot restore enabled", "pvc", pvcName, "snapshotId", snapshotID, "zone", zone)
+	}
+
+	// Enabling takes about an hour per TiB of the snapshot
+	poll := pollBackoff{initial: snapshotPollInitial, max: snapshotPollMax}
+	for {
+		err := m.retryStep(ctx, pvcName, StepCreateVolume, func() (err error) {
+			state, err = m.awsClient.FastSnapshotRestoreState(ctx, snapshotID, zone)
+			return err
+		})
+		switch {
+		case err != nil:
+			warn(err)
+			return enabled
+		case state == aws.FastRestoreEnabled:
+			return enabled
+		case state != aws.FastRestoreEnabling && state != aws.FastRestoreOptimizing:
+			warn(fmt.Errorf("fast snapshot restore of %s in %s is %q", snapshotID, zone, state))
+			return false
+		}
+
+		progress := 10
+		if state == aws.FastRestoreOptimizing {
+			progress = 50
+		}
+		m.updateStatus(pvcName, StepCreateVolume, progress, nil)
+		select {
+		case <-ctx.Done():
+			return enabled
+		case <-time.After(poll.next()):
+		}
+	}
+}
+
+// disableFastRestore disables the fast snapshot restore the run enabled, as it
+// is billed by the hour. Failing to is recorded as a warning with the command
+// to disable it by hand.
+func (m *Migrator) disableFastRestore(ctx context.Context, pvcName, snapshotID, zone string) {
+	err := m.retryStep(ctx, pvcName, StepCreateVolume, func() error {
+		return m.awsClient.DisableFastSnapshotRestore(ctx, snapshotID, zone)
+	})
+	if err != nil {
+		m.AddWarning(Warning{
+			PVC:     pvcName,
+			Message: i18n.T("warn.fsr_disable_failed", snapshotID, zone, err),
+			Action:  i18n.T("warn.fsr_disable_action", zone, snapshotID),
+		})
+		return
+	}
+	slog.Info("fast snapshot restore disabled", "pvc", pvcName, "snapshotId", snapshotID, "zone", zone)
+}
@@ -0,0 +1,104 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// fakeFastRestore holds the fast snapshot restore state of one snapshot and zone,
+// enabled at once when asked to, and counts the calls that change it
+type fakeFastRestore struct {
+	mu        sync.Mutex
+	state     ec2types.FastSnapshotRestoreStateCode
+	enableErr error
+	enabled   int
+	disabled  int
+}
+
+func (f *fakeFastRestore) EnableFastSnapshotRestores(context.Context, *ec2.EnableFastSnapshotRestoresInput, ...func(*ec2.Options)) (*ec2.EnableFastSnapshotRestoresOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.enableErr != nil {
+		return nil, f.enableErr
+	}
+	f.enabled++
+	f.state = ec2types.FastSnapshotRestoreStateCodeEnabled
+	return &ec2.EnableFastSnapshotRestoresOutput{}, nil
+}
+
+func (f *fakeFastRestore) DisableFastSnapshotRestores(context.Context, *ec2.DisableFastSnapshotRestoresInput, ...func(*ec2.Options)) (*ec2.DisableFastSnapshotRestoresOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disabled++
+	f.state = ec2types.FastSnapshotRestoreStateCodeDisabled
+	return &ec2.DisableFastSnapshotRestoresOutput{}, nil
+}
+
+func (f *fakeFastRestore) DescribeFastSnapshotRestores(context.Context, *ec2.DescribeFastSnapshotRestoresInput, ...func(*ec2.Options)) (*ec2.DescribeFastSnapshotRestoresOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &ec2.DescribeFastSnapshotRestoresOutput{}
+	if f.state != "" {
+		out.FastSnapshotRestores = []ec2types.DescribeFastSnapshotRestoreSuccessItem{{State: f.state}}
+	}
+	return out, nil
+}
+
+func TestRun_FastSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		state        ec2types.FastSnapshotRestoreStateCode
+		enableErr    error
+		wantEnabled  int
+		wantDisabled int
+		wantWarning  bool
+	}{
+		{name: "enabled for the run", wantEnabled: 1, wantDisabled: 1},
+		{name: "already enabled", state: ec2types.FastSnapshotRestoreStateCodeEnabled},
+		{name: "enable fails", enableErr: errors.New("ConcurrentSnapshotLimitExceeded"), wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsr := &fakeFastRestore{state: tt.state, enableErr: tt.enableErr}
+			m := New(&Config{
+				PVCList:             []string{"shop/data"},
+				TargetZone:          "eu-west-1a",
+				StorageClass:        "gp3",
+				MaxConcurrency:      1,
+				StepRetry:           RetryPolicy{MaxAttempts: 1},
+				FastSnapshotRestore: true,
+			}, k8s.NewClientWithInterface(bindingClientset(boundClaim("shop", "data", "vol-old")...), nil), aws.NewEC2ClientWithFastRestore(&fakeEC2{
+				zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+				created: map[string]string{"snap-vol-old": "vol-new"},
+			}, fsr))
+			m.Run(context.Background())
+
+			status := m.GetStatuses()["shop/data"]
+			require.NoError(t, status.Error)
+			assert.Equal(t, StepDone, status.Step)
+			assert.Equal(t, tt.wantEnabled, fsr.enabled)
+			assert.Equal(t, tt.wantDisabled, fsr.disabled)
+			if tt.wantWarning {
+				require.Len(t, m.Warnings(), 1)
+				assert.Contains(t, m.Warnings()[0].Message, "ConcurrentSnapshotLimitExceeded")
+			} else {
+				assert.Empty(t, m.Warnings())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// DefaultFreezeCommand and DefaultThawCommand freeze and thaw the filesystem
+// mounted at $1. fsfreeze needs a container allowed to, e.g. a privileged one.
+const (
+	DefaultFreezeCommand = `fsfreeze -f "$1"`
+	DefaultThawCommand   = `fsfreeze -u "$1"`
+)
+
+// thawAttempts is how often a thaw is tried before it is left to the operator,
+// as a filesystem left frozen blocks every write of the application
+const thawAttempts = 3
+
+// freezeCommands returns the commands run around a snapshot of a mounted volume
+func (c *Config) freezeCommands() (freeze, thaw string) {
+	freeze, thaw = c.FreezeCommand, c.ThawCommand
+	if freeze == "" {
+		freeze = DefaultFreezeCommand
+	}
+	if thaw == "" {
+		thaw = DefaultThawCommand
+	}
+	return freeze, thaw
+}
+
+// freezeClaim runs the freeze command in every running container that mounts the
+// PVC read-write and returns the function that thaws them again, to be called
+// once the snapshot has been requested; EBS snapshots are point in time from
+// then. A PVC nothing mounts is not frozen. When a freeze fails, the containers
+// already frozen are thawed.
+func (m *Migrator) freezeClaim(ctx context.Context, pvcName string) (func(), error) {
+	namespace, shortName := ParsePVCName(pvcName)
+	mounts, err := m.k8sClient.ClaimMounts(ctx, namespace, shortName)
+	if err != nil {
+		return nil, err
+	}
+	freeze, _ := m.config.freezeCommands()
+
+	var frozen []k8s.ClaimMount
+	thaw := func() {
+		for _, mount := range frozen {
+			m.thawMount(ctx, pvcName, mount)
+		}
+	}
+	for _, mount := range mounts {
+		if err := m.k8sClient.ExecHook(ctx, namespace, mount, freeze); err != nil {
+			thaw()
+			return nil, err
+		}
+		frozen = append(frozen, mount)
+	}
+	if len(frozen) > 0 {
+		slog.Info("filesystem frozen for the snapshot", "pvc", pvcName, "pods", len(frozen))
+	}
+	return thaw, nil
+}
+
+// thawMount runs the thaw command in a frozen container, even once the run is
+// cancelled, and reports a warning with the command to run by hand if it fails
+func (m *Migrator) thawMount(ctx context.Context, pvcName string, mount k8s.ClaimMount) {
+	namespace, _ := ParsePVCName(pvcName)
+	_, thaw := m.config.freezeCommands()
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	for attempt := 1; attempt <= thawAttempts; attempt++ {
+		if err = m.k8sClient.ExecHook(ctx, namespace, mount, thaw); err == nil {
+			slog.Info("filesystem thawed", "pvc", pvcName, "pod", mount.Pod)
+			return
+		}
+		if attempt < thawAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	m.AddWarning(Warning{
+		PVC:     pvcName,
+		Message: i18n.T("warn.thaw_failed", mount.Pod, err),
+		Action: i18n.T("warn.thaw_action") + fmt.Sprintf("\nkubectl exec -n %s %s -c %s%s -- sh -c '%s' sh %s",
+			namespace, mount.Pod, mount.Container, contextFlag(m.config.KubeContext), strings.ReplaceAll(thaw, "'", `'\''`), mount.MountPath),
+	})
+}
@@ -0,0 +1,132 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// hookExecutor records the hooks run in pods, with the number of snapshots the
+// EC2 API had been asked for at the time, and fails those containing failOn
+type hookExecutor struct {
+	mu     sync.Mutex
+	ec2API *fakeEC2
+	failOn string
+	calls  []string
+}
+
+func (h *hookExecutor) Exec(_ context.Context, namespace, pod, container string, command []string) (string, error) {
+	snapshots := len(h.ec2API.snapshotTags())
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	call := strings.Join(append([]string{namespace, pod, container}, command...), " ")
+	h.calls = append(h.calls, call)
+	if h.failOn != "" && strings.Contains(call, h.failOn) {
+		return "", errors.New("fsfreeze: cannot open: permission denied")
+	}
+	if snapshots > 0 {
+		h.calls[len(h.calls)-1] += " (after snapshot)"
+	}
+	return "", nil
+}
+
+func TestRunSnapshots_FreezeFilesystem(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		failOn    string
+		wantStep  Step
+		wantCalls []string
+	}{
+		{
+			name:     "frozen_while_requested",
+			wantStep: StepDone,
+			wantCalls: []string{
+				`db postgres-0 postgres sh -c fsfreeze -f "$1" sh /var/lib/postgresql`,
+				`db postgres-0 postgres sh -c fsfreeze -u "$1" sh /var/lib/postgresql (after snapshot)`,
+			},
+		},
+		{
+			name:      "freeze_fails",
+			failOn:    "fsfreeze -f",
+			wantStep:  StepFailed,
+			wantCalls: []string{`db postgres-0 postgres sh -c fsfreeze -f "$1" sh /var/lib/postgresql`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pod := mountingPod("db", "postgres-0", "data-0")
+			pod.Spec.Containers = []corev1.Container{{
+				Name:         "postgres",
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql"}},
+			}}
+			objects := append([]runtime.Object{pod}, boundClaim("db", "data-0", "vol-0")...)
+			ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+			m := newFakeMigrator(&Config{
+				PVCList:          []string{"db/data-0"},
+				TargetZone:       "eu-west-1a",
+				MaxConcurrency:   1,
+				StepRetry:        RetryPolicy{MaxAttempts: 1},
+				FreezeFilesystem: true,
+			}, ec2API, objects...)
+			executor := &hookExecutor{ec2API: ec2API, failOn: tc.failOn}
+			m.k8sClient.SetExecutor(executor)
+
+			m.RunSnapshots(context.Background())
+
+			status := m.GetStatuses()["db/data-0"]
+			assert.Equal(t, tc.wantStep, status.Step)
+			assert.Equal(t, tc.wantCalls, executor.calls)
+			if tc.wantStep == StepFailed {
+				require.ErrorContains(t, status.Error, "freeze filesystem")
+				assert.Empty(t, ec2API.snapshotTags(), "no snapshot is taken of a volume that could not be frozen")
+				return
+			}
+			require.NoError(t, status.Error)
+			assert.Equal(t, "snap-vol-0", status.SnapshotID)
+			assert.Empty(t, m.Warnings())
+		})
+	}
+}
+
+func TestRunSnapshots_FreezeUnmounted(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+	m := newFakeMigrator(&Config{
+		PVCList:          []string{"db/data-0"},
+		TargetZone:       "eu-west-1a",
+		MaxConcurrency:   1,
+		FreezeFilesystem: true,
+	}, ec2API, boundClaim("db", "data-0", "vol-0")...)
+	executor := &hookExecutor{ec2API: ec2API}
+	m.k8sClient.SetExecutor(executor)
+
+	m.RunSnapshots(context.Background())
+
+	assert.Equal(t, StepDone, m.GetStatuses()["db/data-0"].Step)
+	assert.Empty(t, executor.calls, "a volume nothing mounts is not frozen")
+}
+
+func TestConfig_FreezeCommands(t *testing.T) {
+	t.Parallel()
+
+	freeze, thaw := (&Config{}).freezeCommands()
+	assert.Equal(t, DefaultFreezeCommand, freeze)
+	assert.Equal(t, DefaultThawCommand, thaw)
+
+	freeze, thaw = (&Config{FreezeCommand: "psql -c 'CHECKPOINT'", ThawCommand: "true"}).freezeCommands()
+	assert.Equal(t, "psql -c 'CHECKPOINT'", freeze)
+	assert.Equal(t, "true", thaw)
+}
@@ -0,0 +1,51 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// HelmOwnershipCommand returns the kubectl commands that give a PVC the Helm
+// ownership metadata of the release, so its next upgrade adopts the PVC that
+// replaced the one it installed
+func HelmOwnershipCommand(claim string, release k8s.HelmReleaseInfo, kubeContext string) string {
+	namespace, name := ParsePVCName(claim)
+	kctx := contextFlag(kubeContext)
+	return fmt.Sprintf("kubectl annotate pvc %s -n %s%s meta.helm.sh/release-name=%s meta.helm.sh/release-namespace=%s && "+
+		"kubectl label pvc %s -n %s%s app.kubernetes.io/managed-by=Helm",
+		name, namespace, kctx, release.Name, release.Namespace, name, namespace, kctx)
+}
+
+// HelmValues returns the changes the values of a Helm release need for its next
+// upgrade to match the PVCs the run migrated, and whether any of its PVCs was
+// migrated. The zone is only set when they all ended up in the same one.
+func (m *Migrator) HelmValues(release k8s.HelmReleaseInfo) (map[string]interface{}, bool) {
+	m.mu.RLock()
+	var storageClass, zone string
+	migrated, mixed := false, false
+	for _, claim := range release.Claims {
+		s, ok := m.statuses[claim]
+		if !ok || s.Step != StepDone {
+			continue
+		}
+		claimZone := s.TargetZone
+		if claimZone == "" {
+			claimZone = m.config.TargetZone
+		}
+		if !migrated {
+			storageClass, zone = m.config.StorageClassFor(claim), claimZone
+		}
+		mixed = mixed || claimZone != zone
+		migrated = true
+	}
+	m.mu.RUnlock()
+
+	if !migrated {
+		return nil, false
+	}
+	if mixed {
+		zone = ""
+	}
+	return k8s.HelmValuesChanges(release.Values, storageClass, zone), true
+}
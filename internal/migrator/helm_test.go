@@ -0,0 +1,74 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestHelmValues(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{
+		PVCList:        []string{"shop/data-db-0", "shop/data-db-1", "shop/uploads"},
+		TargetZone:     "eu-west-1a",
+		StorageClass:   "gp3",
+		StorageClasses: map[string]string{"shop/uploads": "io2"},
+	}, nil, nil)
+	m.statuses["shop/data-db-0"].Step = StepDone
+	m.statuses["shop/data-db-1"].Step = StepDone
+	m.statuses["shop/data-db-1"].TargetZone = "eu-west-1b"
+	m.statuses["shop/uploads"].Step = StepFailed
+	values := map[string]interface{}{
+		"persistence":  map[string]interface{}{"storageClass": "gp2"},
+		"nodeSelector": map[string]interface{}{},
+	}
+
+	cases := []struct {
+		name         string
+		claims       []string
+		want         map[string]interface{}
+		wantMigrated bool
+	}{
+		{
+			name:   "one_zone",
+			claims: []string{"shop/data-db-0"},
+			want: map[string]interface{}{
+				"persistence":  map[string]interface{}{"storageClass": "gp3"},
+				"nodeSelector": map[string]interface{}{"topology.kubernetes.io/zone": "eu-west-1a"},
+			},
+			wantMigrated: true,
+		},
+		{
+			name:         "spread_across_zones",
+			claims:       []string{"shop/data-db-0", "shop/data-db-1"},
+			want:         map[string]interface{}{"persistence": map[string]interface{}{"storageClass": "gp3"}},
+			wantMigrated: true,
+		},
+		{
+			name:   "not_migrated",
+			claims: []string{"shop/uploads"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			changes, migrated := m.HelmValues(k8s.HelmReleaseInfo{Name: "db", Namespace: "shop", Claims: tc.claims, Values: values})
+			assert.Equal(t, tc.wantMigrated, migrated)
+			assert.Equal(t, tc.want, changes)
+		})
+	}
+}
+
+func TestHelmOwnershipCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"kubectl annotate pvc uploads -n shop --context=prod meta.helm.sh/release-name=web meta.helm.sh/release-namespace=shop && "+
+			"kubectl label pvc uploads -n shop --context=prod app.kubernetes.io/managed-by=Helm",
+		HelmOwnershipCommand("shop/uploads", k8s.HelmReleaseInfo{Name: "web", Namespace: "shop"}, "prod"))
+}
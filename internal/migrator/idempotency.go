@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// newMigrationID returns an ID for a run that was not given one, from its start
+// time and a random suffix so runs started in the same second differ
+func newMigrationID(now time.Time) string {
+	return fmt.Sprintf("%s-%04x", now.UTC().Format("20060102T150405Z"), rand.N(0x10000)) //nolint:gosec // Only tells runs apart
+}
+
+// MigrationID returns the ID the client tokens of the run are derived from
+func (m *Migrator) MigrationID() string {
+	return m.config.MigrationID
+}
+
+// snapshotToken returns the client token of the snapshot of a PVC's volume
+func (m *Migrator) snapshotToken(pvcName, volumeID, targetZone string) string {
+	return aws.ClientToken(m.config.MigrationID, pvcName, volumeID, targetZone)
+}
+
+// volumeToken returns the client token of the volume created for a PVC from a
+// snapshot. Once a volume of the PVC ended in the error state the token includes
+// the count of such volumes, as the old token would keep returning the failed one.
+func (m *Migrator) volumeToken(pvcName, snapshotID, targetZone string) string {
+	m.mu.RLock()
+	failed := m.volumeAttempts[pvcName]
+	m.mu.RUnlock()
+	if failed == 0 {
+		return aws.ClientToken(m.config.MigrationID, pvcName, snapshotID, targetZone)
+	}
+	return aws.ClientToken(m.config.MigrationID, pvcName, snapshotID, targetZone, strconv.Itoa(failed))
+}
+
+// earlierSnapshot returns the ID of the snapshot a run with the same migration
+// ID took of the volume before it crashed, or an attempt that timed out, or "".
+// It is only adopted while the volume is detached: once workloads mount it again
+// the snapshot may miss writes.
+func (m *Migrator) earlierSnapshot(ctx context.Context, pvcName string, volume *aws.VolumeInfo, token string) string {
+	if volume.State == "in-use" {
+		return ""
+	}
+	snap, err := m.awsClient.FindSnapshotByToken(ctx, volume.VolumeID, token)
+	if err != nil {
+		slog.Warn("failed to look up snapshots of earlier runs, taking a new one", "pvc", pvcName, "error", err)
+		return ""
+	}
+	if snap == nil {
+		return ""
+	}
+	slog.Info("adopting snapshot of an earlier run", "pvc", pvcName, "snapshotId", snap.SnapshotID, "started", snap.StartTime)
+	return snap.SnapshotID
+}
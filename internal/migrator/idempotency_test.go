@@ -0,0 +1,69 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+func TestNewMigrationID(t *testing.T) {
+	t.Parallel()
+
+	id := newMigrationID(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Regexp(t, `^20260102T030405Z-[0-9a-f]{4}$`, id)
+
+	m := New(&Config{}, nil, nil)
+	assert.NotEmpty(t, m.MigrationID(), "generated when not given")
+	m = New(&Config{MigrationID: "nightly-42"}, nil, nil)
+	assert.Equal(t, "nightly-42", m.MigrationID())
+}
+
+func TestRun_AdoptsEarlierSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		migrationID string
+		wantAdopted bool
+	}{
+		{name: "same migration ID", migrationID: "run-1", wantAdopted: true},
+		{name: "other migration ID", migrationID: "run-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ec2API := &fakeEC2{
+				zones:   map[string]string{"vol-0": "eu-west-1b"},
+				earlier: map[string]string{aws.ClientToken("run-1", "db/data-0", "vol-0", "eu-west-1a"): "snap-vol-0"},
+			}
+			m := newFakeMigrator(&Config{
+				PVCList:        []string{"db/data-0"},
+				TargetZone:     "eu-west-1a",
+				MaxConcurrency: 1,
+				MigrationID:    tt.migrationID,
+			}, ec2API, boundClaim("db", "data-0", "vol-0")...)
+
+			// The fake cannot create volumes, so the PVC fails after its snapshot
+			m.Run(context.Background())
+
+			s := m.GetStatuses()["db/data-0"]
+			require.Equal(t, StepCreateVolume, s.FailedStep)
+			assert.Equal(t, "snap-vol-0", s.SnapshotID)
+			assert.Equal(t, tt.wantAdopted, s.AdoptedSnapshot)
+			if tt.wantAdopted {
+				assert.Empty(t, ec2API.snapshotTags(), "no new snapshot is taken")
+				return
+			}
+			tags := ec2API.snapshotTags()
+			require.Len(t, tags, 1)
+			assert.Equal(t, aws.ClientToken("run-2", "db/data-0", "vol-0", "eu-west-1a"), tags["vol-0"][aws.TagClientToken])
+		})
+	}
+}
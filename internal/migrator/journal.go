@@ -0,0 +1,153 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+// Keys of the journal ConfigMap
+const (
+	JournalKeyMigrationID = "migrationId"
+	JournalKeyKubeContext = "kubeContext"
+	JournalKeyOperator    = "operator"
+	JournalKeyStartTime   = "startTime"
+	JournalKeyEndTime     = "endTime"
+	JournalKeyResult      = "result.json" // The run's Result document, with the volumes and snapshots of each PVC
+	// JournalKeyBackupPrefix starts the keys holding the PVC and PV manifests
+	// saved before cleanup, as backup.<namespace>.<pvc>.yaml, with JournalBackups
+	JournalKeyBackupPrefix = "backup."
+)
+
+// Journal describes a run for the record SaveJournal keeps in the cluster
+type Journal struct {
+	Namespace string    // Namespace of the ConfigMap
+	Operator  string    // User the cluster authenticated the run as
+	StartTime time.Time // When the run started
+	EndTime   time.Time // When the run finished; zero while it is in progress
+}
+
+// SaveJournal writes the run's current result to its journal ConfigMap, so the
+// cluster itself keeps the volume and snapshot IDs needed to audit or roll back
+// the migration
+func (m *Migrator) SaveJournal(ctx context.Context, j Journal) error {
+	result, err := json.MarshalIndent(m.Result(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	data := map[string]string{
+		JournalKeyMigrationID: m.config.MigrationID,
+		JournalKeyKubeContext: m.k8sClient.ContextName(),
+		JournalKeyOperator:    j.Operator,
+		JournalKeyStartTime:   j.StartTime.UTC().Format(time.RFC3339),
+		JournalKeyResult:      string(result),
+	}
+	if !j.EndTime.IsZero() {
+		data[JournalKeyEndTime] = j.EndTime.UTC().Format(time.RFC3339)
+	}
+	if m.config.JournalBackups {
+		m.mu.RLock()
+		for pvcName, manifests := range m.backups {
+			namespace, shortName := ParsePVCName(pvcName)
+			data[JournalKeyBackupPrefix+namespace+"."+shortName+".yaml"] = manifests
+		}
+		m.mu.RUnlock()
+	}
+	return m.k8sClient.SaveJournal(ctx, j.Namespace, m.config.MigrationID, data)
+}
+
+// Outcomes of a run recorded in its journal
+const (
+	RunSucceeded  = "succeeded"
+	RunFailed     = "failed"
+	RunUnfinished = "unfinished" // Still running, or stopped before recording its end
+)
+
+// JournalRecord is a past run as its journal ConfigMap recorded it
+type JournalRecord struct {
+	MigrationID string
+	KubeContext string
+	Operator    string
+	StartTime   time.Time
+	EndTime     time.Time // Zero when the run never recorded its end
+	Result      apiv1.Result
+}
+
+// Outcome returns RunUnfinished until the run recorded its end, then RunFailed
+// when a PVC failed or a volume was left in the source zone, and RunSucceeded
+// otherwise
+func (r JournalRecord) Outcome() string {
+	switch {
+	case r.EndTime.IsZero():
+		return RunUnfinished
+	case r.Result.Failed > 0 || len(r.Result.Stragglers) > 0:
+		return RunFailed
+	default:
+		return RunSucceeded
+	}
+}
+
+// ParseJournal reads the record of a run from the data of its journal ConfigMap
+func ParseJournal(data map[string]string) (JournalRecord, error) {
+	r := JournalRecord{
+		MigrationID: data[JournalKeyMigrationID],
+		KubeContext: data[JournalKeyKubeContext],
+		Operator:    data[JournalKeyOperator],
+	}
+	if r.MigrationID == "" {
+		return r, fmt.Errorf("journal has no %s", JournalKeyMigrationID)
+	}
+	var err error
+	if r.StartTime, err = time.Parse(time.RFC3339, data[JournalKeyStartTime]); err != nil {
+		return r, fmt.Errorf("invalid %s in journal %s: %w", JournalKeyStartTime, r.MigrationID, err)
+	}
+	if end := data[JournalKeyEndTime]; end != "" {
+		if r.EndTime, err = time.Parse(time.RFC3339, end); err != nil {
+			return r, fmt.Errorf("invalid %s in journal %s: %w", JournalKeyEndTime, r.MigrationID, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(data[JournalKeyResult]), &r.Result); err != nil {
+		return r, fmt.Errorf("invalid %s in journal %s: %w", JournalKeyResult, r.MigrationID, err)
+	}
+	return r, nil
+}
+
+// ReadJournals returns the runs recorded in the namespace, latest first.
+// Journals that cannot be read are logged and left out.
+func ReadJournals(ctx context.Context, k8sClient *k8s.Client, namespace string) ([]JournalRecord, error) {
+	journals, err := k8sClient.ListJournals(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]JournalRecord, 0, len(journals))
+	for _, data := range journals {
+		r, err := ParseJournal(data)
+		if err != nil {
+			slog.Warn("skipping unreadable journal", "namespace", namespace, "error", err)
+			continue
+		}
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].StartTime.Equal(records[j].StartTime) {
+			return records[i].StartTime.After(records[j].StartTime)
+		}
+		return records[i].MigrationID < records[j].MigrationID
+	})
+	return records, nil
+}
+
+// ReadJournal returns the run recorded in the namespace under a migration ID
+func ReadJournal(ctx context.Context, k8sClient *k8s.Client, namespace, migrationID string) (JournalRecord, error) {
+	data, err := k8sClient.GetJournal(ctx, namespace, migrationID)
+	if err != nil {
+		return JournalRecord{}, err
+	}
+	return ParseJournal(data)
+}
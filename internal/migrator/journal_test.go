@@ -0,0 +1,101 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+func TestSaveJournal(t *testing.T) {
+	t.Parallel()
+
+	clientset := bindingClientset()
+	m := New(&Config{
+		PVCList:        []string{"db/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		MigrationID:    "20261016T120000Z-0a1b",
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{zones: map[string]string{"vol-new": "eu-west-1a"}}))
+	ctx := context.Background()
+	require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+	m.Run(ctx)
+
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	journal := Journal{Namespace: "ops", Operator: "alice@example.com", StartTime: start}
+	require.NoError(t, m.SaveJournal(ctx, journal))
+
+	cm, err := clientset.CoreV1().ConfigMaps("ops").Get(ctx, k8s.JournalName("20261016T120000Z-0a1b"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "20261016T120000Z-0a1b", cm.Data[JournalKeyMigrationID])
+	assert.Equal(t, "alice@example.com", cm.Data[JournalKeyOperator])
+	assert.Equal(t, "2026-10-16T12:00:00Z", cm.Data[JournalKeyStartTime])
+	assert.NotContains(t, cm.Data, JournalKeyEndTime, "the run is in progress")
+
+	var result apiv1.Result
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[JournalKeyResult]), &result))
+	require.Len(t, result.PVCs, 1)
+	assert.Equal(t, "db/data", result.PVCs[0].PVC)
+	assert.Equal(t, "vol-new", result.PVCs[0].VolumeID)
+
+	journal.EndTime = start.Add(time.Hour)
+	require.NoError(t, m.SaveJournal(ctx, journal))
+	cm, err = clientset.CoreV1().ConfigMaps("ops").Get(ctx, k8s.JournalName("20261016T120000Z-0a1b"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16T13:00:00Z", cm.Data[JournalKeyEndTime])
+}
+
+func TestReadJournals(t *testing.T) {
+	t.Parallel()
+
+	client := k8s.NewClientWithInterface(fake.NewSimpleClientset(), nil) //nolint:staticcheck // NewClientset requires apply configurations
+	ctx := context.Background()
+	save := func(id, start, end, result string) {
+		data := map[string]string{
+			JournalKeyMigrationID: id,
+			JournalKeyKubeContext: "prod",
+			JournalKeyOperator:    "alice@example.com",
+			JournalKeyStartTime:   start,
+			JournalKeyResult:      result,
+		}
+		if end != "" {
+			data[JournalKeyEndTime] = end
+		}
+		require.NoError(t, client.SaveJournal(ctx, "ops", id, data))
+	}
+	save("first", "2026-10-14T09:00:00Z", "2026-10-14T10:00:00Z", `{"total": 2, "migrated": 1, "failed": 1}`)
+	save("second", "2026-10-15T09:00:00Z", "2026-10-15T09:30:00Z", `{"total": 3, "migrated": 3}`)
+	save("crashed", "2026-10-16T09:00:00Z", "", `{"total": 1}`)
+	save("broken", "yesterday", "", `{}`)
+
+	records, err := ReadJournals(ctx, client, "ops")
+	require.NoError(t, err)
+	require.Len(t, records, 3, "the unreadable journal is left out")
+	assert.Equal(t, "crashed", records[0].MigrationID, "latest first")
+	assert.Equal(t, RunUnfinished, records[0].Outcome())
+	assert.Equal(t, "second", records[1].MigrationID)
+	assert.Equal(t, RunSucceeded, records[1].Outcome())
+	assert.Equal(t, 3, records[1].Result.Migrated)
+	assert.Equal(t, "first", records[2].MigrationID)
+	assert.Equal(t, RunFailed, records[2].Outcome())
+	assert.Equal(t, "prod", records[2].KubeContext)
+	assert.Equal(t, time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC), records[2].EndTime)
+
+	record, err := ReadJournal(ctx, client, "ops", "second")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", record.Operator)
+
+	_, err = ReadJournal(ctx, client, "ops", "broken")
+	require.ErrorContains(t, err, "invalid startTime in journal broken")
+}
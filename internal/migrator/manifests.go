@@ -0,0 +1,74 @@
+package migrator
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// ManifestsPath returns the file the new PV and PVC of a claim are written to
+// when the run outputs manifests instead of applying them
+func (c *Config) ManifestsPath(pvcName string) string {
+	namespace, shortName := ParsePVCName(pvcName)
+	return filepath.Join(c.OutputManifests, namespace, shortName+".yaml")
+}
+
+// DeletePatchPath returns the file of the kustomize patches deleting the old PVC
+// and PV of a claim
+func (c *Config) DeletePatchPath(pvcName string) string {
+	namespace, shortName := ParsePVCName(pvcName)
+	return filepath.Join(c.OutputManifests, namespace, shortName+"-delete.yaml")
+}
+
+// writeManifests writes the new PV and PVC of a claim, bound to newVolumeID, and
+// the patches deleting its old PVC and PV, for committing to the GitOps repo
+// that applies them in place of switchClaim
+func (m *Migrator) writeManifests(pvcName string, info *k8s.PVCInfo, newPVName, newVolumeID, targetZone string) error {
+	namespace, shortName := ParsePVCName(pvcName)
+	item := PVCPlanItem{Name: pvcName, Namespace: namespace, PVCName: shortName, Capacity: info.Capacity, TargetZone: targetZone,
+		Block: info.VolumeMode == corev1.PersistentVolumeBlock}
+	storageClass := m.config.StorageClassFor(pvcName)
+	manifests := runbookPVManifest(newPVName, newVolumeID, item, storageClass, m.config.CSIDriver) + "---\n" + runbookPVCManifest(newPVName, item, storageClass)
+
+	path := m.config.ManifestsPath(pvcName)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create manifests directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(manifests), 0o600); err != nil {
+		return fmt.Errorf("failed to write manifests: %w", err)
+	}
+	if err := os.WriteFile(m.config.DeletePatchPath(pvcName), []byte(deletePatches(namespace, shortName, info.PVName)), 0o600); err != nil {
+		return fmt.Errorf("failed to write delete patches: %w", err)
+	}
+	slog.Info("PV and PVC manifests written", "pvc", pvcName, "pv", newPVName, "volumeId", newVolumeID, "path", path)
+	return nil
+}
+
+// deletePatches returns the kustomize patches deleting a PVC and its PV, or
+// only the PVC when the PV is gone
+func deletePatches(namespace, pvcName, pvName string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `$patch: delete
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s
+  namespace: %s
+`, pvcName, namespace)
+	if pvName != "" {
+		fmt.Fprintf(&b, `---
+$patch: delete
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: %s
+`, pvName)
+	}
+	return b.String()
+}
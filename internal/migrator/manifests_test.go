@@ -0,0 +1,68 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestRun_OutputManifests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+	m := New(&Config{
+		PVCList:         []string{"shop/data"},
+		TargetZone:      "eu-west-1a",
+		StorageClass:    "gp3",
+		MaxConcurrency:  1,
+		StepRetry:       RetryPolicy{MaxAttempts: 1},
+		OutputManifests: dir,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-new"},
+	}))
+	ctx := context.Background()
+	m.Run(ctx)
+
+	status := m.GetStatuses()["shop/data"]
+	require.NoError(t, status.Error)
+	assert.Equal(t, StepDone, status.Step)
+	assert.Equal(t, "vol-new", status.NewVolumeID)
+
+	manifests, err := os.ReadFile(filepath.Join(dir, "shop", "data.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifests), "kind: PersistentVolume\n")
+	assert.Contains(t, string(manifests), "volumeHandle: vol-new")
+	assert.Contains(t, string(manifests), "values: [eu-west-1a]")
+	assert.Contains(t, string(manifests), "kind: PersistentVolumeClaim")
+	assert.Contains(t, string(manifests), "volumeName: "+staticPVName("data"))
+
+	patches, err := os.ReadFile(m.config.DeletePatchPath("shop/data"))
+	require.NoError(t, err)
+	assert.Equal(t, deletePatches("shop", "data", "pv-data"), string(patches))
+	assert.Contains(t, string(patches), "$patch: delete\napiVersion: v1\nkind: PersistentVolume\nmetadata:\n  name: pv-data\n")
+
+	// Nothing is applied to the cluster
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims("shop").Get(ctx, "data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "pv-data", pvc.Spec.VolumeName)
+	_, err = clientset.CoreV1().PersistentVolumes().Get(ctx, staticPVName("data"), metav1.GetOptions{})
+	require.Error(t, err, "the new PV is not created")
+}
+
+func TestDeletePatches_MissingPV(t *testing.T) {
+	t.Parallel()
+
+	patches := deletePatches("shop", "data", "")
+	assert.Contains(t, patches, "kind: PersistentVolumeClaim")
+	assert.NotContains(t, patches, "---")
+}
@@ -4,23 +4,185 @@ package migrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
 	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
 	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
 )
 
+// ErrSnapshotFailed is reported when EC2 moves the snapshot to the error state,
+// which it never leaves
+var ErrSnapshotFailed = errors.New("snapshot failed")
+
 // Config holds the migration configuration
 type Config struct {
 	Namespaces     []string
-	TargetZone     string
+	TargetZone     string   // TargetZoneAuto picks the least-loaded zone for each namespace
+	TargetZones    []string // Spread PVCs across these zones instead of moving them all to TargetZone
+	SourceZone     string   // Zone the run evacuates; checked for volumes left behind once it is over
 	StorageClass   string
-	MaxConcurrency int
-	PVCList        []string // Format: "namespace/pvcname"
-	DryRun         bool
+	StorageClasses map[string]string // "namespace/pvcname" -> storage class overriding StorageClass
+	// NamespaceStorageClasses holds the storage class of PVCs not in StorageClasses,
+	// by namespace, for PVCs added while planning
+	NamespaceStorageClasses map[string]string
+	MaxConcurrency          int
+	PVCList                 []string // Format: "namespace/pvcname"
+	DryRun                  bool
+	KubeContext             string // Appended to generated kubectl commands as --context when set
+
+	// Scheduling is the order PVCs are started in: ScheduleFIFO (the default) or
+	// ScheduleRoundRobin, so a namespace with many PVCs does not starve the others
+	Scheduling string
+	// MaxPerNamespace caps the PVCs of one namespace in progress at once; 0 for no cap
+	MaxPerNamespace int
+	// Priorities holds the priority of PVCs that are not PriorityNormal, by
+	// "namespace/pvcname". High priority PVCs start first and low priority ones last.
+	Priorities map[string]string
+	// NamespacePriorities holds the priority of PVCs not in Priorities, by
+	// namespace, for PVCs added while planning
+	NamespacePriorities map[string]string
+	// Windows holds the daily window the PVCs of a namespace may be migrated in,
+	// by namespace. PVCs are held until it opens and wait between steps while
+	// it is closed.
+	Windows map[string]TimeWindow
+
+	// IncludeCoMounted adds PVCs that pods mount together with the PVCs of the
+	// run, so no pod is left with volumes in two zones
+	IncludeCoMounted bool
+	// AllowEmptyZone only reports the target zones the pods of migrated PVCs
+	// could not be scheduled in, instead of failing their PVCs in the plan
+	AllowEmptyZone bool
+	// CheckProvisioning looks at the Karpenter NodePools and node groups for a
+	// target zone without healthy nodes: the zone passes the check when one of
+	// them can launch nodes in it
+	CheckProvisioning bool
+	// SimulateScheduling dry-runs a pod of each workload mounting PVCs of the plan
+	// in their target zone, and reports whether it could be scheduled there
+	SimulateScheduling bool
+	// CheckPermissions dry-runs the EC2 calls of a migration and fails the PVCs
+	// to migrate in the plan when the credentials are denied any of them
+	CheckPermissions bool
+	// CheckQuotas fails the PVCs to migrate in the plan when the snapshots and
+	// storage the run adds would exceed the account's EBS quotas
+	CheckQuotas bool
+	// SnapshotQuota and StorageQuotasTiB, by volume type, are the EBS quotas of
+	// the account; 0 or a missing type stands for the AWS defaults
+	SnapshotQuota    int
+	StorageQuotasTiB map[string]int
+
+	// FastSnapshotRestore enables fast snapshot restore of each snapshot in its
+	// target zone before creating the new volume from it, and disables it once
+	// the volume is available, so the volume is fully initialized from the start
+	FastSnapshotRestore bool
+	// VolumeType, VolumeIOPS and VolumeThroughput override the type, provisioned
+	// IOPS and gp3 throughput of the new volumes, which otherwise are those of
+	// the old volume; types new volumes cannot be created as become aws.VolumeType
+	VolumeType       string
+	VolumeIOPS       int32
+	VolumeThroughput int32
+
+	// StagedSnapshotMaxAge lets the migration start from a snapshot staged by the
+	// snapshot command when it is younger than this; 0 disables adoption
+	StagedSnapshotMaxAge time.Duration
+	// MaxSnapshotStaleness lets it start from a staged snapshot when the volume was
+	// last written at most this long after the snapshot started; 0 disables the check
+	MaxSnapshotStaleness time.Duration
+	// CheckWriteActivity reads the volume's CloudWatch write metrics to find its
+	// last write instead of assuming it is written up to now
+	CheckWriteActivity bool
+
+	// BackupVault, when named, also backs each source volume up to the AWS
+	// Backup vault once it is snapshotted, so the vault's retention and lock
+	// policies apply to a restore point of the data
+	BackupVault aws.BackupVault
+
+	// KMSKeyID encrypts the new volumes with this key. When empty they keep the
+	// encryption of their snapshot, or get the account default.
+	KMSKeyID string
+	// TagAnnotationPrefix turns the PVC annotations starting with it into tags of
+	// its snapshot and new volume, named by the rest of the annotation; empty
+	// disables them
+	TagAnnotationPrefix string
+	// CSIDriver is the driver of the new PVs the runbook, remediations and
+	// OutputManifests write; k8s.DefaultCSIDriver when empty. The k8s client
+	// creating them is given the same with SetCSIDriver.
+	CSIDriver string
+	// Tags are added to every snapshot and new volume, their values Go templates
+	// of {{.Namespace}}, {{.PVCName}} and {{.RunID}}, the MigrationID. Tags from
+	// annotations win over them.
+	Tags map[string]string
+	// MigrationID scopes the client tokens of the snapshots and volumes the run
+	// creates, so a run restarted with the same ID adopts those it already
+	// created. New generates one when empty.
+	MigrationID string
+	// StepRetry retries the call of a step that fails with a transient error,
+	// such as a 5xx from EC2 or a conflict creating the PV, instead of failing
+	// the PVC
+	StepRetry RetryPolicy
+
+	// BackupDir receives the manifests of each PVC and PV before cleanup deletes
+	// them, in <BackupDir>/<MigrationID>/<namespace>/<pvc>.yaml; empty disables it
+	BackupDir string
+	// JournalBackups also keeps those manifests in the run's journal ConfigMap
+	JournalBackups bool
+	// OutputManifests receives the new PV and PVC of each claim, and kustomize
+	// patches deleting the old ones, in <OutputManifests>/<namespace>/, instead of
+	// them being applied, for a GitOps repo to apply; empty applies them
+	OutputManifests string
+	// BindTimeout is how long a new claim may take to be Bound before its PVC
+	// fails; DefaultBindTimeout when 0
+	BindTimeout time.Duration
+	// VerifyMount runs a pod in the target zone that writes to each migrated
+	// claim and reads it back, before its workloads are scaled up
+	VerifyMount bool
+	CheckImage  string // Image of the mount check and checksum pods (needs sh); busybox when empty
+	// VerifyChecksum hashes the files on each source volume before its snapshot
+	// and on the new volume after, and fails the PVC when they differ
+	VerifyChecksum  bool
+	ChecksumTimeout time.Duration // How long one checksum may take; DefaultChecksumTimeout when 0
+	// FreezeFilesystem runs FreezeCommand in the pods still mounting a volume
+	// before its snapshot and ThawCommand after, with the mount path as $1;
+	// DefaultFreezeCommand and DefaultThawCommand when empty
+	FreezeFilesystem bool
+	FreezeCommand    string
+	ThawCommand      string
+
+	autoZones []string // Zones GeneratePlan picked for the namespaces with TargetZoneAuto
+}
+
+// DefaultBindTimeout is how long a new claim may take to be Bound by default
+const DefaultBindTimeout = 2 * time.Minute
+
+// bindTimeout returns how long a new claim may take to be Bound
+func (c *Config) bindTimeout() time.Duration {
+	if c.BindTimeout > 0 {
+		return c.BindTimeout
+	}
+	return DefaultBindTimeout
+}
+
+// StorageClassFor returns the storage class of the new PV and PVC of a claim
+func (c *Config) StorageClassFor(pvcName string) string {
+	if class, ok := c.StorageClasses[pvcName]; ok {
+		return class
+	}
+	ns, _ := ParsePVCName(pvcName)
+	if class, ok := c.NamespaceStorageClasses[ns]; ok {
+		return class
+	}
+	return c.StorageClass
 }
 
 // Step represents a migration step
@@ -65,20 +227,51 @@ func (s Step) String() string {
 
 // PVCStatus represents the current status of a PVC migration
 type PVCStatus struct {
-	Name        string // Full name in format "namespace/pvcname"
-	Namespace   string
-	PVCName     string // Just the PVC name without namespace
-	Step        Step
-	Progress    int
-	Error       error
-	StartTime   time.Time
-	EndTime     time.Time
-	SnapshotID  string
+	Name           string // Full name in format "namespace/pvcname"
+	Namespace      string
+	PVCName        string // Just the PVC name without namespace
+	Step           Step
+	FailedStep     Step // Step that was running when the migration failed
+	Progress       int
+	Error          error
+	StartTime      time.Time
+	EndTime        time.Time
+	SnapshotID     string
+	StagedSnapshot bool   // SnapshotID was made by the snapshot command and carries its tags
+	BackupJobID    string // AWS Backup job backing the source volume up to the vault
+	// AdoptedSnapshot and AdoptedVolume are set when SnapshotID or NewVolumeID was
+	// created by an earlier run with the same migration ID
+	AdoptedSnapshot bool
+	AdoptedVolume   bool
+	// MountCheck is MountCheckPassed or MountCheckFailed once the mount check
+	// of the new claim has run, with the reason it failed in MountCheckError
+	MountCheck      string
+	MountCheckError error
+	// Checksum is of the files on the source volume, taken right before its
+	// snapshot; ChecksumVerified is set once the new volume matched it
+	Checksum         string
+	ChecksumVerified bool
+	// ResumedAt is the step the run went on from after finding the static PV an
+	// earlier run left behind; StepPending when it started over
+	ResumedAt   Step
 	NewVolumeID string
 	OldVolumeID string
 	PVName      string
 	Capacity    string
-	CurrentZone string // Current availability zone of the volume
+	SizeGiB     int32     // Capacity rounded up to whole GiB
+	CurrentZone string    // Current availability zone of the volume
+	TargetZone  string    // Zone the volume is moved to, set by GeneratePlan
+	ThrottledAt time.Time // Last time EC2 throttled one of its calls, which are retried with backoff
+	// VolumeSpec is the type and performance the new volume is created with: those
+	// of the old volume, where the run does not override them
+	VolumeSpec aws.VolumeSpec
+	// Block is set for claims of raw block devices (volumeMode: Block), which
+	// have no files to freeze, hash or write
+	Block bool
+	// StaticPVRolledBack is set when the new PV of a PVC that failed before its
+	// claim was switched over has been deleted again
+	StaticPVRolledBack bool
+	Seq                uint64 // Sequence number of the last change, see StatusesSince
 }
 
 // ParsePVCName parses a "namespace/pvcname" string into its components
@@ -121,10 +314,50 @@ type PVCPlanItem struct {
 	PVName      string
 	VolumeID    string
 	Capacity    string
+	CapacityGi  int32
 	CurrentZone string
 	TargetZone  string
 	Action      PlanAction
 	Reason      string // Reason for skip or error
+	Attached    bool   // Mounted by a pod, so its workloads must be scaled down
+
+	StorageClass       string         // Overrides the plan's storage class for this PVC, if set
+	ClaimPhase         string         // Phase of the PVC, e.g. Bound or Lost
+	PVPhase            string         // Phase of the PV, empty when it was deleted
+	PVMissing          bool           // The PV was deleted and its volume was found by its tags
+	CoMountedWith      string         // Pod whose other PVCs pulled this one into the run, if any
+	Priority           string         // PriorityHigh or PriorityLow, empty for normal
+	Window             string         // Daily window of its namespace, e.g. "02:00-04:00 UTC", if any
+	StagedSnapshotID   string         // Staged snapshot the migration starts from, if any
+	StagedSnapshotTime time.Time      // When the staged snapshot was started
+	SourceKMSKeyID     string         // Key the current volume is encrypted with, empty when it is not
+	KMSKeyID           string         // Key the new volume is encrypted with, empty when it is not
+	VolumeSpec         aws.VolumeSpec // Type and performance the new volume is created with
+	Block              bool           // A raw block device (volumeMode: Block); the new PV and PVC are too
+
+	Tags map[string]string // Tags the PVC's annotations add to its snapshot and volume
+
+	// ResumeAt is the step the migration goes on from, as an earlier run left the
+	// static PV behind; StepPending when it starts over
+	ResumeAt Step
+}
+
+// volumeMode returns the volume mode of the PVC and of its new PV and PVC
+func (i PVCPlanItem) volumeMode() corev1.PersistentVolumeMode {
+	if i.Block {
+		return corev1.PersistentVolumeBlock
+	}
+	return corev1.PersistentVolumeFilesystem
+}
+
+// Rebuilt reports whether the PVC is not a healthy Bound pair: it is Lost, or its
+// PV was deleted or is not Bound. The run replaces both with a Bound pair.
+func (i PVCPlanItem) Rebuilt() bool {
+	switch corev1.PersistentVolumePhase(i.PVPhase) {
+	case corev1.VolumePending, corev1.VolumeReleased, corev1.VolumeFailed:
+		return true
+	}
+	return i.PVMissing || corev1.PersistentVolumeClaimPhase(i.ClaimPhase) == corev1.ClaimLost
 }
 
 // MigrationPlan holds the complete migration plan
@@ -135,6 +368,67 @@ type MigrationPlan struct {
 	DryRun       bool
 	Namespaces   []string
 	Concurrency  int
+	KMSKeyID     string                  // Key set for the run, if any
+	CSIDriver    string                  // Driver of the new PVs; k8s.DefaultCSIDriver when empty
+	Encryption   *aws.EncryptionDefaults // Account encryption defaults; nil when unknown
+	AutoZones    []ZoneChoice            // Zone picked for each namespace with TargetZoneAuto
+	ZoneIDs      map[string]string       // ID of each zone of the region, by name; nil when unknown
+	FastRestore  bool                    // Fast snapshot restore is enabled while the new volumes are created
+	VolumeSpec   aws.VolumeSpec          // Type and performance the run gives the new volumes
+	KeepVolume   bool                    // New volumes keep the type and performance of the old ones VolumeSpec does not set
+	NodeIssues   []NodeIssue             // Target zones the pods of PVCs to migrate could not run in
+	// Schedulability is, with SimulateScheduling, whether a pod of each workload
+	// mounting PVCs to migrate could be scheduled in their target zone
+	Schedulability []WorkloadSchedule
+	// Quotas is, with CheckQuotas, the EBS usage the run adds to and the quotas
+	// it was compared against; nil when it was not checked
+	Quotas *QuotaCheck
+	// MissingPermissions are, with CheckPermissions, the EC2 actions the
+	// credentials are denied, such as "ec2:CreateSnapshot"
+	MissingPermissions []string
+}
+
+// ScaleNamespaces returns the sorted namespaces whose workloads must be scaled down:
+// those with at least one mounted PVC to migrate
+func (p *MigrationPlan) ScaleNamespaces() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range p.Items {
+		if item.Action != PlanActionMigrate || !item.Attached || seen[item.Namespace] {
+			continue
+		}
+		seen[item.Namespace] = true
+		result = append(result, item.Namespace)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// MigrateNamespaces returns the namespaces with a PVC to migrate, sorted. Their
+// PVCs are deleted and recreated, whether or not their workloads are scaled.
+func (p *MigrationPlan) MigrateNamespaces() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range p.Items {
+		if item.Action != PlanActionMigrate || seen[item.Namespace] {
+			continue
+		}
+		seen[item.Namespace] = true
+		result = append(result, item.Namespace)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Pending returns the number of PVCs the plan migrates
+func (p *MigrationPlan) Pending() int {
+	pending := 0
+	for _, item := range p.Items {
+		if item.Action == PlanActionMigrate {
+			pending++
+		}
+	}
+	return pending
 }
 
 // Migrator handles PVC migrations
@@ -143,20 +437,39 @@ type Migrator struct {
 	k8sClient *k8s.Client
 	awsClient *aws.Client
 	statuses  map[string]*PVCStatus
-	mu        sync.RWMutex
-	done      bool
+	listeners []EventListener
+	warnings  []Warning
+	orphans   []OrphanedObject
+	// stragglers are the volumes VerifySourceZone found in the source zone
+	stragglers []Straggler
+	mu         sync.RWMutex
+	done       bool
+	seq        uint64        // Incremented on every status change
+	resumed    chan struct{} // Closed on resume; nil unless paused
+
+	retrySnapshots map[string]string // Completed snapshots retried PVCs start from
+	retryVolumes   map[string]string // Volumes created by failed attempts retried PVCs adopt
+	volumeAttempts map[string]int    // Volumes of a PVC that ended in the error state
+	blocked        map[string]string // Why the plan keeps a PVC from moving, by name
+	backups        map[string]string // Manifests saved before cleanup for the journal, by name
+
+	warningListeners []WarningListener
 }
 
 // New creates a new Migrator
 func New(config *Config, k8sClient *k8s.Client, awsClient *aws.Client) *Migrator {
+	if config.MigrationID == "" {
+		config.MigrationID = newMigrationID(time.Now())
+	}
 	statuses := make(map[string]*PVCStatus)
-	for _, pvc := range config.PVCList {
+	for i, pvc := range config.PVCList {
 		ns, name := ParsePVCName(pvc)
 		statuses[pvc] = &PVCStatus{
 			Name:      pvc,
 			Namespace: ns,
 			PVCName:   name,
 			Step:      StepPending,
+			Seq:       uint64(i + 1),
 		}
 	}
 
@@ -165,6 +478,7 @@ func New(config *Config, k8sClient *k8s.Client, awsClient *aws.Client) *Migrator
 		k8sClient: k8sClient,
 		awsClient: awsClient,
 		statuses:  statuses,
+		seq:       uint64(len(config.PVCList)),
 	}
 }
 
@@ -186,6 +500,45 @@ func (m *Migrator) GetStatuses() map[string]*PVCStatus {
 	return result
 }
 
+// StatusesSince returns copies of the statuses that changed after sequence
+// number seq, sorted by name, and the latest sequence number to pass next time.
+// Changes to a PVC coalesce into its latest status, so a consumer that polls
+// slowly gets fewer, not more, entries to process.
+func (m *Migrator) StatusesSince(seq uint64) ([]*PVCStatus, uint64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*PVCStatus
+	for _, v := range m.statuses {
+		if v.Seq > seq {
+			copyStatus := *v
+			result = append(result, &copyStatus)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, m.seq
+}
+
+// touch marks a status as changed; the caller must hold m.mu
+func (m *Migrator) touch(s *PVCStatus) {
+	m.seq++
+	s.Seq = m.seq
+}
+
+// observeThrottles returns a context whose throttled EC2 calls are recorded in
+// the status of the PVC, so the UI can show that it is backing off
+func (m *Migrator) observeThrottles(ctx context.Context, pvcName string) context.Context {
+	return aws.WithThrottleObserver(ctx, func(operation string) {
+		slog.Warn("EC2 throttled the request, backing off", "pvc", pvcName, "operation", operation)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if s, ok := m.statuses[pvcName]; ok {
+			s.ThrottledAt = time.Now()
+			m.touch(s)
+		}
+	})
+}
+
 // IsDone returns true if all migrations are complete
 func (m *Migrator) IsDone() bool {
 	m.mu.RLock()
@@ -195,34 +548,95 @@ func (m *Migrator) IsDone() bool {
 
 func (m *Migrator) updateStatus(pvcName string, step Step, progress int, err error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
-	if s, ok := m.statuses[pvcName]; ok {
-		s.Step = step
-		s.Progress = progress
-		if err != nil {
-			s.Error = err
-			s.Step = StepFailed
-			s.EndTime = time.Now()
-		}
-		if step == StepDone {
-			s.EndTime = time.Now()
+	s, ok := m.statuses[pvcName]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+
+	changed := s.Step != step || s.Progress != progress || err != nil
+	if err != nil && s.Step != StepFailed {
+		s.FailedStep = s.Step
+	}
+	s.Step = step
+	s.Progress = progress
+	if err != nil {
+		s.Error = err
+		s.Step = StepFailed
+		s.EndTime = time.Now()
+	}
+	if step == StepDone {
+		s.EndTime = time.Now()
+	}
+	if changed {
+		m.touch(s)
+	}
+
+	var event Event
+	listeners := m.listeners
+	if changed && len(listeners) > 0 {
+		event = newEvent(s)
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		slog.Error("PVC migration failed", "pvc", pvcName, "error", err)
+	} else if changed {
+		slog.Debug("PVC status changed", "pvc", pvcName, "step", step.String(), "progress", progress)
+	}
+
+	// Notify outside the lock so slow listeners never block status readers
+	if changed {
+		for _, l := range listeners {
+			l(event)
 		}
 	}
 }
 
 // Run starts the migration process
 func (m *Migrator) Run(ctx context.Context) {
+	m.runEach(ctx, "migration run", m.config.PVCList, m.migratePVC)
+}
+
+// RunSnapshots only creates, and waits for, a staged snapshot of every PVC that
+// is not in the target zone yet. Kubernetes resources are left untouched, so it
+// can run days before the migration to take the data copy out of the window.
+func (m *Migrator) RunSnapshots(ctx context.Context) {
+	m.runEach(ctx, "snapshot run", m.config.PVCList, m.stagePVC)
+}
+
+// runEach calls process for every PVC in names, at most MaxConcurrency at a time.
+// A goroutine is only started once a slot is free, so a run over thousands of
+// PVCs does not keep thousands of goroutines waiting.
+func (m *Migrator) runEach(ctx context.Context, spanName string, names []string, process func(context.Context, string)) {
+	ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(
+		attribute.Int("migration.pvc_count", len(names)),
+		attribute.Int("migration.concurrency", m.config.MaxConcurrency),
+		attribute.String("migration.target_zone", m.config.Destination()),
+	))
+	defer span.End()
+
 	semaphore := make(chan struct{}, m.config.MaxConcurrency)
+	sched := newScheduler(names, m.config)
+	stop := context.AfterFunc(ctx, sched.wake) // Stop holding PVCs for their window
+	defer stop()
 	var wg sync.WaitGroup
 
-	for _, pvcName := range m.config.PVCList {
+	for {
+		semaphore <- struct{}{}
+		m.waitResumed(ctx)
+		pvcName, ok := sched.next(ctx)
+		if !ok {
+			<-semaphore
+			break
+		}
 		wg.Add(1)
 		go func(name string) {
 			defer wg.Done()
-			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
-			m.migratePVC(ctx, name)
+			defer sched.finished(name)
+			process(ctx, name)
 		}(pvcName)
 	}
 
@@ -233,72 +647,548 @@ func (m *Migrator) Run(ctx context.Context) {
 	m.mu.Unlock()
 }
 
+// Pause stops new PVCs from being started. PVCs already in progress finish.
+func (m *Migrator) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.resumed == nil {
+		m.resumed = make(chan struct{})
+		slog.Info("migration paused")
+	}
+}
+
+// Resume starts new PVCs again after Pause
+func (m *Migrator) Resume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.resumed != nil {
+		close(m.resumed)
+		m.resumed = nil
+		slog.Info("migration resumed")
+	}
+}
+
+// Paused reports whether new PVCs are held back
+func (m *Migrator) Paused() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.resumed != nil
+}
+
+// waitResumed blocks while the migration is paused or until ctx is done
+func (m *Migrator) waitResumed(ctx context.Context) {
+	m.mu.RLock()
+	resumed := m.resumed
+	m.mu.RUnlock()
+	if resumed == nil {
+		return
+	}
+	select {
+	case <-resumed:
+	case <-ctx.Done():
+	}
+}
+
 func (m *Migrator) migratePVC(ctx context.Context, pvcName string) {
+	ctx = m.observeThrottles(ctx, pvcName)
 	m.mu.Lock()
 	status := m.statuses[pvcName]
 	status.StartTime = time.Now()
+	m.touch(status)
 	namespace := status.Namespace
 	shortName := status.PVCName
 	m.mu.Unlock()
 
+	ctx, root := tracer.Start(ctx, "migrate PVC", trace.WithAttributes(
+		attribute.String("pvc.namespace", namespace),
+		attribute.String("pvc.name", shortName),
+		attribute.String("migration.target_zone", m.config.Destination()),
+	))
+	spans := &stepSpans{tracer: tracer, root: ctx}
+	defer func() {
+		err := m.statusError(pvcName)
+		spans.end(err)
+		tracing.End(root, err)
+	}()
+
+	info, snapshotID, ok := m.snapshotVolume(ctx, spans, root, pvcName, false)
+	if !ok {
+		return
+	}
+	if m.config.BackupVault.Name != "" && info.VolumeID != "" {
+		m.backupVolume(ctx, pvcName, info.VolumeID)
+	}
+	targetZone, _ := m.targetZone(pvcName) // Checked by snapshotVolume
+	newPVName := staticPVName(shortName)
+
+	// A run resumed from the static PV of an earlier one has its volume already
+	m.mu.RLock()
+	resumedAt := m.statuses[pvcName].ResumedAt
+	newVolumeID := m.statuses[pvcName].NewVolumeID
+	m.mu.RUnlock()
+	if resumedAt == StepPending {
+		if newVolumeID, ok = m.provisionVolume(ctx, spans, root, pvcName, info, snapshotID, targetZone, newPVName); !ok {
+			return
+		}
+	}
+
+	if m.config.OutputManifests != "" {
+		m.updateStatus(pvcName, StepCreatePV, 0, nil)
+		spans.start(StepCreatePV)
+		if err := m.writeManifests(pvcName, info, newPVName, newVolumeID, targetZone); err != nil {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("write manifests: %w", err))
+			return
+		}
+		m.updateStatus(pvcName, StepDone, 100, nil)
+		return
+	}
+
+	// A run resumed after the old PVC was deleted skips the cleanup
+	if !m.switchClaim(ctx, spans, pvcName, info, newPVName, newVolumeID, resumedAt != StepCreatePVC) {
+		return
+	}
+	block := info.VolumeMode == corev1.PersistentVolumeBlock
+	if m.config.VerifyChecksum && !block {
+		m.updateStatus(pvcName, StepCreatePVC, 60, nil)
+		if !m.verifyChecksum(ctx, pvcName, targetZone) {
+			return
+		}
+	}
+	if m.config.VerifyMount && !block {
+		m.updateStatus(pvcName, StepCreatePVC, 75, nil)
+		m.verifyMount(ctx, pvcName, targetZone)
+	}
+	m.updateStatus(pvcName, StepDone, 100, nil)
+	slog.Info("PVC migrated", "pvc", pvcName, "zone", targetZone, "pv", newPVName)
+}
+
+// switchClaim replaces the PVC's old claim and PV with a claim bound to
+// newPVName, inside the PVC's window. It returns false when the PVC failed; its
+// status is already set. The old claim is left alone when cleanup is false, as
+// an earlier run deleted it.
+func (m *Migrator) switchClaim(ctx context.Context, spans *stepSpans, pvcName string, info *k8s.PVCInfo, newPVName, newVolumeID string, cleanup bool) bool {
+	namespace, shortName := ParsePVCName(pvcName)
+
+	// The old PVC is only deleted, and recreated right after, inside the window
+	m.waitWindow(ctx, pvcName)
+
+	// Step 7: Cleanup
+	// We do cleanup AFTER creating the new PV to minimize the risk of data loss/orphaned volumes
+	// if the process crashes.
+	if cleanup {
+		m.updateStatus(pvcName, StepCleanup, 0, nil)
+		stepCtx := spans.start(StepCleanup)
+		if err := m.backupClaim(stepCtx, pvcName, info); err != nil {
+			m.releaseStaticPV(ctx, pvcName, info.PVName, newPVName, newVolumeID)
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("backup: %w", err))
+			return false
+		}
+		if err := m.k8sClient.CleanupResources(stepCtx, namespace, shortName, info.PVName, info.VolumeID); err != nil {
+			// If cleanup fails, we still have the new PV created, but the old one might still exist.
+			// This is a partial failure but better than data loss.
+			m.releaseStaticPV(ctx, pvcName, info.PVName, newPVName, newVolumeID)
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("cleanup: %w", err))
+			return false
+		}
+	}
+
+	// Step 8: Create PVC
+	m.updateStatus(pvcName, StepCreatePVC, 0, nil)
+	stepCtx := spans.start(StepCreatePVC)
+	retried := false
+	err := m.retryStep(stepCtx, pvcName, StepCreatePVC, func() error {
+		err := m.k8sClient.CreateBoundPVC(stepCtx, namespace, shortName, newPVName, info.Capacity, m.config.StorageClassFor(pvcName), info.VolumeMode)
+		if retried && apierrors.IsAlreadyExists(err) {
+			return nil // Created by an attempt that timed out
+		}
+		retried = true
+		return err
+	})
+	if err != nil {
+		m.releaseStaticPV(ctx, pvcName, info.PVName, newPVName, newVolumeID)
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create PVC: %w", err))
+		return false
+	}
+
+	// A claim that does not match its PV, such as by storage class or capacity,
+	// is created but stays Pending, so the PVC is only done once it is Bound
+	m.updateStatus(pvcName, StepCreatePVC, 50, nil)
+	if err := m.k8sClient.WaitForPVCBound(stepCtx, namespace, shortName, m.config.bindTimeout()); err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("wait for PVC to bind: %w", err))
+		return false
+	}
+	return true
+}
+
+// provisionVolume runs the create-volume, wait-volume and create-PV steps for a
+// PVC from its snapshot, and returns the new volume, bound through newPVName. It
+// returns false when the PVC failed; its status is already set.
+func (m *Migrator) provisionVolume(ctx context.Context, spans *stepSpans, root trace.Span, pvcName string, info *k8s.PVCInfo, snapshotID, targetZone, newPVName string) (string, bool) {
+	namespace, shortName := ParsePVCName(pvcName)
+
+	// The snapshot, whether new, staged or reused, becomes the only copy of the
+	// data once the old volume is released, so check it before going further
+	err := m.retryStep(ctx, pvcName, StepWaitSnapshot, func() error {
+		return m.awsClient.ValidateSnapshot(ctx, snapshotID, info.VolumeID, info.CapacityGi)
+	})
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("validate snapshot: %w", err))
+		return "", false
+	}
+
+	m.waitWindow(ctx, pvcName)
+
+	// Step 4: Create Volume
+	m.updateStatus(pvcName, StepCreateVolume, 0, nil)
+	stepCtx := spans.start(StepCreateVolume)
+	// Only a snapshot this run did not take may have a volume already
+	m.mu.RLock()
+	earlier := m.statuses[pvcName].AdoptedSnapshot || m.statuses[pvcName].StagedSnapshot
+	m.mu.RUnlock()
+	newVolumeID := m.retryVolume(stepCtx, pvcName)
+	if newVolumeID == "" && earlier {
+		newVolumeID = m.migratedVolume(stepCtx, pvcName, snapshotID, targetZone)
+	}
+	adopted := newVolumeID != ""
+	// Disables the fast snapshot restore the run enabled, once the volume is available
+	releaseFastRestore := func() {}
+	if !adopted {
+		m.mu.RLock()
+		spec := m.statuses[pvcName].VolumeSpec
+		m.mu.RUnlock()
+		if err := spec.Validate(info.CapacityGi); err != nil {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create volume: %w", err))
+			return "", false
+		}
+		if m.config.FastSnapshotRestore && m.enableFastRestore(stepCtx, pvcName, snapshotID, targetZone) {
+			releaseFastRestore = sync.OnceFunc(func() {
+				m.disableFastRestore(context.WithoutCancel(ctx), pvcName, snapshotID, targetZone)
+			})
+			defer releaseFastRestore()
+		}
+		err = m.retryStep(stepCtx, pvcName, StepCreateVolume, func() (err error) {
+			// The client token makes a retry return the volume of an attempt that timed out
+			newVolumeID, adopted, err = m.awsClient.CreateVolume(stepCtx, snapshotID, targetZone, shortName, namespace, m.config.KMSKeyID,
+				m.volumeToken(pvcName, snapshotID, targetZone), info.CapacityGi, spec, m.pvcTags(namespace, shortName, info.Annotations))
+			return err
+		})
+		if err != nil {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create volume: %w", err))
+			return "", false
+		}
+	}
+
+	m.mu.Lock()
+	m.statuses[pvcName].NewVolumeID = newVolumeID
+	m.statuses[pvcName].AdoptedVolume = adopted
+	m.touch(m.statuses[pvcName])
+	m.mu.Unlock()
+	if adopted {
+		slog.Info("adopting volume of an earlier run", "pvc", pvcName, "snapshotId", snapshotID, "volumeId", newVolumeID, "zone", targetZone)
+	} else {
+		slog.Info("volume created", "pvc", pvcName, "snapshotId", snapshotID, "volumeId", newVolumeID, "zone", targetZone)
+	}
+	root.SetAttributes(attribute.String("ec2.new_volume_id", newVolumeID), attribute.Bool("migration.adopted_volume", adopted))
+
+	// Step 5: Wait for Volume
+	m.updateStatus(pvcName, StepWaitVolume, 0, nil)
+	stepCtx = spans.start(StepWaitVolume)
+	poll := pollBackoff{initial: volumePollInitial, max: volumePollMax}
+	for {
+		var state string
+		err := m.retryStep(stepCtx, pvcName, StepWaitVolume, func() (err error) {
+			state, err = m.awsClient.GetVolumeState(stepCtx, newVolumeID)
+			return err
+		})
+		if err != nil {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get volume state: %w", err))
+			return "", false
+		}
+
+		if state == "available" {
+			m.updateStatus(pvcName, StepWaitVolume, 100, nil)
+			releaseFastRestore()
+			break
+		}
+		if state == "error" {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("volume creation failed"))
+			return "", false
+		}
+
+		progress := 50
+		if state == "creating" {
+			progress = 25
+		}
+		m.updateStatus(pvcName, StepWaitVolume, progress, nil)
+
+		select {
+		case <-ctx.Done():
+			m.updateStatus(pvcName, StepFailed, 0, ctx.Err())
+			return "", false
+		case <-time.After(poll.next()):
+		}
+	}
+
+	// The PV is written out with its claim instead when the run outputs manifests
+	if m.config.OutputManifests != "" {
+		return newVolumeID, true
+	}
+
+	m.waitWindow(ctx, pvcName)
+
+	// Step 6: Create PV
+	m.updateStatus(pvcName, StepCreatePV, 0, nil)
+	stepCtx = spans.start(StepCreatePV)
+	retried := false
+	err = m.retryStep(stepCtx, pvcName, StepCreatePV, func() error {
+		err := m.k8sClient.CreateStaticPV(stepCtx, newPVName, newVolumeID, info.Capacity, m.config.StorageClassFor(pvcName), targetZone, info.VolumeMode)
+		if retried && apierrors.IsAlreadyExists(err) {
+			return nil // Created by an attempt that timed out
+		}
+		retried = true
+		return err
+	})
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create PV: %w", err))
+		return "", false
+	}
+
+	return newVolumeID, true
+}
+
+// stagePVC creates the staged snapshot of one PVC and waits for it to complete
+func (m *Migrator) stagePVC(ctx context.Context, pvcName string) {
+	ctx = m.observeThrottles(ctx, pvcName)
+	m.mu.Lock()
+	status := m.statuses[pvcName]
+	status.StartTime = time.Now()
+	m.touch(status)
+	namespace := status.Namespace
+	shortName := status.PVCName
+	m.mu.Unlock()
+
+	ctx, root := tracer.Start(ctx, "snapshot PVC", trace.WithAttributes(
+		attribute.String("pvc.namespace", namespace),
+		attribute.String("pvc.name", shortName),
+		attribute.String("migration.target_zone", m.config.Destination()),
+	))
+	spans := &stepSpans{tracer: tracer, root: ctx}
+	defer func() {
+		err := m.statusError(pvcName)
+		spans.end(err)
+		tracing.End(root, err)
+	}()
+
+	_, snapshotID, ok := m.snapshotVolume(ctx, spans, root, pvcName, true)
+	if !ok {
+		return
+	}
+	m.updateStatus(pvcName, StepDone, 100, nil)
+	slog.Info("snapshot staged", "pvc", pvcName, "snapshotId", snapshotID)
+}
+
+// snapshotVolume runs the get-info, snapshot and wait-snapshot steps for a PVC.
+// Staged snapshots carry the tags that let a later migration adopt them. It
+// returns false when the PVC failed or was skipped; its status is already set.
+func (m *Migrator) snapshotVolume(ctx context.Context, spans *stepSpans, root trace.Span, pvcName string, staged bool) (*k8s.PVCInfo, string, bool) {
+	m.mu.RLock()
+	namespace := m.statuses[pvcName].Namespace
+	shortName := m.statuses[pvcName].PVCName
+	m.mu.RUnlock()
+
 	// Step 1: Get PVC Info
 	m.updateStatus(pvcName, StepGetInfo, 0, nil)
-	info, err := m.k8sClient.GetPVCInfo(ctx, namespace, shortName)
+	stepCtx := spans.start(StepGetInfo)
+	m.mu.RLock()
+	reason, blocked := m.blocked[pvcName]
+	m.mu.RUnlock()
+	if blocked && !staged {
+		m.updateStatus(pvcName, StepFailed, 0, errors.New(reason))
+		return nil, "", false
+	}
+	var info *k8s.PVCInfo
+	err := m.retryStep(stepCtx, pvcName, StepGetInfo, func() (err error) {
+		info, err = m.pvcInfo(stepCtx, namespace, shortName)
+		return err
+	})
+	if err != nil && !staged && apierrors.IsNotFound(err) {
+		// An earlier run deleted the old PVC before it stopped
+		if pv := m.earlierStaticPV(stepCtx, pvcName); pv != nil {
+			return m.resumeDeletedClaim(stepCtx, pvcName, pv)
+		}
+	}
 	if err != nil {
 		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get info: %w", err))
-		return
+		return nil, "", false
 	}
 
 	m.mu.Lock()
 	m.statuses[pvcName].OldVolumeID = info.VolumeID
 	m.statuses[pvcName].PVName = info.PVName
 	m.statuses[pvcName].Capacity = info.Capacity
+	m.statuses[pvcName].SizeGiB = info.CapacityGi
+	m.statuses[pvcName].Block = info.VolumeMode == corev1.PersistentVolumeBlock
+	m.touch(m.statuses[pvcName])
 	m.mu.Unlock()
+	root.SetAttributes(attribute.String("ec2.volume_id", info.VolumeID), attribute.Int("pvc.size_gib", int(info.CapacityGi)))
 
 	// Check if the volume is already in the target zone
-	volumeInfo, err := m.awsClient.GetVolumeInfo(ctx, info.VolumeID)
+	var volumeInfo *aws.VolumeInfo
+	err = m.retryStep(stepCtx, pvcName, StepGetInfo, func() (err error) {
+		volumeInfo, err = m.awsClient.GetVolumeInfo(stepCtx, info.VolumeID)
+		return err
+	})
 	if err != nil {
 		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get volume info: %w", err))
-		return
+		return nil, "", false
 	}
 
 	m.mu.Lock()
 	m.statuses[pvcName].CurrentZone = volumeInfo.AvailabilityZone
+	m.statuses[pvcName].VolumeSpec = m.volumeSpec(volumeInfo)
+	m.touch(m.statuses[pvcName])
 	m.mu.Unlock()
+	root.SetAttributes(attribute.String("migration.source_zone", volumeInfo.AvailabilityZone))
 
 	// Skip migration if already in target zone
-	if volumeInfo.AvailabilityZone == m.config.TargetZone {
+	if m.inTargetZone(pvcName, volumeInfo.AvailabilityZone) {
+		slog.Info("skipping PVC already in target zone", "pvc", pvcName, "zone", volumeInfo.AvailabilityZone)
 		m.updateStatus(pvcName, StepSkipped, 100, nil)
 		m.mu.Lock()
 		m.statuses[pvcName].EndTime = time.Now()
+		m.touch(m.statuses[pvcName])
 		m.mu.Unlock()
-		return
+		return nil, "", false
+	}
+
+	targetZone, err := m.targetZone(pvcName)
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, err)
+		return nil, "", false
 	}
+	root.SetAttributes(attribute.String("migration.target_zone", targetZone))
 
 	if m.config.DryRun {
+		slog.Info("dry run: would migrate PVC", "pvc", pvcName, "from", volumeInfo.AvailabilityZone, "to", targetZone)
 		m.updateStatus(pvcName, StepDone, 100, nil)
-		return
+		return nil, "", false
+	}
+
+	// Go on from the static PV an earlier run created before it stopped
+	if !staged {
+		if pv := m.earlierStaticPV(stepCtx, pvcName); pv != nil {
+			if err := m.resumeStaticPV(stepCtx, pvcName, pv, StepCleanup, volumeInfo); err != nil {
+				m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get info: %w", err))
+				return nil, "", false
+			}
+			return info, "", true
+		}
+	}
+
+	// Start from the snapshot a failed attempt completed
+	if !staged {
+		if snapshotID := m.retrySnapshot(pvcName); snapshotID != "" {
+			slog.Info("reusing snapshot of failed attempt", "pvc", pvcName, "snapshotId", snapshotID)
+			root.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID))
+			return info, snapshotID, true
+		}
+	}
+
+	// Start from a fresh enough snapshot staged by the snapshot command
+	if !staged {
+		if snap := m.stagedSnapshot(stepCtx, info.VolumeID, namespace, shortName); snap != nil {
+			m.mu.Lock()
+			m.statuses[pvcName].SnapshotID = snap.SnapshotID
+			m.statuses[pvcName].StagedSnapshot = true
+			m.touch(m.statuses[pvcName])
+			m.mu.Unlock()
+			slog.Info("adopting staged snapshot", "pvc", pvcName, "snapshotId", snap.SnapshotID, "started", snap.StartTime)
+			root.SetAttributes(attribute.String("ec2.snapshot_id", snap.SnapshotID), attribute.Bool("migration.staged_snapshot", true))
+			return info, snap.SnapshotID, true
+		}
 	}
 
 	// Step 2: Create Snapshot
 	m.updateStatus(pvcName, StepSnapshot, 0, nil)
-	snapshotID, err := m.awsClient.CreateSnapshot(ctx, info.VolumeID, shortName, m.config.TargetZone)
+	stepCtx = spans.start(StepSnapshot)
+	var snapshotID string
+	tags := m.pvcTags(namespace, shortName, info.Annotations)
+	token := m.snapshotToken(pvcName, info.VolumeID, targetZone)
+	if !staged {
+		snapshotID = m.earlierSnapshot(stepCtx, pvcName, volumeInfo, token)
+	}
+	if !staged && snapshotID == "" {
+		snapshotID = m.migrationSnapshot(stepCtx, pvcName, volumeInfo)
+	}
+	adopted := snapshotID != ""
+	// A raw block device has no files, so the checks of its files are skipped
+	block := info.VolumeMode == corev1.PersistentVolumeBlock
+	if block {
+		m.warnBlockChecks(pvcName)
+	}
+	// The checksum is only comparable with a snapshot taken right after it
+	if !staged && !adopted && m.config.VerifyChecksum && !block && !m.sourceChecksum(stepCtx, pvcName, volumeInfo.AvailabilityZone) {
+		return nil, "", false
+	}
+	// A volume still in use, as in a hot snapshot, is frozen while the snapshot is requested
+	thaw := func() {}
+	if (staged || !adopted) && m.config.FreezeFilesystem && !block {
+		if thaw, err = m.freezeClaim(stepCtx, pvcName); err != nil {
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("freeze filesystem: %w", err))
+			return nil, "", false
+		}
+	}
+	switch {
+	case staged:
+		err = m.retryStep(stepCtx, pvcName, StepSnapshot, func() (err error) {
+			snapshotID, err = m.awsClient.CreateStagedSnapshot(stepCtx, info.VolumeID, namespace, shortName, targetZone, tags)
+			return err
+		})
+	case !adopted:
+		retried := false
+		err = m.retryStep(stepCtx, pvcName, StepSnapshot, func() (err error) {
+			// A snapshot taken by an attempt that timed out carries the token
+			if retried {
+				if snapshotID = m.earlierSnapshot(stepCtx, pvcName, volumeInfo, token); snapshotID != "" {
+					return nil
+				}
+			}
+			retried = true
+			snapshotID, err = m.awsClient.CreateSnapshot(stepCtx, info.VolumeID, shortName, targetZone, token, tags)
+			return err
+		})
+	}
+	thaw()
 	if err != nil {
 		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create snapshot: %w", err))
-		return
+		return nil, "", false
 	}
 
 	m.mu.Lock()
 	m.statuses[pvcName].SnapshotID = snapshotID
+	m.statuses[pvcName].StagedSnapshot = staged
+	m.statuses[pvcName].AdoptedSnapshot = adopted
+	m.touch(m.statuses[pvcName])
 	m.mu.Unlock()
+	if !adopted {
+		slog.Info("snapshot created", "pvc", pvcName, "volumeId", info.VolumeID, "snapshotId", snapshotID)
+	}
+	root.SetAttributes(attribute.String("ec2.snapshot_id", snapshotID), attribute.Bool("migration.adopted_snapshot", adopted))
 
 	// Step 3: Wait for Snapshot with progress
 	m.updateStatus(pvcName, StepWaitSnapshot, 0, nil)
+	stepCtx = spans.start(StepWaitSnapshot)
+	poll := pollBackoff{initial: snapshotPollInitial, max: snapshotPollMax}
 	for {
-		progress, state, err := m.awsClient.GetSnapshotProgress(ctx, snapshotID)
+		var progress int
+		var state string
+		err := m.retryStep(stepCtx, pvcName, StepWaitSnapshot, func() (err error) {
+			progress, state, err = m.awsClient.GetSnapshotProgress(stepCtx, snapshotID)
+			return err
+		})
 		if err != nil {
 			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get snapshot progress: %w", err))
-			return
+			return nil, "", false
 		}
 
 		m.updateStatus(pvcName, StepWaitSnapshot, progress, nil)
@@ -307,145 +1197,364 @@ func (m *Migrator) migratePVC(ctx context.Context, pvcName string) {
 			break
 		}
 		if state == "error" {
-			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("snapshot failed"))
-			return
+			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("snapshot %s: %w", snapshotID, ErrSnapshotFailed))
+			return nil, "", false
 		}
 
 		select {
 		case <-ctx.Done():
 			m.updateStatus(pvcName, StepFailed, 0, ctx.Err())
-			return
-		case <-time.After(5 * time.Second):
+			return nil, "", false
+		case <-time.After(poll.next()):
 		}
 	}
 
-	// Step 4: Create Volume
-	m.updateStatus(pvcName, StepCreateVolume, 0, nil)
-	newVolumeID, err := m.awsClient.CreateVolume(ctx, snapshotID, m.config.TargetZone, shortName, namespace, info.CapacityGi)
+	return info, snapshotID, true
+}
+
+// stagedSnapshot returns the staged snapshot of a volume when adoption is enabled,
+// the snapshot is younger than StagedSnapshotMaxAge and the volume has not been
+// written for longer than MaxSnapshotStaleness after it, or nil
+func (m *Migrator) stagedSnapshot(ctx context.Context, volumeID, namespace, pvcName string) *aws.SnapshotInfo {
+	if m.config.StagedSnapshotMaxAge <= 0 && m.config.MaxSnapshotStaleness <= 0 {
+		return nil
+	}
+	snap, err := m.awsClient.FindStagedSnapshot(ctx, volumeID, namespace, pvcName)
 	if err != nil {
-		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create volume: %w", err))
-		return
+		slog.Warn("failed to look up staged snapshots, taking a new one", "pvc", namespace+"/"+pvcName, "error", err)
+		return nil
 	}
-
-	m.mu.Lock()
-	m.statuses[pvcName].NewVolumeID = newVolumeID
-	m.mu.Unlock()
-
-	// Step 5: Wait for Volume
-	m.updateStatus(pvcName, StepWaitVolume, 0, nil)
-	for {
-		state, err := m.awsClient.GetVolumeState(ctx, newVolumeID)
+	if snap == nil {
+		return nil
+	}
+	if age := time.Since(snap.StartTime); m.config.StagedSnapshotMaxAge > 0 && age > m.config.StagedSnapshotMaxAge {
+		slog.Info("staged snapshot too old, taking a new one", "pvc", namespace+"/"+pvcName,
+			"snapshotId", snap.SnapshotID, "age", age.Round(time.Minute), "maxAge", m.config.StagedSnapshotMaxAge)
+		return nil
+	}
+	if m.config.MaxSnapshotStaleness > 0 {
+		lastWrite, err := m.awsClient.LastWrite(ctx, volumeID, snap.StartTime, m.config.CheckWriteActivity)
 		if err != nil {
-			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get volume state: %w", err))
-			return
+			slog.Warn("failed to check writes since the staged snapshot, taking a new one", "pvc", namespace+"/"+pvcName, "error", err)
+			return nil
+		}
+		if staleness := lastWrite.Sub(snap.StartTime); staleness > m.config.MaxSnapshotStaleness {
+			slog.Info("volume written after the staged snapshot, taking a new one", "pvc", namespace+"/"+pvcName,
+				"snapshotId", snap.SnapshotID, "lastWrite", lastWrite, "staleness", staleness.Round(time.Minute),
+				"maxStaleness", m.config.MaxSnapshotStaleness)
+			return nil
 		}
+	}
+	return snap
+}
 
-		if state == "available" {
-			m.updateStatus(pvcName, StepWaitVolume, 100, nil)
-			break
+// pvcInfo gets the PVC's info. When its PV was deleted, the EBS volume that was
+// provisioned for it is looked up by its tags and adopted.
+func (m *Migrator) pvcInfo(ctx context.Context, namespace, pvcName string) (*k8s.PVCInfo, error) {
+	info, err := m.k8sClient.GetPVCInfo(ctx, namespace, pvcName)
+	if err != nil {
+		return nil, err
+	}
+	if info.PVMissing {
+		volumeID, err := m.awsClient.FindVolumeByPV(ctx, info.PVName)
+		if err != nil {
+			return nil, fmt.Errorf("PV %s was deleted and its volume was not found: %w", info.PVName, err)
 		}
-		if state == "error" {
-			m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("volume creation failed"))
-			return
+		slog.Info("adopting volume of deleted PV", "pvc", namespace+"/"+pvcName, "pv", info.PVName, "volumeId", volumeID)
+		info.VolumeID = volumeID
+	}
+	return info, nil
+}
+
+// mountedClaims returns the PVCs of the namespace mounted by a pod that has not
+// finished, and the groups of PVCs pods mount together. When pods cannot be
+// listed every PVC is assumed mounted, so the workloads are still scaled down.
+func (m *Migrator) mountedClaims(ctx context.Context, namespace string) (map[string]bool, []coMountedGroup) {
+	podClaims, err := m.k8sClient.PodClaims(ctx, namespace)
+	if err != nil {
+		slog.Warn("failed to find mounted PVCs, assuming all are mounted", "namespace", namespace, "error", err)
+		return nil, nil
+	}
+	mounted := make(map[string]bool)
+	for _, claims := range podClaims {
+		for _, claim := range claims {
+			mounted[claim] = true
 		}
+	}
+	return mounted, coMountedGroups(namespace, podClaims)
+}
 
-		progress := 50
-		if state == "creating" {
-			progress = 25
+// planItem reads one PVC and its PV for the plan; placeItems then decides what
+// happens to it. mounted holds the claims of its namespace that pods mount, or
+// nil when they are unknown.
+func (m *Migrator) planItem(ctx context.Context, pvcName string, mounted map[string]bool) PVCPlanItem {
+	ns, shortName := ParsePVCName(pvcName)
+	item := PVCPlanItem{
+		Name:       pvcName,
+		Namespace:  ns,
+		PVCName:    shortName,
+		TargetZone: m.config.fixedZone(), // Empty until assigned when spreading across or picking zones
+	}
+	if class := m.config.StorageClassFor(pvcName); class != m.config.StorageClass {
+		item.StorageClass = class
+	}
+	if priority := m.config.PriorityFor(pvcName); priority != PriorityNormal {
+		item.Priority = priority
+	}
+	if w, ok := m.config.windowFor(pvcName); ok {
+		item.Window = w.String()
+	}
+
+	// Get PVC info from Kubernetes
+	info, err := m.pvcInfo(ctx, ns, shortName)
+	if err != nil && apierrors.IsNotFound(err) {
+		// An earlier run deleted the old PVC before it stopped
+		if pv := m.earlierStaticPV(ctx, pvcName); pv != nil {
+			item.Action = PlanActionMigrate
+			item.ResumeAt = StepCreatePVC
+			item.PVName = pv.Name
+			item.VolumeID = pv.VolumeID
+			item.Capacity = pv.Capacity
+			item.CapacityGi = pv.CapacityGi
+			item.Block = pv.VolumeMode == corev1.PersistentVolumeBlock
+			item.CurrentZone = pv.Zone
+			if pv.Zone != "" {
+				item.TargetZone = pv.Zone
+			}
+			item.Attached = mounted == nil || mounted[shortName]
+			return item
 		}
-		m.updateStatus(pvcName, StepWaitVolume, progress, nil)
+	}
+	if err != nil {
+		item.Action = PlanActionError
+		item.Reason = fmt.Sprintf("Failed to get PVC info: %v", err)
+		return item
+	}
 
-		select {
-		case <-ctx.Done():
-			m.updateStatus(pvcName, StepFailed, 0, ctx.Err())
-			return
-		case <-time.After(3 * time.Second):
+	item.PVName = info.PVName
+	item.VolumeID = info.VolumeID
+	item.Capacity = info.Capacity
+	item.CapacityGi = info.CapacityGi
+	item.ClaimPhase = string(info.ClaimPhase)
+	item.PVPhase = string(info.PVPhase)
+	item.PVMissing = info.PVMissing
+	item.Block = info.VolumeMode == corev1.PersistentVolumeBlock
+	item.Tags = m.pvcTags(ns, shortName, info.Annotations)
+
+	// Claims that no pod mounts can move without scaling anything down
+	item.Attached = mounted == nil || mounted[shortName]
+
+	// The static PV an earlier run left behind keeps its zone
+	if pv := m.earlierStaticPV(ctx, pvcName); pv != nil {
+		item.ResumeAt = StepCleanup
+		if pv.Zone != "" {
+			item.TargetZone = pv.Zone
 		}
 	}
+	return item
+}
 
-	// Step 6: Create PV
-	m.updateStatus(pvcName, StepCreatePV, 0, nil)
-	newPVName := shortName + "-static"
-	if err := m.k8sClient.CreateStaticPV(ctx, newPVName, newVolumeID, info.Capacity, m.config.StorageClass, m.config.TargetZone); err != nil {
-		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create PV: %w", err))
+// placeItems looks up the volumes of the items in batched DescribeVolumes calls
+// and, from their zone, decides whether each PVC moves and how its new volume is
+// encrypted given the account's defaults. Items that already failed, or resume
+// after their old PVC was deleted, are left as they are.
+func (m *Migrator) placeItems(ctx context.Context, items []PVCPlanItem, encryption *aws.EncryptionDefaults) {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Action != PlanActionError && item.ResumeAt != StepCreatePVC {
+			ids = append(ids, item.VolumeID)
+		}
+	}
+	if len(ids) == 0 {
 		return
 	}
+	volumes, err := m.awsClient.GetVolumesInfo(ctx, ids)
 
-	// Step 7: Cleanup
-	// We do cleanup AFTER creating the new PV to minimize the risk of data loss/orphaned volumes
-	// if the process crashes.
-	m.updateStatus(pvcName, StepCleanup, 0, nil)
-	if err := m.k8sClient.CleanupResources(ctx, namespace, shortName, info.PVName); err != nil {
-		// If cleanup fails, we still have the new PV created, but the old one might still exist.
-		// This is a partial failure but better than data loss.
-		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("cleanup: %w", err))
-		return
+	var moving []*PVCPlanItem
+	for i := range items {
+		item := &items[i]
+		if item.Action == PlanActionError || item.ResumeAt == StepCreatePVC {
+			continue
+		}
+		if err != nil {
+			item.Action = PlanActionError
+			item.Reason = fmt.Sprintf("Failed to get volume info: %v", err)
+			continue
+		}
+		volumeInfo, ok := volumes[item.VolumeID]
+		if !ok {
+			item.Action = PlanActionError
+			item.Reason = fmt.Sprintf("Failed to get volume info: volume not found: %s", item.VolumeID)
+			continue
+		}
+
+		item.CurrentZone = volumeInfo.AvailabilityZone
+		if volumeInfo.Encrypted {
+			item.SourceKMSKeyID = volumeInfo.KMSKeyID
+		}
+		item.KMSKeyID = newVolumeKey(m.config.KMSKeyID, item.SourceKMSKeyID, encryption)
+		if m.config.inTargetZone(volumeInfo.AvailabilityZone) {
+			item.Action = PlanActionSkip
+			item.Reason = "Already in target zone"
+			continue
+		}
+		item.Action = PlanActionMigrate
+		item.VolumeSpec = m.volumeSpec(volumeInfo)
+		moving = append(moving, item)
 	}
 
-	// Step 8: Create PVC
-	m.updateStatus(pvcName, StepCreatePVC, 0, nil)
-	if err := m.k8sClient.CreateBoundPVC(ctx, namespace, shortName, newPVName, info.Capacity, m.config.StorageClass); err != nil {
-		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("create PVC: %w", err))
-		return
+	m.parallel(len(moving), func(i int) {
+		item := moving[i]
+		if snap := m.stagedSnapshot(ctx, item.VolumeID, item.Namespace, item.PVCName); snap != nil {
+			item.StagedSnapshotID = snap.SnapshotID
+			item.StagedSnapshotTime = snap.StartTime
+		}
+	})
+}
+
+// parallel calls fn for every index below n, running up to MaxConcurrency calls
+// at once, and returns once they all have
+func (m *Migrator) parallel(n int, fn func(i int)) {
+	semaphore := make(chan struct{}, max(m.config.MaxConcurrency, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(i)
+		}()
 	}
+	wg.Wait()
+}
 
-	m.updateStatus(pvcName, StepDone, 100, nil)
+// addPVC adds a PVC found while planning to the run
+func (m *Migrator) addPVC(pvcName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.statuses[pvcName]; ok {
+		return
+	}
+	ns, name := ParsePVCName(pvcName)
+	s := &PVCStatus{Name: pvcName, Namespace: ns, PVCName: name, Step: StepPending}
+	m.touch(s)
+	m.statuses[pvcName] = s
+	m.config.PVCList = append(m.config.PVCList, pvcName)
 }
 
 // GeneratePlan creates a migration plan by fetching volume info for all PVCs
 func (m *Migrator) GeneratePlan(ctx context.Context) (*MigrationPlan, error) {
 	plan := &MigrationPlan{
-		Items:        make([]PVCPlanItem, 0, len(m.config.PVCList)),
-		TargetZone:   m.config.TargetZone,
+		Items:        make([]PVCPlanItem, len(m.config.PVCList)),
+		TargetZone:   m.config.Destination(),
 		StorageClass: m.config.StorageClass,
 		DryRun:       m.config.DryRun,
 		Namespaces:   m.config.Namespaces,
 		Concurrency:  m.config.MaxConcurrency,
+		KMSKeyID:     m.config.KMSKeyID,
+		CSIDriver:    m.config.CSIDriver,
+		FastRestore:  m.config.FastSnapshotRestore,
+		VolumeSpec:   m.volumeSpec(nil),
+		KeepVolume:   m.config.VolumeType == "",
 	}
+	plan.Encryption = m.encryptionDefaults(ctx)
+	plan.ZoneIDs = m.zoneIDs(ctx)
 
+	var namespaces []string
+	seen := make(map[string]bool)
 	for _, pvcName := range m.config.PVCList {
-		ns, shortName := ParsePVCName(pvcName)
-		item := PVCPlanItem{
-			Name:       pvcName,
-			Namespace:  ns,
-			PVCName:    shortName,
-			TargetZone: m.config.TargetZone,
-		}
-
-		// Get PVC info from Kubernetes
-		info, err := m.k8sClient.GetPVCInfo(ctx, ns, shortName)
-		if err != nil {
-			item.Action = PlanActionError
-			item.Reason = fmt.Sprintf("Failed to get PVC info: %v", err)
-			plan.Items = append(plan.Items, item)
-			continue
+		if ns, _ := ParsePVCName(pvcName); !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
 		}
+	}
+	mountedByNS := make([]map[string]bool, len(namespaces))
+	groupsByNS := make([][]coMountedGroup, len(namespaces))
+	m.parallel(len(namespaces), func(i int) {
+		mountedByNS[i], groupsByNS[i] = m.mountedClaims(ctx, namespaces[i])
+	})
+	mounted := make(map[string]map[string]bool, len(namespaces))
+	var groups []coMountedGroup
+	for i, ns := range namespaces {
+		mounted[ns] = mountedByNS[i]
+		groups = append(groups, groupsByNS[i]...)
+	}
 
-		item.PVName = info.PVName
-		item.VolumeID = info.VolumeID
-		item.Capacity = info.Capacity
+	m.parallel(len(m.config.PVCList), func(i int) {
+		pvcName := m.config.PVCList[i]
+		ns, _ := ParsePVCName(pvcName)
+		plan.Items[i] = m.planItem(ctx, pvcName, mounted[ns])
+	})
+	m.placeItems(ctx, plan.Items, plan.Encryption)
 
-		// Get volume info from AWS
-		volumeInfo, err := m.awsClient.GetVolumeInfo(ctx, info.VolumeID)
-		if err != nil {
-			item.Action = PlanActionError
-			item.Reason = fmt.Sprintf("Failed to get volume info: %v", err)
-			plan.Items = append(plan.Items, item)
-			continue
+	if m.config.IncludeCoMounted {
+		var siblings []PVCPlanItem
+		for _, sibling := range m.coMountedSiblings(ctx, plan.Items, groups) {
+			m.addPVC(sibling.name)
+			ns, _ := ParsePVCName(sibling.name)
+			item := m.planItem(ctx, sibling.name, mounted[ns])
+			item.CoMountedWith = sibling.pod
+			siblings = append(siblings, item)
 		}
+		m.placeItems(ctx, siblings, plan.Encryption)
+		plan.Items = append(plan.Items, siblings...)
+	}
 
-		item.CurrentZone = volumeInfo.AvailabilityZone
+	// Listed in the order the run starts them
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		return PriorityRank(plan.Items[i].Priority) < PriorityRank(plan.Items[j].Priority)
+	})
 
-		// Determine action
-		if volumeInfo.AvailabilityZone == m.config.TargetZone {
-			item.Action = PlanActionSkip
-			item.Reason = "Already in target zone"
-		} else {
-			item.Action = PlanActionMigrate
+	switch {
+	case len(m.config.TargetZones) > 0:
+		assignTargetZones(plan.Items, m.config.TargetZones)
+	case m.config.autoZone():
+		plan.AutoZones = m.chooseZones(ctx, plan.Items)
+		m.config.autoZones = chosenZones(plan.AutoZones)
+		plan.TargetZone = m.config.Destination()
+	}
+	// Pods mounting several PVCs need them all in the same zone
+	blocked := m.resolveCoMounted(ctx, plan.Items, groups)
+	// Moved PVCs are of no use where nothing can mount them
+	var failed map[string]string
+	plan.NodeIssues, failed = m.checkZoneNodes(ctx, plan.Items)
+	for name, reason := range failed {
+		blocked[name] = reason
+	}
+	// EC2 would only reject a volume it cannot create once its snapshot is taken
+	failed = checkVolumeSpecs(plan.Items)
+	for name, reason := range failed {
+		blocked[name] = reason
+	}
+	// Fail before the first snapshot rather than on every PVC's first call
+	if m.config.CheckPermissions {
+		plan.MissingPermissions, failed = m.checkPermissions(ctx, plan.Items)
+		for name, reason := range failed {
+			blocked[name] = reason
+		}
+	}
+	// Fail before the first snapshot rather than when a quota is hit midway
+	if m.config.CheckQuotas {
+		plan.Quotas, failed = m.checkQuotas(ctx, plan.Items)
+		for name, reason := range failed {
+			blocked[name] = reason
 		}
+	}
+	if m.config.SimulateScheduling {
+		plan.Schedulability = m.simulateScheduling(ctx, plan.Items)
+	}
 
-		plan.Items = append(plan.Items, item)
+	// The run moves each PVC to the zone shown in the plan
+	m.mu.Lock()
+	m.blocked = blocked
+	for _, item := range plan.Items {
+		if s, ok := m.statuses[item.Name]; ok && s.TargetZone != item.TargetZone {
+			s.TargetZone = item.TargetZone
+			m.touch(s)
+		}
 	}
+	m.mu.Unlock()
 
 	return plan, nil
 }
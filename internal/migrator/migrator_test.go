@@ -1,14 +1,263 @@
 package migrator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	goruntime "runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
 )
 
+// fakeEC2 serves DescribeVolumes from a volume ID to zone map. Snapshots it
+// creates are completed straight away, as large as the 10Gi test claims, and
+// recorded with their input. Staged snapshots are listed by volume ID with their
+// start time, and snapshots of earlier runs by their client token. Volumes of
+// deleted PVs are found by their PV name tag. Volumes are only created from
+// the snapshots in created. Every DescribeVolumes call is counted. Calls made
+// with DryRun are allowed unless their action is in denied.
+type fakeEC2 struct {
+	zones     map[string]string
+	staged    map[string]time.Time
+	pvVolumes map[string]string
+	kmsKeys   map[string]string // Volume ID -> KMS key of encrypted volumes
+	earlier   map[string]string // Client token -> snapshot left by an earlier run
+	migrated  map[string]string // Volume ID -> snapshot an earlier run with another migration ID took
+	volumes   map[string]string // Snapshot ID -> volume an earlier run created from it
+	attached  map[string]bool   // Volumes in use
+	errored   map[string]bool   // Volumes in the error state
+	created   map[string]string // Snapshot ID -> volume CreateVolume creates from it
+	denied    map[string]bool   // Actions such as "ec2:CreateTags" dry runs are denied
+
+	// perf holds the VolumeType, Iops and Throughput of the volumes that are not
+	// baseline gp3, by volume ID
+	perf map[string]ec2types.Volume
+
+	mu              sync.Mutex
+	snapshots       []*ec2.CreateSnapshotInput
+	newVolumes      []*ec2.CreateVolumeInput
+	describeVolumes int
+}
+
+// dryRun answers a call made with DryRun; tagging on create also needs ec2:CreateTags
+func (f *fakeEC2) dryRun(action string, tags []ec2types.TagSpecification) error {
+	if f.denied[action] || (len(tags) > 0 && f.denied["ec2:CreateTags"]) {
+		return &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+	}
+	return &smithy.GenericAPIError{Code: "DryRunOperation"}
+}
+
+func (f *fakeEC2) CreateSnapshot(_ context.Context, params *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	if awssdk.ToBool(params.DryRun) {
+		return nil, f.dryRun("ec2:CreateSnapshot", params.TagSpecifications)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snapshots = append(f.snapshots, params)
+	return &ec2.CreateSnapshotOutput{SnapshotId: awssdk.String("snap-" + awssdk.ToString(params.VolumeId))}, nil
+}
+
+func (f *fakeEC2) DescribeSnapshots(_ context.Context, params *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	if awssdk.ToBool(params.DryRun) {
+		return nil, f.dryRun("ec2:DescribeSnapshots", nil)
+	}
+	out := &ec2.DescribeSnapshotsOutput{}
+	volumeFilter := func() string {
+		for _, filter := range params.Filters {
+			if awssdk.ToString(filter.Name) == "volume-id" {
+				return filter.Values[0]
+			}
+		}
+		return ""
+	}
+	for _, filter := range params.Filters {
+		switch awssdk.ToString(filter.Name) {
+		case "tag:" + aws.TagClientToken:
+			if id, ok := f.earlier[filter.Values[0]]; ok {
+				out.Snapshots = append(out.Snapshots, ec2types.Snapshot{SnapshotId: awssdk.String(id), State: ec2types.SnapshotStatePending})
+			}
+			return out, nil
+		case "tag:" + aws.TagMigratedPVC:
+			if id, ok := f.migrated[volumeFilter()]; ok {
+				out.Snapshots = append(out.Snapshots, ec2types.Snapshot{SnapshotId: awssdk.String(id), State: ec2types.SnapshotStateCompleted})
+			}
+			return out, nil
+		}
+	}
+	for _, filter := range params.Filters {
+		if awssdk.ToString(filter.Name) != "volume-id" {
+			continue
+		}
+		for _, volumeID := range filter.Values {
+			if started, ok := f.staged[volumeID]; ok {
+				out.Snapshots = append(out.Snapshots, ec2types.Snapshot{
+					SnapshotId: awssdk.String("snap-staged-" + volumeID),
+					StartTime:  awssdk.Time(started),
+					State:      ec2types.SnapshotStateCompleted,
+				})
+			}
+		}
+	}
+	for _, id := range params.SnapshotIds {
+		volumeID := strings.TrimPrefix(strings.TrimPrefix(id, "snap-"), "staged-")
+		out.Snapshots = append(out.Snapshots, ec2types.Snapshot{
+			SnapshotId: awssdk.String(id),
+			VolumeId:   awssdk.String(volumeID),
+			VolumeSize: awssdk.Int32(10),
+			State:      ec2types.SnapshotStateCompleted,
+			Progress:   awssdk.String("100%"),
+		})
+	}
+	return out, nil
+}
+
+// snapshotTags returns the tags of every snapshot created so far, by volume ID
+func (f *fakeEC2) snapshotTags() map[string]map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make(map[string]map[string]string)
+	for _, in := range f.snapshots {
+		tags := make(map[string]string)
+		for _, tag := range in.TagSpecifications[0].Tags {
+			tags[awssdk.ToString(tag.Key)] = awssdk.ToString(tag.Value)
+		}
+		result[awssdk.ToString(in.VolumeId)] = tags
+	}
+	return result
+}
+
+func (f *fakeEC2) CreateVolume(_ context.Context, params *ec2.CreateVolumeInput, _ ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	if awssdk.ToBool(params.DryRun) {
+		return nil, f.dryRun("ec2:CreateVolume", params.TagSpecifications)
+	}
+	if id, ok := f.created[awssdk.ToString(params.SnapshotId)]; ok {
+		f.mu.Lock()
+		f.newVolumes = append(f.newVolumes, params)
+		f.mu.Unlock()
+		return &ec2.CreateVolumeOutput{VolumeId: awssdk.String(id), State: ec2types.VolumeStateCreating}, nil
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeEC2) DescribeVolumes(_ context.Context, params *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	if awssdk.ToBool(params.DryRun) {
+		return nil, f.dryRun("ec2:DescribeVolumes", nil)
+	}
+	f.mu.Lock()
+	f.describeVolumes++
+	f.mu.Unlock()
+
+	out := &ec2.DescribeVolumesOutput{}
+	ids := params.VolumeIds
+	for _, filter := range params.Filters {
+		switch awssdk.ToString(filter.Name) {
+		case "volume-id":
+			ids = append(ids, filter.Values...)
+		case "tag:kubernetes.io/created-for/pv/name":
+			for _, pvName := range filter.Values {
+				if id, ok := f.pvVolumes[pvName]; ok {
+					ids = append(ids, id)
+				}
+			}
+		case "snapshot-id":
+			if id, ok := f.volumes[filter.Values[0]]; ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	for _, id := range ids {
+		if zone, ok := f.zones[id]; ok {
+			vol := ec2types.Volume{VolumeId: awssdk.String(id), AvailabilityZone: awssdk.String(zone), State: ec2types.VolumeStateAvailable, Size: awssdk.Int32(10)}
+			if f.attached[id] {
+				vol.State = ec2types.VolumeStateInUse
+			}
+			if f.errored[id] {
+				vol.State = ec2types.VolumeStateError
+			}
+			if key, ok := f.kmsKeys[id]; ok {
+				vol.Encrypted, vol.KmsKeyId = awssdk.Bool(true), awssdk.String(key)
+			}
+			if perf, ok := f.perf[id]; ok {
+				vol.VolumeType, vol.Iops, vol.Throughput = perf.VolumeType, perf.Iops, perf.Throughput
+			}
+			out.Volumes = append(out.Volumes, vol)
+		}
+	}
+	return out, nil
+}
+
+// boundClaim returns a 10Gi PVC bound to a CSI PV backed by volumeID
+func boundClaim(namespace, name, volumeID string) []runtime.Object {
+	pvName := "pv-" + name
+	return []runtime.Object{
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName: pvName,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvName},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: volumeID},
+				},
+			},
+		},
+	}
+}
+
+// mountingPod returns a running pod that mounts the claim
+func mountingPod(namespace, name, claim string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// newFakeMigrator returns a Migrator backed by a fake clientset and EC2 API
+func newFakeMigrator(cfg *Config, ec2API *fakeEC2, objects ...runtime.Object) *Migrator {
+	return New(cfg, k8s.NewClientWithInterface(bindingClientset(objects...), nil), aws.NewEC2ClientWithInterface(ec2API))
+}
+
+// bindingClientset returns a fake clientset holding the objects that marks the
+// claims it creates Bound, as the PV controller would
+func bindingClientset(objects ...runtime.Object) *fake.Clientset {
+	clientset := fake.NewSimpleClientset(objects...) //nolint:staticcheck // NewClientset requires apply configurations
+	clientset.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		action.(k8stesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimBound
+		return false, nil, nil
+	})
+	return clientset
+}
+
 func TestParsePVCName(t *testing.T) {
 	t.Parallel()
 
@@ -143,6 +392,41 @@ func TestGetStatuses(t *testing.T) {
 	assert.Len(t, m.GetStatuses(), 2)
 }
 
+func TestStatusesSince(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		PVCList: []string{"ns/pvc-2", "ns/pvc-1", "ns/pvc-3"},
+	}
+	m := New(config, nil, nil)
+
+	all, seq := m.StatusesSince(0)
+	require.Len(t, all, 3)
+	assert.Equal(t, "ns/pvc-1", all[0].Name, "sorted by name")
+
+	changed, next := m.StatusesSince(seq)
+	assert.Empty(t, changed)
+	assert.Equal(t, seq, next)
+
+	m.updateStatus("ns/pvc-3", StepSnapshot, 0, nil)
+	m.updateStatus("ns/pvc-2", StepWaitSnapshot, 10, nil)
+	m.updateStatus("ns/pvc-2", StepWaitSnapshot, 50, nil)
+	m.updateStatus("ns/pvc-2", StepWaitSnapshot, 50, nil) // unchanged
+
+	changed, next = m.StatusesSince(seq)
+	require.Len(t, changed, 2, "changes to one PVC coalesce")
+	assert.Equal(t, "ns/pvc-2", changed[0].Name)
+	assert.Equal(t, 50, changed[0].Progress)
+	assert.Equal(t, "ns/pvc-3", changed[1].Name)
+	assert.Equal(t, seq+3, next)
+	assert.Equal(t, next, changed[0].Seq)
+
+	// Returned statuses are copies
+	changed[0].Progress = 0
+	latest, _ := m.StatusesSince(0)
+	assert.Equal(t, 50, latest[1].Progress)
+}
+
 func TestIsDone(t *testing.T) {
 	t.Parallel()
 
@@ -339,3 +623,398 @@ func TestMigrator_ConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestGeneratePlan_Attached(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("db", "scratch", "vol-1")...)
+	objects = append(objects, boundClaim("logs", "archive", "vol-2")...)
+	objects = append(objects, boundClaim("web", "static", "vol-3")...)
+	objects = append(objects, mountingPod("db", "postgres-0", "data-0"), mountingPod("web", "nginx-0", "static"))
+
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0", "db/scratch", "logs/archive", "web/static"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b", "vol-2": "eu-west-1b", "vol-3": "eu-west-1a"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 4)
+
+	attached := make(map[string]bool)
+	for _, item := range plan.Items {
+		attached[item.Name] = item.Attached
+	}
+	assert.Equal(t, map[string]bool{"db/data-0": true, "db/scratch": false, "logs/archive": false, "web/static": true}, attached)
+	assert.Equal(t, []string{"db"}, plan.ScaleNamespaces(), "skipped and unmounted PVCs need no scaling")
+	assert.Equal(t, []string{"db", "logs"}, plan.MigrateNamespaces())
+	assert.Equal(t, 3, plan.Pending(), "web/static is already in the target zone")
+}
+
+func TestGeneratePlan_BatchesVolumeLookups(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	zones := make(map[string]string)
+	cfg := &Config{TargetZone: "eu-west-1a"}
+	for i := range 5 {
+		name := fmt.Sprintf("data-%d", i)
+		objects = append(objects, boundClaim("db", name, "vol-"+name)...)
+		zones["vol-"+name] = "eu-west-1b"
+		cfg.PVCList = append(cfg.PVCList, "db/"+name)
+	}
+	delete(zones, "vol-data-3")
+	ec2API := &fakeEC2{zones: zones}
+
+	plan, err := newFakeMigrator(cfg, ec2API, objects...).GeneratePlan(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, ec2API.describeVolumes, "one call for the whole plan")
+	require.Len(t, plan.Items, 5)
+	for _, item := range plan.Items {
+		if item.Name == "db/data-3" {
+			assert.Equal(t, PlanActionError, item.Action)
+			assert.Contains(t, item.Reason, "volume not found: vol-data-3")
+			continue
+		}
+		assert.Equal(t, PlanActionMigrate, item.Action, item.Name)
+		assert.Equal(t, "eu-west-1b", item.CurrentZone)
+	}
+}
+
+func TestMigrator_Parallel(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{MaxConcurrency: 3}, nil, nil)
+	var mu sync.Mutex
+	running, most := 0, 0
+	done := make([]bool, 20)
+	m.parallel(len(done), func(i int) {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+
+		time.Sleep(2 * time.Millisecond) // Overlap with the calls started next
+		mu.Lock()
+		running--
+		done[i] = true
+		mu.Unlock()
+	})
+
+	assert.NotContains(t, done, false, "every index is processed")
+	assert.LessOrEqual(t, most, 3)
+	assert.Greater(t, most, 1, "calls run at the same time")
+}
+
+func TestGeneratePlan_StorageClassOverrides(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("web", "static", "vol-1")...)
+
+	m := newFakeMigrator(&Config{
+		PVCList:        []string{"db/data-0", "web/static"},
+		TargetZone:     "eu-west-1a",
+		StorageClass:   "gp3",
+		StorageClasses: map[string]string{"db/data-0": "io2"},
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 2)
+
+	assert.Equal(t, "io2", plan.Items[0].StorageClass)
+	assert.Empty(t, plan.Items[1].StorageClass, "uses the plan's storage class")
+	assert.Equal(t, "gp3", plan.StorageClass)
+}
+
+func TestGeneratePlan_Priorities(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("archive", "cold", "vol-0")...)
+	objects = append(objects, boundClaim("web", "static", "vol-1")...)
+	objects = append(objects, boundClaim("db", "data-0", "vol-2")...)
+
+	m := newFakeMigrator(&Config{
+		PVCList:             []string{"archive/cold", "web/static", "db/data-0"},
+		TargetZone:          "eu-west-1a",
+		Priorities:          map[string]string{"archive/cold": PriorityLow},
+		NamespacePriorities: map[string]string{"db": PriorityHigh},
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b", "vol-2": "eu-west-1b"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 3)
+
+	assert.Equal(t, "db/data-0", plan.Items[0].Name, "listed in the order the run starts them")
+	assert.Equal(t, PriorityHigh, plan.Items[0].Priority)
+	assert.Empty(t, plan.Items[1].Priority, "normal priority")
+	assert.Equal(t, PriorityLow, plan.Items[2].Priority)
+}
+
+func TestGeneratePlan_RebuiltClaims(t *testing.T) {
+	t.Parallel()
+
+	lost := boundClaim("db", "data-0", "vol-0")
+	lost[0].(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimLost
+	released := boundClaim("db", "data-1", "vol-1")
+	released[1].(*corev1.PersistentVolume).Status.Phase = corev1.VolumeReleased
+
+	var objects []runtime.Object
+	objects = append(objects, lost[0]) // its PV was deleted
+	objects = append(objects, released...)
+	objects = append(objects, boundClaim("db", "data-2", "vol-2")...)
+
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0", "db/data-1", "db/data-2"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{
+		zones:     map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b", "vol-2": "eu-west-1b"},
+		pvVolumes: map[string]string{"pv-data-0": "vol-0"},
+	}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 3)
+
+	assert.Equal(t, PlanActionMigrate, plan.Items[0].Action, plan.Items[0].Reason)
+	assert.Equal(t, "vol-0", plan.Items[0].VolumeID, "adopted by its PV name tag")
+	assert.True(t, plan.Items[0].PVMissing)
+	assert.True(t, plan.Items[0].Rebuilt())
+
+	assert.Equal(t, "Released", plan.Items[1].PVPhase)
+	assert.False(t, plan.Items[1].PVMissing)
+	assert.True(t, plan.Items[1].Rebuilt())
+
+	assert.False(t, plan.Items[2].Rebuilt())
+}
+
+func TestGeneratePlan_DeletedPVWithoutVolume(t *testing.T) {
+	t.Parallel()
+
+	claim := boundClaim("db", "data-0", "vol-0")[0]
+	claim.(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimLost
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}, claim)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, PlanActionError, plan.Items[0].Action)
+	assert.Contains(t, plan.Items[0].Reason, "volume was not found")
+}
+
+func TestGeneratePlan_BoundClaimWithoutPV(t *testing.T) {
+	t.Parallel()
+
+	claim := boundClaim("db", "data-0", "vol-0")[0]
+	claim.(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimBound
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}, claim)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, PlanActionError, plan.Items[0].Action)
+	assert.Contains(t, plan.Items[0].Reason, "failed to get PV")
+	assert.False(t, plan.Items[0].PVMissing)
+}
+
+func TestRunEach_Paused(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/a", "ns/b"}, MaxConcurrency: 2}, nil, nil)
+	m.Pause()
+	assert.True(t, m.Paused())
+
+	started := make(chan string, 2)
+	finished := make(chan struct{})
+	go func() {
+		m.runEach(context.Background(), "test", m.config.PVCList, func(_ context.Context, name string) { started <- name })
+		close(finished)
+	}()
+
+	select {
+	case name := <-started:
+		t.Fatalf("%s started while paused", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.Resume()
+	assert.False(t, m.Paused())
+	<-finished
+	assert.Len(t, started, 2)
+}
+
+func TestRunEach_CancelledWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/a"}, MaxConcurrency: 1}, nil, nil)
+	m.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var errs []error
+	m.runEach(ctx, "test", m.config.PVCList, func(ctx context.Context, _ string) { errs = append(errs, ctx.Err()) })
+
+	require.Len(t, errs, 1, "the PVC is handed a cancelled context instead of blocking")
+	assert.ErrorIs(t, errs[0], context.Canceled)
+	assert.True(t, m.IsDone())
+}
+
+// Not parallel: it counts the goroutines of the whole test binary
+func TestRunEach_BoundedGoroutines(t *testing.T) {
+	pvcs := make([]string, 1000)
+	for i := range pvcs {
+		pvcs[i] = fmt.Sprintf("ns/pvc-%d", i)
+	}
+	m := New(&Config{PVCList: pvcs, MaxConcurrency: 2}, nil, nil)
+
+	started := make(chan struct{}, len(pvcs))
+	release := make(chan struct{})
+	before := goruntime.NumGoroutine()
+	go m.runEach(context.Background(), "test run", m.config.PVCList, func(context.Context, string) {
+		started <- struct{}{}
+		<-release
+	})
+	<-started
+	<-started
+
+	assert.Less(t, goruntime.NumGoroutine()-before, 10, "only running PVCs have a goroutine")
+	close(release)
+	assert.Eventually(t, m.IsDone, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, started, len(pvcs)-2)
+}
+
+func TestRunSnapshots(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+	objects = append(objects, mountingPod("db", "postgres-0", "data-0"))
+	objects[0].(*corev1.PersistentVolumeClaim).Annotations = map[string]string{
+		"pv-zone-migrator.io/tag-costcenter":       "42",
+		"pv-zone-migrator.io/tag-" + aws.TagStaged: "false",
+	}
+	ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1a"}}
+	m := newFakeMigrator(&Config{
+		PVCList:             []string{"db/data-0", "db/data-1"},
+		TargetZone:          "eu-west-1a",
+		MaxConcurrency:      2,
+		TagAnnotationPrefix: "pv-zone-migrator.io/tag-",
+	}, ec2API, objects...)
+
+	m.RunSnapshots(context.Background())
+
+	statuses := m.GetStatuses()
+	assert.True(t, m.IsDone())
+	assert.Equal(t, StepDone, statuses["db/data-0"].Step)
+	assert.Equal(t, "snap-vol-0", statuses["db/data-0"].SnapshotID)
+	assert.Empty(t, statuses["db/data-0"].NewVolumeID, "no volume is created")
+	assert.Equal(t, StepSkipped, statuses["db/data-1"].Step)
+
+	tags := ec2API.snapshotTags()
+	require.Len(t, tags, 1)
+	assert.Equal(t, "true", tags["vol-0"][aws.TagStaged], "the tool's own tags win")
+	assert.Equal(t, "db/data-0", tags["vol-0"][aws.TagPVC])
+	assert.Equal(t, "42", tags["vol-0"]["costcenter"])
+}
+
+func TestGeneratePlan_StagedSnapshots(t *testing.T) {
+	t.Parallel()
+
+	staged := map[string]time.Time{
+		"vol-0": time.Now().Add(-time.Hour),
+		"vol-1": time.Now().Add(-48 * time.Hour),
+	}
+	tests := []struct {
+		name         string
+		maxAge       time.Duration
+		maxStaleness time.Duration
+		want         map[string]string
+	}{
+		{
+			name:   "disabled",
+			maxAge: 0,
+			want:   map[string]string{"db/data-0": "", "db/data-1": "", "db/data-2": ""},
+		},
+		{
+			name:   "adopts fresh snapshots only",
+			maxAge: 24 * time.Hour,
+			want:   map[string]string{"db/data-0": "snap-staged-vol-0", "db/data-1": "", "db/data-2": ""},
+		},
+		{
+			name:         "staleness without write metrics counts up to now",
+			maxStaleness: 2 * time.Hour,
+			want:         map[string]string{"db/data-0": "snap-staged-vol-0", "db/data-1": "", "db/data-2": ""},
+		},
+		{
+			name:         "stale snapshots are not adopted",
+			maxAge:       72 * time.Hour,
+			maxStaleness: 30 * time.Minute,
+			want:         map[string]string{"db/data-0": "", "db/data-1": "", "db/data-2": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var objects []runtime.Object
+			objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+			objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+			objects = append(objects, boundClaim("db", "data-2", "vol-2")...)
+			m := newFakeMigrator(&Config{
+				PVCList:              []string{"db/data-0", "db/data-1", "db/data-2"},
+				TargetZone:           "eu-west-1a",
+				StagedSnapshotMaxAge: tt.maxAge,
+				MaxSnapshotStaleness: tt.maxStaleness,
+			}, &fakeEC2{
+				zones:  map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b", "vol-2": "eu-west-1b"},
+				staged: staged,
+			}, objects...)
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+
+			got := make(map[string]string)
+			for _, item := range plan.Items {
+				got[item.Name] = item.StagedSnapshotID
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigratePVC_ClaimNotBound(t *testing.T) {
+	t.Parallel()
+
+	// Nothing binds the new claim, as with a storage class its PV does not have
+	clientset := fake.NewSimpleClientset(boundClaim("shop", "data", "vol-old")...) //nolint:staticcheck // NewClientset requires apply configurations
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+		BindTimeout:    time.Nanosecond,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-new"},
+	}))
+	m.Run(context.Background())
+
+	status := m.GetStatuses()["shop/data"]
+	assert.Equal(t, StepFailed, status.Step)
+	assert.Equal(t, StepCreatePVC, status.FailedStep)
+	require.ErrorContains(t, status.Error, "wait for PVC to bind: PVC shop/data is still Pending")
+}
@@ -0,0 +1,48 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// Outcomes of the mount check of a PVC, see Config.VerifyMount
+const (
+	MountCheckPassed = "passed"
+	MountCheckFailed = "failed"
+)
+
+// mountCheckTimeout is how long the mount check pod may take, including pulling
+// its image and attaching the volume
+const mountCheckTimeout = 3 * time.Minute
+
+// verifyMount runs the mount check of a PVC whose claim was switched over to a
+// volume in zone and records its outcome. A failed check does not fail the PVC,
+// as its data is in place, but is reported as a warning.
+func (m *Migrator) verifyMount(ctx context.Context, pvcName, zone string) {
+	namespace, shortName := ParsePVCName(pvcName)
+	err := m.k8sClient.RunMountCheck(ctx, namespace, shortName, zone, m.config.CheckImage, mountCheckTimeout)
+
+	m.mu.Lock()
+	status := m.statuses[pvcName]
+	status.MountCheck = MountCheckPassed
+	status.MountCheckError = err
+	if err != nil {
+		status.MountCheck = MountCheckFailed
+	}
+	m.touch(status)
+	m.mu.Unlock()
+
+	if err != nil {
+		m.AddWarning(Warning{
+			PVC:     pvcName,
+			Message: i18n.T("warn.mount_check_failed", err),
+			Action:  i18n.T("warn.mount_check_action") + fmt.Sprintf("\nkubectl describe pvc %s -n %s", shortName, namespace),
+		})
+		return
+	}
+	slog.Info("mount check passed", "pvc", pvcName, "zone", zone)
+}
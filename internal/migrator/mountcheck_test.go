@@ -0,0 +1,69 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestMigratePVC_VerifyMount(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		phase        corev1.PodPhase
+		want         string
+		wantWarnings int
+	}{
+		{name: "passed", phase: corev1.PodSucceeded, want: MountCheckPassed},
+		{name: "failed", phase: corev1.PodFailed, want: MountCheckFailed, wantWarnings: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+			var zone string
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+				zone = pod.Spec.NodeSelector[corev1.LabelTopologyZone]
+				pod.Status.Phase = tc.phase
+				pod.Status.Message = "volume is read-only"
+				return false, nil, nil
+			})
+			m := New(&Config{
+				PVCList:        []string{"shop/data"},
+				TargetZone:     "eu-west-1a",
+				MaxConcurrency: 1,
+				StepRetry:      RetryPolicy{MaxAttempts: 1},
+				VerifyMount:    true,
+			}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{
+				zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+				created: map[string]string{"snap-vol-old": "vol-new"},
+			}))
+			m.Run(context.Background())
+
+			status := m.GetStatuses()["shop/data"]
+			require.NoError(t, status.Error)
+			assert.Equal(t, StepDone, status.Step, "a failed check does not fail the PVC")
+			assert.Equal(t, tc.want, status.MountCheck)
+			assert.Equal(t, "eu-west-1a", zone)
+			warnings := m.Warnings()
+			require.Len(t, warnings, tc.wantWarnings)
+			if tc.wantWarnings > 0 {
+				assert.Equal(t, "shop/data", warnings[0].PVC)
+				require.ErrorContains(t, status.MountCheckError, "volume is read-only")
+				assert.Contains(t, warnings[0].Action, "kubectl describe pvc data -n shop")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// completedAtFormat is the layout of the completed-at label; label values may not contain ':'
+const completedAtFormat = "20060102T150405Z"
+
+// completedNamespaces returns the zone the PVCs of each namespace ended up in, for
+// the namespaces whose PVCs were all migrated or already in their target zone. The
+// zone is empty when they ended up in different zones.
+func (m *Migrator) completedNamespaces() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	zones := make(map[string]string)
+	incomplete := make(map[string]bool)
+	for _, s := range m.statuses {
+		var zone string
+		switch s.Step {
+		case StepDone:
+			zone = s.TargetZone
+			if zone == "" {
+				zone = m.config.TargetZone
+			}
+		case StepSkipped:
+			zone = s.CurrentZone
+		case StepPending, StepGetInfo, StepSnapshot, StepWaitSnapshot, StepCreateVolume,
+			StepWaitVolume, StepCleanup, StepCreatePV, StepCreatePVC, StepFailed:
+			incomplete[s.Namespace] = true
+			continue
+		}
+		if prev, ok := zones[s.Namespace]; ok && prev != zone {
+			zone = ""
+		}
+		zones[s.Namespace] = zone
+	}
+	for ns := range incomplete {
+		delete(zones, ns)
+	}
+	return zones
+}
+
+// LabelCompletedNamespaces labels every namespace whose PVCs were all migrated or
+// already in their target zone, once each of them is found Bound again. Namespaces
+// get the completed-at label, and the zone label when their PVCs share one zone.
+// It returns the namespaces labelled; a dry run, or one that output manifests,
+// labels none.
+func (m *Migrator) LabelCompletedNamespaces(ctx context.Context, at time.Time) ([]string, error) {
+	if m.config.DryRun || m.config.OutputManifests != "" {
+		return nil, nil
+	}
+
+	zones := m.completedNamespaces()
+	namespaces := make([]string, 0, len(zones))
+	for ns := range zones {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var labelled []string
+	var errs []error
+	for _, ns := range namespaces {
+		if err := m.verifyBound(ctx, ns); err != nil {
+			slog.Warn("namespace not labelled", "namespace", ns, "error", err)
+			errs = append(errs, fmt.Errorf("namespace %s: %w", ns, err))
+			continue
+		}
+		labels := map[string]string{k8s.LabelCompletedAt: at.UTC().Format(completedAtFormat)}
+		if zone := zones[ns]; zone != "" {
+			labels[k8s.LabelZone] = zone
+		}
+		if err := m.k8sClient.LabelNamespace(ctx, ns, labels); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		labelled = append(labelled, ns)
+	}
+	return labelled, errors.Join(errs...)
+}
+
+// verifyBound checks that every PVC of the namespace in the migration is Bound to a Bound PV
+func (m *Migrator) verifyBound(ctx context.Context, namespace string) error {
+	m.mu.RLock()
+	var pvcs []string
+	for _, s := range m.statuses {
+		if s.Namespace == namespace {
+			pvcs = append(pvcs, s.PVCName)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(pvcs)
+
+	for _, pvc := range pvcs {
+		info, err := m.k8sClient.GetPVCInfo(ctx, namespace, pvc)
+		if err != nil {
+			return err
+		}
+		if info.ClaimPhase != corev1.ClaimBound || info.PVPhase != corev1.VolumeBound {
+			return fmt.Errorf("PVC %s is not Bound yet (PVC phase %q, PV phase %q)", pvc, info.ClaimPhase, info.PVPhase)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// healthyClaim returns a PVC and PV like boundClaim, both in the Bound phase
+func healthyClaim(namespace, name, volumeID string) []runtime.Object {
+	objects := boundClaim(namespace, name, volumeID)
+	objects[0].(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimBound
+	objects[1].(*corev1.PersistentVolume).Status.Phase = corev1.VolumeBound
+	return objects
+}
+
+func TestLabelCompletedNamespaces(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "db"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cache"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "queue"}},
+	}
+	objects = append(objects, healthyClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, healthyClaim("db", "data-1", "vol-1")...)
+	objects = append(objects, healthyClaim("web", "static", "vol-2")...)
+	objects = append(objects, healthyClaim("cache", "redis", "vol-3")...)
+	objects = append(objects, boundClaim("queue", "log", "vol-4")...) // Not Bound yet
+
+	clientset := bindingClientset(objects...)
+	m := New(&Config{
+		PVCList:    []string{"db/data-0", "db/data-1", "web/static", "cache/redis", "queue/log"},
+		TargetZone: "eu-west-1a",
+	}, k8s.NewClientWithInterface(clientset, nil), nil)
+	m.statuses["db/data-0"].Step = StepDone
+	m.statuses["db/data-1"].Step = StepSkipped
+	m.statuses["db/data-1"].CurrentZone = "eu-west-1a"
+	m.statuses["web/static"].Step = StepFailed
+	m.statuses["cache/redis"].Step = StepDone
+	m.statuses["cache/redis"].TargetZone = "eu-west-1c"
+	m.statuses["queue/log"].Step = StepDone
+
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	labelled, err := m.LabelCompletedNamespaces(context.Background(), at)
+	require.ErrorContains(t, err, `namespace queue: PVC log is not Bound yet (PVC phase "", PV phase "")`)
+	assert.Equal(t, []string{"cache", "db"}, labelled)
+
+	labels := func(name string) map[string]string {
+		ns, err := clientset.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return ns.Labels
+	}
+	assert.Equal(t, map[string]string{k8s.LabelZone: "eu-west-1a", k8s.LabelCompletedAt: "20261016T120000Z"}, labels("db"))
+	assert.Equal(t, map[string]string{k8s.LabelZone: "eu-west-1c", k8s.LabelCompletedAt: "20261016T120000Z"}, labels("cache"))
+	assert.Empty(t, labels("web"), "a PVC failed")
+	assert.Empty(t, labels("queue"), "not verified")
+}
+
+func TestLabelCompletedNamespaces_MixedZones(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "db"}}}
+	objects = append(objects, healthyClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, healthyClaim("db", "data-1", "vol-1")...)
+
+	clientset := bindingClientset(objects...)
+	m := New(&Config{
+		PVCList:     []string{"db/data-0", "db/data-1"},
+		TargetZones: []string{"eu-west-1a", "eu-west-1b"},
+	}, k8s.NewClientWithInterface(clientset, nil), nil)
+	m.statuses["db/data-0"].Step = StepDone
+	m.statuses["db/data-0"].TargetZone = "eu-west-1a"
+	m.statuses["db/data-1"].Step = StepDone
+	m.statuses["db/data-1"].TargetZone = "eu-west-1b"
+
+	labelled, err := m.LabelCompletedNamespaces(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{"db"}, labelled)
+
+	ns, err := clientset.CoreV1().Namespaces().Get(context.Background(), "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, ns.Labels, k8s.LabelZone)
+	assert.Contains(t, ns.Labels, k8s.LabelCompletedAt)
+}
+
+func TestLabelCompletedNamespaces_DryRun(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{PVCList: []string{"db/data-0"}, TargetZone: "eu-west-1a", DryRun: true}, &fakeEC2{})
+	m.statuses["db/data-0"].Step = StepDone
+
+	labelled, err := m.LabelCompletedNamespaces(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, labelled)
+}
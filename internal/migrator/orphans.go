@@ -0,0 +1,124 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// releaseTimeout bounds the rollback of a static PV, which runs even when the
+// migration was cancelled
+const releaseTimeout = 30 * time.Second
+
+// OrphanedObject is a Kubernetes object the run created for a PVC but could not
+// hand over to a claim, such as the static PV of a PVC that failed before its
+// new claim was created. Its EBS volume is kept when it is deleted.
+type OrphanedObject struct {
+	PVC      string // Full PVC name
+	Kind     string // Kubernetes kind, e.g. PersistentVolume
+	Name     string
+	VolumeID string // EBS volume the object references
+	Reason   string // Why it was not rolled back
+}
+
+// DeleteCommand returns the kubectl command that garbage collects the object
+func (o OrphanedObject) DeleteCommand(kubeContext string) string {
+	cmd := fmt.Sprintf("kubectl delete %s %s --ignore-not-found", strings.ToLower(o.Kind), o.Name)
+	if kubeContext != "" {
+		cmd += " --context=" + kubeContext
+	}
+	return cmd
+}
+
+// Orphans returns the objects left without a claim so far, sorted by PVC
+func (m *Migrator) Orphans() []OrphanedObject {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	orphans := append([]OrphanedObject(nil), m.orphans...)
+	sort.SliceStable(orphans, func(i, j int) bool { return orphans[i].PVC < orphans[j].PVC })
+	return orphans
+}
+
+// releaseStaticPV is called when a PVC fails after its static PV was created.
+// While the old claim is still bound to its PV nothing was switched over, so the
+// new PV is deleted. Otherwise the PV is what the finish commands bind the new
+// claim to: it is kept and recorded as orphaned. It runs even when ctx is done.
+func (m *Migrator) releaseStaticPV(ctx context.Context, pvcName, oldPVName, newPVName, volumeID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	namespace, shortName := ParsePVCName(pvcName)
+	orphan := OrphanedObject{PVC: pvcName, Kind: "PersistentVolume", Name: newPVName, VolumeID: volumeID}
+	info, err := m.k8sClient.GetPVCInfo(ctx, namespace, shortName)
+	switch {
+	case err != nil:
+		orphan.Reason = fmt.Sprintf("the old claim could not be checked: %v", err)
+	case info.PVName == newPVName:
+		// The claim was created even though its request failed
+		return
+	case info.PVName != oldPVName:
+		orphan.Reason = fmt.Sprintf("the claim is bound to %s", info.PVName)
+	default:
+		if err := m.k8sClient.DeleteUnboundPV(ctx, newPVName); err != nil {
+			orphan.Reason = fmt.Sprintf("rollback failed: %v", err)
+			break
+		}
+		slog.Info("static PV rolled back", "pvc", pvcName, "pv", newPVName, "volumeId", volumeID)
+		m.mu.Lock()
+		m.statuses[pvcName].StaticPVRolledBack = true
+		m.touch(m.statuses[pvcName])
+		m.mu.Unlock()
+		return
+	}
+
+	slog.Warn("static PV left without a claim", "pvc", pvcName, "pv", newPVName, "volumeId", volumeID, "reason", orphan.Reason)
+	m.mu.Lock()
+	m.orphans = append(m.orphans, orphan)
+	m.mu.Unlock()
+}
+
+// FormatOrphans renders the orphaned objects as a runbook section, with the
+// commands that garbage collect them
+func FormatOrphans(orphans []OrphanedObject, kubeContext string) string {
+	if len(orphans) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Orphaned Kubernetes objects\n\n")
+	b.WriteString("Bind a claim to them with the finish commands of their PVC, or delete them once\n")
+	b.WriteString("the PVC is migrated or rolled back. Their EBS volumes are kept.\n\n")
+	for _, o := range orphans {
+		b.WriteString(fmt.Sprintf("- %s: %s %s (volume %s), %s\n", o.PVC, o.Kind, o.Name, o.VolumeID, o.Reason))
+	}
+	b.WriteString("\n```sh\n")
+	for _, o := range orphans {
+		b.WriteString(o.DeleteCommand(kubeContext) + "\n")
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// FormatOrphansPlain renders the orphaned objects as sentences, followed by the
+// commands that garbage collect them
+func FormatOrphansPlain(orphans []OrphanedObject, kubeContext string) string {
+	if len(orphans) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("plain.orphans", len(orphans)) + "\n")
+	for _, o := range orphans {
+		b.WriteString(i18n.T("summary.orphan", o.PVC, o.Kind, o.Name, o.VolumeID, o.Reason) + "\n")
+	}
+	b.WriteString(i18n.T("plain.orphans_gc") + "\n")
+	for _, o := range orphans {
+		b.WriteString(o.DeleteCommand(kubeContext) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestReleaseStaticPV(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		objects      []runtime.Object
+		wantDeleted  bool
+		wantOrphan   string // Reason of the orphan, if one is recorded
+		wantRollback bool
+	}{
+		{
+			name:         "old_claim_untouched",
+			objects:      boundClaim("db", "data", "vol-old"),
+			wantDeleted:  true,
+			wantRollback: true,
+		},
+		{
+			name:       "old_claim_deleted",
+			wantOrphan: "the old claim could not be checked",
+		},
+		{
+			name: "new_claim_created",
+			objects: []runtime.Object{&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "db"},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "data-static"},
+			}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientset := bindingClientset(tc.objects...)
+			m := New(&Config{PVCList: []string{"db/data"}}, k8s.NewClientWithInterface(clientset, nil), nil)
+			require.NoError(t, m.k8sClient.CreateStaticPV(context.Background(), "data-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel() // Rollback still runs after the migration is cancelled
+			m.releaseStaticPV(ctx, "db/data", "pv-data", "data-static", "vol-new")
+
+			_, err := clientset.CoreV1().PersistentVolumes().Get(context.Background(), "data-static", metav1.GetOptions{})
+			assert.Equal(t, tc.wantDeleted, apierrors.IsNotFound(err))
+			assert.Equal(t, tc.wantRollback, m.GetStatuses()["db/data"].StaticPVRolledBack)
+
+			orphans := m.Orphans()
+			if tc.wantOrphan == "" {
+				assert.Empty(t, orphans)
+				return
+			}
+			require.Len(t, orphans, 1)
+			assert.Equal(t, "db/data", orphans[0].PVC)
+			assert.Equal(t, "data-static", orphans[0].Name)
+			assert.Equal(t, "vol-new", orphans[0].VolumeID)
+			assert.Contains(t, orphans[0].Reason, tc.wantOrphan)
+		})
+	}
+}
+
+func TestFormatOrphans(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, FormatOrphans(nil, "prod"))
+
+	orphans := []OrphanedObject{{PVC: "db/data", Kind: "PersistentVolume", Name: "data-static", VolumeID: "vol-new", Reason: "the claim is bound to pv-x"}}
+	out := FormatOrphans(orphans, "prod")
+	assert.Contains(t, out, "## Orphaned Kubernetes objects")
+	assert.Contains(t, out, "- db/data: PersistentVolume data-static (volume vol-new), the claim is bound to pv-x")
+	assert.Contains(t, out, "kubectl delete persistentvolume data-static --ignore-not-found --context=prod")
+}
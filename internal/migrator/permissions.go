@@ -0,0 +1,48 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// checkPermissions dry-runs the EC2 calls of a migration with the volume of the
+// first PVC to migrate and its target zone, and returns the actions the
+// credentials are denied. When any is, every PVC to migrate is failed in the
+// plan and returned with the reason, by name, so the run does not start only to
+// fail each PVC on its first call. A dry run failing otherwise is logged and the
+// check skipped.
+func (m *Migrator) checkPermissions(ctx context.Context, items []PVCPlanItem) ([]string, map[string]string) {
+	var probe *PVCPlanItem
+	for i := range items {
+		if items[i].Action == PlanActionMigrate && items[i].VolumeID != "" && items[i].TargetZone != "" {
+			probe = &items[i]
+			break
+		}
+	}
+	if probe == nil {
+		return nil, nil
+	}
+
+	missing, err := m.awsClient.MissingPermissions(ctx, probe.VolumeID, probe.TargetZone)
+	if err != nil {
+		slog.Warn("failed to dry-run the EC2 calls, not checking the permissions", "error", err)
+		return nil, nil
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	reason := fmt.Sprintf("missing EC2 permissions: %s", strings.Join(missing, ", "))
+	failed := make(map[string]string)
+	for i := range items {
+		if items[i].Action != PlanActionMigrate {
+			continue
+		}
+		items[i].Action = PlanActionError
+		items[i].Reason = reason
+		failed[items[i].Name] = reason
+	}
+	return missing, failed
+}
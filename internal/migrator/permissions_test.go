@@ -0,0 +1,54 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePlan_CheckPermissions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		check       bool
+		denied      map[string]bool
+		wantMissing []string
+		wantAction  PlanAction
+		wantReason  string
+	}{
+		{name: "allowed", check: true, wantAction: PlanActionMigrate},
+		{
+			name:        "denied",
+			check:       true,
+			denied:      map[string]bool{"ec2:CreateVolume": true, "ec2:CreateTags": true},
+			wantMissing: []string{"ec2:CreateVolume", "ec2:CreateTags"},
+			wantAction:  PlanActionError,
+			wantReason:  "missing EC2 permissions: ec2:CreateVolume, ec2:CreateTags",
+		},
+		{name: "not checked", denied: map[string]bool{"ec2:CreateVolume": true}, wantAction: PlanActionMigrate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}, denied: tt.denied}
+			m := newFakeMigrator(&Config{
+				PVCList:          []string{"db/data-0"},
+				TargetZone:       "eu-west-1a",
+				CheckPermissions: tt.check,
+			}, fake, boundClaim("db", "data-0", "vol-0")...)
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMissing, plan.MissingPermissions)
+			require.Len(t, plan.Items, 1)
+			assert.Equal(t, tt.wantAction, plan.Items[0].Action)
+			assert.Equal(t, tt.wantReason, plan.Items[0].Reason)
+			assert.Empty(t, fake.snapshots, "dry runs create nothing")
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package migrator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// FormatPlanPlain renders the migration plan as short sentences without colors,
+// box drawing or icons, for screen readers and plain-text terminals
+func FormatPlanPlain(plan *MigrationPlan) string {
+	var b strings.Builder
+
+	migrateCount, skipCount, errorCount := 0, 0, 0
+	for _, item := range plan.Items {
+		switch item.Action {
+		case PlanActionMigrate:
+			migrateCount++
+		case PlanActionSkip:
+			skipCount++
+		case PlanActionError:
+			errorCount++
+		}
+	}
+
+	lines := []string{
+		i18n.T("plain.title"),
+		i18n.T("plain.target_zone", plan.ZoneLabel(plan.TargetZone)),
+		i18n.T("plain.storage_class", plan.StorageClass),
+		i18n.T("plain.volume_type", volumeSummary(plan)),
+		i18n.T("plain.namespaces", strings.Join(plan.Namespaces, ", ")),
+		i18n.T("plain.concurrency", plan.Concurrency),
+		i18n.T("plain.encryption", encryptionSummary(plan)),
+	}
+	if plan.KMSConflict() {
+		lines = append(lines, i18n.T("plain.kms_conflict", plan.KMSKeyID, plan.Encryption.KMSKeyID))
+	}
+	if plan.FastRestore {
+		lines = append(lines, i18n.T("plain.fast_restore"))
+	}
+	if plan.DryRun {
+		lines = append(lines, i18n.T("plain.dry_run"))
+	}
+	for _, c := range plan.AutoZones {
+		if c.Zone == "" {
+			lines = append(lines, i18n.T("plain.auto_zone_none", c.Namespace, strings.Join(c.Excluded, ", ")))
+			continue
+		}
+		lines = append(lines, i18n.T("plain.auto_zone", c.Namespace, c.Zone, c.Nodes, c.CPUPercent, c.MemoryPercent))
+	}
+	for _, issue := range plan.NodeIssues {
+		lines = append(lines, nodeIssueText("plain", issue))
+	}
+	for _, s := range plan.Schedulability {
+		lines = append(lines, scheduleText("plain", s))
+	}
+	if len(plan.MissingPermissions) > 0 {
+		lines = append(lines, i18n.T("plain.missing_permissions", strings.Join(plan.MissingPermissions, ", ")))
+	}
+	if plan.Quotas != nil {
+		lines = append(lines, quotaSnapshotsText("plain", plan.Quotas))
+		for _, s := range plan.Quotas.Storage {
+			lines = append(lines, quotaStorageText("plain", s))
+		}
+	}
+	lines = append(lines, i18n.T("plain.counts", len(plan.Items), migrateCount, skipCount, errorCount))
+
+	for _, item := range plan.Items {
+		switch item.Action {
+		case PlanActionMigrate:
+			lines = append(lines, i18n.T("plain.migrate", item.Name, item.Capacity, item.CurrentZone, item.TargetZone))
+			if !item.Attached {
+				lines = append(lines, i18n.T("plain.unattached", item.Name))
+			}
+			if item.StorageClass != "" {
+				lines = append(lines, i18n.T("plain.storage_class_override", item.Name, item.StorageClass))
+			}
+			if item.VolumeSpec != plan.VolumeSpec {
+				lines = append(lines, i18n.T("plain.volume_override", item.Name, item.VolumeSpec))
+			}
+			if item.VolumeSpec.BlockExpress(item.CapacityGi) {
+				lines = append(lines, i18n.T("plain.block_express", item.Name))
+			}
+			if item.Priority != "" {
+				lines = append(lines, i18n.T("plain.priority", item.Name, i18n.T("priority."+item.Priority)))
+			}
+			if item.Window != "" {
+				lines = append(lines, i18n.T("plain.window", item.Name, item.Window))
+			}
+			if item.CoMountedWith != "" {
+				lines = append(lines, i18n.T("plain.co_mounted", item.Name, item.CoMountedWith))
+			}
+			if item.Block {
+				lines = append(lines, i18n.T("plain.block", item.Name))
+			}
+			switch {
+			case item.PVMissing:
+				lines = append(lines, i18n.T("plain.pv_deleted", item.Name, item.PVName, item.VolumeID))
+			case item.Rebuilt():
+				lines = append(lines, i18n.T("plain.pv_unhealthy", item.Name, item.ClaimPhase, item.PVPhase))
+			}
+			switch item.ResumeAt {
+			case StepCreatePVC:
+				lines = append(lines, i18n.T("plain.resume_claim", item.Name, item.PVName, item.VolumeID))
+			case StepCleanup:
+				lines = append(lines, i18n.T("plain.resume_pv", item.Name, staticPVName(item.PVCName)))
+			}
+			if item.StagedSnapshotID != "" {
+				lines = append(lines, i18n.T("plain.staged_snapshot", item.Name, item.StagedSnapshotID, formatStagedTime(item.StagedSnapshotTime)))
+			}
+			if len(item.Tags) > 0 {
+				lines = append(lines, i18n.T("plain.tags", item.Name, formatTags(item.Tags)))
+			}
+			switch {
+			case item.Reencrypted():
+				lines = append(lines, i18n.T("plain.reencrypted", item.Name, item.SourceKMSKeyID, item.KMSKeyID))
+			case item.KMSKeyID != "":
+				lines = append(lines, i18n.T("plain.encrypted", item.Name, item.KMSKeyID))
+			case plan.Encryption != nil:
+				lines = append(lines, i18n.T("plain.unencrypted", item.Name))
+			}
+		case PlanActionSkip:
+			lines = append(lines, i18n.T("plain.skip", item.Name))
+		case PlanActionError:
+			lines = append(lines, i18n.T("plain.error", item.Name, item.Reason))
+		}
+	}
+
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// FormatSummaryPlain renders the end-of-run summary of m as short sentences without
+// colors, rules or icons. Failed PVCs are followed by their remediation commands
+// and the summary ends with the warnings that need follow-up.
+func FormatSummaryPlain(m *Migrator) string {
+	statuses := m.GetStatuses()
+	remediations := m.Remediations()
+	warnings := m.Warnings()
+
+	var b strings.Builder
+	b.WriteString(i18n.T("plain.summary_title") + "\n")
+
+	byPVC := make(map[string]Remediation, len(remediations))
+	for _, r := range remediations {
+		byPVC[r.PVC] = r
+	}
+
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	migrated, skipped, failed := 0, 0, 0
+	for _, name := range names {
+		s := statuses[name]
+		switch s.Step {
+		case StepDone:
+			migrated++
+			sentence := i18n.T("plain.done")
+			if s.NewVolumeID != "" {
+				sentence += " " + i18n.T("plain.new_volume", s.NewVolumeID)
+			}
+			if s.ChecksumVerified {
+				sentence += " " + i18n.T("plain.checksum_ok", s.Checksum)
+			}
+			switch s.MountCheck {
+			case MountCheckPassed:
+				sentence += " " + i18n.T("plain.mount_passed")
+			case MountCheckFailed:
+				sentence += " " + i18n.T("plain.mount_failed", s.MountCheckError)
+			}
+			b.WriteString(fmt.Sprintf("%s: %s\n", name, sentence))
+		case StepSkipped:
+			skipped++
+			b.WriteString(fmt.Sprintf("%s: %s\n", name, i18n.T("plain.skipped")))
+		case StepFailed:
+			failed++
+			sentence := i18n.T("plain.failed")
+			if s.Error != nil {
+				sentence = i18n.T("plain.failed_error", s.Error.Error())
+			}
+			b.WriteString(fmt.Sprintf("%s: %s\n", name, sentence))
+			r := byPVC[name]
+			writePlainCommands(&b, i18n.T("plain.finish", name), r.Finish)
+			writePlainCommands(&b, i18n.T("plain.rollback", name), r.Rollback)
+		case StepPending, StepGetInfo, StepSnapshot, StepWaitSnapshot, StepCreateVolume,
+			StepWaitVolume, StepCleanup, StepCreatePV, StepCreatePVC:
+			b.WriteString(fmt.Sprintf("%s: %s\n", name, i18n.T("plain.incomplete")))
+		}
+	}
+
+	b.WriteString(i18n.T("plain.summary_counts", len(statuses), migrated, skipped, failed) + "\n")
+	if usage := FormatAPIUsage(m.APIUsage()); usage != "" {
+		b.WriteString(i18n.T("plain.api_calls", usage) + "\n")
+	}
+	switch {
+	case failed > 0:
+		b.WriteString(i18n.T("plain.some_failed") + "\n")
+		if retryable := len(m.RetryableFailures()); retryable > 0 {
+			b.WriteString(i18n.T("summary.retry_hint", retryable) + ".\n")
+		}
+	case len(m.Stragglers()) > 0:
+		b.WriteString(i18n.T("plain.zone_not_empty") + "\n")
+	case len(warnings) > 0:
+		b.WriteString(i18n.T("plain.with_warnings", len(warnings)) + "\n")
+	case migrated > 0:
+		b.WriteString(i18n.T("plain.all_ok") + "\n")
+		b.WriteString(i18n.T("summary.next_step", m.GetConfig().Destination()) + ".\n")
+	}
+
+	b.WriteString(FormatOrphansPlain(m.Orphans(), m.GetConfig().KubeContext))
+	b.WriteString(FormatStragglersPlain(m.Stragglers(), m.GetConfig().SourceZone))
+	b.WriteString(FormatWarningsPlain(warnings))
+	return b.String()
+}
+
+// FormatWarningsPlain renders the warnings that need follow-up as sentences, each
+// followed by the commands or steps that resolve it
+func FormatWarningsPlain(warnings []Warning) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("plain.action_required", len(warnings)) + "\n")
+	for _, w := range warnings {
+		if w.PVC != "" {
+			b.WriteString(w.PVC + ": ")
+		}
+		b.WriteString(w.Message + "\n")
+		if w.Action != "" {
+			b.WriteString(i18n.T("plain.action") + "\n")
+			b.WriteString(w.Action + "\n")
+		}
+	}
+	return b.String()
+}
+
+func writePlainCommands(b *strings.Builder, title string, commands []string) {
+	if len(commands) == 0 {
+		return
+	}
+	b.WriteString(title + "\n")
+	for _, c := range commands {
+		b.WriteString(c + "\n")
+	}
+}
+
+// NewPlainEventWriter returns a listener that prints one sentence per step change.
+// Snapshot progress is only announced in 25% increments so the output stays short
+// enough to follow with a screen reader. total is the number of PVCs in the run.
+func NewPlainEventWriter(w io.Writer, total int) EventListener {
+	var mu sync.Mutex
+	lastStep := make(map[string]string)
+	lastBucket := make(map[string]int)
+	finished := 0
+
+	return func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if e.Step == lastStep[e.PVC] {
+			// Same step: only announce progress milestones
+			bucket := e.Progress / 25
+			if e.Step != StepWaitSnapshot.String() || bucket <= lastBucket[e.PVC] || bucket >= 4 {
+				return
+			}
+			lastBucket[e.PVC] = bucket
+			_, _ = fmt.Fprintf(w, "%s: %s\n", e.PVC, i18n.T("plain.progress", bucket*25))
+			return
+		}
+		lastStep[e.PVC] = e.Step
+		lastBucket[e.PVC] = 0
+
+		sentence := plainStepSentence(e)
+		if sentence == "" {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s: %s\n", e.PVC, sentence)
+
+		if e.Step == StepDone.String() || e.Step == StepSkipped.String() || e.Step == StepFailed.String() {
+			finished++
+			_, _ = fmt.Fprintln(w, i18n.T("plain.finished", finished, total))
+		}
+	}
+}
+
+// plainStepSentence describes the step an event moved to
+func plainStepSentence(e Event) string {
+	switch e.Step {
+	case StepGetInfo.String():
+		return i18n.T("plain.get_info")
+	case StepSkipped.String():
+		return i18n.T("plain.skipped")
+	case StepSnapshot.String():
+		return i18n.T("plain.snapshot")
+	case StepWaitSnapshot.String():
+		return i18n.T("plain.wait_snapshot")
+	case StepCreateVolume.String():
+		return i18n.T("plain.create_volume")
+	case StepWaitVolume.String():
+		return i18n.T("plain.wait_volume")
+	case StepCleanup.String():
+		return i18n.T("plain.cleanup")
+	case StepCreatePV.String():
+		return i18n.T("plain.create_pv")
+	case StepCreatePVC.String():
+		return i18n.T("plain.create_pvc")
+	case StepDone.String():
+		return i18n.T("plain.done")
+	case StepFailed.String():
+		if e.Error != "" {
+			return i18n.T("plain.failed_error", e.Error)
+		}
+		return i18n.T("plain.failed")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,184 @@
+package migrator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+func TestFormatPlanPlain(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		Items: []PVCPlanItem{
+			{Name: "db/data-0", Action: PlanActionMigrate, Capacity: "20Gi", CurrentZone: "us-west-2b", TargetZone: "us-west-2a", Attached: true},
+			{Name: "db/scratch", Action: PlanActionMigrate, Capacity: "5Gi", CurrentZone: "us-west-2b", TargetZone: "us-west-2a", StorageClass: "sc1", Priority: PriorityLow, Window: "02:00-04:00 UTC"},
+			{Name: "db/data-1", Action: PlanActionSkip},
+			{Name: "db/data-2", Action: PlanActionError, Reason: "PV not found"},
+			{Name: "db/data-3", Action: PlanActionMigrate, Capacity: "5Gi", PVName: "pv-3", VolumeID: "vol-3", ClaimPhase: "Lost", PVMissing: true, Attached: true},
+			{Name: "db/data-4", Action: PlanActionMigrate, Capacity: "5Gi", ClaimPhase: "Bound", PVPhase: "Released", Attached: true},
+			{Name: "db/logs", Action: PlanActionMigrate, Capacity: "1Gi", CoMountedWith: "app-0", Attached: true},
+		},
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Concurrency:  2,
+		DryRun:       true,
+	}
+
+	out := FormatPlanPlain(plan)
+
+	assert.Contains(t, out, "Target zone: us-west-2a.")
+	assert.Contains(t, out, "Dry run: no changes will be made.")
+	assert.Contains(t, out, "7 PVCs: 5 to migrate, 1 to skip, 1 with errors.")
+	assert.Contains(t, out, "Migrate db/data-0, 20Gi, from us-west-2b to us-west-2a.\nMigrate db/scratch")
+	assert.Contains(t, out, "No pod mounts db/scratch, so no workloads are scaled down for it.")
+	assert.Contains(t, out, "db/scratch uses storage class sc1.")
+	assert.Contains(t, out, "db/scratch has low priority.")
+	assert.Contains(t, out, "db/scratch is only migrated between 02:00-04:00 UTC.")
+	assert.Contains(t, out, "Skip db/data-1, already in the target zone.")
+	assert.Contains(t, out, "Error for db/data-2: PV not found.")
+	assert.Contains(t, out, "db/data-3 lost PV pv-3; volume vol-3 was found by its tags and is adopted")
+	assert.Contains(t, out, "db/data-4 is Bound with a Released PV; a new PV and PVC are created.")
+	assert.Contains(t, out, "db/logs was added because pod app-0 also mounts it.")
+	assert.NotContains(t, out, "\x1b[", "no ANSI escape sequences")
+	assert.NotContains(t, out, "═")
+}
+
+func TestFormatPlanPlain_Encryption(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		Items: []PVCPlanItem{
+			{Name: "db/data-0", Action: PlanActionMigrate, SourceKMSKeyID: "alias/old", KMSKeyID: "alias/prod"},
+			{Name: "db/data-1", Action: PlanActionMigrate, KMSKeyID: "alias/prod"},
+		},
+		KMSKeyID:   "alias/prod",
+		Encryption: &aws.EncryptionDefaults{ByDefault: true, KMSKeyID: "alias/aws/ebs"},
+	}
+
+	out := FormatPlanPlain(plan)
+
+	assert.Contains(t, out, "Encryption: new volumes use KMS key alias/prod.")
+	assert.Contains(t, out, "Warning: KMS key alias/prod differs from the account default key alias/aws/ebs.")
+	assert.Contains(t, out, "db/data-0 is re-encrypted from alias/old to alias/prod.")
+	assert.Contains(t, out, "db/data-1 is encrypted with alias/prod.")
+	assert.NotContains(t, out, "is tagged")
+
+	plan = &MigrationPlan{
+		Items:      []PVCPlanItem{{Name: "db/data-0", Action: PlanActionMigrate}},
+		Encryption: &aws.EncryptionDefaults{KMSKeyID: "alias/aws/ebs"},
+	}
+	out = FormatPlanPlain(plan)
+	assert.Contains(t, out, "account default off")
+	assert.Contains(t, out, "db/data-0 is not encrypted.")
+	assert.NotContains(t, out, "Warning: KMS key")
+}
+
+func TestNewPlainEventWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	write := NewPlainEventWriter(&buf, 2)
+
+	events := []Event{
+		{PVC: "db/data-0", Step: StepGetInfo.String()},
+		{PVC: "db/data-0", Step: StepSnapshot.String()},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String()},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String(), Progress: 10},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String(), Progress: 30},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String(), Progress: 40},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String(), Progress: 80},
+		{PVC: "db/data-0", Step: StepWaitSnapshot.String(), Progress: 100},
+		{PVC: "db/data-1", Step: StepSkipped.String()},
+		{PVC: "db/data-0", Step: StepFailed.String(), Error: "boom"},
+	}
+	for _, e := range events {
+		write(e)
+	}
+
+	want := []string{
+		"db/data-0: getting volume information.",
+		"db/data-0: creating snapshot.",
+		"db/data-0: waiting for snapshot.",
+		"db/data-0: snapshot 25 percent complete.",
+		"db/data-0: snapshot 75 percent complete.",
+		"db/data-1: skipped, already in the target zone.",
+		"1 of 2 PVCs finished.",
+		"db/data-0: failed: boom",
+		"2 of 2 PVCs finished.",
+	}
+	assert.Equal(t, want, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestFormatSummaryPlain(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0", "db/data-1", "db/data-2", "db/data-3"}, TargetZone: "us-west-2a"}, nil, nil)
+	m.updateStatus("db/data-0", StepDone, 100, nil)
+	m.statuses["db/data-0"].NewVolumeID = "vol-new"
+	m.updateStatus("db/data-1", StepSkipped, 100, nil)
+	m.updateStatus("db/data-2", StepSnapshot, 0, nil)
+	m.updateStatus("db/data-2", StepFailed, 0, errors.New("throttled"))
+	m.AddWarning(Warning{Message: "workloads not restored", Action: "kubectl scale deployment app --replicas=2 -n db"})
+
+	out := FormatSummaryPlain(m)
+
+	assert.Contains(t, out, "Migration summary.")
+	assert.Contains(t, out, "db/data-0: migrated successfully. New volume: vol-new.")
+	assert.Contains(t, out, "db/data-1: skipped, already in the target zone.")
+	assert.Contains(t, out, "db/data-2: failed: throttled")
+	assert.Contains(t, out, "To finish db/data-2 by hand, run:\n# Check whether a snapshot was created")
+	assert.Contains(t, out, "db/data-3: did not finish.")
+	assert.Contains(t, out, "4 PVCs: 1 migrated, 1 skipped, 1 failed.")
+	assert.Contains(t, out, "To roll back db/data-2, run:")
+	assert.Contains(t, out, "Some migrations failed.")
+	assert.Contains(t, out, "Action required: 1 follow-ups.\nworkloads not restored\nTo fix it:\nkubectl scale deployment app --replicas=2 -n db")
+	assert.NotContains(t, out, "\x1b[", "no ANSI escape sequences")
+	for _, glyph := range []string{"═", "✓", "✗", "○", "⚠"} {
+		assert.NotContains(t, out, glyph)
+	}
+}
+
+func TestFormatSummaryPlain_AllOK(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0"}, TargetZone: "us-west-2a"}, nil, nil)
+	m.updateStatus("db/data-0", StepDone, 100, nil)
+
+	out := FormatSummaryPlain(m)
+
+	assert.Contains(t, out, "All migrations completed successfully.")
+	assert.Contains(t, out, "Next step: Ensure your workloads can schedule pods in us-west-2a.")
+	assert.NotContains(t, out, "Action required")
+}
+
+func TestFormatPlanPlain_Tags(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		Items: []PVCPlanItem{{Name: "db/x", Action: PlanActionMigrate, Tags: map[string]string{"team": "data", "costcenter": "42"}}},
+	}
+
+	assert.Contains(t, FormatPlanPlain(plan), "db/x is tagged costcenter=42, team=data.")
+}
+
+func TestFormatPlanPlain_Resume(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		Items: []PVCPlanItem{
+			{Name: "db/x", PVCName: "x", Action: PlanActionMigrate, ResumeAt: StepCleanup},
+			{Name: "db/y", PVCName: "y", PVName: "y-static", VolumeID: "vol-new", Action: PlanActionMigrate, ResumeAt: StepCreatePVC},
+		},
+	}
+
+	out := FormatPlanPlain(plan)
+	assert.Contains(t, out, "db/x goes on from PV x-static an earlier run created; no new snapshot or volume is made.")
+	assert.Contains(t, out, "An earlier run deleted db/y; it is recreated on PV y-static, keeping volume vol-new.")
+}
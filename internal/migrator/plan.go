@@ -3,8 +3,12 @@ package migrator
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
 )
 
 // Plan formatting styles
@@ -47,31 +51,132 @@ var (
 				PaddingRight(2)
 )
 
+// planTableLayout holds the column widths of the plan table
+type planTableLayout struct {
+	pvcCol    int
+	zoneCol   int
+	actionCol int
+	compact   bool
+}
+
+var (
+	defaultPlanLayout = planTableLayout{pvcCol: 40, zoneCol: 14, actionCol: 25}
+	compactPlanLayout = planTableLayout{pvcCol: 24, zoneCol: 12, actionCol: 18, compact: true}
+)
+
 // FormatPlan renders the migration plan as a colored string
 func FormatPlan(plan *MigrationPlan) string {
+	return formatPlan(plan, defaultPlanLayout)
+}
+
+// FormatPlanCompact renders the migration plan for narrow terminals, with shorter
+// columns and abbreviated actions so rows do not wrap
+func FormatPlanCompact(plan *MigrationPlan) string {
+	return formatPlan(plan, compactPlanLayout)
+}
+
+func formatPlan(plan *MigrationPlan, layout planTableLayout) string {
 	var b strings.Builder
 
 	// Title
+	ruleWidth := 75
+	if layout.compact {
+		ruleWidth = 54
+	}
+	rule := strings.Repeat("═", ruleWidth)
+	title := centerText(i18n.T("plan.title"), ruleWidth)
 	b.WriteString("\n")
-	b.WriteString(planTitleStyle.Render("═══════════════════════════════════════════════════════════════════════════"))
+	b.WriteString(planTitleStyle.Render(rule))
 	b.WriteString("\n")
-	b.WriteString(planTitleStyle.Render("                              MIGRATION PLAN"))
+	b.WriteString(planTitleStyle.Render(title))
 	b.WriteString("\n")
-	b.WriteString(planTitleStyle.Render("═══════════════════════════════════════════════════════════════════════════"))
+	b.WriteString(planTitleStyle.Render(rule))
 	b.WriteString("\n\n")
 
 	// Configuration section
-	b.WriteString(planHeaderStyle.Render("Configuration:"))
+	b.WriteString(planHeaderStyle.Render(i18n.T("plan.configuration")))
 	b.WriteString("\n")
-	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render("Target Zone:"), plan.TargetZone))
-	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render("Storage Class:"), plan.StorageClass))
-	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render("Namespaces:"), strings.Join(plan.Namespaces, ", ")))
-	b.WriteString(fmt.Sprintf("  %s %d\n", planInfoStyle.Render("Concurrency:"), plan.Concurrency))
+	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render(i18n.T("plan.target_zone")), plan.ZoneLabel(plan.TargetZone)))
+	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render(i18n.T("plan.storage_class")), plan.StorageClass))
+	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render(i18n.T("plan.volume_type")), volumeSummary(plan)))
+	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render(i18n.T("plan.namespaces")), strings.Join(plan.Namespaces, ", ")))
+	b.WriteString(fmt.Sprintf("  %s %d\n", planInfoStyle.Render(i18n.T("plan.concurrency")), plan.Concurrency))
+	b.WriteString(fmt.Sprintf("  %s %s\n", planInfoStyle.Render(i18n.T("plan.encryption")), encryptionSummary(plan)))
+	if plan.KMSConflict() {
+		b.WriteString(fmt.Sprintf("  %s\n", planWarningStyle.Render(i18n.T("plan.kms_conflict", plan.KMSKeyID, plan.Encryption.KMSKeyID))))
+	}
 	if plan.DryRun {
-		b.WriteString(fmt.Sprintf("  %s\n", planWarningStyle.Render("⚠️  DRY RUN MODE - No changes will be made")))
+		b.WriteString(fmt.Sprintf("  %s\n", planWarningStyle.Render(i18n.T("plan.dry_run"))))
 	}
 	b.WriteString("\n")
 
+	// Zones picked with --zone auto
+	if len(plan.AutoZones) > 0 {
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.auto_zones")))
+		b.WriteString("\n")
+		for _, c := range plan.AutoZones {
+			if c.Zone == "" {
+				b.WriteString(fmt.Sprintf("  %s\n", planErrorStyle.Render(i18n.T("plan.auto_zone_none", c.Namespace, strings.Join(c.Excluded, ", ")))))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("  %s\n", i18n.T("plan.auto_zone", c.Namespace, c.Zone, c.Nodes, c.CPUPercent, c.MemoryPercent)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Target zones the moved pods could not be scheduled in
+	if len(plan.NodeIssues) > 0 {
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.node_issues")))
+		b.WriteString("\n")
+		for _, issue := range plan.NodeIssues {
+			b.WriteString(fmt.Sprintf("  %s\n", planWarningStyle.Render(nodeIssueText("plan", issue))))
+		}
+		b.WriteString("\n")
+	}
+
+	// Pods of the workloads dry-run in their target zone
+	if len(plan.Schedulability) > 0 {
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.scheduling")))
+		b.WriteString("\n")
+		for _, s := range plan.Schedulability {
+			if s.Schedulable() {
+				b.WriteString(fmt.Sprintf("  %s\n", planMigrateStyle.Render(scheduleText("plan", s))))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("  %s\n", planWarningStyle.Render(scheduleText("plan", s))))
+		}
+		b.WriteString("\n")
+	}
+
+	// EC2 actions the dry runs were denied
+	if len(plan.MissingPermissions) > 0 {
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.missing_permissions")))
+		b.WriteString("\n")
+		for _, action := range plan.MissingPermissions {
+			b.WriteString(fmt.Sprintf("  %s\n", planErrorStyle.Render("✗ "+action)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Usage the run adds to against the EBS quotas
+	if q := plan.Quotas; q != nil {
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.quotas")))
+		b.WriteString("\n")
+		style := planMigrateStyle
+		if q.SnapshotsExceeded() {
+			style = planErrorStyle
+		}
+		b.WriteString(fmt.Sprintf("  %s\n", style.Render(quotaSnapshotsText("plan", q))))
+		for _, s := range q.Storage {
+			style = planMigrateStyle
+			if s.Exceeded() {
+				style = planErrorStyle
+			}
+			b.WriteString(fmt.Sprintf("  %s\n", style.Render(quotaStorageText("plan", s))))
+		}
+		b.WriteString("\n")
+	}
+
 	// Count actions
 	migrateCount := 0
 	skipCount := 0
@@ -88,46 +193,49 @@ func FormatPlan(plan *MigrationPlan) string {
 	}
 
 	// Summary
-	b.WriteString(planHeaderStyle.Render(fmt.Sprintf("PVCs to Process (%d):", len(plan.Items))))
+	b.WriteString(planHeaderStyle.Render(i18n.T("plan.pvcs_to_process", len(plan.Items))))
 	b.WriteString("\n")
 	b.WriteString(fmt.Sprintf("  %s  %s  %s\n",
-		planMigrateStyle.Render(fmt.Sprintf("✓ Migrate: %d", migrateCount)),
-		planSkipStyle.Render(fmt.Sprintf("○ Skip: %d", skipCount)),
-		planErrorStyle.Render(fmt.Sprintf("✗ Error: %d", errorCount)),
+		planMigrateStyle.Render(i18n.T("plan.count_migrate", migrateCount)),
+		planSkipStyle.Render(i18n.T("plan.count_skip", skipCount)),
+		planErrorStyle.Render(i18n.T("plan.count_error", errorCount)),
 	))
 	b.WriteString("\n")
 
 	// Table header
-	tableContent := renderPlanTable(plan)
+	tableContent := renderPlanTable(plan, layout)
 	b.WriteString(planBoxStyle.Render(tableContent))
 	b.WriteString("\n\n")
 
 	// Actions summary
 	if migrateCount > 0 {
-		b.WriteString(planHeaderStyle.Render("Actions to be performed:"))
+		b.WriteString(planHeaderStyle.Render(i18n.T("plan.actions")))
 		b.WriteString("\n")
-		b.WriteString(fmt.Sprintf("  %s Create EBS snapshots for %d volume(s)\n", planDimStyle.Render("1."), migrateCount))
-		b.WriteString(fmt.Sprintf("  %s Create new volumes in %s\n", planDimStyle.Render("2."), plan.TargetZone))
-		b.WriteString(fmt.Sprintf("  %s Delete old PVCs and PVs\n", planDimStyle.Render("3.")))
-		b.WriteString(fmt.Sprintf("  %s Create new static PVs and bound PVCs\n", planDimStyle.Render("4.")))
+		b.WriteString(fmt.Sprintf("  %s %s\n", planDimStyle.Render("1."), i18n.T("plan.action_snapshots", migrateCount)))
+		volumes := i18n.T("plan.action_volumes", plan.TargetZone)
+		if plan.FastRestore {
+			volumes = i18n.T("plan.action_volumes_fsr", plan.TargetZone)
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n", planDimStyle.Render("2."), volumes))
+		b.WriteString(fmt.Sprintf("  %s %s\n", planDimStyle.Render("3."), i18n.T("plan.action_delete")))
+		b.WriteString(fmt.Sprintf("  %s %s\n", planDimStyle.Render("4."), i18n.T("plan.action_create")))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-func renderPlanTable(plan *MigrationPlan) string {
+func renderPlanTable(plan *MigrationPlan, layout planTableLayout) string {
 	var b strings.Builder
 
-	// Calculate column widths
-	pvcColWidth := 40
-	zoneColWidth := 14
-	actionColWidth := 25
+	pvcColWidth := layout.pvcCol
+	zoneColWidth := layout.zoneCol
+	actionColWidth := layout.actionCol
 
 	// Header
-	b.WriteString(planTableHeaderStyle.Render(padRight("PVC", pvcColWidth)))
-	b.WriteString(planTableHeaderStyle.Render(padRight("Current Zone", zoneColWidth)))
-	b.WriteString(planTableHeaderStyle.Render(padRight("Action", actionColWidth)))
+	b.WriteString(planTableHeaderStyle.Render(padRight(i18n.T("plan.col_pvc"), pvcColWidth)))
+	b.WriteString(planTableHeaderStyle.Render(padRight(i18n.T("plan.col_zone"), zoneColWidth)))
+	b.WriteString(planTableHeaderStyle.Render(padRight(i18n.T("plan.col_action"), actionColWidth)))
 	b.WriteString("\n")
 
 	// Separator
@@ -150,10 +258,17 @@ func renderPlanTable(plan *MigrationPlan) string {
 		// Action with icon
 		switch item.Action {
 		case PlanActionMigrate:
-			actionStr := fmt.Sprintf("✓ Will migrate → %s", item.TargetZone)
+			actionStr := i18n.T("plan.will_migrate", item.TargetZone)
+			if layout.compact {
+				actionStr = fmt.Sprintf("✓ → %s", item.TargetZone)
+			}
 			b.WriteString(planMigrateStyle.Render(actionStr))
 		case PlanActionSkip:
-			b.WriteString(planSkipStyle.Render("○ Skip (same AZ)"))
+			if layout.compact {
+				b.WriteString(planSkipStyle.Render(i18n.T("plan.skip_short")))
+			} else {
+				b.WriteString(planSkipStyle.Render(i18n.T("plan.skip_same_az")))
+			}
 		case PlanActionError:
 			errStr := truncatePlan(item.Reason, actionColWidth-4)
 			b.WriteString(planErrorStyle.Render(fmt.Sprintf("✗ %s", errStr)))
@@ -163,19 +278,170 @@ func renderPlanTable(plan *MigrationPlan) string {
 
 		// Show capacity and volume ID on second line for migrate items
 		if item.Action == PlanActionMigrate && item.VolumeID != "" {
-			b.WriteString(planDimStyle.Render(fmt.Sprintf("  └─ %s, Volume: %s", item.Capacity, truncatePlan(item.VolumeID, 25))))
+			b.WriteString(planDimStyle.Render(i18n.T("plan.volume_detail", item.Capacity, truncatePlan(item.VolumeID, 25))))
 			b.WriteString("\n")
+			if !item.Attached {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.unattached")))
+				b.WriteString("\n")
+			}
+			if item.StorageClass != "" {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.storage_class_override", item.StorageClass)))
+				b.WriteString("\n")
+			}
+			if item.VolumeSpec != plan.VolumeSpec {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.volume_override", item.VolumeSpec)))
+				b.WriteString("\n")
+			}
+			if item.VolumeSpec.BlockExpress(item.CapacityGi) {
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.block_express")))
+				b.WriteString("\n")
+			}
+			if item.Priority != "" {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.priority", i18n.T("priority."+item.Priority))))
+				b.WriteString("\n")
+			}
+			if item.Window != "" {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.window", item.Window)))
+				b.WriteString("\n")
+			}
+			if item.CoMountedWith != "" {
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.co_mounted", item.CoMountedWith)))
+				b.WriteString("\n")
+			}
+			if item.Block {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.block")))
+				b.WriteString("\n")
+			}
+			switch {
+			case item.PVMissing:
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.pv_deleted", item.PVName)))
+				b.WriteString("\n")
+			case item.Rebuilt():
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.pv_unhealthy", item.ClaimPhase, item.PVPhase)))
+				b.WriteString("\n")
+			}
+			switch item.ResumeAt {
+			case StepCreatePVC:
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.resume_claim", item.PVName)))
+				b.WriteString("\n")
+			case StepCleanup:
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.resume_pv", staticPVName(item.PVCName))))
+				b.WriteString("\n")
+			}
+			if item.StagedSnapshotID != "" {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.staged_snapshot", item.StagedSnapshotID, formatStagedTime(item.StagedSnapshotTime))))
+				b.WriteString("\n")
+			}
+			if len(item.Tags) > 0 {
+				b.WriteString(planDimStyle.Render(i18n.T("plan.tags", formatTags(item.Tags))))
+				b.WriteString("\n")
+			}
+			switch {
+			case item.Reencrypted():
+				b.WriteString(planDimStyle.Render(i18n.T("plan.reencrypted", item.KMSKeyID, item.SourceKMSKeyID)))
+				b.WriteString("\n")
+			case item.KMSKeyID != "":
+				b.WriteString(planDimStyle.Render(i18n.T("plan.encrypted", item.KMSKeyID)))
+				b.WriteString("\n")
+			case plan.Encryption != nil:
+				b.WriteString(planWarningStyle.Render(i18n.T("plan.unencrypted")))
+				b.WriteString("\n")
+			}
 		}
 	}
 
 	return b.String()
 }
 
+// nodeIssueText describes a node issue with the plan or plain messages, by prefix
+func nodeIssueText(prefix string, issue NodeIssue) string {
+	switch {
+	case issue.Unprovisionable:
+		return i18n.T(prefix+".node_issue_launch", issue.Zone, strings.Join(issue.Claims, ", "))
+	case issue.Workload == "":
+		return i18n.T(prefix+".node_issue_empty", issue.Zone, strings.Join(issue.Claims, ", "))
+	}
+	return i18n.T(prefix+".node_issue_selector", issue.Zone, issue.Workload, strings.Join(issue.Claims, ", "))
+}
+
+// scheduleText describes whether a pod of the workload could be scheduled in
+// its target zone, with the plan or plain messages
+func scheduleText(prefix string, s WorkloadSchedule) string {
+	workload := s.Namespace + "/" + s.Kind + "/" + s.Name
+	switch {
+	case !s.Admitted:
+		return i18n.T(prefix+".sched_denied", workload, s.Zone, s.Reason)
+	case s.Nodes == 0:
+		return i18n.T(prefix+".sched_unfit", workload, s.Zone, s.Reason)
+	}
+	return i18n.T(prefix+".sched_fits", workload, s.Nodes, s.Zone)
+}
+
+// quotaSnapshotsText describes the snapshots against their quota; prefix picks
+// the "plan" or "plain" wording
+func quotaSnapshotsText(prefix string, q *QuotaCheck) string {
+	return i18n.T(prefix+".quota_snapshots", q.Snapshots, q.NewSnapshots, q.SnapshotQuota)
+}
+
+// quotaStorageText describes the storage of a type against its quota; prefix picks the
+// "plan" or "plain" wording
+func quotaStorageText(prefix string, q StorageQuotaCheck) string {
+	return i18n.T(prefix+".quota_storage", q.VolumeType, q.StorageGiB, q.NewStorageGiB, q.QuotaGiB)
+}
+
+// volumeSummary describes the type and performance of the new volumes of the
+// plan: those given for the run, or those of the old volumes with the run's
+// overrides
+func volumeSummary(plan *MigrationPlan) string {
+	if !plan.KeepVolume {
+		return plan.VolumeSpec.String()
+	}
+	var overrides []string
+	if plan.VolumeSpec.IOPS > 0 {
+		overrides = append(overrides, fmt.Sprintf("%d IOPS", plan.VolumeSpec.IOPS))
+	}
+	if plan.VolumeSpec.Throughput > 0 {
+		overrides = append(overrides, fmt.Sprintf("%d MiB/s", plan.VolumeSpec.Throughput))
+	}
+	if len(overrides) == 0 {
+		return i18n.T("volume.kept")
+	}
+	return i18n.T("volume.kept_with", strings.Join(overrides, ", "))
+}
+
+// encryptionSummary describes how the new volumes of the plan are encrypted
+func encryptionSummary(plan *MigrationPlan) string {
+	switch {
+	case plan.KMSKeyID != "":
+		return i18n.T("encryption.run_key", plan.KMSKeyID)
+	case plan.Encryption == nil:
+		return i18n.T("encryption.unknown")
+	case plan.Encryption.ByDefault:
+		return i18n.T("encryption.by_default", plan.Encryption.KMSKeyID)
+	}
+	return i18n.T("encryption.off")
+}
+
+// formatStagedTime renders when a staged snapshot was taken, in UTC
+func formatStagedTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
 func padRight(s string, width int) string {
-	if len(s) >= width {
-		return s[:width]
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return string([]rune(s)[:width])
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// centerText pads s with leading spaces so it is centered in width columns
+func centerText(s string, width int) string {
+	pad := (width - lipgloss.Width(s)) / 2
+	if pad <= 0 {
+		return s
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return strings.Repeat(" ", pad) + s
 }
 
 func truncatePlan(s string, maxLen int) string {
@@ -1,8 +1,10 @@
 package migrator
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,6 +127,39 @@ func TestFormatPlan_MultipleItems(t *testing.T) {
 	assert.Contains(t, result, "✗")
 }
 
+func TestFormatPlanCompact(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		Items: []PVCPlanItem{
+			{
+				Name:        "monitoring/prometheus-server-data-volume-0",
+				Action:      PlanActionMigrate,
+				CurrentZone: "us-west-2b",
+				TargetZone:  "us-west-2a",
+				Capacity:    "100Gi",
+				VolumeID:    "vol-0123456789abcdef0",
+			},
+			{Name: "ns1/pvc-2", Action: PlanActionSkip, Reason: "Same zone"},
+		},
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Concurrency:  3,
+	}
+
+	result := FormatPlanCompact(plan)
+
+	assert.Contains(t, result, "monitoring/promethe...")
+	assert.Contains(t, result, "✓ → us-west-2a")
+	assert.Contains(t, result, "○ Skip")
+	assert.NotContains(t, result, "Will migrate")
+	assert.NotContains(t, result, "Skip (same AZ)")
+
+	for _, line := range strings.Split(result, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 80, "line too wide: %q", line)
+	}
+}
+
 func TestPadRight(t *testing.T) {
 	t.Parallel()
 
@@ -281,7 +316,7 @@ func TestRenderPlanTable(t *testing.T) {
 			t.Parallel()
 
 			// Call the actual package function
-			result := renderPlanTable(tc.plan)
+			result := renderPlanTable(tc.plan, defaultPlanLayout)
 
 			for _, want := range tc.wantContains {
 				assert.Contains(t, result, want)
@@ -0,0 +1,131 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// QuotaCheck is the EBS usage of the account in the region, what the run adds to
+// it and the quotas it is compared against. The run keeps the old volumes and
+// its snapshots, so all of it counts whatever the concurrency.
+type QuotaCheck struct {
+	Snapshots     int // Snapshots the account owns
+	NewSnapshots  int // Snapshots the run takes
+	SnapshotQuota int
+	Storage       []StorageQuotaCheck // One for each type of the new volumes, sorted by type
+}
+
+// StorageQuotaCheck is the storage of the volumes of one type, what the run adds
+// to it and the quota it is compared against
+type StorageQuotaCheck struct {
+	VolumeType    string
+	StorageGiB    int64 // Storage of the account's volumes of the type
+	NewStorageGiB int64 // Storage of the volumes of the type the run creates
+	QuotaGiB      int64
+}
+
+// Exceeded reports whether the run would take the account over the quota
+func (q StorageQuotaCheck) Exceeded() bool {
+	return q.StorageGiB+q.NewStorageGiB > q.QuotaGiB
+}
+
+// SnapshotsExceeded reports whether the run would take the account over its snapshot quota
+func (q QuotaCheck) SnapshotsExceeded() bool {
+	return q.Snapshots+q.NewSnapshots > q.SnapshotQuota
+}
+
+// StorageExceeded reports whether the run would take the account over the storage quota of any type
+func (q QuotaCheck) StorageExceeded() bool {
+	return slices.ContainsFunc(q.Storage, StorageQuotaCheck.Exceeded)
+}
+
+// String describes the quotas the run would exceed, as the reason of the plan
+// items it fails; empty when it stays within them
+func (q QuotaCheck) String() string {
+	var exceeded []string
+	if q.SnapshotsExceeded() {
+		exceeded = append(exceeded, fmt.Sprintf("%d snapshots owned plus %d new exceed the quota of %d", q.Snapshots, q.NewSnapshots, q.SnapshotQuota))
+	}
+	for _, s := range q.Storage {
+		if s.Exceeded() {
+			exceeded = append(exceeded, fmt.Sprintf("%dGiB of %s volumes plus %dGiB new exceed the quota of %dGiB", s.StorageGiB, s.VolumeType, s.NewStorageGiB, s.QuotaGiB))
+		}
+	}
+	if len(exceeded) == 0 {
+		return ""
+	}
+	return "EBS quota: " + strings.Join(exceeded, "; ")
+}
+
+// storageQuotaGiB returns the storage of the volumes of the type the account may
+// have, as given for the run or else the AWS default
+func (m *Migrator) storageQuotaGiB(volumeType string) int64 {
+	if tib := m.config.StorageQuotasTiB[volumeType]; tib > 0 {
+		return int64(tib) * 1024
+	}
+	return int64(aws.DefaultStorageQuotaTiB(volumeType)) * 1024
+}
+
+// checkQuotas compares the snapshots and storage the PVCs to migrate add with
+// the account's usage and EBS quotas, the storage for each type of their new
+// volumes. When the run would exceed one, every PVC to migrate is failed in the
+// plan and returned with the reason, by name, so the run fails before it starts
+// rather than midway. The usage failing to be read is logged and the check
+// skipped.
+func (m *Migrator) checkQuotas(ctx context.Context, items []PVCPlanItem) (*QuotaCheck, map[string]string) {
+	check := QuotaCheck{SnapshotQuota: aws.DefaultSnapshotQuota}
+	if m.config.SnapshotQuota > 0 {
+		check.SnapshotQuota = m.config.SnapshotQuota
+	}
+	newStorage := make(map[string]int64)
+	for _, item := range items {
+		// Resumed migrations have their snapshot and volume already
+		if item.Action != PlanActionMigrate || item.ResumeAt != StepPending {
+			continue
+		}
+		if item.StagedSnapshotID == "" {
+			check.NewSnapshots++
+		}
+		newStorage[item.VolumeSpec.VolumeType()] += int64(item.CapacityGi)
+	}
+	if len(newStorage) == 0 {
+		return nil, nil
+	}
+	volumeTypes := slices.Sorted(maps.Keys(newStorage))
+
+	usage, err := m.awsClient.EBSUsage(ctx, volumeTypes)
+	if err != nil {
+		slog.Warn("failed to read the EBS usage, not checking the quotas", "error", err)
+		return nil, nil
+	}
+	check.Snapshots = usage.Snapshots
+	for _, volumeType := range volumeTypes {
+		check.Storage = append(check.Storage, StorageQuotaCheck{
+			VolumeType:    volumeType,
+			StorageGiB:    usage.StorageGiB[volumeType],
+			NewStorageGiB: newStorage[volumeType],
+			QuotaGiB:      m.storageQuotaGiB(volumeType),
+		})
+	}
+
+	reason := check.String()
+	if reason == "" {
+		return &check, nil
+	}
+	failed := make(map[string]string)
+	for i := range items {
+		if items[i].Action != PlanActionMigrate {
+			continue
+		}
+		items[i].Action = PlanActionError
+		items[i].Reason = reason
+		failed[items[i].Name] = reason
+	}
+	return &check, failed
+}
@@ -0,0 +1,97 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGeneratePlan_CheckQuotas(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		checkQuotas   bool
+		snapshotQuota int
+		wantChecked   bool
+		wantAction    PlanAction
+		wantReason    string
+	}{
+		{name: "within the quotas", checkQuotas: true, snapshotQuota: 2, wantChecked: true, wantAction: PlanActionMigrate},
+		{name: "default quotas", checkQuotas: true, wantChecked: true, wantAction: PlanActionMigrate},
+		{
+			name:          "snapshot quota exceeded",
+			checkQuotas:   true,
+			snapshotQuota: 1,
+			wantChecked:   true,
+			wantAction:    PlanActionError,
+			wantReason:    "EBS quota: 0 snapshots owned plus 2 new exceed the quota of 1",
+		},
+		{name: "not checked", snapshotQuota: 1, wantAction: PlanActionMigrate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var objects []runtime.Object
+			objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+			objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+			m := newFakeMigrator(&Config{
+				PVCList:       []string{"db/data-0", "db/data-1"},
+				TargetZone:    "eu-west-1a",
+				CheckQuotas:   tt.checkQuotas,
+				SnapshotQuota: tt.snapshotQuota,
+			}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1b"}}, objects...)
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+			if !tt.wantChecked {
+				assert.Nil(t, plan.Quotas)
+			} else {
+				require.NotNil(t, plan.Quotas)
+				assert.Equal(t, 2, plan.Quotas.NewSnapshots)
+				assert.Equal(t, []StorageQuotaCheck{{VolumeType: "gp3", NewStorageGiB: 20, QuotaGiB: 50 * 1024}}, plan.Quotas.Storage)
+			}
+			for _, item := range plan.Items {
+				assert.Equal(t, tt.wantAction, item.Action, item.Name)
+				assert.Equal(t, tt.wantReason, item.Reason, item.Name)
+			}
+		})
+	}
+}
+
+func TestQuotaCheck_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		check QuotaCheck
+		want  string
+	}{
+		{
+			name:  "within",
+			check: QuotaCheck{Snapshots: 10, NewSnapshots: 5, SnapshotQuota: 15, Storage: []StorageQuotaCheck{{VolumeType: "gp3", StorageGiB: 100, NewStorageGiB: 50, QuotaGiB: 1024}}},
+		},
+		{
+			name:  "storage",
+			check: QuotaCheck{Snapshots: 10, NewSnapshots: 5, SnapshotQuota: 15, Storage: []StorageQuotaCheck{{VolumeType: "gp3", StorageGiB: 1000, NewStorageGiB: 50, QuotaGiB: 1024}}},
+			want:  "EBS quota: 1000GiB of gp3 volumes plus 50GiB new exceed the quota of 1024GiB",
+		},
+		{
+			name:  "both",
+			check: QuotaCheck{Snapshots: 11, NewSnapshots: 5, SnapshotQuota: 15, Storage: []StorageQuotaCheck{{VolumeType: "gp3", StorageGiB: 1000, NewStorageGiB: 50, QuotaGiB: 1024}}},
+			want:  "EBS quota: 11 snapshots owned plus 5 new exceed the quota of 15; 1000GiB of gp3 volumes plus 50GiB new exceed the quota of 1024GiB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.check.String())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package migrator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ordinalSuffix matches the replica ordinal StatefulSets append to claim names
+var ordinalSuffix = regexp.MustCompile(`-\d+$`)
+
+// Destination describes where PVCs are moved: the target zone, or the list of
+// zones they are spread across or that were picked for their namespaces
+func (c *Config) Destination() string {
+	if len(c.TargetZones) > 0 {
+		return strings.Join(c.TargetZones, ", ")
+	}
+	if c.autoZone() && len(c.autoZones) > 0 {
+		return strings.Join(c.autoZones, ", ")
+	}
+	return c.TargetZone
+}
+
+// fixedZone returns the zone every PVC is moved to, or "" when the plan assigns
+// each its own
+func (c *Config) fixedZone() string {
+	if c.autoZone() {
+		return ""
+	}
+	return c.TargetZone
+}
+
+// inTargetZone reports whether a volume in zone is already where it should be.
+// With TargetZoneAuto that depends on the zone picked for the PVC's namespace.
+func (c *Config) inTargetZone(zone string) bool {
+	if len(c.TargetZones) > 0 {
+		return slices.Contains(c.TargetZones, zone)
+	}
+	return zone == c.fixedZone()
+}
+
+// inTargetZone reports whether the PVC's volume in zone is already where it
+// should be, in the zone the plan picked for it with TargetZoneAuto
+func (m *Migrator) inTargetZone(pvcName, zone string) bool {
+	if !m.config.autoZone() {
+		return m.config.inTargetZone(zone)
+	}
+	target, err := m.targetZone(pvcName)
+	return err == nil && zone == target
+}
+
+// targetZone returns the zone a PVC is moved to: the one in the plan, else
+// TargetZone. When PVCs are spread across zones the plan must exist.
+func (m *Migrator) targetZone(pvcName string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if s, ok := m.statuses[pvcName]; ok && s.TargetZone != "" {
+		return s.TargetZone, nil
+	}
+	if len(m.config.TargetZones) == 0 && !m.config.autoZone() {
+		return m.config.TargetZone, nil
+	}
+	return "", fmt.Errorf("no target zone assigned; the plan must be generated first")
+}
+
+// replicaGroup returns the key shared by the claims of one StatefulSet's
+// replicas: the namespace and the claim name without its ordinal
+func replicaGroup(item PVCPlanItem) string {
+	return item.Namespace + "/" + ordinalSuffix.ReplaceAllString(item.PVCName, "")
+}
+
+// assignTargetZones spreads the PVCs to migrate across zones. Replicas of the
+// same StatefulSet go to the zone holding the fewest of them, counting those
+// that stay where they are, so they end up in different zones where possible.
+// Ties go to the zone with the fewest PVCs overall, then to the first listed.
+// PVCs already in one of the zones keep their zone, and those resumed from the
+// static PV of an earlier run keep the zone of its volume.
+func assignTargetZones(items []PVCPlanItem, zones []string) {
+	total := make(map[string]int)
+	perGroup := make(map[string]map[string]int)
+	count := func(item PVCPlanItem, zone string) {
+		group := replicaGroup(item)
+		if perGroup[group] == nil {
+			perGroup[group] = make(map[string]int)
+		}
+		perGroup[group][zone]++
+		total[zone]++
+	}
+
+	placed := func(item PVCPlanItem) bool {
+		return item.ResumeAt != StepPending && item.TargetZone != ""
+	}
+	for i := range items {
+		switch {
+		case items[i].Action == PlanActionSkip:
+			items[i].TargetZone = items[i].CurrentZone
+			count(items[i], items[i].CurrentZone)
+		case items[i].Action == PlanActionMigrate && placed(items[i]):
+			count(items[i], items[i].TargetZone)
+		}
+	}
+	for i := range items {
+		if items[i].Action != PlanActionMigrate || placed(items[i]) {
+			continue
+		}
+		group := perGroup[replicaGroup(items[i])]
+		best := zones[0]
+		for _, zone := range zones[1:] {
+			if group[zone] < group[best] || (group[zone] == group[best] && total[zone] < total[best]) {
+				best = zone
+			}
+		}
+		items[i].TargetZone = best
+		count(items[i], best)
+	}
+}
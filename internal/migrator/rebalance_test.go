@@ -0,0 +1,156 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestConfig_Destination(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "eu-west-1a", (&Config{TargetZone: "eu-west-1a"}).Destination())
+	assert.Equal(t, "eu-west-1a, eu-west-1b", (&Config{TargetZones: []string{"eu-west-1a", "eu-west-1b"}}).Destination())
+}
+
+func TestAssignTargetZones(t *testing.T) {
+	t.Parallel()
+
+	zones := []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}
+	migrate := func(name, current string) PVCPlanItem {
+		ns, pvc := ParsePVCName(name)
+		return PVCPlanItem{Name: name, Namespace: ns, PVCName: pvc, CurrentZone: current, Action: PlanActionMigrate}
+	}
+	skip := func(name, current string) PVCPlanItem {
+		item := migrate(name, current)
+		item.Action = PlanActionSkip
+		return item
+	}
+
+	cases := []struct {
+		name     string
+		items    []PVCPlanItem
+		expected map[string]string
+	}{
+		{
+			name: "replicas_spread_round_robin",
+			items: []PVCPlanItem{
+				migrate("db/data-pg-0", "eu-west-1d"),
+				migrate("db/data-pg-1", "eu-west-1d"),
+				migrate("db/data-pg-2", "eu-west-1d"),
+				migrate("db/data-pg-3", "eu-west-1d"),
+			},
+			expected: map[string]string{
+				"db/data-pg-0": "eu-west-1a",
+				"db/data-pg-1": "eu-west-1b",
+				"db/data-pg-2": "eu-west-1c",
+				"db/data-pg-3": "eu-west-1a",
+			},
+		},
+		{
+			name: "replicas_fill_the_zones_they_are_missing_from",
+			items: []PVCPlanItem{
+				skip("db/data-pg-0", "eu-west-1a"),
+				migrate("db/data-pg-1", "eu-west-1d"),
+				migrate("db/data-pg-2", "eu-west-1d"),
+			},
+			expected: map[string]string{
+				"db/data-pg-0": "eu-west-1a",
+				"db/data-pg-1": "eu-west-1b",
+				"db/data-pg-2": "eu-west-1c",
+			},
+		},
+		{
+			name: "resumed_replicas_keep_their_zone",
+			items: []PVCPlanItem{
+				func() PVCPlanItem {
+					item := migrate("db/data-pg-0", "eu-west-1d")
+					item.ResumeAt, item.TargetZone = StepCleanup, "eu-west-1b"
+					return item
+				}(),
+				migrate("db/data-pg-1", "eu-west-1d"),
+			},
+			expected: map[string]string{
+				"db/data-pg-0": "eu-west-1b",
+				"db/data-pg-1": "eu-west-1a",
+			},
+		},
+		{
+			name: "single_claims_balance_overall",
+			items: []PVCPlanItem{
+				skip("web/static", "eu-west-1a"),
+				migrate("logs/archive", "eu-west-1d"),
+				migrate("cache/redis", "eu-west-1d"),
+			},
+			expected: map[string]string{
+				"web/static":   "eu-west-1a",
+				"logs/archive": "eu-west-1b",
+				"cache/redis":  "eu-west-1c",
+			},
+		},
+		{
+			name: "errors_get_no_zone",
+			items: []PVCPlanItem{
+				{Name: "db/broken", Namespace: "db", PVCName: "broken", Action: PlanActionError},
+			},
+			expected: map[string]string{"db/broken": ""},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assignTargetZones(tc.items, zones)
+
+			got := make(map[string]string)
+			for _, item := range tc.items {
+				got[item.Name] = item.TargetZone
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestGeneratePlan_TargetZones(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+	objects = append(objects, boundClaim("db", "data-2", "vol-2")...)
+
+	m := newFakeMigrator(&Config{
+		PVCList:     []string{"db/data-0", "db/data-1", "db/data-2"},
+		TargetZones: []string{"eu-west-1a", "eu-west-1b"},
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b", "vol-1": "eu-west-1c", "vol-2": "eu-west-1c"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 3)
+
+	assert.Equal(t, "eu-west-1a, eu-west-1b", plan.TargetZone)
+	assert.Equal(t, PlanActionSkip, plan.Items[0].Action, "already in one of the zones")
+	assert.Equal(t, "eu-west-1a", plan.Items[1].TargetZone)
+	assert.Equal(t, "eu-west-1a", plan.Items[2].TargetZone, "ties go to the first zone")
+
+	zone, err := m.targetZone("db/data-1")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1a", zone, "the run follows the plan")
+}
+
+func TestMigrator_TargetZone_NoPlan(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0"}, TargetZones: []string{"eu-west-1a", "eu-west-1b"}}, nil, nil)
+	_, err := m.targetZone("db/data-0")
+	require.Error(t, err)
+
+	m = New(&Config{PVCList: []string{"db/data-0"}, TargetZone: "eu-west-1a"}, nil, nil)
+	zone, err := m.targetZone("db/data-0")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1a", zone)
+}
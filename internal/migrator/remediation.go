@@ -0,0 +1,174 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Remediation lists the manual commands that finish or roll back a failed PVC,
+// based on the step it reached. Comment lines (starting with #) explain each block.
+type Remediation struct {
+	PVC      string
+	FailedAt Step
+	Finish   []string
+	Rollback []string
+}
+
+// Remediations builds remediation commands for every failed PVC, sorted by name
+func (m *Migrator) Remediations() []Remediation {
+	statuses := m.GetStatuses()
+	names := make([]string, 0, len(statuses))
+	for name, s := range statuses {
+		if s.Step == StepFailed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := make([]Remediation, 0, len(names))
+	for _, name := range names {
+		result = append(result, BuildRemediation(statuses[name], m.config))
+	}
+	return result
+}
+
+// BuildRemediation returns the commands an operator runs to finish or undo the
+// migration of s by hand. Resources created before the failure are referenced by
+// their real IDs; later ones use the same placeholders as the runbook.
+func BuildRemediation(s *PVCStatus, cfg *Config) Remediation {
+	kctx := ""
+	if cfg.KubeContext != "" {
+		kctx = " --context=" + cfg.KubeContext
+	}
+	r := Remediation{PVC: s.Name, FailedAt: s.FailedStep}
+	targetZone := s.TargetZone
+	if targetZone == "" {
+		targetZone = cfg.TargetZone
+	}
+
+	item := PVCPlanItem{
+		Name:        s.Name,
+		Namespace:   s.Namespace,
+		PVCName:     s.PVCName,
+		PVName:      s.PVName,
+		VolumeID:    s.OldVolumeID,
+		Capacity:    s.Capacity,
+		CapacityGi:  s.SizeGiB,
+		CurrentZone: s.CurrentZone,
+		TargetZone:  targetZone,
+		KMSKeyID:    cfg.KMSKeyID,
+		Block:       s.Block,
+	}
+	snapshotID := orPlaceholder(s.SnapshotID, "<SNAPSHOT_ID>")
+	newVolumeID := orPlaceholder(s.NewVolumeID, "<NEW_VOLUME_ID>")
+	newPV := staticPVName(s.PVCName)
+
+	deleteSnapshot := "aws ec2 delete-snapshot --snapshot-id " + snapshotID
+	deleteVolume := "aws ec2 delete-volume --volume-id " + newVolumeID
+	deletePV := fmt.Sprintf("kubectl delete pv %s --ignore-not-found%s", newPV, kctx)
+
+	restartFrom := s.FailedStep
+	switch s.FailedStep {
+	case StepPending, StepGetInfo, StepSkipped, StepDone, StepFailed:
+		// Nothing was created yet: inspect the PVC and its volume, then re-run the tool
+		r.Finish = []string{
+			"# Nothing was changed. Check the PVC and its volume, then re-run the migration",
+			fmt.Sprintf("kubectl get pvc %s -n %s -o wide%s", s.PVCName, s.Namespace, kctx),
+		}
+		if s.OldVolumeID != "" {
+			r.Finish = append(r.Finish, "aws ec2 describe-volumes --volume-ids "+s.OldVolumeID)
+		}
+		return r
+	case StepSnapshot:
+		r.Finish = append(r.Finish,
+			"# Check whether a snapshot was created before the error",
+			fmt.Sprintf("aws ec2 describe-snapshots --owner-ids self --filters Name=volume-id,Values=%s Name=tag:MigratedPVC,Values=%s",
+				s.OldVolumeID, s.PVCName))
+		r.Rollback = []string{
+			"# Delete the snapshot found above, if any",
+			"aws ec2 delete-snapshot --snapshot-id <SNAPSHOT_ID>",
+		}
+	case StepWaitSnapshot:
+		r.Rollback = []string{deleteSnapshot}
+		if errors.Is(s.Error, ErrSnapshotFailed) {
+			// A snapshot in the error state never completes: replace it with a new one
+			r.Finish = append(r.Finish,
+				"# The snapshot is in the error state and will never complete. Delete it and start again",
+				deleteSnapshot)
+			restartFrom, snapshotID = StepSnapshot, "<SNAPSHOT_ID>"
+		}
+	case StepCreateVolume:
+		r.Rollback = []string{
+			"# Check for a volume created before the error and delete it",
+			fmt.Sprintf("aws ec2 describe-volumes --filters Name=snapshot-id,Values=%s Name=tag:MigratedPVC,Values=%s", snapshotID, s.PVCName),
+			deleteSnapshot,
+		}
+	case StepWaitVolume:
+		r.Rollback = []string{deleteVolume, deleteSnapshot}
+	case StepCreatePV:
+		r.Rollback = []string{deletePV, deleteVolume, deleteSnapshot}
+	case StepCleanup, StepCreatePVC:
+		if s.StaticPVRolledBack {
+			// The old claim was untouched and the new PV has been deleted again
+			r.Rollback = []string{deleteVolume, deleteSnapshot}
+			restartFrom = StepCreatePV
+			break
+		}
+		r.Rollback = []string{
+			fmt.Sprintf("# The old PVC may already be deleted: finish forward. The data is on %s in %s,", newVolumeID, targetZone),
+			fmt.Sprintf("# and the original volume %s is untouched in %s.", s.OldVolumeID, s.CurrentZone),
+		}
+	}
+
+	// Finish: every manual step from the one that failed onwards
+	started := false
+	for _, step := range manualSteps(item, cfg.StorageClassFor(s.Name), cfg.CSIDriver, kctx, snapshotID, newVolumeID) {
+		if step.step == restartFrom {
+			started = true
+		}
+		if !started {
+			continue
+		}
+		r.Finish = append(r.Finish, "# "+step.title)
+		r.Finish = append(r.Finish, step.commands...)
+	}
+	return r
+}
+
+// FormatRemediations renders remediation commands as a Markdown section suitable
+// for appending to the runbook
+func FormatRemediations(rs []Remediation) string {
+	if len(rs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Remediation for failed PVCs\n\n")
+	for _, r := range rs {
+		b.WriteString(fmt.Sprintf("### %s (failed at: %s)\n\n", r.PVC, r.FailedAt))
+		writeCommandBlock(&b, "Finish by hand", r.Finish)
+		writeCommandBlock(&b, "Roll back", r.Rollback)
+	}
+	return b.String()
+}
+
+func writeCommandBlock(b *strings.Builder, title string, commands []string) {
+	if len(commands) == 0 {
+		return
+	}
+	b.WriteString(title + ":\n\n```sh\n")
+	for _, c := range commands {
+		b.WriteString(c)
+		b.WriteString("\n")
+	}
+	b.WriteString("```\n\n")
+}
+
+func orPlaceholder(value, placeholder string) string {
+	if value == "" {
+		return placeholder
+	}
+	return value
+}
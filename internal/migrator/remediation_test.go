@@ -0,0 +1,182 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failedStatus(step Step) *PVCStatus {
+	s := &PVCStatus{
+		Name:        "db/data",
+		Namespace:   "db",
+		PVCName:     "data",
+		PVName:      "pvc-123",
+		OldVolumeID: "vol-old",
+		Capacity:    "10Gi",
+		SizeGiB:     10,
+		CurrentZone: "eu-west-1b",
+		Step:        StepFailed,
+		FailedStep:  step,
+	}
+	if step > StepSnapshot {
+		s.SnapshotID = "snap-1"
+	}
+	if step > StepCreateVolume {
+		s.NewVolumeID = "vol-new"
+	}
+	return s
+}
+
+func TestBuildRemediation(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{TargetZone: "eu-west-1a", StorageClass: "gp3", KubeContext: "prod"}
+
+	cases := []struct {
+		name          string
+		step          Step
+		wantFinish    []string
+		wantRollback  []string
+		emptyRollback bool
+	}{
+		{
+			name:          "get_info",
+			step:          StepGetInfo,
+			wantFinish:    []string{"kubectl get pvc data -n db -o wide --context=prod", "aws ec2 describe-volumes --volume-ids vol-old"},
+			emptyRollback: true,
+		},
+		{
+			name:         "snapshot",
+			step:         StepSnapshot,
+			wantFinish:   []string{"aws ec2 describe-snapshots", "aws ec2 create-snapshot --volume-id vol-old", "wait snapshot-completed --snapshot-ids <SNAPSHOT_ID>"},
+			wantRollback: []string{"aws ec2 delete-snapshot --snapshot-id <SNAPSHOT_ID>"},
+		},
+		{
+			name:         "wait_snapshot",
+			step:         StepWaitSnapshot,
+			wantFinish:   []string{"wait snapshot-completed --snapshot-ids snap-1", "create-volume --snapshot-id snap-1 --availability-zone eu-west-1a"},
+			wantRollback: []string{"aws ec2 delete-snapshot --snapshot-id snap-1"},
+		},
+		{
+			name:         "wait_volume",
+			step:         StepWaitVolume,
+			wantFinish:   []string{"wait volume-available --volume-ids vol-new", "volumeHandle: vol-new"},
+			wantRollback: []string{"aws ec2 delete-volume --volume-id vol-new", "aws ec2 delete-snapshot --snapshot-id snap-1"},
+		},
+		{
+			name:         "create_pv",
+			step:         StepCreatePV,
+			wantFinish:   []string{"kubectl apply --context=prod", "kubectl delete pv pvc-123 --grace-period=0 --context=prod"},
+			wantRollback: []string{"kubectl delete pv data-static --ignore-not-found --context=prod", "aws ec2 delete-volume --volume-id vol-new"},
+		},
+		{
+			name:         "cleanup",
+			step:         StepCleanup,
+			wantFinish:   []string{"kubectl delete pvc data -n db", "volumeName: data-static"},
+			wantRollback: []string{"finish forward", "vol-old is untouched in eu-west-1b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := BuildRemediation(failedStatus(tc.step), cfg)
+
+			assert.Equal(t, "db/data", r.PVC)
+			assert.Equal(t, tc.step, r.FailedAt)
+			finish := strings.Join(r.Finish, "\n")
+			for _, want := range tc.wantFinish {
+				assert.Contains(t, finish, want)
+			}
+			rollback := strings.Join(r.Rollback, "\n")
+			for _, want := range tc.wantRollback {
+				assert.Contains(t, rollback, want)
+			}
+			if tc.emptyRollback {
+				assert.Empty(t, r.Rollback)
+			}
+		})
+	}
+}
+
+func TestBuildRemediation_SkipsCompletedSteps(t *testing.T) {
+	t.Parallel()
+
+	r := BuildRemediation(failedStatus(StepCreatePVC), &Config{TargetZone: "eu-west-1a", StorageClass: "gp3"})
+
+	finish := strings.Join(r.Finish, "\n")
+	assert.NotContains(t, finish, "create-snapshot")
+	assert.NotContains(t, finish, "kind: PersistentVolume\n")
+	assert.Contains(t, finish, "kind: PersistentVolumeClaim")
+	assert.Contains(t, finish, "kubectl get pvc data -n db   # STATUS must be Bound")
+}
+
+func TestBuildRemediation_StaticPVRolledBack(t *testing.T) {
+	t.Parallel()
+
+	s := failedStatus(StepCleanup)
+	s.StaticPVRolledBack = true
+	r := BuildRemediation(s, &Config{TargetZone: "eu-west-1a", StorageClass: "gp3"})
+
+	assert.Contains(t, strings.Join(r.Finish, "\n"), "kind: PersistentVolume\n", "the PV is created again")
+	assert.Equal(t, []string{"aws ec2 delete-volume --volume-id vol-new", "aws ec2 delete-snapshot --snapshot-id snap-1"}, r.Rollback)
+}
+
+func TestMigrator_Remediations(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/b", "ns/a", "ns/ok"}, TargetZone: "eu-west-1a"}, nil, nil)
+	m.updateStatus("ns/b", StepSnapshot, 0, nil)
+	m.updateStatus("ns/b", StepFailed, 0, errors.New("throttled"))
+	m.updateStatus("ns/a", StepCreatePV, 0, nil)
+	m.updateStatus("ns/a", StepFailed, 0, errors.New("forbidden"))
+	m.updateStatus("ns/ok", StepDone, 100, nil)
+
+	rs := m.Remediations()
+	require.Len(t, rs, 2)
+	assert.Equal(t, "ns/a", rs[0].PVC)
+	assert.Equal(t, StepCreatePV, rs[0].FailedAt)
+	assert.Equal(t, "ns/b", rs[1].PVC)
+	assert.Equal(t, StepSnapshot, rs[1].FailedAt)
+}
+
+func TestFormatRemediations(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, FormatRemediations(nil))
+
+	out := FormatRemediations([]Remediation{{
+		PVC:      "db/data",
+		FailedAt: StepWaitVolume,
+		Finish:   []string{"# Wait", "aws ec2 wait volume-available --volume-ids vol-new"},
+		Rollback: []string{"aws ec2 delete-volume --volume-id vol-new"},
+	}})
+
+	assert.Contains(t, out, "## Remediation for failed PVCs")
+	assert.Contains(t, out, "### db/data (failed at: Volume Creating)")
+	assert.Contains(t, out, "Finish by hand:\n\n```sh\n# Wait\naws ec2 wait volume-available --volume-ids vol-new\n```")
+	assert.Contains(t, out, "Roll back:\n\n```sh\naws ec2 delete-volume --volume-id vol-new\n```")
+}
+
+func TestBuildRemediation_SnapshotInErrorState(t *testing.T) {
+	t.Parallel()
+
+	s := failedStatus(StepWaitSnapshot)
+	s.Error = fmt.Errorf("snapshot snap-1: %w", ErrSnapshotFailed)
+
+	r := BuildRemediation(s, &Config{TargetZone: "eu-west-1a", StorageClass: "gp3"})
+
+	finish := strings.Join(r.Finish, "\n")
+	assert.Contains(t, finish, "aws ec2 delete-snapshot --snapshot-id snap-1")
+	assert.Contains(t, finish, "aws ec2 create-snapshot --volume-id vol-old")
+	assert.Contains(t, finish, "wait snapshot-completed --snapshot-ids <SNAPSHOT_ID>")
+	assert.Contains(t, finish, "create-volume --snapshot-id <SNAPSHOT_ID>")
+	assert.NotContains(t, finish, "--snapshot-ids snap-1")
+	assert.Equal(t, []string{"aws ec2 delete-snapshot --snapshot-id snap-1"}, r.Rollback)
+}
@@ -0,0 +1,124 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// RestorePoint is what a run recorded about one PVC that RestorePVC needs to
+// move it back to the zone it was migrated out of
+type RestorePoint struct {
+	PVC            string // "namespace/name"
+	MigrationID    string
+	SnapshotID     string // Taken of SourceVolumeID by the run
+	SourceVolumeID string
+	Zone           string // Zone of SourceVolumeID
+}
+
+// RestorePoint returns the restore point of a PVC the run migrated. PVCs it
+// skipped, or that failed before their snapshot was taken, have none.
+func (r JournalRecord) RestorePoint(pvcName string) (RestorePoint, error) {
+	for _, p := range r.Result.PVCs {
+		if p.PVC != pvcName {
+			continue
+		}
+		if p.SnapshotID == "" || p.SourceVolumeID == "" || p.SourceZone == "" {
+			return RestorePoint{}, fmt.Errorf("run %s recorded no snapshot of PVC %s (%s at %s)", r.MigrationID, pvcName, p.Outcome, p.Step)
+		}
+		return RestorePoint{
+			PVC:            pvcName,
+			MigrationID:    r.MigrationID,
+			SnapshotID:     p.SnapshotID,
+			SourceVolumeID: p.SourceVolumeID,
+			Zone:           p.SourceZone,
+		}, nil
+	}
+	return RestorePoint{}, fmt.Errorf("PVC %s is not in run %s", pvcName, r.MigrationID)
+}
+
+// restoredPVName returns the name of the PV RestorePVC binds the claim to, other
+// than the one it is bound to now
+func restoredPVName(pvcName, currentPV string) string {
+	if name := pvcName + "-restored"; name != currentPV {
+		return name
+	}
+	return staticPVName(pvcName)
+}
+
+// RestorePVC creates a volume in the point's zone from the snapshot the run took
+// and switches the claim over to it, through the same steps as a migration. The
+// volume the claim is on now is kept, as its PV is deleted with the Retain
+// policy. The PVC must be in the migrator's list and its workloads scaled down.
+// It returns the new volume.
+func (m *Migrator) RestorePVC(ctx context.Context, point RestorePoint) (string, error) {
+	pvcName := point.PVC
+	m.mu.Lock()
+	status, ok := m.statuses[pvcName]
+	if !ok {
+		m.mu.Unlock()
+		return "", fmt.Errorf("PVC %s is not in the PVC list", pvcName)
+	}
+	status.StartTime = time.Now()
+	m.touch(status)
+	m.mu.Unlock()
+	namespace, shortName := ParsePVCName(pvcName)
+
+	ctx, root := tracer.Start(ctx, "restore PVC", trace.WithAttributes(
+		attribute.String("pvc.namespace", namespace),
+		attribute.String("pvc.name", shortName),
+		attribute.String("migration.id", point.MigrationID),
+		attribute.String("migration.target_zone", point.Zone),
+	))
+	spans := &stepSpans{tracer: tracer, root: ctx}
+	defer func() {
+		err := m.statusError(pvcName)
+		spans.end(err)
+		tracing.End(root, err)
+	}()
+
+	m.updateStatus(pvcName, StepGetInfo, 0, nil)
+	stepCtx := spans.start(StepGetInfo)
+	info, err := m.pvcInfo(stepCtx, namespace, shortName)
+	if err == nil && info.VolumeID == point.SourceVolumeID {
+		err = fmt.Errorf("PVC %s is on volume %s already", pvcName, point.SourceVolumeID)
+	}
+	if err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get info: %w", err))
+		return "", m.statusError(pvcName)
+	}
+	m.mu.Lock()
+	status.OldVolumeID = info.VolumeID
+	status.PVName = info.PVName
+	status.Capacity = info.Capacity
+	status.SizeGiB = info.CapacityGi
+	status.SnapshotID = point.SnapshotID
+	status.TargetZone = point.Zone
+	// The volume the snapshot was taken of may be gone, so only the run's
+	// overrides apply
+	status.VolumeSpec = m.volumeSpec(nil)
+	m.touch(status)
+	m.mu.Unlock()
+
+	// The snapshot is checked against the volume it was taken of, and the claim
+	// switched over from the volume it is on now
+	source := *info
+	source.VolumeID = point.SourceVolumeID
+	newPVName := restoredPVName(shortName, info.PVName)
+	newVolumeID, ok := m.provisionVolume(ctx, spans, root, pvcName, &source, point.SnapshotID, point.Zone, newPVName)
+	if !ok {
+		return "", m.statusError(pvcName)
+	}
+	if !m.switchClaim(ctx, spans, pvcName, info, newPVName, newVolumeID, true) {
+		return "", m.statusError(pvcName)
+	}
+	m.updateStatus(pvcName, StepDone, 100, nil)
+	slog.Info("PVC restored", "pvc", pvcName, "zone", point.Zone, "pv", newPVName, "volumeId", newVolumeID, "keptVolumeId", info.VolumeID)
+	return newVolumeID, nil
+}
@@ -0,0 +1,120 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+func TestJournalRecord_RestorePoint(t *testing.T) {
+	t.Parallel()
+
+	record := JournalRecord{
+		MigrationID: "20261016T120000Z-0a1b",
+		Result: apiv1.Result{PVCs: []apiv1.PVCResult{
+			{PVC: "shop/data", Outcome: apiv1.OutcomeMigrated, Step: "Done", SourceZone: "eu-west-1a", SourceVolumeID: "vol-old", SnapshotID: "snap-old"},
+			{PVC: "shop/cache", Outcome: apiv1.OutcomeFailed, Step: "Getting Info"},
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		pvc     string
+		want    RestorePoint
+		wantErr string
+	}{
+		{
+			name: "migrated",
+			pvc:  "shop/data",
+			want: RestorePoint{PVC: "shop/data", MigrationID: "20261016T120000Z-0a1b", SnapshotID: "snap-old", SourceVolumeID: "vol-old", Zone: "eu-west-1a"},
+		},
+		{
+			name:    "no_snapshot",
+			pvc:     "shop/cache",
+			wantErr: "run 20261016T120000Z-0a1b recorded no snapshot of PVC shop/cache",
+		},
+		{
+			name:    "not_in_run",
+			pvc:     "shop/logs",
+			wantErr: "PVC shop/logs is not in run 20261016T120000Z-0a1b",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			point, err := record.RestorePoint(tc.pvc)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, point)
+		})
+	}
+}
+
+func TestRestorePVC(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1a", "vol-new": "eu-west-1b", "vol-restored": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-restored"},
+	}
+	m := newFakeMigrator(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		StorageClass:   "gp3",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, ec2API, boundClaim("shop", "data", "vol-new")...)
+	ctx := context.Background()
+	point := RestorePoint{PVC: "shop/data", MigrationID: "20261016T120000Z-0a1b", SnapshotID: "snap-vol-old", SourceVolumeID: "vol-old", Zone: "eu-west-1a"}
+
+	volumeID, err := m.RestorePVC(ctx, point)
+	require.NoError(t, err)
+	assert.Equal(t, "vol-restored", volumeID)
+
+	info, err := m.k8sClient.GetPVCInfo(ctx, "shop", "data")
+	require.NoError(t, err)
+	assert.Equal(t, "data-restored", info.PVName)
+	assert.Equal(t, "vol-restored", info.VolumeID)
+	assert.Equal(t, "gp3", info.StorageClass)
+	pv, err := m.k8sClient.GetStaticPV(ctx, "data-restored")
+	require.NoError(t, err)
+	require.NotNil(t, pv)
+	assert.Equal(t, "eu-west-1a", pv.Zone)
+
+	status := m.GetStatuses()["shop/data"]
+	assert.Equal(t, StepDone, status.Step)
+	assert.Equal(t, "vol-new", status.OldVolumeID)
+	assert.Equal(t, "vol-restored", status.NewVolumeID)
+
+	// A PVC already on the point's volume has nothing to restore
+	_, err = m.RestorePVC(ctx, RestorePoint{PVC: "shop/data", SnapshotID: "snap-vol-restored", SourceVolumeID: "vol-restored", Zone: "eu-west-1a"})
+	require.ErrorContains(t, err, "PVC shop/data is on volume vol-restored already")
+}
+
+func TestRestorePVC_SnapshotOfAnotherVolume(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, &fakeEC2{zones: map[string]string{"vol-new": "eu-west-1b"}}, boundClaim("shop", "data", "vol-new")...)
+	ctx := context.Background()
+
+	_, err := m.RestorePVC(ctx, RestorePoint{PVC: "shop/data", SnapshotID: "snap-vol-other", SourceVolumeID: "vol-old", Zone: "eu-west-1a"})
+	require.ErrorContains(t, err, "snapshot snap-vol-other was taken of volume vol-other, not vol-old")
+
+	info, err := m.k8sClient.GetPVCInfo(ctx, "shop", "data")
+	require.NoError(t, err)
+	assert.Equal(t, "pv-data", info.PVName, "the claim is left alone")
+}
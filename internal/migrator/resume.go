@@ -0,0 +1,136 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// staticPVName returns the name of the PV the new volume of a PVC is bound through
+func staticPVName(pvcName string) string {
+	return pvcName + "-static"
+}
+
+// earlierStaticPV returns the static PV an earlier run created for the PVC and
+// left without a claim when it stopped, or nil when there is none. A static PV
+// bound to a claim is left out: the PVC is migrated already.
+func (m *Migrator) earlierStaticPV(ctx context.Context, pvcName string) *k8s.StaticPV {
+	_, shortName := ParsePVCName(pvcName)
+	pv, err := m.k8sClient.GetStaticPV(ctx, staticPVName(shortName))
+	if err != nil {
+		slog.Warn("failed to look up the static PV of an earlier run", "pvc", pvcName, "error", err)
+		return nil
+	}
+	if pv == nil || pv.Claim != "" || pv.VolumeID == "" {
+		return nil
+	}
+	return pv
+}
+
+// resumeStaticPV goes on from the static PV an earlier run left behind, once its
+// volume is checked to be available. at is StepCleanup while the old claim still
+// exists, and StepCreatePVC once the earlier run deleted it. The PV is only
+// trusted while the old volume is detached: once workloads mount it again the
+// new volume may miss writes.
+func (m *Migrator) resumeStaticPV(ctx context.Context, pvcName string, pv *k8s.StaticPV, at Step, oldVolume *aws.VolumeInfo) error {
+	if oldVolume != nil && oldVolume.State == "in-use" {
+		return fmt.Errorf("static PV %s of an earlier run exists, but volume %s is in use again and may have changed since; "+
+			"delete the PV to migrate from a new snapshot", pv.Name, oldVolume.VolumeID)
+	}
+	var volume *aws.VolumeInfo
+	err := m.retryStep(ctx, pvcName, StepGetInfo, func() (err error) {
+		volume, err = m.awsClient.GetVolumeInfo(ctx, pv.VolumeID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("volume of static PV %s: %w", pv.Name, err)
+	}
+	if volume.State != "available" {
+		return fmt.Errorf("volume %s of static PV %s is %s, not available", pv.VolumeID, pv.Name, volume.State)
+	}
+
+	slog.Info("resuming earlier run from its static PV", "pvc", pvcName, "pv", pv.Name, "volumeId", pv.VolumeID,
+		"zone", volume.AvailabilityZone, "step", at.String())
+	m.mu.Lock()
+	s := m.statuses[pvcName]
+	s.ResumedAt = at
+	s.NewVolumeID = pv.VolumeID
+	s.AdoptedVolume = true
+	s.TargetZone = volume.AvailabilityZone
+	if at == StepCreatePVC {
+		s.Capacity = pv.Capacity
+		s.SizeGiB = pv.CapacityGi
+		s.Block = pv.VolumeMode == corev1.PersistentVolumeBlock
+	}
+	m.touch(s)
+	m.mu.Unlock()
+	return nil
+}
+
+// resumeDeletedClaim goes on from the static PV of an earlier run that deleted
+// the old PVC before it stopped. The claim's size is read from the PV.
+func (m *Migrator) resumeDeletedClaim(ctx context.Context, pvcName string, pv *k8s.StaticPV) (*k8s.PVCInfo, string, bool) {
+	if m.config.DryRun {
+		slog.Info("dry run: would recreate PVC on the static PV of an earlier run", "pvc", pvcName, "pv", pv.Name)
+		m.updateStatus(pvcName, StepDone, 100, nil)
+		return nil, "", false
+	}
+	if err := m.resumeStaticPV(ctx, pvcName, pv, StepCreatePVC, nil); err != nil {
+		m.updateStatus(pvcName, StepFailed, 0, fmt.Errorf("get info: %w", err))
+		return nil, "", false
+	}
+	return &k8s.PVCInfo{Capacity: pv.Capacity, CapacityGi: pv.CapacityGi, VolumeMode: pv.VolumeMode}, "", true
+}
+
+// migrationSnapshot returns the ID of a snapshot an earlier run with another
+// migration ID took of the volume, or "". It is only adopted when the volume's
+// write metrics show it was not written after the snapshot started, so it needs
+// CheckWriteActivity; without it writes cannot be ruled out.
+func (m *Migrator) migrationSnapshot(ctx context.Context, pvcName string, volume *aws.VolumeInfo) string {
+	if volume.State == "in-use" {
+		return ""
+	}
+	_, shortName := ParsePVCName(pvcName)
+	snap, err := m.awsClient.FindMigrationSnapshot(ctx, volume.VolumeID, shortName)
+	if err != nil {
+		slog.Warn("failed to look up snapshots of earlier runs, taking a new one", "pvc", pvcName, "error", err)
+		return ""
+	}
+	if snap == nil {
+		return ""
+	}
+	if !m.config.CheckWriteActivity {
+		slog.Info("found a snapshot of an earlier run, taking a new one as writes since cannot be ruled out without checking write activity",
+			"pvc", pvcName, "snapshotId", snap.SnapshotID, "started", snap.StartTime)
+		return ""
+	}
+	lastWrite, err := m.awsClient.LastWrite(ctx, volume.VolumeID, snap.StartTime, true)
+	if err != nil {
+		slog.Warn("failed to check writes since the snapshot of an earlier run, taking a new one", "pvc", pvcName, "error", err)
+		return ""
+	}
+	if lastWrite.After(snap.StartTime) {
+		slog.Info("volume written after the snapshot of an earlier run, taking a new one", "pvc", pvcName,
+			"snapshotId", snap.SnapshotID, "lastWrite", lastWrite)
+		return ""
+	}
+	slog.Info("adopting snapshot of an earlier run", "pvc", pvcName, "snapshotId", snap.SnapshotID, "started", snap.StartTime)
+	return snap.SnapshotID
+}
+
+// migratedVolume returns the ID of a volume an earlier run created for the PVC
+// from the snapshot in the target zone, whatever its migration ID, or ""
+func (m *Migrator) migratedVolume(ctx context.Context, pvcName, snapshotID, targetZone string) string {
+	namespace, shortName := ParsePVCName(pvcName)
+	volumeID, err := m.awsClient.FindMigratedVolume(ctx, snapshotID, targetZone, namespace, shortName)
+	if err != nil {
+		slog.Warn("failed to look up volumes of earlier runs, creating a new one", "pvc", pvcName, "error", err)
+		return ""
+	}
+	return volumeID
+}
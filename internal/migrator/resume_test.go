@@ -0,0 +1,172 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestRun_ResumesFromStaticPV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		zones     map[string]string
+		attached  map[string]bool
+		wantAt    Step
+		wantError string
+	}{
+		{
+			name:    "old claim still bound",
+			objects: boundClaim("db", "data", "vol-old"),
+			zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+			wantAt:  StepCleanup,
+		},
+		{
+			name:   "old claim deleted",
+			zones:  map[string]string{"vol-new": "eu-west-1a"},
+			wantAt: StepCreatePVC,
+		},
+		{
+			name:      "old volume in use again",
+			objects:   boundClaim("db", "data", "vol-old"),
+			zones:     map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+			attached:  map[string]bool{"vol-old": true},
+			wantError: "is in use again",
+		},
+		{
+			name:      "new volume gone",
+			wantError: "volume of static PV data-static",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ec2API := &fakeEC2{zones: tt.zones, attached: tt.attached}
+			clientset := bindingClientset(tt.objects...)
+			m := New(&Config{
+				PVCList:        []string{"db/data"},
+				TargetZone:     "eu-west-1a",
+				MaxConcurrency: 1,
+				StepRetry:      RetryPolicy{MaxAttempts: 1},
+			}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(ec2API))
+			ctx := context.Background()
+			require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+
+			m.Run(ctx)
+
+			s := m.GetStatuses()["db/data"]
+			assert.Empty(t, ec2API.snapshotTags(), "no snapshot is taken")
+			if tt.wantError != "" {
+				require.Equal(t, StepFailed, s.Step)
+				assert.Contains(t, s.Error.Error(), tt.wantError)
+				return
+			}
+			require.Equal(t, StepDone, s.Step, "error: %v", s.Error)
+			assert.Equal(t, tt.wantAt, s.ResumedAt)
+			assert.Equal(t, "vol-new", s.NewVolumeID)
+			assert.True(t, s.AdoptedVolume)
+
+			pvc, err := clientset.CoreV1().PersistentVolumeClaims("db").Get(ctx, "data", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "data-static", pvc.Spec.VolumeName)
+		})
+	}
+}
+
+func TestRun_AdoptsVolumeOfEarlierRun(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		staged:  map[string]time.Time{"vol-old": time.Now().Add(-time.Hour)},
+		volumes: map[string]string{"snap-staged-vol-old": "vol-new"},
+	}
+	m := newFakeMigrator(&Config{
+		PVCList:              []string{"db/data"},
+		TargetZone:           "eu-west-1a",
+		MaxConcurrency:       1,
+		StagedSnapshotMaxAge: 24 * time.Hour,
+	}, ec2API, boundClaim("db", "data", "vol-old")...)
+
+	// The fake cannot create volumes, so the PVC only completes with the earlier one
+	m.Run(context.Background())
+
+	s := m.GetStatuses()["db/data"]
+	require.Equal(t, StepDone, s.Step, "error: %v", s.Error)
+	assert.Equal(t, "vol-new", s.NewVolumeID)
+	assert.True(t, s.AdoptedVolume)
+	assert.Equal(t, StepPending, s.ResumedAt)
+}
+
+func TestRun_MigrationSnapshotNeedsWriteCheck(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{
+		zones:    map[string]string{"vol-0": "eu-west-1b"},
+		migrated: map[string]string{"vol-0": "snap-earlier"},
+	}
+	m := newFakeMigrator(&Config{
+		PVCList:        []string{"db/data-0"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+	}, ec2API, boundClaim("db", "data-0", "vol-0")...)
+
+	m.Run(context.Background())
+
+	s := m.GetStatuses()["db/data-0"]
+	assert.Equal(t, "snap-vol-0", s.SnapshotID, "writes since the earlier snapshot cannot be ruled out")
+	assert.False(t, s.AdoptedSnapshot)
+	assert.Len(t, ec2API.snapshotTags(), 1)
+}
+
+func TestGeneratePlan_ResumesFromStaticPV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		wantAt     Step
+		wantVolume string
+	}{
+		{name: "old claim still bound", objects: boundClaim("db", "data", "vol-old"), wantAt: StepCleanup, wantVolume: "vol-old"},
+		{name: "old claim deleted", wantAt: StepCreatePVC, wantVolume: "vol-new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ec2API := &fakeEC2{zones: map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1c"}}
+			m := newFakeMigrator(&Config{
+				PVCList:        []string{"db/data"},
+				TargetZones:    []string{"eu-west-1a", "eu-west-1c"},
+				MaxConcurrency: 1,
+			}, ec2API, tt.objects...)
+			ctx := context.Background()
+			require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-static", "vol-new", "10Gi", "gp3", "eu-west-1c", corev1.PersistentVolumeFilesystem))
+
+			plan, err := m.GeneratePlan(ctx)
+			require.NoError(t, err)
+
+			require.Len(t, plan.Items, 1)
+			item := plan.Items[0]
+			assert.Equal(t, PlanActionMigrate, item.Action, item.Reason)
+			assert.Equal(t, tt.wantAt, item.ResumeAt)
+			assert.Equal(t, tt.wantVolume, item.VolumeID)
+			assert.Equal(t, "eu-west-1c", item.TargetZone, "the zone of the static PV")
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package migrator
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// Retryable reports whether the PVC failed before its old PVC and PV were touched,
+// so migrating it again from the start is safe
+func (s *PVCStatus) Retryable() bool {
+	if s.Step != StepFailed {
+		return false
+	}
+	switch s.FailedStep {
+	case StepGetInfo, StepSnapshot, StepWaitSnapshot, StepCreateVolume, StepWaitVolume, StepCreatePV:
+		return true
+	case StepPending, StepSkipped, StepCleanup, StepCreatePVC, StepDone, StepFailed:
+		return false
+	}
+	return false
+}
+
+// RetryableFailures returns the names of the failed PVCs that can be retried, sorted
+func (m *Migrator) RetryableFailures() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var names []string
+	for name, s := range m.statuses {
+		if s.Retryable() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RetryFailed migrates the retryable failed PVCs again and returns how many were
+// retried. A snapshot that completed in the failed attempt is reused, and so is
+// a volume created from it.
+func (m *Migrator) RetryFailed(ctx context.Context) int {
+	names, events, left := m.resetForRetry()
+	if len(names) == 0 {
+		return 0
+	}
+	for _, e := range events {
+		m.notify(e)
+	}
+	for _, name := range names {
+		if volumeID, ok := left[name]; ok {
+			m.AddWarning(m.leftVolumeWarning(ctx, name, volumeID))
+		}
+	}
+	slog.Info("retrying failed PVCs", "pvcs", names)
+	m.runEach(ctx, "migration retry", names, m.migratePVC)
+	return len(names)
+}
+
+// resetForRetry puts the retryable failed PVCs back to pending and marks the run as
+// not done, in one step so watchers never see it done in between. It returns the
+// PVCs reset, their events and the volumes the failed attempts left behind that
+// the retries do not adopt, by PVC.
+func (m *Migrator) resetForRetry() ([]string, []Event, map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var names []string
+	var events []Event
+	left := make(map[string]string)
+	for name, s := range m.statuses {
+		if !s.Retryable() {
+			continue
+		}
+		names = append(names, name)
+
+		// The snapshot completed once the failed attempt got past waiting for it.
+		// A volume created from it is adopted: its client token is unchanged, so
+		// CreateVolume would return it anyway.
+		reused := false
+		switch s.FailedStep {
+		case StepCreateVolume, StepWaitVolume, StepCreatePV:
+			if s.SnapshotID != "" {
+				if m.retrySnapshots == nil {
+					m.retrySnapshots = make(map[string]string)
+				}
+				m.retrySnapshots[name] = s.SnapshotID
+				reused = true
+			}
+		case StepPending, StepGetInfo, StepSkipped, StepSnapshot, StepWaitSnapshot,
+			StepCleanup, StepCreatePVC, StepDone, StepFailed:
+		}
+		switch {
+		case s.NewVolumeID != "" && reused:
+			if m.retryVolumes == nil {
+				m.retryVolumes = make(map[string]string)
+			}
+			m.retryVolumes[name] = s.NewVolumeID
+		case s.NewVolumeID != "":
+			left[name] = s.NewVolumeID
+		}
+
+		s.Step = StepPending
+		s.FailedStep = StepPending
+		s.Progress = 0
+		s.Error = nil
+		s.StartTime = time.Time{}
+		s.EndTime = time.Time{}
+		s.NewVolumeID = ""
+		m.touch(s)
+		events = append(events, newEvent(s))
+	}
+	if len(names) > 0 {
+		m.done = false
+	}
+	sort.Strings(names)
+	return names, events, left
+}
+
+// leftVolumeWarning returns the warning about a volume a failed attempt created.
+// The attempt may have created a static PV for it too, so deleting the volume is
+// only advised when no PV references it.
+func (m *Migrator) leftVolumeWarning(ctx context.Context, pvcName, volumeID string) Warning {
+	w := Warning{PVC: pvcName, Message: i18n.T("warn.retry_volume_left", volumeID)}
+	pvs, err := m.k8sClient.VolumePVs(ctx, volumeID)
+	switch {
+	case err != nil:
+		w.Action = i18n.T("warn.retry_volume_check", err)
+	case len(pvs) > 0:
+		w.Action = i18n.T("warn.retry_volume_pvs", strings.Join(pvs, ", "))
+	default:
+		w.Action = i18n.T("warn.retry_volume_action") + "\naws ec2 delete-volume --volume-id " + volumeID
+	}
+	return w
+}
+
+// notify calls the listeners with an event; the caller must not hold m.mu
+func (m *Migrator) notify(e Event) {
+	m.mu.RLock()
+	listeners := m.listeners
+	m.mu.RUnlock()
+	for _, l := range listeners {
+		l(e)
+	}
+}
+
+// retrySnapshot returns, and forgets, the snapshot a retried PVC starts from
+func (m *Migrator) retrySnapshot(pvcName string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.retrySnapshots[pvcName]
+	delete(m.retrySnapshots, pvcName)
+	return id
+}
+
+// retryVolume returns, and forgets, the volume a failed attempt created for a
+// retried PVC, or "" when there is none to adopt. A volume that is no longer
+// creating or available is reported instead, and the PVC's next volume gets a
+// new client token, as the old one would keep returning the failed volume.
+func (m *Migrator) retryVolume(ctx context.Context, pvcName string) string {
+	m.mu.Lock()
+	volumeID := m.retryVolumes[pvcName]
+	delete(m.retryVolumes, pvcName)
+	m.mu.Unlock()
+	if volumeID == "" {
+		return ""
+	}
+
+	var state string
+	err := m.retryStep(ctx, pvcName, StepCreateVolume, func() (err error) {
+		state, err = m.awsClient.GetVolumeState(ctx, volumeID)
+		return err
+	})
+	switch {
+	case err != nil:
+		// CreateVolume returns the volume of the unchanged client token anyway
+		slog.Warn("failed to get the state of the failed attempt's volume", "pvc", pvcName, "volumeId", volumeID, "error", err)
+		return ""
+	case state == "creating" || state == "available":
+		slog.Info("adopting volume of failed attempt", "pvc", pvcName, "volumeId", volumeID, "state", state)
+		return volumeID
+	}
+
+	slog.Warn("volume of failed attempt is not usable, creating a new one", "pvc", pvcName, "volumeId", volumeID, "state", state)
+	m.mu.Lock()
+	if m.volumeAttempts == nil {
+		m.volumeAttempts = make(map[string]int)
+	}
+	m.volumeAttempts[pvcName]++
+	m.mu.Unlock()
+	m.AddWarning(m.leftVolumeWarning(ctx, pvcName, volumeID))
+	return ""
+}
@@ -0,0 +1,241 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestPVCStatus_Retryable(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		step       Step
+		failedStep Step
+		want       bool
+	}{
+		{name: "get_info", step: StepFailed, failedStep: StepGetInfo, want: true},
+		{name: "wait_snapshot", step: StepFailed, failedStep: StepWaitSnapshot, want: true},
+		{name: "create_pv", step: StepFailed, failedStep: StepCreatePV, want: true},
+		{name: "cleanup", step: StepFailed, failedStep: StepCleanup, want: false},
+		{name: "create_pvc", step: StepFailed, failedStep: StepCreatePVC, want: false},
+		{name: "done", step: StepDone, want: false},
+		{name: "in_progress", step: StepWaitVolume, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := &PVCStatus{Step: tc.step, FailedStep: tc.failedStep}
+			assert.Equal(t, tc.want, s.Retryable())
+		})
+	}
+}
+
+func TestRetryFailed_ReusesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+	m := newFakeMigrator(&Config{
+		PVCList:        []string{"db/data-0"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+	}, ec2API, boundClaim("db", "data-0", "vol-0")...)
+
+	// The fake cannot create volumes, so the snapshot completes and the PVC fails after it
+	m.Run(context.Background())
+	s := m.GetStatuses()["db/data-0"]
+	require.Equal(t, StepFailed, s.Step)
+	require.Equal(t, StepCreateVolume, s.FailedStep)
+	require.Equal(t, []string{"db/data-0"}, m.RetryableFailures())
+
+	var mu sync.Mutex
+	var steps []string
+	m.AddListener(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, e.Step)
+	})
+
+	assert.Equal(t, 1, m.RetryFailed(context.Background()))
+	assert.True(t, m.IsDone())
+
+	s = m.GetStatuses()["db/data-0"]
+	assert.Equal(t, StepCreateVolume, s.FailedStep, "fails again at the same step")
+	assert.Equal(t, "snap-vol-0", s.SnapshotID)
+	assert.Len(t, ec2API.snapshotTags(), 1, "the first attempt's snapshot is reused")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, steps)
+	assert.Equal(t, StepPending.String(), steps[0], "listeners see the PVC reset")
+	assert.NotContains(t, steps, StepSnapshot.String())
+}
+
+func TestRetryFailed_NothingToRetry(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0", "db/data-1"}, MaxConcurrency: 1}, nil, nil)
+	m.statuses["db/data-0"].Step = StepDone
+	m.statuses["db/data-1"].Step = StepFailed
+	m.statuses["db/data-1"].FailedStep = StepCleanup
+	m.done = true
+
+	assert.Empty(t, m.RetryableFailures())
+	assert.Zero(t, m.RetryFailed(context.Background()))
+	assert.True(t, m.IsDone())
+}
+
+func TestResetForRetry(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0", "db/data-1"}, MaxConcurrency: 1}, nil, nil)
+	s := m.statuses["db/data-0"]
+	s.Step = StepFailed
+	s.FailedStep = StepWaitVolume
+	s.Error = errors.New("volume creation failed")
+	s.SnapshotID = "snap-1"
+	s.NewVolumeID = "vol-new"
+	early := m.statuses["db/data-1"]
+	early.Step = StepFailed
+	early.FailedStep = StepWaitSnapshot
+	early.SnapshotID = "snap-2"
+	m.done = true
+
+	names, events, left := m.resetForRetry()
+
+	assert.Equal(t, []string{"db/data-0", "db/data-1"}, names)
+	assert.Len(t, events, 2)
+	assert.False(t, m.IsDone())
+
+	assert.Equal(t, StepPending, s.Step)
+	require.NoError(t, s.Error)
+	assert.Empty(t, s.NewVolumeID)
+	assert.Equal(t, "snap-1", m.retrySnapshot("db/data-0"))
+	assert.Empty(t, m.retrySnapshot("db/data-0"), "a snapshot is reused once")
+	assert.Empty(t, m.retrySnapshot("db/data-1"), "the snapshot may not have completed")
+
+	assert.Empty(t, left, "the retry adopts the volume")
+	m.mu.RLock()
+	assert.Equal(t, map[string]string{"db/data-0": "vol-new"}, m.retryVolumes)
+	m.mu.RUnlock()
+}
+
+func TestRetryFailed_AdoptsVolume(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-new"},
+	}
+	clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+	// The first attempt fails to create the PV after creating the volume
+	failed := false
+	clientset.PrependReactor("create", "persistentvolumes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failed {
+			return false, nil, nil
+		}
+		failed = true
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumes"}, "data-static", errors.New("denied"))
+	})
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(ec2API))
+
+	m.Run(context.Background())
+	s := m.GetStatuses()["shop/data"]
+	require.Equal(t, StepCreatePV, s.FailedStep)
+	require.Equal(t, "vol-new", s.NewVolumeID)
+
+	assert.Equal(t, 1, m.RetryFailed(context.Background()))
+
+	s = m.GetStatuses()["shop/data"]
+	require.NoError(t, s.Error)
+	assert.Equal(t, StepDone, s.Step)
+	assert.Equal(t, "vol-new", s.NewVolumeID)
+	assert.True(t, s.AdoptedVolume)
+	assert.Len(t, ec2API.newVolumes, 1, "the volume is not created again")
+
+	pv, err := clientset.CoreV1().PersistentVolumes().Get(context.Background(), "data-static", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "vol-new", pv.Spec.CSI.VolumeHandle)
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims("shop").Get(context.Background(), "data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "data-static", pvc.Spec.VolumeName)
+
+	for _, w := range m.Warnings() {
+		assert.NotContains(t, w.Action, "delete-volume")
+	}
+}
+
+func TestRetryFailed_ErroredVolume(t *testing.T) {
+	t.Parallel()
+
+	ec2API := &fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-bad": "eu-west-1a", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-bad"},
+		errored: map[string]bool{"vol-bad": true},
+	}
+	clientset := bindingClientset(boundClaim("shop", "data", "vol-old")...)
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(ec2API))
+
+	m.Run(context.Background())
+	require.Equal(t, StepWaitVolume, m.GetStatuses()["shop/data"].FailedStep)
+
+	ec2API.created["snap-vol-old"] = "vol-new"
+	assert.Equal(t, 1, m.RetryFailed(context.Background()))
+
+	s := m.GetStatuses()["shop/data"]
+	require.NoError(t, s.Error)
+	assert.Equal(t, StepDone, s.Step)
+	assert.Equal(t, "vol-new", s.NewVolumeID)
+
+	require.Len(t, ec2API.newVolumes, 2)
+	assert.NotEqual(t, awssdk.ToString(ec2API.newVolumes[0].ClientToken), awssdk.ToString(ec2API.newVolumes[1].ClientToken),
+		"the failed volume's token is not reused")
+
+	warnings := m.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Message, "vol-bad")
+	assert.Contains(t, warnings[0].Action, "aws ec2 delete-volume --volume-id vol-bad")
+}
+
+func TestLeftVolumeWarning(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{MaxConcurrency: 1}, &fakeEC2{})
+	ctx := context.Background()
+	require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-0-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+
+	w := m.leftVolumeWarning(ctx, "db/data-0", "vol-new")
+	assert.Equal(t, "db/data-0", w.PVC)
+	assert.Contains(t, w.Message, "vol-new")
+	assert.Contains(t, w.Action, "data-0-static")
+	assert.NotContains(t, w.Action, "delete-volume", "the static PV still references the volume")
+
+	w = m.leftVolumeWarning(ctx, "db/data-1", "vol-other")
+	assert.Contains(t, w.Action, "aws ec2 delete-volume --volume-id vol-other")
+}
@@ -0,0 +1,541 @@
+package migrator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// RunbookOptions controls how the fallback commands in a runbook are rendered
+type RunbookOptions struct {
+	KubeContext string    // Appended to kubectl commands as --context when set
+	GeneratedAt time.Time // Timestamp printed in the runbook header
+
+	// Workloads are the running workloads per namespace. They are scaled to zero
+	// before the migration and back to their replica counts afterwards.
+	Workloads map[string][]k8s.WorkloadInfo
+	// ArgoCDApps are the applications whose auto-sync is disabled during the run
+	ArgoCDApps []k8s.ArgoCDAppInfo
+	// ApplicationSets are the ArgoCD ApplicationSets made create-only during the run
+	ApplicationSets []k8s.ApplicationSetInfo
+	// ScaledObjects are the KEDA ScaledObjects paused during the run
+	ScaledObjects []k8s.ScaledObjectInfo
+	// FluxResources are the Flux objects suspended during the run
+	FluxResources []k8s.FluxResourceInfo
+}
+
+// ScaleCommand returns the kubectl command that scales a workload to replicas
+func ScaleCommand(w k8s.WorkloadInfo, namespace string, replicas int32, kubeContext string) string {
+	return fmt.Sprintf("kubectl scale %s %s --replicas=%d -n %s%s", strings.ToLower(w.Kind), w.Name, replicas, namespace, contextFlag(kubeContext))
+}
+
+// ArgoCDDisableCommand returns the command that turns off auto-sync for app the
+// way its strategy says
+func ArgoCDDisableCommand(app k8s.ArgoCDAppInfo, kubeContext string) string {
+	switch app.Strategy {
+	case k8s.ArgoCDStrategySyncWindow:
+		return fmt.Sprintf("argocd proj windows add %s --kind deny --schedule '* * * * *' --duration 24h --applications %s",
+			app.ProjectName(), app.Name)
+	case k8s.ArgoCDStrategyAnnotation:
+		return fmt.Sprintf("kubectl annotate application %s -n %s %s=true --overwrite%s",
+			app.Name, app.Namespace, k8s.ArgoCDSkipReconcileAnnotation, contextFlag(kubeContext))
+	}
+	return fmt.Sprintf("kubectl patch application %s -n %s --type=json -p '[{\"op\":\"remove\",\"path\":\"/spec/syncPolicy/automated\"}]'%s",
+		app.Name, app.Namespace, contextFlag(kubeContext))
+}
+
+// ArgoCDEnableCommand returns the command that restores app's original automated
+// sync policy, or lifts what its strategy put in place
+func ArgoCDEnableCommand(app k8s.ArgoCDAppInfo, kubeContext string) string {
+	switch app.Strategy {
+	case k8s.ArgoCDStrategySyncWindow:
+		// Windows are deleted by their position in the list
+		return fmt.Sprintf("argocd proj windows list %s  # then delete the deny window of %s: argocd proj windows delete %s <ID>",
+			app.ProjectName(), app.Name, app.ProjectName())
+	case k8s.ArgoCDStrategyAnnotation:
+		return fmt.Sprintf("kubectl annotate application %s -n %s %s-%s",
+			app.Name, app.Namespace, k8s.ArgoCDSkipReconcileAnnotation, contextFlag(kubeContext))
+	}
+	policy := string(app.AutoSyncPolicy)
+	if policy == "" || policy == "null" {
+		policy = "{}"
+	}
+	return fmt.Sprintf("kubectl patch application %s -n %s --type=merge -p '{\"spec\":{\"syncPolicy\":{\"automated\":%s}}}'%s",
+		app.Name, app.Namespace, policy, contextFlag(kubeContext))
+}
+
+// ApplicationSetPauseCommand returns the kubectl command that stops an
+// ApplicationSet from updating its applications
+func ApplicationSetPauseCommand(set k8s.ApplicationSetInfo, kubeContext string) string {
+	return fmt.Sprintf("kubectl patch applicationset %s -n %s --type=merge -p '{\"spec\":{\"syncPolicy\":{\"applicationsSync\":\"%s\"}}}'%s",
+		set.Name, set.Namespace, k8s.ArgoCDAppSetCreateOnly, contextFlag(kubeContext))
+}
+
+// ApplicationSetResumeCommand returns the kubectl command that restores the
+// applicationsSync policy an ApplicationSet had before the run
+func ApplicationSetResumeCommand(set k8s.ApplicationSetInfo, kubeContext string) string {
+	policy := "null"
+	if set.ApplicationsSync != "" {
+		policy = `"` + set.ApplicationsSync + `"`
+	}
+	return fmt.Sprintf("kubectl patch applicationset %s -n %s --type=merge -p '{\"spec\":{\"syncPolicy\":{\"applicationsSync\":%s}}}'%s",
+		set.Name, set.Namespace, policy, contextFlag(kubeContext))
+}
+
+// ApplicationSetNote returns the guidance for an application whose
+// ApplicationSet reverts the changes made to it and is not paused
+func ApplicationSetNote(app k8s.ArgoCDAppInfo) string {
+	return fmt.Sprintf("%s/%s is owned by ApplicationSet %s, which reverts this change. Make it %s for the run, "+
+		"or use --argocd-appsets pause or --argocd-strategy sync-window.",
+		app.Namespace, app.Name, app.ApplicationSet, k8s.ArgoCDAppSetCreateOnly)
+}
+
+// unpausedApplicationSetApps returns the applications owned by an ApplicationSet
+// that is not paused and reverts the changes made to them
+func unpausedApplicationSetApps(apps []k8s.ArgoCDAppInfo, paused []k8s.ApplicationSetInfo) []k8s.ArgoCDAppInfo {
+	var result []k8s.ArgoCDAppInfo
+	for _, app := range apps {
+		if !app.UsesApplicationSet() || slices.ContainsFunc(paused, func(set k8s.ApplicationSetInfo) bool {
+			return set.Name == app.ApplicationSet && set.Namespace == app.Namespace
+		}) {
+			continue
+		}
+		result = append(result, app)
+	}
+	return result
+}
+
+// FluxSuspendCommand returns the kubectl command that suspends a Flux object
+func FluxSuspendCommand(res k8s.FluxResourceInfo, kubeContext string) string {
+	return fmt.Sprintf("kubectl patch %s %s -n %s --type=merge -p '{\"spec\":{\"suspend\":true}}'%s",
+		fluxResource(res), res.Name, res.Namespace, contextFlag(kubeContext))
+}
+
+// FluxResumeCommand returns the kubectl command that resumes a Flux object
+func FluxResumeCommand(res k8s.FluxResourceInfo, kubeContext string) string {
+	return fmt.Sprintf("kubectl patch %s %s -n %s --type=merge -p '{\"spec\":{\"suspend\":null}}'%s",
+		fluxResource(res), res.Name, res.Namespace, contextFlag(kubeContext))
+}
+
+// fluxResource is the fully qualified resource of a Flux object for kubectl, as
+// Kustomization alone is ambiguous
+func fluxResource(res k8s.FluxResourceInfo) string {
+	if res.Kind == k8s.KindHelmRelease {
+		return "helmreleases.helm.toolkit.fluxcd.io"
+	}
+	return "kustomizations.kustomize.toolkit.fluxcd.io"
+}
+
+// KEDAPauseCommand returns the kubectl command that pauses a KEDA ScaledObject at
+// zero replicas
+func KEDAPauseCommand(obj k8s.ScaledObjectInfo, kubeContext string) string {
+	return fmt.Sprintf("kubectl annotate scaledobject %s -n %s %s=0 --overwrite%s",
+		obj.Name, obj.Namespace, k8s.KEDAPausedReplicasAnnotation, contextFlag(kubeContext))
+}
+
+// KEDAResumeCommand returns the kubectl command that restores the paused-replicas
+// annotation a KEDA ScaledObject had before the run
+func KEDAResumeCommand(obj k8s.ScaledObjectInfo, kubeContext string) string {
+	if obj.PausedReplicas == "" {
+		return fmt.Sprintf("kubectl annotate scaledobject %s -n %s %s-%s",
+			obj.Name, obj.Namespace, k8s.KEDAPausedReplicasAnnotation, contextFlag(kubeContext))
+	}
+	return fmt.Sprintf("kubectl annotate scaledobject %s -n %s %s=%s --overwrite%s",
+		obj.Name, obj.Namespace, k8s.KEDAPausedReplicasAnnotation, obj.PausedReplicas, contextFlag(kubeContext))
+}
+
+// contextFlag is the --context argument for kubectl, or "" for the current context
+func contextFlag(kubeContext string) string {
+	if kubeContext == "" {
+		return ""
+	}
+	return " --context=" + kubeContext
+}
+
+// FormatRunbook renders a printable, step-by-step runbook for the plan. For every PVC
+// it lists what the tool is about to do together with the equivalent manual commands,
+// so an operator can finish the migration by hand if the tool dies mid-run.
+func FormatRunbook(plan *MigrationPlan, opts RunbookOptions) string {
+	var b strings.Builder
+
+	kctx := contextFlag(opts.KubeContext)
+
+	b.WriteString("# PVC Migration Runbook\n\n")
+	if !opts.GeneratedAt.IsZero() {
+		b.WriteString(fmt.Sprintf("Generated: %s\n", opts.GeneratedAt.UTC().Format(time.RFC3339)))
+	}
+	if opts.KubeContext != "" {
+		b.WriteString(fmt.Sprintf("Context: %s\n", opts.KubeContext))
+	}
+	b.WriteString(fmt.Sprintf("Target zone: %s\n", plan.TargetZone))
+	b.WriteString(fmt.Sprintf("Storage class: %s\n", plan.StorageClass))
+	b.WriteString(fmt.Sprintf("Namespaces: %s\n\n", strings.Join(plan.Namespaces, ", ")))
+
+	b.WriteString("Values in <ANGLE_BRACKETS> are only known at run time: copy them from the\n")
+	b.WriteString("output of the previous command or from the tool's summary.\n\n")
+
+	// Pre-checks
+	b.WriteString("## 1. Pre-checks\n\n")
+	b.WriteString("```sh\n")
+	b.WriteString("kubectl config current-context\n")
+	b.WriteString("aws sts get-caller-identity\n")
+	for _, ns := range plan.Namespaces {
+		b.WriteString(fmt.Sprintf("kubectl get pvc -n %s%s\n", ns, kctx))
+		b.WriteString(fmt.Sprintf("kubectl get deploy,sts,pods -n %s%s\n", ns, kctx))
+	}
+	b.WriteString("```\n\n")
+
+	migrateItems := make([]PVCPlanItem, 0, len(plan.Items))
+	for _, item := range plan.Items {
+		if item.Action == PlanActionMigrate {
+			migrateItems = append(migrateItems, item)
+		}
+	}
+
+	if len(migrateItems) == 0 {
+		b.WriteString("No PVCs need to be migrated.\n")
+		return b.String()
+	}
+
+	section := 2
+	namespaces := workloadNamespaces(opts.Workloads)
+	if len(opts.ArgoCDApps) > 0 {
+		b.WriteString(fmt.Sprintf("## %d. Disable ArgoCD auto-sync\n\n", section))
+		b.WriteString("Otherwise ArgoCD scales the workloads straight back up.\n\n")
+		for _, app := range unpausedApplicationSetApps(opts.ArgoCDApps, opts.ApplicationSets) {
+			b.WriteString("> **Warning:** " + ApplicationSetNote(app) + "\n\n")
+		}
+		b.WriteString("```sh\n")
+		for _, set := range opts.ApplicationSets {
+			b.WriteString(ApplicationSetPauseCommand(set, opts.KubeContext) + "\n")
+		}
+		for _, app := range opts.ArgoCDApps {
+			b.WriteString(ArgoCDDisableCommand(app, opts.KubeContext) + "\n")
+		}
+		b.WriteString("```\n\n")
+		section++
+	}
+	if len(opts.FluxResources) > 0 {
+		b.WriteString(fmt.Sprintf("## %d. Suspend Flux\n\n", section))
+		b.WriteString("Otherwise Flux recreates the deleted PVCs.\n\n")
+		b.WriteString("```sh\n")
+		for _, res := range opts.FluxResources {
+			b.WriteString(FluxSuspendCommand(res, opts.KubeContext) + "\n")
+		}
+		b.WriteString("```\n\n")
+		section++
+	}
+	if len(namespaces) > 0 {
+		b.WriteString(fmt.Sprintf("## %d. Scale down workloads\n\n", section))
+		if len(opts.ScaledObjects) > 0 {
+			b.WriteString("Pause KEDA first, otherwise it scales the workloads straight back up.\n\n")
+		}
+		b.WriteString("```sh\n")
+		for _, obj := range opts.ScaledObjects {
+			b.WriteString(KEDAPauseCommand(obj, opts.KubeContext) + "\n")
+		}
+		for _, ns := range namespaces {
+			for _, w := range opts.Workloads[ns] {
+				b.WriteString(ScaleCommand(w, ns, 0, opts.KubeContext) + "\n")
+			}
+		}
+		for _, ns := range namespaces {
+			b.WriteString(fmt.Sprintf("kubectl wait --for=delete pod --all -n %s --timeout=5m%s\n", ns, kctx))
+		}
+		b.WriteString("```\n\n")
+		section++
+	}
+
+	for _, item := range migrateItems {
+		storageClass := plan.StorageClass
+		if item.StorageClass != "" {
+			storageClass = item.StorageClass
+		}
+		writeRunbookItem(&b, section, item, storageClass, plan.CSIDriver, kctx)
+		section++
+	}
+
+	// Post-migration
+	b.WriteString(fmt.Sprintf("## %d. Post-migration\n\n", section))
+	step := 1
+	if len(namespaces) > 0 {
+		b.WriteString(fmt.Sprintf("%d. Scale workloads back to their original replica counts:\n\n", step))
+		b.WriteString("```sh\n")
+		for _, ns := range namespaces {
+			for _, w := range opts.Workloads[ns] {
+				b.WriteString(ScaleCommand(w, ns, w.Replicas, opts.KubeContext) + "\n")
+			}
+		}
+		b.WriteString("```\n\n")
+		step++
+	}
+	if len(opts.ScaledObjects) > 0 {
+		b.WriteString(fmt.Sprintf("%d. Resume KEDA autoscaling:\n\n", step))
+		b.WriteString("```sh\n")
+		for _, obj := range opts.ScaledObjects {
+			b.WriteString(KEDAResumeCommand(obj, opts.KubeContext) + "\n")
+		}
+		b.WriteString("```\n\n")
+		step++
+	}
+	if len(opts.ArgoCDApps) > 0 {
+		b.WriteString(fmt.Sprintf("%d. Re-enable ArgoCD auto-sync:\n\n", step))
+		b.WriteString("```sh\n")
+		for _, app := range opts.ArgoCDApps {
+			b.WriteString(ArgoCDEnableCommand(app, opts.KubeContext) + "\n")
+		}
+		for _, set := range opts.ApplicationSets {
+			b.WriteString(ApplicationSetResumeCommand(set, opts.KubeContext) + "\n")
+		}
+		b.WriteString("```\n\n")
+		step++
+	}
+	if len(opts.FluxResources) > 0 {
+		b.WriteString(fmt.Sprintf("%d. Resume Flux:\n\n", step))
+		b.WriteString("```sh\n")
+		for _, res := range opts.FluxResources {
+			b.WriteString(FluxResumeCommand(res, opts.KubeContext) + "\n")
+		}
+		b.WriteString("```\n\n")
+		step++
+	}
+	b.WriteString(fmt.Sprintf("%d. Verify pods are scheduled in %s.\n", step, plan.TargetZone))
+	b.WriteString(fmt.Sprintf("%d. Delete migration snapshots once the data has been verified.\n", step+1))
+
+	return b.String()
+}
+
+// workloadNamespaces returns the namespaces that have workloads, sorted
+func workloadNamespaces(workloads map[string][]k8s.WorkloadInfo) []string {
+	namespaces := make([]string, 0, len(workloads))
+	for ns, ws := range workloads {
+		if len(ws) > 0 {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func writeRunbookItem(b *strings.Builder, section int, item PVCPlanItem, storageClass, csiDriver, kctx string) {
+	b.WriteString(fmt.Sprintf("## %d. %s\n\n", section, item.Name))
+	b.WriteString(fmt.Sprintf("Volume %s (%s) in %s → %s\n\n", item.VolumeID, item.Capacity, item.CurrentZone, item.TargetZone))
+	if !item.Attached {
+		b.WriteString("No pod mounts this PVC, so no workloads need to be scaled down for it.\n\n")
+	}
+
+	if item.CoMountedWith != "" {
+		b.WriteString(fmt.Sprintf("Added to the run because pod %s also mounts it.\n\n", item.CoMountedWith))
+	}
+	switch {
+	case item.PVMissing:
+		b.WriteString(fmt.Sprintf("PV %s was deleted; volume %s was found by its tags and is adopted.\n\n", item.PVName, item.VolumeID))
+	case item.Rebuilt():
+		b.WriteString(fmt.Sprintf("The PVC is %s and PV %s is %s; both are replaced by a Bound pair.\n\n", item.ClaimPhase, item.PVName, item.PVPhase))
+	}
+
+	snapshotID := "<SNAPSHOT_ID>"
+	if item.StagedSnapshotID != "" {
+		snapshotID = item.StagedSnapshotID
+		b.WriteString(fmt.Sprintf("Starts from staged snapshot %s taken %s. Writes made after it are not migrated.\n\n",
+			snapshotID, formatStagedTime(item.StagedSnapshotTime)))
+	}
+
+	i := 0
+	for _, step := range manualSteps(item, storageClass, csiDriver, kctx, snapshotID, "<NEW_VOLUME_ID>") {
+		if item.StagedSnapshotID != "" && (step.step == StepSnapshot || step.step == StepWaitSnapshot) {
+			continue
+		}
+		i++
+		b.WriteString(fmt.Sprintf("%d.%d %s\n\n", section, i, step.title))
+		b.WriteString("```sh\n")
+		for _, c := range step.commands {
+			b.WriteString(c)
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n\n")
+	}
+}
+
+// manualStep is the hand-run equivalent of one migration step
+type manualStep struct {
+	step     Step
+	title    string
+	commands []string
+}
+
+// tagList renders the MigratedPVC tag and the PVC's own tags in the shorthand of
+// the AWS CLI's --tag-specifications
+func tagList(pvc string, tags map[string]string) string {
+	list := []string{fmt.Sprintf("{Key=%s,Value=%s}", aws.TagMigratedPVC, pvc)}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if key := aws.SanitizeTag(k); key != aws.TagMigratedPVC {
+			list = append(list, fmt.Sprintf("{Key=%s,Value=%s}", key, aws.SanitizeTag(tags[k])))
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// encryptionFlags returns the create-volume flags that encrypt with kmsKeyID, if set
+func encryptionFlags(kmsKeyID string) string {
+	if kmsKeyID == "" {
+		return ""
+	}
+	return "  --encrypted --kms-key-id " + kmsKeyID + " \\\n"
+}
+
+// volumeTypeFlags returns the create-volume type of the spec, with its IOPS and
+// throughput when set
+func volumeTypeFlags(spec aws.VolumeSpec) string {
+	flags := spec.VolumeType()
+	if spec.IOPS > 0 {
+		flags += fmt.Sprintf(" --iops %d", spec.IOPS)
+	}
+	if spec.Throughput > 0 {
+		flags += fmt.Sprintf(" --throughput %d", spec.Throughput)
+	}
+	return flags
+}
+
+// manualSteps returns the commands that migrate item by hand, in the order the
+// tool runs them. snapshotID and newVolumeID may be placeholders when not yet known.
+func manualSteps(item PVCPlanItem, storageClass, csiDriver, kctx, snapshotID, newVolumeID string) []manualStep {
+	ns := item.Namespace
+	pvc := item.PVCName
+	newPV := staticPVName(pvc)
+	size := item.CapacityGi
+	if size < 1 {
+		size = 1
+	}
+
+	cleanup := manualStep{
+		step:  StepCleanup,
+		title: "Back up and delete the old PVC and PV",
+		commands: []string{
+			fmt.Sprintf("kubectl get pvc %s -n %s -o yaml%s > %s-pvc-backup.yaml", pvc, ns, kctx, pvc),
+			fmt.Sprintf("kubectl get pv %s -o yaml%s > %s-pv-backup.yaml", item.PVName, kctx, pvc),
+			fmt.Sprintf("kubectl patch pvc %s -n %s --type=merge -p '{\"metadata\":{\"finalizers\":null}}'%s", pvc, ns, kctx),
+			fmt.Sprintf("kubectl delete pvc %s -n %s --grace-period=0%s", pvc, ns, kctx),
+			fmt.Sprintf("kubectl patch pv %s --type=merge -p '{\"metadata\":{\"finalizers\":null}}'%s", item.PVName, kctx),
+			fmt.Sprintf("kubectl delete pv %s --grace-period=0%s", item.PVName, kctx),
+		},
+	}
+	if item.PVMissing {
+		// Only the PVC is left to back up and delete
+		cleanup.title = "Back up and delete the old PVC"
+		cleanup.commands = []string{cleanup.commands[0], cleanup.commands[2], cleanup.commands[3]}
+	}
+
+	return []manualStep{
+		{
+			step:  StepSnapshot,
+			title: "Create EBS snapshot",
+			commands: []string{
+				fmt.Sprintf("aws ec2 create-snapshot --volume-id %s --description \"Migrate %s to %s\" \\\n"+
+					"  --tag-specifications 'ResourceType=snapshot,Tags=[%s]'",
+					item.VolumeID, pvc, item.TargetZone, tagList(pvc, item.Tags)),
+			},
+		},
+		{
+			step:     StepWaitSnapshot,
+			title:    "Wait for the snapshot to complete",
+			commands: []string{"aws ec2 wait snapshot-completed --snapshot-ids " + snapshotID},
+		},
+		{
+			step:  StepCreateVolume,
+			title: "Create the volume in the target zone",
+			commands: []string{
+				fmt.Sprintf("aws ec2 create-volume --snapshot-id %s --availability-zone %s \\\n"+
+					"  --volume-type %s --size %d \\\n%s"+
+					"  --tag-specifications 'ResourceType=volume,Tags=[%s]'",
+					snapshotID, item.TargetZone, volumeTypeFlags(item.VolumeSpec), size, encryptionFlags(item.KMSKeyID), tagList(pvc, item.Tags)),
+			},
+		},
+		{
+			step:     StepWaitVolume,
+			title:    "Wait for the volume to become available",
+			commands: []string{"aws ec2 wait volume-available --volume-ids " + newVolumeID},
+		},
+		{
+			step:     StepCreatePV,
+			title:    "Create the static PV",
+			commands: []string{fmt.Sprintf("kubectl apply%s -f - <<'EOF'\n%sEOF", kctx, runbookPVManifest(newPV, newVolumeID, item, storageClass, csiDriver))},
+		},
+		cleanup,
+		{
+			step:     StepCreatePVC,
+			title:    "Create the PVC bound to the new PV",
+			commands: []string{fmt.Sprintf("kubectl apply%s -f - <<'EOF'\n%sEOF", kctx, runbookPVCManifest(newPV, item, storageClass))},
+		},
+		{
+			step:     StepDone,
+			title:    "Verify",
+			commands: []string{fmt.Sprintf("kubectl get pvc %s -n %s%s   # STATUS must be Bound", pvc, ns, kctx)},
+		},
+	}
+}
+
+// runbookPVManifest returns the static PV bound to volumeID, as CreateStaticPV
+// creates it. An empty csiDriver is k8s.DefaultCSIDriver.
+func runbookPVManifest(pvName, volumeID string, item PVCPlanItem, storageClass, csiDriver string) string {
+	if csiDriver == "" {
+		csiDriver = k8s.DefaultCSIDriver
+	}
+	fsType := "\n    fsType: ext4"
+	if item.Block {
+		fsType = ""
+	}
+	return fmt.Sprintf(`apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: %s
+  labels:
+    migrated: "true"
+spec:
+  capacity:
+    storage: %s
+  volumeMode: %s
+  accessModes: [ReadWriteOnce]
+  persistentVolumeReclaimPolicy: Retain
+  storageClassName: %s
+  csi:
+    driver: %s%s
+    volumeHandle: %s
+  nodeAffinity:
+    required:
+      nodeSelectorTerms:
+        - matchExpressions:
+            - key: topology.kubernetes.io/zone
+              operator: In
+              values: [%s]
+`, pvName, item.Capacity, item.volumeMode(), storageClass, csiDriver, fsType, volumeID, item.TargetZone)
+}
+
+func runbookPVCManifest(pvName string, item PVCPlanItem, storageClass string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    migrated: "true"
+spec:
+  accessModes: [ReadWriteOnce]
+  volumeMode: %s
+  storageClassName: %s
+  resources:
+    requests:
+      storage: %s
+  volumeName: %s
+`, item.PVCName, item.Namespace, item.volumeMode(), storageClass, item.Capacity, pvName)
+}
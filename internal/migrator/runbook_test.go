@@ -0,0 +1,425 @@
+package migrator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestFormatRunbook(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Items: []PVCPlanItem{
+			{
+				Name:        "db/data-0",
+				Namespace:   "db",
+				PVCName:     "data-0",
+				PVName:      "pvc-123",
+				VolumeID:    "vol-abc",
+				Capacity:    "20Gi",
+				CapacityGi:  20,
+				CurrentZone: "us-west-2b",
+				TargetZone:  "us-west-2a",
+				Action:      PlanActionMigrate,
+			},
+			{
+				Name:        "db/data-1",
+				Namespace:   "db",
+				PVCName:     "data-1",
+				CurrentZone: "us-west-2a",
+				Action:      PlanActionSkip,
+			},
+		},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{
+		KubeContext: "staging",
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+
+	assert.Contains(t, out, "# PVC Migration Runbook")
+	assert.Contains(t, out, "Generated: 2026-01-02T03:04:05Z")
+	assert.Contains(t, out, "## 1. Pre-checks")
+	assert.Contains(t, out, "kubectl get pvc -n db --context=staging")
+	assert.Contains(t, out, "## 2. db/data-0")
+	assert.Contains(t, out, "aws ec2 create-snapshot --volume-id vol-abc")
+	assert.Contains(t, out, "--availability-zone us-west-2a")
+	assert.Contains(t, out, "--size 20")
+	assert.Contains(t, out, "kubectl delete pv pvc-123 --grace-period=0 --context=staging")
+	assert.Contains(t, out, "volumeName: data-0-static")
+	assert.Contains(t, out, "## 3. Post-migration")
+	assert.NotContains(t, out, "db/data-1", "skipped PVCs have no section")
+	assert.NotContains(t, out, "--encrypted", "unencrypted volumes get no KMS flags")
+}
+
+func TestFormatRunbook_KMSKey(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"db"},
+		Items: []PVCPlanItem{{
+			Name: "db/data-0", Namespace: "db", PVCName: "data-0", PVName: "pvc-123", VolumeID: "vol-abc",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate, KMSKeyID: "alias/prod",
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+	assert.Contains(t, out, "--size 20 \\\n  --encrypted --kms-key-id alias/prod \\\n  --tag-specifications")
+}
+
+func TestFormatRunbook_VolumeSpec(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"db"},
+		Items: []PVCPlanItem{{
+			Name: "db/data-0", Namespace: "db", PVCName: "data-0", PVName: "pvc-123", VolumeID: "vol-abc",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate,
+			VolumeSpec: aws.VolumeSpec{Type: aws.VolumeTypeIO2, IOPS: 16000},
+		}, {
+			Name: "db/data-1", Namespace: "db", PVCName: "data-1", PVName: "pvc-456", VolumeID: "vol-def",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate,
+			VolumeSpec: aws.VolumeSpec{IOPS: 6000, Throughput: 500},
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+	assert.Contains(t, out, "--volume-type io2 --iops 16000 --size 20 \\\n")
+	assert.Contains(t, out, "--volume-type gp3 --iops 6000 --throughput 500 --size 20 \\\n")
+}
+
+func TestFormatRunbook_CSIDriver(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"db"},
+		Items: []PVCPlanItem{{
+			Name: "db/data-0", Namespace: "db", PVCName: "data-0", PVName: "pvc-123", VolumeID: "vol-abc",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate,
+		}},
+	}
+
+	assert.Contains(t, FormatRunbook(plan, RunbookOptions{}), "  csi:\n    driver: ebs.csi.aws.com\n    fsType: ext4\n    volumeHandle: <NEW_VOLUME_ID>\n")
+	plan.CSIDriver = "ebs.csi.example.com"
+	assert.Contains(t, FormatRunbook(plan, RunbookOptions{}), "  csi:\n    driver: ebs.csi.example.com\n")
+}
+
+func TestFormatRunbook_BlockVolumeMode(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"db"},
+		Items: []PVCPlanItem{{
+			Name: "db/raw-0", Namespace: "db", PVCName: "raw-0", PVName: "pvc-123", VolumeID: "vol-abc",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate, Block: true,
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+	assert.Contains(t, out, "  volumeMode: Block\n  accessModes: [ReadWriteOnce]\n")
+	assert.Contains(t, out, "  accessModes: [ReadWriteOnce]\n  volumeMode: Block\n")
+	assert.Contains(t, out, "    driver: ebs.csi.aws.com\n    volumeHandle: <NEW_VOLUME_ID>\n")
+	assert.NotContains(t, out, "fsType")
+	assert.NotContains(t, out, "Filesystem")
+}
+
+func TestFormatRunbook_Tags(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"db"},
+		Items: []PVCPlanItem{{
+			Name: "db/data-0", Namespace: "db", PVCName: "data-0", PVName: "pvc-123", VolumeID: "vol-abc",
+			CapacityGi: 20, TargetZone: "us-west-2a", Action: PlanActionMigrate, Tags: map[string]string{"costcenter": "42"},
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+	assert.Contains(t, out, "'ResourceType=snapshot,Tags=[{Key=MigratedPVC,Value=data-0},{Key=costcenter,Value=42}]'")
+	assert.Contains(t, out, "'ResourceType=volume,Tags=[{Key=MigratedPVC,Value=data-0},{Key=costcenter,Value=42}]'")
+}
+
+func TestFormatRunbook_StagedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Items: []PVCPlanItem{{
+			Name:               "db/data-0",
+			Namespace:          "db",
+			PVCName:            "data-0",
+			PVName:             "pvc-123",
+			VolumeID:           "vol-abc",
+			CapacityGi:         20,
+			CurrentZone:        "us-west-2b",
+			TargetZone:         "us-west-2a",
+			Action:             PlanActionMigrate,
+			Attached:           true,
+			StagedSnapshotID:   "snap-staged",
+			StagedSnapshotTime: time.Date(2026, 1, 1, 22, 30, 0, 0, time.UTC),
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+
+	assert.Contains(t, out, "Starts from staged snapshot snap-staged taken 2026-01-01 22:30 UTC.")
+	assert.NotContains(t, out, "aws ec2 create-snapshot", "the staged snapshot replaces the snapshot steps")
+	assert.Contains(t, out, "--snapshot-id snap-staged")
+	assert.Contains(t, out, "2.1 ")
+}
+
+func TestFormatRunbook_StorageClassOverride(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Items: []PVCPlanItem{{
+			Name:         "db/data-0",
+			Namespace:    "db",
+			PVCName:      "data-0",
+			VolumeID:     "vol-abc",
+			Capacity:     "20Gi",
+			CurrentZone:  "us-west-2b",
+			TargetZone:   "us-west-2a",
+			Action:       PlanActionMigrate,
+			StorageClass: "io2",
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+
+	assert.Contains(t, out, "storageClassName: io2")
+	assert.NotContains(t, out, "storageClassName: gp3")
+}
+
+func TestFormatRunbook_DeletedPV(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Items: []PVCPlanItem{{
+			Name:        "db/data-0",
+			Namespace:   "db",
+			PVCName:     "data-0",
+			PVName:      "pvc-123",
+			VolumeID:    "vol-abc",
+			Capacity:    "20Gi",
+			CurrentZone: "us-west-2b",
+			TargetZone:  "us-west-2a",
+			Action:      PlanActionMigrate,
+			ClaimPhase:  "Lost",
+			PVMissing:   true,
+		}},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+
+	assert.Contains(t, out, "PV pvc-123 was deleted; volume vol-abc was found by its tags and is adopted.")
+	assert.Contains(t, out, "Back up and delete the old PVC\n")
+	assert.Contains(t, out, "kubectl delete pvc data-0 -n db")
+	assert.NotContains(t, out, "kubectl get pv pvc-123")
+	assert.NotContains(t, out, "kubectl delete pv pvc-123")
+}
+
+func TestFormatRunbook_NothingToMigrate(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone: "us-west-2a",
+		Namespaces: []string{"default"},
+		Items: []PVCPlanItem{
+			{Name: "default/pvc", Action: PlanActionSkip},
+		},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{})
+
+	assert.Contains(t, out, "No PVCs need to be migrated.")
+	assert.NotContains(t, out, "Generated:")
+	assert.NotContains(t, out, "--context")
+}
+
+func TestFormatRunbook_WorkloadsAndArgoCD(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"db"},
+		Items: []PVCPlanItem{
+			{Name: "db/data-0", Namespace: "db", PVCName: "data-0", Action: PlanActionMigrate},
+		},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{
+		KubeContext: "staging",
+		Workloads: map[string][]k8s.WorkloadInfo{
+			"db":    {{Kind: "StatefulSet", Name: "postgres", Replicas: 3}},
+			"empty": nil,
+		},
+		ArgoCDApps: []k8s.ArgoCDAppInfo{
+			{Name: "db", Namespace: "argocd", AutoSyncPolicy: []byte(`{"prune":true,"selfHeal":true}`)},
+		},
+	})
+
+	assert.Contains(t, out, "## 2. Disable ArgoCD auto-sync")
+	assert.Contains(t, out, `kubectl patch application db -n argocd --type=json -p '[{"op":"remove","path":"/spec/syncPolicy/automated"}]' --context=staging`)
+	assert.Contains(t, out, "## 3. Scale down workloads")
+	assert.Contains(t, out, "kubectl scale statefulset postgres --replicas=0 -n db --context=staging")
+	assert.Contains(t, out, "kubectl wait --for=delete pod --all -n db --timeout=5m --context=staging")
+	assert.NotContains(t, out, "-n empty")
+	assert.Contains(t, out, "## 4. db/data-0")
+	assert.Contains(t, out, "## 5. Post-migration")
+	assert.Contains(t, out, "kubectl scale statefulset postgres --replicas=3 -n db --context=staging")
+	assert.Contains(t, out, `kubectl patch application db -n argocd --type=merge -p '{"spec":{"syncPolicy":{"automated":{"prune":true,"selfHeal":true}}}}' --context=staging`)
+
+	// The scale-down must come before the first PVC and the restore after the last
+	assert.Less(t, strings.Index(out, "--replicas=0"), strings.Index(out, "## 4. db/data-0"))
+	assert.Greater(t, strings.Index(out, "--replicas=3"), strings.Index(out, "## 5. Post-migration"))
+}
+
+func TestFormatRunbook_KEDA(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"shop"},
+		Items: []PVCPlanItem{
+			{Name: "shop/data", Namespace: "shop", PVCName: "data", Action: PlanActionMigrate},
+		},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{
+		KubeContext: "staging",
+		Workloads:   map[string][]k8s.WorkloadInfo{"shop": {{Kind: "Deployment", Name: "web", Replicas: 2}}},
+		ScaledObjects: []k8s.ScaledObjectInfo{
+			{Name: "web", Namespace: "shop", Target: "Deployment/web"},
+			{Name: "worker", Namespace: "shop", Target: "Deployment/worker", PausedReplicas: "1"},
+		},
+	})
+
+	pause := "kubectl annotate scaledobject web -n shop autoscaling.keda.sh/paused-replicas=0 --overwrite --context=staging"
+	assert.Contains(t, out, pause)
+	assert.Less(t, strings.Index(out, pause), strings.Index(out, "--replicas=0"), "KEDA is paused before the scale-down")
+	resume := "kubectl annotate scaledobject web -n shop autoscaling.keda.sh/paused-replicas- --context=staging"
+	assert.Contains(t, out, "2. Resume KEDA autoscaling")
+	assert.Greater(t, strings.Index(out, resume), strings.Index(out, "--replicas=2"), "KEDA is resumed after the scale-up")
+	assert.Contains(t, out, "kubectl annotate scaledobject worker -n shop autoscaling.keda.sh/paused-replicas=1 --overwrite --context=staging")
+}
+
+func TestFormatRunbook_Flux(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"shop"},
+		Items: []PVCPlanItem{
+			{Name: "shop/data", Namespace: "shop", PVCName: "data", Action: PlanActionMigrate},
+		},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{
+		FluxResources: []k8s.FluxResourceInfo{
+			{Kind: k8s.KindKustomization, Name: "apps", Namespace: "flux-system"},
+			{Kind: k8s.KindHelmRelease, Name: "redis", Namespace: "shop"},
+		},
+	})
+
+	assert.Contains(t, out, "## 2. Suspend Flux")
+	assert.Contains(t, out, `kubectl patch kustomizations.kustomize.toolkit.fluxcd.io apps -n flux-system --type=merge -p '{"spec":{"suspend":true}}'`)
+	assert.Contains(t, out, `kubectl patch helmreleases.helm.toolkit.fluxcd.io redis -n shop --type=merge -p '{"spec":{"suspend":true}}'`)
+	assert.Contains(t, out, "## 3. shop/data")
+	assert.Contains(t, out, "1. Resume Flux")
+	assert.Contains(t, out, `kubectl patch kustomizations.kustomize.toolkit.fluxcd.io apps -n flux-system --type=merge -p '{"spec":{"suspend":null}}'`)
+}
+
+func TestArgoCDEnableCommand_EmptyPolicy(t *testing.T) {
+	t.Parallel()
+
+	got := ArgoCDEnableCommand(k8s.ArgoCDAppInfo{Name: "app", Namespace: "argocd"}, "")
+	assert.Equal(t, `kubectl patch application app -n argocd --type=merge -p '{"spec":{"syncPolicy":{"automated":{}}}}'`, got)
+}
+
+func TestFormatRunbook_ApplicationSets(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{
+		TargetZone:   "us-west-2a",
+		StorageClass: "gp3",
+		Namespaces:   []string{"shop"},
+		Items: []PVCPlanItem{
+			{Name: "shop/data", Namespace: "shop", PVCName: "data", Action: PlanActionMigrate},
+		},
+	}
+	apps := []k8s.ArgoCDAppInfo{
+		{Name: "web", Namespace: "argocd", ApplicationSet: "shops"},
+		{Name: "api", Namespace: "argocd", ApplicationSet: "apis"},
+	}
+
+	out := FormatRunbook(plan, RunbookOptions{
+		ArgoCDApps:      apps,
+		ApplicationSets: []k8s.ApplicationSetInfo{{Name: "shops", Namespace: "argocd", ApplicationsSync: "create-update"}},
+	})
+
+	assert.Contains(t, out, `kubectl patch applicationset shops -n argocd --type=merge -p '{"spec":{"syncPolicy":{"applicationsSync":"create-only"}}}'`)
+	assert.Contains(t, out, `kubectl patch applicationset shops -n argocd --type=merge -p '{"spec":{"syncPolicy":{"applicationsSync":"create-update"}}}'`)
+	assert.Contains(t, out, "> **Warning:** argocd/api is owned by ApplicationSet apis")
+	assert.NotContains(t, out, "argocd/web is owned", "paused ApplicationSets need no guidance")
+	assert.Less(t, strings.Index(out, "applicationset shops"), strings.Index(out, "application web"), "the ApplicationSet is paused first")
+}
+
+func TestArgoCDCommands_Strategies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		strategy    string
+		wantDisable string
+		wantEnable  string
+	}{
+		{
+			name:        "sync_window",
+			strategy:    k8s.ArgoCDStrategySyncWindow,
+			wantDisable: `argocd proj windows add shop --kind deny --schedule '* * * * *' --duration 24h --applications app`,
+			wantEnable:  `argocd proj windows list shop  # then delete the deny window of app: argocd proj windows delete shop <ID>`,
+		},
+		{
+			name:        "annotation",
+			strategy:    k8s.ArgoCDStrategyAnnotation,
+			wantDisable: `kubectl annotate application app -n argocd argocd.argoproj.io/skip-reconcile=true --overwrite --context=prod`,
+			wantEnable:  `kubectl annotate application app -n argocd argocd.argoproj.io/skip-reconcile- --context=prod`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := k8s.ArgoCDAppInfo{Name: "app", Namespace: "argocd", Project: "shop", Strategy: tc.strategy}
+			assert.Equal(t, tc.wantDisable, ArgoCDDisableCommand(app, "prod"))
+			assert.Equal(t, tc.wantEnable, ArgoCDEnableCommand(app, "prod"))
+		})
+	}
+}
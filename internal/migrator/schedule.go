@@ -0,0 +1,161 @@
+package migrator
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Orders in which PVCs are started, see Config.Scheduling
+const (
+	ScheduleFIFO       = "fifo"        // In the order of the PVC list
+	ScheduleRoundRobin = "round-robin" // One PVC of each namespace in turn
+)
+
+// PVC priorities, see Config.Priorities
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// PriorityFor returns the priority of a claim
+func (c *Config) PriorityFor(pvcName string) string {
+	if priority, ok := c.Priorities[pvcName]; ok {
+		return priority
+	}
+	ns, _ := ParsePVCName(pvcName)
+	if priority, ok := c.NamespacePriorities[ns]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// priorityRank orders priorities, high first. Empty means normal.
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	}
+	return 1
+}
+
+// roundRobinOrder interleaves names by namespace, one of each namespace in turn,
+// keeping the order of the namespaces and of the PVCs within each namespace
+func roundRobinOrder(names []string) []string {
+	var namespaces []string
+	byNamespace := make(map[string][]string)
+	for _, name := range names {
+		ns, _ := ParsePVCName(name)
+		if _, ok := byNamespace[ns]; !ok {
+			namespaces = append(namespaces, ns)
+		}
+		byNamespace[ns] = append(byNamespace[ns], name)
+	}
+
+	order := make([]string, 0, len(names))
+	for len(order) < len(names) {
+		for _, ns := range namespaces {
+			if queue := byNamespace[ns]; len(queue) > 0 {
+				order = append(order, queue[0])
+				byNamespace[ns] = queue[1:]
+			}
+		}
+	}
+	return order
+}
+
+// scheduler hands out the PVCs of a run in the configured order, higher priorities
+// first, holding back the PVCs of namespaces that already have MaxPerNamespace
+// PVCs in progress or whose window is closed
+type scheduler struct {
+	mu       sync.Mutex
+	freed    *sync.Cond // Signalled when a PVC finishes or a window may have opened
+	pending  []string
+	running  map[string]int // Namespace -> PVCs in progress
+	perNSCap int            // 0 for no cap
+	windows  map[string]TimeWindow
+	now      func() time.Time
+	timer    *time.Timer // Wakes next when the earliest closed window opens
+}
+
+func newScheduler(names []string, config *Config) *scheduler {
+	pending := append([]string(nil), names...)
+	rank := func(name string) int { return PriorityRank(config.PriorityFor(name)) }
+	sort.SliceStable(pending, func(i, j int) bool { return rank(pending[i]) < rank(pending[j]) })
+	if config.Scheduling == ScheduleRoundRobin {
+		// Namespaces take turns within each priority
+		for start := 0; start < len(pending); {
+			end := start
+			for end < len(pending) && rank(pending[end]) == rank(pending[start]) {
+				end++
+			}
+			copy(pending[start:end], roundRobinOrder(pending[start:end]))
+			start = end
+		}
+	}
+	s := &scheduler{
+		pending:  pending,
+		running:  make(map[string]int),
+		perNSCap: config.MaxPerNamespace,
+		windows:  config.Windows,
+		now:      time.Now,
+	}
+	s.freed = sync.NewCond(&s.mu)
+	return s
+}
+
+// next returns the PVC to start next, waiting while every namespace with pending
+// PVCs is at its cap or outside its window. Windows stop holding PVCs back once
+// ctx is done. It returns false once every PVC has been handed out.
+func (s *scheduler) next(ctx context.Context) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.pending) > 0 {
+		var opens time.Duration // Until the earliest closed window opens
+		for i, name := range s.pending {
+			ns, _ := ParsePVCName(name)
+			if s.perNSCap > 0 && s.running[ns] >= s.perNSCap {
+				continue
+			}
+			if w, ok := s.windows[ns]; ok && ctx.Err() == nil {
+				if wait := w.Opens(s.now()); wait > 0 {
+					if opens == 0 || wait < opens {
+						opens = wait
+					}
+					continue
+				}
+			}
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			s.running[ns]++
+			return name, true
+		}
+		if opens > 0 {
+			if s.timer != nil {
+				s.timer.Stop()
+			}
+			s.timer = time.AfterFunc(opens, s.wake)
+		}
+		s.freed.Wait()
+	}
+	return "", false
+}
+
+// wake makes a waiting next look at the pending PVCs again
+func (s *scheduler) wake() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.freed.Broadcast()
+}
+
+// finished records that a PVC handed out by next is no longer in progress
+func (s *scheduler) finished(name string) {
+	ns, _ := ParsePVCName(name)
+	s.mu.Lock()
+	s.running[ns]--
+	s.mu.Unlock()
+	s.freed.Broadcast()
+}
@@ -0,0 +1,174 @@
+package migrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundRobinOrder(t *testing.T) {
+	t.Parallel()
+
+	order := roundRobinOrder([]string{"big/a", "big/b", "big/c", "big/d", "small/x", "small/y", "tiny/z"})
+	assert.Equal(t, []string{"big/a", "small/x", "tiny/z", "big/b", "small/y", "big/c", "big/d"}, order)
+	assert.Empty(t, roundRobinOrder(nil))
+}
+
+func TestScheduler_Priorities(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Scheduling:          ScheduleRoundRobin,
+		Priorities:          map[string]string{"big/d": PriorityHigh, "small/x": PriorityLow},
+		NamespacePriorities: map[string]string{"db": PriorityHigh},
+	}
+	s := newScheduler([]string{"big/a", "big/b", "big/d", "small/x", "small/y", "db/z"}, config)
+
+	var order []string
+	for name, ok := s.next(context.Background()); ok; name, ok = s.next(context.Background()) {
+		order = append(order, name)
+	}
+	assert.Equal(t, []string{"big/d", "db/z", "big/a", "small/y", "big/b", "small/x"}, order)
+}
+
+func TestScheduler_PerNamespaceCap(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduler([]string{"big/a", "big/b", "big/c", "small/x"}, &Config{MaxPerNamespace: 1})
+
+	first, ok := s.next(context.Background())
+	require.True(t, ok)
+	assert.Equal(t, "big/a", first)
+	second, _ := s.next(context.Background())
+	assert.Equal(t, "small/x", second, "big is at its cap")
+
+	got := make(chan string)
+	go func() {
+		name, _ := s.next(context.Background())
+		got <- name
+	}()
+	s.finished("small/x")
+	s.finished(first)
+	assert.Equal(t, "big/b", <-got, "waits for a big PVC to finish")
+
+	s.finished("big/b")
+	name, ok := s.next(context.Background())
+	assert.Equal(t, "big/c", name)
+	assert.True(t, ok)
+	_, ok = s.next(context.Background())
+	assert.False(t, ok)
+}
+
+func TestScheduler_Windows(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Windows: map[string]TimeWindow{"payments": {Start: 2 * time.Hour, End: 4 * time.Hour}}}
+	s := newScheduler([]string{"payments/db", "web/static"}, config)
+	var mu sync.Mutex
+	now := time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	name, ok := s.next(context.Background())
+	require.True(t, ok)
+	assert.Equal(t, "web/static", name, "payments is outside its window")
+
+	got := make(chan string)
+	go func() {
+		name, _ := s.next(context.Background())
+		got <- name
+	}()
+	mu.Lock()
+	now = now.Add(90 * time.Minute)
+	mu.Unlock()
+	s.wake()
+	assert.Equal(t, "payments/db", <-got, "held until the window opens")
+}
+
+func TestScheduler_WindowsIgnoredOnceCancelled(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Windows: map[string]TimeWindow{"payments": {Start: 2 * time.Hour, End: 4 * time.Hour}}}
+	s := newScheduler([]string{"payments/db"}, config)
+	s.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	name, ok := s.next(ctx)
+	assert.True(t, ok, "handed out so it fails instead of waiting for the window")
+	assert.Equal(t, "payments/db", name)
+}
+
+func TestRunEach_Fairness(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		config    Config
+		wantOrder []string
+		wantMax   int // Most PVCs of big in progress at once
+	}{
+		{
+			name:      "fifo",
+			config:    Config{MaxConcurrency: 1},
+			wantOrder: []string{"big/a", "big/b", "big/c", "small/x"},
+			wantMax:   1,
+		},
+		{
+			name:      "round_robin",
+			config:    Config{MaxConcurrency: 1, Scheduling: ScheduleRoundRobin},
+			wantOrder: []string{"big/a", "small/x", "big/b", "big/c"},
+			wantMax:   1,
+		},
+		{
+			name:    "per_namespace_cap",
+			config:  Config{MaxConcurrency: 3, MaxPerNamespace: 2},
+			wantMax: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := tc.config
+			config.PVCList = []string{"big/a", "big/b", "big/c", "small/x"}
+			m := New(&config, nil, nil)
+
+			var mu sync.Mutex
+			var order []string
+			running, most := 0, 0
+			m.runEach(context.Background(), "test", config.PVCList, func(_ context.Context, name string) {
+				ns, _ := ParsePVCName(name)
+				mu.Lock()
+				order = append(order, name)
+				if ns == "big" {
+					running++
+					most = max(most, running)
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond) // Overlap with the PVCs started next
+				mu.Lock()
+				if ns == "big" {
+					running--
+				}
+				mu.Unlock()
+			})
+
+			assert.Len(t, order, 4)
+			if tc.wantOrder != nil {
+				assert.Equal(t, tc.wantOrder, order)
+			}
+			assert.LessOrEqual(t, most, tc.wantMax)
+			assert.True(t, m.IsDone())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// WorkloadSchedule is whether a pod of a Deployment or StatefulSet mounting PVCs
+// of the plan could be scheduled in the zone they are moved to
+type WorkloadSchedule struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Zone      string
+	// Admitted is set when the API server accepted the pod in a dry run;
+	// otherwise Reason holds what rejected it
+	Admitted bool
+	Nodes    int // Healthy nodes of the zone the admitted pod fits on
+	// Reason is why the pod was rejected or, when it fits on no node, why the
+	// first node of the zone could not take it
+	Reason string
+}
+
+// Schedulable reports whether the pod was admitted and fits on a node of the zone
+func (w WorkloadSchedule) Schedulable() bool {
+	return w.Admitted && w.Nodes > 0
+}
+
+// simulateScheduling dry-runs a pod of each workload mounting PVCs to migrate,
+// pinned to their target zone, and checks the healthy nodes of the zone for one
+// that would take it. It only reports: the PVCs are migrated either way.
+// Workloads whose PVCs go to different zones are skipped, as their pods could
+// not run anywhere.
+func (m *Migrator) simulateScheduling(ctx context.Context, items []PVCPlanItem) []WorkloadSchedule {
+	moving := make(map[string]map[string]string) // Namespace -> PVC -> target zone
+	var namespaces []string
+	for _, item := range items {
+		if item.Action != PlanActionMigrate || item.TargetZone == "" {
+			continue
+		}
+		if moving[item.Namespace] == nil {
+			moving[item.Namespace] = make(map[string]string)
+			namespaces = append(namespaces, item.Namespace)
+		}
+		moving[item.Namespace][item.PVCName] = item.TargetZone
+	}
+	sort.Strings(namespaces)
+
+	usage := make(map[string][]k8s.NodeUsage) // By zone
+	var result []WorkloadSchedule
+	for _, ns := range namespaces {
+		claims := make([]string, 0, len(moving[ns]))
+		for claim := range moving[ns] {
+			claims = append(claims, claim)
+		}
+		sort.Strings(claims)
+
+		workloads, err := m.k8sClient.ClaimWorkloads(ctx, ns, claims)
+		if err != nil {
+			slog.Warn("failed to find the workloads of namespace, not simulating their pods", "namespace", ns, "error", err)
+			continue
+		}
+		for _, w := range workloads {
+			zone := workloadZone(w.Claims, moving[ns])
+			if zone == "" {
+				slog.Debug("workload PVCs go to different zones, not simulating its pods", "namespace", ns, "workload", w.Name)
+				continue
+			}
+			schedule := WorkloadSchedule{Namespace: ns, Kind: w.Kind, Name: w.Name, Zone: zone}
+
+			pod, err := m.k8sClient.DryRunWorkloadPod(ctx, ns, w.Kind, w.Name, zone)
+			if err != nil {
+				schedule.Reason = err.Error()
+				result = append(result, schedule)
+				continue
+			}
+			schedule.Admitted = true
+
+			nodes, ok := usage[zone]
+			if !ok {
+				if nodes, err = m.k8sClient.ZoneNodeUsage(ctx, zone); err != nil {
+					schedule.Reason = err.Error()
+					result = append(result, schedule)
+					continue
+				}
+				usage[zone] = nodes
+			}
+			for _, node := range nodes {
+				reason := node.Unfit(pod)
+				if reason == "" {
+					schedule.Nodes++
+				} else if schedule.Reason == "" {
+					schedule.Reason = fmt.Sprintf("node %s: %s", node.Name, reason)
+				}
+			}
+			if schedule.Nodes > 0 {
+				schedule.Reason = ""
+			} else if len(nodes) == 0 {
+				schedule.Reason = fmt.Sprintf("no Ready, schedulable node in zone %s", zone)
+			}
+			result = append(result, schedule)
+		}
+	}
+	return result
+}
+
+// workloadZone returns the zone the claims of a workload are moved to, or
+// nothing when they go to different zones
+func workloadZone(claims []string, zones map[string]string) string {
+	var zone string
+	for _, claim := range claims {
+		z, ok := zones[claim]
+		if !ok {
+			continue
+		}
+		if zone != "" && z != zone {
+			return ""
+		}
+		zone = z
+	}
+	return zone
+}
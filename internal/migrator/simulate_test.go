@@ -0,0 +1,100 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestGeneratePlan_SimulateScheduling(t *testing.T) {
+	t.Parallel()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{corev1.LabelTopologyZone: "eu-west-1a"}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	deployment := func(name, claim, cpu string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "db"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				}}},
+				Volumes: []corev1.Volume{{
+					Name:         claim,
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+				}},
+			}}},
+		}
+	}
+
+	objects := []runtime.Object{
+		node,
+		deployment("web", "data-0", "500m"),
+		deployment("big", "data-1", "4"),
+		deployment("locked", "data-2", "100m"),
+	}
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+	objects = append(objects, boundClaim("db", "data-2", "vol-2")...)
+	clientset := bindingClientset(objects...)
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		if pod.Name != "locked-zone-check" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), pod.Name, assert.AnError)
+	})
+	m := New(&Config{
+		PVCList:            []string{"db/data-0", "db/data-1", "db/data-2"},
+		TargetZone:         "eu-west-1a",
+		SimulateScheduling: true,
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{zones: map[string]string{
+		"vol-0": "eu-west-1b", "vol-1": "eu-west-1b", "vol-2": "eu-west-1b",
+	}}))
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Schedulability, 3)
+	bigSchedule, lockedSchedule, webSchedule := plan.Schedulability[0], plan.Schedulability[1], plan.Schedulability[2]
+
+	assert.Equal(t, WorkloadSchedule{Namespace: "db", Kind: "Deployment", Name: "web", Zone: "eu-west-1a", Admitted: true, Nodes: 1}, webSchedule)
+	assert.True(t, webSchedule.Schedulable())
+	assert.Equal(t, WorkloadSchedule{
+		Namespace: "db", Kind: "Deployment", Name: "big", Zone: "eu-west-1a", Admitted: true, Reason: "node node-a: insufficient cpu",
+	}, bigSchedule)
+	assert.False(t, bigSchedule.Schedulable())
+	assert.False(t, lockedSchedule.Admitted)
+	assert.Contains(t, lockedSchedule.Reason, "forbidden")
+	for _, item := range plan.Items {
+		assert.Equal(t, PlanActionMigrate, item.Action, "the simulation only reports")
+	}
+}
+
+func TestGeneratePlan_SimulateScheduling_Off(t *testing.T) {
+	t.Parallel()
+
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}, boundClaim("db", "data-0", "vol-0")...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, plan.Schedulability)
+}
@@ -0,0 +1,106 @@
+package migrator
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+// maxStateLine bounds a line of the state file, which holds one PVC's result
+const maxStateLine = 1 << 20
+
+// StateKey identifies the migration a state file record belongs to, so a run
+// only resumes from the records of runs to the same zones of the same cluster
+type StateKey struct {
+	KubeContext string `json:"kubeContext"`
+	Destination string `json:"destination"` // Target zone, or the target zones joined by commas
+}
+
+// StateRecord is a line of the state file a batched run spills each batch's
+// results to, so the run only ever holds one batch in memory
+type StateRecord struct {
+	StateKey
+	MigrationID string `json:"migrationId"` // Run that migrated the PVC, whose ID adopts what it created
+	Batch       int    `json:"batch"`
+	apiv1.PVCResult
+}
+
+// AppendState appends the result of every PVC of the run to the state file at
+// path, one JSON line per PVC under key, creating the file if needed
+func (m *Migrator) AppendState(path string, key StateKey, batch int) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open state file %s: %w", path, err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, pvc := range m.Result().PVCs {
+		if err := enc.Encode(StateRecord{StateKey: key, MigrationID: m.config.MigrationID, Batch: batch, PVCResult: pvc}); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write state file %s: %w", path, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// ReadState calls fn with each record of the state file at path, in the order
+// they were appended, reading one line at a time. A missing file has no records.
+func ReadState(path string, fn func(StateRecord)) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open state file %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStateLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r StateRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return fmt.Errorf("state file %s, line %d: %w", path, line, err)
+		}
+		fn(r)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+	return nil
+}
+
+// MigratedPVCs returns the "namespace/name" of the PVCs whose latest record
+// under key in the state file at path is migrated, which a resumed run leaves
+// out. Records of other clusters or target zones are ignored. Skipped PVCs are
+// planned again, as whatever skipped them may have been fixed since.
+func MigratedPVCs(path string, key StateKey) (map[string]bool, error) {
+	done := make(map[string]bool)
+	err := ReadState(path, func(r StateRecord) {
+		if r.StateKey == key {
+			done[r.PVC] = r.Outcome == apiv1.OutcomeMigrated
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for pvc, ok := range done {
+		if !ok {
+			delete(done, pvc)
+		}
+	}
+	return done, nil
+}
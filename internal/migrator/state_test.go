@@ -0,0 +1,87 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+	apiv1 "github.com/cesarempathy/pv-zone-migrator/pkg/api/v1"
+)
+
+func TestAppendState(t *testing.T) {
+	t.Parallel()
+
+	clientset := bindingClientset()
+	m := New(&Config{
+		PVCList:        []string{"db/data"},
+		TargetZone:     "eu-west-1a",
+		MaxConcurrency: 1,
+		MigrationID:    "20261016T120000Z-0a1b",
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, k8s.NewClientWithInterface(clientset, nil), aws.NewEC2ClientWithInterface(&fakeEC2{zones: map[string]string{"vol-new": "eu-west-1a"}}))
+	ctx := context.Background()
+	require.NoError(t, m.k8sClient.CreateStaticPV(ctx, "data-static", "vol-new", "10Gi", "gp3", "eu-west-1a", corev1.PersistentVolumeFilesystem))
+	m.Run(ctx)
+
+	path := filepath.Join(t.TempDir(), "state.jsonl")
+	key := StateKey{KubeContext: "prod", Destination: "eu-west-1a"}
+	require.NoError(t, m.AppendState(path, key, 1))
+	require.NoError(t, m.AppendState(path, key, 2))
+
+	var records []StateRecord
+	require.NoError(t, ReadState(path, func(r StateRecord) { records = append(records, r) }))
+	require.Len(t, records, 2)
+	assert.Equal(t, key, records[0].StateKey)
+	assert.Equal(t, "20261016T120000Z-0a1b", records[0].MigrationID)
+	assert.Equal(t, 1, records[0].Batch)
+	assert.Equal(t, 2, records[1].Batch)
+	assert.Equal(t, "db/data", records[0].PVC)
+	assert.Equal(t, apiv1.OutcomeMigrated, records[0].Outcome)
+}
+
+func TestReadState_Missing(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	require.NoError(t, ReadState(filepath.Join(t.TempDir(), "state.jsonl"), func(StateRecord) { called = true }))
+	assert.False(t, called)
+}
+
+func TestReadState_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"pvc":"db/data","outcome":"migrated"}`+"\nnot json\n"), 0600))
+
+	err := ReadState(path, func(StateRecord) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestMigratedPVCs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.jsonl")
+	lines := `{"kubeContext":"prod","destination":"eu-west-1a","pvc":"db/data","outcome":"failed"}
+{"kubeContext":"prod","destination":"eu-west-1a","pvc":"db/logs","outcome":"migrated"}
+{"kubeContext":"prod","destination":"eu-west-1a","pvc":"web/cache","outcome":"skipped"}
+
+{"kubeContext":"prod","destination":"eu-west-1a","pvc":"db/data","outcome":"migrated"}
+{"kubeContext":"prod","destination":"eu-west-1a","pvc":"web/assets","outcome":"migrated"}
+{"kubeContext":"prod","destination":"eu-west-1a","pvc":"web/assets","outcome":"incomplete"}
+{"kubeContext":"prod","destination":"eu-west-1b","pvc":"web/static","outcome":"migrated"}
+{"kubeContext":"staging","destination":"eu-west-1a","pvc":"web/uploads","outcome":"migrated"}
+`
+	require.NoError(t, os.WriteFile(path, []byte(lines), 0600))
+
+	done, err := MigratedPVCs(path, StateKey{KubeContext: "prod", Destination: "eu-west-1a"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"db/data": true, "db/logs": true}, done)
+}
@@ -0,0 +1,77 @@
+package migrator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// Defaults of the retry policy of a step
+const (
+	DefaultStepMaxAttempts = 3
+	DefaultStepBackoff     = 2 * time.Second
+	DefaultStepMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy decides how often a step is attempted again when its call fails
+// with a transient error. Zero fields take the defaults.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts of a call, including the first; 1 disables retries
+	Backoff     time.Duration // Wait before the second attempt, doubling after each one
+	MaxBackoff  time.Duration // Cap of the wait
+}
+
+// withDefaults returns the policy with its zero fields set to the defaults
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultStepMaxAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultStepBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = max(DefaultStepMaxBackoff, p.Backoff)
+	}
+	return p
+}
+
+// transient reports whether a step that failed with err may succeed if its call
+// is made again: 5xx, timeouts and throttling from EC2 or the API server, and
+// conflicts with concurrent changes to Kubernetes objects
+func transient(err error) bool {
+	return aws.IsTransient(err) || k8s.IsTransient(err)
+}
+
+// retryStep calls fn until it succeeds, fails with an error that is not
+// transient, or was attempted as often as the policy allows, backing off with
+// jitter in between. It returns the last error, or ctx's when it is done while
+// backing off. The EC2 SDK's own retries happen inside each attempt.
+func (m *Migrator) retryStep(ctx context.Context, pvcName string, step Step, fn func() error) error {
+	policy := m.config.StepRetry.withDefaults()
+	backoff := pollBackoff{initial: policy.Backoff, max: policy.MaxBackoff}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= policy.MaxAttempts || !transient(err) {
+			return err
+		}
+
+		wait := backoff.next()
+		slog.Warn("step failed with a transient error, retrying", "pvc", pvcName, "step", step.String(),
+			"attempt", attempt, "maxAttempts", policy.MaxAttempts, "wait", wait, "error", err)
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt),
+			attribute.String("retry.error", err.Error()),
+		))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
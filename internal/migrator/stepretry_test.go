@@ -0,0 +1,80 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRetryPolicy_WithDefaults(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, RetryPolicy{MaxAttempts: DefaultStepMaxAttempts, Backoff: DefaultStepBackoff, MaxBackoff: DefaultStepMaxBackoff},
+		RetryPolicy{}.withDefaults())
+	assert.Equal(t, RetryPolicy{MaxAttempts: 1, Backoff: time.Minute, MaxBackoff: time.Minute},
+		RetryPolicy{MaxAttempts: 1, Backoff: time.Minute}.withDefaults(), "the cap is never below the first wait")
+}
+
+func TestRetryStep(t *testing.T) {
+	t.Parallel()
+
+	serverError := &smithy.GenericAPIError{Code: "RequestTimeout"}
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "persistentvolumes"}, "data-static", errors.New("modified"))
+	forbidden := errors.New("UnauthorizedOperation")
+
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		errs         []error // Returned by successive attempts; nil once they run out
+		wantErr      error
+		wantAttempts int
+	}{
+		{name: "succeeds first time", maxAttempts: 3, wantAttempts: 1},
+		{name: "retries EC2 errors", maxAttempts: 3, errs: []error{serverError, serverError}, wantAttempts: 3},
+		{name: "retries conflicts", maxAttempts: 3, errs: []error{conflict}, wantAttempts: 2},
+		{name: "gives up", maxAttempts: 2, errs: []error{serverError, conflict, serverError}, wantErr: conflict, wantAttempts: 2},
+		{name: "permanent errors fail at once", maxAttempts: 3, errs: []error{forbidden}, wantErr: forbidden, wantAttempts: 1},
+		{name: "disabled", maxAttempts: 1, errs: []error{serverError}, wantErr: serverError, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := New(&Config{StepRetry: RetryPolicy{MaxAttempts: tt.maxAttempts, Backoff: time.Millisecond}}, nil, nil)
+			attempts := 0
+			err := m.retryStep(context.Background(), "db/data-0", StepCreatePV, func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
+
+func TestRetryStep_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{StepRetry: RetryPolicy{Backoff: time.Hour}}, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := m.retryStep(ctx, "db/data-0", StepSnapshot, func() error {
+		attempts++
+		cancel()
+		return &smithy.GenericAPIError{Code: "RequestTimeout"}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts, "no attempt after the context is done")
+}
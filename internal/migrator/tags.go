@@ -0,0 +1,87 @@
+package migrator
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// tagTemplateData is what the values of Config.Tags are rendered with:
+// {{.Namespace}}, {{.PVCName}} and {{.RunID}}. A map rather than a struct so
+// config validation can render them the same way without importing this package.
+func tagTemplateData(namespace, pvcName, runID string) map[string]string {
+	return map[string]string{"Namespace": namespace, "PVCName": pvcName, "RunID": runID}
+}
+
+// templateTags renders the values of templates, Go templates of the tags every
+// snapshot and volume gets, for a PVC. A value that fails to render is dropped
+// with a warning; config validation renders them all before the run.
+func templateTags(templates map[string]string, namespace, pvcName, runID string) map[string]string {
+	if len(templates) == 0 {
+		return nil
+	}
+	data := tagTemplateData(namespace, pvcName, runID)
+	tags := make(map[string]string, len(templates))
+	for key, text := range templates {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			slog.Warn("tag template is invalid, ignoring it", "key", key, "error", err)
+			continue
+		}
+		var value bytes.Buffer
+		if err := tmpl.Execute(&value, data); err != nil {
+			slog.Warn("tag template failed to render, ignoring it", "key", key, "error", err)
+			continue
+		}
+		tags[key] = value.String()
+	}
+	return tags
+}
+
+// pvcTags returns the tags of the snapshot and new volume of a PVC besides the
+// tool's own: those of Config.Tags, overridden by those its annotations ask for
+func (m *Migrator) pvcTags(namespace, pvcName string, annotations map[string]string) map[string]string {
+	tags := templateTags(m.config.Tags, namespace, pvcName, m.config.MigrationID)
+	for key, value := range annotationTags(annotations, m.config.TagAnnotationPrefix) {
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+// annotationTags returns the tags a PVC asks for with annotations starting with
+// prefix, named by the rest of the annotation: with the prefix
+// pv-zone-migrator.io/tag-, the annotation pv-zone-migrator.io/tag-costcenter: "42"
+// asks for the tag costcenter=42. It returns nil when prefix is empty.
+func annotationTags(annotations map[string]string, prefix string) map[string]string {
+	if prefix == "" {
+		return nil
+	}
+	var tags map[string]string
+	for name, value := range annotations {
+		key, ok := strings.CutPrefix(name, prefix)
+		if !ok || key == "" {
+			continue
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+// formatTags renders tags as key=value pairs sorted by key
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
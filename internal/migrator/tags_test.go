@@ -0,0 +1,112 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+func TestAnnotationTags(t *testing.T) {
+	t.Parallel()
+
+	annotations := map[string]string{
+		"pv-zone-migrator.io/tag-costcenter": "42",
+		"pv-zone-migrator.io/tag-team":       "data",
+		"pv-zone-migrator.io/tag-":           "ignored",
+		"example.com/tag-owner":              "ignored",
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		want   map[string]string
+	}{
+		{name: "disabled", prefix: "", want: nil},
+		{name: "matching annotations", prefix: "pv-zone-migrator.io/tag-", want: map[string]string{"costcenter": "42", "team": "data"}},
+		{name: "no match", prefix: "other.io/tag-", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, annotationTags(annotations, tt.prefix))
+		})
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, formatTags(nil))
+	assert.Equal(t, "costcenter=42, team=data", formatTags(map[string]string{"team": "data", "costcenter": "42"}))
+}
+
+func TestTemplateTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		templates map[string]string
+		want      map[string]string
+	}{
+		{name: "none", templates: nil, want: nil},
+		{name: "plain value", templates: map[string]string{"team": "data"}, want: map[string]string{"team": "data"}},
+		{
+			name:      "variables",
+			templates: map[string]string{"owner": "{{.Namespace}}/{{.PVCName}}", "run": "pvc-migrator-{{.RunID}}"},
+			want:      map[string]string{"owner": "db/data-0", "run": "pvc-migrator-run-1"},
+		},
+		{name: "unknown variable dropped", templates: map[string]string{"team": "data", "bad": "{{.Cluster}}"}, want: map[string]string{"team": "data"}},
+		{name: "invalid template dropped", templates: map[string]string{"bad": "{{.PVCName"}, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, templateTags(tt.templates, "db", "data-0", "run-1"))
+		})
+	}
+}
+
+func TestRunSnapshots_TemplatedTags(t *testing.T) {
+	t.Parallel()
+
+	var objects []runtime.Object
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	objects[0].(*corev1.PersistentVolumeClaim).Annotations = map[string]string{"pv-zone-migrator.io/tag-team": "search"}
+	ec2API := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+	m := newFakeMigrator(&Config{
+		PVCList:             []string{"db/data-0"},
+		TargetZone:          "eu-west-1a",
+		MaxConcurrency:      1,
+		MigrationID:         "run-1",
+		TagAnnotationPrefix: "pv-zone-migrator.io/tag-",
+		Tags: map[string]string{
+			"team":        "data",
+			"owner":       "{{.Namespace}}/{{.PVCName}}",
+			"migration":   "{{.RunID}}",
+			aws.TagStaged: "false",
+			"costcenter":  "42",
+		},
+	}, ec2API, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	want := map[string]string{"team": "search", "owner": "db/data-0", "migration": "run-1", aws.TagStaged: "false", "costcenter": "42"}
+	assert.Equal(t, want, plan.Items[0].Tags, "the plan lists the rendered tags, annotations winning")
+
+	m.RunSnapshots(context.Background())
+
+	tags := ec2API.snapshotTags()["vol-0"]
+	assert.Equal(t, "search", tags["team"], "annotations win over the config")
+	assert.Equal(t, "db/data-0", tags["owner"])
+	assert.Equal(t, "run-1", tags["migration"])
+	assert.Equal(t, "42", tags["costcenter"])
+	assert.Equal(t, "true", tags[aws.TagStaged], "the tool's own tags win")
+}
@@ -0,0 +1,133 @@
+package migrator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// Terraform resource types of the AWS resources a run creates
+const (
+	TerraformSnapshot = "aws_ebs_snapshot"
+	TerraformVolume   = "aws_ebs_volume"
+)
+
+// CreatedResource is an AWS resource created for a PVC, to be reconciled with the
+// infrastructure code that manages the account
+type CreatedResource struct {
+	Type       string // TerraformSnapshot or TerraformVolume
+	ID         string
+	PVC        string // Full name, "namespace/pvcname"
+	Source     string // Volume a snapshot was taken of, or snapshot a volume was created from
+	Zone       string // Volumes only
+	VolumeType string // Volumes only
+	SizeGiB    int32
+	Tags       map[string]string
+}
+
+// CreatedResources returns the snapshots and volumes created for the PVCs, sorted
+// by PVC, including those of PVCs that failed later. Snapshots adopted from the
+// snapshot command are included, as it created them for this migration.
+func (m *Migrator) CreatedResources() []CreatedResource {
+	statuses := m.GetStatuses()
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var resources []CreatedResource
+	for _, name := range names {
+		s := statuses[name]
+		if s.SnapshotID != "" {
+			tags := aws.SnapshotTags(s.PVCName)
+			if s.StagedSnapshot {
+				tags = aws.StagedSnapshotTags(s.Namespace, s.PVCName)
+			}
+			resources = append(resources, CreatedResource{
+				Type:    TerraformSnapshot,
+				ID:      s.SnapshotID,
+				PVC:     name,
+				Source:  s.OldVolumeID,
+				SizeGiB: s.SizeGiB,
+				Tags:    tags,
+			})
+		}
+		if s.NewVolumeID != "" {
+			zone := s.TargetZone
+			if zone == "" {
+				zone = m.config.TargetZone
+			}
+			resources = append(resources, CreatedResource{
+				Type:       TerraformVolume,
+				ID:         s.NewVolumeID,
+				PVC:        name,
+				Source:     s.SnapshotID,
+				Zone:       zone,
+				VolumeType: s.VolumeSpec.VolumeType(),
+				SizeGiB:    s.SizeGiB,
+				Tags:       aws.VolumeTags(s.Namespace, s.PVCName),
+			})
+		}
+	}
+	return resources
+}
+
+// invalidTerraformName matches the characters not allowed in Terraform resource names
+var invalidTerraformName = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// terraformName turns a PVC name into a unique Terraform resource name
+func terraformName(pvc string, used map[string]bool) string {
+	name := invalidTerraformName.ReplaceAllString(pvc, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "pvc_" + name
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// FormatTerraformImports renders the resources as Terraform import blocks, each
+// preceded by comments with the PVC, parameters and tags it was created with, so
+// it can be applied as is or read as a drift report
+func FormatTerraformImports(resources []CreatedResource, generatedAt time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# AWS resources created by pvc-migrator, %s\n", generatedAt.UTC().Format(time.RFC3339)))
+	b.WriteString("# Import them with Terraform 1.5 or later, generating their configuration with:\n")
+	b.WriteString("#   terraform plan -generate-config-out=pvc-migrator.tf\n")
+	if len(resources) == 0 {
+		b.WriteString("\n# No resources were created.\n")
+		return b.String()
+	}
+
+	used := map[string]map[string]bool{TerraformSnapshot: {}, TerraformVolume: {}}
+	for _, r := range resources {
+		b.WriteString("\n")
+		switch r.Type {
+		case TerraformSnapshot:
+			b.WriteString(fmt.Sprintf("# %s: snapshot of %s (%d GiB)\n", r.PVC, r.Source, r.SizeGiB))
+		case TerraformVolume:
+			b.WriteString(fmt.Sprintf("# %s: %s volume in %s from %s (%d GiB)\n", r.PVC, r.VolumeType, r.Zone, r.Source, r.SizeGiB))
+		}
+		keys := make([]string, 0, len(r.Tags))
+		for k := range r.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(fmt.Sprintf("#   tag %s = %q\n", k, r.Tags[k]))
+		}
+		b.WriteString("import {\n")
+		b.WriteString(fmt.Sprintf("  to = %s.%s\n", r.Type, terraformName(r.PVC, used[r.Type])))
+		b.WriteString(fmt.Sprintf("  id = %q\n", r.ID))
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatedResources(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"db/data-0", "db/data-1", "db/data-2"}, TargetZone: "eu-west-1a", MaxConcurrency: 1}, nil, nil)
+	done := m.statuses["db/data-0"]
+	done.Step = StepDone
+	done.OldVolumeID = "vol-old"
+	done.SnapshotID = "snap-0"
+	done.NewVolumeID = "vol-new"
+	done.SizeGiB = 10
+	failed := m.statuses["db/data-1"]
+	failed.Step = StepFailed
+	failed.SnapshotID = "snap-1"
+	failed.StagedSnapshot = true
+	m.statuses["db/data-2"].Step = StepSkipped
+
+	resources := m.CreatedResources()
+	require.Len(t, resources, 3)
+
+	assert.Equal(t, CreatedResource{
+		Type:    TerraformSnapshot,
+		ID:      "snap-0",
+		PVC:     "db/data-0",
+		Source:  "vol-old",
+		SizeGiB: 10,
+		Tags:    map[string]string{"Name": "migrate-data-0", "MigratedPVC": "data-0"},
+	}, resources[0])
+
+	volume := resources[1]
+	assert.Equal(t, TerraformVolume, volume.Type)
+	assert.Equal(t, "vol-new", volume.ID)
+	assert.Equal(t, "snap-0", volume.Source)
+	assert.Equal(t, "eu-west-1a", volume.Zone)
+	assert.Equal(t, "gp3", volume.VolumeType)
+	assert.Equal(t, "db", volume.Tags["kubernetes.io/created-for/pvc/namespace"])
+
+	assert.Equal(t, "snap-1", resources[2].ID, "a failed PVC's snapshot is still listed")
+	assert.Equal(t, "db/data-1", resources[2].Tags["pvc-migrator/pvc"], "staged snapshots carry the snapshot command's tags")
+}
+
+func TestFormatTerraformImports(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	out := FormatTerraformImports([]CreatedResource{
+		{Type: TerraformSnapshot, ID: "snap-0", PVC: "db/data.0", Source: "vol-old", SizeGiB: 10, Tags: map[string]string{"Name": "migrate-data.0"}},
+		{Type: TerraformVolume, ID: "vol-new", PVC: "db/data.0", Source: "snap-0", Zone: "eu-west-1a", VolumeType: "gp3", SizeGiB: 10},
+		{Type: TerraformVolume, ID: "vol-other", PVC: "db/data_0", Source: "snap-9", Zone: "eu-west-1a", VolumeType: "gp3", SizeGiB: 1},
+	}, at)
+
+	assert.Contains(t, out, "# AWS resources created by pvc-migrator, 2026-10-16T12:00:00Z")
+	assert.Contains(t, out, "# db/data.0: snapshot of vol-old (10 GiB)\n#   tag Name = \"migrate-data.0\"\nimport {\n  to = aws_ebs_snapshot.db_data_0\n  id = \"snap-0\"\n}\n")
+	assert.Contains(t, out, "# db/data.0: gp3 volume in eu-west-1a from snap-0 (10 GiB)\nimport {\n  to = aws_ebs_volume.db_data_0\n")
+	assert.Contains(t, out, "to = aws_ebs_volume.db_data_0_2", "names stay unique")
+
+	empty := FormatTerraformImports(nil, at)
+	assert.Contains(t, empty, "No resources were created")
+	assert.NotContains(t, empty, "import {")
+}
+
+func TestTerraformName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pvc  string
+		want string
+	}{
+		{pvc: "db/data-0", want: "db_data-0"},
+		{pvc: "1ns/data", want: "pvc_1ns_data"},
+		{pvc: "ns/a.b", want: "ns_a_b"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pvc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, terraformName(tc.pvc, map[string]bool{}))
+		})
+	}
+}
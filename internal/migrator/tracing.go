@@ -0,0 +1,81 @@
+package migrator
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/tracing"
+)
+
+// tracer creates one root span per PVC with a child span for each step
+var tracer = otel.Tracer("github.com/cesarempathy/pv-zone-migrator/internal/migrator")
+
+// stepSpans keeps the span of the step currently running for a PVC. Starting a
+// step ends the previous one, so the spans line up as a timeline under the root.
+type stepSpans struct {
+	tracer  trace.Tracer
+	root    context.Context
+	current trace.Span
+}
+
+// start ends the running step span and opens one for step. The returned context
+// carries the new span so client calls are nested below it.
+func (s *stepSpans) start(step Step) context.Context {
+	s.end(nil)
+	ctx, span := s.tracer.Start(s.root, stepSpanName(step))
+	s.current = span
+	return ctx
+}
+
+// end closes the running step span, marking it failed when err is set
+func (s *stepSpans) end(err error) {
+	if s.current == nil {
+		return
+	}
+	tracing.End(s.current, err)
+	s.current = nil
+}
+
+// stepSpanName returns the span name used for a migration step
+func stepSpanName(step Step) string {
+	switch step {
+	case StepPending:
+		return "pending"
+	case StepGetInfo:
+		return "get-info"
+	case StepSkipped:
+		return "skipped"
+	case StepSnapshot:
+		return "snapshot"
+	case StepWaitSnapshot:
+		return "wait-snapshot"
+	case StepCreateVolume:
+		return "create-volume"
+	case StepWaitVolume:
+		return "wait-volume"
+	case StepCleanup:
+		return "cleanup"
+	case StepCreatePV:
+		return "create-pv"
+	case StepCreatePVC:
+		return "create-pvc"
+	case StepDone:
+		return "done"
+	case StepFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// statusError returns the error recorded for a PVC, if any
+func (m *Migrator) statusError(pvcName string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if s, ok := m.statuses[pvcName]; ok {
+		return s.Error
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStepSpans(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tr := provider.Tracer("test")
+
+	rootCtx, root := tr.Start(context.Background(), "migrate PVC")
+	spans := &stepSpans{tracer: tr, root: rootCtx}
+
+	spans.start(StepSnapshot)
+	spans.start(StepWaitSnapshot)
+	spans.end(errors.New("snapshot failed"))
+	spans.end(nil) // no span running: must not panic
+	root.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+
+	assert.Equal(t, "snapshot", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Equal(t, root.SpanContext().SpanID(), ended[0].Parent().SpanID())
+
+	assert.Equal(t, "wait-snapshot", ended[1].Name())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, "snapshot failed", ended[1].Status().Description)
+	assert.Equal(t, root.SpanContext().SpanID(), ended[1].Parent().SpanID())
+	assert.True(t, !ended[1].StartTime().Before(ended[0].EndTime()), "steps must not overlap")
+
+	assert.Equal(t, "migrate PVC", ended[2].Name())
+}
+
+func TestStepSpanName(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]Step)
+	for step := StepPending; step <= StepFailed; step++ {
+		name := stepSpanName(step)
+		assert.NotEqual(t, "unknown", name, "step %s has no span name", step)
+		if prev, ok := seen[name]; ok {
+			t.Errorf("span name %q used by both %s and %s", name, prev, step)
+		}
+		seen[name] = step
+	}
+	assert.Equal(t, "unknown", stepSpanName(Step(99)))
+}
+
+func TestStatusError(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/data"}}, nil, nil)
+	require.NoError(t, m.statusError("ns/data"))
+	assert.NoError(t, m.statusError("ns/missing"))
+
+	m.updateStatus("ns/data", StepSnapshot, 0, errors.New("boom"))
+	assert.EqualError(t, m.statusError("ns/data"), "boom")
+}
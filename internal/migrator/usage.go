@@ -0,0 +1,36 @@
+package migrator
+
+import (
+	"strings"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// APIUsage returns the calls the run made so far to EC2, CloudWatch and the
+// Kubernetes API, by operation
+func (m *Migrator) APIUsage() []apiusage.Count {
+	var counts []apiusage.Count
+	if m.awsClient != nil {
+		counts = append(counts, m.awsClient.APIUsage()...)
+	}
+	if m.k8sClient != nil {
+		counts = append(counts, m.k8sClient.APIUsage()...)
+	}
+	return counts
+}
+
+// FormatAPIUsage renders the calls made to each service with the throttled ones,
+// e.g. "EC2 412 (3 throttled), Kubernetes 96", or "" when none were counted
+func FormatAPIUsage(counts []apiusage.Count) string {
+	totals := apiusage.Totals(counts)
+	parts := make([]string, 0, len(totals))
+	for _, t := range totals {
+		part := i18n.T("summary.api_service", t.Service, t.Calls)
+		if t.Throttled > 0 {
+			part += " " + i18n.T("summary.api_throttled", t.Throttled)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
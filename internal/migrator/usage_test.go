@@ -0,0 +1,40 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/apiusage"
+)
+
+func TestFormatAPIUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		counts []apiusage.Count
+		want   string
+	}{
+		{
+			name: "none counted",
+			want: "",
+		},
+		{
+			name: "totals by service",
+			counts: []apiusage.Count{
+				{Service: "EC2", Operation: "CreateSnapshot", Calls: 2},
+				{Service: "EC2", Operation: "DescribeSnapshots", Calls: 40, Throttled: 3},
+				{Service: apiusage.ServiceKubernetes, Operation: "get persistentvolumeclaims", Calls: 6},
+			},
+			want: "EC2 42 (3 throttled), Kubernetes 6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, FormatAPIUsage(tt.counts))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package migrator
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// backupVolume starts an AWS Backup job backing the PVC's source volume up to
+// the configured vault, now that its workloads no longer write to it. The job
+// runs on after the migration; the volume is retained with its PV, so it is
+// still there when the job starts. Failing to start it is recorded as a warning
+// and the migration goes on, its snapshot then the only restore point.
+func (m *Migrator) backupVolume(ctx context.Context, pvcName, volumeID string) {
+	vault := m.config.BackupVault
+	var jobID string
+	err := m.retryStep(ctx, pvcName, StepSnapshot, func() (err error) {
+		// The token makes a retry, or a resumed run, return the job already started
+		jobID, err = m.awsClient.StartVolumeBackup(ctx, vault, volumeID, pvcName,
+			aws.ClientToken(m.config.MigrationID, pvcName, volumeID, vault.Name))
+		return err
+	})
+	if err != nil {
+		m.AddWarning(Warning{
+			PVC:     pvcName,
+			Message: i18n.T("warn.vault_failed", volumeID, vault.Name, err),
+			Action:  i18n.T("warn.vault_action"),
+		})
+		return
+	}
+	slog.Info("volume backup started", "pvc", pvcName, "volumeId", volumeID, "vault", vault.Name, "jobId", jobID)
+	m.mu.Lock()
+	m.statuses[pvcName].BackupJobID = jobID
+	m.mu.Unlock()
+}
@@ -0,0 +1,91 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// fakeBackup records the volumes AWS Backup is asked to back up
+type fakeBackup struct {
+	mu        sync.Mutex
+	err       error
+	resources []string
+}
+
+func (f *fakeBackup) StartBackupJob(_ context.Context, params *backup.StartBackupJobInput, _ ...func(*backup.Options)) (*backup.StartBackupJobOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.resources = append(f.resources, awssdk.ToString(params.ResourceArn))
+	return &backup.StartBackupJobOutput{BackupJobId: awssdk.String("job-1")}, nil
+}
+
+// fakeCaller is the identity of the migration role
+type fakeCaller struct{}
+
+func (fakeCaller) GetCallerIdentity(context.Context, *sts.GetCallerIdentityInput, ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Arn:     awssdk.String("arn:aws:sts::123456789012:assumed-role/migrator/pvc-migrator"),
+		Account: awssdk.String("123456789012"),
+	}, nil
+}
+
+func TestMigrator_BackupVault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		err         error
+		wantJobID   string
+		wantWarning bool
+	}{
+		{name: "backed up", wantJobID: "job-1"},
+		{name: "backup fails", err: errors.New("AccessDeniedException"), wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vault := &fakeBackup{err: tt.err}
+			m := New(&Config{
+				PVCList:        []string{"shop/data"},
+				TargetZone:     "eu-west-1a",
+				StorageClass:   "gp3",
+				MaxConcurrency: 1,
+				StepRetry:      RetryPolicy{MaxAttempts: 1},
+				BackupVault:    aws.BackupVault{Name: "compliance", RoleARN: "arn:aws:iam::123456789012:role/backup"},
+			}, k8s.NewClientWithInterface(bindingClientset(boundClaim("shop", "data", "vol-old")...), nil), aws.NewEC2ClientWithBackup(&fakeEC2{
+				zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+				created: map[string]string{"snap-vol-old": "vol-new"},
+			}, vault, fakeCaller{}))
+			m.Run(context.Background())
+
+			status := m.GetStatuses()["shop/data"]
+			require.NoError(t, status.Error)
+			assert.Equal(t, StepDone, status.Step)
+			assert.Equal(t, tt.wantJobID, status.BackupJobID)
+			assert.Equal(t, tt.wantJobID, m.Result().PVCs[0].BackupJobID)
+			if tt.wantWarning {
+				require.Len(t, m.Warnings(), 1)
+				assert.Contains(t, m.Warnings()[0].Message, "AccessDeniedException")
+			} else {
+				assert.Equal(t, []string{"arn:aws:ec2::123456789012:volume/vol-old"}, vault.resources)
+				assert.Empty(t, m.Warnings())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// volumeSpec returns the type and performance the new volume of the source
+// volume is created with: those of the source volume, overridden by the run's.
+// A type given for the run drops the performance of a source of another type.
+// With a nil source only the run's apply.
+func (m *Migrator) volumeSpec(source *aws.VolumeInfo) aws.VolumeSpec {
+	var spec aws.VolumeSpec
+	if source != nil {
+		spec = aws.SourceVolumeSpec(source)
+	}
+	if m.config.VolumeType != "" && m.config.VolumeType != spec.VolumeType() {
+		spec = aws.VolumeSpec{Type: m.config.VolumeType}
+	}
+	if m.config.VolumeIOPS > 0 {
+		spec.IOPS = m.config.VolumeIOPS
+	}
+	if m.config.VolumeThroughput > 0 {
+		spec.Throughput = m.config.VolumeThroughput
+	}
+	if spec.Type == aws.VolumeType {
+		spec.Type = ""
+	}
+	return spec
+}
+
+// checkVolumeSpecs validates the volume each PVC to migrate is created as
+// against the limits of its type, such as the IOPS per GiB of io2, and fails
+// those EC2 would reject in the plan. They are returned with the reason, by
+// name. PVCs resuming from an earlier run have their volume already.
+func checkVolumeSpecs(items []PVCPlanItem) map[string]string {
+	failed := make(map[string]string)
+	for i := range items {
+		item := &items[i]
+		if item.Action != PlanActionMigrate || item.ResumeAt != StepPending {
+			continue
+		}
+		if err := item.VolumeSpec.Validate(item.CapacityGi); err != nil {
+			item.Action = PlanActionError
+			item.Reason = fmt.Sprintf("new volume: %v", err)
+			failed[item.Name] = item.Reason
+		}
+	}
+	return failed
+}
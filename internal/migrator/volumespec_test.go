@@ -0,0 +1,119 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestGeneratePlan_VolumeSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		source     ec2types.Volume
+		volumeType string
+		iops       int32
+		throughput int32
+		wantSpec   aws.VolumeSpec
+		wantReason string
+	}{
+		{name: "gp3 by default"},
+		{
+			name:     "io2 of the old volume",
+			source:   ec2types.Volume{VolumeType: ec2types.VolumeTypeIo2, Iops: awssdk.Int32(8000)},
+			wantSpec: aws.VolumeSpec{Type: "io2", IOPS: 8000},
+		},
+		{
+			name:     "gp3 performance of the old volume",
+			source:   ec2types.Volume{VolumeType: ec2types.VolumeTypeGp3, Iops: awssdk.Int32(4000), Throughput: awssdk.Int32(250)},
+			wantSpec: aws.VolumeSpec{IOPS: 4000, Throughput: 250},
+		},
+		{
+			name:     "iops overridden",
+			source:   ec2types.Volume{VolumeType: ec2types.VolumeTypeIo2, Iops: awssdk.Int32(8000)},
+			iops:     2000,
+			wantSpec: aws.VolumeSpec{Type: "io2", IOPS: 2000},
+		},
+		{
+			name:       "type overridden",
+			source:     ec2types.Volume{VolumeType: ec2types.VolumeTypeIo2, Iops: awssdk.Int32(8000)},
+			volumeType: "gp3",
+		},
+		{name: "gp2 becomes gp3", source: ec2types.Volume{VolumeType: ec2types.VolumeTypeGp2, Iops: awssdk.Int32(100)}},
+		{name: "io2", volumeType: "io2", iops: 5000, wantSpec: aws.VolumeSpec{Type: "io2", IOPS: 5000}},
+		{
+			name:       "io2 above its IOPS per GiB",
+			volumeType: "io2",
+			iops:       16000,
+			wantReason: "new volume: io2 volumes have at most 1000 IOPS per GiB: 16000 IOPS need at least 16 GiB, not 10 GiB",
+		},
+		{name: "io2 without IOPS", volumeType: "io2", wantReason: "new volume: io2 volumes need their IOPS set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var objects []runtime.Object
+			objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+			m := newFakeMigrator(&Config{
+				PVCList:          []string{"db/data-0"},
+				TargetZone:       "eu-west-1a",
+				VolumeType:       tt.volumeType,
+				VolumeIOPS:       tt.iops,
+				VolumeThroughput: tt.throughput,
+			}, &fakeEC2{
+				zones: map[string]string{"vol-0": "eu-west-1b"},
+				perf:  map[string]ec2types.Volume{"vol-0": tt.source},
+			}, objects...)
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+			require.Len(t, plan.Items, 1)
+			item := plan.Items[0]
+			if tt.wantReason != "" {
+				assert.Equal(t, PlanActionError, item.Action)
+				assert.Equal(t, tt.wantReason, item.Reason)
+				assert.Equal(t, tt.wantReason, m.blocked[item.Name], "the run does not create it either")
+				return
+			}
+			assert.Equal(t, PlanActionMigrate, item.Action)
+			assert.Equal(t, tt.wantSpec, item.VolumeSpec)
+			assert.Empty(t, m.blocked)
+		})
+	}
+}
+
+func TestRun_VolumeSpecOfOldVolume(t *testing.T) {
+	t.Parallel()
+
+	ec2Fake := &fakeEC2{
+		zones:   map[string]string{"vol-old": "eu-west-1b", "vol-new": "eu-west-1a"},
+		created: map[string]string{"snap-vol-old": "vol-new"},
+		perf:    map[string]ec2types.Volume{"vol-old": {VolumeType: ec2types.VolumeTypeIo2, Iops: awssdk.Int32(8000)}},
+	}
+	m := New(&Config{
+		PVCList:        []string{"shop/data"},
+		TargetZone:     "eu-west-1a",
+		StorageClass:   "gp3",
+		MaxConcurrency: 1,
+		StepRetry:      RetryPolicy{MaxAttempts: 1},
+	}, k8s.NewClientWithInterface(bindingClientset(boundClaim("shop", "data", "vol-old")...), nil), aws.NewEC2ClientWithInterface(ec2Fake))
+	m.Run(context.Background())
+
+	status := m.GetStatuses()["shop/data"]
+	require.NoError(t, status.Error)
+	assert.Equal(t, aws.VolumeSpec{Type: "io2", IOPS: 8000}, status.VolumeSpec)
+	require.Len(t, ec2Fake.newVolumes, 1)
+	assert.Equal(t, ec2types.VolumeTypeIo2, ec2Fake.newVolumes[0].VolumeType)
+	assert.Equal(t, int32(8000), awssdk.ToInt32(ec2Fake.newVolumes[0].Iops))
+}
@@ -0,0 +1,76 @@
+package migrator
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Warning is a problem that did not fail the run but needs an operator to follow
+// up, such as workloads that could not be scaled back up. Warnings are collected
+// during the run and re-printed in the summary so they do not scroll away.
+type Warning struct {
+	Time    time.Time
+	PVC     string // Full PVC name, empty for warnings that concern the whole run
+	Message string
+	Action  string // What the operator should do, e.g. the kubectl commands to run
+}
+
+// WarningListener is called for every warning as it is recorded. Listeners must be
+// safe for concurrent use.
+type WarningListener func(Warning)
+
+// AddWarningListener registers a listener for warnings
+func (m *Migrator) AddWarningListener(l WarningListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warningListeners = append(m.warningListeners, l)
+}
+
+// AddWarning records a warning for the end-of-run summary
+func (m *Migrator) AddWarning(w Warning) {
+	if w.Time.IsZero() {
+		w.Time = time.Now()
+	}
+	slog.Warn(w.Message, "pvc", w.PVC, "action", w.Action)
+
+	m.mu.Lock()
+	m.warnings = append(m.warnings, w)
+	listeners := m.warningListeners
+	m.mu.Unlock()
+
+	for _, l := range listeners {
+		l(w)
+	}
+}
+
+// Warnings returns a copy of the warnings recorded so far, oldest first
+func (m *Migrator) Warnings() []Warning {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Warning(nil), m.warnings...)
+}
+
+// FormatWarnings renders the warnings as a runbook section, so the runbook file
+// records the follow-ups the operator still owes after the run
+func FormatWarnings(warnings []Warning) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Action required\n\n")
+	for _, w := range warnings {
+		title := w.Message
+		if w.PVC != "" {
+			title = w.PVC + ": " + w.Message
+		}
+		b.WriteString(fmt.Sprintf("- %s (%s)\n", title, w.Time.UTC().Format(time.RFC3339)))
+		if w.Action != "" {
+			b.WriteString("\n```\n" + w.Action + "\n```\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Warnings(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{PVCList: []string{"ns/data"}}, nil, nil)
+	assert.Empty(t, m.Warnings())
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.AddWarning(Warning{Time: at, Message: "workloads not restored", Action: "kubectl scale ..."})
+	m.AddWarning(Warning{PVC: "ns/data", Message: "warm-up job not created"})
+
+	warnings := m.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, at, warnings[0].Time)
+	assert.Equal(t, "workloads not restored", warnings[0].Message)
+	assert.Equal(t, "ns/data", warnings[1].PVC)
+	assert.False(t, warnings[1].Time.IsZero(), "time defaults to now")
+
+	// The returned slice is a copy
+	warnings[0].Message = "changed"
+	assert.Equal(t, "workloads not restored", m.Warnings()[0].Message)
+}
+
+func TestMigrator_WarningListener(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{}, nil, nil)
+	var got []Warning
+	m.AddWarningListener(func(w Warning) { got = append(got, w) })
+
+	m.AddWarning(Warning{PVC: "ns/data", Message: "warm-up job not created"})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "ns/data", got[0].PVC)
+	assert.False(t, got[0].Time.IsZero())
+}
+
+func TestFormatWarnings(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, FormatWarnings(nil))
+
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	out := FormatWarnings([]Warning{
+		{Time: at, Message: "workloads not restored", Action: "kubectl scale deployment app --replicas=2 -n ns"},
+		{Time: at, PVC: "ns/data", Message: "warm-up job not created"},
+	})
+
+	assert.Contains(t, out, "## Action required")
+	assert.Contains(t, out, "- workloads not restored (2024-01-01T12:00:00Z)")
+	assert.Contains(t, out, "```\nkubectl scale deployment app --replicas=2 -n ns\n```")
+	assert.Contains(t, out, "- ns/data: warm-up job not created")
+}
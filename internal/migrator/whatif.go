@@ -0,0 +1,70 @@
+package migrator
+
+import (
+	"math"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+// Proposal is the volume type and performance a what-if analysis compares the
+// PVCs' volumes with
+type Proposal struct {
+	Spec aws.VolumeSpec
+	// IOPSPerGiB gives each volume IOPS in proportion to its size, as the
+	// iopsPerGB of a storage class, when Spec.IOPS is not set
+	IOPSPerGiB int32
+}
+
+// specFor returns the spec the proposal gives a volume of the size
+func (p Proposal) specFor(sizeGiB int32) aws.VolumeSpec {
+	spec := p.Spec
+	if spec.IOPS == 0 && p.IOPSPerGiB > 0 {
+		spec.IOPS = int32(min(int64(sizeGiB)*int64(p.IOPSPerGiB), math.MaxInt32))
+	}
+	return spec
+}
+
+// WhatIf is the performance and monthly cost of a PVC's volume as it is and as
+// the proposal would make it
+type WhatIf struct {
+	VolumeZone
+	Spec     aws.VolumeSpec // What the proposal gives the volume
+	Current  aws.VolumePerformance
+	Proposed aws.VolumePerformance
+	// CurrentCost and ProposedCost are in USD a month at the prices of
+	// aws.PriceRegion; Priced is false when either type has no known price
+	CurrentCost  float64
+	ProposedCost float64
+	Priced       bool
+	// Error is why a volume of the size cannot be created with the proposal,
+	// such as IOPS above what its size allows; the proposal is not compared then
+	Error string
+}
+
+// WhatIfs compares each volume found in EC2 with the proposal, without
+// planning a zone change or calling AWS. Volumes that were not found are left
+// out.
+func WhatIfs(volumes []VolumeZone, p Proposal) []WhatIf {
+	var result []WhatIf
+	for _, v := range volumes {
+		if v.Zone == "" {
+			continue
+		}
+		w := WhatIf{VolumeZone: v, Spec: p.specFor(v.SizeGiB)}
+		current := aws.VolumeInfo{VolumeType: v.VolumeType, SizeGiB: v.SizeGiB, IOPS: v.IOPS, Throughput: v.Throughput}
+		w.Current = current.Performance()
+		if err := w.Spec.Validate(v.SizeGiB); err != nil {
+			w.Error = err.Error()
+			result = append(result, w)
+			continue
+		}
+		w.Proposed = w.Spec.Performance(v.SizeGiB)
+		currentCost, currentOK := aws.MonthlyCost(v.VolumeType, v.SizeGiB, w.Current)
+		proposedCost, proposedOK := aws.MonthlyCost(w.Spec.VolumeType(), v.SizeGiB, w.Proposed)
+		if currentOK && proposedOK {
+			w.CurrentCost, w.ProposedCost, w.Priced = currentCost, proposedCost, true
+		}
+		result = append(result, w)
+	}
+	return result
+}
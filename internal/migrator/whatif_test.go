@@ -0,0 +1,55 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+)
+
+func TestWhatIfs(t *testing.T) {
+	t.Parallel()
+
+	volumes := []VolumeZone{
+		{PVC: "db/data", VolumeID: "vol-1", Zone: "eu-west-1a", SizeGiB: 100, VolumeType: "gp3", IOPS: 3000, Throughput: 125},
+		{PVC: "db/logs", VolumeID: "vol-2", Zone: "eu-west-1a", SizeGiB: 2, VolumeType: "gp2", IOPS: 100},
+		{PVC: "db/gone", VolumeID: "vol-3"},
+		{PVC: "db/archive", VolumeID: "vol-4", Zone: "eu-west-1b", SizeGiB: 500, VolumeType: "io3", IOPS: 3000},
+	}
+
+	got := WhatIfs(volumes, Proposal{Spec: aws.VolumeSpec{Type: aws.VolumeTypeIO2, IOPS: 10000}})
+	require.Len(t, got, 3)
+
+	data := got[0]
+	assert.Equal(t, "db/data", data.PVC)
+	assert.Equal(t, aws.VolumePerformance{IOPS: 3000, Throughput: 125}, data.Current)
+	assert.Equal(t, aws.VolumePerformance{IOPS: 10000, Throughput: 2500}, data.Proposed)
+	assert.True(t, data.Priced)
+	assert.InDelta(t, 8, data.CurrentCost, 0.001)
+	assert.InDelta(t, 12.5+650, data.ProposedCost, 0.001)
+	assert.Empty(t, data.Error)
+
+	logs := got[1]
+	assert.Equal(t, "io2 volumes are 4 to 65536 GiB, not 2 GiB", logs.Error)
+	assert.False(t, logs.Priced)
+
+	archive := got[2]
+	assert.Empty(t, archive.Error)
+	assert.False(t, archive.Priced, "io3 has no known price")
+}
+
+func TestWhatIfs_IOPSPerGiB(t *testing.T) {
+	t.Parallel()
+
+	volumes := []VolumeZone{{PVC: "db/data", Zone: "eu-west-1a", SizeGiB: 200, VolumeType: "gp3", IOPS: 3000, Throughput: 125}}
+
+	got := WhatIfs(volumes, Proposal{Spec: aws.VolumeSpec{Type: aws.VolumeTypeIO1}, IOPSPerGiB: 50})
+	require.Len(t, got, 1)
+	assert.Equal(t, aws.VolumeSpec{Type: aws.VolumeTypeIO1, IOPS: 10000}, got[0].Spec)
+	assert.Equal(t, aws.VolumePerformance{IOPS: 10000, Throughput: 1000}, got[0].Proposed)
+
+	got = WhatIfs(volumes, Proposal{Spec: aws.VolumeSpec{Type: aws.VolumeTypeIO1, IOPS: 5000}, IOPSPerGiB: 50})
+	assert.Equal(t, int32(5000), got[0].Spec.IOPS)
+}
@@ -0,0 +1,73 @@
+package migrator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TimeWindow is a daily window of UTC time in which PVCs may be migrated, from
+// Start to End after midnight. An End before Start spans midnight.
+type TimeWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Open reports whether t falls inside the window
+func (w TimeWindow) Open(t time.Time) bool {
+	offset := sinceMidnight(t)
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// Opens returns how long after t the window next opens, or 0 when it is open
+func (w TimeWindow) Opens(t time.Time) time.Duration {
+	if w.Open(t) {
+		return 0
+	}
+	wait := w.Start - sinceMidnight(t)
+	if wait < 0 {
+		wait += 24 * time.Hour
+	}
+	return wait
+}
+
+func (w TimeWindow) String() string {
+	clock := func(d time.Duration) string {
+		return time.Time{}.Add(d).Format("15:04")
+	}
+	return clock(w.Start) + "-" + clock(w.End) + " UTC"
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	t = t.UTC()
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// windowFor returns the window of the PVC's namespace, if it has one
+func (c *Config) windowFor(pvcName string) (TimeWindow, bool) {
+	ns, _ := ParsePVCName(pvcName)
+	w, ok := c.Windows[ns]
+	return w, ok
+}
+
+// waitWindow blocks, between steps, while the window of the PVC's namespace is
+// closed, or until ctx is done
+func (m *Migrator) waitWindow(ctx context.Context, pvcName string) {
+	w, ok := m.config.windowFor(pvcName)
+	if !ok {
+		return
+	}
+	wait := w.Opens(time.Now())
+	if wait == 0 {
+		return
+	}
+	slog.Info("namespace window closed, pausing PVC until it opens", "pvc", pvcName, "window", w, "wait", wait.Round(time.Minute))
+	select {
+	case <-time.After(wait):
+		slog.Info("namespace window open, resuming PVC", "pvc", pvcName)
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,50 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeWindow(t *testing.T) {
+	t.Parallel()
+
+	night := TimeWindow{Start: 2 * time.Hour, End: 4 * time.Hour}
+	midnight := TimeWindow{Start: 23 * time.Hour, End: time.Hour}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name      string
+		window    TimeWindow
+		time      time.Time
+		wantOpen  bool
+		wantOpens time.Duration
+	}{
+		{name: "before", window: night, time: at(1, 30), wantOpens: 30 * time.Minute},
+		{name: "start", window: night, time: at(2, 0), wantOpen: true},
+		{name: "inside", window: night, time: at(3, 59), wantOpen: true},
+		{name: "end", window: night, time: at(4, 0), wantOpens: 22 * time.Hour},
+		{name: "spans_midnight_late", window: midnight, time: at(23, 30), wantOpen: true},
+		{name: "spans_midnight_early", window: midnight, time: at(0, 30), wantOpen: true},
+		{name: "spans_midnight_closed", window: midnight, time: at(12, 0), wantOpens: 11 * time.Hour},
+		{
+			name:     "other_time_zone",
+			window:   night,
+			time:     time.Date(2026, 10, 16, 5, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			wantOpen: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.wantOpen, tc.window.Open(tc.time))
+			assert.Equal(t, tc.wantOpens, tc.window.Opens(tc.time))
+		})
+	}
+
+	assert.Equal(t, "02:00-04:00 UTC", night.String())
+}
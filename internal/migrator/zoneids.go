@@ -0,0 +1,32 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// zoneIDs reads the ID of each zone of the region, by name, for the plan to
+// show, returning nil when they cannot be read
+func (m *Migrator) zoneIDs(ctx context.Context) map[string]string {
+	ids, err := m.awsClient.ZoneIDs(ctx)
+	if err != nil {
+		slog.Debug("failed to read the zone IDs, not showing them", "error", err)
+		return nil
+	}
+	return ids
+}
+
+// ZoneLabel returns the zone with its ID, e.g. "us-east-1a (use1-az4)", or the
+// zone alone when its ID is unknown. A comma-separated list of zones is labelled
+// zone by zone.
+func (p *MigrationPlan) ZoneLabel(zones string) string {
+	parts := strings.Split(zones, ", ")
+	for i, zone := range parts {
+		if id, ok := p.ZoneIDs[zone]; ok {
+			parts[i] = fmt.Sprintf("%s (%s)", zone, id)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,52 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// fakeZones serves DescribeAvailabilityZones from a zone name to ID map
+type fakeZones map[string]string
+
+func (f fakeZones) DescribeAvailabilityZones(context.Context, *ec2.DescribeAvailabilityZonesInput, ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	out := &ec2.DescribeAvailabilityZonesOutput{}
+	for name, id := range f {
+		out.AvailabilityZones = append(out.AvailabilityZones, ec2types.AvailabilityZone{ZoneName: awssdk.String(name), ZoneId: awssdk.String(id)})
+	}
+	return out, nil
+}
+
+func TestGeneratePlan_ZoneIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := fakeZones{"eu-west-1a": "euw1-az3", "eu-west-1b": "euw1-az1"}
+	fake := &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1b"}}
+	m := New(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: "eu-west-1a",
+	}, k8s.NewClientWithInterface(bindingClientset(boundClaim("db", "data-0", "vol-0")...), nil), aws.NewEC2ClientWithZones(fake, ids))
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string(ids), plan.ZoneIDs)
+	assert.Contains(t, FormatPlanPlain(plan), "Target zone: eu-west-1a (euw1-az3).")
+	assert.Equal(t, map[string]string(ids), plan.API().ZoneIDs)
+}
+
+func TestMigrationPlan_ZoneLabel(t *testing.T) {
+	t.Parallel()
+
+	plan := &MigrationPlan{ZoneIDs: map[string]string{"us-east-1a": "use1-az4", "us-east-1b": "use1-az6"}}
+	assert.Equal(t, "us-east-1a (use1-az4)", plan.ZoneLabel("us-east-1a"))
+	assert.Equal(t, "us-east-1a (use1-az4), us-east-1c", plan.ZoneLabel("us-east-1a, us-east-1c"))
+	assert.Equal(t, "us-east-1a", (&MigrationPlan{}).ZoneLabel("us-east-1a"))
+}
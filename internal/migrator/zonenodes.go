@@ -0,0 +1,227 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+// NodeIssue is a target zone where the pods mounting PVCs of the plan could not
+// run once they are moved: no healthy node is in it, or none matches the
+// nodeSelector of a workload mounting them
+type NodeIssue struct {
+	Zone string
+	// Workload is the Deployment or StatefulSet no node of the zone matches, as
+	// "namespace/Kind/name"; empty when the zone has no healthy node at all
+	Workload string
+	Claims   []string // PVCs of the plan moved to the zone, as "namespace/pvcname"
+	// Unprovisionable is set when, with CheckProvisioning, no Karpenter NodePool
+	// or node group can launch a node in the zone either
+	Unprovisionable bool
+}
+
+// String describes the issue, as the reason of the plan items it fails
+func (i NodeIssue) String() string {
+	switch {
+	case i.Unprovisionable:
+		return fmt.Sprintf("no Ready, schedulable node in zone %s, and no Karpenter NodePool or node group can launch one", i.Zone)
+	case i.Workload == "":
+		return fmt.Sprintf("no Ready, schedulable node in zone %s", i.Zone)
+	}
+	return fmt.Sprintf("no Ready, schedulable node in zone %s matches the nodeSelector of %s", i.Zone, i.Workload)
+}
+
+// checkZoneNodes looks for target zones the pods of the PVCs to migrate could
+// not be scheduled in. Unless AllowEmptyZone is set, the PVCs they concern are
+// failed in the plan and returned with the reason, by name, so the run does not
+// move them either. Clusters whose nodes carry no zone label are not checked.
+func (m *Migrator) checkZoneNodes(ctx context.Context, items []PVCPlanItem) ([]NodeIssue, map[string]string) {
+	moving := make(map[string]map[string]string) // Namespace -> PVC -> target zone
+	var namespaces []string
+	for _, item := range items {
+		if item.Action != PlanActionMigrate || item.TargetZone == "" {
+			continue
+		}
+		if moving[item.Namespace] == nil {
+			moving[item.Namespace] = make(map[string]string)
+			namespaces = append(namespaces, item.Namespace)
+		}
+		moving[item.Namespace][item.PVCName] = item.TargetZone
+	}
+	if len(namespaces) == 0 {
+		return nil, nil
+	}
+
+	zoneNodes, err := m.k8sClient.ZoneNodeLabels(ctx)
+	if err != nil {
+		slog.Warn("failed to list nodes, not checking the target zones", "error", err)
+		return nil, nil
+	}
+	if len(zoneNodes) == 0 {
+		slog.Debug("no node has a zone label, not checking the target zones")
+		return nil, nil
+	}
+	sort.Strings(namespaces)
+
+	var issues []NodeIssue
+	empty := make(map[string]*NodeIssue)
+	for _, ns := range namespaces {
+		claims := make([]string, 0, len(moving[ns]))
+		for claim, zone := range moving[ns] {
+			claims = append(claims, claim)
+			if len(zoneNodes[zone]) > 0 {
+				continue
+			}
+			if empty[zone] == nil {
+				empty[zone] = &NodeIssue{Zone: zone}
+			}
+			empty[zone].Claims = append(empty[zone].Claims, ns+"/"+claim)
+		}
+		sort.Strings(claims)
+
+		workloads, err := m.k8sClient.ClaimWorkloads(ctx, ns, claims)
+		if err != nil {
+			slog.Warn("failed to find the workloads of namespace, not checking their nodeSelectors", "namespace", ns, "error", err)
+			continue
+		}
+		for _, w := range workloads {
+			issues = append(issues, selectorIssues(ns, w, moving[ns], zoneNodes)...)
+		}
+	}
+	var provisioners map[string][]string // Nil when they were not checked
+	if m.config.CheckProvisioning && len(empty) > 0 {
+		zones := make([]string, 0, len(empty))
+		for zone := range empty {
+			zones = append(zones, zone)
+		}
+		if provisioners, err = m.zoneProvisioners(ctx, zones); err != nil {
+			slog.Warn("failed to check what can launch nodes in the target zones", "error", err)
+		}
+	}
+	for zone, issue := range empty {
+		if provisioners != nil {
+			if len(provisioners[zone]) > 0 {
+				slog.Info("target zone has no healthy node, but nodes can be launched in it", "zone", zone, "by", provisioners[zone])
+				continue
+			}
+			issue.Unprovisionable = true
+		}
+		sort.Strings(issue.Claims)
+		issues = append(issues, *issue)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Zone != issues[j].Zone {
+			return issues[i].Zone < issues[j].Zone
+		}
+		return issues[i].Workload < issues[j].Workload
+	})
+
+	if m.config.AllowEmptyZone {
+		return issues, nil
+	}
+	failed := make(map[string]string)
+	for _, issue := range issues {
+		for _, claim := range issue.Claims {
+			if _, ok := failed[claim]; !ok {
+				failed[claim] = issue.String()
+			}
+		}
+	}
+	for i := range items {
+		if reason, ok := failed[items[i].Name]; ok {
+			items[i].Action = PlanActionError
+			items[i].Reason = reason
+		}
+	}
+	return issues, failed
+}
+
+// zoneProvisioners returns the Karpenter NodePools, as "NodePool/name", and the
+// node groups, as "nodegroup/name", that can launch nodes in each of the zones.
+// A NodePool can when its requirements allow the zone and its EC2NodeClass has
+// a subnet there, and a node group when it has a node there already.
+func (m *Migrator) zoneProvisioners(ctx context.Context, zones []string) (map[string][]string, error) {
+	pools, err := m.k8sClient.KarpenterNodePools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := m.k8sClient.NodeGroupZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	for _, pool := range pools {
+		subnetZones := pool.SubnetZones
+		if subnetZones == nil && len(pool.SubnetSelectors) > 0 {
+			selectors := make([]aws.SubnetSelector, 0, len(pool.SubnetSelectors))
+			for _, term := range pool.SubnetSelectors {
+				selectors = append(selectors, aws.SubnetSelector{ID: term.ID, Tags: term.Tags})
+			}
+			if subnetZones, err = m.awsClient.SubnetZones(ctx, selectors); err != nil {
+				return nil, fmt.Errorf("failed to look up the subnets of EC2NodeClass %s: %w", pool.NodeClass, err)
+			}
+		}
+		for _, zone := range zones {
+			if pool.AllowsZone(zone) && slices.Contains(subnetZones, zone) {
+				result[zone] = append(result[zone], "NodePool/"+pool.Name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, zone := range zones {
+			if slices.Contains(groups[name], zone) {
+				result[zone] = append(result[zone], "nodegroup/"+name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// selectorIssues returns, for each zone the workload's PVCs are moved to that
+// has healthy nodes, an issue when none of them matches its nodeSelector
+func selectorIssues(namespace string, w k8s.ClaimWorkload, zones map[string]string, zoneNodes map[string][]map[string]string) []NodeIssue {
+	if len(w.NodeSelector) == 0 {
+		return nil
+	}
+	byZone := make(map[string][]string)
+	for _, claim := range w.Claims {
+		if zone, ok := zones[claim]; ok && len(zoneNodes[zone]) > 0 {
+			byZone[zone] = append(byZone[zone], namespace+"/"+claim)
+		}
+	}
+
+	var issues []NodeIssue
+	for zone, claims := range byZone {
+		matched := false
+		for _, labels := range zoneNodes[zone] {
+			matched = matched || matchesSelector(labels, w.NodeSelector)
+		}
+		if !matched {
+			sort.Strings(claims)
+			issues = append(issues, NodeIssue{Zone: zone, Workload: namespace + "/" + w.Kind + "/" + w.Name, Claims: claims})
+		}
+	}
+	return issues
+}
+
+// matchesSelector reports whether the labels carry every key and value of the selector
+func matchesSelector(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/aws"
+	"github.com/cesarempathy/pv-zone-migrator/internal/k8s"
+)
+
+func TestGeneratePlan_ZoneNodes(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, zone string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone, "pool": "general"}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+	deployment := func(name, claim string, selector map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "db"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector: selector,
+				Volumes: []corev1.Volume{{
+					Name:         claim,
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+				}},
+			}}},
+		}
+	}
+
+	cases := []struct {
+		name           string
+		targetZone     string
+		allowEmptyZone bool
+		issues         []NodeIssue
+		reasons        map[string]string // By PVC; those left out stay PlanActionMigrate
+	}{
+		{
+			name:       "selector_unmatched",
+			targetZone: "eu-west-1a",
+			issues:     []NodeIssue{{Zone: "eu-west-1a", Workload: "db/Deployment/gpu", Claims: []string{"db/data-1"}}},
+			reasons:    map[string]string{"db/data-1": "no Ready, schedulable node in zone eu-west-1a matches the nodeSelector of db/Deployment/gpu"},
+		},
+		{
+			name:       "zone_cordoned",
+			targetZone: "eu-west-1b",
+			issues:     []NodeIssue{{Zone: "eu-west-1b", Claims: []string{"db/data-0", "db/data-1"}}},
+			reasons: map[string]string{
+				"db/data-0": "no Ready, schedulable node in zone eu-west-1b",
+				"db/data-1": "no Ready, schedulable node in zone eu-west-1b",
+			},
+		},
+		{
+			name:           "allowed",
+			targetZone:     "eu-west-1b",
+			allowEmptyZone: true,
+			issues:         []NodeIssue{{Zone: "eu-west-1b", Claims: []string{"db/data-0", "db/data-1"}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			objects := []runtime.Object{
+				node("node-a", "eu-west-1a", false),
+				node("node-b", "eu-west-1b", true),
+				deployment("web", "data-0", map[string]string{"pool": "general"}),
+				deployment("gpu", "data-1", map[string]string{"pool": "gpu"}),
+			}
+			objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+			objects = append(objects, boundClaim("db", "data-1", "vol-1")...)
+			m := newFakeMigrator(&Config{
+				PVCList:        []string{"db/data-0", "db/data-1"},
+				TargetZone:     tc.targetZone,
+				AllowEmptyZone: tc.allowEmptyZone,
+			}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1c", "vol-1": "eu-west-1c"}}, objects...)
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.issues, plan.NodeIssues)
+			for _, item := range plan.Items {
+				reason, failed := tc.reasons[item.Name]
+				if !failed {
+					assert.Equal(t, PlanActionMigrate, item.Action, item.Name)
+					continue
+				}
+				assert.Equal(t, PlanActionError, item.Action, item.Name)
+				assert.Equal(t, reason, item.Reason, item.Name)
+				assert.Equal(t, reason, m.blocked[item.Name], "the run does not move it either")
+			}
+		})
+	}
+}
+
+func TestGeneratePlan_ZoneNodes_Unlabelled(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}}
+	objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+	m := newFakeMigrator(&Config{
+		PVCList:    []string{"db/data-0"},
+		TargetZone: "eu-west-1a",
+	}, &fakeEC2{zones: map[string]string{"vol-0": "eu-west-1c"}}, objects...)
+
+	plan, err := m.GeneratePlan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, plan.NodeIssues, "nodes without a zone label are not checked")
+	assert.Equal(t, PlanActionMigrate, plan.Items[0].Action)
+}
+
+// fakeSubnets returns the same subnets for every DescribeSubnets call
+type fakeSubnets struct {
+	zones []string
+}
+
+func (f *fakeSubnets) DescribeSubnets(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	out := &ec2.DescribeSubnetsOutput{}
+	for _, zone := range f.zones {
+		out.Subnets = append(out.Subnets, ec2types.Subnet{AvailabilityZone: awssdk.String(zone)})
+	}
+	return out, nil
+}
+
+func TestGeneratePlan_ZoneNodes_Provisioning(t *testing.T) {
+	t.Parallel()
+
+	nodePoolGVR := schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}
+	nodeClassGVR := schema.GroupVersionResource{Group: "karpenter.k8s.aws", Version: "v1", Resource: "ec2nodeclasses"}
+	nodePool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata":   map[string]interface{}{"name": "default"},
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"nodeClassRef": map[string]interface{}{"name": "default"},
+			"requirements": []interface{}{map[string]interface{}{"key": corev1.LabelTopologyZone, "operator": "NotIn", "values": []interface{}{"eu-west-1d"}}},
+		}}},
+	}}
+	nodeClass := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.k8s.aws/v1",
+		"kind":       "EC2NodeClass",
+		"metadata":   map[string]interface{}{"name": "default"},
+		"spec":       map[string]interface{}{"subnetSelectorTerms": []interface{}{map[string]interface{}{"tags": map[string]interface{}{"karpenter.sh/discovery": "prod"}}}},
+	}}
+	node := func(name, zone string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone, "eks.amazonaws.com/nodegroup": "general"}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+
+	cases := []struct {
+		name       string
+		targetZone string
+		issues     []NodeIssue
+	}{
+		{name: "node_group", targetZone: "eu-west-1b"},
+		{name: "node_pool", targetZone: "eu-west-1c"},
+		{
+			name:       "excluded_by_node_pool",
+			targetZone: "eu-west-1d",
+			issues:     []NodeIssue{{Zone: "eu-west-1d", Claims: []string{"db/data-0"}, Unprovisionable: true}},
+		},
+		{
+			name:       "no_subnet",
+			targetZone: "eu-west-1e",
+			issues:     []NodeIssue{{Zone: "eu-west-1e", Claims: []string{"db/data-0"}, Unprovisionable: true}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			objects := []runtime.Object{node("node-a", "eu-west-1a", false), node("node-b", "eu-west-1b", true)}
+			objects = append(objects, boundClaim("db", "data-0", "vol-0")...)
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				nodePoolGVR:  "NodePoolList",
+				nodeClassGVR: "EC2NodeClassList",
+			}, nodePool, nodeClass)
+			m := New(&Config{
+				PVCList:           []string{"db/data-0"},
+				TargetZone:        tc.targetZone,
+				CheckProvisioning: true,
+			}, k8s.NewClientWithInterface(bindingClientset(objects...), dynamicClient),
+				aws.NewEC2ClientWithSubnets(&fakeEC2{zones: map[string]string{"vol-0": "eu-west-1a"}}, &fakeSubnets{zones: []string{"eu-west-1a", "eu-west-1c", "eu-west-1d"}}))
+
+			plan, err := m.GeneratePlan(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.issues, plan.NodeIssues)
+			if len(tc.issues) == 0 {
+				assert.Equal(t, PlanActionMigrate, plan.Items[0].Action)
+				return
+			}
+			assert.Equal(t, PlanActionError, plan.Items[0].Action)
+			assert.Equal(t, "no Ready, schedulable node in zone "+tc.targetZone+", and no Karpenter NodePool or node group can launch one", plan.Items[0].Reason)
+		})
+	}
+}
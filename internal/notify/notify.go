@@ -0,0 +1,291 @@
+// Package notify posts migration notifications to Slack or generic webhooks.
+// Messages are sent when a run starts, when a PVC fails and when the run ends,
+// so on-call engineers can follow a migration without watching the TUI.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// Notification event names, as used in the config file
+const (
+	EventStart   = "start"
+	EventFailure = "failure"
+	EventSummary = "summary"
+)
+
+// Payload formats
+const (
+	FormatSlack   = "slack"
+	FormatGeneric = "generic"
+)
+
+// requestTimeout bounds each webhook call so a slow receiver cannot hold up the summary
+const requestTimeout = 10 * time.Second
+
+// queueSize bounds the messages waiting to be delivered
+const queueSize = 256
+
+// Webhook is a notification destination
+type Webhook struct {
+	URL    string
+	Format string   // FormatSlack or FormatGeneric; empty picks slack for hooks.slack.com URLs
+	Events []string // Events to send; empty sends all of them
+}
+
+// RunInfo describes the migration run in notification messages
+type RunInfo struct {
+	KubeContext string
+	Namespaces  []string
+	TargetZone  string
+	Total       int
+	DryRun      bool
+}
+
+// Message is the JSON body posted to generic webhooks
+type Message struct {
+	Event      string    `json:"event"`
+	Text       string    `json:"text"`
+	Time       time.Time `json:"time"`
+	Context    string    `json:"context,omitempty"`
+	TargetZone string    `json:"targetZone"`
+	PVC        string    `json:"pvc,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Total      int       `json:"total,omitempty"`
+	Migrated   int       `json:"migrated,omitempty"`
+	Skipped    int       `json:"skipped,omitempty"`
+	Failed     int       `json:"failed,omitempty"`
+	FailedPVCs []string  `json:"failedPvcs,omitempty"`
+	Duration   string    `json:"duration,omitempty"`
+}
+
+// Notifier sends notifications for one migration run. Messages are delivered in
+// order by a single background worker, so webhooks never block the migration.
+type Notifier struct {
+	webhooks []Webhook
+	run      RunInfo
+	client   *http.Client
+	queue    chan Message
+	done     chan struct{}
+
+	mu         sync.Mutex
+	closed     bool
+	started    time.Time
+	migrated   int
+	skipped    int
+	failedPVCs []string
+}
+
+// New creates a notifier for the given webhooks and starts its delivery worker.
+// A nil client uses http.DefaultClient. Summary must be called to stop the worker.
+func New(webhooks []Webhook, run RunInfo, client *http.Client) *Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	n := &Notifier{
+		webhooks: webhooks,
+		run:      run,
+		client:   client,
+		queue:    make(chan Message, queueSize),
+		done:     make(chan struct{}),
+	}
+	go n.deliver()
+	return n
+}
+
+// Observe is a migrator.EventListener. The first event of the run queues the start
+// notification, and every PVC that moves to the failed step queues a failure one.
+func (n *Notifier) Observe(e migrator.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return
+	}
+
+	if n.started.IsZero() {
+		n.started = e.Time
+		n.enqueue(n.startMessage(e.Time))
+	}
+	switch e.Step {
+	case migrator.StepDone.String():
+		n.migrated++
+	case migrator.StepSkipped.String():
+		n.skipped++
+	case migrator.StepFailed.String():
+		n.failedPVCs = append(n.failedPVCs, e.PVC)
+		n.enqueue(n.failureMessage(e))
+	}
+}
+
+// Summary queues the final summary and waits until every queued notification has
+// been delivered or ctx is done. Nothing is sent if the run never started, for
+// example when the user declined the confirmation prompt.
+func (n *Notifier) Summary(ctx context.Context) {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	if !n.started.IsZero() {
+		n.enqueue(n.summaryMessage())
+	}
+	n.closed = true
+	close(n.queue)
+	n.mu.Unlock()
+
+	select {
+	case <-n.done:
+	case <-ctx.Done():
+		slog.Warn("gave up waiting for notifications to be delivered", "error", ctx.Err())
+	}
+}
+
+// enqueue hands msg to the delivery worker; the caller must hold n.mu
+func (n *Notifier) enqueue(msg Message) {
+	select {
+	case n.queue <- msg:
+	default:
+		slog.Warn("dropped notification, delivery queue is full", "event", msg.Event)
+	}
+}
+
+// deliver sends queued messages in order until the queue is closed
+func (n *Notifier) deliver() {
+	defer close(n.done)
+	for msg := range n.queue {
+		n.send(context.Background(), msg)
+	}
+}
+
+// summaryMessage builds the summary notification; the caller must hold n.mu
+func (n *Notifier) summaryMessage() Message {
+	msg := Message{
+		Event:      EventSummary,
+		Time:       time.Now(),
+		Context:    n.run.KubeContext,
+		TargetZone: n.run.TargetZone,
+		Total:      n.run.Total,
+		Migrated:   n.migrated,
+		Skipped:    n.skipped,
+		Failed:     len(n.failedPVCs),
+		FailedPVCs: append([]string(nil), n.failedPVCs...),
+		Duration:   time.Since(n.started).Round(time.Second).String(),
+	}
+
+	icon := "✅"
+	if msg.Failed > 0 {
+		icon = "❌"
+	}
+	msg.Text = fmt.Sprintf("%s PVC migration to %s finished%s in %s: %d migrated, %d skipped, %d failed",
+		icon, msg.TargetZone, n.where(), msg.Duration, msg.Migrated, msg.Skipped, msg.Failed)
+	if msg.Failed > 0 {
+		msg.Text += "\nFailed: " + strings.Join(msg.FailedPVCs, ", ")
+	}
+	return msg
+}
+
+func (n *Notifier) startMessage(at time.Time) Message {
+	text := fmt.Sprintf("🚀 PVC migration started%s: %d PVC(s) in %s → %s",
+		n.where(), n.run.Total, strings.Join(n.run.Namespaces, ", "), n.run.TargetZone)
+	if n.run.DryRun {
+		text += " (dry run)"
+	}
+	return Message{
+		Event:      EventStart,
+		Text:       text,
+		Time:       at,
+		Context:    n.run.KubeContext,
+		TargetZone: n.run.TargetZone,
+		Total:      n.run.Total,
+	}
+}
+
+func (n *Notifier) failureMessage(e migrator.Event) Message {
+	return Message{
+		Event:      EventFailure,
+		Text:       fmt.Sprintf("❌ PVC %s failed to migrate%s: %s", e.PVC, n.where(), e.Error),
+		Time:       e.Time,
+		Context:    n.run.KubeContext,
+		TargetZone: n.run.TargetZone,
+		PVC:        e.PVC,
+		Error:      e.Error,
+	}
+}
+
+// where names the cluster context in messages, if one was given
+func (n *Notifier) where() string {
+	if n.run.KubeContext == "" {
+		return ""
+	}
+	return fmt.Sprintf(" on %s", n.run.KubeContext)
+}
+
+// send posts msg to every webhook subscribed to its event. Delivery failures are
+// logged and never interrupt the migration.
+func (n *Notifier) send(ctx context.Context, msg Message) {
+	for _, w := range n.webhooks {
+		if !w.wants(msg.Event) {
+			continue
+		}
+		if err := n.post(ctx, w, msg); err != nil {
+			slog.Warn("failed to send notification", "event", msg.Event, "error", err)
+		}
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, w Webhook, msg Message) error {
+	var payload any = msg
+	if w.format() == FormatSlack {
+		payload = map[string]string{"text": msg.Text}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// format resolves the payload format, detecting Slack incoming webhooks by host
+func (w Webhook) format() string {
+	if w.Format != "" {
+		return w.Format
+	}
+	if u, err := url.Parse(w.URL); err == nil && u.Host == "hooks.slack.com" {
+		return FormatSlack
+	}
+	return FormatGeneric
+}
+
+// wants reports whether the webhook is subscribed to event
+func (w Webhook) wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+// recorder is a webhook receiver that keeps every request body
+type recorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+	status int
+}
+
+func newRecorder(t *testing.T, status int) (*recorder, *httptest.Server) {
+	t.Helper()
+	r := &recorder{status: status}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func (r *recorder) received() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.bodies...)
+}
+
+func testRun() RunInfo {
+	return RunInfo{
+		KubeContext: "prod",
+		Namespaces:  []string{"db"},
+		TargetZone:  "eu-west-1a",
+		Total:       3,
+	}
+}
+
+func event(pvc string, step migrator.Step, errMsg string) migrator.Event {
+	return migrator.Event{Time: time.Now(), PVC: pvc, Step: step.String(), Error: errMsg}
+}
+
+func TestNotifier_GenericLifecycle(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusOK)
+	n := New([]Webhook{{URL: srv.URL, Format: FormatGeneric}}, testRun(), srv.Client())
+
+	n.Observe(event("db/a", migrator.StepGetInfo, ""))
+	n.Observe(event("db/b", migrator.StepGetInfo, ""))
+	n.Observe(event("db/a", migrator.StepDone, ""))
+	n.Observe(event("db/b", migrator.StepFailed, "create snapshot: throttled"))
+	n.Observe(event("db/c", migrator.StepSkipped, ""))
+	n.Summary(context.Background())
+
+	bodies := rec.received()
+	require.Len(t, bodies, 3)
+
+	assert.Equal(t, EventStart, bodies[0]["event"])
+	assert.Equal(t, "prod", bodies[0]["context"])
+	assert.Contains(t, bodies[0]["text"], "3 PVC(s) in db → eu-west-1a")
+
+	assert.Equal(t, EventFailure, bodies[1]["event"])
+	assert.Equal(t, "db/b", bodies[1]["pvc"])
+	assert.Equal(t, "create snapshot: throttled", bodies[1]["error"])
+
+	assert.Equal(t, EventSummary, bodies[2]["event"])
+	assert.InDelta(t, 1, bodies[2]["migrated"], 0)
+	assert.InDelta(t, 1, bodies[2]["skipped"], 0)
+	assert.InDelta(t, 1, bodies[2]["failed"], 0)
+	assert.Equal(t, []any{"db/b"}, bodies[2]["failedPvcs"])
+	assert.Contains(t, bodies[2]["text"], "Failed: db/b")
+}
+
+func TestNotifier_SlackPayload(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusOK)
+	n := New([]Webhook{{URL: srv.URL, Format: FormatSlack, Events: []string{EventStart}}}, testRun(), srv.Client())
+
+	n.Observe(event("db/a", migrator.StepGetInfo, ""))
+	n.Summary(context.Background())
+
+	bodies := rec.received()
+	require.Len(t, bodies, 1)
+	assert.Len(t, bodies[0], 1, "slack payload only carries text")
+	assert.Contains(t, bodies[0]["text"], "PVC migration started on prod")
+}
+
+func TestNotifier_EventFilter(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusOK)
+	n := New([]Webhook{{URL: srv.URL, Events: []string{EventSummary}}}, testRun(), srv.Client())
+
+	n.Observe(event("db/a", migrator.StepGetInfo, ""))
+	n.Observe(event("db/a", migrator.StepFailed, "boom"))
+	n.Summary(context.Background())
+
+	bodies := rec.received()
+	require.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0]["text"], "0 migrated, 0 skipped, 1 failed")
+}
+
+func TestNotifier_SummaryWithoutStart(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusOK)
+	n := New([]Webhook{{URL: srv.URL}}, testRun(), srv.Client())
+
+	n.Summary(context.Background())
+
+	assert.Empty(t, rec.received())
+}
+
+func TestNotifier_DeliveryErrorIgnored(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusInternalServerError)
+	n := New([]Webhook{{URL: srv.URL}}, testRun(), srv.Client())
+
+	assert.NotPanics(t, func() {
+		n.Observe(event("db/a", migrator.StepGetInfo, ""))
+		n.Summary(context.Background())
+	})
+	assert.Len(t, rec.received(), 2)
+}
+
+func TestNotifier_ObserveDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		mu.Lock()
+		events = append(events, body["event"].(string))
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	n := New([]Webhook{{URL: srv.URL}}, testRun(), srv.Client())
+
+	observed := make(chan struct{})
+	go func() {
+		n.Observe(event("db/a", migrator.StepGetInfo, ""))
+		n.Observe(event("db/a", migrator.StepFailed, "boom"))
+		close(observed)
+	}()
+	select {
+	case <-observed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Observe blocked on a slow webhook")
+	}
+
+	close(release)
+	n.Summary(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{EventStart, EventFailure, EventSummary}, events, "delivered in order")
+}
+
+func TestNotifier_ObserveAfterSummary(t *testing.T) {
+	t.Parallel()
+
+	rec, srv := newRecorder(t, http.StatusOK)
+	n := New([]Webhook{{URL: srv.URL}}, testRun(), srv.Client())
+
+	n.Observe(event("db/a", migrator.StepGetInfo, ""))
+	n.Summary(context.Background())
+	assert.NotPanics(t, func() {
+		n.Observe(event("db/a", migrator.StepFailed, "late"))
+		n.Summary(context.Background())
+	})
+	assert.Len(t, rec.received(), 2)
+}
+
+func TestWebhook_Format(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		webhook Webhook
+		want    string
+	}{
+		{name: "slack_host", webhook: Webhook{URL: "https://hooks.slack.com/services/T/B/X"}, want: FormatSlack},
+		{name: "other_host", webhook: Webhook{URL: "https://example.com/hook"}, want: FormatGeneric},
+		{name: "explicit", webhook: Webhook{URL: "https://hooks.slack.com/x", Format: FormatGeneric}, want: FormatGeneric},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.webhook.format())
+		})
+	}
+}
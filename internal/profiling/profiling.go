@@ -0,0 +1,81 @@
+// Package profiling helps diagnose the tool itself on very large migrations.
+// It serves the standard pprof endpoints during a run and writes heap and
+// goroutine profiles to disk, to be opened with go tool pprof.
+package profiling
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"time"
+)
+
+// Handler returns the pprof endpoints under /debug/pprof/
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Serve starts an HTTP server exposing the pprof endpoints on addr. The
+// listener is opened synchronously so a bad address is reported immediately.
+func Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	// No write timeout: /debug/pprof/profile streams for as long as requested
+	srv := &http.Server{Handler: Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = ln.Close()
+		}
+	}()
+	return srv, nil
+}
+
+// WriteProfiles writes heap and goroutine profiles to dir, named after the
+// time they were taken, and returns their paths
+func WriteProfiles(dir string, at time.Time) ([]string, error) {
+	stamp := at.UTC().Format("20060102T150405Z")
+	var paths []string
+	for _, name := range []string{"heap", "goroutine"} {
+		path := filepath.Join(dir, fmt.Sprintf("pvc-migrator-%s-%s.pprof", name, stamp))
+		if err := writeProfile(name, path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeProfile(name, path string) error {
+	if name == "heap" {
+		// Up-to-date allocation statistics instead of those of the last GC
+		runtime.GC()
+	}
+	f, err := os.Create(path) //nolint:gosec // Directory comes from a CLI flag
+	if err != nil {
+		return fmt.Errorf("failed to create %s profile: %w", name, err)
+	}
+	if err := rpprof.Lookup(name).WriteTo(f, 0); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package profiling
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/pprof/goroutine?debug=1")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "goroutine profile:")
+}
+
+func TestServe_InvalidAddress(t *testing.T) {
+	t.Parallel()
+
+	_, err := Serve("not-an-address")
+	require.Error(t, err)
+}
+
+func TestWriteProfiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	paths, err := WriteProfiles(dir, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "pvc-migrator-heap-20260102T030405Z.pprof"),
+		filepath.Join(dir, "pvc-migrator-goroutine-20260102T030405Z.pprof"),
+	}, paths)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Positive(t, info.Size())
+	}
+
+	_, err = WriteProfiles(filepath.Join(dir, "missing"), time.Now())
+	require.Error(t, err)
+}
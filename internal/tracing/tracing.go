@@ -0,0 +1,84 @@
+// Package tracing configures OpenTelemetry tracing for migration runs.
+// Spans are exported over OTLP/HTTP so a run can be inspected as a timeline in
+// Jaeger, Tempo or any other OTLP-compatible backend.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name resource attribute attached to every span
+const ServiceName = "pvc-migrator"
+
+// endpointEnv is the standard OTLP variable honoured when no endpoint is given
+const endpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// ShutdownFunc flushes pending spans and stops the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Enabled reports whether tracing would be turned on for the given endpoint,
+// either explicitly or through OTEL_EXPORTER_OTLP_ENDPOINT
+func Enabled(endpoint string) bool {
+	return endpoint != "" || os.Getenv(endpointEnv) != ""
+}
+
+// Setup installs a global tracer provider that exports spans to an OTLP/HTTP
+// collector. endpoint is a URL such as http://localhost:4318; when empty the
+// standard OTEL_EXPORTER_OTLP_* variables are used. If tracing is not enabled,
+// the global no-op provider is left in place and the returned shutdown does nothing.
+func Setup(ctx context.Context, endpoint string) (ShutdownFunc, error) {
+	if !Enabled(endpoint) {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// RecordError marks the span as failed. A nil error is ignored so callers can
+// pass the result of an operation unconditionally.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End records err on the span, if any, and ends it. It is meant to be deferred
+// with a named error result.
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnabled(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		env      string
+		want     bool
+	}{
+		{name: "disabled", want: false},
+		{name: "flag", endpoint: "http://localhost:4318", want: true},
+		{name: "env", env: "http://collector:4318", want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(endpointEnv, tc.env)
+			assert.Equal(t, tc.want, Enabled(tc.endpoint))
+		})
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	t.Setenv(endpointEnv, "")
+
+	shutdown, err := Setup(context.Background(), "")
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestEnd(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tr := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tr.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tr.Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Empty(t, ended[0].Events())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, "boom", ended[1].Status().Description)
+	require.Len(t, ended[1].Events(), 1)
+	assert.Equal(t, "exception", ended[1].Events()[0].Name)
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
+)
+
+// Log pane layout. The pane takes a third of the terminal, within these bounds.
+const (
+	defaultLogLines = 8
+	minLogLines     = 4
+	maxLogLines     = 20
+)
+
+// LogBuffer keeps the last lines written to it for the log pane. It is safe for
+// concurrent use, so a slog handler can write to it while the UI renders.
+type LogBuffer struct {
+	mu       sync.Mutex
+	lines    []string
+	partial  string // Text after the last newline
+	maxLines int
+}
+
+// NewLogBuffer creates a buffer that keeps up to maxLines lines
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return &LogBuffer{maxLines: maxLines}
+}
+
+// Write appends p, splitting it into lines. It never fails.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parts := strings.Split(b.partial+string(p), "\n")
+	b.partial = parts[len(parts)-1]
+	b.lines = append(b.lines, parts[:len(parts)-1]...)
+	if over := len(b.lines) - b.maxLines; over > 0 {
+		b.lines = append([]string(nil), b.lines[over:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the complete lines kept, oldest first
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.lines...)
+}
+
+// Len returns the number of complete lines kept
+func (b *LogBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.lines)
+}
+
+// logLines returns how many log lines the pane shows for the terminal height
+func (m Model) logLines() int {
+	if m.height == 0 {
+		return defaultLogLines
+	}
+	return min(max(m.height/3, minLogLines), maxLogLines)
+}
+
+// scrollLogs moves the log pane by delta lines, positive towards older lines,
+// keeping it within the buffer
+func (m *Model) scrollLogs(delta int) {
+	limit := max(m.logs.Len()-m.logLines(), 0)
+	m.logOffset = min(max(m.logOffset+delta, 0), limit)
+}
+
+// renderLogPane renders the newest log lines, or older ones when scrolled back
+func (m Model) renderLogPane() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render("  " + i18n.T("tui.logs")))
+	if m.logOffset > 0 {
+		b.WriteString(dimStyle.Render(" " + i18n.T("tui.logs_scrolled", m.logOffset)))
+	}
+	b.WriteString("\n")
+
+	lines := m.logs.Lines()
+	end := max(len(lines)-m.logOffset, 0)
+	start := max(end-m.logLines(), 0)
+	width := compactWidth
+	if m.width > 0 {
+		width = max(m.width-4, 10)
+	}
+	for _, line := range lines[start:end] {
+		b.WriteString("  " + dimStyle.Render(truncate(line, width)) + "\n")
+	}
+	for i := end - start; i < m.logLines(); i++ {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
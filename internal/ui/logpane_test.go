@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
+)
+
+func TestLogBuffer_Write(t *testing.T) {
+	t.Parallel()
+
+	b := NewLogBuffer(3)
+	_, _ = b.Write([]byte("one\ntw"))
+	assert.Equal(t, []string{"one"}, b.Lines(), "a partial line waits for its newline")
+
+	_, _ = b.Write([]byte("o\nthree\nfour\n"))
+	assert.Equal(t, []string{"two", "three", "four"}, b.Lines())
+
+	n, err := b.Write([]byte("five\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, []string{"three", "four", "five"}, b.Lines(), "the oldest lines are dropped")
+	assert.Equal(t, 3, b.Len())
+}
+
+// logModel returns a started model with a log pane holding lines numbered from 1
+func logModel(lines int) Model {
+	config := &migrator.Config{PVCList: []string{"ns/pvc-1"}}
+	logs := NewLogBuffer(100)
+	for i := 1; i <= lines; i++ {
+		_, _ = fmt.Fprintf(logs, "msg=line-%02d\n", i)
+	}
+	model := NewModel(migrator.New(config, nil, nil), config).WithLogs(logs)
+	model.generatingPlan = false
+	model.confirmed = true
+	model.started = true
+	return model
+}
+
+func TestModel_View_LogPane(t *testing.T) {
+	t.Parallel()
+
+	model := logModel(12)
+	require.Equal(t, defaultLogLines, model.logLines())
+
+	view := model.View()
+	assert.Contains(t, view, "Log")
+	assert.Contains(t, view, "line-12", "follows the newest line")
+	assert.Contains(t, view, "line-05")
+	assert.NotContains(t, view, "line-04")
+	assert.Contains(t, view, "l to show or hide the log")
+}
+
+func TestModel_Update_LogKeys(t *testing.T) {
+	t.Parallel()
+
+	model := logModel(12)
+	key := func(m tea.Model, k tea.KeyMsg) Model {
+		next, _ := m.Update(k)
+		return next.(Model)
+	}
+
+	model = key(model, tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 1, model.logOffset)
+	view := model.View()
+	assert.Contains(t, view, "line-04")
+	assert.NotContains(t, view, "line-12")
+	assert.Contains(t, view, "1 line(s) back")
+
+	model = key(model, tea.KeyMsg{Type: tea.KeyPgUp})
+	assert.Equal(t, 4, model.logOffset, "cannot scroll past the oldest line")
+
+	model = key(model, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 3, model.logOffset)
+
+	model = key(model, tea.KeyMsg{Type: tea.KeyEnd})
+	assert.Zero(t, model.logOffset)
+
+	model = key(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	assert.False(t, model.showLogs)
+	assert.NotContains(t, model.View(), "line-12")
+}
+
+func TestModel_LogLines(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		height int
+		want   int
+	}{
+		{height: 0, want: defaultLogLines},
+		{height: 9, want: minLogLines},
+		{height: 30, want: 10},
+		{height: 120, want: maxLogLines},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.height), func(t *testing.T) {
+			t.Parallel()
+			model := logModel(0)
+			model.height = tc.height
+			assert.Equal(t, tc.want, model.logLines())
+		})
+	}
+}
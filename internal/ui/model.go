@@ -14,6 +14,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/cesarempathy/pv-zone-migrator/internal/i18n"
 	"github.com/cesarempathy/pv-zone-migrator/internal/migrator"
 )
 
@@ -56,6 +57,18 @@ var (
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("99")).
 			Padding(1, 2)
+
+	compactNameStyle = pvcNameStyle.Width(24)
+
+	compactStepStyle = stepStyle.Width(10)
+)
+
+// Layout constants. Below compactWidth columns the compact layout is used so
+// status lines do not wrap.
+const (
+	compactWidth            = 100
+	progressBarWidth        = 30
+	compactProgressBarWidth = 12
 )
 
 type tickMsg time.Time
@@ -80,6 +93,18 @@ type Model struct {
 	generatingPlan bool
 	plan           *migrator.MigrationPlan
 	planError      error
+	width          int // Terminal width, 0 until the first WindowSizeMsg
+	height         int // Terminal height, 0 until the first WindowSizeMsg
+
+	// Log pane below the progress list, when the model was given a log buffer
+	logs      *LogBuffer
+	showLogs  bool
+	logOffset int // Lines scrolled back from the newest
+
+	// Statuses are refreshed on every tick with only the PVCs that changed
+	statuses  map[string]*migrator.PVCStatus
+	pvcNames  []string // Keys of statuses, by priority then name
+	statusSeq uint64
 }
 
 // NewModel creates a new UI model
@@ -92,7 +117,7 @@ func NewModel(m *migrator.Migrator, config *migrator.Config) Model {
 	for _, pvc := range config.PVCList {
 		p := progress.New(
 			progress.WithDefaultGradient(),
-			progress.WithWidth(30),
+			progress.WithWidth(progressBarWidth),
 			progress.WithoutPercentage(),
 		)
 		progressBars[pvc] = p
@@ -100,7 +125,7 @@ func NewModel(m *migrator.Migrator, config *migrator.Config) Model {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return Model{
+	model := Model{
 		migrator:       m,
 		config:         config,
 		spinner:        s,
@@ -108,11 +133,72 @@ func NewModel(m *migrator.Migrator, config *migrator.Config) Model {
 		ctx:            ctx,
 		cancel:         cancel,
 		generatingPlan: true, // Start by generating the plan
+		statuses:       make(map[string]*migrator.PVCStatus),
+	}
+	model.statusSeq = model.refreshStatuses(0)
+	return model
+}
+
+// refreshStatuses merges the statuses that changed after seq into the cache and
+// returns the sequence number to pass next time
+func (m *Model) refreshStatuses(seq uint64) uint64 {
+	changed, latest := m.migrator.StatusesSince(seq)
+	added := false
+	for _, s := range changed {
+		if _, ok := m.statuses[s.Name]; !ok {
+			added = true
+		}
+		m.statuses[s.Name] = s
+	}
+	if added {
+		names := make([]string, 0, len(m.statuses))
+		for name := range m.statuses {
+			names = append(names, name)
+		}
+		// In the order the run starts them: by priority, then by name
+		sort.Slice(names, func(i, j int) bool {
+			ri, rj := migrator.PriorityRank(m.config.PriorityFor(names[i])), migrator.PriorityRank(m.config.PriorityFor(names[j]))
+			if ri != rj {
+				return ri < rj
+			}
+			return names[i] < names[j]
+		})
+		m.pvcNames = names
 	}
+	return latest
+}
+
+// WithPlan returns a copy of the model that shows plan instead of generating its
+// own, so the confirmation screen matches what was decided before workloads were scaled
+func (m Model) WithPlan(plan *migrator.MigrationPlan) Model {
+	m.plan = plan
+	m.generatingPlan = false
+	return m
+}
+
+// WithAutoStart returns a copy of the model that starts the migration as soon as
+// the plan is ready, without asking for confirmation
+func (m Model) WithAutoStart() Model {
+	m.confirmed = true
+	return m
+}
+
+// WithLogs returns a copy of the model that shows the lines of logs in a pane
+// below the progress list while the migration runs
+func (m Model) WithLogs(logs *LogBuffer) Model {
+	m.logs = logs
+	m.showLogs = true
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
+	if !m.generatingPlan {
+		if m.confirmed {
+			return tea.Batch(m.spinner.Tick, m.tickCmd(), m.startMigration())
+		}
+		return tea.Batch(m.spinner.Tick, m.tickCmd())
+	}
 	return tea.Batch(m.spinner.Tick, m.tickCmd(), m.generatePlanCmd())
 }
 
@@ -143,6 +229,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmed = true
 				return m, m.startMigration()
 			}
+		case "p":
+			if m.started && !m.migrator.IsDone() {
+				if m.migrator.Paused() {
+					m.migrator.Resume()
+				} else {
+					m.migrator.Pause()
+				}
+			}
+		case "r":
+			if m.started && m.migrator.IsDone() && len(m.migrator.RetryableFailures()) > 0 {
+				return m, m.retryFailed()
+			}
+		case "l":
+			if m.logs != nil {
+				m.showLogs = !m.showLogs
+			}
+		case "up", "k":
+			if m.showLogs && m.logs != nil {
+				m.scrollLogs(1)
+			}
+		case "down", "j":
+			if m.showLogs && m.logs != nil {
+				m.scrollLogs(-1)
+			}
+		case "pgup":
+			if m.showLogs && m.logs != nil {
+				m.scrollLogs(m.logLines())
+			}
+		case "pgdown":
+			if m.showLogs && m.logs != nil {
+				m.scrollLogs(-m.logLines())
+			}
+		case "end":
+			m.logOffset = 0
 		case "n":
 			if !m.confirmed {
 				m.quitting = true
@@ -151,12 +271,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		barWidth := progressBarWidth
+		if m.compact() {
+			barWidth = compactProgressBarWidth
+		}
+		for name, p := range m.progressBars {
+			p.Width = barWidth
+			m.progressBars[name] = p
+		}
 		return m, nil
 
 	case planReadyMsg:
 		m.generatingPlan = false
 		m.plan = msg.plan
 		m.planError = msg.err
+		if m.confirmed && m.planError == nil {
+			return m, tea.Batch(m.tickCmd(), m.startMigration())
+		}
 		return m, m.tickCmd()
 
 	case startMsg:
@@ -167,7 +300,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case tickMsg:
-		if m.started && m.migrator.IsDone() {
+		m.statusSeq = m.refreshStatuses(m.statusSeq)
+		// Failures that can be retried keep the UI open until r or q is pressed
+		if m.started && m.migrator.IsDone() && len(m.migrator.RetryableFailures()) == 0 {
 			return m, tea.Tick(time.Second, func(_ time.Time) tea.Msg {
 				return doneMsg{}
 			})
@@ -186,6 +321,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// compact reports whether the terminal is too narrow for the full layout
+func (m Model) compact() bool {
+	return m.width > 0 && m.width < compactWidth
+}
+
 func (m Model) startMigration() tea.Cmd {
 	return func() tea.Msg {
 		go m.migrator.Run(m.ctx)
@@ -193,16 +333,24 @@ func (m Model) startMigration() tea.Cmd {
 	}
 }
 
+// retryFailed migrates the retryable failed PVCs again in the background
+func (m Model) retryFailed() tea.Cmd {
+	return func() tea.Msg {
+		go m.migrator.RetryFailed(m.ctx)
+		return startMsg{}
+	}
+}
+
 // View renders the UI
 func (m Model) View() string {
 	if m.quitting {
-		return "\n  👋 Migration cancelled.\n\n"
+		return "\n  " + i18n.T("tui.cancelled") + "\n\n"
 	}
 
 	var b strings.Builder
 
 	b.WriteString("\n")
-	b.WriteString(titleStyle.Render("  🚀 PVC Migration Tool"))
+	b.WriteString(titleStyle.Render("  " + i18n.T("tui.title")))
 	b.WriteString("\n\n")
 
 	// Show loading state while generating plan
@@ -210,38 +358,44 @@ func (m Model) View() string {
 		b.WriteString("  ")
 		b.WriteString(m.spinner.View())
 		b.WriteString(" ")
-		b.WriteString(infoStyle.Render("Generating migration plan..."))
+		b.WriteString(infoStyle.Render(i18n.T("tui.generating")))
 		b.WriteString("\n\n")
-		b.WriteString(dimStyle.Render("  Fetching volume information from AWS..."))
+		b.WriteString(dimStyle.Render("  " + i18n.T("tui.fetching")))
 		b.WriteString("\n\n")
 		return b.String()
 	}
 
 	// Show error if plan generation failed
 	if m.planError != nil {
-		b.WriteString(errorStyle.Render("  ✗ Failed to generate plan: "))
+		b.WriteString(errorStyle.Render("  " + i18n.T("tui.plan_failed")))
 		b.WriteString(errorStyle.Render(m.planError.Error()))
 		b.WriteString("\n\n")
-		b.WriteString(dimStyle.Render("  Press q to exit"))
+		b.WriteString(dimStyle.Render("  " + i18n.T("tui.press_q_exit")))
 		b.WriteString("\n\n")
 		return b.String()
 	}
 
 	// Show plan before confirmation
 	if !m.confirmed && m.plan != nil {
+		if m.compact() {
+			b.WriteString(migrator.FormatPlanCompact(m.plan))
+			b.WriteString(warningStyle.Render("  " + i18n.T("tui.scale_warning_short")))
+			b.WriteString("\n\n")
+			b.WriteString("  ")
+			b.WriteString(i18n.T("tui.confirm_short", headerStyle.Render("Enter/y"), headerStyle.Render("n/q")))
+			b.WriteString("\n\n")
+			return b.String()
+		}
+
 		b.WriteString(migrator.FormatPlan(m.plan))
 
-		b.WriteString(warningStyle.Render("  ⚠️  WARNING: Ensure all deployments/statefulsets are SCALED TO 0"))
+		b.WriteString(warningStyle.Render("  " + i18n.T("tui.scale_warning")))
+		b.WriteString("\n\n")
+		b.WriteString("  ")
+		b.WriteString(i18n.T("tui.confirm",
+			headerStyle.Render("Enter"), headerStyle.Render("y"),
+			headerStyle.Render("n"), headerStyle.Render("q")))
 		b.WriteString("\n\n")
-		b.WriteString("  Press ")
-		b.WriteString(headerStyle.Render("Enter"))
-		b.WriteString(" or ")
-		b.WriteString(headerStyle.Render("y"))
-		b.WriteString(" to start, ")
-		b.WriteString(headerStyle.Render("n"))
-		b.WriteString(" or ")
-		b.WriteString(headerStyle.Render("q"))
-		b.WriteString(" to cancel\n\n")
 		return b.String()
 	}
 
@@ -249,54 +403,85 @@ func (m Model) View() string {
 	namespacesStr := strings.Join(m.config.Namespaces, ", ")
 	configContent := fmt.Sprintf(
 		"%s %s\n%s %s\n%s %s\n%s %d\n%s %d",
-		infoStyle.Render("Namespaces:"),
+		infoStyle.Render(i18n.T("plan.namespaces")),
 		namespacesStr,
-		infoStyle.Render("Target Zone:"),
-		m.config.TargetZone,
-		infoStyle.Render("Storage Class:"),
+		infoStyle.Render(i18n.T("plan.target_zone")),
+		m.config.Destination(),
+		infoStyle.Render(i18n.T("plan.storage_class")),
 		m.config.StorageClass,
-		infoStyle.Render("Concurrency:"),
+		infoStyle.Render(i18n.T("plan.concurrency")),
 		m.config.MaxConcurrency,
-		infoStyle.Render("PVCs to migrate:"),
+		infoStyle.Render(i18n.T("tui.pvcs_to_migrate")),
 		len(m.config.PVCList),
 	)
 
 	if m.config.DryRun {
-		configContent += "\n" + warningStyle.Render("⚠️  DRY RUN MODE - No changes will be made")
+		configContent += "\n" + warningStyle.Render(i18n.T("plan.dry_run"))
 	}
 
-	b.WriteString(boxStyle.Render(configContent))
+	if m.compact() {
+		b.WriteString(m.renderCompactConfig())
+	} else {
+		b.WriteString(boxStyle.Render(configContent))
+	}
 	b.WriteString("\n\n")
 
-	b.WriteString(headerStyle.Render("  Migration Progress:"))
+	b.WriteString(headerStyle.Render("  " + i18n.T("tui.progress")))
 	b.WriteString("\n\n")
 
-	statuses := m.migrator.GetStatuses()
-
-	pvcNames := make([]string, 0, len(statuses))
-	for name := range statuses {
-		pvcNames = append(pvcNames, name)
+	for _, name := range m.pvcNames {
+		status := m.statuses[name]
+		b.WriteString(m.renderPVCStatus(status))
+		b.WriteString("\n")
 	}
-	sort.Strings(pvcNames)
 
-	for _, name := range pvcNames {
-		status := statuses[name]
-		b.WriteString(m.renderPVCStatus(status))
+	if m.showLogs && m.logs != nil {
 		b.WriteString("\n")
+		b.WriteString(m.renderLogPane())
 	}
 
 	b.WriteString("\n")
 	if !m.migrator.IsDone() {
-		b.WriteString(dimStyle.Render("  Press q or Ctrl+C to cancel"))
+		if m.migrator.Paused() {
+			b.WriteString(warningStyle.Render("  " + i18n.T("tui.paused")))
+			b.WriteString("\n")
+		}
+		if m.logs != nil {
+			b.WriteString(dimStyle.Render("  " + i18n.T("tui.press_cancel_logs")))
+		} else {
+			b.WriteString(dimStyle.Render("  " + i18n.T("tui.press_cancel")))
+		}
+	} else if retryable := len(m.migrator.RetryableFailures()); retryable > 0 {
+		b.WriteString(warningStyle.Render("  " + i18n.T("tui.retry_prompt", retryable)))
 	} else {
-		b.WriteString(successStyle.Render("  ✅ Migration complete! Press q to exit"))
+		b.WriteString(successStyle.Render("  " + i18n.T("tui.complete")))
 	}
 	b.WriteString("\n\n")
 
 	return b.String()
 }
 
+// renderCompactConfig renders the configuration without the surrounding box
+func (m Model) renderCompactConfig() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("  %s %s\n", infoStyle.Render("NS:"), truncate(strings.Join(m.config.Namespaces, ","), 40)))
+	b.WriteString(fmt.Sprintf("  %s %s  %s %s\n",
+		infoStyle.Render(i18n.T("tui.zone_short")), m.config.Destination(),
+		infoStyle.Render("SC:"), m.config.StorageClass))
+	b.WriteString(fmt.Sprintf("  %s %d  %s %d",
+		infoStyle.Render("PVCs:"), len(m.config.PVCList),
+		infoStyle.Render("Conc:"), m.config.MaxConcurrency))
+	if m.config.DryRun {
+		b.WriteString("\n  " + warningStyle.Render("⚠️  DRY RUN"))
+	}
+	return b.String()
+}
+
 func (m Model) renderPVCStatus(status *migrator.PVCStatus) string {
+	if m.compact() {
+		return m.renderCompactPVCStatus(status)
+	}
+
 	var b strings.Builder
 
 	b.WriteString("  ")
@@ -307,12 +492,15 @@ func (m Model) renderPVCStatus(status *migrator.PVCStatus) string {
 	case migrator.StepPending:
 		b.WriteString(dimStyle.Render("○"))
 		b.WriteString(" ")
-		b.WriteString(stepStyle.Render("Pending"))
+		b.WriteString(stepStyle.Render(stepName(status.Step)))
+		if priority := m.config.PriorityFor(status.Name); priority != migrator.PriorityNormal {
+			b.WriteString(dimStyle.Render(" " + i18n.T("tui.priority", i18n.T("priority."+priority))))
+		}
 
 	case migrator.StepDone:
 		b.WriteString(successStyle.Render("✓"))
 		b.WriteString(" ")
-		b.WriteString(successStyle.Render("Completed"))
+		b.WriteString(successStyle.Render(stepName(status.Step)))
 		if !status.EndTime.IsZero() && !status.StartTime.IsZero() {
 			duration := status.EndTime.Sub(status.StartTime).Round(time.Second)
 			b.WriteString(dimStyle.Render(fmt.Sprintf(" (%s)", duration)))
@@ -321,13 +509,13 @@ func (m Model) renderPVCStatus(status *migrator.PVCStatus) string {
 	case migrator.StepSkipped:
 		b.WriteString(warningStyle.Render("○"))
 		b.WriteString(" ")
-		b.WriteString(warningStyle.Render("Skipped"))
-		b.WriteString(dimStyle.Render(" (already in target zone)"))
+		b.WriteString(warningStyle.Render(stepName(status.Step)))
+		b.WriteString(dimStyle.Render(" " + i18n.T("summary.already_in_zone")))
 
 	case migrator.StepFailed:
 		b.WriteString(errorStyle.Render("✗"))
 		b.WriteString(" ")
-		b.WriteString(errorStyle.Render("Failed"))
+		b.WriteString(errorStyle.Render(stepName(status.Step)))
 		if status.Error != nil {
 			b.WriteString(dimStyle.Render(fmt.Sprintf(" - %s", truncate(status.Error.Error(), 40))))
 		}
@@ -337,7 +525,7 @@ func (m Model) renderPVCStatus(status *migrator.PVCStatus) string {
 		migrator.StepCreatePV, migrator.StepCreatePVC:
 		b.WriteString(m.spinner.View())
 		b.WriteString(" ")
-		b.WriteString(stepStyle.Render(status.Step.String()))
+		b.WriteString(stepStyle.Render(stepName(status.Step)))
 		b.WriteString(" ")
 
 		if status.Step == migrator.StepWaitSnapshot && status.Progress > 0 {
@@ -350,11 +538,110 @@ func (m Model) renderPVCStatus(status *migrator.PVCStatus) string {
 				b.WriteString(p.ViewAs(float64(status.Progress) / 100.0))
 			}
 		}
+		if throttled(status) {
+			b.WriteString(warningStyle.Render(" " + i18n.T("tui.throttled")))
+		}
+	}
+
+	return b.String()
+}
+
+// throttleNotice is how long a row says its EC2 calls are throttled after the last one was
+const throttleNotice = 30 * time.Second
+
+// throttled reports whether EC2 throttled a call of the PVC within throttleNotice
+func throttled(status *migrator.PVCStatus) bool {
+	return !status.ThrottledAt.IsZero() && time.Since(status.ThrottledAt) < throttleNotice
+}
+
+// renderCompactPVCStatus renders a status line that fits in about 60 columns
+func (m Model) renderCompactPVCStatus(status *migrator.PVCStatus) string {
+	var b strings.Builder
+
+	b.WriteString(" ")
+	b.WriteString(compactNameStyle.Render(truncate(status.Name, 22)))
+	b.WriteString(" ")
+
+	switch status.Step {
+	case migrator.StepPending:
+		b.WriteString(dimStyle.Render("○ "))
+		b.WriteString(compactStepStyle.Render(shortStepName(status.Step)))
+
+	case migrator.StepDone:
+		b.WriteString(successStyle.Render("✓ " + shortStepName(status.Step)))
+		if !status.EndTime.IsZero() && !status.StartTime.IsZero() {
+			duration := status.EndTime.Sub(status.StartTime).Round(time.Second)
+			b.WriteString(dimStyle.Render(fmt.Sprintf(" %s", duration)))
+		}
+
+	case migrator.StepSkipped:
+		b.WriteString(warningStyle.Render("○ " + shortStepName(status.Step)))
+
+	case migrator.StepFailed:
+		b.WriteString(errorStyle.Render("✗ " + shortStepName(status.Step)))
+		if status.Error != nil {
+			b.WriteString(dimStyle.Render(" " + truncate(status.Error.Error(), 20)))
+		}
+
+	case migrator.StepGetInfo, migrator.StepSnapshot, migrator.StepWaitSnapshot,
+		migrator.StepCreateVolume, migrator.StepWaitVolume, migrator.StepCleanup,
+		migrator.StepCreatePV, migrator.StepCreatePVC:
+		b.WriteString(m.spinner.View())
+		b.WriteString(" ")
+		b.WriteString(compactStepStyle.Render(shortStepName(status.Step)))
+
+		if (status.Step == migrator.StepWaitSnapshot || status.Step == migrator.StepWaitVolume) && status.Progress > 0 {
+			if p, ok := m.progressBars[status.Name]; ok {
+				b.WriteString(p.ViewAs(float64(status.Progress) / 100.0))
+				b.WriteString(dimStyle.Render(fmt.Sprintf(" %d%%", status.Progress)))
+			}
+		}
 	}
 
 	return b.String()
 }
 
+// stepName returns the localized step name
+func stepName(step migrator.Step) string {
+	return i18n.T("step." + stepKey(step))
+}
+
+// shortStepName returns an abbreviated step name for the compact layout
+func shortStepName(step migrator.Step) string {
+	return i18n.T("step.short." + stepKey(step))
+}
+
+// stepKey returns the message catalog key suffix of a step
+func stepKey(step migrator.Step) string {
+	switch step {
+	case migrator.StepPending:
+		return "pending"
+	case migrator.StepGetInfo:
+		return "get_info"
+	case migrator.StepSkipped:
+		return "skipped"
+	case migrator.StepSnapshot:
+		return "snapshot"
+	case migrator.StepWaitSnapshot:
+		return "wait_snapshot"
+	case migrator.StepCreateVolume:
+		return "create_volume"
+	case migrator.StepWaitVolume:
+		return "wait_volume"
+	case migrator.StepCleanup:
+		return "cleanup"
+	case migrator.StepCreatePV:
+		return "create_pv"
+	case migrator.StepCreatePVC:
+		return "create_pvc"
+	case migrator.StepDone:
+		return "done"
+	case migrator.StepFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
 // HasErrors returns true if any migration failed
 func (m Model) HasErrors() bool {
 	statuses := m.migrator.GetStatuses()
@@ -369,14 +656,20 @@ func (m Model) HasErrors() bool {
 // PrintSummary prints a summary after the TUI exits
 func (m Model) PrintSummary() {
 	if m.quitting && !m.started {
+		// Nothing was migrated, but restore failures still need follow-up
+		PrintActionRequired(m.migrator.Warnings())
 		return
 	}
 
 	statuses := m.migrator.GetStatuses()
+	remediations := make(map[string]migrator.Remediation)
+	for _, r := range m.migrator.Remediations() {
+		remediations[r.PVC] = r
+	}
 
 	fmt.Println()
 	fmt.Println(headerStyle.Render("═══════════════════════════════════════════════════════════════"))
-	fmt.Println(headerStyle.Render("                      MIGRATION SUMMARY"))
+	fmt.Println(headerStyle.Render(centerText(i18n.T("summary.title"), 63)))
 	fmt.Println(headerStyle.Render("═══════════════════════════════════════════════════════════════"))
 	fmt.Println()
 
@@ -401,41 +694,210 @@ func (m Model) PrintSummary() {
 			}
 			fmt.Printf("  %s %s%s\n", successStyle.Render("✓"), s.Name, dimStyle.Render(duration))
 			if s.NewVolumeID != "" {
-				fmt.Printf("    %s %s\n", dimStyle.Render("New Volume:"), s.NewVolumeID)
+				fmt.Printf("    %s %s\n", dimStyle.Render(i18n.T("summary.new_volume")), s.NewVolumeID)
+			}
+			if adopted := adoptedResources(s); adopted != "" {
+				fmt.Printf("    %s %s\n", dimStyle.Render(i18n.T("summary.adopted")), adopted)
+			}
+			if s.ChecksumVerified {
+				fmt.Printf("    %s %s\n", dimStyle.Render(i18n.T("summary.checksum")), successStyle.Render(i18n.T("summary.checksum_ok", s.Checksum)))
+			}
+			switch s.MountCheck {
+			case migrator.MountCheckPassed:
+				fmt.Printf("    %s %s\n", dimStyle.Render(i18n.T("summary.mount_check")), successStyle.Render(i18n.T("summary.mount_passed")))
+			case migrator.MountCheckFailed:
+				fmt.Printf("    %s %s\n", dimStyle.Render(i18n.T("summary.mount_check")), warningStyle.Render(i18n.T("summary.mount_failed", s.MountCheckError)))
 			}
 		case migrator.StepSkipped:
 			skippedCount++
-			fmt.Printf("  %s %s %s\n", warningStyle.Render("○"), s.Name, dimStyle.Render("(already in target zone)"))
+			fmt.Printf("  %s %s %s\n", warningStyle.Render("○"), s.Name, dimStyle.Render(i18n.T("summary.already_in_zone")))
 		case migrator.StepFailed:
 			failedCount++
 			fmt.Printf("  %s %s\n", errorStyle.Render("✗"), s.Name)
 			if s.Error != nil {
-				fmt.Printf("    %s %s\n", errorStyle.Render("Error:"), s.Error.Error())
+				fmt.Printf("    %s %s\n", errorStyle.Render(i18n.T("summary.error")), s.Error.Error())
 			}
+			fmt.Print(formatRemediation(remediations[name]))
 		case migrator.StepPending, migrator.StepGetInfo, migrator.StepSnapshot,
 			migrator.StepWaitSnapshot, migrator.StepCreateVolume, migrator.StepWaitVolume,
 			migrator.StepCleanup, migrator.StepCreatePV, migrator.StepCreatePVC:
-			fmt.Printf("  %s %s (Incomplete)\n", warningStyle.Render("○"), s.Name)
+			fmt.Printf("  %s %s %s\n", warningStyle.Render("○"), s.Name, i18n.T("summary.incomplete"))
 		}
 	}
 
 	fmt.Println()
 	fmt.Println(headerStyle.Render("═══════════════════════════════════════════════════════════════"))
-	fmt.Printf("  Total: %d | ", len(statuses))
-	fmt.Printf("%s | ", successStyle.Render(fmt.Sprintf("Success: %d", successCount)))
-	fmt.Printf("%s | ", warningStyle.Render(fmt.Sprintf("Skipped: %d", skippedCount)))
-	fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Failed: %d", failedCount)))
+	fmt.Printf("  %s | ", i18n.T("summary.total", len(statuses)))
+	fmt.Printf("%s | ", successStyle.Render(i18n.T("summary.success", successCount)))
+	fmt.Printf("%s | ", warningStyle.Render(i18n.T("summary.skipped", skippedCount)))
+	fmt.Printf("%s\n", errorStyle.Render(i18n.T("summary.failed", failedCount)))
+	if usage := migrator.FormatAPIUsage(m.migrator.APIUsage()); usage != "" {
+		fmt.Printf("  %s\n", dimStyle.Render(i18n.T("summary.api_calls", usage)))
+	}
 	fmt.Println(headerStyle.Render("═══════════════════════════════════════════════════════════════"))
 
-	if failedCount > 0 {
+	warnings := m.migrator.Warnings()
+	stragglers := m.migrator.Stragglers()
+	switch {
+	case failedCount > 0:
 		fmt.Println()
-		fmt.Println(warningStyle.Render("  ⚠️  Some migrations failed. Please check the errors above."))
-	} else if successCount > 0 {
+		fmt.Println(warningStyle.Render("  " + i18n.T("summary.some_failed")))
+		if retryable := len(m.migrator.RetryableFailures()); retryable > 0 {
+			fmt.Printf("  %s\n", dimStyle.Render(i18n.T("summary.retry_hint", retryable)))
+		}
+	case len(stragglers) > 0:
 		fmt.Println()
-		fmt.Println(successStyle.Render("  🎉 All migrations completed successfully!"))
-		fmt.Printf("  %s\n", infoStyle.Render(fmt.Sprintf("Next step: Ensure your workloads can schedule pods in %s", m.config.TargetZone)))
+		fmt.Println(warningStyle.Render("  " + i18n.T("summary.zone_not_empty")))
+	case len(warnings) > 0:
+		fmt.Println()
+		fmt.Println(warningStyle.Render("  " + i18n.T("summary.with_warnings", len(warnings))))
+	case successCount > 0:
+		fmt.Println()
+		fmt.Println(successStyle.Render("  " + i18n.T("summary.all_ok")))
+		fmt.Printf("  %s\n", infoStyle.Render(i18n.T("summary.next_step", m.config.Destination())))
 	}
 	fmt.Println()
+
+	PrintOrphans(m.migrator.Orphans(), m.config.KubeContext)
+	PrintStragglers(stragglers, m.config.SourceZone)
+	PrintActionRequired(warnings)
+}
+
+// PrintStragglers prints the volumes an evacuation run left in the source zone,
+// if any
+func PrintStragglers(stragglers []migrator.Straggler, sourceZone string) {
+	if len(stragglers) > 0 {
+		fmt.Print(formatStragglers(stragglers, sourceZone))
+	}
+}
+
+// PrintOrphans prints the Kubernetes objects the run left without a claim, if any,
+// with the commands that delete them
+func PrintOrphans(orphans []migrator.OrphanedObject, kubeContext string) {
+	if len(orphans) > 0 {
+		fmt.Print(formatOrphans(orphans, kubeContext))
+	}
+}
+
+// PrintActionRequired prints the warnings collected during the run, if any. It is
+// used on its own when a run ends without a summary, e.g. when it was cancelled.
+func PrintActionRequired(warnings []migrator.Warning) {
+	if len(warnings) > 0 {
+		fmt.Print(formatActionRequired(warnings))
+	}
+}
+
+// adoptedResources lists the snapshot, volume and static PV of a PVC that an
+// earlier run created, or returns "" when there are none
+func adoptedResources(s *migrator.PVCStatus) string {
+	var ids []string
+	if s.AdoptedSnapshot {
+		ids = append(ids, s.SnapshotID)
+	}
+	if s.AdoptedVolume {
+		ids = append(ids, s.NewVolumeID)
+	}
+	if s.ResumedAt != migrator.StepPending {
+		ids = append(ids, s.PVCName+"-static")
+	}
+	return strings.Join(ids, ", ")
+}
+
+// formatRemediation renders the manual commands for a failed PVC below its error
+func formatRemediation(r migrator.Remediation) string {
+	var b strings.Builder
+	for _, block := range []struct {
+		title    string
+		commands []string
+	}{
+		{i18n.T("summary.finish"), r.Finish},
+		{i18n.T("summary.rollback"), r.Rollback},
+	} {
+		if len(block.commands) == 0 {
+			continue
+		}
+		b.WriteString("    " + infoStyle.Render(block.title) + "\n")
+		for _, c := range block.commands {
+			for _, line := range strings.Split(c, "\n") {
+				b.WriteString("      " + dimStyle.Render(line) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatActionRequired lists the warnings collected during the run, with the
+// follow-up each one needs, so they are not lost in the scrollback
+func formatActionRequired(warnings []migrator.Warning) string {
+	var b strings.Builder
+	b.WriteString(warningStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+	b.WriteString(warningStyle.Render(centerText(i18n.T("summary.action_required"), 63)) + "\n")
+	b.WriteString(warningStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+
+	for _, w := range warnings {
+		b.WriteString("\n  " + warningStyle.Render("⚠") + " ")
+		if w.PVC != "" {
+			b.WriteString(w.PVC + ": ")
+		}
+		b.WriteString(w.Message + "\n")
+		if w.Action == "" {
+			continue
+		}
+		lines := strings.Split(w.Action, "\n")
+		b.WriteString("    " + infoStyle.Render(i18n.T("summary.action")) + " " + lines[0] + "\n")
+		for _, line := range lines[1:] {
+			b.WriteString("      " + dimStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatOrphans lists the objects left without a claim in their own section, as
+// they outlive the run unless someone deletes them
+func formatOrphans(orphans []migrator.OrphanedObject, kubeContext string) string {
+	var b strings.Builder
+	b.WriteString(warningStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+	b.WriteString(warningStyle.Render(centerText(i18n.T("summary.orphans"), 63)) + "\n")
+	b.WriteString(warningStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+	b.WriteString("\n  " + dimStyle.Render(i18n.T("summary.orphans_hint")) + "\n\n")
+
+	for _, o := range orphans {
+		b.WriteString("  " + warningStyle.Render("⚠") + " " + i18n.T("summary.orphan", o.PVC, o.Kind, o.Name, o.VolumeID, o.Reason) + "\n")
+	}
+	b.WriteString("    " + infoStyle.Render(i18n.T("summary.orphans_gc")) + "\n")
+	for _, o := range orphans {
+		b.WriteString("      " + dimStyle.Render(o.DeleteCommand(kubeContext)) + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatStragglers lists the volumes left in the source zone in their own
+// section, as they keep the zone from being decommissioned
+func formatStragglers(stragglers []migrator.Straggler, sourceZone string) string {
+	var b strings.Builder
+	b.WriteString(errorStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+	b.WriteString(errorStyle.Render(centerText(i18n.T("summary.stragglers", sourceZone), 63)) + "\n")
+	b.WriteString(errorStyle.Render("═══════════════════════════════════════════════════════════════") + "\n")
+	b.WriteString("\n  " + dimStyle.Render(i18n.T("summary.stragglers_hint")) + "\n\n")
+
+	for _, s := range stragglers {
+		b.WriteString("  " + errorStyle.Render("✗") + " " + i18n.T("summary.straggler", s.PVC, s.PVName, s.VolumeID) + "\n")
+	}
+	b.WriteString("    " + infoStyle.Render(i18n.T("summary.stragglers_list")) + "\n")
+	b.WriteString("      " + dimStyle.Render(migrator.DescribeStragglersCommand(stragglers)) + "\n")
+	b.WriteString("\n")
+	return b.String()
+}
+
+// centerText pads s with leading spaces so it is centered in width columns
+func centerText(s string, width int) string {
+	pad := (width - lipgloss.Width(s)) / 2
+	if pad <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", pad) + s
 }
 
 func truncate(s string, maxLen int) string {
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -87,6 +90,27 @@ func TestModel_Init(t *testing.T) {
 	require.NotNil(t, cmd)
 }
 
+func TestModel_WithPlan(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList:    []string{"ns/pvc-1"},
+		TargetZone: "us-east-1a",
+	}
+	m := migrator.New(config, nil, nil)
+	plan := &migrator.MigrationPlan{
+		TargetZone: "us-east-1a",
+		Items:      []migrator.PVCPlanItem{{Name: "ns/pvc-1", Action: migrator.PlanActionMigrate, TargetZone: "us-east-1a"}},
+	}
+
+	model := NewModel(m, config).WithPlan(plan)
+
+	assert.False(t, model.generatingPlan)
+	assert.Same(t, plan, model.plan)
+	assert.NotNil(t, model.Init())
+	assert.Contains(t, model.View(), "ns/pvc-1", "the confirmation screen shows the given plan")
+}
+
 func TestModel_Update_QuitKeys(t *testing.T) {
 	t.Parallel()
 
@@ -169,6 +193,49 @@ func TestModel_Update_EnterKey(t *testing.T) {
 	assert.True(t, updatedModel.confirmed)
 }
 
+func TestModel_Update_PauseKey(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList: []string{"ns/pvc-1"},
+	}
+	m := migrator.New(config, nil, nil)
+	model := NewModel(m, config)
+	model.generatingPlan = false
+	model.confirmed = true
+
+	pause := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}
+
+	_, _ = model.Update(pause)
+	assert.False(t, m.Paused(), "ignored before the migration starts")
+
+	model.started = true
+	newModel, _ := model.Update(pause)
+	require.True(t, m.Paused())
+	assert.Contains(t, newModel.View(), "Paused")
+
+	newModel, _ = newModel.Update(pause)
+	assert.False(t, m.Paused())
+	assert.NotContains(t, newModel.View(), "Paused")
+}
+
+func TestModel_Update_RetryKeyWithoutFailures(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList: []string{"ns/pvc-1"},
+	}
+	m := migrator.New(config, nil, nil)
+	model := NewModel(m, config)
+	model.generatingPlan = false
+	model.confirmed = true
+	model.started = true
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	assert.Nil(t, cmd, "nothing to retry")
+	assert.Empty(t, m.RetryableFailures())
+}
+
 func TestModel_Update_NKey(t *testing.T) {
 	t.Parallel()
 
@@ -189,6 +256,30 @@ func TestModel_Update_NKey(t *testing.T) {
 	assert.NotNil(t, cmd)
 }
 
+func TestModel_WithAutoStart(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList:    []string{"ns/pvc-1"},
+		TargetZone: "us-east-1a",
+	}
+	m := migrator.New(config, nil, nil)
+	plan := &migrator.MigrationPlan{
+		Items: []migrator.PVCPlanItem{{Name: "ns/pvc-1", Action: migrator.PlanActionMigrate}},
+	}
+
+	model := NewModel(m, config).WithPlan(plan).WithAutoStart()
+
+	assert.True(t, model.confirmed)
+	view := model.View()
+	assert.NotContains(t, view, "to start", "no confirmation is asked")
+	assert.Contains(t, view, "Migration Progress")
+
+	// A plan that failed is shown rather than started
+	newModel, _ := NewModel(m, config).WithAutoStart().Update(planReadyMsg{err: assert.AnError})
+	assert.Contains(t, newModel.View(), "Failed to generate plan")
+}
+
 func TestModel_Update_PlanReadyMsg(t *testing.T) {
 	t.Parallel()
 
@@ -247,6 +338,76 @@ func TestModel_Update_WindowSizeMsg(t *testing.T) {
 	assert.Nil(t, cmd)
 }
 
+func TestModel_Update_WindowSizeMsg_Compact(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		width       int
+		wantCompact bool
+		wantBar     int
+	}{
+		{name: "wide_terminal", width: 120, wantCompact: false, wantBar: progressBarWidth},
+		{name: "at_threshold", width: compactWidth, wantCompact: false, wantBar: progressBarWidth},
+		{name: "narrow_terminal", width: 80, wantCompact: true, wantBar: compactProgressBarWidth},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &migrator.Config{
+				PVCList: []string{"ns/pvc-1"},
+			}
+			m := migrator.New(config, nil, nil)
+			model := NewModel(m, config)
+
+			newModel, _ := model.Update(tea.WindowSizeMsg{Width: tc.width, Height: 40})
+			updated := newModel.(Model)
+
+			assert.Equal(t, tc.wantCompact, updated.compact())
+			assert.Equal(t, tc.wantBar, updated.progressBars["ns/pvc-1"].Width)
+		})
+	}
+}
+
+func TestModel_View_Compact(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		Namespaces:     []string{"monitoring"},
+		TargetZone:     "us-west-2a",
+		StorageClass:   "gp3",
+		MaxConcurrency: 5,
+		PVCList:        []string{"monitoring/prometheus-server-data-volume-0"},
+	}
+	m := migrator.New(config, nil, nil)
+	model := NewModel(m, config)
+	model.confirmed = true
+	model.generatingPlan = false
+
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 60, Height: 40})
+	view := newModel.(Model).View()
+
+	assert.Contains(t, view, "Zone:")
+	assert.Contains(t, view, "monitoring/promethe...")
+	assert.NotContains(t, view, "Storage Class:", "config box is replaced by compact lines")
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 60, "line too wide: %q", line)
+	}
+}
+
+func TestShortStepName(t *testing.T) {
+	t.Parallel()
+
+	for step := migrator.StepPending; step <= migrator.StepFailed; step++ {
+		name := shortStepName(step)
+		assert.NotEmpty(t, name)
+		assert.LessOrEqual(t, lipgloss.Width(name), 10, "step %s", step)
+	}
+	assert.Equal(t, "Snap…", shortStepName(migrator.StepWaitSnapshot))
+}
+
 func TestModel_View_GeneratingPlan(t *testing.T) {
 	t.Parallel()
 
@@ -430,6 +591,20 @@ func TestModel_HasErrors(t *testing.T) {
 	}
 }
 
+func TestAdoptedResources(t *testing.T) {
+	t.Parallel()
+
+	s := &migrator.PVCStatus{SnapshotID: "snap-1", NewVolumeID: "vol-2"}
+	assert.Empty(t, adoptedResources(s))
+	s.AdoptedVolume = true
+	assert.Equal(t, "vol-2", adoptedResources(s))
+	s.AdoptedSnapshot = true
+	assert.Equal(t, "snap-1, vol-2", adoptedResources(s))
+
+	s = &migrator.PVCStatus{PVCName: "data", NewVolumeID: "vol-2", AdoptedVolume: true, ResumedAt: migrator.StepCleanup}
+	assert.Equal(t, "vol-2, data-static", adoptedResources(s))
+}
+
 func TestTruncate(t *testing.T) {
 	t.Parallel()
 
@@ -607,3 +782,94 @@ func TestTickMsg(t *testing.T) {
 	assert.NotNil(t, newModel)
 	assert.NotNil(t, cmd)
 }
+
+func TestTickMsg_RefreshesChangedStatuses(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList: []string{"ns/pvc-2", "ns/pvc-1"},
+	}
+	m := migrator.New(config, nil, nil)
+	model := NewModel(m, config)
+	require.Equal(t, []string{"ns/pvc-1", "ns/pvc-2"}, model.pvcNames)
+	assert.Equal(t, migrator.StepPending, model.statuses["ns/pvc-1"].Step)
+
+	_, latest := m.StatusesSince(0)
+	assert.Equal(t, latest, model.statusSeq)
+
+	updated, _ := model.Update(tickMsg{})
+	assert.Equal(t, latest, updated.(Model).statusSeq, "nothing changed")
+}
+
+func TestNewModel_PriorityOrder(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{
+		PVCList:    []string{"archive/cold", "db/data", "web/static"},
+		Priorities: map[string]string{"archive/cold": migrator.PriorityLow, "web/static": migrator.PriorityHigh},
+	}
+	model := NewModel(migrator.New(config, nil, nil), config)
+	assert.Equal(t, []string{"web/static", "db/data", "archive/cold"}, model.pvcNames)
+	assert.Contains(t, model.renderPVCStatus(model.statuses["web/static"]), "high priority")
+	assert.NotContains(t, model.renderPVCStatus(model.statuses["db/data"]), "priority")
+}
+
+func TestRenderPVCStatus_Throttled(t *testing.T) {
+	t.Parallel()
+
+	config := &migrator.Config{PVCList: []string{"db/data"}}
+	model := NewModel(migrator.New(config, nil, nil), config)
+
+	status := &migrator.PVCStatus{Name: "db/data", Step: migrator.StepWaitSnapshot}
+	assert.NotContains(t, model.renderPVCStatus(status), "throttled")
+
+	status.ThrottledAt = time.Now()
+	assert.Contains(t, model.renderPVCStatus(status), "throttled by EC2, backing off")
+
+	status.ThrottledAt = time.Now().Add(-time.Minute)
+	assert.NotContains(t, model.renderPVCStatus(status), "throttled", "only recent throttling is shown")
+}
+
+func TestFormatActionRequired(t *testing.T) {
+	t.Parallel()
+
+	out := formatActionRequired([]migrator.Warning{
+		{
+			Message: "Workloads in namespace 'db' were not restored: timeout",
+			Action:  "Scale the workloads back up:\nkubectl scale statefulset postgres --replicas=3 -n db",
+		},
+		{PVC: "db/data", Message: "Warm-up job was not created: forbidden"},
+	})
+
+	assert.Contains(t, out, "ACTION REQUIRED")
+	assert.Contains(t, out, "Workloads in namespace 'db' were not restored: timeout")
+	assert.Contains(t, out, "Action: Scale the workloads back up:")
+	assert.Contains(t, out, "      kubectl scale statefulset postgres --replicas=3 -n db")
+	assert.Contains(t, out, "db/data: Warm-up job was not created: forbidden")
+	assert.Equal(t, 1, strings.Count(out, "Action:"), "warnings without an action print no action line")
+}
+
+func TestFormatOrphans(t *testing.T) {
+	t.Parallel()
+
+	out := formatOrphans([]migrator.OrphanedObject{
+		{PVC: "db/data", Kind: "PersistentVolume", Name: "data-static", VolumeID: "vol-new", Reason: "the claim is bound to pv-x"},
+	}, "")
+
+	assert.Contains(t, out, "ORPHANED KUBERNETES OBJECTS")
+	assert.Contains(t, out, "db/data: PersistentVolume data-static (volume vol-new), the claim is bound to pv-x")
+	assert.Contains(t, out, "      kubectl delete persistentvolume data-static --ignore-not-found\n")
+}
+
+func TestFormatRemediation(t *testing.T) {
+	t.Parallel()
+
+	out := formatRemediation(migrator.Remediation{
+		Finish: []string{"# Create the static PV", "kubectl apply -f - <<'EOF'\nkind: PersistentVolume\nEOF"},
+	})
+
+	assert.Contains(t, out, "To finish by hand:")
+	assert.Contains(t, out, "      kind: PersistentVolume\n")
+	assert.NotContains(t, out, "To roll back:")
+	assert.Empty(t, formatRemediation(migrator.Remediation{}))
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// Schema returns the JSON schema (draft 2020-12) of a kind of document
+func Schema(kind string) ([]byte, error) {
+	switch kind {
+	case KindPlan:
+		return schemas.ReadFile("schemas/plan.json")
+	case KindResult:
+		return schemas.ReadFile("schemas/result.json")
+	}
+	return nil, fmt.Errorf("unknown kind '%s'", kind)
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaObject is the part of a JSON schema object the tests compare with the types
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Defs       map[string]schemaObject    `json:"$defs"`
+}
+
+// jsonFields returns the JSON names of a struct's fields, and those always written
+func jsonFields(t reflect.Type) (all, required []string) {
+	for i := range t.NumField() {
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		all = append(all, name)
+		if opts != "omitempty" {
+			required = append(required, name)
+		}
+	}
+	return all, required
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func TestSchema_MatchesTypes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		kind string
+		root any
+		defs map[string]any
+	}{
+		{kind: KindPlan, root: Plan{}, defs: map[string]any{"planItem": PlanItem{}, "zoneChoice": ZoneChoice{}, "nodeIssue": NodeIssue{}, "workloadSchedule": WorkloadSchedule{}, "quotaCheck": QuotaCheck{}, "storageQuota": StorageQuota{}}},
+		{kind: KindResult, root: Result{}, defs: map[string]any{"pvcResult": PVCResult{}, "warning": Warning{}, "orphan": Orphan{}, "apiUsage": APIUsage{}, "straggler": Straggler{}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.kind, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Schema(tc.kind)
+			require.NoError(t, err)
+			var schema schemaObject
+			require.NoError(t, json.Unmarshal(data, &schema))
+
+			check := func(name string, object schemaObject, v any) {
+				all, required := jsonFields(reflect.TypeOf(v))
+				properties := keys(object.Properties)
+				sort.Strings(all)
+				sort.Strings(properties)
+				sort.Strings(required)
+				sort.Strings(object.Required)
+				assert.Equal(t, all, properties, "properties of %s", name)
+				assert.Equal(t, required, object.Required, "required properties of %s", name)
+			}
+			check(tc.kind, schema, tc.root)
+			require.Len(t, schema.Defs, len(tc.defs))
+			for name, v := range tc.defs {
+				check(name, schema.Defs[name], v)
+			}
+		})
+	}
+}
+
+func TestSchema_UnknownKind(t *testing.T) {
+	t.Parallel()
+
+	_, err := Schema("Summary")
+	assert.ErrorContains(t, err, "unknown kind 'Summary'")
+}
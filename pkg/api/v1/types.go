@@ -0,0 +1,243 @@
+// Package v1 defines the JSON documents pvc-migrator writes with --output json:
+// the migration plan and the result of a run. Fields are only ever added to
+// this version; renaming or removing one, or changing its meaning, needs a new
+// version. The JSON schemas of both documents are embedded, see Schema.
+package v1
+
+import "time"
+
+// APIVersion is the apiVersion of every document of this package
+const APIVersion = "pvc-migrator/v1"
+
+// Kinds of document
+const (
+	KindPlan   = "Plan"
+	KindResult = "Result"
+)
+
+// Actions of a plan item
+const (
+	ActionMigrate = "migrate"
+	ActionSkip    = "skip"
+	ActionError   = "error"
+)
+
+// Outcomes of a PVC in a result
+const (
+	OutcomeMigrated   = "migrated"
+	OutcomeSkipped    = "skipped"
+	OutcomeFailed     = "failed"
+	OutcomeIncomplete = "incomplete" // The run was cancelled before the PVC finished
+)
+
+// Plan is what a run will do to each PVC
+type Plan struct {
+	APIVersion   string     `json:"apiVersion"`
+	Kind         string     `json:"kind"`
+	TargetZone   string     `json:"targetZone"` // Comma-separated when PVCs are spread across zones
+	StorageClass string     `json:"storageClass"`
+	DryRun       bool       `json:"dryRun"`
+	Namespaces   []string   `json:"namespaces"`
+	Concurrency  int        `json:"concurrency"`
+	Items        []PlanItem `json:"items"`
+
+	KMSKeyID            string `json:"kmsKeyId,omitempty"`            // Key given for the run
+	EncryptionByDefault *bool  `json:"encryptionByDefault,omitempty"` // Account setting; omitted when it could not be read
+	DefaultKMSKeyID     string `json:"defaultKmsKeyId,omitempty"`     // Key the account encrypts with by default
+
+	AutoZones  []ZoneChoice `json:"autoZones,omitempty"`  // Zone picked for each namespace with --zone auto
+	NodeIssues []NodeIssue  `json:"nodeIssues,omitempty"` // Target zones the pods of PVCs to migrate could not run in
+	// Schedulability is, with --simulate-scheduling, whether a pod of each
+	// workload mounting PVCs to migrate could be scheduled in their target zone
+	Schedulability []WorkloadSchedule `json:"schedulability,omitempty"`
+	// Quotas is, with --check-quotas, the EBS usage the run adds to and the
+	// quotas it was compared against
+	Quotas *QuotaCheck `json:"quotas,omitempty"`
+	// MissingPermissions are, with --check-permissions, the EC2 actions the
+	// credentials are denied, such as "ec2:CreateSnapshot"
+	MissingPermissions []string `json:"missingPermissions,omitempty"`
+	// ZoneIDs is the ID of each zone of the region, such as use1-az2, by name;
+	// names point at different physical zones in each account, IDs do not
+	ZoneIDs map[string]string `json:"zoneIds,omitempty"`
+	// FastSnapshotRestore is set with --fast-snapshot-restore: fast snapshot
+	// restore is enabled on each snapshot in its target zone while its volume is created
+	FastSnapshotRestore bool `json:"fastSnapshotRestore,omitempty"`
+	// VolumeType, IOPS and Throughput are given for the run and override those
+	// of the old volumes; each item has those of its new volume
+	VolumeType string `json:"volumeType,omitempty"`
+	IOPS       int32  `json:"iops,omitempty"`
+	Throughput int32  `json:"throughput,omitempty"`
+}
+
+// QuotaCheck is the EBS usage of the account in the region, what the run adds
+// to it and the quotas it is compared against
+type QuotaCheck struct {
+	Snapshots     int            `json:"snapshots"` // Snapshots the account owns
+	NewSnapshots  int            `json:"newSnapshots"`
+	SnapshotQuota int            `json:"snapshotQuota"`
+	Storage       []StorageQuota `json:"storage"` // One for each type of the new volumes
+}
+
+// StorageQuota is the storage of the account's volumes of one type, what the
+// run adds to it and the quota it is compared against
+type StorageQuota struct {
+	VolumeType    string `json:"volumeType"`
+	StorageGiB    int64  `json:"storageGiB"`
+	NewStorageGiB int64  `json:"newStorageGiB"`
+	QuotaGiB      int64  `json:"quotaGiB"`
+}
+
+// WorkloadSchedule is whether a pod of a Deployment or StatefulSet, dry-run in
+// the zone its PVCs are moved to, was admitted and fits on a node there
+type WorkloadSchedule struct {
+	Workload string `json:"workload"` // "namespace/Kind/name"
+	Zone     string `json:"zone"`
+	Admitted bool   `json:"admitted"`
+	Nodes    int    `json:"nodes"`            // Healthy nodes of the zone the pod fits on
+	Reason   string `json:"reason,omitempty"` // Why it was rejected or fits on no node
+}
+
+// NodeIssue is a target zone without a healthy node, or without one matching the
+// nodeSelector of a workload mounting PVCs moved there
+type NodeIssue struct {
+	Zone     string   `json:"zone"`
+	Workload string   `json:"workload,omitempty"` // "namespace/Kind/name"; omitted when the zone has no healthy node
+	PVCs     []string `json:"pvcs"`
+	// Unprovisionable is set when, with --check-provisioning, no Karpenter
+	// NodePool or node group can launch a node in the zone either
+	Unprovisionable bool `json:"unprovisionable,omitempty"`
+}
+
+// ZoneChoice is the zone picked for the PVCs of a namespace with --zone auto
+type ZoneChoice struct {
+	Namespace     string   `json:"namespace"`
+	Zone          string   `json:"zone,omitempty"` // Omitted when no healthy zone is left to move to
+	Excluded      []string `json:"excluded"`       // Zones the namespace's volumes are moved out of
+	Nodes         int      `json:"nodes"`          // Ready, schedulable nodes in the zone
+	CPUPercent    int      `json:"cpuPercent"`     // Share of the zone's allocatable CPU requested after the move
+	MemoryPercent int      `json:"memoryPercent"`  // Share of the zone's allocatable memory requested after the move
+}
+
+// PlanItem is the plan of one PVC
+type PlanItem struct {
+	PVC          string `json:"pvc"` // "namespace/name"
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	Action       string `json:"action"`           // ActionMigrate, ActionSkip or ActionError
+	Reason       string `json:"reason,omitempty"` // Why the PVC is skipped or cannot be migrated
+	PVName       string `json:"pvName,omitempty"`
+	VolumeID     string `json:"volumeId,omitempty"`
+	Capacity     string `json:"capacity,omitempty"` // As requested by the claim, e.g. "10Gi"
+	SizeGiB      int32  `json:"sizeGiB,omitempty"`
+	CurrentZone  string `json:"currentZone,omitempty"`
+	TargetZone   string `json:"targetZone,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"` // Only when overridden for this PVC
+	Attached     bool   `json:"attached"`               // Mounted by a pod, so its workloads are scaled down
+	ClaimPhase   string `json:"claimPhase,omitempty"`
+	PVPhase      string `json:"pvPhase,omitempty"`
+	VolumeMode   string `json:"volumeMode,omitempty"` // "Block" for raw block devices; omitted for Filesystem
+	PVMissing    bool   `json:"pvMissing,omitempty"`
+	Priority     string `json:"priority,omitempty"` // "high" or "low"; omitted for normal
+	Window       string `json:"window,omitempty"`   // Daily window of its namespace, e.g. "02:00-04:00 UTC"
+
+	CoMountedWith      string     `json:"coMountedWith,omitempty"` // Pod whose other PVCs pulled this one into the run
+	StagedSnapshotID   string     `json:"stagedSnapshotId,omitempty"`
+	StagedSnapshotTime *time.Time `json:"stagedSnapshotTime,omitempty"`
+	SourceKMSKeyID     string     `json:"sourceKmsKeyId,omitempty"` // Key of the current volume; omitted when unencrypted
+	KMSKeyID           string     `json:"kmsKeyId,omitempty"`       // Key of the new volume; omitted when unencrypted
+
+	VolumeType   string `json:"volumeType,omitempty"`   // Type of the new volume
+	IOPS         int32  `json:"iops,omitempty"`         // Provisioned IOPS of the new volume
+	Throughput   int32  `json:"throughput,omitempty"`   // MiB/s of the new gp3 volume
+	BlockExpress bool   `json:"blockExpress,omitempty"` // The new io2 volume only attaches to instances supporting io2 Block Express
+
+	Tags map[string]string `json:"tags,omitempty"` // Tags the PVC's annotations add to its snapshot and volume
+
+	ResumeAt string `json:"resumeAt,omitempty"` // Step it goes on from, as an earlier run left its static PV behind
+}
+
+// Result is the outcome of a run
+type Result struct {
+	APIVersion  string      `json:"apiVersion"`
+	Kind        string      `json:"kind"`
+	TargetZone  string      `json:"targetZone"`
+	MigrationID string      `json:"migrationId,omitempty"` // Pass to --migration-id to adopt what the run created
+	DryRun      bool        `json:"dryRun"`
+	Total       int         `json:"total"`
+	Migrated    int         `json:"migrated"`
+	Skipped     int         `json:"skipped"`
+	Failed      int         `json:"failed"`
+	PVCs        []PVCResult `json:"pvcs"`
+	Warnings    []Warning   `json:"warnings"`
+	Orphans     []Orphan    `json:"orphans,omitempty"`    // Objects left without a claim
+	Stragglers  []Straggler `json:"stragglers,omitempty"` // Volumes an evacuation run left in the source zone; any fails the run
+	APIUsage    []APIUsage  `json:"apiUsage,omitempty"`   // Calls made to EC2, CloudWatch and Kubernetes, by operation
+}
+
+// PVCResult is the outcome of one PVC
+type PVCResult struct {
+	PVC            string `json:"pvc"` // "namespace/name"
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	Outcome        string `json:"outcome"` // OutcomeMigrated, OutcomeSkipped, OutcomeFailed or OutcomeIncomplete
+	Step           string `json:"step"`    // Last step, or the step that failed
+	Error          string `json:"error,omitempty"`
+	SourceZone     string `json:"sourceZone,omitempty"`
+	TargetZone     string `json:"targetZone,omitempty"`
+	SourceVolumeID string `json:"sourceVolumeId,omitempty"`
+	SnapshotID     string `json:"snapshotId,omitempty"`
+	BackupJobID    string `json:"backupJobId,omitempty"` // AWS Backup job backing the source volume up to the vault
+	VolumeID       string `json:"volumeId,omitempty"`    // New volume in the target zone
+	// AdoptedSnapshot and AdoptedVolume are set when they were created by an
+	// earlier run
+	AdoptedSnapshot bool       `json:"adoptedSnapshot,omitempty"`
+	AdoptedVolume   bool       `json:"adoptedVolume,omitempty"`
+	ResumedAt       string     `json:"resumedAt,omitempty"` // Step it went on from, as an earlier run left its static PV behind
+	SizeGiB         int32      `json:"sizeGiB,omitempty"`
+	StartTime       *time.Time `json:"startTime,omitempty"`
+	EndTime         *time.Time `json:"endTime,omitempty"`
+	Finish          []string   `json:"finish,omitempty"`   // Commands completing a failed migration by hand
+	Rollback        []string   `json:"rollback,omitempty"` // Commands undoing a failed migration
+	// MountCheck is "passed" or "failed" when the run had a pod write to the
+	// new volume in the target zone
+	MountCheck string `json:"mountCheck,omitempty"`
+	// Checksum is a SHA-256 of the files on the source volume, taken before its
+	// snapshot when the run verified checksums; ChecksumVerified is set once the
+	// new volume matched it
+	Checksum         string `json:"checksum,omitempty"`
+	ChecksumVerified bool   `json:"checksumVerified,omitempty"`
+}
+
+// Straggler is an EBS volume claimed in the run's namespaces that is still in the
+// source zone after an evacuation run
+type Straggler struct {
+	PVC      string `json:"pvc"`
+	PVName   string `json:"pvName"`
+	VolumeID string `json:"volumeId"`
+}
+
+// Warning is something the operator has to follow up on after the run
+type Warning struct {
+	Time    time.Time `json:"time"`
+	PVC     string    `json:"pvc,omitempty"` // Empty for warnings about the whole run
+	Message string    `json:"message"`
+	Action  string    `json:"action,omitempty"`
+}
+
+// Orphan is a Kubernetes object the run created but could not hand over to a claim
+type Orphan struct {
+	PVC      string `json:"pvc"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	VolumeID string `json:"volumeId"`
+	Reason   string `json:"reason"`
+	Delete   string `json:"delete"` // Command that deletes the object; its EBS volume is kept
+}
+
+// APIUsage is the number of calls the run made to one operation of an API
+type APIUsage struct {
+	Service   string `json:"service"`   // EC2, CloudWatch or Kubernetes
+	Operation string `json:"operation"` // e.g. DescribeSnapshots or "list persistentvolumeclaims"
+	Calls     int    `json:"calls"`     // Requests sent, retries included
+	Throttled int    `json:"throttled"` // Requests rejected for exceeding the API's rate limit
+}